@@ -8,7 +8,6 @@ import (
 	_ "github.com/davidborzek/tvhgo/docs/api"
 	"github.com/go-chi/chi/v5"
 	"github.com/go-chi/chi/v5/middleware"
-	"github.com/go-chi/cors"
 	httpSwagger "github.com/swaggo/http-swagger/v2"
 )
 
@@ -28,14 +27,19 @@ type router struct {
 	twoFactorService      core.TwoFactorAuthService
 	dvrConfigService      core.DVRConfigService
 	profileService        core.ProfileService
-}
-
-var corsOpts = cors.Options{
-	AllowedOrigins:   []string{"*"},
-	AllowedMethods:   []string{"GET", "POST", "PUT", "DELETE", "OPTIONS"},
-	AllowedHeaders:   []string{"Accept", "Authorization", "Content-Type"},
-	AllowCredentials: true,
-	MaxAge:           300,
+	streamSessions        core.StreamSessionRegistry
+	streamLimiter         core.StreamLimiter
+	streamSigner          core.StreamSigner
+	hls                   core.HLSService
+	oidcAuthenticator     core.OIDCAuthenticator
+	webAuthn              core.WebAuthnService
+	recordingOwners       core.RecordingOwnerRepository
+	loginThrottle         core.LoginThrottleService
+	audit                 core.AuditService
+	deviceAuth            core.DeviceAuthService
+	parentalControls      core.ParentalControlsService
+	trustedDevices        core.TrustedDeviceService
+	passwordHasher        core.PasswordHasher
 }
 
 func New(
@@ -54,6 +58,19 @@ func New(
 	twoFactorService core.TwoFactorAuthService,
 	dvrConfigService core.DVRConfigService,
 	profileService core.ProfileService,
+	streamSessions core.StreamSessionRegistry,
+	streamLimiter core.StreamLimiter,
+	streamSigner core.StreamSigner,
+	hls core.HLSService,
+	oidcAuthenticator core.OIDCAuthenticator,
+	webAuthn core.WebAuthnService,
+	recordingOwners core.RecordingOwnerRepository,
+	loginThrottle core.LoginThrottleService,
+	audit core.AuditService,
+	deviceAuth core.DeviceAuthService,
+	parentalControls core.ParentalControlsService,
+	trustedDevices core.TrustedDeviceService,
+	passwordHasher core.PasswordHasher,
 ) *router {
 	return &router{
 		cfg:                   cfg,
@@ -71,6 +88,19 @@ func New(
 		twoFactorService:      twoFactorService,
 		dvrConfigService:      dvrConfigService,
 		profileService:        profileService,
+		streamSessions:        streamSessions,
+		streamLimiter:         streamLimiter,
+		streamSigner:          streamSigner,
+		hls:                   hls,
+		oidcAuthenticator:     oidcAuthenticator,
+		webAuthn:              webAuthn,
+		recordingOwners:       recordingOwners,
+		loginThrottle:         loginThrottle,
+		audit:                 audit,
+		deviceAuth:            deviceAuth,
+		parentalControls:      parentalControls,
+		trustedDevices:        trustedDevices,
+		passwordHasher:        passwordHasher,
 	}
 }
 
@@ -78,11 +108,31 @@ func (s *router) Handler() http.Handler {
 	r := chi.NewRouter()
 	r.Use(middleware.Recoverer)
 	r.Use(middleware.NoCache)
-	r.Use(cors.Handler(corsOpts))
+	if cors := corsHandler(&s.cfg.Server.CORS); cors != nil {
+		r.Use(cors)
+	}
+	if s.cfg.Server.CSRF.Enabled != nil && *s.cfg.Server.CSRF.Enabled {
+		r.Use(s.CSRF)
+	}
 	r.Use(s.Log)
 
 	r.Post("/login", s.Login)
 
+	if s.cfg.Auth.OIDC.Enabled {
+		r.Get("/auth/oidc/login", s.OIDCLogin)
+		r.Get("/auth/oidc/callback", s.OIDCCallback)
+	}
+
+	if s.cfg.Auth.WebAuthn.Enabled {
+		r.Post("/auth/webauthn/login/begin", s.BeginWebAuthnLogin)
+		r.Post("/auth/webauthn/login/finish", s.FinishWebAuthnLogin)
+	}
+
+	if s.cfg.Auth.Device.Enabled {
+		r.Post("/auth/device/code", s.CreateDeviceCode)
+		r.Post("/auth/device/token", s.CreateDeviceToken)
+	}
+
 	authenticated := r.With(s.HandleAuthentication)
 
 	enabledSwaggerUI := s.cfg.Server.SwaggerUI.Enabled
@@ -91,7 +141,7 @@ func (s *router) Handler() http.Handler {
 			http.Redirect(w, r, "/api/swagger/index.html", http.StatusMovedPermanently)
 		})
 
-		authenticated.Get("/swagger/*", httpSwagger.Handler(
+		authenticated.With(swaggerSecurityHeaders).Get("/swagger/*", httpSwagger.Handler(
 			httpSwagger.URL("/api/swagger/doc.json"),
 		))
 	}
@@ -100,63 +150,127 @@ func (s *router) Handler() http.Handler {
 	authenticated.Get("/auth/info", s.GetAuthInfo)
 
 	authenticated.Get("/user", s.GetCurrentUser)
-	authenticated.Patch("/user", s.UpdateUser)
-	authenticated.Patch("/user/password", s.UpdateUserPassword)
-
-	authenticated.Get("/two-factor-auth", s.GetTwoFactorAuthSettings)
-	authenticated.Put("/two-factor-auth/setup", s.SetupTwoFactorAuth)
-	authenticated.Put("/two-factor-auth/activate", s.ActivateTwoFactorAuth)
-	authenticated.Put("/two-factor-auth/deactivate", s.DeactivateTwoFactorAuth)
-
-	authenticated.Get("/sessions", s.GetSessionsForCurrentUser)
-	authenticated.Delete("/sessions/{id}", s.DeleteSession)
-
-	authenticated.Get("/tokens", s.GetTokens)
-	authenticated.Post("/tokens", s.CreateToken)
-	authenticated.Delete("/tokens/{id}", s.DeleteToken)
-
-	authenticated.Get("/epg", s.GetEpg)
-	authenticated.Get("/epg/events", s.GetEpgEvents)
-	authenticated.Get("/epg/events/{id}", s.GetEpgEvent)
-	authenticated.Get("/epg/events/{id}/related", s.GetRelatedEpgEvents)
-	authenticated.Get("/epg/content-types", s.GetEpgContentTypes)
-
-	authenticated.Get("/channels", s.GetChannels)
-	authenticated.Get("/channels/{id}", s.GetChannel)
-	authenticated.Get("/channels/{number}/stream", s.StreamChannel)
-
-	authenticated.Get("/picon/{id}", s.GetPicon)
-
-	authenticated.Get("/recordings", s.GetRecordings)
-	authenticated.Post("/recordings", s.CreateRecording)
-
-	authenticated.Delete("/recordings", s.BatchRemoveRecordings)
-	authenticated.Put("/recordings/stop", s.BatchStopRecordings)
-	authenticated.Put("/recordings/cancel", s.BatchCancelRecordings)
-
-	authenticated.Post("/recordings/event", s.CreateRecordingByEvent)
-
-	authenticated.Get("/recordings/{id}", s.GetRecording)
-	authenticated.Delete("/recordings/{id}", s.RemoveRecording)
-	authenticated.Patch("/recordings/{id}", s.UpdateRecording)
-	authenticated.Put("/recordings/{id}/stop", s.StopRecording)
-	authenticated.Put("/recordings/{id}/cancel", s.CancelRecording)
-	authenticated.Put("/recordings/{id}/move/{dest}", s.MoveRecording)
-	authenticated.Get("/recordings/{id}/stream", s.StreamRecording)
-
-	authenticated.Get("/profiles/stream", s.GetStreamProfiles)
-
-	authenticated.Get("/dvr/config", s.GetDVRConfigList)
-	authenticated.Get("/dvr/config/{id}", s.GetDVRConfig)
-	authenticated.Delete("/dvr/config/{id}", s.DeleteDVRConfig)
-
-	admin := authenticated.With(s.IsAdmin)
-	admin.Get("/users", s.GetUsers)
-	admin.Post("/users", s.CreateUser)
-	admin.Delete("/users/{id}", s.DeleteUser)
-	admin.Get("/users/{id}", s.GetUser)
-	admin.Get("/users/{id}/sessions", s.GetSessions)
-	admin.Delete("/users/{userId}/sessions/{id}", s.DeleteUserSession)
+
+	account := authenticated.With(s.RequirePermission(core.PermissionAccount))
+	account.Patch("/user", s.UpdateUser)
+	account.Patch("/user/password", s.UpdateUserPassword)
+
+	account.Get("/two-factor-auth", s.GetTwoFactorAuthSettings)
+	account.Put("/two-factor-auth/setup", s.SetupTwoFactorAuth)
+	account.Put("/two-factor-auth/activate", s.ActivateTwoFactorAuth)
+	account.Put("/two-factor-auth/deactivate", s.DeactivateTwoFactorAuth)
+	account.Put("/two-factor-auth/recovery-codes", s.RegenerateTwoFactorAuthRecoveryCodes)
+
+	if s.cfg.Auth.WebAuthn.Enabled {
+		account.Get("/webauthn/credentials", s.GetWebAuthnCredentials)
+		account.Patch("/webauthn/credentials/{id}", s.UpdateWebAuthnCredential)
+		account.Delete("/webauthn/credentials/{id}", s.DeleteWebAuthnCredential)
+		account.Post("/webauthn/registration/begin", s.BeginWebAuthnRegistration)
+		account.Post("/webauthn/registration/finish", s.FinishWebAuthnRegistration)
+	}
+
+	account.Get("/user/parental-controls", s.GetParentalControls)
+
+	account.Get("/sessions", s.GetSessionsForCurrentUser)
+	account.Delete("/sessions", s.DeleteSessions)
+	account.Delete("/sessions/{id}", s.DeleteSession)
+
+	account.Get("/trusted-devices", s.GetTrustedDevices)
+	account.Delete("/trusted-devices", s.DeleteTrustedDevices)
+	account.Delete("/trusted-devices/{id}", s.DeleteTrustedDevice)
+
+	account.Get("/tokens", s.GetTokens)
+	account.Post("/tokens", s.CreateToken)
+	account.Delete("/tokens/{id}", s.DeleteToken)
+
+	if s.cfg.Auth.Device.Enabled {
+		account.Get("/auth/device/{code}", s.GetDeviceAuthorization)
+		account.Put("/auth/device/{code}/approve", s.ApproveDeviceAuthorization)
+		account.Put("/auth/device/{code}/deny", s.DenyDeviceAuthorization)
+	}
+
+	epg := authenticated.With(s.RequirePermission(core.PermissionEPGRead))
+	epg.Get("/epg", s.GetEpg)
+	epg.Get("/epg/events", s.GetEpgEvents)
+	epg.Get("/epg/events/{id}", s.GetEpgEvent)
+	epg.Get("/epg/events/{id}/related", s.GetRelatedEpgEvents)
+	epg.Get("/epg/content-types", s.GetEpgContentTypes)
+
+	epg.Get("/channels", s.GetChannels)
+	epg.Get("/channels/{id}", s.GetChannel)
+	epg.Get("/picon/{id}", s.GetPicon)
+	epg.Get("/profiles/stream", s.GetStreamProfiles)
+
+	channelStream := r.With(
+		s.HandleStreamAuthentication(channelStreamResource),
+		s.RequirePermission(core.PermissionStream),
+	)
+	channelStream.Get("/channels/{number}/stream", s.StreamChannel)
+	channelStream.Get("/channels/{number}/hls/index.m3u8", s.GetChannelHLSPlaylist)
+	channelStream.Get("/channels/{number}/hls/{sequence}.ts", s.GetChannelHLSSegment)
+
+	authenticated.With(s.RequirePermission(core.PermissionStream)).
+		Post("/streams/sign", s.SignStream)
+
+	recordingsRead := authenticated.With(s.RequirePermission(core.PermissionRecordingsRead))
+	recordingsRead.Get("/recordings", s.GetRecordings)
+	recordingsRead.Get("/recordings/{id}", s.GetRecording)
+	r.With(
+		s.HandleStreamAuthentication(recordingStreamResource),
+		s.RequirePermission(core.PermissionRecordingsRead),
+	).Get("/recordings/{id}/stream", s.StreamRecording)
+
+	// Users without the permission to manage all recordings
+	// can only modify recordings they have created.
+	recordingsWrite := authenticated.With(s.RequirePermission(core.PermissionRecordingsWrite))
+	recordingsWrite.Post("/recordings", s.CreateRecording)
+	recordingsWrite.Post("/recordings/event", s.CreateRecordingByEvent)
+
+	recordingsWrite.Delete("/recordings", s.BatchRemoveRecordings)
+	recordingsWrite.Put("/recordings/stop", s.BatchStopRecordings)
+	recordingsWrite.Put("/recordings/cancel", s.BatchCancelRecordings)
+
+	recordingsWrite.Delete("/recordings/{id}", s.RemoveRecording)
+	recordingsWrite.Patch("/recordings/{id}", s.UpdateRecording)
+	recordingsWrite.Put("/recordings/{id}/stop", s.StopRecording)
+	recordingsWrite.Put("/recordings/{id}/cancel", s.CancelRecording)
+	recordingsWrite.Put("/recordings/{id}/move/{dest}", s.MoveRecording)
+
+	recordingsWrite.Get("/dvr/config", s.GetDVRConfigList)
+	recordingsWrite.Get("/dvr/config/{id}", s.GetDVRConfig)
+	authenticated.With(s.RequirePermission(core.PermissionDVRConfigManage)).
+		Delete("/dvr/config/{id}", s.DeleteDVRConfig)
+
+	users := authenticated.With(s.RequirePermission(core.PermissionUsersManage))
+	users.Get("/users", s.GetUsers)
+	users.Post("/users", s.CreateUser)
+	users.Delete("/users/{id}", s.DeleteUser)
+	users.Get("/users/{id}", s.GetUser)
+	users.Patch("/users/{id}", s.UpdateUserByID)
+	users.Put("/users/{id}/password", s.ResetUserPassword)
+	users.Put("/users/{id}/role", s.UpdateUserRole)
+	users.Get("/users/{id}/sessions", s.GetSessions)
+	users.Delete("/users/{id}/sessions", s.DeleteUserSessions)
+	users.Delete("/users/{userId}/sessions/{id}", s.DeleteUserSession)
+	users.Get("/users/{id}/trusted-devices", s.GetUserTrustedDevices)
+	users.Delete("/users/{id}/trusted-devices", s.DeleteUserTrustedDevices)
+	users.Delete("/users/{userId}/trusted-devices/{id}", s.DeleteUserTrustedDevice)
+	users.Get("/users/{id}/parental-controls", s.GetUserParentalControls)
+	users.Put("/users/{id}/parental-controls", s.UpdateUserParentalControls)
+	users.Delete("/users/{id}/parental-controls", s.DeleteUserParentalControls)
+	users.Get("/users/{id}/tokens", s.GetUserTokens)
+	users.Delete("/users/{id}/tokens", s.DeleteUserTokens)
+	users.Delete("/users/{userId}/tokens/{id}", s.DeleteUserToken)
+	users.Delete("/users/{id}/two-factor-auth", s.DeleteUserTwoFactorAuth)
+	users.Get("/lockouts", s.GetLockouts)
+	users.Delete("/lockouts/{id}", s.DeleteLockout)
+
+	authenticated.With(s.RequirePermission(core.PermissionAuditRead)).
+		Get("/audit", s.GetAuditLog)
+
+	streams := authenticated.With(s.RequirePermission(core.PermissionStreamsManage))
+	streams.Get("/streams", s.GetStreamSessions)
+	streams.Delete("/streams/{id}", s.TerminateStreamSession)
 
 	return r
 }
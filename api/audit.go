@@ -0,0 +1,69 @@
+package api
+
+import (
+	"net/http"
+
+	"github.com/davidborzek/tvhgo/api/request"
+	"github.com/davidborzek/tvhgo/api/response"
+	"github.com/davidborzek/tvhgo/core"
+	"github.com/rs/zerolog/log"
+)
+
+// GetAuditLog godoc
+//
+//	@Summary	Get the security audit log
+//	@Tags		audit
+//	@Param		query	query	core.AuditQueryParams	false	"Query"
+//	@Produce	json
+//	@Success	200	{object}	core.AuditListResult
+//	@Failure	400	{object}	response.ErrorResponse
+//	@Failure	401	{object}	response.ErrorResponse
+//	@Failure	403	{object}	response.ErrorResponse
+//	@Failure	500	{object}	response.ErrorResponse
+//	@Security	JWT
+//	@Router		/audit [get]
+func (s *router) GetAuditLog(w http.ResponseWriter, r *http.Request) {
+	var q core.AuditQueryParams
+	if err := request.BindQuery(r, &q); err != nil {
+		response.BadRequest(w, err)
+		return
+	}
+
+	if err := q.Validate(); err != nil {
+		response.BadRequest(w, err)
+		return
+	}
+
+	result, err := s.audit.Find(r.Context(), q)
+	if err != nil {
+		log.Error().Err(err).Msg("failed to get audit log")
+
+		response.InternalErrorCommon(w)
+		return
+	}
+
+	response.JSON(w, result, http.StatusOK)
+}
+
+// recordAudit records an action of the authenticated user in the audit log.
+func (s *router) recordAudit(r *http.Request, action core.AuditAction, target string) {
+	entry := &core.AuditEntry{
+		Action: action,
+		Target: target,
+	}
+
+	if ctx, ok := request.GetAuthContext(r.Context()); ok {
+		entry.UserID = &ctx.UserID
+	}
+
+	s.recordAuditEntry(r, entry)
+}
+
+// recordAuditEntry completes an entry with the client ip and
+// user agent of the request and records it in the audit log.
+func (s *router) recordAuditEntry(r *http.Request, entry *core.AuditEntry) {
+	entry.ClientIP = request.RemoteAddr(r)
+	entry.UserAgent = r.UserAgent()
+
+	s.audit.Record(r.Context(), entry)
+}
@@ -92,7 +92,7 @@ func (router *router) handleTokenAuthorization(
 	next http.Handler,
 	token string,
 ) {
-	ctx, err := router.tokenService.Validate(r.Context(), token)
+	ctx, err := router.tokenService.Validate(r.Context(), token, request.RemoteAddr(r))
 	if err != nil {
 		if errors.As(err, &core.InvalidOrExpiredTokenError{}) {
 			response.Unauthorized(w, err)
@@ -124,25 +124,30 @@ func extractTokenFromCookie(r *http.Request, cookieName string) string {
 	return token.Value
 }
 
-func (router *router) IsAdmin(next http.Handler) http.Handler {
-	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
-		ctx, ok := request.GetAuthContext(r.Context())
-		if !ok {
-			response.InternalErrorCommon(w)
-			return
-		}
-
-		user, err := router.users.FindById(r.Context(), ctx.UserID)
-		if err != nil {
-			response.InternalError(w, err)
-			return
-		}
-
-		if !user.IsAdmin {
-			response.Forbidden(w, core.ErrPermissionDenied)
-			return
-		}
-
-		next.ServeHTTP(w, r)
-	})
+// RequirePermission only allows requests of users whose role grants the permission.
+// Requests authorized via a scoped token additionally require the permission as scope.
+func (router *router) RequirePermission(permission core.Permission) func(http.Handler) http.Handler {
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			ctx, ok := request.GetAuthContext(r.Context())
+			if !ok {
+				response.InternalErrorCommon(w)
+				return
+			}
+
+			user, err := router.users.FindById(r.Context(), ctx.UserID)
+			if err != nil {
+				response.InternalError(w, err)
+				return
+			}
+
+			if user == nil || user.Disabled ||
+				!user.HasPermission(permission) || !ctx.HasScope(permission) {
+				response.Forbidden(w, core.ErrPermissionDenied)
+				return
+			}
+
+			next.ServeHTTP(w, r)
+		})
+	}
 }
@@ -4,6 +4,8 @@ import (
 	"fmt"
 	"net"
 	"net/http"
+	"slices"
+	"strings"
 
 	"github.com/davidborzek/tvhgo/api/request"
 	"github.com/davidborzek/tvhgo/core"
@@ -54,6 +56,16 @@ func (router *router) handleForwardAuth(
 
 			return false
 		}
+	} else if user.Disabled {
+		log.Debug().Str("remote_user", remoteUser).
+			Msg("[reverse proxy auth] remote user is disabled")
+
+		return false
+	} else if err := router.syncForwardAuthRole(r, user); err != nil {
+		log.Error().Err(err).Str("remote_user", remoteUser).
+			Msg("[reverse proxy auth] failed to update role of user")
+
+		return false
 	}
 
 	ctx := &core.AuthContext{
@@ -90,6 +102,10 @@ func (router *router) createForwardAuthUser(r *http.Request, remoteUser string)
 		Email:       email,
 	}
 
+	if role, ok := router.forwardAuthRole(r); ok {
+		user.Role = role
+	}
+
 	if err := router.users.Create(r.Context(), user); err != nil {
 		return nil, err
 	}
@@ -97,6 +113,77 @@ func (router *router) createForwardAuthUser(r *http.Request, remoteUser string)
 	return user, nil
 }
 
+// syncForwardAuthRole updates the role of a user from the groups header on every
+// request, so that removing a user from a group revokes the permissions immediately.
+func (router *router) syncForwardAuthRole(r *http.Request, user *core.User) error {
+	role, ok := router.forwardAuthRole(r)
+	if !ok || user.Role == role {
+		return nil
+	}
+
+	log.Info().Str("username", user.Username).
+		Str("role", string(role)).
+		Msg("[reverse proxy auth] updating role of user")
+
+	user.Role = role
+	return router.users.Update(r.Context(), user)
+}
+
+// forwardAuthRole returns the role mapped from the groups header. It returns false
+// when neither admin groups nor group roles are configured and roles are not
+// managed by the reverse proxy.
+func (router *router) forwardAuthRole(r *http.Request) (core.Role, bool) {
+	cfg := router.cfg.Auth.ReverseProxy
+	if len(cfg.AdminGroups) == 0 && len(cfg.GroupRoles) == 0 {
+		return "", false
+	}
+
+	var role core.Role
+	for _, group := range strings.Split(r.Header.Get(cfg.GroupsHeader), ",") {
+		group = strings.TrimSpace(group)
+		if group == "" {
+			continue
+		}
+
+		if slices.Contains(cfg.AdminGroups, group) {
+			return core.RoleAdmin, true
+		}
+
+		name, ok := cfg.GroupRoles[group]
+		if !ok {
+			continue
+		}
+
+		mapped, err := core.ParseRole(name)
+		if err != nil {
+			log.Warn().Str("group", group).Str("role", name).
+				Msg("[reverse proxy auth] ignoring invalid role of group")
+
+			continue
+		}
+
+		if slices.Index(core.Roles, mapped) > slices.Index(core.Roles, role) {
+			role = mapped
+		}
+	}
+
+	if role != "" {
+		return role, true
+	}
+
+	defaultRole, err := core.ParseRole(cfg.DefaultRole)
+	if err != nil {
+		if cfg.DefaultRole != "" {
+			log.Warn().Str("role", cfg.DefaultRole).
+				Msg("[reverse proxy auth] ignoring invalid default role")
+		}
+
+		return core.DefaultRole, true
+	}
+
+	return defaultRole, true
+}
+
 // isIPAllowed checks if the remote address is contained in the list of allowed networks.
 // The list of allowed networks can be either IP addresses or CIDR notation.
 func isIPAllowed(addr string, allowedNetworks []string) bool {
@@ -0,0 +1,159 @@
+package api
+
+import (
+	"crypto/subtle"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+
+	"github.com/davidborzek/tvhgo/api/request"
+	"github.com/davidborzek/tvhgo/api/response"
+	"github.com/davidborzek/tvhgo/core"
+	"github.com/rs/zerolog/log"
+)
+
+const (
+	oidcCookieName = "tvhgo_oidc"
+	oidcCookiePath = "/api/auth/oidc"
+	// oidcCookieMaxAge is the time a user has to log in at the provider.
+	oidcCookieMaxAge = 10 * 60
+)
+
+// OIDCLogin godoc
+//
+//	@Summary	Starts a login via OpenID Connect
+//	@Tags		auth
+//	@Success	302
+//	@Failure	500	{object}	response.ErrorResponse
+//	@Router		/auth/oidc/login [get]
+func (s *router) OIDCLogin(w http.ResponseWriter, r *http.Request) {
+	authRequest, err := s.oidcAuthenticator.AuthCodeURL(r.Context())
+	if err != nil {
+		log.Error().Err(err).Msg("[oidc] failed to start login")
+
+		response.InternalErrorCommon(w)
+		return
+	}
+
+	value, err := json.Marshal(authRequest)
+	if err != nil {
+		response.InternalError(w, err)
+		return
+	}
+
+	http.SetCookie(w, &http.Cookie{
+		Name:     oidcCookieName,
+		Value:    base64.RawURLEncoding.EncodeToString(value),
+		Path:     oidcCookiePath,
+		MaxAge:   oidcCookieMaxAge,
+		Secure:   s.cfg.Auth.Session.CookieSecure,
+		HttpOnly: true,
+		SameSite: http.SameSiteLaxMode,
+	})
+
+	http.Redirect(w, r, authRequest.URL, http.StatusFound)
+}
+
+// OIDCCallback godoc
+//
+//	@Summary	Callback of the OpenID Connect provider
+//	@Tags		auth
+//	@Param		code	query	string	true	"Authorization code"
+//	@Param		state	query	string	true	"State"
+//	@Success	302
+//	@Failure	401	{object}	response.ErrorResponse
+//	@Failure	500	{object}	response.ErrorResponse
+//	@Router		/auth/oidc/callback [get]
+func (s *router) OIDCCallback(w http.ResponseWriter, r *http.Request) {
+	query := r.URL.Query()
+	addr := request.RemoteAddr(r)
+
+	// The auth request can only be used once.
+	http.SetCookie(w, &http.Cookie{
+		Name:     oidcCookieName,
+		Path:     oidcCookiePath,
+		MaxAge:   -1,
+		Secure:   s.cfg.Auth.Session.CookieSecure,
+		HttpOnly: true,
+	})
+
+	if errCode := query.Get("error"); errCode != "" {
+		log.Error().Str("ip", addr).
+			Str("error", errCode).
+			Str("description", query.Get("error_description")).
+			Msg("[oidc] login failed at provider")
+
+		s.recordLoginFailureAudit(r, "", fmt.Errorf("oidc login failed: %s", errCode))
+		response.Unauthorizedf(w, "oidc login failed: %s", errCode)
+		return
+	}
+
+	authRequest, ok := oidcAuthRequestFromCookie(r)
+	if !ok || subtle.ConstantTimeCompare([]byte(authRequest.State), []byte(query.Get("state"))) != 1 {
+		s.recordLoginFailureAudit(r, "", core.ErrOIDCStateInvalid)
+		response.Unauthorized(w, core.ErrOIDCStateInvalid)
+		return
+	}
+
+	user, err := s.oidcAuthenticator.Exchange(r.Context(), query.Get("code"), *authRequest)
+	if err != nil {
+		log.Error().Str("ip", addr).
+			Err(err).Msg("[oidc] login failed")
+
+		if errors.Is(err, core.ErrOIDCStateInvalid) ||
+			errors.Is(err, core.ErrOIDCUsernameMissing) ||
+			errors.Is(err, core.ErrOIDCRegistrationDisabled) ||
+			errors.Is(err, core.ErrUserDisabled) {
+			s.recordLoginFailureAudit(r, "", err)
+			response.Unauthorized(w, err)
+			return
+		}
+
+		response.InternalErrorCommon(w)
+		return
+	}
+
+	token, err := s.sessionManager.Create(
+		r.Context(),
+		user.ID,
+		addr,
+		r.UserAgent(),
+	)
+	if err != nil {
+		response.InternalError(w, err)
+		return
+	}
+
+	s.recordLoginAudit(r, user.ID)
+
+	setSessionCookie(
+		w,
+		s.cfg.Auth.Session.CookieName,
+		token,
+		s.cfg.Auth.Session.CookieSecure,
+	)
+
+	http.Redirect(w, r, "/", http.StatusFound)
+}
+
+// oidcAuthRequestFromCookie decodes the auth request of a started login.
+func oidcAuthRequestFromCookie(r *http.Request) (*core.OIDCAuthRequest, bool) {
+	cookie, err := r.Cookie(oidcCookieName)
+	if err != nil {
+		return nil, false
+	}
+
+	value, err := base64.RawURLEncoding.DecodeString(cookie.Value)
+	if err != nil {
+		return nil, false
+	}
+
+	var authRequest core.OIDCAuthRequest
+	if err := json.Unmarshal(value, &authRequest); err != nil || authRequest.State == "" {
+		return nil, false
+	}
+
+	return &authRequest, true
+}
@@ -0,0 +1,125 @@
+package api_test
+
+import (
+	"encoding/base64"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+
+	"github.com/davidborzek/tvhgo/api"
+	"github.com/davidborzek/tvhgo/config"
+	"github.com/davidborzek/tvhgo/core"
+	mock_core "github.com/davidborzek/tvhgo/mock/core"
+	"go.uber.org/mock/gomock"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("OIDCCallback", func() {
+	var mockCtrl *gomock.Controller
+	var mockSessionManager *mock_core.MockSessionManager
+	var mockOIDCAuthenticator *mock_core.MockOIDCAuthenticator
+	var mockAudit *mock_core.MockAuditService
+
+	cfg := &config.Config{
+		Auth: config.AuthConfig{
+			Session: config.SessionConfig{
+				CookieName: "tvhgo_session",
+			},
+			OIDC: config.OIDCConfig{
+				Enabled: true,
+			},
+		},
+	}
+
+	authRequest := core.OIDCAuthRequest{
+		State:    "someState",
+		Nonce:    "someNonce",
+		Verifier: "someVerifier",
+	}
+
+	newCallbackRequest := func(state string, withCookie bool) *http.Request {
+		req, err := http.NewRequest("GET", "/auth/oidc/callback?code=someCode&state="+state, nil)
+		if err != nil {
+			Fail(err.Error())
+		}
+
+		if withCookie {
+			value, _ := json.Marshal(authRequest)
+			req.AddCookie(&http.Cookie{
+				Name:  "tvhgo_oidc",
+				Value: base64.RawURLEncoding.EncodeToString(value),
+			})
+		}
+
+		return req
+	}
+
+	BeforeEach(func() {
+		mockCtrl = gomock.NewController(GinkgoT())
+		mockSessionManager = mock_core.NewMockSessionManager(mockCtrl)
+		mockOIDCAuthenticator = mock_core.NewMockOIDCAuthenticator(mockCtrl)
+		mockAudit = mock_core.NewMockAuditService(mockCtrl)
+		mockAudit.EXPECT().
+			Record(gomock.Any(), gomock.Any()).
+			AnyTimes()
+	})
+
+	AfterEach(func() {
+		mockCtrl.Finish()
+	})
+
+	DescribeTable("returns status unauthorized for invalid state",
+		func(state string, withCookie bool) {
+			sut := api.New(cfg, nil, nil, nil, nil, nil, mockSessionManager, nil, nil, nil, nil, nil, nil, nil, nil, nil, nil, nil, nil, mockOIDCAuthenticator, nil, nil, nil, mockAudit, nil, nil, nil, nil)
+
+			rr := httptest.NewRecorder()
+			sut.Handler().ServeHTTP(rr, newCallbackRequest(state, withCookie))
+
+			Expect(rr.Code).To(Equal(http.StatusUnauthorized))
+			Expect(rr.Body.String()).To(MatchJSON(`{"message":"invalid oidc state"}`))
+		},
+		Entry("without cookie", "someState", false),
+		Entry("with mismatching state", "otherState", true),
+	)
+
+	It("creates a session and redirects to the ui", func() {
+		mockOIDCAuthenticator.EXPECT().
+			Exchange(gomock.Any(), "someCode", authRequest).
+			Return(&core.User{ID: 1}, nil).
+			Times(1)
+
+		mockSessionManager.EXPECT().
+			Create(gomock.Any(), int64(1), gomock.Any(), gomock.Any()).
+			Return("someToken", nil).
+			Times(1)
+
+		sut := api.New(cfg, nil, nil, nil, nil, nil, mockSessionManager, nil, nil, nil, nil, nil, nil, nil, nil, nil, nil, nil, nil, mockOIDCAuthenticator, nil, nil, nil, mockAudit, nil, nil, nil, nil)
+
+		rr := httptest.NewRecorder()
+		sut.Handler().ServeHTTP(rr, newCallbackRequest("someState", true))
+
+		Expect(rr.Code).To(Equal(http.StatusFound))
+		Expect(rr.Header().Get("Location")).To(Equal("/"))
+		Expect(rr.Result().Cookies()).To(ContainElement(And(
+			HaveField("Name", "tvhgo_session"),
+			HaveField("Value", "someToken"),
+		)))
+	})
+
+	It("returns status unauthorized when registration is disabled", func() {
+		mockOIDCAuthenticator.EXPECT().
+			Exchange(gomock.Any(), "someCode", authRequest).
+			Return(nil, core.ErrOIDCRegistrationDisabled).
+			Times(1)
+
+		sut := api.New(cfg, nil, nil, nil, nil, nil, mockSessionManager, nil, nil, nil, nil, nil, nil, nil, nil, nil, nil, nil, nil, mockOIDCAuthenticator, nil, nil, nil, mockAudit, nil, nil, nil, nil)
+
+		rr := httptest.NewRecorder()
+		sut.Handler().ServeHTTP(rr, newCallbackRequest("someState", true))
+
+		Expect(rr.Code).To(Equal(http.StatusUnauthorized))
+		Expect(rr.Body.String()).To(MatchJSON(`{"message":"user not found and oidc registration is disabled"}`))
+	})
+})
@@ -34,7 +34,7 @@ var _ = Describe("HandleAuthentication", func() {
 	})
 
 	It("returns status unauthorized", func() {
-		sut := api.New(&config.Config{}, nil, nil, nil, nil, nil, nil, nil, mockUserRepo, nil, nil, nil, nil, nil, nil)
+		sut := api.New(&config.Config{}, nil, nil, nil, nil, nil, nil, nil, mockUserRepo, nil, nil, nil, nil, nil, nil, nil, nil, nil, nil, nil, nil, nil, nil, nil, nil, nil, nil, nil)
 
 		middleware := sut.HandleAuthentication(nil)
 
@@ -76,7 +76,7 @@ var _ = Describe("HandleAuthentication", func() {
 		DescribeTable("remote addr is not allowed",
 			func(remoteAddr string, allowedAddresses []string) {
 				cfg.Auth.ReverseProxy.AllowedProxies = allowedAddresses
-				sut := api.New(cfg, nil, nil, nil, nil, nil, nil, nil, mockUserRepo, nil, nil, nil, nil, nil, nil)
+				sut := api.New(cfg, nil, nil, nil, nil, nil, nil, nil, mockUserRepo, nil, nil, nil, nil, nil, nil, nil, nil, nil, nil, nil, nil, nil, nil, nil, nil, nil, nil, nil)
 				m := sut.HandleAuthentication(nil)
 
 				req, err := http.NewRequest("GET", "/foobar", nil)
@@ -103,7 +103,7 @@ var _ = Describe("HandleAuthentication", func() {
 
 		DescribeTable("remote addr is allowed and user is found",
 			func(remoteAddr string) {
-				sut := api.New(cfg, nil, nil, nil, nil, nil, nil, nil, mockUserRepo, nil, nil, nil, nil, nil, nil)
+				sut := api.New(cfg, nil, nil, nil, nil, nil, nil, nil, mockUserRepo, nil, nil, nil, nil, nil, nil, nil, nil, nil, nil, nil, nil, nil, nil, nil, nil, nil, nil, nil)
 
 				nextHandler := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
 					authCtx, ok := request.GetAuthContext(r.Context())
@@ -146,10 +146,97 @@ var _ = Describe("HandleAuthentication", func() {
 			Entry("remote ip is in allowed cidr", "10.0.0.1"),
 		)
 
+		DescribeTable("syncs the role of a user from the groups header",
+			func(groups string, currentRole core.Role, expectedRole core.Role) {
+				cfg.Auth.ReverseProxy.GroupsHeader = "Remote-Groups"
+				cfg.Auth.ReverseProxy.AdminGroups = []string{"admins"}
+				cfg.Auth.ReverseProxy.GroupRoles = map[string]string{
+					"family": "recorder",
+					"guests": "viewer",
+				}
+				cfg.Auth.ReverseProxy.DefaultRole = "viewer"
+
+				sut := api.New(cfg, nil, nil, nil, nil, nil, nil, nil, mockUserRepo, nil, nil, nil, nil, nil, nil, nil, nil, nil, nil, nil, nil, nil, nil, nil, nil, nil, nil, nil)
+
+				nextHandler := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+					w.WriteHeader(http.StatusOK)
+				})
+
+				req, err := http.NewRequest("GET", "/foobar", nil)
+				if err != nil {
+					Fail(err.Error())
+				}
+
+				req.RemoteAddr = remoteAddr
+				req.Header.Set(userHeader, username)
+				req.Header.Set("Remote-Groups", groups)
+
+				mockUserRepo.EXPECT().
+					FindByUsername(req.Context(), "foobar").
+					Return(&core.User{ID: 1, Username: username, Role: currentRole}, nil)
+
+				if currentRole != expectedRole {
+					mockUserRepo.EXPECT().
+						Update(req.Context(), &core.User{ID: 1, Username: username, Role: expectedRole}).
+						Return(nil)
+				}
+
+				rr := httptest.NewRecorder()
+				sut.HandleAuthentication(nextHandler).ServeHTTP(rr, req)
+
+				Expect(rr.Code).To(Equal(http.StatusOK))
+			},
+			Entry("grants admin to members of admin groups", "family, admins", core.RoleDVRAdmin, core.RoleAdmin),
+			Entry("grants the role with most permissions", "guests,family", core.RoleViewer, core.RoleRecorder),
+			Entry("revokes admin when removed from admin group", "family", core.RoleAdmin, core.RoleRecorder),
+			Entry("falls back to the default role", "others", core.RoleAdmin, core.RoleViewer),
+			Entry("falls back to the default role without groups", "", core.RoleRecorder, core.RoleViewer),
+			Entry("keeps an unchanged role", "family", core.RoleRecorder, core.RoleRecorder),
+		)
+
+		It("creates a new user with the role mapped from the groups header", func() {
+			cfg.Auth.ReverseProxy.AllowRegistration = true
+			cfg.Auth.ReverseProxy.GroupsHeader = "Remote-Groups"
+			cfg.Auth.ReverseProxy.AdminGroups = []string{"admins"}
+
+			sut := api.New(cfg, nil, nil, nil, nil, nil, nil, nil, mockUserRepo, nil, nil, nil, nil, nil, nil, nil, nil, nil, nil, nil, nil, nil, nil, nil, nil, nil, nil, nil)
+
+			nextHandler := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				w.WriteHeader(http.StatusOK)
+			})
+
+			req, err := http.NewRequest("GET", "/foobar", nil)
+			if err != nil {
+				Fail(err.Error())
+			}
+
+			req.RemoteAddr = remoteAddr
+			req.Header.Set(userHeader, username)
+			req.Header.Set("Remote-Groups", "admins")
+
+			mockUserRepo.EXPECT().
+				FindByUsername(req.Context(), "foobar").
+				Return(nil, nil)
+
+			mockUserRepo.EXPECT().
+				Create(req.Context(), &core.User{
+					Username:    "foobar",
+					Email:       "foobar@tvhgo.local",
+					DisplayName: "foobar",
+					Role:        core.RoleAdmin,
+				}).
+				Return(nil)
+
+			rr := httptest.NewRecorder()
+			sut.HandleAuthentication(nextHandler).ServeHTTP(rr, req)
+
+			Expect(rr.Code).To(Equal(http.StatusOK))
+		})
+
 		When("remote addr is allowed", func() {
 			Context("and user header is empty", func() {
 				It("returns status unauthorized", func() {
-					sut := api.New(cfg, nil, nil, nil, nil, nil, nil, nil, mockUserRepo, nil, nil, nil, nil, nil, nil)
+					sut := api.New(cfg, nil, nil, nil, nil, nil, nil, nil, mockUserRepo, nil, nil, nil, nil, nil, nil, nil, nil, nil, nil, nil, nil, nil, nil, nil, nil, nil, nil, nil)
 					m := sut.HandleAuthentication(nil)
 
 					req, err := http.NewRequest("GET", "/foobar", nil)
@@ -171,7 +258,7 @@ var _ = Describe("HandleAuthentication", func() {
 			Context("user is not found", func() {
 				Context("and registration is disabled", func() {
 					It("returns status unauthorized", func() {
-						sut := api.New(cfg, nil, nil, nil, nil, nil, nil, nil, mockUserRepo, nil, nil, nil, nil, nil, nil)
+						sut := api.New(cfg, nil, nil, nil, nil, nil, nil, nil, mockUserRepo, nil, nil, nil, nil, nil, nil, nil, nil, nil, nil, nil, nil, nil, nil, nil, nil, nil, nil, nil)
 						m := sut.HandleAuthentication(nil)
 
 						req, err := http.NewRequest("GET", "/foobar", nil)
@@ -199,7 +286,7 @@ var _ = Describe("HandleAuthentication", func() {
 				Context("and registration is enabled", func() {
 					It("creates a new user", func() {
 						cfg.Auth.ReverseProxy.AllowRegistration = true
-						sut := api.New(cfg, nil, nil, nil, nil, nil, nil, nil, mockUserRepo, nil, nil, nil, nil, nil, nil)
+						sut := api.New(cfg, nil, nil, nil, nil, nil, nil, nil, mockUserRepo, nil, nil, nil, nil, nil, nil, nil, nil, nil, nil, nil, nil, nil, nil, nil, nil, nil, nil, nil)
 
 						nextHandler := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
 							authCtx, ok := request.GetAuthContext(r.Context())
@@ -243,7 +330,7 @@ var _ = Describe("HandleAuthentication", func() {
 
 					It("fails to create a new user", func() {
 						cfg.Auth.ReverseProxy.AllowRegistration = true
-						sut := api.New(cfg, nil, nil, nil, nil, nil, nil, nil, mockUserRepo, nil, nil, nil, nil, nil, nil)
+						sut := api.New(cfg, nil, nil, nil, nil, nil, nil, nil, mockUserRepo, nil, nil, nil, nil, nil, nil, nil, nil, nil, nil, nil, nil, nil, nil, nil, nil, nil, nil, nil)
 
 						middleware := sut.HandleAuthentication(nil)
 						req, err := http.NewRequest("GET", "/foobar", nil)
@@ -280,7 +367,7 @@ var _ = Describe("HandleAuthentication", func() {
 			})
 
 			It("fails to find user", func() {
-				sut := api.New(cfg, nil, nil, nil, nil, nil, nil, nil, mockUserRepo, nil, nil, nil, nil, nil, nil)
+				sut := api.New(cfg, nil, nil, nil, nil, nil, nil, nil, mockUserRepo, nil, nil, nil, nil, nil, nil, nil, nil, nil, nil, nil, nil, nil, nil, nil, nil, nil, nil, nil)
 				middleware := sut.HandleAuthentication(nil)
 
 				req, err := http.NewRequest("GET", "/foobar", nil)
@@ -309,7 +396,7 @@ var _ = Describe("HandleAuthentication", func() {
 	Describe("authorization header", func() {
 		When("token is valid", func() {
 			It("returns status ok", func() {
-				sut := api.New(&config.Config{}, nil, nil, nil, nil, nil, nil, nil, mockUserRepo, nil, nil, mockTokenService, nil, nil, nil)
+				sut := api.New(&config.Config{}, nil, nil, nil, nil, nil, nil, nil, mockUserRepo, nil, nil, mockTokenService, nil, nil, nil, nil, nil, nil, nil, nil, nil, nil, nil, nil, nil, nil, nil, nil)
 
 				nextHandler := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
 					authCtx, ok := request.GetAuthContext(r.Context())
@@ -333,7 +420,7 @@ var _ = Describe("HandleAuthentication", func() {
 				rr := httptest.NewRecorder()
 
 				mockTokenService.EXPECT().
-					Validate(req.Context(), "valid").
+					Validate(req.Context(), "valid", gomock.Any()).
 					Return(&core.AuthContext{
 						UserID:      1,
 						SessionID:   nil,
@@ -349,7 +436,7 @@ var _ = Describe("HandleAuthentication", func() {
 
 		When("token is invalid", func() {
 			It("returns status unauthorized", func() {
-				sut := api.New(&config.Config{}, nil, nil, nil, nil, nil, nil, nil, mockUserRepo, nil, nil, mockTokenService, nil, nil, nil)
+				sut := api.New(&config.Config{}, nil, nil, nil, nil, nil, nil, nil, mockUserRepo, nil, nil, mockTokenService, nil, nil, nil, nil, nil, nil, nil, nil, nil, nil, nil, nil, nil, nil, nil, nil)
 				m := sut.HandleAuthentication(nil)
 
 				req, err := http.NewRequest("GET", "/foobar", nil)
@@ -362,7 +449,7 @@ var _ = Describe("HandleAuthentication", func() {
 				rr := httptest.NewRecorder()
 
 				mockTokenService.EXPECT().
-					Validate(req.Context(), "invalid").
+					Validate(req.Context(), "invalid", gomock.Any()).
 					Return(nil, core.InvalidOrExpiredTokenError{
 						Reason: core.ErrTokenInvalid,
 					}).
@@ -377,7 +464,7 @@ var _ = Describe("HandleAuthentication", func() {
 
 		When("token service returns error", func() {
 			It("returns status internal server error", func() {
-				sut := api.New(&config.Config{}, nil, nil, nil, nil, nil, nil, nil, mockUserRepo, nil, nil, mockTokenService, nil, nil, nil)
+				sut := api.New(&config.Config{}, nil, nil, nil, nil, nil, nil, nil, mockUserRepo, nil, nil, mockTokenService, nil, nil, nil, nil, nil, nil, nil, nil, nil, nil, nil, nil, nil, nil, nil, nil)
 				m := sut.HandleAuthentication(nil)
 
 				req, err := http.NewRequest("GET", "/foobar", nil)
@@ -390,7 +477,7 @@ var _ = Describe("HandleAuthentication", func() {
 				rr := httptest.NewRecorder()
 
 				mockTokenService.EXPECT().
-					Validate(req.Context(), "invalid").
+					Validate(req.Context(), "invalid", gomock.Any()).
 					Return(nil, errors.New("unexpected error")).
 					Times(1)
 
@@ -424,7 +511,7 @@ var _ = Describe("HandleAuthentication", func() {
 			It("returns status ok", func() {
 				sessionID := int64(1234)
 
-				sut := api.New(cfg, nil, nil, nil, nil, nil, mockSessionManager, nil, mockUserRepo, nil, nil, mockTokenService, nil, nil, nil)
+				sut := api.New(cfg, nil, nil, nil, nil, nil, mockSessionManager, nil, mockUserRepo, nil, nil, mockTokenService, nil, nil, nil, nil, nil, nil, nil, nil, nil, nil, nil, nil, nil, nil, nil, nil)
 
 				nextHandler := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
 					authCtx, ok := request.GetAuthContext(r.Context())
@@ -468,7 +555,7 @@ var _ = Describe("HandleAuthentication", func() {
 				sessionID := int64(1234)
 				rotatedToken := "rotatedToken"
 
-				sut := api.New(cfg, nil, nil, nil, nil, nil, mockSessionManager, nil, mockUserRepo, nil, nil, mockTokenService, nil, nil, nil)
+				sut := api.New(cfg, nil, nil, nil, nil, nil, mockSessionManager, nil, mockUserRepo, nil, nil, mockTokenService, nil, nil, nil, nil, nil, nil, nil, nil, nil, nil, nil, nil, nil, nil, nil, nil)
 
 				nextHandler := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
 					authCtx, ok := request.GetAuthContext(r.Context())
@@ -512,7 +599,7 @@ var _ = Describe("HandleAuthentication", func() {
 
 		When("token is invalid", func() {
 			It("returns status unauthorized", func() {
-				sut := api.New(cfg, nil, nil, nil, nil, nil, mockSessionManager, nil, mockUserRepo, nil, nil, mockTokenService, nil, nil, nil)
+				sut := api.New(cfg, nil, nil, nil, nil, nil, mockSessionManager, nil, mockUserRepo, nil, nil, mockTokenService, nil, nil, nil, nil, nil, nil, nil, nil, nil, nil, nil, nil, nil, nil, nil, nil)
 				m := sut.HandleAuthentication(nil)
 
 				req, err := http.NewRequest("GET", "/foobar", nil)
@@ -543,7 +630,7 @@ var _ = Describe("HandleAuthentication", func() {
 
 		When("session manager returns error", func() {
 			It("returns status internal server error", func() {
-				sut := api.New(cfg, nil, nil, nil, nil, nil, mockSessionManager, nil, mockUserRepo, nil, nil, mockTokenService, nil, nil, nil)
+				sut := api.New(cfg, nil, nil, nil, nil, nil, mockSessionManager, nil, mockUserRepo, nil, nil, mockTokenService, nil, nil, nil, nil, nil, nil, nil, nil, nil, nil, nil, nil, nil, nil, nil, nil)
 				m := sut.HandleAuthentication(nil)
 
 				req, err := http.NewRequest("GET", "/foobar", nil)
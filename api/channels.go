@@ -39,14 +39,23 @@ func (s *router) GetChannels(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
+	filter, ok := s.contentFilter(w, r)
+	if !ok {
+		return
+	}
+
 	channels, err := s.channels.GetAll(r.Context(), q)
 	if err != nil {
 		log.Error().Err(err).Msg("failed to get channels")
 
-		response.InternalErrorCommon(w)
+		writeTvheadendError(w, err)
 		return
 	}
 
+	channels = filterEntries(channels, func(c *core.Channel) bool {
+		return filter.AllowsChannel(c.ID)
+	})
+
 	response.JSON(w, channels, 200)
 }
 
@@ -67,6 +76,16 @@ func (s *router) GetChannels(w http.ResponseWriter, r *http.Request) {
 func (s *router) GetChannel(w http.ResponseWriter, r *http.Request) {
 	id := chi.URLParam(r, "id")
 
+	filter, ok := s.contentFilter(w, r)
+	if !ok {
+		return
+	}
+
+	if !filter.AllowsChannel(id) {
+		writeContentRestricted(w)
+		return
+	}
+
 	channel, err := s.channels.Get(r.Context(), id)
 	if err != nil {
 		if err == core.ErrChannelNotFound {
@@ -77,7 +96,7 @@ func (s *router) GetChannel(w http.ResponseWriter, r *http.Request) {
 		log.Error().Str("id", id).
 			Err(err).Msg("failed to get channel")
 
-		response.InternalErrorCommon(w)
+		writeTvheadendError(w, err)
 		return
 	}
 
@@ -1,17 +1,20 @@
 package api_test
 
 import (
+	"context"
 	"encoding/json"
 	"errors"
 	"fmt"
 	"net/http"
 	"net/http/httptest"
 	"net/url"
+	"time"
 
 	"github.com/davidborzek/tvhgo/api"
 	"github.com/davidborzek/tvhgo/config"
 	"github.com/davidborzek/tvhgo/core"
 	mock_core "github.com/davidborzek/tvhgo/mock/core"
+	"github.com/davidborzek/tvhgo/tvheadend"
 	. "github.com/onsi/ginkgo/v2"
 	. "github.com/onsi/gomega"
 	"go.uber.org/mock/gomock"
@@ -21,19 +24,37 @@ var _ = Describe("Channels", func() {
 	var mockCtrl *gomock.Controller
 	var mockChannelService *mock_core.MockChannelService
 	var mockTokenService *mock_core.MockTokenService
+	var mockUserRepository *mock_core.MockUserRepository
+	var mockParentalControls *mock_core.MockParentalControlsService
+	var filter *core.ContentFilter
 	var sut http.Handler
 
 	BeforeEach(func() {
 		mockCtrl = gomock.NewController(GinkgoT())
 		mockChannelService = mock_core.NewMockChannelService(mockCtrl)
 		mockTokenService = mock_core.NewMockTokenService(mockCtrl)
+		mockUserRepository = mock_core.NewMockUserRepository(mockCtrl)
+		mockParentalControls = mock_core.NewMockParentalControlsService(mockCtrl)
+		filter = nil
 
 		mockTokenService.EXPECT().
-			Validate(gomock.Any(), gomock.Any()).
+			Validate(gomock.Any(), gomock.Any(), gomock.Any()).
 			Return(&core.AuthContext{}, nil).
 			AnyTimes()
 
-		sut = api.New(&config.Config{}, mockChannelService, nil, nil, nil, nil, nil, nil, nil, nil, nil, mockTokenService, nil, nil, nil).
+		mockUserRepository.EXPECT().
+			FindById(gomock.Any(), gomock.Any()).
+			Return(&core.User{Role: core.RoleViewer}, nil).
+			AnyTimes()
+
+		mockParentalControls.EXPECT().
+			Filter(gomock.Any(), gomock.Any()).
+			DoAndReturn(func(context.Context, int64) (*core.ContentFilter, error) {
+				return filter, nil
+			}).
+			AnyTimes()
+
+		sut = api.New(&config.Config{}, mockChannelService, nil, nil, nil, nil, nil, nil, mockUserRepository, nil, nil, mockTokenService, nil, nil, nil, nil, nil, nil, nil, nil, nil, nil, nil, nil, nil, mockParentalControls, nil, nil).
 			Handler()
 
 	})
@@ -63,6 +84,26 @@ var _ = Describe("Channels", func() {
 			Expect(rr.Body.String()).To(MatchJSON(`{"message":"unexpected error"}`))
 		})
 
+		It("should return 503 when tvheadend is unavailable", func() {
+			req, err := http.NewRequest("GET", "/channels", nil)
+			if err != nil {
+				Fail(err.Error())
+			}
+			req.Header.Set("Authorization", "Bearer token")
+
+			rr := httptest.NewRecorder()
+
+			mockChannelService.EXPECT().
+				GetAll(gomock.Any(), core.PaginationSortQueryParams{}).
+				Return(nil, fmt.Errorf("%w: connection refused", tvheadend.ErrTvheadendUnavailable)).
+				Times(1)
+
+			sut.ServeHTTP(rr, req)
+
+			Expect(rr.Code).To(Equal(http.StatusServiceUnavailable))
+			Expect(rr.Body.String()).To(MatchJSON(`{"message":"tvheadend is unavailable","code":"tvheadend_unavailable"}`))
+		})
+
 		DescribeTable(
 			"should return 400 on invalid query parameters",
 			func(limit, offset, sortKey, sortDir, errMsg string) {
@@ -187,6 +228,37 @@ var _ = Describe("Channels", func() {
 		Expect(rr.Body.String()).To(MatchJSON(string(expected)))
 	})
 
+	It("should only return channels allowed by parental controls", func() {
+		req, err := http.NewRequest("GET", "/channels", nil)
+		if err != nil {
+			Fail(err.Error())
+		}
+		req.Header.Set("Authorization", "Bearer token")
+
+		rr := httptest.NewRecorder()
+
+		channels := []*core.Channel{
+			{ID: "1", Name: "kids", Number: 1, Tags: []string{"kidsTag"}},
+			{ID: "2", Name: "movies", Number: 2},
+		}
+
+		filter = core.NewContentFilter(&core.ParentalControls{
+			ChannelTags: []string{"kidsTag"},
+		}, channels, time.Now())
+
+		mockChannelService.EXPECT().
+			GetAll(gomock.Any(), gomock.Any()).
+			Return(channels, nil).
+			Times(1)
+
+		sut.ServeHTTP(rr, req)
+
+		expected, _ := json.Marshal(channels[:1])
+
+		Expect(rr.Code).To(Equal(http.StatusOK))
+		Expect(rr.Body.String()).To(MatchJSON(string(expected)))
+	})
+
 	Describe("GetChannel", func() {
 		DescribeTable(
 			"should return erroneous status code",
@@ -252,5 +324,26 @@ var _ = Describe("Channels", func() {
 			Expect(rr.Code).To(Equal(http.StatusOK))
 			Expect(rr.Body.String()).To(MatchJSON(string(expected)))
 		})
+
+		It("should return 403 when channel is restricted by parental controls", func() {
+			req, err := http.NewRequest("GET", "/channels/foobar", nil)
+			if err != nil {
+				Fail(err.Error())
+			}
+			req.Header.Set("Authorization", "Bearer token")
+
+			rr := httptest.NewRecorder()
+
+			filter = core.NewContentFilter(&core.ParentalControls{
+				Channels: []string{"kids"},
+			}, []*core.Channel{{ID: "kids"}, {ID: "foobar"}}, time.Now())
+
+			sut.ServeHTTP(rr, req)
+
+			Expect(rr.Code).To(Equal(http.StatusForbidden))
+			Expect(rr.Body.String()).To(MatchJSON(
+				`{"message":"content is restricted by parental controls","code":"content_restricted"}`,
+			))
+		})
 	})
 })
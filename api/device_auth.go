@@ -0,0 +1,243 @@
+package api
+
+import (
+	"errors"
+	"net/http"
+
+	"github.com/davidborzek/tvhgo/api/request"
+	"github.com/davidborzek/tvhgo/api/response"
+	"github.com/davidborzek/tvhgo/core"
+	"github.com/go-chi/chi/v5"
+	"github.com/rs/zerolog/log"
+)
+
+const deviceCodeGrantType = "urn:ietf:params:oauth:grant-type:device_code"
+
+// deviceTokenError is the error response of the device token
+// endpoint as defined by RFC 6749, section 5.2 and RFC 8628, section 3.5.
+type deviceTokenError struct {
+	Error            string `json:"error"`
+	ErrorDescription string `json:"error_description"`
+}
+
+type approveDeviceRequest struct {
+	// Scopes restrict the api token issued to the device.
+	// Without scopes the device gets a session.
+	Scopes []core.Permission `json:"scopes"`
+}
+
+// CreateDeviceCode godoc
+//
+//	@Summary	Starts the device authorization grant for a device
+//	@Tags		device-auth
+//	@Accept		x-www-form-urlencoded
+//	@Param		device_name	formData	string	true	"Friendly name of the device"
+//	@Produce	json
+//	@Success	200	{object}	core.DeviceCode
+//	@Failure	400	{object}	response.ErrorResponse
+//	@Failure	429	{object}	response.ErrorResponse
+//	@Failure	500	{object}	response.ErrorResponse
+//	@Router		/auth/device/code [post]
+func (s *router) CreateDeviceCode(w http.ResponseWriter, r *http.Request) {
+	code, err := s.deviceAuth.Start(
+		r.Context(),
+		r.PostFormValue("device_name"),
+		request.RemoteAddr(r),
+	)
+	if err != nil {
+		if errors.Is(err, core.ErrDeviceNameInvalid) {
+			response.BadRequest(w, err)
+			return
+		}
+
+		if errors.Is(err, core.ErrDeviceCodeLimit) {
+			response.TooManyRequests(w, err, "device_code_limit")
+			return
+		}
+
+		response.InternalError(w, err)
+		return
+	}
+
+	response.JSON(w, code, http.StatusOK)
+}
+
+// CreateDeviceToken godoc
+//
+//	@Summary	Polls for the session or token of an authorized device
+//	@Tags		device-auth
+//	@Accept		x-www-form-urlencoded
+//	@Param		grant_type	formData	string	true	"urn:ietf:params:oauth:grant-type:device_code"
+//	@Param		device_code	formData	string	true	"Device code"
+//	@Produce	json
+//	@Success	200	{object}	core.DeviceToken
+//	@Failure	400	{object}	deviceTokenError
+//	@Failure	500	{object}	response.ErrorResponse
+//	@Router		/auth/device/token [post]
+func (s *router) CreateDeviceToken(w http.ResponseWriter, r *http.Request) {
+	if r.PostFormValue("grant_type") != deviceCodeGrantType {
+		writeDeviceTokenError(w, "unsupported_grant_type", "unsupported grant type")
+		return
+	}
+
+	token, err := s.deviceAuth.Poll(
+		r.Context(),
+		r.PostFormValue("device_code"),
+		request.RemoteAddr(r),
+		r.UserAgent(),
+	)
+	if err != nil {
+		switch {
+		case errors.Is(err, core.ErrDeviceAuthPending):
+			writeDeviceTokenError(w, "authorization_pending", err.Error())
+		case errors.Is(err, core.ErrDeviceAuthSlowDown):
+			writeDeviceTokenError(w, "slow_down", err.Error())
+		case errors.Is(err, core.ErrDeviceAuthDenied):
+			writeDeviceTokenError(w, "access_denied", err.Error())
+		case errors.Is(err, core.ErrDeviceAuthExpired):
+			writeDeviceTokenError(w, "expired_token", err.Error())
+		case errors.Is(err, core.ErrDeviceCodeInvalid):
+			writeDeviceTokenError(w, "invalid_grant", err.Error())
+		default:
+			response.InternalError(w, err)
+		}
+		return
+	}
+
+	if token.TokenType == core.DeviceTokenTypeSession {
+		s.recordLoginAudit(r, token.UserID)
+
+		setSessionCookie(
+			w,
+			s.cfg.Auth.Session.CookieName,
+			token.AccessToken,
+			s.cfg.Auth.Session.CookieSecure,
+		)
+	} else {
+		s.recordAuditEntry(r, &core.AuditEntry{
+			UserID: &token.UserID,
+			Action: core.AuditActionTokenCreate,
+			Target: token.DeviceName,
+		})
+	}
+
+	response.JSON(w, token, http.StatusOK)
+}
+
+// GetDeviceAuthorization godoc
+//
+//	@Summary	Get a pending device authorization by the user code
+//	@Tags		device-auth
+//	@Param		code	path	string	true	"User code"
+//	@Produce	json
+//	@Success	200	{object}	core.DeviceAuthorization
+//	@Failure	401	{object}	response.ErrorResponse
+//	@Failure	404	{object}	response.ErrorResponse
+//	@Failure	500	{object}	response.ErrorResponse
+//	@Security	JWT
+//	@Router		/auth/device/{code} [get]
+func (s *router) GetDeviceAuthorization(w http.ResponseWriter, r *http.Request) {
+	auth, err := s.deviceAuth.Find(r.Context(), chi.URLParam(r, "code"))
+	if err != nil {
+		writeDeviceAuthorizationError(w, err)
+		return
+	}
+
+	response.JSON(w, auth, http.StatusOK)
+}
+
+// ApproveDeviceAuthorization godoc
+//
+//	@Summary	Approves a pending device authorization
+//	@Tags		device-auth
+//	@Param		code	path	string					true	"User code"
+//	@Param		body	body	approveDeviceRequest	true	"Body"
+//	@Produce	json
+//	@Success	200	{object}	core.DeviceAuthorization
+//	@Failure	400	{object}	response.ErrorResponse
+//	@Failure	401	{object}	response.ErrorResponse
+//	@Failure	403	{object}	response.ErrorResponse
+//	@Failure	404	{object}	response.ErrorResponse
+//	@Failure	500	{object}	response.ErrorResponse
+//	@Security	JWT
+//	@Router		/auth/device/{code}/approve [put]
+func (s *router) ApproveDeviceAuthorization(w http.ResponseWriter, r *http.Request) {
+	ctx, ok := request.GetAuthContext(r.Context())
+	if !ok {
+		response.InternalErrorCommon(w)
+		return
+	}
+
+	if ctx.SessionID == nil {
+		response.Forbiddenf(w, "a device can only be approved via a web session")
+		return
+	}
+
+	var in approveDeviceRequest
+	if err := request.BindJSON(r, &in); err != nil {
+		response.BadRequest(w, err)
+		return
+	}
+
+	auth, err := s.deviceAuth.Approve(r.Context(), chi.URLParam(r, "code"), ctx.UserID, in.Scopes)
+	if err != nil {
+		writeDeviceAuthorizationError(w, err)
+		return
+	}
+
+	s.recordAudit(r, core.AuditActionDeviceApprove, auth.DeviceName)
+
+	response.JSON(w, auth, http.StatusOK)
+}
+
+// DenyDeviceAuthorization godoc
+//
+//	@Summary	Denies a pending device authorization
+//	@Tags		device-auth
+//	@Param		code	path	string	true	"User code"
+//	@Produce	json
+//	@Success	200	{object}	core.DeviceAuthorization
+//	@Failure	401	{object}	response.ErrorResponse
+//	@Failure	404	{object}	response.ErrorResponse
+//	@Failure	500	{object}	response.ErrorResponse
+//	@Security	JWT
+//	@Router		/auth/device/{code}/deny [put]
+func (s *router) DenyDeviceAuthorization(w http.ResponseWriter, r *http.Request) {
+	ctx, ok := request.GetAuthContext(r.Context())
+	if !ok {
+		response.InternalErrorCommon(w)
+		return
+	}
+
+	auth, err := s.deviceAuth.Deny(r.Context(), chi.URLParam(r, "code"), ctx.UserID)
+	if err != nil {
+		writeDeviceAuthorizationError(w, err)
+		return
+	}
+
+	s.recordAudit(r, core.AuditActionDeviceDeny, auth.DeviceName)
+
+	response.JSON(w, auth, http.StatusOK)
+}
+
+// writeDeviceTokenError writes an error of the device token endpoint.
+func writeDeviceTokenError(w http.ResponseWriter, code string, description string) {
+	response.JSON(w, &deviceTokenError{
+		Error:            code,
+		ErrorDescription: description,
+	}, http.StatusBadRequest)
+}
+
+// writeDeviceAuthorizationError writes an error of the approval of a device.
+func writeDeviceAuthorizationError(w http.ResponseWriter, err error) {
+	switch {
+	case errors.Is(err, core.ErrDeviceUserCodeInvalid):
+		response.NotFound(w, err)
+	case errors.Is(err, core.ErrTokenScopeInvalid):
+		response.BadRequest(w, err)
+	default:
+		log.Error().Err(err).Msg("failed to decide device authorization")
+
+		response.InternalErrorCommon(w)
+	}
+}
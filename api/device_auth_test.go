@@ -0,0 +1,235 @@
+package api_test
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"strings"
+
+	"github.com/davidborzek/tvhgo/api"
+	"github.com/davidborzek/tvhgo/config"
+	"github.com/davidborzek/tvhgo/core"
+	mock_core "github.com/davidborzek/tvhgo/mock/core"
+	"go.uber.org/mock/gomock"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("Device authorization", func() {
+	var mockCtrl *gomock.Controller
+	var mockSessionManager *mock_core.MockSessionManager
+	var mockTokenService *mock_core.MockTokenService
+	var mockUserRepository *mock_core.MockUserRepository
+	var mockDeviceAuth *mock_core.MockDeviceAuthService
+	var mockAudit *mock_core.MockAuditService
+
+	cfg := &config.Config{
+		Auth: config.AuthConfig{
+			Session: config.SessionConfig{
+				CookieName: "tvhgo_session",
+			},
+			Device: config.DeviceAuthConfig{
+				Enabled: true,
+			},
+		},
+	}
+
+	serve := func(req *http.Request) *httptest.ResponseRecorder {
+		sut := api.New(cfg, nil, nil, nil, nil, nil, mockSessionManager, nil, mockUserRepository, nil,
+			nil, mockTokenService, nil, nil, nil, nil, nil, nil, nil, nil, nil, nil, nil, mockAudit, mockDeviceAuth, nil, nil, nil)
+
+		rr := httptest.NewRecorder()
+		sut.Handler().ServeHTTP(rr, req)
+		return rr
+	}
+
+	newFormRequest := func(path string, form url.Values) *http.Request {
+		req, err := http.NewRequest("POST", path, strings.NewReader(form.Encode()))
+		if err != nil {
+			Fail(err.Error())
+		}
+		req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+		req.RemoteAddr = "192.168.1.10:1234"
+		return req
+	}
+
+	pollRequest := func() *http.Request {
+		return newFormRequest("/auth/device/token", url.Values{
+			"grant_type":  {"urn:ietf:params:oauth:grant-type:device_code"},
+			"device_code": {"someDeviceCode"},
+		})
+	}
+
+	BeforeEach(func() {
+		mockCtrl = gomock.NewController(GinkgoT())
+		mockSessionManager = mock_core.NewMockSessionManager(mockCtrl)
+		mockTokenService = mock_core.NewMockTokenService(mockCtrl)
+		mockUserRepository = mock_core.NewMockUserRepository(mockCtrl)
+		mockDeviceAuth = mock_core.NewMockDeviceAuthService(mockCtrl)
+		mockAudit = mock_core.NewMockAuditService(mockCtrl)
+
+		mockUserRepository.EXPECT().
+			FindById(gomock.Any(), int64(1)).
+			Return(&core.User{ID: 1, Role: core.RoleViewer}, nil).
+			AnyTimes()
+	})
+
+	AfterEach(func() {
+		mockCtrl.Finish()
+	})
+
+	It("starts the authorization of a device", func() {
+		mockDeviceAuth.EXPECT().
+			Start(gomock.Any(), "Living Room TV", "192.168.1.10").
+			Return(&core.DeviceCode{
+				DeviceCode:              "someDeviceCode",
+				UserCode:                "BCDF-GHJK",
+				VerificationURI:         "https://tvhgo.example.com/device",
+				VerificationURIComplete: "https://tvhgo.example.com/device?code=BCDF-GHJK",
+				ExpiresIn:               600,
+				Interval:                5,
+			}, nil)
+
+		rr := serve(newFormRequest("/auth/device/code", url.Values{"device_name": {"Living Room TV"}}))
+
+		Expect(rr.Code).To(Equal(http.StatusOK))
+		Expect(rr.Body.String()).To(MatchJSON(`{
+			"device_code": "someDeviceCode",
+			"user_code": "BCDF-GHJK",
+			"verification_uri": "https://tvhgo.example.com/device",
+			"verification_uri_complete": "https://tvhgo.example.com/device?code=BCDF-GHJK",
+			"expires_in": 600,
+			"interval": 5
+		}`))
+	})
+
+	It("returns too many requests when the pending device codes are exhausted", func() {
+		mockDeviceAuth.EXPECT().
+			Start(gomock.Any(), "Living Room TV", "192.168.1.10").
+			Return(nil, core.ErrDeviceCodeLimit)
+
+		rr := serve(newFormRequest("/auth/device/code", url.Values{"device_name": {"Living Room TV"}}))
+
+		Expect(rr.Code).To(Equal(http.StatusTooManyRequests))
+		Expect(rr.Body.String()).To(ContainSubstring(`"code":"device_code_limit"`))
+	})
+
+	It("rejects an unsupported grant type", func() {
+		rr := serve(newFormRequest("/auth/device/token", url.Values{
+			"grant_type":  {"password"},
+			"device_code": {"someDeviceCode"},
+		}))
+
+		Expect(rr.Code).To(Equal(http.StatusBadRequest))
+		Expect(rr.Body.String()).To(ContainSubstring(`"error":"unsupported_grant_type"`))
+	})
+
+	DescribeTable("returns the poll errors of RFC 8628",
+		func(pollErr error, code string) {
+			mockDeviceAuth.EXPECT().
+				Poll(gomock.Any(), "someDeviceCode", "192.168.1.10", gomock.Any()).
+				Return(nil, pollErr)
+
+			rr := serve(pollRequest())
+
+			Expect(rr.Code).To(Equal(http.StatusBadRequest))
+			Expect(rr.Body.String()).To(MatchJSON(`{"error":"` + code + `","error_description":"` + pollErr.Error() + `"}`))
+		},
+		Entry("pending", core.ErrDeviceAuthPending, "authorization_pending"),
+		Entry("slow down", core.ErrDeviceAuthSlowDown, "slow_down"),
+		Entry("denied", core.ErrDeviceAuthDenied, "access_denied"),
+		Entry("expired", core.ErrDeviceAuthExpired, "expired_token"),
+		Entry("invalid", core.ErrDeviceCodeInvalid, "invalid_grant"),
+	)
+
+	It("issues a session to an approved device", func() {
+		mockDeviceAuth.EXPECT().
+			Poll(gomock.Any(), "someDeviceCode", "192.168.1.10", gomock.Any()).
+			Return(&core.DeviceToken{
+				AccessToken: "someSessionToken",
+				TokenType:   core.DeviceTokenTypeSession,
+				UserID:      1,
+				DeviceName:  "Living Room TV",
+			}, nil)
+
+		userID := int64(1)
+		mockAudit.EXPECT().
+			Record(gomock.Any(), &core.AuditEntry{
+				UserID:   &userID,
+				Action:   core.AuditActionLogin,
+				ClientIP: "192.168.1.10",
+			})
+
+		rr := serve(pollRequest())
+
+		Expect(rr.Code).To(Equal(http.StatusOK))
+		Expect(rr.Body.String()).To(MatchJSON(`{"access_token":"someSessionToken","token_type":"session"}`))
+		Expect(rr.Header().Get("Set-Cookie")).To(ContainSubstring("tvhgo_session=someSessionToken"))
+	})
+
+	It("approves a device via a web session", func() {
+		sessionID := int64(2)
+		mockSessionManager.EXPECT().
+			Validate(gomock.Any(), "someSession").
+			Return(&core.AuthContext{UserID: 1, SessionID: &sessionID}, nil, nil)
+
+		scopes := []core.Permission{core.PermissionEPGRead, core.PermissionStream}
+		mockDeviceAuth.EXPECT().
+			Approve(gomock.Any(), "bcdf-ghjk", int64(1), scopes).
+			Return(&core.DeviceAuthorization{
+				UserCode:   "BCDF-GHJK",
+				DeviceName: "Living Room TV",
+				Status:     core.DeviceAuthStatusApproved,
+				Scopes:     scopes,
+			}, nil)
+
+		mockAudit.EXPECT().
+			Record(gomock.Any(), gomock.Any()).
+			Do(func(_ context.Context, entry *core.AuditEntry) {
+				Expect(entry.Action).To(Equal(core.AuditActionDeviceApprove))
+				Expect(entry.Target).To(Equal("Living Room TV"))
+			})
+
+		req, _ := http.NewRequest("PUT", "/auth/device/bcdf-ghjk/approve",
+			strings.NewReader(`{"scopes":["epg:read","stream"]}`))
+		req.AddCookie(&http.Cookie{Name: "tvhgo_session", Value: "someSession"})
+
+		rr := serve(req)
+
+		Expect(rr.Code).To(Equal(http.StatusOK))
+		Expect(rr.Body.String()).To(ContainSubstring(`"status":"approved"`))
+	})
+
+	It("forbids to approve a device via an api token", func() {
+		mockTokenService.EXPECT().
+			Validate(gomock.Any(), "someToken", gomock.Any()).
+			Return(&core.AuthContext{UserID: 1}, nil)
+
+		req, _ := http.NewRequest("PUT", "/auth/device/BCDF-GHJK/approve", strings.NewReader(`{}`))
+		req.Header.Set("Authorization", "Bearer someToken")
+
+		rr := serve(req)
+
+		Expect(rr.Code).To(Equal(http.StatusForbidden))
+	})
+
+	It("returns not found for an unknown user code", func() {
+		mockTokenService.EXPECT().
+			Validate(gomock.Any(), "someToken", gomock.Any()).
+			Return(&core.AuthContext{UserID: 1}, nil)
+
+		mockDeviceAuth.EXPECT().
+			Find(gomock.Any(), "BCDF-GHJK").
+			Return(nil, core.ErrDeviceUserCodeInvalid)
+
+		req, _ := http.NewRequest("GET", "/auth/device/BCDF-GHJK", nil)
+		req.Header.Set("Authorization", "Bearer someToken")
+
+		rr := serve(req)
+
+		Expect(rr.Code).To(Equal(http.StatusNotFound))
+		Expect(rr.Body.String()).To(MatchJSON(`{"message":"invalid or expired user code"}`))
+	})
+})
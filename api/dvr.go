@@ -25,7 +25,7 @@ func (s *router) GetDVRConfigList(w http.ResponseWriter, r *http.Request) {
 	if err != nil {
 		log.Error().Err(err).Msg("failed to get channels")
 
-		response.InternalErrorCommon(w)
+		writeTvheadendError(w, err)
 		return
 	}
 
@@ -52,7 +52,7 @@ func (s *router) GetDVRConfig(w http.ResponseWriter, r *http.Request) {
 	if err != nil {
 		log.Error().Err(err).Msg("failed to get channels")
 
-		response.InternalErrorCommon(w)
+		writeTvheadendError(w, err)
 		return
 	}
 
@@ -95,9 +95,11 @@ func (s *router) DeleteDVRConfig(w http.ResponseWriter, r *http.Request) {
 
 		log.Error().Err(err).Msg("failed to delete dvr config")
 
-		response.InternalErrorCommon(w)
+		writeTvheadendError(w, err)
 		return
 	}
 
+	s.recordAudit(r, core.AuditActionDVRConfigDelete, id)
+
 	response.JSON(w, nil, 204)
 }
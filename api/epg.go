@@ -47,14 +47,25 @@ func (s *router) GetEpgEvents(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
+	filter, ok := s.contentFilter(w, r)
+	if !ok {
+		return
+	}
+
+	page := unpaginate(filter, &q.PaginationQueryParams)
+
 	events, err := s.epg.GetEvents(r.Context(), q)
 	if err != nil {
 		log.Error().Err(err).Msg("failed to get epg events")
 
-		response.InternalErrorCommon(w)
+		writeTvheadendError(w, err)
 		return
 	}
 
+	if filter != nil {
+		filterListResult(events, filter.AllowsEvent, page)
+	}
+
 	response.JSON(w, events, 200)
 }
 
@@ -86,14 +97,27 @@ func (s *router) GetEpg(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
+	filter, ok := s.contentFilter(w, r)
+	if !ok {
+		return
+	}
+
 	events, err := s.epg.GetEpg(r.Context(), q)
 	if err != nil {
 		log.Error().Err(err).Msg("failed to get epg")
 
-		response.InternalErrorCommon(w)
+		writeTvheadendError(w, err)
 		return
 	}
 
+	events = filterEntries(events, func(c *core.EpgChannel) bool {
+		return filter.AllowsChannel(c.ChannelID)
+	})
+
+	for _, channel := range events {
+		channel.Events = filterEntries(channel.Events, filter.AllowsEvent)
+	}
+
 	response.JSON(w, events, 200)
 }
 
@@ -118,6 +142,11 @@ func (s *router) GetEpgEvent(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
+	filter, ok := s.contentFilter(w, r)
+	if !ok {
+		return
+	}
+
 	event, err := s.epg.GetEvent(r.Context(), id)
 	if err != nil {
 		if err == core.ErrEpgEventNotFound {
@@ -128,7 +157,12 @@ func (s *router) GetEpgEvent(w http.ResponseWriter, r *http.Request) {
 		log.Error().Int64("id", id).
 			Err(err).Msg("failed to get epg event")
 
-		response.InternalErrorCommon(w)
+		writeTvheadendError(w, err)
+		return
+	}
+
+	if !filter.AllowsEvent(event) {
+		writeContentRestricted(w)
 		return
 	}
 
@@ -170,14 +204,25 @@ func (s *router) GetRelatedEpgEvents(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
+	filter, ok := s.contentFilter(w, r)
+	if !ok {
+		return
+	}
+
+	page := unpaginate(filter, &q.PaginationQueryParams)
+
 	events, err := s.epg.GetRelatedEvents(r.Context(), id, q)
 	if err != nil {
 		log.Error().Err(err).Msg("failed to get related epg events")
 
-		response.InternalErrorCommon(w)
+		writeTvheadendError(w, err)
 		return
 	}
 
+	if filter != nil {
+		filterListResult(events, filter.AllowsEvent, page)
+	}
+
 	response.JSON(w, events, 200)
 }
 
@@ -198,7 +243,7 @@ func (s *router) GetEpgContentTypes(w http.ResponseWriter, r *http.Request) {
 	if err != nil {
 		log.Error().Err(err).Msg("failed to get epg content types")
 
-		response.InternalErrorCommon(w)
+		writeTvheadendError(w, err)
 		return
 	}
 
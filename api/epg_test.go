@@ -0,0 +1,139 @@
+package api_test
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"time"
+
+	"github.com/davidborzek/tvhgo/api"
+	"github.com/davidborzek/tvhgo/config"
+	"github.com/davidborzek/tvhgo/core"
+	mock_core "github.com/davidborzek/tvhgo/mock/core"
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	"go.uber.org/mock/gomock"
+)
+
+// fakeEpgService returns the events of a function, since
+// mockgen can't generate mocks for the generic list results.
+type fakeEpgService struct {
+	core.EpgService
+	getEvents func(q core.GetEpgEventsQueryParams) (*core.EpgEventsResult, error)
+}
+
+func (s *fakeEpgService) GetEvents(_ context.Context, q core.GetEpgEventsQueryParams) (*core.EpgEventsResult, error) {
+	return s.getEvents(q)
+}
+
+var _ = Describe("Epg", func() {
+	var mockCtrl *gomock.Controller
+	var epgService *fakeEpgService
+	var mockTokenService *mock_core.MockTokenService
+	var mockUserRepository *mock_core.MockUserRepository
+	var mockParentalControls *mock_core.MockParentalControlsService
+	var filter *core.ContentFilter
+	var sut http.Handler
+
+	events := []*core.EpgEvent{
+		{ID: 1, ChannelID: "kids", Title: "kids1"},
+		{ID: 2, ChannelID: "news", Title: "news"},
+		{ID: 3, ChannelID: "kids", Title: "kids2"},
+	}
+
+	BeforeEach(func() {
+		mockCtrl = gomock.NewController(GinkgoT())
+		epgService = &fakeEpgService{}
+		mockTokenService = mock_core.NewMockTokenService(mockCtrl)
+		mockUserRepository = mock_core.NewMockUserRepository(mockCtrl)
+		mockParentalControls = mock_core.NewMockParentalControlsService(mockCtrl)
+		filter = nil
+
+		mockTokenService.EXPECT().
+			Validate(gomock.Any(), gomock.Any(), gomock.Any()).
+			Return(&core.AuthContext{}, nil).
+			AnyTimes()
+
+		mockUserRepository.EXPECT().
+			FindById(gomock.Any(), gomock.Any()).
+			Return(&core.User{Role: core.RoleViewer}, nil).
+			AnyTimes()
+
+		mockParentalControls.EXPECT().
+			Filter(gomock.Any(), gomock.Any()).
+			DoAndReturn(func(context.Context, int64) (*core.ContentFilter, error) {
+				return filter, nil
+			}).
+			AnyTimes()
+
+		sut = api.New(&config.Config{}, nil, epgService, nil, nil, nil, nil, nil, mockUserRepository, nil, nil, mockTokenService, nil, nil, nil, nil, nil, nil, nil, nil, nil, nil, nil, nil, nil, mockParentalControls, nil, nil).
+			Handler()
+	})
+
+	AfterEach(func() {
+		mockCtrl.Finish()
+	})
+
+	newRequest := func(target string) *http.Request {
+		req := httptest.NewRequest("GET", target, nil)
+		req.Header.Set("Authorization", "Bearer token")
+		return req
+	}
+
+	Describe("GetEpgEvents", func() {
+		It("should pass the pagination to tvheadend for unrestricted users", func() {
+			epgService.getEvents = func(q core.GetEpgEventsQueryParams) (*core.EpgEventsResult, error) {
+				Expect(q.Limit).To(Equal(int64(1)))
+				Expect(q.Offset).To(Equal(int64(1)))
+
+				return &core.EpgEventsResult{Entries: events[1:2], Total: 3, Offset: 1}, nil
+			}
+
+			rr := httptest.NewRecorder()
+			sut.ServeHTTP(rr, newRequest("/epg/events?limit=1&offset=1"))
+
+			expected, _ := json.Marshal(core.EpgEventsResult{Entries: events[1:2], Total: 3, Offset: 1})
+
+			Expect(rr.Code).To(Equal(http.StatusOK))
+			Expect(rr.Body.String()).To(MatchJSON(string(expected)))
+		})
+
+		It("should filter the events of restricted users before paginating them", func() {
+			filter = core.NewContentFilter(&core.ParentalControls{
+				Channels: []string{"kids"},
+			}, []*core.Channel{{ID: "kids"}, {ID: "news"}}, time.Now())
+
+			epgService.getEvents = func(q core.GetEpgEventsQueryParams) (*core.EpgEventsResult, error) {
+				Expect(q.Limit).To(Equal(int64(10000)))
+				Expect(q.Offset).To(BeZero())
+
+				return &core.EpgEventsResult{Entries: events, Total: 3}, nil
+			}
+
+			rr := httptest.NewRecorder()
+			sut.ServeHTTP(rr, newRequest("/epg/events?limit=1&offset=1"))
+
+			expected, _ := json.Marshal(core.EpgEventsResult{Entries: events[2:], Total: 2, Offset: 1})
+
+			Expect(rr.Code).To(Equal(http.StatusOK))
+			Expect(rr.Body.String()).To(MatchJSON(string(expected)))
+		})
+
+		It("should return an empty page after the allowed events", func() {
+			filter = core.NewContentFilter(&core.ParentalControls{
+				Channels: []string{"kids"},
+			}, []*core.Channel{{ID: "kids"}, {ID: "news"}}, time.Now())
+
+			epgService.getEvents = func(core.GetEpgEventsQueryParams) (*core.EpgEventsResult, error) {
+				return &core.EpgEventsResult{Entries: events, Total: 3}, nil
+			}
+
+			rr := httptest.NewRecorder()
+			sut.ServeHTTP(rr, newRequest("/epg/events?limit=2&offset=2"))
+
+			Expect(rr.Code).To(Equal(http.StatusOK))
+			Expect(rr.Body.String()).To(MatchJSON(`{"entries":[],"total":2,"offset":2}`))
+		})
+	})
+})
@@ -0,0 +1,145 @@
+package api
+
+import (
+	"errors"
+	"net/http"
+	"strconv"
+
+	"github.com/davidborzek/tvhgo/api/request"
+	"github.com/davidborzek/tvhgo/api/response"
+	"github.com/davidborzek/tvhgo/core"
+	"github.com/go-chi/chi/v5"
+	"github.com/rs/zerolog/log"
+)
+
+// GetChannelHLSPlaylist godoc
+//
+//	@Summary	Get the HLS playlist of a channel stream
+//	@Tags		channels
+//	@Param		number	path	string	true	"Channel number"
+//	@Param		profile	query	string	false	"Streaming profile"
+//	@Produce	application/vnd.apple.mpegurl
+//	@Produce	json
+//	@Success	200
+//	@Failure	401	{object}	response.ErrorResponse
+//	@Failure	403	{object}	response.ErrorResponse
+//	@Failure	410	{object}	response.ErrorResponse
+//	@Failure	429	{object}	response.ErrorResponse
+//	@Failure	503	{object}	response.ErrorResponse
+//	@Security	JWT
+//	@Router		/channels/{number}/hls/index.m3u8 [get]
+func (s *router) GetChannelHLSPlaylist(w http.ResponseWriter, r *http.Request) {
+	number, err := request.NumericURLParam(r, "number")
+	if err != nil {
+		response.BadRequestf(w, "invalid value for parameter 'number'")
+		return
+	}
+
+	viewer, ok := hlsViewer(w, r, number)
+	if !ok {
+		return
+	}
+
+	// The parental controls are only checked once for a new viewer
+	// and not for each request of the playlist or a segment.
+	authorize := func() error {
+		return s.checkChannelStream(r, number)
+	}
+
+	// The query is passed to the segment uris to keep the profile
+	// and the signature of signed stream urls.
+	playlist, err := s.hls.GetPlaylist(r.Context(), viewer, r.URL.RawQuery, authorize)
+	if err != nil {
+		writeHLSError(w, err, number)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/vnd.apple.mpegurl")
+	w.WriteHeader(http.StatusOK)
+	w.Write(playlist)
+}
+
+// GetChannelHLSSegment godoc
+//
+//	@Summary	Get a HLS segment of a channel stream
+//	@Tags		channels
+//	@Param		number		path	string	true	"Channel number"
+//	@Param		sequence	path	string	true	"Segment sequence number"
+//	@Param		profile		query	string	false	"Streaming profile"
+//	@Produce	video/mp2t
+//	@Produce	json
+//	@Success	200
+//	@Failure	401	{object}	response.ErrorResponse
+//	@Failure	404	{object}	response.ErrorResponse
+//	@Failure	410	{object}	response.ErrorResponse
+//	@Security	JWT
+//	@Router		/channels/{number}/hls/{sequence}.ts [get]
+func (s *router) GetChannelHLSSegment(w http.ResponseWriter, r *http.Request) {
+	number, err := request.NumericURLParam(r, "number")
+	if err != nil {
+		response.BadRequestf(w, "invalid value for parameter 'number'")
+		return
+	}
+
+	sequence, err := strconv.ParseInt(chi.URLParam(r, "sequence"), 10, 64)
+	if err != nil {
+		response.BadRequestf(w, "invalid value for parameter 'sequence'")
+		return
+	}
+
+	viewer, ok := hlsViewer(w, r, number)
+	if !ok {
+		return
+	}
+
+	segment, err := s.hls.GetSegment(r.Context(), viewer, sequence)
+	if err != nil {
+		writeHLSError(w, err, number)
+		return
+	}
+
+	w.Header().Set("Content-Type", "video/mp2t")
+	w.Header().Set("Content-Length", strconv.Itoa(len(segment)))
+	w.WriteHeader(http.StatusOK)
+	w.Write(segment)
+}
+
+// hlsViewer returns the stream session of the viewer of a hls request.
+func hlsViewer(w http.ResponseWriter, r *http.Request, number int64) (core.StreamSession, bool) {
+	authCtx, ok := request.GetAuthContext(r.Context())
+	if !ok {
+		response.InternalErrorCommon(w)
+		return core.StreamSession{}, false
+	}
+
+	return core.StreamSession{
+		UserID:        authCtx.UserID,
+		ChannelNumber: number,
+		Profile:       r.URL.Query().Get("profile"),
+		ClientIP:      request.RemoteAddr(r),
+		UserAgent:     r.UserAgent(),
+	}, true
+}
+
+func writeHLSError(w http.ResponseWriter, err error, number int64) {
+	switch {
+	case errors.Is(err, core.ErrHLSStreamNotFound),
+		errors.Is(err, core.ErrHLSSegmentNotFound):
+		response.NotFound(w, err)
+	case errors.Is(err, core.ErrHLSStreamTimeout):
+		response.ServiceUnavailable(w, err, "hls_timeout")
+	case errors.Is(err, core.ErrContentRestricted):
+		writeContentRestricted(w)
+	case errors.Is(err, core.ErrStreamSessionTerminated):
+		response.ErrorWithCode(w, err, "stream_terminated", http.StatusGone)
+	case errors.Is(err, core.ErrStreamLimitPerUserReached),
+		errors.Is(err, core.ErrStreamLimitReached),
+		errors.Is(err, core.ErrTunersReserved):
+		writeStreamLimitError(w, err)
+	default:
+		log.Error().Int64("channel", number).
+			Err(err).Msg("failed to get hls stream")
+
+		writeTvheadendError(w, err)
+	}
+}
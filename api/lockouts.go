@@ -0,0 +1,74 @@
+package api
+
+import (
+	"errors"
+	"net/http"
+	"strconv"
+
+	"github.com/davidborzek/tvhgo/api/request"
+	"github.com/davidborzek/tvhgo/api/response"
+	"github.com/davidborzek/tvhgo/core"
+	"github.com/rs/zerolog/log"
+)
+
+// GetLockouts godoc
+//
+//	@Summary	Get list of client ips and usernames blocked after failed logins
+//	@Tags		lockouts
+//	@Produce	json
+//	@Success	200	{array}		core.LoginThrottle
+//	@Failure	401	{object}	response.ErrorResponse
+//	@Failure	403	{object}	response.ErrorResponse
+//	@Failure	500	{object}	response.ErrorResponse
+//	@Security	JWT
+//	@Router		/lockouts [get]
+func (s *router) GetLockouts(w http.ResponseWriter, r *http.Request) {
+	lockouts, err := s.loginThrottle.List(r.Context())
+	if err != nil {
+		log.Error().Err(err).Msg("failed to get lockouts")
+
+		response.InternalErrorCommon(w)
+		return
+	}
+
+	response.JSON(w, lockouts, http.StatusOK)
+}
+
+// DeleteLockout godoc
+//
+//	@Summary	Clears the lockout of a client ip or username
+//	@Tags		lockouts
+//	@Param		id	path	int	true	"Lockout id"
+//	@Produce	json
+//	@Success	204
+//	@Failure	400	{object}	response.ErrorResponse
+//	@Failure	401	{object}	response.ErrorResponse
+//	@Failure	403	{object}	response.ErrorResponse
+//	@Failure	404	{object}	response.ErrorResponse
+//	@Failure	500	{object}	response.ErrorResponse
+//	@Security	JWT
+//	@Router		/lockouts/{id} [delete]
+func (s *router) DeleteLockout(w http.ResponseWriter, r *http.Request) {
+	id, err := request.NumericURLParam(r, "id")
+	if err != nil {
+		response.BadRequestf(w, "invalid value for parameter 'id'")
+		return
+	}
+
+	if err := s.loginThrottle.Clear(r.Context(), id); err != nil {
+		if errors.Is(err, core.ErrLoginThrottleNotFound) {
+			response.NotFound(w, err)
+			return
+		}
+
+		log.Error().Int64("id", id).
+			Err(err).Msg("failed to clear lockout")
+
+		response.InternalErrorCommon(w)
+		return
+	}
+
+	s.recordAudit(r, core.AuditActionLockoutClear, strconv.FormatInt(id, 10))
+
+	w.WriteHeader(http.StatusNoContent)
+}
@@ -1,7 +1,10 @@
 package api
 
 import (
+	"errors"
+	"math"
 	"net/http"
+	"strconv"
 	"time"
 
 	"github.com/davidborzek/tvhgo/api/request"
@@ -16,9 +19,15 @@ var (
 )
 
 type loginRequest struct {
-	Username string  `json:"username"`
-	Password string  `json:"password"`
-	TOTP     *string `json:"totp"`
+	Username string `json:"username"`
+	Password string `json:"password"`
+	// TOTP is a totp code or a recovery code.
+	TOTP *string `json:"totp"`
+	// WebAuthn is a passkey assertion used as second factor instead of the totp code.
+	WebAuthn *webAuthnAssertion `json:"webauthn"`
+	// TrustDeviceDays trusts the device for a number of days, so that
+	// the second factor is skipped on further logins from this device.
+	TrustDeviceDays int `json:"trustDeviceDays"`
 }
 
 type loginResponse struct {
@@ -46,28 +55,69 @@ func (s *router) Login(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
+	if err := s.validateTrustDeviceDays(in.TrustDeviceDays); err != nil {
+		response.BadRequest(w, err)
+		return
+	}
+
+	addr := request.RemoteAddr(r)
+
+	if err := s.loginThrottle.Check(r.Context(), in.Username, addr); err != nil {
+		s.recordLoginFailureAudit(r, in.Username, err)
+		writeLoginThrottledError(w, err)
+		return
+	}
+
+	trustedDevice, err := s.verifyTrustedDevice(w, r)
+	if err != nil {
+		response.InternalError(w, err)
+		return
+	}
+
+	ctx := r.Context()
+	if trustedDevice != nil {
+		ctx = core.WithTwoFactorVerified(ctx, trustedDevice.UserID)
+	}
+
+	if in.WebAuthn != nil {
+		if !s.cfg.Auth.WebAuthn.Enabled {
+			response.BadRequestf(w, "webauthn is not enabled")
+			return
+		}
+
+		user, ok := s.verifyWebAuthnAssertion(w, r, in.WebAuthn)
+		if !ok {
+			return
+		}
+
+		ctx = core.WithTwoFactorVerified(ctx, user.ID)
+	}
+
 	user, err := s.passwordAuthenticator.Login(
-		r.Context(),
+		ctx,
 		in.Username,
 		in.Password,
 		in.TOTP,
 	)
 
-	addr := request.RemoteAddr(r)
-
 	if err != nil {
-		if err == core.ErrInvalidUsernameOrPassword {
+		if err == core.ErrInvalidUsernameOrPassword ||
+			err == core.ErrTwoFactorCodeInvalid ||
+			err == core.ErrUserDisabled {
 			// TODO: This won't work for json logging
 			log.Error().
 				Str("ip", addr).
 				Str("username", in.Username).
-				Msg("login failed: invalid username or password")
+				Msgf("login failed: %s", err)
+
+			s.recordLoginFailure(r, in.Username, addr)
+			s.recordLoginFailureAudit(r, in.Username, err)
 
 			response.Unauthorized(w, err)
 			return
 		}
 
-		if err == core.ErrTwoFactorRequired || err == core.ErrTwoFactorCodeInvalid {
+		if err == core.ErrTwoFactorRequired {
 			response.Unauthorized(w, err)
 			return
 		}
@@ -76,6 +126,10 @@ func (s *router) Login(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
+	if err := s.loginThrottle.RecordSuccess(r.Context(), in.Username); err != nil {
+		log.Error().Err(err).Msg("failed to reset login throttle")
+	}
+
 	token, err := s.sessionManager.Create(
 		r.Context(),
 		user.ID,
@@ -88,6 +142,15 @@ func (s *router) Login(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
+	s.recordLoginAudit(r, user.ID)
+
+	if in.TrustDeviceDays > 0 && (trustedDevice == nil || trustedDevice.UserID != user.ID) {
+		if err := s.trustDevice(w, r, user.ID, in.TrustDeviceDays); err != nil {
+			log.Error().Int64("user", user.ID).
+				Err(err).Msg("failed to trust device")
+		}
+	}
+
 	setSessionCookie(
 		w,
 		s.cfg.Auth.Session.CookieName,
@@ -96,3 +159,41 @@ func (s *router) Login(w http.ResponseWriter, r *http.Request) {
 	)
 	response.JSON(w, loginResponse{Token: token}, 200)
 }
+
+// recordLoginFailure records a failed login attempt for the brute-force protection.
+func (s *router) recordLoginFailure(r *http.Request, username string, addr string) {
+	if err := s.loginThrottle.RecordFailure(r.Context(), username, addr); err != nil {
+		log.Error().Err(err).Msg("failed to record failed login")
+	}
+}
+
+// recordLoginAudit records a successful login in the audit log.
+func (s *router) recordLoginAudit(r *http.Request, userID int64) {
+	s.recordAuditEntry(r, &core.AuditEntry{
+		UserID: &userID,
+		Action: core.AuditActionLogin,
+	})
+}
+
+// recordLoginFailureAudit records a failed login attempt in the audit log.
+func (s *router) recordLoginFailureAudit(r *http.Request, username string, reason error) {
+	s.recordAuditEntry(r, &core.AuditEntry{
+		Username: username,
+		Action:   core.AuditActionLoginFailed,
+		Reason:   reason.Error(),
+	})
+}
+
+// writeLoginThrottledError writes the response for a rejected login
+// including the Retry-After header.
+func writeLoginThrottledError(w http.ResponseWriter, err error) {
+	var throttledErr core.LoginThrottledError
+	if !errors.As(err, &throttledErr) {
+		response.InternalError(w, err)
+		return
+	}
+
+	seconds := int(math.Ceil(throttledErr.RetryAfter.Seconds()))
+	w.Header().Set("Retry-After", strconv.Itoa(seconds))
+	response.TooManyRequests(w, err, "login_throttled")
+}
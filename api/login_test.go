@@ -0,0 +1,331 @@
+package api_test
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"time"
+
+	"github.com/davidborzek/tvhgo/api"
+	"github.com/davidborzek/tvhgo/config"
+	"github.com/davidborzek/tvhgo/core"
+	mock_core "github.com/davidborzek/tvhgo/mock/core"
+	"go.uber.org/mock/gomock"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("Login throttling", func() {
+	var mockCtrl *gomock.Controller
+	var mockSessionManager *mock_core.MockSessionManager
+	var mockPasswordAuthenticator *mock_core.MockPasswordAuthenticator
+	var mockLoginThrottle *mock_core.MockLoginThrottleService
+	var mockAudit *mock_core.MockAuditService
+
+	cfg := &config.Config{
+		Auth: config.AuthConfig{
+			Session: config.SessionConfig{
+				CookieName: "tvhgo_session",
+			},
+		},
+	}
+
+	login := func(body string) *httptest.ResponseRecorder {
+		req, err := http.NewRequest("POST", "/login", strings.NewReader(body))
+		if err != nil {
+			Fail(err.Error())
+		}
+		req.RemoteAddr = "192.168.1.1:1234"
+
+		sut := api.New(cfg, nil, nil, nil, nil, nil, mockSessionManager, nil, nil, mockPasswordAuthenticator,
+			nil, nil, nil, nil, nil, nil, nil, nil, nil, nil, nil, nil, mockLoginThrottle, mockAudit, nil, nil, nil, nil)
+
+		rr := httptest.NewRecorder()
+		sut.Handler().ServeHTTP(rr, req)
+		return rr
+	}
+
+	BeforeEach(func() {
+		mockCtrl = gomock.NewController(GinkgoT())
+		mockSessionManager = mock_core.NewMockSessionManager(mockCtrl)
+		mockPasswordAuthenticator = mock_core.NewMockPasswordAuthenticator(mockCtrl)
+		mockLoginThrottle = mock_core.NewMockLoginThrottleService(mockCtrl)
+		mockAudit = mock_core.NewMockAuditService(mockCtrl)
+	})
+
+	AfterEach(func() {
+		mockCtrl.Finish()
+	})
+
+	It("rejects a throttled login with retry after", func() {
+		mockLoginThrottle.EXPECT().
+			Check(gomock.Any(), "someUser", "192.168.1.1").
+			Return(core.LoginThrottledError{RetryAfter: 90 * time.Second})
+
+		mockAudit.EXPECT().
+			Record(gomock.Any(), &core.AuditEntry{
+				Username: "someUser",
+				Action:   core.AuditActionLoginFailed,
+				Reason:   "too many failed login attempts",
+				ClientIP: "192.168.1.1",
+			})
+
+		rr := login(`{"username":"someUser","password":"somePassword"}`)
+
+		Expect(rr.Code).To(Equal(http.StatusTooManyRequests))
+		Expect(rr.Header().Get("Retry-After")).To(Equal("90"))
+		Expect(rr.Body.String()).To(MatchJSON(`{"message":"too many failed login attempts","code":"login_throttled"}`))
+	})
+
+	DescribeTable("records failed logins",
+		func(loginErr error) {
+			mockLoginThrottle.EXPECT().
+				Check(gomock.Any(), "someUser", "192.168.1.1").
+				Return(nil)
+
+			mockPasswordAuthenticator.EXPECT().
+				Login(gomock.Any(), "someUser", "somePassword", nil).
+				Return(nil, loginErr)
+
+			mockLoginThrottle.EXPECT().
+				RecordFailure(gomock.Any(), "someUser", "192.168.1.1").
+				Return(nil)
+
+			mockAudit.EXPECT().
+				Record(gomock.Any(), &core.AuditEntry{
+					Username: "someUser",
+					Action:   core.AuditActionLoginFailed,
+					Reason:   loginErr.Error(),
+					ClientIP: "192.168.1.1",
+				})
+
+			rr := login(`{"username":"someUser","password":"somePassword"}`)
+
+			Expect(rr.Code).To(Equal(http.StatusUnauthorized))
+		},
+		Entry("invalid password", core.ErrInvalidUsernameOrPassword),
+		Entry("invalid totp code", core.ErrTwoFactorCodeInvalid),
+	)
+
+	It("does not record a missing totp code as failure", func() {
+		mockLoginThrottle.EXPECT().
+			Check(gomock.Any(), "someUser", "192.168.1.1").
+			Return(nil)
+
+		mockPasswordAuthenticator.EXPECT().
+			Login(gomock.Any(), "someUser", "somePassword", nil).
+			Return(nil, core.ErrTwoFactorRequired)
+
+		rr := login(`{"username":"someUser","password":"somePassword"}`)
+
+		Expect(rr.Code).To(Equal(http.StatusUnauthorized))
+	})
+
+	It("resets the failed logins after a successful login", func() {
+		mockLoginThrottle.EXPECT().
+			Check(gomock.Any(), "someUser", "192.168.1.1").
+			Return(nil)
+
+		mockPasswordAuthenticator.EXPECT().
+			Login(gomock.Any(), "someUser", "somePassword", nil).
+			Return(&core.User{ID: 1}, nil)
+
+		mockLoginThrottle.EXPECT().
+			RecordSuccess(gomock.Any(), "someUser").
+			Return(nil)
+
+		mockSessionManager.EXPECT().
+			Create(gomock.Any(), int64(1), "192.168.1.1", gomock.Any()).
+			Return("someToken", nil)
+
+		userID := int64(1)
+		mockAudit.EXPECT().
+			Record(gomock.Any(), &core.AuditEntry{
+				UserID:   &userID,
+				Action:   core.AuditActionLogin,
+				ClientIP: "192.168.1.1",
+			})
+
+		rr := login(`{"username":"someUser","password":"somePassword"}`)
+
+		Expect(rr.Code).To(Equal(http.StatusOK))
+	})
+})
+
+var _ = Describe("Trusted devices", func() {
+	var mockCtrl *gomock.Controller
+	var mockSessionManager *mock_core.MockSessionManager
+	var mockPasswordAuthenticator *mock_core.MockPasswordAuthenticator
+	var mockTwoFactorService *mock_core.MockTwoFactorAuthService
+	var mockLoginThrottle *mock_core.MockLoginThrottleService
+	var mockAudit *mock_core.MockAuditService
+	var mockTrustedDevices *mock_core.MockTrustedDeviceService
+
+	cfg := &config.Config{
+		Auth: config.AuthConfig{
+			Session: config.SessionConfig{
+				CookieName: "tvhgo_session",
+			},
+			TrustedDevice: config.TrustedDeviceConfig{
+				Enabled:     true,
+				CookieName:  "tvhgo_trusted_device",
+				MaxLifetime: 30 * 24 * time.Hour,
+			},
+		},
+	}
+
+	login := func(body string, cookies ...*http.Cookie) *httptest.ResponseRecorder {
+		req, err := http.NewRequest("POST", "/login", strings.NewReader(body))
+		if err != nil {
+			Fail(err.Error())
+		}
+		req.RemoteAddr = "192.168.1.1:1234"
+		for _, c := range cookies {
+			req.AddCookie(c)
+		}
+
+		sut := api.New(cfg, nil, nil, nil, nil, nil, mockSessionManager, nil, nil, mockPasswordAuthenticator,
+			nil, nil, mockTwoFactorService, nil, nil, nil, nil, nil, nil, nil, nil, nil, mockLoginThrottle, mockAudit, nil, nil, mockTrustedDevices, nil)
+
+		rr := httptest.NewRecorder()
+		sut.Handler().ServeHTTP(rr, req)
+		return rr
+	}
+
+	findCookie := func(rr *httptest.ResponseRecorder, name string) *http.Cookie {
+		for _, c := range rr.Result().Cookies() {
+			if c.Name == name {
+				return c
+			}
+		}
+		return nil
+	}
+
+	expectSuccessfulLogin := func() {
+		mockLoginThrottle.EXPECT().
+			RecordSuccess(gomock.Any(), "someUser").
+			Return(nil)
+
+		mockSessionManager.EXPECT().
+			Create(gomock.Any(), int64(1), "192.168.1.1", gomock.Any()).
+			Return("someToken", nil)
+	}
+
+	BeforeEach(func() {
+		mockCtrl = gomock.NewController(GinkgoT())
+		mockSessionManager = mock_core.NewMockSessionManager(mockCtrl)
+		mockPasswordAuthenticator = mock_core.NewMockPasswordAuthenticator(mockCtrl)
+		mockTwoFactorService = mock_core.NewMockTwoFactorAuthService(mockCtrl)
+		mockLoginThrottle = mock_core.NewMockLoginThrottleService(mockCtrl)
+		mockAudit = mock_core.NewMockAuditService(mockCtrl)
+		mockTrustedDevices = mock_core.NewMockTrustedDeviceService(mockCtrl)
+
+		mockLoginThrottle.EXPECT().
+			Check(gomock.Any(), "someUser", "192.168.1.1").
+			Return(nil).
+			AnyTimes()
+
+		mockAudit.EXPECT().
+			Record(gomock.Any(), gomock.Any()).
+			AnyTimes()
+	})
+
+	AfterEach(func() {
+		mockCtrl.Finish()
+	})
+
+	It("skips the second factor on a trusted device", func() {
+		mockTrustedDevices.EXPECT().
+			Verify(gomock.Any(), "someDeviceToken").
+			Return(&core.TrustedDevice{ID: 2, UserID: 1}, nil)
+
+		mockPasswordAuthenticator.EXPECT().
+			Login(gomock.Cond(func(ctx any) bool {
+				return core.IsTwoFactorVerified(ctx.(context.Context), 1)
+			}), "someUser", "somePassword", nil).
+			Return(&core.User{ID: 1}, nil)
+
+		expectSuccessfulLogin()
+
+		rr := login(`{"username":"someUser","password":"somePassword"}`,
+			&http.Cookie{Name: "tvhgo_trusted_device", Value: "someDeviceToken"})
+
+		Expect(rr.Code).To(Equal(http.StatusOK))
+		Expect(findCookie(rr, "tvhgo_trusted_device")).To(BeNil())
+	})
+
+	It("removes an invalid device cookie", func() {
+		mockTrustedDevices.EXPECT().
+			Verify(gomock.Any(), "someDeviceToken").
+			Return(nil, nil)
+
+		mockPasswordAuthenticator.EXPECT().
+			Login(gomock.Cond(func(ctx any) bool {
+				return !core.IsTwoFactorVerified(ctx.(context.Context), 1)
+			}), "someUser", "somePassword", nil).
+			Return(nil, core.ErrTwoFactorRequired)
+
+		rr := login(`{"username":"someUser","password":"somePassword"}`,
+			&http.Cookie{Name: "tvhgo_trusted_device", Value: "someDeviceToken"})
+
+		Expect(rr.Code).To(Equal(http.StatusUnauthorized))
+
+		cookie := findCookie(rr, "tvhgo_trusted_device")
+		Expect(cookie).NotTo(BeNil())
+		Expect(cookie.MaxAge).To(BeNumerically("<", 0))
+	})
+
+	It("trusts the device after a login with the second factor", func() {
+		totp := "123456"
+
+		mockPasswordAuthenticator.EXPECT().
+			Login(gomock.Any(), "someUser", "somePassword", &totp).
+			Return(&core.User{ID: 1}, nil)
+
+		expectSuccessfulLogin()
+
+		mockTwoFactorService.EXPECT().
+			GetSettings(gomock.Any(), int64(1)).
+			Return(&core.TwoFactorSettings{Enabled: true}, nil)
+
+		mockTrustedDevices.EXPECT().
+			Trust(gomock.Any(), int64(1), 7, "192.168.1.1", gomock.Any()).
+			Return("someDeviceToken", &core.TrustedDevice{ID: 2, UserID: 1}, nil)
+
+		rr := login(`{"username":"someUser","password":"somePassword","totp":"123456","trustDeviceDays":7}`)
+
+		Expect(rr.Code).To(Equal(http.StatusOK))
+
+		cookie := findCookie(rr, "tvhgo_trusted_device")
+		Expect(cookie).NotTo(BeNil())
+		Expect(cookie.Value).To(Equal("someDeviceToken"))
+		Expect(cookie.Path).To(Equal("/api/login"))
+		Expect(cookie.HttpOnly).To(BeTrue())
+		Expect(cookie.MaxAge).To(Equal(7 * 24 * 60 * 60))
+	})
+
+	It("does not trust the device without two factor auth", func() {
+		mockPasswordAuthenticator.EXPECT().
+			Login(gomock.Any(), "someUser", "somePassword", nil).
+			Return(&core.User{ID: 1}, nil)
+
+		expectSuccessfulLogin()
+
+		mockTwoFactorService.EXPECT().
+			GetSettings(gomock.Any(), int64(1)).
+			Return(nil, nil)
+
+		rr := login(`{"username":"someUser","password":"somePassword","trustDeviceDays":7}`)
+
+		Expect(rr.Code).To(Equal(http.StatusOK))
+		Expect(findCookie(rr, "tvhgo_trusted_device")).To(BeNil())
+	})
+
+	It("rejects a lifetime above the maximum lifetime", func() {
+		rr := login(`{"username":"someUser","password":"somePassword","trustDeviceDays":31}`)
+
+		Expect(rr.Code).To(Equal(http.StatusBadRequest))
+	})
+})
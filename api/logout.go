@@ -3,9 +3,11 @@ package api
 import (
 	"fmt"
 	"net/http"
+	"strconv"
 
 	"github.com/davidborzek/tvhgo/api/request"
 	"github.com/davidborzek/tvhgo/api/response"
+	"github.com/davidborzek/tvhgo/core"
 )
 
 // Internal implementation to delete the session cookie.
@@ -42,6 +44,8 @@ func (s *router) Logout(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
+	s.recordAudit(r, core.AuditActionLogout, strconv.FormatInt(*ctx.SessionID, 10))
+
 	deleteSessionCookie(w, s.cfg.Auth.Session.CookieName)
 	w.WriteHeader(200)
 }
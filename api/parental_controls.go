@@ -0,0 +1,367 @@
+package api
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"strconv"
+
+	"github.com/davidborzek/tvhgo/api/request"
+	"github.com/davidborzek/tvhgo/api/response"
+	"github.com/davidborzek/tvhgo/core"
+	"github.com/rs/zerolog/log"
+)
+
+// GetParentalControls godoc
+//
+//	@Summary	Get the parental controls of the current user
+//	@Tags		parental-controls
+//	@Produce	json
+//	@Success	200	{object}	core.ParentalControls
+//	@Failure	401	{object}	response.ErrorResponse
+//	@Failure	404	{object}	response.ErrorResponse
+//	@Failure	500	{object}	response.ErrorResponse
+//	@Security	JWT
+//	@Router		/user/parental-controls [get]
+func (s *router) GetParentalControls(w http.ResponseWriter, r *http.Request) {
+	ctx, ok := request.GetAuthContext(r.Context())
+	if !ok {
+		response.InternalErrorCommon(w)
+		return
+	}
+
+	s.writeParentalControls(w, r, ctx.UserID)
+}
+
+// GetUserParentalControls godoc
+//
+//	@Summary	Get the parental controls of a user
+//	@Tags		parental-controls
+//	@Param		id	path	int	true	"User id"
+//	@Produce	json
+//	@Success	200	{object}	core.ParentalControls
+//	@Failure	400	{object}	response.ErrorResponse
+//	@Failure	401	{object}	response.ErrorResponse
+//	@Failure	403	{object}	response.ErrorResponse
+//	@Failure	404	{object}	response.ErrorResponse
+//	@Failure	500	{object}	response.ErrorResponse
+//	@Security	JWT
+//	@Router		/users/{id}/parental-controls [get]
+func (s *router) GetUserParentalControls(w http.ResponseWriter, r *http.Request) {
+	userID, err := request.NumericURLParam(r, "id")
+	if err != nil {
+		response.BadRequestf(w, "invalid value for parameter 'id'")
+		return
+	}
+
+	s.writeParentalControls(w, r, userID)
+}
+
+// UpdateUserParentalControls godoc
+//
+//	@Summary	Sets the parental controls of a user
+//	@Tags		parental-controls
+//	@Accept		json
+//	@Param		id		path	int						true	"User id"
+//	@Param		body	body	core.ParentalControls	true	"Body"
+//	@Produce	json
+//	@Success	200	{object}	core.ParentalControls
+//	@Failure	400	{object}	response.ErrorResponse
+//	@Failure	401	{object}	response.ErrorResponse
+//	@Failure	403	{object}	response.ErrorResponse
+//	@Failure	404	{object}	response.ErrorResponse
+//	@Failure	500	{object}	response.ErrorResponse
+//	@Security	JWT
+//	@Router		/users/{id}/parental-controls [put]
+func (s *router) UpdateUserParentalControls(w http.ResponseWriter, r *http.Request) {
+	userID, err := request.NumericURLParam(r, "id")
+	if err != nil {
+		response.BadRequestf(w, "invalid value for parameter 'id'")
+		return
+	}
+
+	var in core.ParentalControls
+	if err := request.BindJSON(r, &in); err != nil {
+		response.BadRequest(w, err)
+		return
+	}
+
+	user, err := s.users.FindById(r.Context(), userID)
+	if err != nil {
+		response.InternalError(w, err)
+		return
+	}
+
+	if user == nil {
+		response.NotFound(w, fmt.Errorf("user not found"))
+		return
+	}
+
+	in.UserID = user.ID
+	if err := s.parentalControls.Save(r.Context(), &in); err != nil {
+		if err == core.ErrParentalControlsInvalidViewingTime ||
+			err == core.ErrParentalControlsInvalidContentType {
+			response.BadRequest(w, err)
+			return
+		}
+
+		response.InternalErrorCommon(w)
+		return
+	}
+
+	s.recordAudit(r, core.AuditActionParentalControlsUpdate, strconv.FormatInt(userID, 10))
+
+	response.JSON(w, in, 200)
+}
+
+// DeleteUserParentalControls godoc
+//
+//	@Summary	Removes the parental controls of a user
+//	@Tags		parental-controls
+//	@Param		id	path	int	true	"User id"
+//	@Success	204
+//	@Failure	400	{object}	response.ErrorResponse
+//	@Failure	401	{object}	response.ErrorResponse
+//	@Failure	403	{object}	response.ErrorResponse
+//	@Failure	500	{object}	response.ErrorResponse
+//	@Security	JWT
+//	@Router		/users/{id}/parental-controls [delete]
+func (s *router) DeleteUserParentalControls(w http.ResponseWriter, r *http.Request) {
+	userID, err := request.NumericURLParam(r, "id")
+	if err != nil {
+		response.BadRequestf(w, "invalid value for parameter 'id'")
+		return
+	}
+
+	if err := s.parentalControls.Delete(r.Context(), userID); err != nil {
+		response.InternalErrorCommon(w)
+		return
+	}
+
+	s.recordAudit(r, core.AuditActionParentalControlsDelete, strconv.FormatInt(userID, 10))
+
+	w.WriteHeader(http.StatusNoContent)
+}
+
+func (s *router) writeParentalControls(w http.ResponseWriter, r *http.Request, userID int64) {
+	controls, err := s.parentalControls.Get(r.Context(), userID)
+	if err != nil {
+		response.InternalErrorCommon(w)
+		return
+	}
+
+	if controls == nil {
+		response.NotFound(w, core.ErrParentalControlsNotFound)
+		return
+	}
+
+	response.JSON(w, controls, 200)
+}
+
+// contentFilter resolves the parental controls of the current user and
+// writes an error response if this fails. The filter is nil for
+// unrestricted users.
+func (s *router) contentFilter(w http.ResponseWriter, r *http.Request) (*core.ContentFilter, bool) {
+	ctx, ok := request.GetAuthContext(r.Context())
+	if !ok {
+		response.InternalErrorCommon(w)
+		return nil, false
+	}
+
+	filter, err := s.parentalControls.Filter(r.Context(), ctx.UserID)
+	if err != nil {
+		response.InternalErrorCommon(w)
+		return nil, false
+	}
+
+	return filter, true
+}
+
+// authorizeChannelStream checks the parental controls of the current user
+// for a stream of a channel and the currently running epg event.
+func (s *router) authorizeChannelStream(w http.ResponseWriter, r *http.Request, number int64) bool {
+	if err := s.checkChannelStream(r, number); err != nil {
+		if errors.Is(err, core.ErrContentRestricted) {
+			writeContentRestricted(w)
+			return false
+		}
+
+		writeTvheadendError(w, err)
+		return false
+	}
+
+	return true
+}
+
+// checkChannelStream returns ErrContentRestricted, if the parental controls
+// of the current user forbid a stream of a channel and the currently running epg event.
+func (s *router) checkChannelStream(r *http.Request, number int64) error {
+	ctx, ok := request.GetAuthContext(r.Context())
+	if !ok {
+		return core.ErrUnexpectedError
+	}
+
+	filter, err := s.parentalControls.Filter(r.Context(), ctx.UserID)
+	if err != nil {
+		return err
+	}
+
+	if filter == nil {
+		return nil
+	}
+
+	id, allowed := filter.ChannelID(number)
+	if !allowed || !filter.AllowsStreaming() {
+		return core.ErrContentRestricted
+	}
+
+	if !filter.BlocksContentTypes() {
+		return nil
+	}
+
+	q := core.GetEpgEventsQueryParams{
+		NowPlaying: true,
+		Channel:    id,
+	}
+	q.Limit = 1
+
+	events, err := s.epg.GetEvents(r.Context(), q)
+	if err != nil {
+		log.Error().Int64("channel", number).
+			Err(err).Msg("failed to get running epg event")
+
+		return err
+	}
+
+	for _, event := range events.Entries {
+		if !filter.AllowsEvent(event) {
+			return core.ErrContentRestricted
+		}
+	}
+
+	return nil
+}
+
+// authorizeEvent checks the parental controls of the current user for an epg event.
+func (s *router) authorizeEvent(w http.ResponseWriter, r *http.Request, id int64) bool {
+	filter, ok := s.contentFilter(w, r)
+	if !ok {
+		return false
+	}
+
+	if filter == nil {
+		return true
+	}
+
+	event, err := s.epg.GetEvent(r.Context(), id)
+	if err != nil {
+		if err == core.ErrEpgEventNotFound {
+			response.NotFound(w, err)
+			return false
+		}
+
+		log.Error().Int64("id", id).
+			Err(err).Msg("failed to get epg event")
+
+		writeTvheadendError(w, err)
+		return false
+	}
+
+	if !filter.AllowsEvent(event) {
+		writeContentRestricted(w)
+		return false
+	}
+
+	return true
+}
+
+// authorizeRecordingStream checks the parental controls of the
+// current user for a stream of a recording.
+func (s *router) authorizeRecordingStream(w http.ResponseWriter, r *http.Request, id string) bool {
+	filter, ok := s.contentFilter(w, r)
+	if !ok {
+		return false
+	}
+
+	if filter == nil {
+		return true
+	}
+
+	if !filter.AllowsStreaming() {
+		writeContentRestricted(w)
+		return false
+	}
+
+	recording, err := s.recordings.Get(r.Context(), id)
+	if err != nil {
+		if err == core.ErrRecordingNotFound {
+			response.NotFound(w, err)
+			return false
+		}
+
+		log.Error().Str("id", id).
+			Err(err).Msg("failed to get recording")
+
+		writeTvheadendError(w, err)
+		return false
+	}
+
+	if !filter.AllowsRecording(recording) {
+		writeContentRestricted(w)
+		return false
+	}
+
+	return true
+}
+
+// filteredListLimit is the maximum number of entries loaded for
+// a list of a restricted user, which are filtered and then paginated.
+const filteredListLimit = 10000
+
+// writeContentRestricted writes the error response for
+// content restricted by parental controls.
+func writeContentRestricted(w http.ResponseWriter) {
+	response.ErrorWithCode(w, core.ErrContentRestricted, "content_restricted", http.StatusForbidden)
+}
+
+// filterEntries returns the entries allowed by a filter function.
+func filterEntries[T any](entries []T, allows func(T) bool) []T {
+	filtered := make([]T, 0, len(entries))
+	for _, entry := range entries {
+		if allows(entry) {
+			filtered = append(filtered, entry)
+		}
+	}
+	return filtered
+}
+
+// unpaginate replaces the pagination of a list query of a restricted user,
+// so that all entries are loaded and filtered before they are paginated.
+// It returns the requested pagination.
+func unpaginate(filter *core.ContentFilter, params *core.PaginationQueryParams) core.PaginationQueryParams {
+	page := *params
+	if filter != nil {
+		params.Limit = filteredListLimit
+		params.Offset = 0
+	}
+
+	return page
+}
+
+// filterListResult removes entries not allowed by a filter function from
+// an unpaginated list result and applies the requested pagination, so that
+// the total only counts the allowed entries.
+func filterListResult[T any](result *core.ListResult[T], allows func(T) bool, page core.PaginationQueryParams) {
+	filtered := filterEntries(result.Entries, allows)
+	total := int64(len(filtered))
+
+	start := min(page.Offset, total)
+	end := total
+	if page.Limit > 0 {
+		end = min(start+page.Limit, total)
+	}
+
+	result.Entries = filtered[start:end]
+	result.Total = total
+	result.Offset = page.Offset
+}
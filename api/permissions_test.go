@@ -0,0 +1,541 @@
+package api_test
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/davidborzek/tvhgo/api"
+	"github.com/davidborzek/tvhgo/config"
+	"github.com/davidborzek/tvhgo/core"
+	mock_core "github.com/davidborzek/tvhgo/mock/core"
+	"github.com/davidborzek/tvhgo/services/clock"
+	"github.com/davidborzek/tvhgo/services/streaming"
+	"go.uber.org/mock/gomock"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+)
+
+type fakeRecordingService struct {
+	core.RecordingService
+	stopped []string
+}
+
+func (f *fakeRecordingService) Stop(_ context.Context, id string) error {
+	f.stopped = append(f.stopped, id)
+	return nil
+}
+
+var _ = Describe("Permissions", func() {
+	var mockCtrl *gomock.Controller
+	var mockSessionManager *mock_core.MockSessionManager
+	var mockTokenService *mock_core.MockTokenService
+	var mockTwoFactorService *mock_core.MockTwoFactorAuthService
+	var mockUserRepository *mock_core.MockUserRepository
+	var mockRecordingOwners *mock_core.MockRecordingOwnerRepository
+	var mockAudit *mock_core.MockAuditService
+	var mockParentalControls *mock_core.MockParentalControlsService
+	var mockTrustedDevices *mock_core.MockTrustedDeviceService
+	var mockPasswordHasher *mock_core.MockPasswordHasher
+	var recordings *fakeRecordingService
+	var streamSigner core.StreamSigner
+	var role core.Role
+	var disabled bool
+	var scopes []core.Permission
+
+	newRequest := func(method string, path string, body string) *http.Request {
+		req, err := http.NewRequest(method, path, strings.NewReader(body))
+		if err != nil {
+			Fail(err.Error())
+		}
+		req.Header.Set("Authorization", "Bearer token")
+		return req
+	}
+
+	serve := func(req *http.Request) *httptest.ResponseRecorder {
+		sut := api.New(&config.Config{}, nil, nil, nil, recordings, nil, mockSessionManager, nil, mockUserRepository, nil,
+			nil, mockTokenService, mockTwoFactorService, nil, nil, nil, nil, streamSigner, nil, nil, nil, mockRecordingOwners, nil, mockAudit, nil, mockParentalControls, mockTrustedDevices, mockPasswordHasher)
+
+		rr := httptest.NewRecorder()
+		sut.Handler().ServeHTTP(rr, req)
+		return rr
+	}
+
+	ownerID := func(id int64) *int64 {
+		return &id
+	}
+
+	BeforeEach(func() {
+		mockCtrl = gomock.NewController(GinkgoT())
+		mockSessionManager = mock_core.NewMockSessionManager(mockCtrl)
+		mockTokenService = mock_core.NewMockTokenService(mockCtrl)
+		mockTwoFactorService = mock_core.NewMockTwoFactorAuthService(mockCtrl)
+		mockUserRepository = mock_core.NewMockUserRepository(mockCtrl)
+		mockRecordingOwners = mock_core.NewMockRecordingOwnerRepository(mockCtrl)
+		mockAudit = mock_core.NewMockAuditService(mockCtrl)
+		mockParentalControls = mock_core.NewMockParentalControlsService(mockCtrl)
+		mockParentalControls.EXPECT().
+			Filter(gomock.Any(), gomock.Any()).
+			Return(nil, nil).
+			AnyTimes()
+		mockTrustedDevices = mock_core.NewMockTrustedDeviceService(mockCtrl)
+		mockPasswordHasher = mock_core.NewMockPasswordHasher(mockCtrl)
+		mockAudit.EXPECT().
+			Record(gomock.Any(), gomock.Any()).
+			AnyTimes()
+		recordings = &fakeRecordingService{}
+		streamSigner = streaming.NewSigner(&config.StreamSigningConfig{
+			Keys: []string{"someKey"},
+			TTL:  time.Hour,
+		}, clock.NewClock())
+		scopes = nil
+		disabled = false
+
+		mockTokenService.EXPECT().
+			Validate(gomock.Any(), gomock.Any(), gomock.Any()).
+			DoAndReturn(func(context.Context, string, string) (*core.AuthContext, error) {
+				return &core.AuthContext{UserID: 1, Scopes: scopes}, nil
+			}).
+			AnyTimes()
+
+		mockUserRepository.EXPECT().
+			FindById(gomock.Any(), int64(1)).
+			DoAndReturn(func(context.Context, int64) (*core.User, error) {
+				return &core.User{ID: 1, Role: role, Disabled: disabled}, nil
+			}).
+			AnyTimes()
+	})
+
+	AfterEach(func() {
+		mockCtrl.Finish()
+	})
+
+	It("forbids a viewer to schedule recordings", func() {
+		role = core.RoleViewer
+
+		rr := serve(newRequest("POST", "/recordings", "{}"))
+
+		Expect(rr.Code).To(Equal(http.StatusForbidden))
+		Expect(rr.Body.String()).To(MatchJSON(`{"message":"permission denied"}`))
+	})
+
+	It("allows a recorder to stop an own recording", func() {
+		role = core.RoleRecorder
+
+		mockRecordingOwners.EXPECT().
+			FindUserID(gomock.Any(), "someID").
+			Return(ownerID(1), nil)
+
+		rr := serve(newRequest("PUT", "/recordings/someID/stop", ""))
+
+		Expect(rr.Code).To(Equal(http.StatusNoContent))
+		Expect(recordings.stopped).To(Equal([]string{"someID"}))
+	})
+
+	DescribeTable("forbids a recorder to stop recordings of other users",
+		func(owner *int64) {
+			role = core.RoleRecorder
+
+			mockRecordingOwners.EXPECT().
+				FindUserID(gomock.Any(), "someID").
+				Return(owner, nil)
+
+			rr := serve(newRequest("PUT", "/recordings/someID/stop", ""))
+
+			Expect(rr.Code).To(Equal(http.StatusForbidden))
+			Expect(recordings.stopped).To(BeEmpty())
+		},
+		Entry("with other owner", ownerID(2)),
+		Entry("without owner", nil),
+	)
+
+	It("allows a dvr-admin to stop any recording", func() {
+		role = core.RoleDVRAdmin
+
+		rr := serve(newRequest("PUT", "/recordings/someID/stop", ""))
+
+		Expect(rr.Code).To(Equal(http.StatusNoContent))
+		Expect(recordings.stopped).To(Equal([]string{"someID"}))
+	})
+
+	It("forbids a dvr-admin to manage users", func() {
+		role = core.RoleDVRAdmin
+
+		rr := serve(newRequest("GET", "/users", ""))
+
+		Expect(rr.Code).To(Equal(http.StatusForbidden))
+	})
+
+	It("forbids a dvr-admin to read the audit log", func() {
+		role = core.RoleDVRAdmin
+
+		rr := serve(newRequest("GET", "/audit", ""))
+
+		Expect(rr.Code).To(Equal(http.StatusForbidden))
+	})
+
+	It("allows an admin to read the audit log", func() {
+		role = core.RoleAdmin
+
+		userID := int64(2)
+		mockAudit.EXPECT().
+			Find(gomock.Any(), core.AuditQueryParams{
+				PaginationQueryParams: core.PaginationQueryParams{Limit: 10},
+				UserID:                &userID,
+				Action:                core.AuditActionLogin,
+			}).
+			Return(&core.AuditListResult{
+				Entries: []*core.AuditEntry{{ID: 1, Action: core.AuditActionLogin}},
+				Total:   1,
+			}, nil)
+
+		rr := serve(newRequest("GET", "/audit?user_id=2&action=login&limit=10", ""))
+
+		Expect(rr.Code).To(Equal(http.StatusOK))
+		Expect(rr.Body.String()).To(ContainSubstring(`"total":1`))
+	})
+
+	It("rejects an unknown audit action", func() {
+		role = core.RoleAdmin
+
+		rr := serve(newRequest("GET", "/audit?action=unknown", ""))
+
+		Expect(rr.Code).To(Equal(http.StatusBadRequest))
+		Expect(rr.Body.String()).To(MatchJSON(`{"message":"invalid audit action"}`))
+	})
+
+	It("allows an admin to assign a role", func() {
+		role = core.RoleAdmin
+
+		mockUserRepository.EXPECT().
+			FindById(gomock.Any(), int64(2)).
+			Return(&core.User{ID: 2, Role: core.RoleViewer}, nil)
+
+		mockUserRepository.EXPECT().
+			Update(gomock.Any(), &core.User{ID: 2, Role: core.RoleRecorder}).
+			Return(nil)
+
+		rr := serve(newRequest("PUT", "/users/2/role", `{"role":"recorder"}`))
+
+		Expect(rr.Code).To(Equal(http.StatusOK))
+		Expect(rr.Body.String()).To(ContainSubstring(`"role":"recorder"`))
+		Expect(rr.Body.String()).To(ContainSubstring(`"isAdmin":false`))
+	})
+
+	DescribeTable("rejects invalid role assignments",
+		func(path string, body string, message string) {
+			role = core.RoleAdmin
+
+			rr := serve(newRequest("PUT", path, body))
+
+			Expect(rr.Code).To(Equal(http.StatusBadRequest))
+			Expect(rr.Body.String()).To(MatchJSON(`{"message":"` + message + `"}`))
+		},
+		Entry("unknown role", "/users/2/role", `{"role":"superuser"}`, "invalid role"),
+		Entry("own role", "/users/1/role", `{"role":"viewer"}`, "role of current user cannot be changed"),
+	)
+
+	It("forbids a disabled admin to manage users", func() {
+		role = core.RoleAdmin
+		disabled = true
+
+		rr := serve(newRequest("GET", "/users", ""))
+
+		Expect(rr.Code).To(Equal(http.StatusForbidden))
+	})
+
+	It("allows an admin to update a user", func() {
+		role = core.RoleAdmin
+
+		mockUserRepository.EXPECT().
+			FindById(gomock.Any(), int64(2)).
+			Return(&core.User{ID: 2, Username: "someUser", Role: core.RoleViewer}, nil)
+
+		mockUserRepository.EXPECT().
+			Update(gomock.Any(), &core.User{ID: 2, Username: "someUser", Email: "user@example.com", Role: core.RoleAdmin}).
+			Return(nil)
+
+		rr := serve(newRequest("PATCH", "/users/2", `{"email":"user@example.com","isAdmin":true}`))
+
+		Expect(rr.Code).To(Equal(http.StatusOK))
+		Expect(rr.Body.String()).To(ContainSubstring(`"isAdmin":true`))
+	})
+
+	It("revokes all sessions and tokens of a disabled user", func() {
+		role = core.RoleAdmin
+
+		mockUserRepository.EXPECT().
+			FindById(gomock.Any(), int64(2)).
+			Return(&core.User{ID: 2, Role: core.RoleViewer}, nil)
+
+		mockUserRepository.EXPECT().
+			Update(gomock.Any(), &core.User{ID: 2, Role: core.RoleViewer, Disabled: true}).
+			Return(nil)
+
+		mockSessionManager.EXPECT().
+			RevokeAll(gomock.Any(), int64(2), nil).
+			Return(int64(3), nil)
+
+		mockTokenService.EXPECT().
+			RevokeAll(gomock.Any(), int64(2)).
+			Return(int64(1), nil)
+
+		rr := serve(newRequest("PATCH", "/users/2", `{"disabled":true}`))
+
+		Expect(rr.Code).To(Equal(http.StatusOK))
+		Expect(rr.Body.String()).To(ContainSubstring(`"disabled":true`))
+	})
+
+	DescribeTable("rejects updates of the current user",
+		func(method string, path string, body string, message string) {
+			role = core.RoleAdmin
+
+			rr := serve(newRequest(method, path, body))
+
+			Expect(rr.Code).To(Equal(http.StatusBadRequest))
+			Expect(rr.Body.String()).To(MatchJSON(`{"message":"` + message + `"}`))
+		},
+		Entry("disable", "PATCH", "/users/1", `{"disabled":true}`, "current user cannot be demoted or disabled"),
+		Entry("demote", "PATCH", "/users/1", `{"isAdmin":false}`, "current user cannot be demoted or disabled"),
+		Entry("reset password", "PUT", "/users/1/password", `{"password":"secret"}`,
+			"password of current user must be changed with the current password"),
+	)
+
+	It("allows an admin to revoke a token of a user", func() {
+		role = core.RoleAdmin
+
+		mockTokenService.EXPECT().
+			Revoke(gomock.Any(), int64(3), int64(2)).
+			Return(nil)
+
+		rr := serve(newRequest("DELETE", "/users/2/tokens/3", ""))
+
+		Expect(rr.Code).To(Equal(http.StatusNoContent))
+	})
+
+	It("allows an admin to disable two factor auth of a user", func() {
+		role = core.RoleAdmin
+
+		mockTwoFactorService.EXPECT().
+			Disable(gomock.Any(), int64(2)).
+			Return(nil)
+
+		rr := serve(newRequest("DELETE", "/users/2/two-factor-auth", ""))
+
+		Expect(rr.Code).To(Equal(http.StatusNoContent))
+	})
+
+	It("returns conflict if two factor auth of a user is not enabled", func() {
+		role = core.RoleAdmin
+
+		mockTwoFactorService.EXPECT().
+			Disable(gomock.Any(), int64(2)).
+			Return(core.ErrTwoFactorAuthNotEnabled)
+
+		rr := serve(newRequest("DELETE", "/users/2/two-factor-auth", ""))
+
+		Expect(rr.Code).To(Equal(http.StatusConflict))
+	})
+
+	DescribeTable("forbids a dvr-admin to manage tokens and two factor auth of other users",
+		func(method string, path string) {
+			role = core.RoleDVRAdmin
+
+			rr := serve(newRequest(method, path, ""))
+
+			Expect(rr.Code).To(Equal(http.StatusForbidden))
+		},
+		Entry("list tokens", "GET", "/users/2/tokens"),
+		Entry("revoke tokens", "DELETE", "/users/2/tokens"),
+		Entry("revoke token", "DELETE", "/users/2/tokens/3"),
+		Entry("disable two factor auth", "DELETE", "/users/2/two-factor-auth"),
+		Entry("list trusted devices", "GET", "/users/2/trusted-devices"),
+		Entry("revoke trusted devices", "DELETE", "/users/2/trusted-devices"),
+		Entry("revoke trusted device", "DELETE", "/users/2/trusted-devices/3"),
+	)
+
+	It("allows an admin to reset the password of a user", func() {
+		role = core.RoleAdmin
+
+		mockUserRepository.EXPECT().
+			FindById(gomock.Any(), int64(2)).
+			Return(&core.User{ID: 2, PasswordHash: "oldHash"}, nil)
+
+		mockPasswordHasher.EXPECT().
+			Hash("newPassword").
+			Return("newHash", nil)
+
+		mockUserRepository.EXPECT().
+			Update(gomock.Any(), gomock.Any()).
+			Do(func(_ context.Context, user *core.User) {
+				Expect(user.PasswordHash).To(Equal("newHash"))
+			}).
+			Return(nil)
+
+		mockTrustedDevices.EXPECT().
+			RevokeAll(gomock.Any(), int64(2)).
+			Return(int64(1), nil)
+
+		rr := serve(newRequest("PUT", "/users/2/password", `{"password":"newPassword"}`))
+
+		Expect(rr.Code).To(Equal(http.StatusNoContent))
+	})
+
+	It("allows an admin to revoke a trusted device of a user", func() {
+		role = core.RoleAdmin
+
+		mockTrustedDevices.EXPECT().
+			Revoke(gomock.Any(), int64(3), int64(2)).
+			Return(nil)
+
+		rr := serve(newRequest("DELETE", "/users/2/trusted-devices/3", ""))
+
+		Expect(rr.Code).To(Equal(http.StatusNoContent))
+	})
+
+	It("allows an admin to set the parental controls of a user", func() {
+		role = core.RoleAdmin
+
+		mockUserRepository.EXPECT().
+			FindById(gomock.Any(), int64(2)).
+			Return(&core.User{ID: 2}, nil)
+
+		mockParentalControls.EXPECT().
+			Save(gomock.Any(), &core.ParentalControls{
+				UserID:              2,
+				ChannelTags:         []string{"kidsTag"},
+				BlockedContentTypes: []int{16},
+			}).
+			Return(nil)
+
+		rr := serve(newRequest("PUT", "/users/2/parental-controls",
+			`{"channelTags":["kidsTag"],"blockedContentTypes":[16]}`))
+
+		Expect(rr.Code).To(Equal(http.StatusOK))
+	})
+
+	It("rejects invalid parental controls", func() {
+		role = core.RoleAdmin
+
+		mockUserRepository.EXPECT().
+			FindById(gomock.Any(), int64(2)).
+			Return(&core.User{ID: 2}, nil)
+
+		mockParentalControls.EXPECT().
+			Save(gomock.Any(), gomock.Any()).
+			Return(core.ErrParentalControlsInvalidViewingTime)
+
+		rr := serve(newRequest("PUT", "/users/2/parental-controls", `{"viewingFrom":"6am"}`))
+
+		Expect(rr.Code).To(Equal(http.StatusBadRequest))
+		Expect(rr.Body.String()).To(MatchJSON(`{"message":"viewing time must be formatted as HH:MM"}`))
+	})
+
+	DescribeTable("forbids a dvr-admin to manage parental controls",
+		func(method string) {
+			role = core.RoleDVRAdmin
+
+			rr := serve(newRequest(method, "/users/2/parental-controls", "{}"))
+
+			Expect(rr.Code).To(Equal(http.StatusForbidden))
+		},
+		Entry("get", "GET"),
+		Entry("update", "PUT"),
+		Entry("delete", "DELETE"),
+	)
+
+	DescribeTable("forbids scoped tokens requests outside of their scopes",
+		func(method string, path string) {
+			role = core.RoleAdmin
+			scopes = []core.Permission{core.PermissionEPGRead}
+
+			rr := serve(newRequest(method, path, "{}"))
+
+			Expect(rr.Code).To(Equal(http.StatusForbidden))
+			Expect(rr.Body.String()).To(MatchJSON(`{"message":"permission denied"}`))
+		},
+		Entry("users", "GET", "/users"),
+		Entry("recordings", "POST", "/recordings"),
+		Entry("account", "PATCH", "/user/password"),
+		Entry("tokens", "GET", "/tokens"),
+	)
+
+	It("forbids a scoped token to stop recordings of other users", func() {
+		role = core.RoleDVRAdmin
+		scopes = []core.Permission{core.PermissionRecordingsWrite}
+
+		mockRecordingOwners.EXPECT().
+			FindUserID(gomock.Any(), "someID").
+			Return(ownerID(2), nil)
+
+		rr := serve(newRequest("PUT", "/recordings/someID/stop", ""))
+
+		Expect(rr.Code).To(Equal(http.StatusForbidden))
+		Expect(recordings.stopped).To(BeEmpty())
+	})
+
+	It("allows a scoped token to stop recordings with the manage scope", func() {
+		role = core.RoleDVRAdmin
+		scopes = []core.Permission{core.PermissionRecordingsWrite, core.PermissionRecordingsManage}
+
+		rr := serve(newRequest("PUT", "/recordings/someID/stop", ""))
+
+		Expect(rr.Code).To(Equal(http.StatusNoContent))
+		Expect(recordings.stopped).To(Equal([]string{"someID"}))
+	})
+
+	It("forbids a token scoped to stream to sign recording urls", func() {
+		role = core.RoleRecorder
+		scopes = []core.Permission{core.PermissionStream}
+
+		rr := serve(newRequest("POST", "/streams/sign", `{"recordingId":"someID"}`))
+
+		Expect(rr.Code).To(Equal(http.StatusForbidden))
+		Expect(rr.Body.String()).To(MatchJSON(`{"message":"permission denied"}`))
+	})
+
+	It("applies the scopes of the signer to signed stream urls", func() {
+		role = core.RoleRecorder
+
+		sig := streamSigner.Sign(1, []core.Permission{core.PermissionStream},
+			core.RecordingStreamResource("someID"), "")
+
+		query := url.Values{}
+		query.Set("user", "1")
+		query.Set("scopes", "stream")
+		query.Set("expires", strconv.FormatInt(sig.Expires, 10))
+		query.Set("sig", sig.Signature)
+
+		req := newRequest("GET", "/recordings/someID/stream?"+query.Encode(), "")
+		req.Header.Del("Authorization")
+
+		rr := serve(req)
+
+		Expect(rr.Code).To(Equal(http.StatusForbidden))
+		Expect(rr.Body.String()).To(MatchJSON(`{"message":"permission denied"}`))
+	})
+
+	It("rejects signed stream urls with removed scopes", func() {
+		role = core.RoleRecorder
+
+		sig := streamSigner.Sign(1, []core.Permission{core.PermissionStream},
+			core.RecordingStreamResource("someID"), "")
+
+		query := url.Values{}
+		query.Set("user", "1")
+		query.Set("expires", strconv.FormatInt(sig.Expires, 10))
+		query.Set("sig", sig.Signature)
+
+		req := newRequest("GET", "/recordings/someID/stream?"+query.Encode(), "")
+		req.Header.Del("Authorization")
+
+		rr := serve(req)
+
+		Expect(rr.Code).To(Equal(http.StatusUnauthorized))
+	})
+})
@@ -41,7 +41,7 @@ func (s *router) GetPicon(w http.ResponseWriter, r *http.Request) {
 		log.Error().Int("id", id).
 			Err(err).Msg("failed to get picon")
 
-		response.InternalErrorCommon(w)
+		writeTvheadendError(w, err)
 		return
 	}
 
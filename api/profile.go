@@ -22,7 +22,7 @@ func (s *router) GetStreamProfiles(w http.ResponseWriter, r *http.Request) {
 	if err != nil {
 		log.Error().Err(err).Msg("failed to get channels")
 
-		response.InternalErrorCommon(w)
+		writeTvheadendError(w, err)
 		return
 	}
 
@@ -40,14 +40,25 @@ func (s *router) GetRecordings(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
+	filter, ok := s.contentFilter(w, r)
+	if !ok {
+		return
+	}
+
+	page := unpaginate(filter, &q.PaginationQueryParams)
+
 	recordings, err := s.recordings.GetAll(r.Context(), q)
 	if err != nil {
 		log.Error().Err(err).Msg("failed to get recordings")
 
-		response.InternalErrorCommon(w)
+		writeTvheadendError(w, err)
 		return
 	}
 
+	if filter != nil {
+		filterListResult(recordings, filter.AllowsRecording, page)
+	}
+
 	response.JSON(w, recordings, 200)
 }
 
@@ -68,7 +79,12 @@ func (s *router) GetRecordings(w http.ResponseWriter, r *http.Request) {
 func (s *router) GetRecording(w http.ResponseWriter, r *http.Request) {
 	id := chi.URLParam(r, "id")
 
-	recordings, err := s.recordings.Get(r.Context(), id)
+	filter, ok := s.contentFilter(w, r)
+	if !ok {
+		return
+	}
+
+	recording, err := s.recordings.Get(r.Context(), id)
 	if err != nil {
 		if err == core.ErrRecordingNotFound {
 			response.NotFound(w, err)
@@ -78,11 +94,16 @@ func (s *router) GetRecording(w http.ResponseWriter, r *http.Request) {
 		log.Error().Str("id", id).
 			Err(err).Msg("failed to get recording")
 
-		response.InternalErrorCommon(w)
+		writeTvheadendError(w, err)
 		return
 	}
 
-	response.JSON(w, recordings, 200)
+	if !filter.AllowsRecording(recording) {
+		writeContentRestricted(w)
+		return
+	}
+
+	response.JSON(w, recording, 200)
 }
 
 // CreateRecording godoc
@@ -110,14 +131,26 @@ func (s *router) CreateRecording(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	err := s.recordings.Create(r.Context(), in)
+	filter, ok := s.contentFilter(w, r)
+	if !ok {
+		return
+	}
+
+	if !filter.AllowsChannel(in.ChannelID) {
+		writeContentRestricted(w)
+		return
+	}
+
+	id, err := s.recordings.Create(r.Context(), in)
 	if err != nil {
 		log.Error().Err(err).Msg("failed to create recording")
 
-		response.InternalErrorCommon(w)
+		writeTvheadendError(w, err)
 		return
 	}
 
+	s.setRecordingOwner(r, id)
+
 	w.WriteHeader(201)
 }
 
@@ -146,15 +179,23 @@ func (s *router) CreateRecordingByEvent(w http.ResponseWriter, r *http.Request)
 		return
 	}
 
-	err := s.recordings.CreateByEvent(r.Context(), in)
+	if !s.authorizeEvent(w, r, in.EventID) {
+		return
+	}
+
+	ids, err := s.recordings.CreateByEvent(r.Context(), in)
 	if err != nil {
 
 		log.Error().Err(err).Msg("failed to create recording by event")
 
-		response.InternalErrorCommon(w)
+		writeTvheadendError(w, err)
 		return
 	}
 
+	for _, id := range ids {
+		s.setRecordingOwner(r, id)
+	}
+
 	w.WriteHeader(201)
 }
 
@@ -166,18 +207,23 @@ func (s *router) CreateRecordingByEvent(w http.ResponseWriter, r *http.Request)
 //	@Produce	json
 //	@Success	204
 //	@Failure	401	{object}	response.ErrorResponse
+//	@Failure	403	{object}	response.ErrorResponse
 //	@Failure	500	{object}	response.ErrorResponse
 //	@Security	JWT
 //	@Router		/recordings/{id}/stop [put]
 func (s *router) StopRecording(w http.ResponseWriter, r *http.Request) {
 	id := chi.URLParam(r, "id")
 
+	if !s.authorizeRecordings(w, r, id) {
+		return
+	}
+
 	err := s.recordings.Stop(r.Context(), id)
 	if err != nil {
 		log.Error().Str("id", id).
 			Err(err).Msg("failed to stop recording")
 
-		response.InternalErrorCommon(w)
+		writeTvheadendError(w, err)
 		return
 	}
 
@@ -193,6 +239,7 @@ func (s *router) StopRecording(w http.ResponseWriter, r *http.Request) {
 //	@Success	204
 //	@Failure	400	{object}	response.ErrorResponse
 //	@Failure	401	{object}	response.ErrorResponse
+//	@Failure	403	{object}	response.ErrorResponse
 //	@Failure	500	{object}	response.ErrorResponse
 //	@Security	JWT
 //	@Router		/recordings/stop [put]
@@ -205,12 +252,16 @@ func (s *router) BatchStopRecordings(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
+	if !s.authorizeRecordings(w, r, ids...) {
+		return
+	}
+
 	err := s.recordings.BatchStop(r.Context(), ids)
 	if err != nil {
 		log.Error().Interface("ids", ids).
 			Err(err).Msg("failed to stop recordings")
 
-		response.InternalErrorCommon(w)
+		writeTvheadendError(w, err)
 		return
 	}
 
@@ -225,21 +276,28 @@ func (s *router) BatchStopRecordings(w http.ResponseWriter, r *http.Request) {
 //	@Produce	json
 //	@Success	204
 //	@Failure	401	{object}	response.ErrorResponse
+//	@Failure	403	{object}	response.ErrorResponse
 //	@Failure	500	{object}	response.ErrorResponse
 //	@Security	JWT
 //	@Router		/recordings/{id}/cancel [put]
 func (s *router) CancelRecording(w http.ResponseWriter, r *http.Request) {
 	id := chi.URLParam(r, "id")
 
+	if !s.authorizeRecordings(w, r, id) {
+		return
+	}
+
 	err := s.recordings.Cancel(r.Context(), id)
 	if err != nil {
 		log.Error().Str("id", id).
 			Err(err).Msg("failed to cancel recording")
 
-		response.InternalErrorCommon(w)
+		writeTvheadendError(w, err)
 		return
 	}
 
+	s.recordAudit(r, core.AuditActionRecordingCancel, id)
+
 	w.WriteHeader(204)
 }
 
@@ -252,6 +310,7 @@ func (s *router) CancelRecording(w http.ResponseWriter, r *http.Request) {
 //	@Success	204
 //	@Failure	400	{object}	response.ErrorResponse
 //	@Failure	401	{object}	response.ErrorResponse
+//	@Failure	403	{object}	response.ErrorResponse
 //	@Failure	500	{object}	response.ErrorResponse
 //	@Security	JWT
 //	@Router		/recordings/cancel [put]
@@ -264,15 +323,23 @@ func (s *router) BatchCancelRecordings(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
+	if !s.authorizeRecordings(w, r, ids...) {
+		return
+	}
+
 	err := s.recordings.BatchCancel(r.Context(), ids)
 	if err != nil {
 		log.Error().Interface("ids", ids).
 			Err(err).Msg("failed to cancel recordings")
 
-		response.InternalErrorCommon(w)
+		writeTvheadendError(w, err)
 		return
 	}
 
+	for _, id := range ids {
+		s.recordAudit(r, core.AuditActionRecordingCancel, id)
+	}
+
 	w.WriteHeader(204)
 }
 
@@ -284,21 +351,29 @@ func (s *router) BatchCancelRecordings(w http.ResponseWriter, r *http.Request) {
 //	@Produce	json
 //	@Success	204
 //	@Failure	401	{object}	response.ErrorResponse
+//	@Failure	403	{object}	response.ErrorResponse
 //	@Failure	500	{object}	response.ErrorResponse
 //	@Security	JWT
 //	@Router		/recordings/{id} [delete]
 func (s *router) RemoveRecording(w http.ResponseWriter, r *http.Request) {
 	id := chi.URLParam(r, "id")
 
+	if !s.authorizeRecordings(w, r, id) {
+		return
+	}
+
 	err := s.recordings.Remove(r.Context(), id)
 	if err != nil {
 		log.Error().Str("id", id).
 			Err(err).Msg("failed to remove recording")
 
-		response.InternalErrorCommon(w)
+		writeTvheadendError(w, err)
 		return
 	}
 
+	s.removeRecordingOwner(r, id)
+	s.recordAudit(r, core.AuditActionRecordingRemove, id)
+
 	w.WriteHeader(204)
 }
 
@@ -311,6 +386,7 @@ func (s *router) RemoveRecording(w http.ResponseWriter, r *http.Request) {
 //	@Success	204
 //	@Failure	400	{object}	response.ErrorResponse
 //	@Failure	401	{object}	response.ErrorResponse
+//	@Failure	403	{object}	response.ErrorResponse
 //	@Failure	500	{object}	response.ErrorResponse
 //	@Security	JWT
 //	@Router		/recordings [delete]
@@ -323,15 +399,24 @@ func (s *router) BatchRemoveRecordings(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
+	if !s.authorizeRecordings(w, r, ids...) {
+		return
+	}
+
 	err := s.recordings.BatchRemove(r.Context(), ids)
 	if err != nil {
 		log.Error().Interface("ids", ids).
 			Err(err).Msg("failed to remove recordings")
 
-		response.InternalErrorCommon(w)
+		writeTvheadendError(w, err)
 		return
 	}
 
+	for _, id := range ids {
+		s.removeRecordingOwner(r, id)
+		s.recordAudit(r, core.AuditActionRecordingRemove, id)
+	}
+
 	w.WriteHeader(204)
 }
 
@@ -345,6 +430,7 @@ func (s *router) BatchRemoveRecordings(w http.ResponseWriter, r *http.Request) {
 //	@Success	204
 //	@Failure	400	{object}	response.ErrorResponse
 //	@Failure	401	{object}	response.ErrorResponse
+//	@Failure	403	{object}	response.ErrorResponse
 //	@Failure	500	{object}	response.ErrorResponse
 //	@Security	JWT
 //	@Router		/recordings/{id}/move/{dest} [put]
@@ -352,6 +438,10 @@ func (s *router) MoveRecording(w http.ResponseWriter, r *http.Request) {
 	id := chi.URLParam(r, "id")
 	dest := chi.URLParam(r, "dest")
 
+	if !s.authorizeRecordings(w, r, id) {
+		return
+	}
+
 	var err error
 	switch dest {
 	case "finished":
@@ -367,7 +457,7 @@ func (s *router) MoveRecording(w http.ResponseWriter, r *http.Request) {
 		log.Error().Str("id", id).Str("destination", dest).
 			Err(err).Msg("failed to move recording")
 
-		response.InternalErrorCommon(w)
+		writeTvheadendError(w, err)
 		return
 	}
 
@@ -384,12 +474,17 @@ func (s *router) MoveRecording(w http.ResponseWriter, r *http.Request) {
 //	@Success	201
 //	@Failure	400	{object}	response.ErrorResponse
 //	@Failure	401	{object}	response.ErrorResponse
+//	@Failure	403	{object}	response.ErrorResponse
 //	@Failure	500	{object}	response.ErrorResponse
 //	@Security	JWT
 //	@Router		/recordings/{id} [patch]
 func (s *router) UpdateRecording(w http.ResponseWriter, r *http.Request) {
 	id := chi.URLParam(r, "id")
 
+	if !s.authorizeRecordings(w, r, id) {
+		return
+	}
+
 	var in core.UpdateRecording
 	if err := request.BindJSON(r, &in); err != nil {
 		response.BadRequest(w, err)
@@ -406,9 +501,75 @@ func (s *router) UpdateRecording(w http.ResponseWriter, r *http.Request) {
 		log.Error().Str("id", id).
 			Err(err).Msg("failed to update recording")
 
-		response.InternalErrorCommon(w)
+		writeTvheadendError(w, err)
 		return
 	}
 
 	w.WriteHeader(201)
 }
+
+// authorizeRecordings checks if the current user may modify the recordings and
+// writes an error response if not. Users without the permission to manage all
+// recordings may only modify recordings they have created.
+func (s *router) authorizeRecordings(w http.ResponseWriter, r *http.Request, ids ...string) bool {
+	ctx, ok := request.GetAuthContext(r.Context())
+	if !ok {
+		response.InternalErrorCommon(w)
+		return false
+	}
+
+	user, err := s.users.FindById(r.Context(), ctx.UserID)
+	if err != nil {
+		response.InternalError(w, err)
+		return false
+	}
+
+	if user == nil {
+		response.Forbidden(w, core.ErrPermissionDenied)
+		return false
+	}
+
+	if user.HasPermission(core.PermissionRecordingsManage) &&
+		ctx.HasScope(core.PermissionRecordingsManage) {
+		return true
+	}
+
+	for _, id := range ids {
+		ownerID, err := s.recordingOwners.FindUserID(r.Context(), id)
+		if err != nil {
+			log.Error().Str("id", id).
+				Err(err).Msg("failed to find owner of recording")
+
+			writeTvheadendError(w, err)
+			return false
+		}
+
+		if ownerID == nil || *ownerID != user.ID {
+			response.Forbidden(w, core.ErrPermissionDenied)
+			return false
+		}
+	}
+
+	return true
+}
+
+// setRecordingOwner sets the current user as owner of a created recording.
+func (s *router) setRecordingOwner(r *http.Request, id string) {
+	ctx, ok := request.GetAuthContext(r.Context())
+	if !ok || id == "" {
+		return
+	}
+
+	if err := s.recordingOwners.Create(r.Context(), id, ctx.UserID); err != nil {
+		log.Error().Str("id", id).
+			Err(err).Msg("failed to set owner of recording")
+	}
+}
+
+// removeRecordingOwner removes the owner of a removed recording.
+func (s *router) removeRecordingOwner(r *http.Request, id string) {
+	if err := s.recordingOwners.Delete(r.Context(), id); err != nil {
+		log.Error().Str("id", id).
+			Err(err).Msg("failed to remove owner of recording")
+	}
+}
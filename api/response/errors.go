@@ -9,6 +9,8 @@ import (
 type (
 	ErrorResponse struct {
 		Message string `json:"message"`
+		// Code is an optional machine readable error code.
+		Code string `json:"code,omitempty"`
 	}
 )
 
@@ -17,6 +19,12 @@ func Error(w http.ResponseWriter, err error, status int) {
 	JSON(w, &ErrorResponse{Message: err.Error()}, status)
 }
 
+// ErrorWithCode writes the json encoded error message together
+// with a machine readable error code to the response.
+func ErrorWithCode(w http.ResponseWriter, err error, code string, status int) {
+	JSON(w, &ErrorResponse{Message: err.Error(), Code: code}, status)
+}
+
 // InternalError writes the json encoded error message to the response
 // with 500 internal server error status code.
 func InternalError(w http.ResponseWriter, err error) {
@@ -76,3 +84,15 @@ func Forbidden(w http.ResponseWriter, err error) {
 func Forbiddenf(w http.ResponseWriter, format string, a ...any) {
 	Error(w, fmt.Errorf(format, a...), 403)
 }
+
+// TooManyRequests writes the json encoded error message together with
+// a machine readable error code to the response with 429 too many requests status code.
+func TooManyRequests(w http.ResponseWriter, err error, code string) {
+	ErrorWithCode(w, err, code, 429)
+}
+
+// ServiceUnavailable writes the json encoded error message together with
+// a machine readable error code to the response with 503 service unavailable status code.
+func ServiceUnavailable(w http.ResponseWriter, err error, code string) {
+	ErrorWithCode(w, err, code, 503)
+}
@@ -0,0 +1,117 @@
+package api
+
+import (
+	"errors"
+	"net/http"
+	"strconv"
+
+	"github.com/davidborzek/tvhgo/api/response"
+	"github.com/davidborzek/tvhgo/config"
+	"github.com/go-chi/cors"
+	"github.com/rs/zerolog/log"
+)
+
+// swaggerContentSecurityPolicy is used for the swagger ui instead of the
+// configured policy, because it requires inline scripts and styles.
+const swaggerContentSecurityPolicy = "default-src 'self'; script-src 'self' 'unsafe-inline'; " +
+	"style-src 'self' 'unsafe-inline'; img-src 'self' data:; object-src 'none'; frame-ancestors 'none'"
+
+var errCrossOriginRequest = errors.New("cross-origin request rejected")
+
+// corsHandler returns the cors middleware for the allowed origins
+// or nil if no origins are allowed.
+func corsHandler(cfg *config.CORSConfig) func(http.Handler) http.Handler {
+	if len(cfg.AllowedOrigins) == 0 {
+		return nil
+	}
+
+	return cors.Handler(cors.Options{
+		AllowedOrigins:   cfg.AllowedOrigins,
+		AllowedMethods:   []string{"GET", "POST", "PUT", "PATCH", "DELETE", "OPTIONS"},
+		AllowedHeaders:   []string{"Accept", "Authorization", "Content-Type"},
+		AllowCredentials: true,
+		MaxAge:           300,
+	})
+}
+
+// CSRF rejects state-changing cross-origin requests from browsers, which
+// are not sent from an allowed origin. Requests with an Authorization
+// header are exempt, since browsers don't send it automatically.
+func (s *router) CSRF(next http.Handler) http.Handler {
+	protection := http.NewCrossOriginProtection()
+	for _, origin := range s.cfg.Server.CORS.AllowedOrigins {
+		if err := protection.AddTrustedOrigin(origin); err != nil {
+			log.Error().Str("origin", origin).
+				Err(err).Msg("failed to add trusted origin")
+		}
+	}
+
+	protection.SetDenyHandler(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		log.Warn().Str("origin", r.Header.Get("Origin")).
+			Str("path", r.URL.Path).Msg("rejected cross-origin request")
+
+		response.ErrorWithCode(w, errCrossOriginRequest, "csrf", http.StatusForbidden)
+	}))
+
+	protected := protection.Handler(next)
+
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Header.Get("Authorization") != "" {
+			next.ServeHTTP(w, r)
+			return
+		}
+
+		protected.ServeHTTP(w, r)
+	})
+}
+
+// SecurityHeaders sets the configured security headers on all responses.
+func SecurityHeaders(cfg *config.SecurityHeadersConfig) func(http.Handler) http.Handler {
+	return func(next http.Handler) http.Handler {
+		if cfg.Enabled == nil || !*cfg.Enabled {
+			return next
+		}
+
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			h := w.Header()
+			h.Set("X-Content-Type-Options", "nosniff")
+			h.Set("Referrer-Policy", "same-origin")
+
+			if cfg.ContentSecurityPolicy != "" {
+				h.Set("Content-Security-Policy", cfg.ContentSecurityPolicy)
+			}
+
+			if cfg.FrameOptions != "" {
+				h.Set("X-Frame-Options", cfg.FrameOptions)
+			}
+
+			if cfg.HSTSMaxAge > 0 && isHTTPS(r, cfg.TrustForwardedProto) {
+				h.Set("Strict-Transport-Security",
+					"max-age="+strconv.Itoa(int(cfg.HSTSMaxAge.Seconds())))
+			}
+
+			next.ServeHTTP(w, r)
+		})
+	}
+}
+
+// swaggerSecurityHeaders relaxes the content security policy for the swagger ui.
+func swaggerSecurityHeaders(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if w.Header().Get("Content-Security-Policy") != "" {
+			w.Header().Set("Content-Security-Policy", swaggerContentSecurityPolicy)
+		}
+
+		next.ServeHTTP(w, r)
+	})
+}
+
+// isHTTPS checks if the request was sent via https directly or, if the
+// forwarded proto is trusted, to a reverse proxy in front of tvhgo.
+func isHTTPS(r *http.Request, trustForwardedProto bool) bool {
+	if r.TLS != nil {
+		return true
+	}
+
+	return trustForwardedProto && r.Header.Get("X-Forwarded-Proto") == "https"
+}
@@ -0,0 +1,170 @@
+package api_test
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"time"
+
+	"github.com/davidborzek/tvhgo/api"
+	"github.com/davidborzek/tvhgo/config"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("Security", func() {
+	enabled := true
+
+	cfg := &config.Config{
+		Server: config.ServerConfig{
+			CORS: config.CORSConfig{
+				AllowedOrigins: []string{"https://app.example.com"},
+			},
+			CSRF: config.CSRFConfig{
+				Enabled: &enabled,
+			},
+		},
+	}
+
+	serve := func(req *http.Request) *httptest.ResponseRecorder {
+		sut := api.New(cfg, nil, nil, nil, nil, nil, nil, nil, nil, nil,
+			nil, nil, nil, nil, nil, nil, nil, nil, nil, nil, nil, nil, nil, nil, nil, nil, nil, nil)
+
+		rr := httptest.NewRecorder()
+		sut.Handler().ServeHTTP(rr, req)
+		return rr
+	}
+
+	// The login request is invalid, so requests passing
+	// the csrf protection are rejected with bad request.
+	newLoginRequest := func(origin string) *http.Request {
+		req, err := http.NewRequest("POST", "http://tvhgo.example.com/login", strings.NewReader("invalid"))
+		if err != nil {
+			Fail(err.Error())
+		}
+		if origin != "" {
+			req.Header.Set("Origin", origin)
+		}
+		return req
+	}
+
+	DescribeTable("allows state-changing requests",
+		func(origin string) {
+			rr := serve(newLoginRequest(origin))
+
+			Expect(rr.Code).To(Equal(http.StatusBadRequest))
+		},
+		Entry("without origin", ""),
+		Entry("from the same origin", "http://tvhgo.example.com"),
+		Entry("from an allowed origin", "https://app.example.com"),
+	)
+
+	It("rejects state-changing cross-origin requests", func() {
+		rr := serve(newLoginRequest("https://evil.example.com"))
+
+		Expect(rr.Code).To(Equal(http.StatusForbidden))
+		Expect(rr.Body.String()).To(MatchJSON(`{"message":"cross-origin request rejected","code":"csrf"}`))
+	})
+
+	It("rejects cross-site requests by fetch metadata", func() {
+		req := newLoginRequest("")
+		req.Header.Set("Sec-Fetch-Site", "cross-site")
+
+		rr := serve(req)
+
+		Expect(rr.Code).To(Equal(http.StatusForbidden))
+	})
+
+	It("exempts requests with an authorization header", func() {
+		req := newLoginRequest("https://evil.example.com")
+		req.Header.Set("Authorization", "Bearer someToken")
+
+		rr := serve(req)
+
+		Expect(rr.Code).To(Equal(http.StatusBadRequest))
+	})
+
+	It("allows cors requests with credentials from allowed origins", func() {
+		req := newLoginRequest("https://app.example.com")
+		req.Method = "OPTIONS"
+		req.Header.Set("Access-Control-Request-Method", "PATCH")
+
+		rr := serve(req)
+
+		Expect(rr.Header().Get("Access-Control-Allow-Origin")).To(Equal("https://app.example.com"))
+		Expect(rr.Header().Get("Access-Control-Allow-Credentials")).To(Equal("true"))
+		Expect(rr.Header().Get("Access-Control-Allow-Methods")).To(Equal("PATCH"))
+	})
+
+	It("does not allow cors requests from other origins", func() {
+		req := newLoginRequest("https://evil.example.com")
+		req.Method = "OPTIONS"
+		req.Header.Set("Access-Control-Request-Method", "POST")
+
+		rr := serve(req)
+
+		Expect(rr.Header().Get("Access-Control-Allow-Origin")).To(BeEmpty())
+	})
+
+	Describe("SecurityHeaders", func() {
+		headersCfg := &config.SecurityHeadersConfig{
+			Enabled:               &enabled,
+			ContentSecurityPolicy: "default-src 'self'",
+			FrameOptions:          "DENY",
+			HSTSMaxAge:            24 * time.Hour,
+		}
+
+		handler := api.SecurityHeaders(headersCfg)(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			w.WriteHeader(http.StatusOK)
+		}))
+
+		It("sets the security headers", func() {
+			req := httptest.NewRequest("GET", "http://tvhgo.example.com/", nil)
+			rr := httptest.NewRecorder()
+
+			handler.ServeHTTP(rr, req)
+
+			Expect(rr.Header().Get("Content-Security-Policy")).To(Equal("default-src 'self'"))
+			Expect(rr.Header().Get("X-Frame-Options")).To(Equal("DENY"))
+			Expect(rr.Header().Get("X-Content-Type-Options")).To(Equal("nosniff"))
+			Expect(rr.Header().Get("Strict-Transport-Security")).To(BeEmpty())
+		})
+
+		It("sets hsts for requests via https", func() {
+			req := httptest.NewRequest("GET", "https://tvhgo.example.com/", nil)
+			rr := httptest.NewRecorder()
+
+			handler.ServeHTTP(rr, req)
+
+			Expect(rr.Header().Get("Strict-Transport-Security")).To(Equal("max-age=86400"))
+		})
+
+		It("ignores the forwarded proto by default", func() {
+			req := httptest.NewRequest("GET", "http://tvhgo.example.com/", nil)
+			req.Header.Set("X-Forwarded-Proto", "https")
+			rr := httptest.NewRecorder()
+
+			handler.ServeHTTP(rr, req)
+
+			Expect(rr.Header().Get("Strict-Transport-Security")).To(BeEmpty())
+		})
+
+		It("sets hsts behind a trusted reverse proxy with https", func() {
+			proxyCfg := *headersCfg
+			proxyCfg.TrustForwardedProto = true
+
+			proxyHandler := api.SecurityHeaders(&proxyCfg)(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				w.WriteHeader(http.StatusOK)
+			}))
+
+			req := httptest.NewRequest("GET", "http://tvhgo.example.com/", nil)
+			req.Header.Set("X-Forwarded-Proto", "https")
+			rr := httptest.NewRecorder()
+
+			proxyHandler.ServeHTTP(rr, req)
+
+			Expect(rr.Header().Get("Strict-Transport-Security")).To(Equal("max-age=86400"))
+		})
+	})
+})
@@ -2,9 +2,11 @@ package api
 
 import (
 	"net/http"
+	"strconv"
 
 	"github.com/davidborzek/tvhgo/api/request"
 	"github.com/davidborzek/tvhgo/api/response"
+	"github.com/davidborzek/tvhgo/core"
 	"github.com/rs/zerolog/log"
 )
 
@@ -103,6 +105,8 @@ func (s *router) DeleteSession(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
+	s.recordAudit(r, core.AuditActionSessionRevoke, strconv.FormatInt(sessionID, 10))
+
 	w.WriteHeader(http.StatusNoContent)
 }
 
@@ -150,5 +154,118 @@ func (s *router) DeleteUserSession(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
+	s.recordAudit(r, core.AuditActionSessionRevoke, strconv.FormatInt(sessionID, 10))
+
 	w.WriteHeader(http.StatusNoContent)
 }
+
+// DeleteSessions godoc
+//
+//	@Summary	Revokes all other sessions of the current user
+//	@Tags		sessions
+//	@Produce	json
+//	@Success	204
+//	@Failure	401	{object}	response.ErrorResponse
+//	@Failure	500	{object}	response.ErrorResponse
+//	@Security	JWT
+//	@Router		/sessions [delete]
+func (s *router) DeleteSessions(w http.ResponseWriter, r *http.Request) {
+	ctx, ok := request.GetAuthContext(r.Context())
+	if !ok {
+		response.InternalErrorCommon(w)
+		return
+	}
+
+	if err := s.revokeSessions(r, ctx.UserID, ctx.SessionID); err != nil {
+		response.InternalErrorCommon(w)
+		return
+	}
+
+	w.WriteHeader(http.StatusNoContent)
+}
+
+// DeleteUserSessions godoc
+//
+//	@Summary	Revokes all sessions of a user
+//	@Tags		sessions
+//	@Param		id	path	int	true	"User id"
+//	@Produce	json
+//	@Success	204
+//	@Failure	400	{object}	response.ErrorResponse
+//	@Failure	401	{object}	response.ErrorResponse
+//	@Failure	403	{object}	response.ErrorResponse
+//	@Failure	500	{object}	response.ErrorResponse
+//	@Security	JWT
+//	@Router		/users/{id}/sessions [delete]
+func (s *router) DeleteUserSessions(w http.ResponseWriter, r *http.Request) {
+	userID, err := request.NumericURLParam(r, "id")
+	if err != nil {
+		response.BadRequestf(w, "invalid value for parameter 'id'")
+		return
+	}
+
+	ctx, ok := request.GetAuthContext(r.Context())
+	if !ok {
+		response.InternalErrorCommon(w)
+		return
+	}
+
+	// The current session is kept, when an admin revokes the own sessions.
+	var exceptSessionID *int64
+	if ctx.UserID == userID {
+		exceptSessionID = ctx.SessionID
+	}
+
+	if err := s.revokeSessions(r, userID, exceptSessionID); err != nil {
+		response.InternalErrorCommon(w)
+		return
+	}
+
+	w.WriteHeader(http.StatusNoContent)
+}
+
+// revokeSessions revokes all sessions of a user except the optional session.
+func (s *router) revokeSessions(r *http.Request, userID int64, exceptSessionID *int64) error {
+	revoked, err := s.sessionManager.RevokeAll(r.Context(), userID, exceptSessionID)
+	if err != nil {
+		log.Error().Int64("user", userID).
+			Err(err).Msg("failed to revoke sessions")
+
+		return err
+	}
+
+	log.Info().Int64("user", userID).Int64("revoked", revoked).
+		Msg("revoked sessions")
+
+	s.recordAudit(r, core.AuditActionSessionRevokeAll, strconv.FormatInt(userID, 10))
+	return nil
+}
+
+// revokeOtherSessionsAndTokens revokes all sessions except the current
+// session and all tokens of the authenticated user, e.g. after the
+// credentials have been changed.
+func (s *router) revokeOtherSessionsAndTokens(r *http.Request, ctx *core.AuthContext) error {
+	return s.revokeSessionsAndTokens(r, ctx.UserID, ctx.SessionID)
+}
+
+// revokeSessionsAndTokens revokes all sessions except the optional
+// session and all tokens of a user.
+func (s *router) revokeSessionsAndTokens(r *http.Request, userID int64, exceptSessionID *int64) error {
+	if err := s.revokeSessions(r, userID, exceptSessionID); err != nil {
+		return err
+	}
+
+	revoked, err := s.tokenService.RevokeAll(r.Context(), userID)
+	if err != nil {
+		log.Error().Int64("user", userID).
+			Err(err).Msg("failed to revoke tokens")
+
+		return err
+	}
+
+	log.Info().Int64("user", userID).Int64("revoked", revoked).
+		Msg("revoked tokens")
+
+	s.recordAudit(r, core.AuditActionTokenRevokeAll, strconv.FormatInt(userID, 10))
+	return nil
+}
@@ -0,0 +1,233 @@
+package api
+
+import (
+	"fmt"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+
+	"github.com/davidborzek/tvhgo/api/request"
+	"github.com/davidborzek/tvhgo/api/response"
+	"github.com/davidborzek/tvhgo/core"
+	"github.com/go-chi/chi/v5"
+	"github.com/rs/zerolog/log"
+)
+
+const (
+	streamSignatureUserParam    = "user"
+	streamSignatureScopesParam  = "scopes"
+	streamSignatureExpiresParam = "expires"
+	streamSignatureParam        = "sig"
+)
+
+type (
+	signStreamRequest struct {
+		// ChannelNumber is the number of the channel to sign.
+		// Either ChannelNumber or RecordingID must be set.
+		ChannelNumber *int64 `json:"channelNumber"`
+		// RecordingID is the id of the recording to sign.
+		// Either ChannelNumber or RecordingID must be set.
+		RecordingID *string `json:"recordingId"`
+		// Profile is the streaming profile of a channel stream.
+		Profile string `json:"profile"`
+		// HLS signs the HLS playlist instead of the raw stream of a channel.
+		HLS bool `json:"hls"`
+	}
+
+	signedStreamResponse struct {
+		URL       string `json:"url"`
+		ExpiresAt int64  `json:"expiresAt"`
+	}
+)
+
+// SignStream godoc
+//
+//	@Summary	Creates a signed and short-lived stream url
+//	@Tags		streams
+//	@Param		body	body	signStreamRequest	true	"Body"
+//	@Produce	json
+//	@Success	200	{object}	signedStreamResponse
+//	@Failure	400	{object}	response.ErrorResponse
+//	@Failure	401	{object}	response.ErrorResponse
+//	@Failure	403	{object}	response.ErrorResponse
+//	@Failure	500	{object}	response.ErrorResponse
+//	@Security	JWT
+//	@Router		/streams/sign [post]
+func (s *router) SignStream(w http.ResponseWriter, r *http.Request) {
+	ctx, ok := request.GetAuthContext(r.Context())
+	if !ok {
+		response.InternalErrorCommon(w)
+		return
+	}
+
+	var in signStreamRequest
+	if err := request.BindJSON(r, &in); err != nil {
+		response.BadRequest(w, err)
+		return
+	}
+
+	var (
+		path     string
+		resource string
+		query    = url.Values{}
+	)
+
+	switch {
+	case in.ChannelNumber != nil && in.RecordingID == nil:
+		path = fmt.Sprintf("/api/channels/%d/stream", *in.ChannelNumber)
+		if in.HLS {
+			path = fmt.Sprintf("/api/channels/%d/hls/index.m3u8", *in.ChannelNumber)
+		}
+
+		resource = core.ChannelStreamResource(*in.ChannelNumber)
+		if in.Profile != "" {
+			query.Set("profile", in.Profile)
+		}
+	case in.RecordingID != nil && in.ChannelNumber == nil:
+		if *in.RecordingID == "" {
+			response.BadRequestf(w, "invalid recordingId")
+			return
+		}
+
+		if in.Profile != "" || in.HLS {
+			response.BadRequestf(w, "profile and hls are only supported for channel streams")
+			return
+		}
+
+		// Signing is only guarded by the stream permission. A scoped
+		// token must not grant access to recordings via a signed url.
+		if !ctx.HasScope(core.PermissionRecordingsRead) {
+			response.Forbidden(w, core.ErrPermissionDenied)
+			return
+		}
+
+		path = fmt.Sprintf("/api/recordings/%s/stream", url.PathEscape(*in.RecordingID))
+		resource = core.RecordingStreamResource(*in.RecordingID)
+	default:
+		response.BadRequestf(w, "either channelNumber or recordingId must be provided")
+		return
+	}
+
+	sig := s.streamSigner.Sign(ctx.UserID, ctx.Scopes, resource, in.Profile)
+
+	query.Set(streamSignatureUserParam, strconv.FormatInt(sig.UserID, 10))
+	if sig.Scopes != nil {
+		query.Set(streamSignatureScopesParam, formatScopes(sig.Scopes))
+	}
+	query.Set(streamSignatureExpiresParam, strconv.FormatInt(sig.Expires, 10))
+	query.Set(streamSignatureParam, sig.Signature)
+
+	response.JSON(w, signedStreamResponse{
+		URL:       path + "?" + query.Encode(),
+		ExpiresAt: sig.Expires,
+	}, 200)
+}
+
+// HandleStreamAuthentication authenticates a stream request via a signed url.
+// Requests without a signature fall back to the regular authentication.
+func (s *router) HandleStreamAuthentication(
+	resource func(r *http.Request) string,
+) func(next http.Handler) http.Handler {
+	return func(next http.Handler) http.Handler {
+		authenticate := s.HandleAuthentication(next)
+
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			query := r.URL.Query()
+			if !query.Has(streamSignatureParam) {
+				authenticate.ServeHTTP(w, r)
+				return
+			}
+
+			userID, err := strconv.ParseInt(query.Get(streamSignatureUserParam), 10, 64)
+			if err != nil {
+				response.Unauthorized(w, core.ErrStreamSignatureInvalid)
+				return
+			}
+
+			expires, err := strconv.ParseInt(query.Get(streamSignatureExpiresParam), 10, 64)
+			if err != nil {
+				response.Unauthorized(w, core.ErrStreamSignatureInvalid)
+				return
+			}
+
+			var scopes []core.Permission
+			if query.Has(streamSignatureScopesParam) {
+				scopes = parseScopes(query.Get(streamSignatureScopesParam))
+			}
+
+			err = s.streamSigner.Verify(core.StreamSignature{
+				UserID:    userID,
+				Scopes:    scopes,
+				Resource:  resource(r),
+				Profile:   query.Get("profile"),
+				Expires:   expires,
+				Signature: query.Get(streamSignatureParam),
+			})
+			if err != nil {
+				response.Unauthorized(w, err)
+				return
+			}
+
+			user, err := s.users.FindById(r.Context(), userID)
+			if err != nil {
+				log.Error().Int64("user", userID).
+					Err(err).Msg("failed to find user of signed stream")
+
+				response.InternalErrorCommon(w)
+				return
+			}
+
+			if user == nil {
+				response.Unauthorized(w, core.ErrStreamSignatureInvalid)
+				return
+			}
+
+			next.ServeHTTP(w, r.WithContext(
+				request.WithAuthContext(r.Context(), &core.AuthContext{
+					UserID: user.ID,
+					Scopes: scopes,
+				}),
+			))
+		})
+	}
+}
+
+// channelStreamResource returns the signed resource of a channel stream request.
+func channelStreamResource(r *http.Request) string {
+	number, err := request.NumericURLParam(r, "number")
+	if err != nil {
+		return ""
+	}
+
+	return core.ChannelStreamResource(number)
+}
+
+// recordingStreamResource returns the signed resource of a recording stream request.
+func recordingStreamResource(r *http.Request) string {
+	return core.RecordingStreamResource(chi.URLParam(r, "id"))
+}
+
+// formatScopes encodes the scopes of a signed stream url.
+func formatScopes(scopes []core.Permission) string {
+	fields := make([]string, len(scopes))
+	for i, scope := range scopes {
+		fields[i] = string(scope)
+	}
+
+	return strings.Join(fields, ",")
+}
+
+// parseScopes decodes the scopes of a signed stream url.
+func parseScopes(value string) []core.Permission {
+	scopes := []core.Permission{}
+	if value == "" {
+		return scopes
+	}
+
+	for _, scope := range strings.Split(value, ",") {
+		scopes = append(scopes, core.Permission(scope))
+	}
+
+	return scopes
+}
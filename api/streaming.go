@@ -8,10 +8,24 @@ import (
 
 	"github.com/davidborzek/tvhgo/api/request"
 	"github.com/davidborzek/tvhgo/api/response"
+	"github.com/davidborzek/tvhgo/core"
 	"github.com/go-chi/chi/v5"
 	"github.com/rs/zerolog/log"
 )
 
+// countingResponseWriter reports the number of bytes
+// written to the underlying response writer.
+type countingResponseWriter struct {
+	http.ResponseWriter
+	onWrite func(n int64)
+}
+
+func (w *countingResponseWriter) Write(b []byte) (int, error) {
+	n, err := w.ResponseWriter.Write(b)
+	w.onWrite(int64(n))
+	return n, err
+}
+
 // StreamChannel godoc
 //
 //	@Summary	Stream a channel by channel number
@@ -22,6 +36,8 @@ import (
 //	@Produce	json
 //	@Success	200
 //	@Failure	401	{object}	response.ErrorResponse
+//	@Failure	429	{object}	response.ErrorResponse
+//	@Failure	503	{object}	response.ErrorResponse
 //	@Security	JWT
 //	@Router		/channels/{number}/stream [get]
 func (s *router) StreamChannel(w http.ResponseWriter, r *http.Request) {
@@ -34,17 +50,40 @@ func (s *router) StreamChannel(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	res, err := s.streaming.GetChannelStream(context.Background(), number, profile)
+	authCtx, ok := request.GetAuthContext(r.Context())
+	if !ok {
+		response.InternalErrorCommon(w)
+		return
+	}
+
+	if !s.authorizeChannelStream(w, r, number) {
+		return
+	}
+
+	id, ctx, err := s.streamLimiter.StartChannelStream(r.Context(), core.StreamSession{
+		UserID:        authCtx.UserID,
+		ChannelNumber: number,
+		Profile:       profile,
+		ClientIP:      request.RemoteAddr(r),
+		UserAgent:     r.UserAgent(),
+	})
+	if err != nil {
+		writeStreamLimitError(w, err)
+		return
+	}
+	defer s.streamSessions.Stop(id)
+
+	res, err := s.streaming.GetChannelStream(ctx, number, profile)
 	if err != nil {
 		log.Error().Int64("channel", number).
 			Err(err).Msg("failed to get channel stream")
 
-		response.InternalErrorCommon(w)
+		writeTvheadendError(w, err)
 		return
 	}
 
-	if _, err := response.CopyResponse(w, res); err != nil {
-		if errors.Is(err, syscall.EPIPE) {
+	if _, err := response.CopyResponse(s.countingWriter(w, id), res); err != nil {
+		if isStreamClosedError(err) {
 			return
 		}
 
@@ -68,17 +107,36 @@ func (s *router) StreamChannel(w http.ResponseWriter, r *http.Request) {
 func (s *router) StreamRecording(w http.ResponseWriter, r *http.Request) {
 	id := chi.URLParam(r, "id")
 
-	res, err := s.streaming.GetRecordingStream(context.Background(), id)
+	authCtx, ok := request.GetAuthContext(r.Context())
+	if !ok {
+		response.InternalErrorCommon(w)
+		return
+	}
+
+	if !s.authorizeRecordingStream(w, r, id) {
+		return
+	}
+
+	sessionID, ctx := s.streamSessions.Start(r.Context(), core.StreamSession{
+		UserID:      authCtx.UserID,
+		Type:        core.StreamTypeRecording,
+		RecordingID: id,
+		ClientIP:    request.RemoteAddr(r),
+		UserAgent:   r.UserAgent(),
+	})
+	defer s.streamSessions.Stop(sessionID)
+
+	res, err := s.streaming.GetRecordingStream(ctx, id)
 	if err != nil {
 		log.Error().Str("id", id).
 			Err(err).Msg("failed to get recording stream")
 
-		response.InternalErrorCommon(w)
+		writeTvheadendError(w, err)
 		return
 	}
 
-	if _, err := response.CopyResponse(w, res); err != nil {
-		if errors.Is(err, syscall.EPIPE) {
+	if _, err := response.CopyResponse(s.countingWriter(w, sessionID), res); err != nil {
+		if isStreamClosedError(err) {
 			return
 		}
 
@@ -86,3 +144,80 @@ func (s *router) StreamRecording(w http.ResponseWriter, r *http.Request) {
 			Err(err).Msg("unexpected error occurred during streaming the recording")
 	}
 }
+
+// GetStreamSessions godoc
+//
+//	@Summary	Get list of active stream sessions
+//	@Tags		streams
+//	@Produce	json
+//	@Success	200	{array}		core.StreamSession
+//	@Failure	401	{object}	response.ErrorResponse
+//	@Failure	403	{object}	response.ErrorResponse
+//	@Security	JWT
+//	@Router		/streams [get]
+func (s *router) GetStreamSessions(w http.ResponseWriter, r *http.Request) {
+	response.JSON(w, s.streamSessions.List(), 200)
+}
+
+// TerminateStreamSession godoc
+//
+//	@Summary	Terminates an active stream session
+//	@Tags		streams
+//	@Param		id	path	string	true	"Stream session id"
+//	@Success	204
+//	@Failure	401	{object}	response.ErrorResponse
+//	@Failure	403	{object}	response.ErrorResponse
+//	@Failure	404	{object}	response.ErrorResponse
+//	@Security	JWT
+//	@Router		/streams/{id} [delete]
+func (s *router) TerminateStreamSession(w http.ResponseWriter, r *http.Request) {
+	id := chi.URLParam(r, "id")
+
+	if err := s.streamSessions.Terminate(id); err != nil {
+		if errors.Is(err, core.ErrStreamSessionNotFound) {
+			response.NotFound(w, err)
+			return
+		}
+
+		response.InternalError(w, err)
+		return
+	}
+
+	w.WriteHeader(http.StatusNoContent)
+}
+
+// writeStreamLimitError writes a structured error response
+// for a rejected stream.
+func writeStreamLimitError(w http.ResponseWriter, err error) {
+	switch err {
+	case core.ErrStreamLimitPerUserReached:
+		response.TooManyRequests(w, err, "stream_limit_user")
+	case core.ErrStreamLimitReached:
+		response.TooManyRequests(w, err, "stream_limit_global")
+	case core.ErrTunersReserved:
+		response.ServiceUnavailable(w, err, "tuners_reserved")
+	default:
+		log.Error().Err(err).Msg("failed to check stream limits")
+
+		writeTvheadendError(w, err)
+	}
+}
+
+// countingWriter wraps the response writer to track
+// the transferred bytes of a stream session.
+func (s *router) countingWriter(w http.ResponseWriter, sessionID string) http.ResponseWriter {
+	return &countingResponseWriter{
+		ResponseWriter: w,
+		onWrite: func(n int64) {
+			s.streamSessions.AddBytes(sessionID, n)
+		},
+	}
+}
+
+// isStreamClosedError checks if a streaming error was caused by
+// the client disconnecting or the stream session being terminated.
+func isStreamClosedError(err error) bool {
+	return errors.Is(err, syscall.EPIPE) ||
+		errors.Is(err, syscall.ECONNRESET) ||
+		errors.Is(err, context.Canceled)
+}
@@ -1,10 +1,13 @@
 package api
 
 import (
+	"errors"
 	"net/http"
+	"strconv"
 
 	"github.com/davidborzek/tvhgo/api/request"
 	"github.com/davidborzek/tvhgo/api/response"
+	"github.com/davidborzek/tvhgo/core"
 	"github.com/rs/zerolog/log"
 )
 
@@ -14,6 +17,10 @@ type tokenResponse struct {
 
 type createTokenRequest struct {
 	Name string `json:"name"`
+	// ExpiresAt is an optional unix timestamp after which the token is invalid.
+	ExpiresAt *int64 `json:"expiresAt"`
+	// Scopes optionally restrict the token to a subset of the permissions of the user.
+	Scopes []core.Permission `json:"scopes"`
 }
 
 // GetSessions godoc
@@ -52,6 +59,7 @@ func (s *router) GetTokens(w http.ResponseWriter, r *http.Request) {
 //	@Param		body	body	createTokenRequest	true	"Body"
 //	@Produce	json
 //	@Success	200	{object}	tokenResponse
+//	@Failure	400	{object}	response.ErrorResponse
 //	@Failure	401	{object}	response.ErrorResponse
 //	@Failure	403	{object}	response.ErrorResponse
 //	@Failure	500	{object}	response.ErrorResponse
@@ -80,14 +88,26 @@ func (s *router) CreateToken(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	token, err := s.tokenService.Create(r.Context(), ctx.UserID, in.Name)
+	token, err := s.tokenService.Create(r.Context(), ctx.UserID, core.CreateToken{
+		Name:      in.Name,
+		ExpiresAt: in.ExpiresAt,
+		Scopes:    in.Scopes,
+	})
 	if err != nil {
+		if errors.Is(err, core.ErrTokenScopeInvalid) ||
+			errors.Is(err, core.ErrTokenExpiryInvalid) {
+			response.BadRequest(w, err)
+			return
+		}
+
 		log.Error().Err(err).Msg("failed to create tokens")
 
 		response.InternalErrorCommon(w)
 		return
 	}
 
+	s.recordAudit(r, core.AuditActionTokenCreate, in.Name)
+
 	response.JSON(w, &tokenResponse{Token: token}, 200)
 }
 
@@ -121,7 +141,7 @@ func (s *router) DeleteToken(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	if err := s.tokenService.Revoke(r.Context(), tokenID); err != nil {
+	if err := s.tokenService.Revoke(r.Context(), tokenID, ctx.UserID); err != nil {
 		log.Error().Int64("id", tokenID).
 			Err(err).Msg("failed to get tokens")
 
@@ -129,5 +149,115 @@ func (s *router) DeleteToken(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
+	s.recordAudit(r, core.AuditActionTokenRevoke, strconv.FormatInt(tokenID, 10))
+
+	w.WriteHeader(http.StatusNoContent)
+}
+
+// GetUserTokens godoc
+//
+//	@Summary	Get list of tokens for a user
+//	@Tags		tokens
+//	@Param		id	path	int	true	"User id"
+//	@Produce	json
+//	@Success	200	{array}		core.Token
+//	@Failure	400	{object}	response.ErrorResponse
+//	@Failure	401	{object}	response.ErrorResponse
+//	@Failure	403	{object}	response.ErrorResponse
+//	@Failure	500	{object}	response.ErrorResponse
+//	@Security	JWT
+//	@Router		/users/{id}/tokens [get]
+func (s *router) GetUserTokens(w http.ResponseWriter, r *http.Request) {
+	userID, err := request.NumericURLParam(r, "id")
+	if err != nil {
+		response.BadRequestf(w, "invalid value for parameter 'id'")
+		return
+	}
+
+	tokens, err := s.tokens.FindByUser(r.Context(), userID)
+	if err != nil {
+		log.Error().Err(err).Msg("failed to get tokens")
+
+		response.InternalErrorCommon(w)
+		return
+	}
+
+	response.JSON(w, tokens, 200)
+}
+
+// DeleteUserToken godoc
+//
+//	@Summary	Revokes a token of a user
+//	@Tags		tokens
+//	@Param		id		path	int	true	"Token id"
+//	@Param		userId	path	int	true	"User id"
+//	@Produce	json
+//	@Success	204
+//	@Failure	400	{object}	response.ErrorResponse
+//	@Failure	401	{object}	response.ErrorResponse
+//	@Failure	403	{object}	response.ErrorResponse
+//	@Failure	500	{object}	response.ErrorResponse
+//	@Security	JWT
+//	@Router		/users/{userId}/tokens/{id} [delete]
+func (s *router) DeleteUserToken(w http.ResponseWriter, r *http.Request) {
+	userID, err := request.NumericURLParam(r, "userId")
+	if err != nil {
+		response.BadRequestf(w, "invalid value for parameter 'userId'")
+		return
+	}
+
+	tokenID, err := request.NumericURLParam(r, "id")
+	if err != nil {
+		response.BadRequestf(w, "invalid value for parameter 'id'")
+		return
+	}
+
+	if err := s.tokenService.Revoke(r.Context(), tokenID, userID); err != nil {
+		log.Error().Int64("id", tokenID).
+			Err(err).Msg("failed to revoke token")
+
+		response.InternalErrorCommon(w)
+		return
+	}
+
+	s.recordAudit(r, core.AuditActionTokenRevoke, strconv.FormatInt(tokenID, 10))
+
+	w.WriteHeader(http.StatusNoContent)
+}
+
+// DeleteUserTokens godoc
+//
+//	@Summary	Revokes all tokens of a user
+//	@Tags		tokens
+//	@Param		id	path	int	true	"User id"
+//	@Produce	json
+//	@Success	204
+//	@Failure	400	{object}	response.ErrorResponse
+//	@Failure	401	{object}	response.ErrorResponse
+//	@Failure	403	{object}	response.ErrorResponse
+//	@Failure	500	{object}	response.ErrorResponse
+//	@Security	JWT
+//	@Router		/users/{id}/tokens [delete]
+func (s *router) DeleteUserTokens(w http.ResponseWriter, r *http.Request) {
+	userID, err := request.NumericURLParam(r, "id")
+	if err != nil {
+		response.BadRequestf(w, "invalid value for parameter 'id'")
+		return
+	}
+
+	revoked, err := s.tokenService.RevokeAll(r.Context(), userID)
+	if err != nil {
+		log.Error().Int64("user", userID).
+			Err(err).Msg("failed to revoke tokens")
+
+		response.InternalErrorCommon(w)
+		return
+	}
+
+	log.Info().Int64("user", userID).Int64("revoked", revoked).
+		Msg("revoked tokens")
+
+	s.recordAudit(r, core.AuditActionTokenRevokeAll, strconv.FormatInt(userID, 10))
+
 	w.WriteHeader(http.StatusNoContent)
 }
@@ -0,0 +1,298 @@
+package api
+
+import (
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/davidborzek/tvhgo/api/request"
+	"github.com/davidborzek/tvhgo/api/response"
+	"github.com/davidborzek/tvhgo/core"
+	"github.com/rs/zerolog/log"
+)
+
+// GetTrustedDevices godoc
+//
+//	@Summary	Get list of trusted devices for the current user
+//	@Tags		sessions
+//	@Produce	json
+//	@Success	200	{array}		core.TrustedDevice
+//	@Failure	401	{object}	response.ErrorResponse
+//	@Failure	500	{object}	response.ErrorResponse
+//	@Security	JWT
+//	@Router		/trusted-devices [get]
+func (s *router) GetTrustedDevices(w http.ResponseWriter, r *http.Request) {
+	ctx, ok := request.GetAuthContext(r.Context())
+	if !ok {
+		response.InternalErrorCommon(w)
+		return
+	}
+
+	s.writeTrustedDevices(w, r, ctx.UserID)
+}
+
+// GetUserTrustedDevices godoc
+//
+//	@Summary	Get list of trusted devices for a user
+//	@Tags		sessions
+//	@Param		id	path	int	true	"User id"
+//	@Produce	json
+//	@Success	200	{array}		core.TrustedDevice
+//	@Failure	400	{object}	response.ErrorResponse
+//	@Failure	401	{object}	response.ErrorResponse
+//	@Failure	403	{object}	response.ErrorResponse
+//	@Failure	500	{object}	response.ErrorResponse
+//	@Security	JWT
+//	@Router		/users/{id}/trusted-devices [get]
+func (s *router) GetUserTrustedDevices(w http.ResponseWriter, r *http.Request) {
+	userID, err := request.NumericURLParam(r, "id")
+	if err != nil {
+		response.BadRequestf(w, "invalid value for parameter 'id'")
+		return
+	}
+
+	s.writeTrustedDevices(w, r, userID)
+}
+
+// DeleteTrustedDevice godoc
+//
+//	@Summary	Revokes a trusted device of the current user
+//	@Tags		sessions
+//	@Param		id	path	int	true	"Trusted device id"
+//	@Success	204
+//	@Failure	400	{object}	response.ErrorResponse
+//	@Failure	401	{object}	response.ErrorResponse
+//	@Failure	500	{object}	response.ErrorResponse
+//	@Security	JWT
+//	@Router		/trusted-devices/{id} [delete]
+func (s *router) DeleteTrustedDevice(w http.ResponseWriter, r *http.Request) {
+	ctx, ok := request.GetAuthContext(r.Context())
+	if !ok {
+		response.InternalErrorCommon(w)
+		return
+	}
+
+	id, err := request.NumericURLParam(r, "id")
+	if err != nil {
+		response.BadRequestf(w, "invalid value for parameter 'id'")
+		return
+	}
+
+	s.revokeTrustedDevice(w, r, id, ctx.UserID)
+}
+
+// DeleteUserTrustedDevice godoc
+//
+//	@Summary	Revokes a trusted device of a user
+//	@Tags		sessions
+//	@Param		id		path	int	true	"Trusted device id"
+//	@Param		userId	path	int	true	"User id"
+//	@Success	204
+//	@Failure	400	{object}	response.ErrorResponse
+//	@Failure	401	{object}	response.ErrorResponse
+//	@Failure	403	{object}	response.ErrorResponse
+//	@Failure	500	{object}	response.ErrorResponse
+//	@Security	JWT
+//	@Router		/users/{userId}/trusted-devices/{id} [delete]
+func (s *router) DeleteUserTrustedDevice(w http.ResponseWriter, r *http.Request) {
+	userID, err := request.NumericURLParam(r, "userId")
+	if err != nil {
+		response.BadRequestf(w, "invalid value for parameter 'userId'")
+		return
+	}
+
+	id, err := request.NumericURLParam(r, "id")
+	if err != nil {
+		response.BadRequestf(w, "invalid value for parameter 'id'")
+		return
+	}
+
+	s.revokeTrustedDevice(w, r, id, userID)
+}
+
+// DeleteTrustedDevices godoc
+//
+//	@Summary	Revokes all trusted devices of the current user
+//	@Tags		sessions
+//	@Success	204
+//	@Failure	401	{object}	response.ErrorResponse
+//	@Failure	500	{object}	response.ErrorResponse
+//	@Security	JWT
+//	@Router		/trusted-devices [delete]
+func (s *router) DeleteTrustedDevices(w http.ResponseWriter, r *http.Request) {
+	ctx, ok := request.GetAuthContext(r.Context())
+	if !ok {
+		response.InternalErrorCommon(w)
+		return
+	}
+
+	if err := s.revokeTrustedDevices(r, ctx.UserID); err != nil {
+		response.InternalErrorCommon(w)
+		return
+	}
+
+	w.WriteHeader(http.StatusNoContent)
+}
+
+// DeleteUserTrustedDevices godoc
+//
+//	@Summary	Revokes all trusted devices of a user
+//	@Tags		sessions
+//	@Param		id	path	int	true	"User id"
+//	@Success	204
+//	@Failure	400	{object}	response.ErrorResponse
+//	@Failure	401	{object}	response.ErrorResponse
+//	@Failure	403	{object}	response.ErrorResponse
+//	@Failure	500	{object}	response.ErrorResponse
+//	@Security	JWT
+//	@Router		/users/{id}/trusted-devices [delete]
+func (s *router) DeleteUserTrustedDevices(w http.ResponseWriter, r *http.Request) {
+	userID, err := request.NumericURLParam(r, "id")
+	if err != nil {
+		response.BadRequestf(w, "invalid value for parameter 'id'")
+		return
+	}
+
+	if err := s.revokeTrustedDevices(r, userID); err != nil {
+		response.InternalErrorCommon(w)
+		return
+	}
+
+	w.WriteHeader(http.StatusNoContent)
+}
+
+func (s *router) writeTrustedDevices(w http.ResponseWriter, r *http.Request, userID int64) {
+	devices, err := s.trustedDevices.List(r.Context(), userID)
+	if err != nil {
+		response.InternalErrorCommon(w)
+		return
+	}
+
+	response.JSON(w, devices, 200)
+}
+
+func (s *router) revokeTrustedDevice(w http.ResponseWriter, r *http.Request, id int64, userID int64) {
+	if err := s.trustedDevices.Revoke(r.Context(), id, userID); err != nil {
+		log.Error().Int64("id", id).
+			Err(err).Msg("failed to revoke trusted device")
+
+		response.InternalErrorCommon(w)
+		return
+	}
+
+	s.recordAudit(r, core.AuditActionTrustedDeviceRevoke, strconv.FormatInt(id, 10))
+
+	w.WriteHeader(http.StatusNoContent)
+}
+
+// revokeTrustedDevices revokes all trusted devices of a user,
+// e.g. after the password has been changed.
+func (s *router) revokeTrustedDevices(r *http.Request, userID int64) error {
+	revoked, err := s.trustedDevices.RevokeAll(r.Context(), userID)
+	if err != nil {
+		log.Error().Int64("user", userID).
+			Err(err).Msg("failed to revoke trusted devices")
+
+		return err
+	}
+
+	log.Info().Int64("user", userID).Int64("revoked", revoked).
+		Msg("revoked trusted devices")
+
+	s.recordAudit(r, core.AuditActionTrustedDeviceRevokeAll, strconv.FormatInt(userID, 10))
+	return nil
+}
+
+// validateTrustDeviceDays checks the requested lifetime of a trusted device
+// before the login, so that a login doesn't succeed partially.
+func (s *router) validateTrustDeviceDays(days int) error {
+	if days == 0 {
+		return nil
+	}
+
+	if !s.cfg.Auth.TrustedDevice.Enabled {
+		return core.ErrTrustedDevicesDisabled
+	}
+
+	if days < 0 || time.Duration(days)*24*time.Hour > s.cfg.Auth.TrustedDevice.MaxLifetime {
+		return core.ErrTrustedDeviceLifetimeInvalid
+	}
+
+	return nil
+}
+
+// verifyTrustedDevice returns the trusted device of the device cookie or nil
+// if the cookie is missing or invalid. An invalid cookie is removed.
+func (s *router) verifyTrustedDevice(w http.ResponseWriter, r *http.Request) (*core.TrustedDevice, error) {
+	if !s.cfg.Auth.TrustedDevice.Enabled {
+		return nil, nil
+	}
+
+	cookie, err := r.Cookie(s.cfg.Auth.TrustedDevice.CookieName)
+	if err != nil {
+		return nil, nil
+	}
+
+	device, err := s.trustedDevices.Verify(r.Context(), cookie.Value)
+	if err != nil {
+		return nil, err
+	}
+
+	if device == nil {
+		setTrustedDeviceCookie(w, s.cfg.Auth.TrustedDevice.CookieName, "", -1,
+			s.cfg.Auth.Session.CookieSecure)
+	}
+
+	return device, nil
+}
+
+// trustDevice trusts the device of a login if the user
+// has two factor auth enabled and sets the device cookie.
+func (s *router) trustDevice(w http.ResponseWriter, r *http.Request, userID int64, days int) error {
+	settings, err := s.twoFactorService.GetSettings(r.Context(), userID)
+	if err != nil {
+		return err
+	}
+
+	if settings == nil || !settings.Enabled {
+		return nil
+	}
+
+	token, device, err := s.trustedDevices.Trust(
+		r.Context(),
+		userID,
+		days,
+		request.RemoteAddr(r),
+		r.UserAgent(),
+	)
+	if err != nil {
+		return err
+	}
+
+	s.recordAuditEntry(r, &core.AuditEntry{
+		UserID: &userID,
+		Action: core.AuditActionTrustedDeviceCreate,
+		Target: strconv.FormatInt(device.ID, 10),
+	})
+
+	setTrustedDeviceCookie(w, s.cfg.Auth.TrustedDevice.CookieName, token,
+		int((time.Duration(days) * 24 * time.Hour).Seconds()),
+		s.cfg.Auth.Session.CookieSecure)
+	return nil
+}
+
+// Internal implementation to set the trusted device cookie,
+// which is only sent to the login.
+func setTrustedDeviceCookie(w http.ResponseWriter, cookieName string, token string, maxAge int, secure bool) {
+	c := &http.Cookie{
+		Name:     cookieName,
+		Value:    token,
+		Path:     "/api/login",
+		MaxAge:   maxAge,
+		Secure:   secure,
+		HttpOnly: true,
+		SameSite: http.SameSiteStrictMode,
+	}
+
+	http.SetCookie(w, c)
+}
@@ -0,0 +1,21 @@
+package api
+
+import (
+	"errors"
+	"net/http"
+
+	"github.com/davidborzek/tvhgo/api/response"
+	"github.com/davidborzek/tvhgo/tvheadend"
+)
+
+// writeTvheadendError writes a 503 service unavailable error response
+// if tvheadend is unavailable and a 500 internal error otherwise.
+// The cause is not exposed, since it may contain internal addresses.
+func writeTvheadendError(w http.ResponseWriter, err error) {
+	if errors.Is(err, tvheadend.ErrTvheadendUnavailable) {
+		response.ServiceUnavailable(w, tvheadend.ErrTvheadendUnavailable, "tvheadend_unavailable")
+		return
+	}
+
+	response.InternalErrorCommon(w)
+}
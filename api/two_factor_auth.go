@@ -2,6 +2,7 @@ package api
 
 import (
 	"net/http"
+	"strconv"
 
 	"github.com/davidborzek/tvhgo/api/request"
 	"github.com/davidborzek/tvhgo/api/response"
@@ -20,12 +21,24 @@ type twoFactorAuthSetupRequest struct {
 type twoFactorAuthActivateRequest struct {
 	Password string `json:"password"`
 	Code     string `json:"code"`
+	// RevokeSessions revokes all other sessions and all tokens of the user.
+	RevokeSessions bool `json:"revokeSessions"`
 }
 
 type twoFactorAuthDeactivateRequest struct {
 	Code string `json:"code"`
 }
 
+type twoFactorAuthRecoveryCodesRequest struct {
+	Password string `json:"password"`
+}
+
+type twoFactorAuthRecoveryCodesResponse struct {
+	// RecoveryCodes are single-use codes, which can be used instead of a totp code.
+	// They are only returned once and cannot be retrieved later.
+	RecoveryCodes []string `json:"recovery_codes"`
+}
+
 // SetupTwoFactorAuth godoc
 //
 //	@Summary	Starts the two factor auth setup for the current user
@@ -85,7 +98,7 @@ func (s *router) SetupTwoFactorAuth(w http.ResponseWriter, r *http.Request) {
 //	@Tags		two-factor-auth
 //	@Param		body	body	twoFactorAuthActivateRequest	true	"Body"
 //	@Produce	json
-//	@Success	204
+//	@Success	200	{object}	twoFactorAuthRecoveryCodesResponse
 //	@Failure	400	{object}	response.ErrorResponse
 //	@Failure	401	{object}	response.ErrorResponse
 //	@Failure	409	{object}	response.ErrorResponse
@@ -115,7 +128,7 @@ func (s *router) ActivateTwoFactorAuth(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	err := s.twoFactorService.Activate(r.Context(), ctx.UserID, in.Code)
+	recoveryCodes, err := s.twoFactorService.Activate(r.Context(), ctx.UserID, in.Code)
 	if err != nil {
 		if err == core.ErrTwoFactorAuthAlreadyEnabled ||
 			err == core.ErrTwoFactorAuthSetupNotRunning {
@@ -135,7 +148,71 @@ func (s *router) ActivateTwoFactorAuth(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	w.WriteHeader(204)
+	s.recordAudit(r, core.AuditActionTwoFactorActivate, strconv.FormatInt(ctx.UserID, 10))
+
+	if in.RevokeSessions {
+		if err := s.revokeOtherSessionsAndTokens(r, ctx); err != nil {
+			response.InternalErrorCommon(w)
+			return
+		}
+	}
+
+	response.JSON(w, twoFactorAuthRecoveryCodesResponse{RecoveryCodes: recoveryCodes}, 200)
+}
+
+// RegenerateTwoFactorAuthRecoveryCodes godoc
+//
+//	@Summary	Replaces the two factor auth recovery codes for the current user
+//	@Tags		two-factor-auth
+//	@Param		body	body	twoFactorAuthRecoveryCodesRequest	true	"Body"
+//	@Produce	json
+//	@Success	200	{object}	twoFactorAuthRecoveryCodesResponse
+//	@Failure	400	{object}	response.ErrorResponse
+//	@Failure	401	{object}	response.ErrorResponse
+//	@Failure	409	{object}	response.ErrorResponse
+//	@Failure	500	{object}	response.ErrorResponse
+//	@Security	JWT
+//	@Router		/two-factor-auth/recovery-codes [put]
+func (s *router) RegenerateTwoFactorAuthRecoveryCodes(w http.ResponseWriter, r *http.Request) {
+	ctx, ok := request.GetAuthContext(r.Context())
+	if !ok {
+		response.InternalErrorCommon(w)
+		return
+	}
+
+	var in twoFactorAuthRecoveryCodesRequest
+	if err := request.BindJSON(r, &in); err != nil {
+		response.BadRequest(w, err)
+		return
+	}
+
+	if err := s.passwordAuthenticator.ConfirmPassword(r.Context(), ctx.UserID, in.Password); err != nil {
+		if err == core.ErrConfirmationPasswordInvalid {
+			response.BadRequest(w, err)
+		} else {
+			response.InternalErrorCommon(w)
+		}
+
+		return
+	}
+
+	recoveryCodes, err := s.twoFactorService.RegenerateRecoveryCodes(r.Context(), ctx.UserID)
+	if err != nil {
+		if err == core.ErrTwoFactorAuthNotEnabled {
+			response.Conflict(w, err)
+			return
+		}
+
+		log.Error().Int64("id", ctx.UserID).
+			Err(err).Msg("failed to regenerate two factor auth recovery codes")
+
+		response.InternalErrorCommon(w)
+		return
+	}
+
+	s.recordAudit(r, core.AuditActionTwoFactorRecoveryCodes, strconv.FormatInt(ctx.UserID, 10))
+
+	response.JSON(w, twoFactorAuthRecoveryCodesResponse{RecoveryCodes: recoveryCodes}, 200)
 }
 
 // DeactivateTwoFactorAuth godoc
@@ -182,6 +259,8 @@ func (s *router) DeactivateTwoFactorAuth(w http.ResponseWriter, r *http.Request)
 		return
 	}
 
+	s.recordAudit(r, core.AuditActionTwoFactorDeactivate, strconv.FormatInt(ctx.UserID, 10))
+
 	w.WriteHeader(204)
 }
 
@@ -214,3 +293,42 @@ func (s *router) GetTwoFactorAuthSettings(w http.ResponseWriter, r *http.Request
 
 	response.JSON(w, settings, 200)
 }
+
+// DeleteUserTwoFactorAuth godoc
+//
+//	@Summary	Disables two factor auth for a user
+//	@Tags		two-factor-auth
+//	@Param		id	path	int	true	"User id"
+//	@Produce	json
+//	@Success	204
+//	@Failure	400	{object}	response.ErrorResponse
+//	@Failure	401	{object}	response.ErrorResponse
+//	@Failure	403	{object}	response.ErrorResponse
+//	@Failure	409	{object}	response.ErrorResponse
+//	@Failure	500	{object}	response.ErrorResponse
+//	@Security	JWT
+//	@Router		/users/{id}/two-factor-auth [delete]
+func (s *router) DeleteUserTwoFactorAuth(w http.ResponseWriter, r *http.Request) {
+	userID, err := request.NumericURLParam(r, "id")
+	if err != nil {
+		response.BadRequestf(w, "invalid value for parameter 'id'")
+		return
+	}
+
+	if err := s.twoFactorService.Disable(r.Context(), userID); err != nil {
+		if err == core.ErrTwoFactorAuthNotEnabled {
+			response.Conflict(w, err)
+			return
+		}
+
+		log.Error().Int64("id", userID).
+			Err(err).Msg("failed to disable two factor auth")
+
+		response.InternalErrorCommon(w)
+		return
+	}
+
+	s.recordAudit(r, core.AuditActionTwoFactorDeactivate, strconv.FormatInt(userID, 10))
+
+	w.WriteHeader(http.StatusNoContent)
+}
@@ -3,19 +3,25 @@ package api
 import (
 	"fmt"
 	"net/http"
+	"strconv"
 
 	"github.com/davidborzek/tvhgo/api/request"
 	"github.com/davidborzek/tvhgo/api/response"
 	"github.com/davidborzek/tvhgo/core"
-	"github.com/davidborzek/tvhgo/services/auth"
 )
 
 type createUser struct {
-	Username    string `json:"username"`
-	Password    string `json:"password"`
-	Email       string `json:"email"`
-	DisplayName string `json:"displayName"`
-	IsAdmin     bool   `json:"isAdmin"`
+	Username    string    `json:"username"`
+	Password    string    `json:"password"`
+	Email       string    `json:"email"`
+	DisplayName string    `json:"displayName"`
+	Role        core.Role `json:"role"`
+	// IsAdmin is only used if no role is set.
+	IsAdmin bool `json:"isAdmin"`
+}
+
+type userUpdateRole struct {
+	Role core.Role `json:"role"`
 }
 
 type userUpdate struct {
@@ -24,9 +30,27 @@ type userUpdate struct {
 	DisplayName *string `json:"displayName"`
 }
 
+type adminUserUpdate struct {
+	Username    *string `json:"username"`
+	Email       *string `json:"email"`
+	DisplayName *string `json:"displayName"`
+	// IsAdmin grants the admin role or replaces it by the default role.
+	IsAdmin *bool `json:"isAdmin"`
+	// Disabled disables the user and revokes all sessions and tokens.
+	Disabled *bool `json:"disabled"`
+}
+
+type adminUserResetPassword struct {
+	Password string `json:"password"`
+	// RevokeSessions revokes all sessions and tokens of the user.
+	RevokeSessions bool `json:"revokeSessions"`
+}
+
 type userUpdatePassword struct {
 	CurrentPassword string `json:"currentPassword"`
 	Password        string `json:"password"`
+	// RevokeSessions revokes all other sessions and all tokens of the user.
+	RevokeSessions bool `json:"revokeSessions"`
 }
 
 // GetCurrentUser godoc
@@ -148,12 +172,12 @@ func (s *router) UpdateUserPassword(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	if err := auth.ComparePassword(in.CurrentPassword, user.PasswordHash); err != nil {
+	if err := s.passwordHasher.Compare(in.CurrentPassword, user.PasswordHash); err != nil {
 		response.BadRequestf(w, "current password is invalid")
 		return
 	}
 
-	hash, err := auth.HashPassword(in.Password)
+	hash, err := s.passwordHasher.Hash(in.Password)
 	if err != nil {
 		response.InternalError(w, err)
 		return
@@ -167,6 +191,20 @@ func (s *router) UpdateUserPassword(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
+	s.recordAudit(r, core.AuditActionPasswordChange, strconv.FormatInt(user.ID, 10))
+
+	if err := s.revokeTrustedDevices(r, user.ID); err != nil {
+		response.InternalErrorCommon(w)
+		return
+	}
+
+	if in.RevokeSessions {
+		if err := s.revokeOtherSessionsAndTokens(r, ctx); err != nil {
+			response.InternalErrorCommon(w)
+			return
+		}
+	}
+
 	response.JSON(w, user, 200)
 }
 
@@ -210,7 +248,16 @@ func (s *router) CreateUser(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	hash, err := auth.HashPassword(in.Password)
+	if in.Role == "" && in.IsAdmin {
+		in.Role = core.RoleAdmin
+	}
+
+	if in.Role != "" && !in.Role.Valid() {
+		response.BadRequest(w, core.ErrRoleInvalid)
+		return
+	}
+
+	hash, err := s.passwordHasher.Hash(in.Password)
 	if err != nil {
 		response.InternalError(w, err)
 		return
@@ -221,7 +268,7 @@ func (s *router) CreateUser(w http.ResponseWriter, r *http.Request) {
 		Email:        in.Email,
 		DisplayName:  in.DisplayName,
 		PasswordHash: hash,
-		IsAdmin:      in.IsAdmin,
+		Role:         in.Role,
 	}
 
 	err = s.users.Create(r.Context(), user)
@@ -235,6 +282,8 @@ func (s *router) CreateUser(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
+	s.recordAudit(r, core.AuditActionUserCreate, strconv.FormatInt(user.ID, 10))
+
 	response.JSON(w, user, 201)
 }
 
@@ -286,6 +335,270 @@ func (s *router) DeleteUser(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
+	s.recordAudit(r, core.AuditActionUserDelete, strconv.FormatInt(user.ID, 10))
+
+	w.WriteHeader(http.StatusNoContent)
+}
+
+// UpdateUserRole godoc
+//
+//	@Summary	Assigns a role to a user
+//	@Tags		user
+//	@Accept		json
+//	@Param		id		path	string			true	"User ID"
+//	@Param		body	body	userUpdateRole	true	"Body"
+//	@Produce	json
+//	@Success	200	{object}	core.User
+//	@Failure	400	{object}	response.ErrorResponse
+//	@Failure	401	{object}	response.ErrorResponse
+//	@Failure	403	{object}	response.ErrorResponse
+//	@Failure	404	{object}	response.ErrorResponse
+//	@Failure	500	{object}	response.ErrorResponse
+//
+//	@Router		/users/{id}/role [put]
+func (s *router) UpdateUserRole(w http.ResponseWriter, r *http.Request) {
+	id, err := request.NumericURLParam(r, "id")
+	if err != nil {
+		response.BadRequest(w, err)
+		return
+	}
+
+	ctx, ok := request.GetAuthContext(r.Context())
+	if !ok {
+		response.InternalErrorCommon(w)
+		return
+	}
+
+	if ctx.UserID == id {
+		response.BadRequest(w, fmt.Errorf("role of current user cannot be changed"))
+		return
+	}
+
+	var in userUpdateRole
+	if err := request.BindJSON(r, &in); err != nil {
+		response.BadRequest(w, err)
+		return
+	}
+
+	if !in.Role.Valid() {
+		response.BadRequest(w, core.ErrRoleInvalid)
+		return
+	}
+
+	user, err := s.users.FindById(r.Context(), id)
+	if err != nil {
+		response.InternalError(w, err)
+		return
+	}
+
+	if user == nil {
+		response.NotFound(w, fmt.Errorf("user not found"))
+		return
+	}
+
+	user.Role = in.Role
+	if err := s.users.Update(r.Context(), user); err != nil {
+		response.InternalError(w, err)
+		return
+	}
+
+	s.recordAudit(r, core.AuditActionUserRoleUpdate, strconv.FormatInt(user.ID, 10))
+
+	response.JSON(w, user, 200)
+}
+
+// UpdateUserByID godoc
+//
+//	@Summary	Updates a user
+//	@Tags		user
+//	@Accept		json
+//	@Param		id		path	string			true	"User ID"
+//	@Param		body	body	adminUserUpdate	true	"Body"
+//	@Produce	json
+//	@Success	200	{object}	core.User
+//	@Failure	400	{object}	response.ErrorResponse
+//	@Failure	401	{object}	response.ErrorResponse
+//	@Failure	403	{object}	response.ErrorResponse
+//	@Failure	404	{object}	response.ErrorResponse
+//	@Failure	500	{object}	response.ErrorResponse
+//
+//	@Router		/users/{id} [patch]
+func (s *router) UpdateUserByID(w http.ResponseWriter, r *http.Request) {
+	id, err := request.NumericURLParam(r, "id")
+	if err != nil {
+		response.BadRequest(w, err)
+		return
+	}
+
+	ctx, ok := request.GetAuthContext(r.Context())
+	if !ok {
+		response.InternalErrorCommon(w)
+		return
+	}
+
+	var in adminUserUpdate
+	if err := request.BindJSON(r, &in); err != nil {
+		response.BadRequest(w, err)
+		return
+	}
+
+	if ctx.UserID == id && (in.IsAdmin != nil || in.Disabled != nil) {
+		response.BadRequestf(w, "current user cannot be demoted or disabled")
+		return
+	}
+
+	user, err := s.users.FindById(r.Context(), id)
+	if err != nil {
+		response.InternalError(w, err)
+		return
+	}
+
+	if user == nil {
+		response.NotFound(w, fmt.Errorf("user not found"))
+		return
+	}
+
+	if in.Username != nil {
+		user.Username = *in.Username
+	}
+
+	if in.DisplayName != nil {
+		user.DisplayName = *in.DisplayName
+	}
+
+	if in.Email != nil {
+		user.Email = *in.Email
+	}
+
+	roleChanged := in.IsAdmin != nil && *in.IsAdmin != user.IsAdmin()
+	if roleChanged {
+		user.Role = core.DefaultRole
+		if *in.IsAdmin {
+			user.Role = core.RoleAdmin
+		}
+	}
+
+	disabledChanged := in.Disabled != nil && *in.Disabled != user.Disabled
+	if disabledChanged {
+		user.Disabled = *in.Disabled
+	}
+
+	err = s.users.Update(r.Context(), user)
+	if err == core.ErrEmailAlreadyExists || err == core.ErrUsernameAlreadyExists {
+		response.BadRequest(w, err)
+		return
+	}
+
+	if err != nil {
+		response.InternalError(w, err)
+		return
+	}
+
+	target := strconv.FormatInt(user.ID, 10)
+	s.recordAudit(r, core.AuditActionUserUpdate, target)
+
+	if roleChanged {
+		s.recordAudit(r, core.AuditActionUserRoleUpdate, target)
+	}
+
+	if disabledChanged && !user.Disabled {
+		s.recordAudit(r, core.AuditActionUserEnable, target)
+	}
+
+	if disabledChanged && user.Disabled {
+		s.recordAudit(r, core.AuditActionUserDisable, target)
+
+		if err := s.revokeSessionsAndTokens(r, user.ID, nil); err != nil {
+			response.InternalErrorCommon(w)
+			return
+		}
+	}
+
+	response.JSON(w, user, 200)
+}
+
+// ResetUserPassword godoc
+//
+//	@Summary	Resets the password of a user
+//	@Tags		user
+//	@Accept		json
+//	@Param		id		path	string					true	"User ID"
+//	@Param		body	body	adminUserResetPassword	true	"Body"
+//	@Produce	json
+//	@Success	204
+//	@Failure	400	{object}	response.ErrorResponse
+//	@Failure	401	{object}	response.ErrorResponse
+//	@Failure	403	{object}	response.ErrorResponse
+//	@Failure	404	{object}	response.ErrorResponse
+//	@Failure	500	{object}	response.ErrorResponse
+//
+//	@Router		/users/{id}/password [put]
+func (s *router) ResetUserPassword(w http.ResponseWriter, r *http.Request) {
+	id, err := request.NumericURLParam(r, "id")
+	if err != nil {
+		response.BadRequest(w, err)
+		return
+	}
+
+	ctx, ok := request.GetAuthContext(r.Context())
+	if !ok {
+		response.InternalErrorCommon(w)
+		return
+	}
+
+	if ctx.UserID == id {
+		response.BadRequestf(w, "password of current user must be changed with the current password")
+		return
+	}
+
+	var in adminUserResetPassword
+	if err := request.BindJSON(r, &in); err != nil {
+		response.BadRequest(w, err)
+		return
+	}
+
+	if in.Password == "" {
+		response.BadRequestf(w, "invalid password")
+		return
+	}
+
+	user, err := s.users.FindById(r.Context(), id)
+	if err != nil {
+		response.InternalError(w, err)
+		return
+	}
+
+	if user == nil {
+		response.NotFound(w, fmt.Errorf("user not found"))
+		return
+	}
+
+	hash, err := s.passwordHasher.Hash(in.Password)
+	if err != nil {
+		response.InternalError(w, err)
+		return
+	}
+
+	user.PasswordHash = hash
+	if err := s.users.Update(r.Context(), user); err != nil {
+		response.InternalError(w, err)
+		return
+	}
+
+	s.recordAudit(r, core.AuditActionPasswordReset, strconv.FormatInt(user.ID, 10))
+
+	if err := s.revokeTrustedDevices(r, user.ID); err != nil {
+		response.InternalErrorCommon(w)
+		return
+	}
+
+	if in.RevokeSessions {
+		if err := s.revokeSessionsAndTokens(r, user.ID, nil); err != nil {
+			response.InternalErrorCommon(w)
+			return
+		}
+	}
+
 	w.WriteHeader(http.StatusNoContent)
 }
 
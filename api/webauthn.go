@@ -0,0 +1,373 @@
+package api
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+	"strconv"
+
+	"github.com/davidborzek/tvhgo/api/request"
+	"github.com/davidborzek/tvhgo/api/response"
+	"github.com/davidborzek/tvhgo/core"
+	"github.com/rs/zerolog/log"
+)
+
+type webAuthnAssertion struct {
+	ID         string          `json:"id"`
+	Credential json.RawMessage `json:"credential" swaggertype:"object"`
+}
+
+type webAuthnRegistrationBeginRequest struct {
+	Password string `json:"password"`
+}
+
+type webAuthnRegistrationFinishRequest struct {
+	ID         string          `json:"id"`
+	Name       string          `json:"name"`
+	Credential json.RawMessage `json:"credential" swaggertype:"object"`
+}
+
+type webAuthnCredentialUpdateRequest struct {
+	Name string `json:"name"`
+}
+
+// BeginWebAuthnLogin godoc
+//
+//	@Summary	Starts a login with a passkey
+//	@Tags		webauthn
+//	@Produce	json
+//	@Success	200	{object}	core.WebAuthnCeremony
+//	@Failure	500	{object}	response.ErrorResponse
+//	@Router		/auth/webauthn/login/begin [post]
+func (s *router) BeginWebAuthnLogin(w http.ResponseWriter, r *http.Request) {
+	ceremony, err := s.webAuthn.BeginLogin(r.Context())
+	if err != nil {
+		log.Error().Err(err).Msg("[webauthn] failed to begin login")
+
+		response.InternalErrorCommon(w)
+		return
+	}
+
+	response.JSON(w, ceremony, 200)
+}
+
+// FinishWebAuthnLogin godoc
+//
+//	@Summary	Finishes a passwordless login with a passkey
+//	@Tags		webauthn
+//	@Param		body	body	webAuthnAssertion	true	"Body"
+//	@Produce	json
+//	@Success	200	{object}	loginResponse
+//	@Failure	400	{object}	response.ErrorResponse
+//	@Failure	401	{object}	response.ErrorResponse
+//	@Failure	500	{object}	response.ErrorResponse
+//	@Router		/auth/webauthn/login/finish [post]
+func (s *router) FinishWebAuthnLogin(w http.ResponseWriter, r *http.Request) {
+	var in webAuthnAssertion
+	if err := request.BindJSON(r, &in); err != nil {
+		response.BadRequest(w, err)
+		return
+	}
+
+	addr := request.RemoteAddr(r)
+
+	user, ok := s.verifyWebAuthnAssertion(w, r, &in)
+	if !ok {
+		return
+	}
+
+	token, err := s.sessionManager.Create(
+		r.Context(),
+		user.ID,
+		addr,
+		r.UserAgent(),
+	)
+	if err != nil {
+		response.InternalError(w, err)
+		return
+	}
+
+	s.recordLoginAudit(r, user.ID)
+
+	setSessionCookie(
+		w,
+		s.cfg.Auth.Session.CookieName,
+		token,
+		s.cfg.Auth.Session.CookieSecure,
+	)
+	response.JSON(w, loginResponse{Token: token}, 200)
+}
+
+// verifyWebAuthnAssertion finishes a login ceremony and writes
+// an error response if the assertion is invalid.
+func (s *router) verifyWebAuthnAssertion(
+	w http.ResponseWriter,
+	r *http.Request,
+	in *webAuthnAssertion,
+) (*core.User, bool) {
+	user, err := s.webAuthn.FinishLogin(r.Context(), in.ID, in.Credential)
+	if err != nil {
+		if errors.Is(err, core.ErrWebAuthnCeremonyInvalid) ||
+			errors.Is(err, core.ErrWebAuthnVerificationFailed) ||
+			errors.Is(err, core.ErrUserDisabled) {
+			log.Error().Str("ip", request.RemoteAddr(r)).
+				Err(err).Msg("[webauthn] login failed")
+
+			s.recordLoginFailureAudit(r, "", err)
+			response.Unauthorized(w, err)
+			return nil, false
+		}
+
+		log.Error().Err(err).Msg("[webauthn] failed to finish login")
+
+		response.InternalErrorCommon(w)
+		return nil, false
+	}
+
+	return user, true
+}
+
+// GetWebAuthnCredentials godoc
+//
+//	@Summary	Get list of passkeys for the current user
+//	@Tags		webauthn
+//
+//	@Produce	json
+//	@Success	200	{array}		core.WebAuthnCredential
+//	@Failure	401	{object}	response.ErrorResponse
+//	@Failure	500	{object}	response.ErrorResponse
+//	@Security	JWT
+//	@Router		/webauthn/credentials [get]
+func (s *router) GetWebAuthnCredentials(w http.ResponseWriter, r *http.Request) {
+	ctx, ok := request.GetAuthContext(r.Context())
+	if !ok {
+		response.InternalErrorCommon(w)
+		return
+	}
+
+	credentials, err := s.webAuthn.GetCredentials(r.Context(), ctx.UserID)
+	if err != nil {
+		log.Error().Int64("id", ctx.UserID).
+			Err(err).Msg("failed to get webauthn credentials")
+
+		response.InternalErrorCommon(w)
+		return
+	}
+
+	response.JSON(w, credentials, 200)
+}
+
+// BeginWebAuthnRegistration godoc
+//
+//	@Summary	Starts the registration of a passkey for the current user
+//	@Tags		webauthn
+//	@Param		body	body	webAuthnRegistrationBeginRequest	true	"Body"
+//	@Produce	json
+//	@Success	200	{object}	core.WebAuthnCeremony
+//	@Failure	400	{object}	response.ErrorResponse
+//	@Failure	401	{object}	response.ErrorResponse
+//	@Failure	403	{object}	response.ErrorResponse
+//	@Failure	500	{object}	response.ErrorResponse
+//	@Security	JWT
+//	@Router		/webauthn/registration/begin [post]
+func (s *router) BeginWebAuthnRegistration(w http.ResponseWriter, r *http.Request) {
+	ctx, ok := request.GetAuthContext(r.Context())
+	if !ok {
+		response.InternalErrorCommon(w)
+		return
+	}
+
+	if ctx.SessionID == nil {
+		response.Forbiddenf(w, "a passkey can only be registered via a web session")
+		return
+	}
+
+	var in webAuthnRegistrationBeginRequest
+	if err := request.BindJSON(r, &in); err != nil {
+		response.BadRequest(w, err)
+		return
+	}
+
+	if err := s.passwordAuthenticator.ConfirmPassword(r.Context(), ctx.UserID, in.Password); err != nil {
+		if err == core.ErrConfirmationPasswordInvalid {
+			response.BadRequest(w, err)
+		} else {
+			response.InternalErrorCommon(w)
+		}
+
+		return
+	}
+
+	ceremony, err := s.webAuthn.BeginRegistration(r.Context(), ctx.UserID)
+	if err != nil {
+		log.Error().Int64("id", ctx.UserID).
+			Err(err).Msg("failed to begin webauthn registration")
+
+		response.InternalErrorCommon(w)
+		return
+	}
+
+	response.JSON(w, ceremony, 200)
+}
+
+// FinishWebAuthnRegistration godoc
+//
+//	@Summary	Finishes the registration of a passkey for the current user
+//	@Tags		webauthn
+//	@Param		body	body	webAuthnRegistrationFinishRequest	true	"Body"
+//	@Produce	json
+//	@Success	200	{object}	core.WebAuthnCredential
+//	@Failure	400	{object}	response.ErrorResponse
+//	@Failure	401	{object}	response.ErrorResponse
+//	@Failure	403	{object}	response.ErrorResponse
+//	@Failure	500	{object}	response.ErrorResponse
+//	@Security	JWT
+//	@Router		/webauthn/registration/finish [post]
+func (s *router) FinishWebAuthnRegistration(w http.ResponseWriter, r *http.Request) {
+	ctx, ok := request.GetAuthContext(r.Context())
+	if !ok {
+		response.InternalErrorCommon(w)
+		return
+	}
+
+	if ctx.SessionID == nil {
+		response.Forbiddenf(w, "a passkey can only be registered via a web session")
+		return
+	}
+
+	var in webAuthnRegistrationFinishRequest
+	if err := request.BindJSON(r, &in); err != nil {
+		response.BadRequest(w, err)
+		return
+	}
+
+	credential, err := s.webAuthn.FinishRegistration(
+		r.Context(),
+		ctx.UserID,
+		in.ID,
+		in.Name,
+		in.Credential,
+	)
+	if err != nil {
+		if errors.Is(err, core.ErrWebAuthnCeremonyInvalid) ||
+			errors.Is(err, core.ErrWebAuthnVerificationFailed) {
+			response.BadRequest(w, err)
+			return
+		}
+
+		log.Error().Int64("id", ctx.UserID).
+			Err(err).Msg("failed to finish webauthn registration")
+
+		response.InternalErrorCommon(w)
+		return
+	}
+
+	s.recordAudit(r, core.AuditActionPasskeyRegister, strconv.FormatInt(credential.ID, 10))
+
+	response.JSON(w, credential, 200)
+}
+
+// UpdateWebAuthnCredential godoc
+//
+//	@Summary	Renames a passkey of the current user
+//	@Tags		webauthn
+//	@Param		id		path	int								true	"Credential ID"
+//	@Param		body	body	webAuthnCredentialUpdateRequest	true	"Body"
+//	@Produce	json
+//	@Success	200	{object}	core.WebAuthnCredential
+//	@Failure	400	{object}	response.ErrorResponse
+//	@Failure	401	{object}	response.ErrorResponse
+//	@Failure	404	{object}	response.ErrorResponse
+//	@Failure	500	{object}	response.ErrorResponse
+//	@Security	JWT
+//	@Router		/webauthn/credentials/{id} [patch]
+func (s *router) UpdateWebAuthnCredential(w http.ResponseWriter, r *http.Request) {
+	ctx, ok := request.GetAuthContext(r.Context())
+	if !ok {
+		response.InternalErrorCommon(w)
+		return
+	}
+
+	id, err := request.NumericURLParam(r, "id")
+	if err != nil {
+		response.BadRequestf(w, "invalid value for parameter 'id'")
+		return
+	}
+
+	var in webAuthnCredentialUpdateRequest
+	if err := request.BindJSON(r, &in); err != nil {
+		response.BadRequest(w, err)
+		return
+	}
+
+	if in.Name == "" {
+		response.BadRequestf(w, "invalid name")
+		return
+	}
+
+	credential, err := s.webAuthn.RenameCredential(r.Context(), ctx.UserID, id, in.Name)
+	if err != nil {
+		if errors.Is(err, core.ErrWebAuthnCredentialNotFound) {
+			response.NotFound(w, err)
+			return
+		}
+
+		log.Error().Int64("id", id).
+			Err(err).Msg("failed to rename webauthn credential")
+
+		response.InternalErrorCommon(w)
+		return
+	}
+
+	response.JSON(w, credential, 200)
+}
+
+// DeleteWebAuthnCredential godoc
+//
+//	@Summary	Deletes a passkey of the current user
+//	@Tags		webauthn
+//	@Param		id	path	int	true	"Credential ID"
+//	@Produce	json
+//	@Success	204
+//	@Failure	400	{object}	response.ErrorResponse
+//	@Failure	401	{object}	response.ErrorResponse
+//	@Failure	403	{object}	response.ErrorResponse
+//	@Failure	404	{object}	response.ErrorResponse
+//	@Failure	500	{object}	response.ErrorResponse
+//	@Security	JWT
+//	@Router		/webauthn/credentials/{id} [delete]
+func (s *router) DeleteWebAuthnCredential(w http.ResponseWriter, r *http.Request) {
+	ctx, ok := request.GetAuthContext(r.Context())
+	if !ok {
+		response.InternalErrorCommon(w)
+		return
+	}
+
+	if ctx.SessionID == nil {
+		response.Forbiddenf(w, "a passkey can only be deleted via a web session")
+		return
+	}
+
+	id, err := request.NumericURLParam(r, "id")
+	if err != nil {
+		response.BadRequestf(w, "invalid value for parameter 'id'")
+		return
+	}
+
+	if err := s.webAuthn.DeleteCredential(r.Context(), ctx.UserID, id); err != nil {
+		if errors.Is(err, core.ErrWebAuthnCredentialNotFound) {
+			response.NotFound(w, err)
+			return
+		}
+
+		log.Error().Int64("id", id).
+			Err(err).Msg("failed to delete webauthn credential")
+
+		response.InternalErrorCommon(w)
+		return
+	}
+
+	s.recordAudit(r, core.AuditActionPasskeyDelete, strconv.FormatInt(id, 10))
+
+	w.WriteHeader(http.StatusNoContent)
+}
@@ -0,0 +1,135 @@
+package api_test
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+
+	"github.com/davidborzek/tvhgo/api"
+	"github.com/davidborzek/tvhgo/config"
+	"github.com/davidborzek/tvhgo/core"
+	mock_core "github.com/davidborzek/tvhgo/mock/core"
+	"go.uber.org/mock/gomock"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("WebAuthn login", func() {
+	var mockCtrl *gomock.Controller
+	var mockSessionManager *mock_core.MockSessionManager
+	var mockPasswordAuthenticator *mock_core.MockPasswordAuthenticator
+	var mockWebAuthn *mock_core.MockWebAuthnService
+	var mockLoginThrottle *mock_core.MockLoginThrottleService
+	var mockAudit *mock_core.MockAuditService
+
+	cfg := &config.Config{
+		Auth: config.AuthConfig{
+			Session: config.SessionConfig{
+				CookieName: "tvhgo_session",
+			},
+			WebAuthn: config.WebAuthnConfig{
+				Enabled: true,
+			},
+		},
+	}
+
+	newRouter := func() http.Handler {
+		return api.New(cfg, nil, nil, nil, nil, nil, mockSessionManager, nil, nil, mockPasswordAuthenticator,
+			nil, nil, nil, nil, nil, nil, nil, nil, nil, nil, mockWebAuthn, nil, mockLoginThrottle, mockAudit, nil, nil, nil, nil).Handler()
+	}
+
+	newRequest := func(path string, body string) *http.Request {
+		req, err := http.NewRequest("POST", path, strings.NewReader(body))
+		if err != nil {
+			Fail(err.Error())
+		}
+		return req
+	}
+
+	BeforeEach(func() {
+		mockCtrl = gomock.NewController(GinkgoT())
+		mockSessionManager = mock_core.NewMockSessionManager(mockCtrl)
+		mockPasswordAuthenticator = mock_core.NewMockPasswordAuthenticator(mockCtrl)
+		mockWebAuthn = mock_core.NewMockWebAuthnService(mockCtrl)
+		mockLoginThrottle = mock_core.NewMockLoginThrottleService(mockCtrl)
+		mockAudit = mock_core.NewMockAuditService(mockCtrl)
+
+		mockLoginThrottle.EXPECT().
+			Check(gomock.Any(), gomock.Any(), gomock.Any()).
+			Return(nil).
+			AnyTimes()
+		mockLoginThrottle.EXPECT().
+			RecordSuccess(gomock.Any(), gomock.Any()).
+			Return(nil).
+			AnyTimes()
+		mockAudit.EXPECT().
+			Record(gomock.Any(), gomock.Any()).
+			AnyTimes()
+	})
+
+	AfterEach(func() {
+		mockCtrl.Finish()
+	})
+
+	It("creates a session for a passwordless login", func() {
+		mockWebAuthn.EXPECT().
+			FinishLogin(gomock.Any(), "someCeremony", gomock.Any()).
+			Return(&core.User{ID: 1}, nil)
+
+		mockSessionManager.EXPECT().
+			Create(gomock.Any(), int64(1), gomock.Any(), gomock.Any()).
+			Return("someToken", nil)
+
+		rr := httptest.NewRecorder()
+		newRouter().ServeHTTP(rr, newRequest(
+			"/auth/webauthn/login/finish",
+			`{"id":"someCeremony","credential":{"id":"someCredential"}}`,
+		))
+
+		Expect(rr.Code).To(Equal(http.StatusOK))
+		Expect(rr.Body.String()).To(MatchJSON(`{"token":"someToken"}`))
+		Expect(rr.Header().Get("Set-Cookie")).To(ContainSubstring("tvhgo_session=someToken"))
+	})
+
+	It("returns status unauthorized for an invalid assertion", func() {
+		mockWebAuthn.EXPECT().
+			FinishLogin(gomock.Any(), "someCeremony", gomock.Any()).
+			Return(nil, core.ErrWebAuthnVerificationFailed)
+
+		rr := httptest.NewRecorder()
+		newRouter().ServeHTTP(rr, newRequest(
+			"/auth/webauthn/login/finish",
+			`{"id":"someCeremony","credential":{}}`,
+		))
+
+		Expect(rr.Code).To(Equal(http.StatusUnauthorized))
+		Expect(rr.Body.String()).To(MatchJSON(`{"message":"webauthn verification failed"}`))
+	})
+
+	It("accepts a passkey as second factor of a password login", func() {
+		mockWebAuthn.EXPECT().
+			FinishLogin(gomock.Any(), "someCeremony", gomock.Any()).
+			Return(&core.User{ID: 1}, nil)
+
+		mockPasswordAuthenticator.EXPECT().
+			Login(gomock.Any(), "someUser", "somePassword", nil).
+			DoAndReturn(func(ctx context.Context, login string, password string, totp *string) (*core.User, error) {
+				Expect(core.IsTwoFactorVerified(ctx, 1)).To(BeTrue())
+				return &core.User{ID: 1}, nil
+			})
+
+		mockSessionManager.EXPECT().
+			Create(gomock.Any(), int64(1), gomock.Any(), gomock.Any()).
+			Return("someToken", nil)
+
+		rr := httptest.NewRecorder()
+		newRouter().ServeHTTP(rr, newRequest(
+			"/login",
+			`{"username":"someUser","password":"somePassword","webauthn":{"id":"someCeremony","credential":{}}}`,
+		))
+
+		Expect(rr.Code).To(Equal(http.StatusOK))
+	})
+})
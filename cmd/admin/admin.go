@@ -1,6 +1,9 @@
 package admin
 
 import (
+	"github.com/davidborzek/tvhgo/cmd/admin/audit"
+	"github.com/davidborzek/tvhgo/cmd/admin/encryption"
+	"github.com/davidborzek/tvhgo/cmd/admin/lockout"
 	"github.com/davidborzek/tvhgo/cmd/admin/user"
 	"github.com/rs/zerolog"
 	"github.com/urfave/cli/v2"
@@ -12,6 +15,9 @@ var (
 		Usage: "Admin controls for the tvhgo server",
 		Subcommands: []*cli.Command{
 			user.Cmd,
+			lockout.Cmd,
+			audit.Cmd,
+			encryption.Cmd,
 		},
 		Before: before,
 	}
@@ -0,0 +1,11 @@
+package audit
+
+import "github.com/urfave/cli/v2"
+
+var Cmd = &cli.Command{
+	Name:  "audit",
+	Usage: "Inspect the security audit log",
+	Subcommands: []*cli.Command{
+		listCmd,
+	},
+}
@@ -0,0 +1,101 @@
+package audit
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/davidborzek/tvhgo/cmd/common"
+	"github.com/davidborzek/tvhgo/core"
+	auditlog "github.com/davidborzek/tvhgo/repository/audit_log"
+	"github.com/davidborzek/tvhgo/repository/user"
+	"github.com/davidborzek/tvhgo/services/audit"
+	"github.com/davidborzek/tvhgo/services/clock"
+	"github.com/urfave/cli/v2"
+)
+
+var listCmd = &cli.Command{
+	Name:  "list",
+	Usage: "List entries of the audit log, newest first.",
+	Flags: []cli.Flag{
+		&cli.StringFlag{
+			Name:    "username",
+			Aliases: []string{"u"},
+			Usage:   "Only list entries of a user",
+		},
+		&cli.StringFlag{
+			Name:    "action",
+			Aliases: []string{"a"},
+			Usage:   "Only list entries of an action (e.g. login_failed)",
+		},
+		&cli.DurationFlag{
+			Name:    "since",
+			Aliases: []string{"s"},
+			Usage:   "Only list entries created within a duration (e.g. 24h)",
+		},
+		&cli.Int64Flag{
+			Name:    "limit",
+			Aliases: []string{"l"},
+			Usage:   "Maximum number of entries",
+			Value:   50,
+		},
+		&cli.Int64Flag{
+			Name:    "offset",
+			Aliases: []string{"o"},
+			Usage:   "Number of entries to skip",
+		},
+	},
+	Action: list,
+}
+
+func list(ctx *cli.Context) error {
+	_, db := common.Init(ctx)
+
+	clock := clock.NewClock()
+	auditService := audit.New(
+		auditlog.New(db, clock),
+		user.New(db, clock),
+	)
+
+	params := core.AuditQueryParams{
+		PaginationQueryParams: core.PaginationQueryParams{
+			Limit:  ctx.Int64("limit"),
+			Offset: ctx.Int64("offset"),
+		},
+		Username: ctx.String("username"),
+		Action:   core.AuditAction(ctx.String("action")),
+	}
+
+	if since := ctx.Duration("since"); since > 0 {
+		params.From = clock.Now().Add(-since).Unix()
+	}
+
+	result, err := auditService.Find(ctx.Context, params)
+	if err != nil {
+		return err
+	}
+
+	if len(result.Entries) == 0 {
+		fmt.Printf("No audit log entries found.")
+		return nil
+	}
+
+	common.PrintTable(
+		[]string{"ID", "Time", "User", "Action", "Target", "Reason", "IP", "User agent"},
+		common.MapRows(result.Entries, func(entry *core.AuditEntry) []any {
+			return []any{
+				entry.ID,
+				time.Unix(entry.CreatedAt, 0).Format(time.RFC822),
+				entry.Username,
+				entry.Action,
+				entry.Target,
+				entry.Reason,
+				entry.ClientIP,
+				entry.UserAgent,
+			}
+		}),
+	)
+
+	fmt.Printf("Showing %d of %d entries.\n", len(result.Entries), result.Total)
+
+	return nil
+}
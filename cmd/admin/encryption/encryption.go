@@ -0,0 +1,11 @@
+package encryption
+
+import "github.com/urfave/cli/v2"
+
+var Cmd = &cli.Command{
+	Name:  "encryption",
+	Usage: "Manage the encryption of sensitive database columns",
+	Subcommands: []*cli.Command{
+		migrateCmd,
+	},
+}
@@ -0,0 +1,41 @@
+package encryption
+
+import (
+	"fmt"
+
+	"github.com/davidborzek/tvhgo/cmd/common"
+	"github.com/davidborzek/tvhgo/core"
+	twofactorsettings "github.com/davidborzek/tvhgo/repository/two_factor_settings"
+	"github.com/davidborzek/tvhgo/services/encryption"
+	"github.com/urfave/cli/v2"
+)
+
+var migrateCmd = &cli.Command{
+	Name: "migrate",
+	Usage: "Encrypts unencrypted values and values encrypted with an old key " +
+		"with the current key. Run it after configuring or rotating keys.",
+	Action: migrate,
+}
+
+func migrate(ctx *cli.Context) error {
+	cfg, db := common.Init(ctx)
+
+	encryptor, err := encryption.New(&cfg.Database.Encryption)
+	if err != nil {
+		return err
+	}
+
+	if !encryptor.Enabled() {
+		return core.ErrEncryptionNotConfigured
+	}
+
+	count, err := twofactorsettings.New(db, encryptor).
+		Reencrypt(ctx.Context)
+	if err != nil {
+		return err
+	}
+
+	fmt.Printf("Successfully encrypted %d totp secrets.\n", count)
+
+	return nil
+}
@@ -0,0 +1,42 @@
+package lockout
+
+import (
+	"fmt"
+
+	"github.com/davidborzek/tvhgo/cmd/common"
+	loginthrottle "github.com/davidborzek/tvhgo/repository/login_throttle"
+	"github.com/davidborzek/tvhgo/services/auth"
+	"github.com/davidborzek/tvhgo/services/clock"
+	"github.com/urfave/cli/v2"
+)
+
+var clearCmd = &cli.Command{
+	Name:  "clear",
+	Usage: "Clears the lockout of a client ip or username.",
+	Flags: []cli.Flag{
+		&cli.Int64Flag{
+			Name:     "id",
+			Usage:    "ID of the lockout",
+			Required: true,
+		},
+	},
+	Action: clearLockout,
+}
+
+func clearLockout(ctx *cli.Context) error {
+	cfg, db := common.Init(ctx)
+
+	loginThrottleService := auth.NewLoginThrottleService(
+		loginthrottle.New(db),
+		clock.NewClock(),
+		&cfg.Auth.LoginThrottle,
+	)
+
+	if err := loginThrottleService.Clear(ctx.Context, ctx.Int64("id")); err != nil {
+		return err
+	}
+
+	fmt.Println("Lockout successfully cleared.")
+
+	return nil
+}
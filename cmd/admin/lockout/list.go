@@ -0,0 +1,56 @@
+package lockout
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/davidborzek/tvhgo/cmd/common"
+	"github.com/davidborzek/tvhgo/core"
+	loginthrottle "github.com/davidborzek/tvhgo/repository/login_throttle"
+	"github.com/davidborzek/tvhgo/services/auth"
+	"github.com/davidborzek/tvhgo/services/clock"
+	"github.com/urfave/cli/v2"
+)
+
+var listCmd = &cli.Command{
+	Name:   "list",
+	Usage:  "List blocked client ips and usernames.",
+	Action: list,
+}
+
+func list(ctx *cli.Context) error {
+	cfg, db := common.Init(ctx)
+
+	loginThrottleService := auth.NewLoginThrottleService(
+		loginthrottle.New(db),
+		clock.NewClock(),
+		&cfg.Auth.LoginThrottle,
+	)
+
+	lockouts, err := loginThrottleService.List(ctx.Context)
+	if err != nil {
+		return err
+	}
+
+	if len(lockouts) == 0 {
+		fmt.Printf("No lockouts found.")
+		return nil
+	}
+
+	common.PrintTable(
+		[]string{"ID", "Kind", "Key", "Failed attempts", "Locked", "Last failed", "Blocked until"},
+		common.MapRows(lockouts, func(lockout *core.LoginThrottle) []any {
+			return []any{
+				lockout.ID,
+				lockout.Kind,
+				lockout.Key,
+				lockout.FailedAttempts,
+				lockout.Locked,
+				time.Unix(lockout.LastFailedAt, 0).Format(time.RFC822),
+				time.Unix(lockout.BlockedUntil, 0).Format(time.RFC822),
+			}
+		}),
+	)
+
+	return nil
+}
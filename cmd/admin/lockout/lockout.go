@@ -0,0 +1,12 @@
+package lockout
+
+import "github.com/urfave/cli/v2"
+
+var Cmd = &cli.Command{
+	Name:  "lockout",
+	Usage: "Manage client ips and usernames blocked after failed logins",
+	Subcommands: []*cli.Command{
+		listCmd,
+		clearCmd,
+	},
+}
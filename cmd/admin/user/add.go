@@ -39,6 +39,12 @@ var addCmd = &cli.Command{
 			Aliases: []string{"n"},
 			Usage:   "Display name of the new user",
 		},
+		&cli.StringFlag{
+			Name:    "role",
+			Aliases: []string{"r"},
+			Usage:   fmt.Sprintf("Role of the new user (%s)", roleNames()),
+			Value:   string(core.DefaultRole),
+		},
 	},
 	Action: add,
 }
@@ -69,6 +75,11 @@ var qs = []*survey.Question{
 }
 
 func add(ctx *cli.Context) error {
+	role, err := core.ParseRole(ctx.String("role"))
+	if err != nil {
+		return err
+	}
+
 	if ctx.IsSet("username") &&
 		ctx.IsSet("password") &&
 		ctx.IsSet("email") &&
@@ -80,6 +91,7 @@ func add(ctx *cli.Context) error {
 			ctx.String("password"),
 			ctx.String("email"),
 			ctx.String("display-name"),
+			role,
 		)
 	}
 
@@ -90,7 +102,7 @@ func add(ctx *cli.Context) error {
 		DisplayName string
 	}{}
 
-	err := survey.Ask(qs, &answers, survey.WithIcons(func(is *survey.IconSet) {
+	err = survey.Ask(qs, &answers, survey.WithIcons(func(is *survey.IconSet) {
 		is.Question.Text = ""
 	}))
 
@@ -104,14 +116,19 @@ func add(ctx *cli.Context) error {
 		answers.Password,
 		answers.Email,
 		answers.DisplayName,
+		role,
 	)
 }
 
-func createUser(ctx *cli.Context, username, password, email, displayName string) error {
-	_, db := common.Init(ctx)
+func createUser(
+	ctx *cli.Context,
+	username, password, email, displayName string,
+	role core.Role,
+) error {
+	cfg, db := common.Init(ctx)
 	userRepository := user.New(db, clock.NewClock())
 
-	hash, err := auth.HashPassword(password)
+	hash, err := auth.NewPasswordHasher(&cfg.Auth.Password).Hash(password)
 	if err != nil {
 		return err
 	}
@@ -121,6 +138,7 @@ func createUser(ctx *cli.Context, username, password, email, displayName string)
 		Email:        email,
 		DisplayName:  displayName,
 		PasswordHash: string(hash),
+		Role:         role,
 	})
 	if err != nil {
 		return err
@@ -0,0 +1,104 @@
+package user
+
+import (
+	"errors"
+	"fmt"
+
+	"github.com/davidborzek/tvhgo/cmd/common"
+	"github.com/davidborzek/tvhgo/core"
+	"github.com/davidborzek/tvhgo/repository/session"
+	"github.com/davidborzek/tvhgo/repository/token"
+	"github.com/davidborzek/tvhgo/repository/user"
+	"github.com/davidborzek/tvhgo/services/auth"
+	"github.com/davidborzek/tvhgo/services/clock"
+	"github.com/urfave/cli/v2"
+)
+
+var usernameFlag = &cli.StringFlag{
+	Name:     "username",
+	Aliases:  []string{"u"},
+	Usage:    "Username of the user",
+	Required: true,
+}
+
+var disableCmd = &cli.Command{
+	Name:   "disable",
+	Usage:  "Disable a user and revoke all sessions and tokens",
+	Flags:  []cli.Flag{usernameFlag},
+	Action: disable,
+}
+
+var enableCmd = &cli.Command{
+	Name:   "enable",
+	Usage:  "Enable a disabled user",
+	Flags:  []cli.Flag{usernameFlag},
+	Action: enable,
+}
+
+func disable(ctx *cli.Context) error {
+	cfg, db := common.Init(ctx)
+	clock := clock.NewClock()
+
+	user, err := setDisabled(ctx, user.New(db, clock), true)
+	if err != nil {
+		return err
+	}
+
+	fmt.Println("User successfully disabled.")
+
+	sessionManager := auth.NewSessionManager(
+		session.New(db, clock),
+		clock,
+		cfg.Auth.Session.MaximumInactiveLifetime,
+		cfg.Auth.Session.MaximumLifetime,
+		cfg.Auth.Session.TokenRotationInterval,
+	)
+
+	revoked, err := sessionManager.RevokeAll(ctx.Context, user.ID, nil)
+	if err != nil {
+		return err
+	}
+
+	fmt.Printf("%d sessions successfully revoked.\n", revoked)
+
+	tokenService := auth.NewTokenService(token.New(db, clock), clock)
+
+	revoked, err = tokenService.RevokeAll(ctx.Context, user.ID)
+	if err != nil {
+		return err
+	}
+
+	fmt.Printf("%d tokens successfully revoked.\n", revoked)
+
+	return nil
+}
+
+func enable(ctx *cli.Context) error {
+	_, db := common.Init(ctx)
+
+	if _, err := setDisabled(ctx, user.New(db, clock.NewClock()), false); err != nil {
+		return err
+	}
+
+	fmt.Println("User successfully enabled.")
+	return nil
+}
+
+// setDisabled sets the disabled state of the user given by the username flag.
+func setDisabled(ctx *cli.Context, userRepository core.UserRepository, disabled bool) (*core.User, error) {
+	user, err := userRepository.FindByUsername(ctx.Context, ctx.String("username"))
+	if err != nil {
+		return nil, err
+	}
+
+	if user == nil {
+		return nil, errors.New("user not found")
+	}
+
+	user.Disabled = disabled
+	if err := userRepository.Update(ctx.Context, user); err != nil {
+		return nil, err
+	}
+
+	return user, nil
+}
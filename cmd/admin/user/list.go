@@ -33,13 +33,15 @@ func list(ctx *cli.Context) error {
 	}
 
 	common.PrintTable(
-		[]string{"ID", "Username", "Email", "Name", "Created", "Updated"},
+		[]string{"ID", "Username", "Email", "Name", "Role", "Disabled", "Created", "Updated"},
 		common.MapRows(users.Entries, func(user *core.User) []any {
 			return []any{
 				user.ID,
 				user.Username,
 				user.Email,
 				user.DisplayName,
+				user.Role,
+				user.Disabled,
 				time.Unix(user.CreatedAt, 0).Format(time.RFC822),
 				time.Unix(user.UpdatedAt, 0).Format(time.RFC822),
 			}
@@ -0,0 +1,62 @@
+package user
+
+import (
+	"fmt"
+
+	"github.com/davidborzek/tvhgo/cmd/common"
+	"github.com/davidborzek/tvhgo/core"
+	"github.com/davidborzek/tvhgo/repository/user"
+	"github.com/davidborzek/tvhgo/services/auth"
+	"github.com/davidborzek/tvhgo/services/clock"
+	"github.com/urfave/cli/v2"
+)
+
+var passwordCheckCmd = &cli.Command{
+	Name: "password-check",
+	Usage: "Reports users with password hashes of an older algorithm or weaker parameters. " +
+		"These hashes are rehashed on the next login of the user.",
+	Flags: []cli.Flag{
+		&cli.BoolFlag{
+			Name:    "list",
+			Aliases: []string{"l"},
+			Usage:   "List the users with legacy password hashes",
+		},
+	},
+	Action: passwordCheck,
+}
+
+func passwordCheck(ctx *cli.Context) error {
+	cfg, db := common.Init(ctx)
+
+	userRepository := user.New(db, clock.NewClock())
+	hasher := auth.NewPasswordHasher(&cfg.Auth.Password)
+
+	users, err := userRepository.Find(ctx.Context, core.UserQueryParams{})
+	if err != nil {
+		return err
+	}
+
+	var legacy []*core.User
+	for _, user := range users.Entries {
+		// Users of external identity providers have no local password.
+		if user.PasswordHash != "" && hasher.NeedsRehash(user.PasswordHash) {
+			legacy = append(legacy, user)
+		}
+	}
+
+	fmt.Printf("%d of %d users have legacy password hashes.\n", len(legacy), len(users.Entries))
+
+	if ctx.Bool("list") && len(legacy) > 0 {
+		common.PrintTable(
+			[]string{"ID", "Username"},
+			common.MapRows(legacy, func(user *core.User) []any {
+				return []any{
+					user.ID,
+					user.Username,
+				}
+			}),
+		)
+	}
+
+	return nil
+}
@@ -0,0 +1,70 @@
+package user
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+
+	"github.com/davidborzek/tvhgo/cmd/common"
+	"github.com/davidborzek/tvhgo/core"
+	"github.com/davidborzek/tvhgo/repository/user"
+	"github.com/davidborzek/tvhgo/services/clock"
+	"github.com/urfave/cli/v2"
+)
+
+var roleCmd = &cli.Command{
+	Name:  "role",
+	Usage: "Assign a role to a user",
+	Flags: []cli.Flag{
+		&cli.StringFlag{
+			Name:     "username",
+			Aliases:  []string{"u"},
+			Usage:    "Username of the user",
+			Required: true,
+		},
+		&cli.StringFlag{
+			Name:     "role",
+			Aliases:  []string{"r"},
+			Usage:    fmt.Sprintf("Role of the user (%s)", roleNames()),
+			Required: true,
+		},
+	},
+	Action: role,
+}
+
+func role(ctx *cli.Context) error {
+	role, err := core.ParseRole(ctx.String("role"))
+	if err != nil {
+		return err
+	}
+
+	_, db := common.Init(ctx)
+	userRepository := user.New(db, clock.NewClock())
+
+	user, err := userRepository.FindByUsername(ctx.Context, ctx.String("username"))
+	if err != nil {
+		return err
+	}
+
+	if user == nil {
+		return errors.New("user not found")
+	}
+
+	user.Role = role
+	if err := userRepository.Update(ctx.Context, user); err != nil {
+		return err
+	}
+
+	fmt.Println("Role successfully assigned.")
+	return nil
+}
+
+// roleNames returns the names of all roles separated by a comma.
+func roleNames() string {
+	names := make([]string, 0, len(core.Roles))
+	for _, role := range core.Roles {
+		names = append(names, string(role))
+	}
+
+	return strings.Join(names, ", ")
+}
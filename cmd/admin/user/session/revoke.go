@@ -0,0 +1,77 @@
+package session
+
+import (
+	"errors"
+	"fmt"
+
+	"github.com/davidborzek/tvhgo/cmd/common"
+	"github.com/davidborzek/tvhgo/repository/session"
+	"github.com/davidborzek/tvhgo/repository/token"
+	"github.com/davidborzek/tvhgo/repository/user"
+	"github.com/davidborzek/tvhgo/services/auth"
+	"github.com/davidborzek/tvhgo/services/clock"
+	"github.com/urfave/cli/v2"
+)
+
+var revokeCmd = &cli.Command{
+	Name:  "revoke",
+	Usage: "Revokes all sessions of a user",
+	Flags: []cli.Flag{
+		&cli.StringFlag{
+			Name:     "username",
+			Aliases:  []string{"u"},
+			Usage:    "Username of the user",
+			Required: true,
+		},
+		&cli.BoolFlag{
+			Name:  "tokens",
+			Usage: "Revoke all tokens of the user as well",
+		},
+	},
+	Action: revoke,
+}
+
+func revoke(ctx *cli.Context) error {
+	cfg, db := common.Init(ctx)
+	clock := clock.NewClock()
+
+	user, err := user.New(db, clock).
+		FindByUsername(ctx.Context, ctx.String("username"))
+	if err != nil {
+		return err
+	}
+
+	if user == nil {
+		return errors.New("user not found")
+	}
+
+	sessionManager := auth.NewSessionManager(
+		session.New(db, clock),
+		clock,
+		cfg.Auth.Session.MaximumInactiveLifetime,
+		cfg.Auth.Session.MaximumLifetime,
+		cfg.Auth.Session.TokenRotationInterval,
+	)
+
+	revoked, err := sessionManager.RevokeAll(ctx.Context, user.ID, nil)
+	if err != nil {
+		return err
+	}
+
+	fmt.Printf("%d sessions successfully revoked.\n", revoked)
+
+	if !ctx.Bool("tokens") {
+		return nil
+	}
+
+	tokenService := auth.NewTokenService(token.New(db, clock), clock)
+
+	revoked, err = tokenService.RevokeAll(ctx.Context, user.ID)
+	if err != nil {
+		return err
+	}
+
+	fmt.Printf("%d tokens successfully revoked.\n", revoked)
+
+	return nil
+}
@@ -0,0 +1,13 @@
+package session
+
+import (
+	"github.com/urfave/cli/v2"
+)
+
+var Cmd = &cli.Command{
+	Name:  "session",
+	Usage: "Manage sessions of a user",
+	Subcommands: []*cli.Command{
+		revokeCmd,
+	},
+}
@@ -5,6 +5,7 @@ import (
 	"fmt"
 
 	"github.com/davidborzek/tvhgo/cmd/common"
+	"github.com/davidborzek/tvhgo/core"
 	"github.com/davidborzek/tvhgo/repository/token"
 	"github.com/davidborzek/tvhgo/repository/user"
 	"github.com/davidborzek/tvhgo/services/auth"
@@ -28,6 +29,16 @@ var generateCmd = &cli.Command{
 			Usage:    "Name of the token,",
 			Required: true,
 		},
+		&cli.StringSliceFlag{
+			Name:    "scope",
+			Aliases: []string{"s"},
+			Usage:   "Restrict the token to a permission (e.g. epg:read), can be set multiple times",
+		},
+		&cli.DurationFlag{
+			Name:    "expires",
+			Aliases: []string{"e"},
+			Usage:   "Lifetime of the token (e.g. 720h), by default the token never expires",
+		},
 	},
 	Action: generate,
 }
@@ -45,10 +56,24 @@ func generate(ctx *cli.Context) error {
 		return errors.New("user not found")
 	}
 
-	tokenRepository := token.New(db)
-	tokenService := auth.NewTokenService(tokenRepository)
+	clock := clock.NewClock()
+	tokenRepository := token.New(db, clock)
+	tokenService := auth.NewTokenService(tokenRepository, clock)
+
+	opts := core.CreateToken{
+		Name: ctx.String("name"),
+	}
+
+	for _, scope := range ctx.StringSlice("scope") {
+		opts.Scopes = append(opts.Scopes, core.Permission(scope))
+	}
+
+	if expires := ctx.Duration("expires"); expires != 0 {
+		expiresAt := clock.Now().Add(expires).Unix()
+		opts.ExpiresAt = &expiresAt
+	}
 
-	tokenValue, err := tokenService.Create(ctx.Context, user.ID, ctx.String("name"))
+	tokenValue, err := tokenService.Create(ctx.Context, user.ID, opts)
 	if err != nil {
 		return err
 	}
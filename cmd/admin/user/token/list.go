@@ -3,6 +3,7 @@ package token
 import (
 	"errors"
 	"fmt"
+	"strings"
 	"time"
 
 	"github.com/davidborzek/tvhgo/cmd/common"
@@ -40,7 +41,7 @@ func list(ctx *cli.Context) error {
 		return errors.New("user not found")
 	}
 
-	tokenRepository := token.New(db)
+	tokenRepository := token.New(db, clock.NewClock())
 	tokens, err := tokenRepository.FindByUser(ctx.Context, user.ID)
 	if err != nil {
 		return err
@@ -52,11 +53,15 @@ func list(ctx *cli.Context) error {
 	}
 
 	common.PrintTable(
-		[]string{"ID", "Name", "Created"},
+		[]string{"ID", "Name", "Scopes", "Expires", "Last used", "Last IP", "Created"},
 		common.MapRows(tokens, func(token *core.Token) []any {
 			return []any{
 				token.ID,
 				token.Name,
+				formatScopes(token.Scopes),
+				formatOptionalTime(token.ExpiresAt, "Never"),
+				formatOptionalTime(token.LastUsedAt, "Never"),
+				formatOptionalString(token.LastUsedIP),
 				time.Unix(token.CreatedAt, 0).Format(time.RFC822),
 			}
 		}),
@@ -64,3 +69,29 @@ func list(ctx *cli.Context) error {
 
 	return nil
 }
+
+func formatScopes(scopes []core.Permission) string {
+	if scopes == nil {
+		return "All"
+	}
+
+	parts := make([]string, 0, len(scopes))
+	for _, scope := range scopes {
+		parts = append(parts, string(scope))
+	}
+	return strings.Join(parts, ", ")
+}
+
+func formatOptionalTime(ts *int64, fallback string) string {
+	if ts == nil {
+		return fallback
+	}
+	return time.Unix(*ts, 0).Format(time.RFC822)
+}
+
+func formatOptionalString(s *string) string {
+	if s == nil {
+		return "-"
+	}
+	return *s
+}
@@ -1,11 +1,14 @@
 package token
 
 import (
+	"errors"
 	"fmt"
 
 	"github.com/davidborzek/tvhgo/cmd/common"
 	"github.com/davidborzek/tvhgo/repository/token"
+	"github.com/davidborzek/tvhgo/repository/user"
 	"github.com/davidborzek/tvhgo/services/auth"
+	"github.com/davidborzek/tvhgo/services/clock"
 	"github.com/urfave/cli/v2"
 )
 
@@ -13,6 +16,12 @@ var revokeCmd = &cli.Command{
 	Name:  "revoke",
 	Usage: "Revokes a token",
 	Flags: []cli.Flag{
+		&cli.StringFlag{
+			Name:     "username",
+			Aliases:  []string{"u"},
+			Usage:    "Username of the token owner",
+			Required: true,
+		},
 		&cli.StringFlag{
 			Name:     "id",
 			Usage:    "ID of the token,",
@@ -24,10 +33,22 @@ var revokeCmd = &cli.Command{
 
 func revoke(ctx *cli.Context) error {
 	_, db := common.Init(ctx)
-	tokenRepository := token.New(db)
-	tokenService := auth.NewTokenService(tokenRepository)
+	clock := clock.NewClock()
+
+	user, err := user.New(db, clock).
+		FindByUsername(ctx.Context, ctx.String("username"))
+	if err != nil {
+		return err
+	}
+
+	if user == nil {
+		return errors.New("user not found")
+	}
+
+	tokenRepository := token.New(db, clock)
+	tokenService := auth.NewTokenService(tokenRepository, clock)
 
-	if err := tokenService.Revoke(ctx.Context, ctx.Int64("id")); err != nil {
+	if err := tokenService.Revoke(ctx.Context, ctx.Int64("id"), user.ID); err != nil {
 		return err
 	}
 
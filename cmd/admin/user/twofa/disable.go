@@ -5,10 +5,12 @@ import (
 	"fmt"
 
 	"github.com/davidborzek/tvhgo/cmd/common"
-	"github.com/davidborzek/tvhgo/core"
+	twofactorrecoverycode "github.com/davidborzek/tvhgo/repository/two_factor_recovery_code"
 	twofactorsettings "github.com/davidborzek/tvhgo/repository/two_factor_settings"
 	"github.com/davidborzek/tvhgo/repository/user"
+	"github.com/davidborzek/tvhgo/services/auth"
 	"github.com/davidborzek/tvhgo/services/clock"
+	"github.com/davidborzek/tvhgo/services/encryption"
 	"github.com/urfave/cli/v2"
 )
 
@@ -27,7 +29,7 @@ var disableCmd = &cli.Command{
 }
 
 func disable(ctx *cli.Context) error {
-	_, db := common.Init(ctx)
+	cfg, db := common.Init(ctx)
 	userRepository := user.New(db, clock.NewClock())
 
 	user, err := userRepository.FindByUsername(ctx.Context, ctx.String("username"))
@@ -39,13 +41,19 @@ func disable(ctx *cli.Context) error {
 		return errors.New("user not found")
 	}
 
-	twoFactorSettingsRepository := twofactorsettings.New(db)
+	encryptor, err := encryption.New(&cfg.Database.Encryption)
+	if err != nil {
+		return err
+	}
 
-	err = twoFactorSettingsRepository.Delete(ctx.Context, &core.TwoFactorSettings{
-		UserID: user.ID,
-	})
+	twoFactorService := auth.NewTwoFactorAuthService(
+		twofactorsettings.New(db, encryptor),
+		twofactorrecoverycode.New(db, clock.NewClock()),
+		userRepository,
+		&cfg.Auth.TOTP,
+	)
 
-	if err != nil {
+	if err := twoFactorService.Disable(ctx.Context, user.ID); err != nil {
 		return err
 	}
 
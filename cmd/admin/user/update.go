@@ -0,0 +1,97 @@
+package user
+
+import (
+	"errors"
+	"fmt"
+
+	"github.com/davidborzek/tvhgo/cmd/common"
+	trusteddevice "github.com/davidborzek/tvhgo/repository/trusted_device"
+	"github.com/davidborzek/tvhgo/repository/user"
+	"github.com/davidborzek/tvhgo/services/auth"
+	"github.com/davidborzek/tvhgo/services/clock"
+	"github.com/urfave/cli/v2"
+)
+
+var updateCmd = &cli.Command{
+	Name:        "update",
+	Usage:       "Update a user",
+	Description: "Only the given flags are updated. Use the role command to change the role of the user.",
+	Flags: []cli.Flag{
+		&cli.StringFlag{
+			Name:     "username",
+			Aliases:  []string{"u"},
+			Usage:    "Username of the user",
+			Required: true,
+		},
+		&cli.StringFlag{
+			Name:  "new-username",
+			Usage: "New username of the user",
+		},
+		&cli.StringFlag{
+			Name:    "password",
+			Aliases: []string{"p"},
+			Usage:   "New password of the user",
+			EnvVars: []string{"TVHGO_PASSWORD"},
+		},
+		&cli.StringFlag{
+			Name:    "email",
+			Aliases: []string{"e"},
+			Usage:   "New email of the user",
+		},
+		&cli.StringFlag{
+			Name:    "display-name",
+			Aliases: []string{"n"},
+			Usage:   "New display name of the user",
+		},
+	},
+	Action: update,
+}
+
+func update(ctx *cli.Context) error {
+	cfg, db := common.Init(ctx)
+	userRepository := user.New(db, clock.NewClock())
+
+	user, err := userRepository.FindByUsername(ctx.Context, ctx.String("username"))
+	if err != nil {
+		return err
+	}
+
+	if user == nil {
+		return errors.New("user not found")
+	}
+
+	if ctx.IsSet("new-username") {
+		user.Username = ctx.String("new-username")
+	}
+
+	if ctx.IsSet("email") {
+		user.Email = ctx.String("email")
+	}
+
+	if ctx.IsSet("display-name") {
+		user.DisplayName = ctx.String("display-name")
+	}
+
+	if ctx.IsSet("password") {
+		hash, err := auth.NewPasswordHasher(&cfg.Auth.Password).Hash(ctx.String("password"))
+		if err != nil {
+			return err
+		}
+
+		user.PasswordHash = hash
+	}
+
+	if err := userRepository.Update(ctx.Context, user); err != nil {
+		return err
+	}
+
+	if ctx.IsSet("password") {
+		trustedDeviceRepository := trusteddevice.New(db, clock.NewClock())
+		if _, err := trustedDeviceRepository.DeleteByUser(ctx.Context, user.ID); err != nil {
+			return err
+		}
+	}
+
+	fmt.Println("User successfully updated.")
+	return nil
+}
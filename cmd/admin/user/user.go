@@ -1,6 +1,7 @@
 package user
 
 import (
+	"github.com/davidborzek/tvhgo/cmd/admin/user/session"
 	"github.com/davidborzek/tvhgo/cmd/admin/user/token"
 	"github.com/davidborzek/tvhgo/cmd/admin/user/twofa"
 	"github.com/urfave/cli/v2"
@@ -14,8 +15,14 @@ var (
 			addCmd,
 			listCmd,
 			deleteCmd,
+			roleCmd,
+			updateCmd,
+			disableCmd,
+			enableCmd,
+			passwordCheckCmd,
 			twofa.Cmd,
 			token.Cmd,
+			session.Cmd,
 		},
 	}
 )
@@ -6,18 +6,31 @@ import (
 
 	"github.com/davidborzek/tvhgo/api"
 	"github.com/davidborzek/tvhgo/config"
+	"github.com/davidborzek/tvhgo/core"
 	"github.com/davidborzek/tvhgo/db"
 	"github.com/davidborzek/tvhgo/health"
 	"github.com/davidborzek/tvhgo/metrics"
+	auditlog "github.com/davidborzek/tvhgo/repository/audit_log"
+	deviceauthorization "github.com/davidborzek/tvhgo/repository/device_authorization"
+	loginthrottle "github.com/davidborzek/tvhgo/repository/login_throttle"
+	parentalcontrols "github.com/davidborzek/tvhgo/repository/parental_controls"
+	recordingowner "github.com/davidborzek/tvhgo/repository/recording_owner"
 	"github.com/davidborzek/tvhgo/repository/session"
 	"github.com/davidborzek/tvhgo/repository/token"
+	trusteddevice "github.com/davidborzek/tvhgo/repository/trusted_device"
+	twofactorrecoverycode "github.com/davidborzek/tvhgo/repository/two_factor_recovery_code"
 	twofactorsettings "github.com/davidborzek/tvhgo/repository/two_factor_settings"
 	"github.com/davidborzek/tvhgo/repository/user"
+	webauthncredential "github.com/davidborzek/tvhgo/repository/webauthn_credential"
+	"github.com/davidborzek/tvhgo/services/audit"
 	"github.com/davidborzek/tvhgo/services/auth"
 	"github.com/davidborzek/tvhgo/services/channel"
 	"github.com/davidborzek/tvhgo/services/clock"
 	"github.com/davidborzek/tvhgo/services/dvr"
+	"github.com/davidborzek/tvhgo/services/encryption"
 	"github.com/davidborzek/tvhgo/services/epg"
+	"github.com/davidborzek/tvhgo/services/hls"
+	"github.com/davidborzek/tvhgo/services/parental"
 	"github.com/davidborzek/tvhgo/services/picon"
 	profiles "github.com/davidborzek/tvhgo/services/profile"
 	"github.com/davidborzek/tvhgo/services/recording"
@@ -57,21 +70,64 @@ func start(ctx *cli.Context) error {
 
 	clock := clock.NewClock()
 
+	encryptor, err := encryption.New(&cfg.Database.Encryption)
+	if err != nil {
+		log.Fatal().Err(err).Msg("failed to load database encryption keys")
+	}
+
+	if !encryptor.Enabled() {
+		log.Warn().Msg("no database encryption keys configured, storing totp secrets unencrypted")
+	}
+
 	tvhOpts := tvheadend.ClientOpts{
-		URL:      cfg.Tvheadend.URL(),
-		Username: cfg.Tvheadend.Username,
-		Password: cfg.Tvheadend.Password,
+		URL:        cfg.Tvheadend.URL(),
+		Username:   cfg.Tvheadend.Username,
+		Password:   cfg.Tvheadend.Password,
+		AuthMethod: tvheadend.AuthMethod(cfg.Tvheadend.AuthMethod),
+		TLS: tvheadend.TLSOpts{
+			CAFile:             cfg.Tvheadend.TLS.CAFile,
+			CertFile:           cfg.Tvheadend.TLS.CertFile,
+			KeyFile:            cfg.Tvheadend.TLS.KeyFile,
+			InsecureSkipVerify: cfg.Tvheadend.TLS.InsecureSkipVerify,
+		},
+		MaxConcurrentRequests: cfg.Tvheadend.MaxConcurrentRequests,
 	}
 
-	tvhClient := tvheadend.New(tvhOpts)
-	tvhStreamingClient := tvheadend.NewStreamingClient(tvhOpts)
+	if *cfg.Tvheadend.Retry.Enabled {
+		tvhOpts.Retry = tvheadend.RetryOpts{
+			MaxAttempts: cfg.Tvheadend.Retry.MaxAttempts,
+			BaseDelay:   cfg.Tvheadend.Retry.BaseDelay,
+			MaxDelay:    cfg.Tvheadend.Retry.MaxDelay,
+		}
+	}
+
+	if *cfg.Tvheadend.CircuitBreaker.Enabled {
+		tvhOpts.CircuitBreaker = tvheadend.CircuitBreakerOpts{
+			FailureThreshold: cfg.Tvheadend.CircuitBreaker.FailureThreshold,
+			OpenTimeout:      cfg.Tvheadend.CircuitBreaker.OpenTimeout,
+			Clock:            clock,
+		}
+	}
+
+	if cfg.Tvheadend.TLS.InsecureSkipVerify {
+		log.Warn().Msg("tls certificate verification of tvheadend is disabled")
+	}
+
+	tvhClient, err := tvheadend.New(tvhOpts)
+	if err != nil {
+		log.Fatal().Err(err).Msg("failed to create tvheadend client")
+	}
+
+	tvhStreamingClient, err := tvheadend.NewStreamingClient(tvhOpts)
+	if err != nil {
+		log.Fatal().Err(err).Msg("failed to create tvheadend streaming client")
+	}
 
 	userRepository := user.New(dbConn, clock)
 	sessionRepository := session.New(dbConn, clock)
-
-	// TODO clock
-	tokenRepository := token.New(dbConn)
-	twoFactorSettingsRepository := twofactorsettings.New(dbConn)
+	tokenRepository := token.New(dbConn, clock)
+	twoFactorSettingsRepository := twofactorsettings.New(dbConn, encryptor)
+	recoveryCodeRepository := twofactorrecoverycode.New(dbConn, clock)
 
 	sessionManager := auth.NewSessionManager(
 		sessionRepository,
@@ -81,22 +137,84 @@ func start(ctx *cli.Context) error {
 		cfg.Auth.Session.TokenRotationInterval,
 	)
 
-	tokenService := auth.NewTokenService(tokenRepository)
+	tokenService := auth.NewTokenService(tokenRepository, clock)
+	loginThrottleService := auth.NewLoginThrottleService(
+		loginthrottle.New(dbConn),
+		clock,
+		&cfg.Auth.LoginThrottle,
+	)
+	auditService := audit.New(auditlog.New(dbConn, clock), userRepository)
+	deviceAuthService := auth.NewDeviceAuthService(
+		deviceauthorization.New(dbConn, clock),
+		sessionManager,
+		tokenService,
+		clock,
+		&cfg.Auth.Device,
+	)
 
+	passwordHasher := auth.NewPasswordHasher(&cfg.Auth.Password)
 	twoFactorService := auth.NewTwoFactorAuthService(
 		twoFactorSettingsRepository,
+		recoveryCodeRepository,
 		userRepository,
 		&cfg.Auth.TOTP,
 	)
-	passwordAuthenticator := auth.NewLocalPasswordAuthenticator(userRepository, twoFactorService)
+	passwordAuthenticators := make([]core.PasswordAuthenticator, 0, len(cfg.Auth.Authenticators))
+	for _, authenticator := range cfg.Auth.Authenticators {
+		switch authenticator {
+		case config.AuthenticatorLocal:
+			passwordAuthenticators = append(passwordAuthenticators,
+				auth.NewLocalPasswordAuthenticator(userRepository, twoFactorService, passwordHasher))
+		case config.AuthenticatorLDAP:
+			passwordAuthenticators = append(passwordAuthenticators,
+				auth.NewLDAPPasswordAuthenticator(&cfg.Auth.LDAP, userRepository, twoFactorService))
+		}
+	}
+	passwordAuthenticator := auth.NewChainPasswordAuthenticator(passwordAuthenticators...)
+	oidcAuthenticator := auth.NewOIDCAuthenticator(&cfg.Auth.OIDC, userRepository)
+
+	var webAuthnService core.WebAuthnService
+	if cfg.Auth.WebAuthn.Enabled {
+		webAuthnService, err = auth.NewWebAuthnService(
+			&cfg.Auth.WebAuthn,
+			userRepository,
+			webauthncredential.New(dbConn, clock),
+			clock,
+		)
+		if err != nil {
+			log.Fatal().Err(err).Msg("failed to create webauthn service")
+		}
+	}
 
 	channelService := channel.New(tvhClient)
 	epgService := epg.New(tvhClient)
 	piconService := picon.New(tvhClient)
 	recordingService := recording.New(tvhClient)
-	streamingService := streaming.New(tvhStreamingClient)
+	streamingService := streaming.New(&cfg.Streaming, tvhStreamingClient)
+	streamSessionRegistry := streaming.NewSessionRegistry(clock)
+	streamLimiter := streaming.NewLimiter(
+		&cfg.Streaming,
+		streamSessionRegistry,
+		streamingService,
+		tvhClient,
+		recordingService,
+		clock,
+	)
+	streamSigner := streaming.NewSigner(&cfg.Auth.StreamSigning, clock)
+	hlsService := hls.New(
+		&cfg.Streaming.HLS,
+		streamingService,
+		streamLimiter,
+		streamSessionRegistry,
+		clock,
+	)
 	dvrConfigService := dvr.New(tvhClient)
 	profileService := profiles.New(tvhClient)
+	parentalControlsService := parental.New(
+		parentalcontrols.New(dbConn, clock),
+		channelService,
+		clock,
+	)
 
 	sessionCleaner := auth.NewSessionCleaner(
 		sessionRepository,
@@ -106,6 +224,7 @@ func start(ctx *cli.Context) error {
 		cfg.Auth.Session.MaximumLifetime,
 	)
 	sessionCleaner.Start()
+	hlsService.Start()
 
 	apiRouter := api.New(
 		cfg,
@@ -123,6 +242,23 @@ func start(ctx *cli.Context) error {
 		twoFactorService,
 		dvrConfigService,
 		profileService,
+		streamSessionRegistry,
+		streamLimiter,
+		streamSigner,
+		hlsService,
+		oidcAuthenticator,
+		webAuthnService,
+		recordingowner.New(dbConn, clock),
+		loginThrottleService,
+		auditService,
+		deviceAuthService,
+		parentalControlsService,
+		auth.NewTrustedDeviceService(
+			trusteddevice.New(dbConn, clock),
+			clock,
+			&cfg.Auth.TrustedDevice,
+		),
+		passwordHasher,
 	)
 
 	healthRouter := health.New(tvhClient, dbConn)
@@ -141,6 +277,7 @@ func start(ctx *cli.Context) error {
 	}
 
 	r := chi.NewRouter()
+	r.Use(api.SecurityHeaders(&cfg.Server.SecurityHeaders))
 
 	r.Mount("/api", apiRouter.Handler())
 	r.Mount("/health", healthRouter.Handler())
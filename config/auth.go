@@ -1,6 +1,10 @@
 package config
 
-import "time"
+import (
+	"errors"
+	"fmt"
+	"time"
+)
 
 const (
 	defaultSessionCookieName              = "tvhgo_session"
@@ -10,9 +14,64 @@ const (
 	defaultSessionCleanupInterval         = 12 * time.Hour
 	defaultTOTPIssuer                     = "tvhgo"
 
-	defaultReverseProxyAuthUserHeader  = "Remote-User"
-	defaultReverseProxyAuthEmailHeader = "Remote-Email"
-	defaultReverseProxyAuthNameHeader  = "Remote-Name"
+	defaultReverseProxyAuthUserHeader   = "Remote-User"
+	defaultReverseProxyAuthEmailHeader  = "Remote-Email"
+	defaultReverseProxyAuthNameHeader   = "Remote-Name"
+	defaultReverseProxyAuthGroupsHeader = "Remote-Groups"
+
+	defaultStreamSigningTTL = 4 * time.Hour
+
+	defaultOIDCUsernameClaim = "preferred_username"
+	defaultOIDCGroupsClaim   = "groups"
+)
+
+const (
+	AuthenticatorLocal = "local"
+	AuthenticatorLDAP  = "ldap"
+
+	PasswordAlgorithmArgon2id = "argon2id"
+	PasswordAlgorithmBcrypt   = "bcrypt"
+
+	// Upper bounds of the argon2id parameters. Stored hashes
+	// exceeding them are rejected, since they could exhaust
+	// the memory or cpu of the server.
+	PasswordArgon2idMaxMemory      = 1024 * 1024
+	PasswordArgon2idMaxIterations  = 64
+	PasswordArgon2idMaxParallelism = 64
+
+	defaultPasswordArgon2idMemory      = 64 * 1024
+	defaultPasswordArgon2idIterations  = 3
+	defaultPasswordArgon2idParallelism = 2
+	defaultPasswordBcryptCost          = 12
+
+	defaultLDAPUserFilter           = "(&(objectClass=person)(uid={username}))"
+	defaultLDAPUsernameAttribute    = "uid"
+	defaultLDAPEmailAttribute       = "mail"
+	defaultLDAPDisplayNameAttribute = "cn"
+	defaultLDAPGroupFilter          = "(&(objectClass=groupOfNames)(member={dn}))"
+	defaultLDAPGroupNameAttribute   = "cn"
+
+	defaultWebAuthnRPDisplayName = "tvhgo"
+	defaultWebAuthnTimeout       = 5 * time.Minute
+
+	defaultLoginThrottleMaxAttemptsPerUsername = 5
+	defaultLoginThrottleMaxAttemptsPerIP       = 20
+	defaultLoginThrottleBaseDelay              = time.Second
+	defaultLoginThrottleMaxDelay               = time.Minute
+	defaultLoginThrottleLockoutDuration        = 15 * time.Minute
+
+	defaultDeviceAuthCodeLifetime = 10 * time.Minute
+	defaultDeviceAuthPollInterval = 5 * time.Second
+	defaultDeviceAuthMaxPending   = 1000
+	defaultDeviceAuthMaxPendingIP = 5
+
+	defaultTrustedDeviceCookieName  = "tvhgo_trusted_device"
+	defaultTrustedDeviceMaxLifetime = 30 * 24 * time.Hour
+)
+
+var (
+	defaultOIDCScopes     = []string{"openid", "profile", "email"}
+	defaultAuthenticators = []string{AuthenticatorLocal}
 )
 
 type (
@@ -30,18 +89,158 @@ type (
 	}
 
 	ReverseProxyAuthConfig struct {
-		Enabled           bool     `yaml:"enabled"     env:"ENABLED"`
-		UserHeader        string   `yaml:"user_header" env:"USER_HEADER"`
-		EmailHeader       string   `yaml:"email_header" env:"EMAIL_HEADER"`
-		NameHeader        string   `yaml:"name_header" env:"NAME_HEADER"`
+		Enabled     bool   `yaml:"enabled"     env:"ENABLED"`
+		UserHeader  string `yaml:"user_header" env:"USER_HEADER"`
+		EmailHeader string `yaml:"email_header" env:"EMAIL_HEADER"`
+		NameHeader  string `yaml:"name_header" env:"NAME_HEADER"`
+		// GroupsHeader contains the comma separated groups of the user.
+		GroupsHeader string `yaml:"groups_header" env:"GROUPS_HEADER"`
+		// AdminGroups grants the admin role to members of one of the groups.
+		AdminGroups []string `yaml:"admin_groups" env:"ADMIN_GROUPS"`
+		// GroupRoles maps groups to roles. Members of multiple groups
+		// get the role with the most permissions.
+		GroupRoles map[string]string `yaml:"group_roles" env:"GROUP_ROLES"`
+		// DefaultRole is the role of users, who are not member of one of the
+		// admin groups or mapped groups. Defaults to the default role of new users.
+		DefaultRole       string   `yaml:"default_role" env:"DEFAULT_ROLE"`
 		AllowedProxies    []string `yaml:"allowed_proxies" env:"ALLOWED_PROXIES"`
 		AllowRegistration bool     `yaml:"allow_registration" env:"ALLOW_REGISTRATION"`
 	}
 
+	StreamSigningConfig struct {
+		// Keys used to sign stream urls. The first key is used to sign
+		// new urls, all keys are accepted when verifying a signature.
+		Keys []string      `yaml:"keys" env:"KEYS"`
+		TTL  time.Duration `yaml:"ttl"  env:"TTL"`
+	}
+
+	OIDCConfig struct {
+		Enabled      bool   `yaml:"enabled"       env:"ENABLED"`
+		Issuer       string `yaml:"issuer"        env:"ISSUER"`
+		ClientID     string `yaml:"client_id"     env:"CLIENT_ID"`
+		ClientSecret string `yaml:"client_secret" env:"CLIENT_SECRET"`
+		// RedirectURL is the public url of the callback endpoint
+		// (e.g. https://tvhgo.example.com/api/auth/oidc/callback).
+		RedirectURL   string   `yaml:"redirect_url"   env:"REDIRECT_URL"`
+		Scopes        []string `yaml:"scopes"         env:"SCOPES"`
+		UsernameClaim string   `yaml:"username_claim" env:"USERNAME_CLAIM"`
+		GroupsClaim   string   `yaml:"groups_claim"   env:"GROUPS_CLAIM"`
+		// AdminGroups grants the admin role to members of one of the groups.
+		// When not set, the admin role is not managed via OIDC.
+		AdminGroups       []string `yaml:"admin_groups"       env:"ADMIN_GROUPS"`
+		AllowRegistration bool     `yaml:"allow_registration" env:"ALLOW_REGISTRATION"`
+	}
+
+	LDAPConfig struct {
+		// URL of the ldap server (e.g. ldaps://ldap.example.com).
+		URL                string `yaml:"url"                  env:"URL"`
+		StartTLS           bool   `yaml:"start_tls"            env:"START_TLS"`
+		InsecureSkipVerify bool   `yaml:"insecure_skip_verify" env:"INSECURE_SKIP_VERIFY"`
+		// BindDN and BindPassword of the user used to search for users and groups.
+		BindDN       string `yaml:"bind_dn"       env:"BIND_DN"`
+		BindPassword string `yaml:"bind_password" env:"BIND_PASSWORD"`
+		BaseDN       string `yaml:"base_dn"       env:"BASE_DN"`
+		// UserFilter is used to find a user. {username} is replaced by the login.
+		UserFilter           string `yaml:"user_filter"            env:"USER_FILTER"`
+		UsernameAttribute    string `yaml:"username_attribute"     env:"USERNAME_ATTRIBUTE"`
+		EmailAttribute       string `yaml:"email_attribute"        env:"EMAIL_ATTRIBUTE"`
+		DisplayNameAttribute string `yaml:"display_name_attribute" env:"DISPLAY_NAME_ATTRIBUTE"`
+		// GroupBaseDN is the base dn to search for groups. Defaults to BaseDN.
+		GroupBaseDN string `yaml:"group_base_dn" env:"GROUP_BASE_DN"`
+		// GroupFilter is used to find the groups of a user. {dn} is replaced
+		// by the dn and {username} by the username of the user.
+		GroupFilter        string `yaml:"group_filter"         env:"GROUP_FILTER"`
+		GroupNameAttribute string `yaml:"group_name_attribute" env:"GROUP_NAME_ATTRIBUTE"`
+		// AdminGroups grants the admin role to members of one of the groups.
+		// When not set, the admin role is not managed via LDAP.
+		AdminGroups []string `yaml:"admin_groups" env:"ADMIN_GROUPS"`
+	}
+
+	WebAuthnConfig struct {
+		Enabled bool `yaml:"enabled" env:"ENABLED"`
+		// RPID is the relying party id, which is the domain of tvhgo (e.g. tvhgo.example.com).
+		RPID          string `yaml:"rp_id"           env:"RP_ID"`
+		RPDisplayName string `yaml:"rp_display_name" env:"RP_DISPLAY_NAME"`
+		// RPOrigins are the origins tvhgo is served from (e.g. https://tvhgo.example.com).
+		RPOrigins []string `yaml:"rp_origins" env:"RP_ORIGINS"`
+		// Timeout is the time a user has to complete a registration or login.
+		Timeout time.Duration `yaml:"timeout" env:"TIMEOUT"`
+	}
+
+	LoginThrottleConfig struct {
+		// Enabled enables the brute-force protection of the login. Defaults to true.
+		Enabled *bool `yaml:"enabled" env:"ENABLED"`
+		// MaxAttemptsPerUsername and MaxAttemptsPerIP are the failed
+		// attempts after which further logins are locked out.
+		MaxAttemptsPerUsername int `yaml:"max_attempts_per_username" env:"MAX_ATTEMPTS_PER_USERNAME"`
+		MaxAttemptsPerIP       int `yaml:"max_attempts_per_ip"       env:"MAX_ATTEMPTS_PER_IP"`
+		// BaseDelay is the delay after the first failed attempt, which
+		// is doubled on each further failed attempt up to MaxDelay.
+		BaseDelay time.Duration `yaml:"base_delay" env:"BASE_DELAY"`
+		MaxDelay  time.Duration `yaml:"max_delay"  env:"MAX_DELAY"`
+		// LockoutDuration is the duration of a lockout. Failed attempts
+		// are discarded when no further attempt failed within this duration.
+		LockoutDuration time.Duration `yaml:"lockout_duration" env:"LOCKOUT_DURATION"`
+	}
+
+	DeviceAuthConfig struct {
+		// Enabled enables the device authorization grant for devices
+		// with limited input capabilities (e.g. tv apps).
+		Enabled bool `yaml:"enabled" env:"ENABLED"`
+		// VerificationURL is the public url of the page in the web ui, where
+		// users approve a device (e.g. https://tvhgo.example.com/device).
+		VerificationURL string `yaml:"verification_url" env:"VERIFICATION_URL"`
+		// CodeLifetime is the time a user has to approve a device.
+		CodeLifetime time.Duration `yaml:"code_lifetime" env:"CODE_LIFETIME"`
+		// PollInterval is the minimum interval, in which devices poll for the approval.
+		PollInterval time.Duration `yaml:"poll_interval" env:"POLL_INTERVAL"`
+		// MaxPendingCodes is the maximum number of pending device codes.
+		MaxPendingCodes int `yaml:"max_pending_codes" env:"MAX_PENDING_CODES"`
+		// MaxPendingCodesPerIP is the maximum number of pending device codes
+		// started from the same ip address.
+		MaxPendingCodesPerIP int `yaml:"max_pending_codes_per_ip" env:"MAX_PENDING_CODES_PER_IP"`
+	}
+
+	TrustedDeviceConfig struct {
+		// Enabled allows users to trust a device at login, so
+		// that the second factor is skipped on this device.
+		Enabled    bool   `yaml:"enabled"     env:"ENABLED"`
+		CookieName string `yaml:"cookie_name" env:"COOKIE_NAME"`
+		// MaxLifetime is the maximum time a device can be trusted.
+		MaxLifetime time.Duration `yaml:"max_lifetime" env:"MAX_LIFETIME"`
+	}
+
+	// Argon2idConfig contains the cost parameters of argon2id.
+	Argon2idConfig struct {
+		// Memory is the memory used in KiB.
+		Memory      uint32 `yaml:"memory"      env:"MEMORY"`
+		Iterations  uint32 `yaml:"iterations"  env:"ITERATIONS"`
+		Parallelism uint8  `yaml:"parallelism" env:"PARALLELISM"`
+	}
+
+	PasswordConfig struct {
+		// Algorithm is the algorithm used to hash new passwords (argon2id, bcrypt).
+		// Hashes of other algorithms are still verified and rehashed at login.
+		Algorithm  string         `yaml:"algorithm"   env:"ALGORITHM"`
+		Argon2id   Argon2idConfig `yaml:"argon2id"    envPrefix:"ARGON2ID_"`
+		BcryptCost int            `yaml:"bcrypt_cost" env:"BCRYPT_COST"`
+	}
+
 	AuthConfig struct {
-		Session      SessionConfig          `yaml:"session" envPrefix:"SESSION_"`
-		TOTP         TOTPConfig             `yaml:"totp"    envPrefix:"TOTP_"`
-		ReverseProxy ReverseProxyAuthConfig `yaml:"reverse_proxy" envPrefix:"REVERSE_PROXY_"`
+		Session       SessionConfig          `yaml:"session" envPrefix:"SESSION_"`
+		TOTP          TOTPConfig             `yaml:"totp"    envPrefix:"TOTP_"`
+		ReverseProxy  ReverseProxyAuthConfig `yaml:"reverse_proxy" envPrefix:"REVERSE_PROXY_"`
+		StreamSigning StreamSigningConfig    `yaml:"stream_signing" envPrefix:"STREAM_SIGNING_"`
+		OIDC          OIDCConfig             `yaml:"oidc" envPrefix:"OIDC_"`
+		LDAP          LDAPConfig             `yaml:"ldap" envPrefix:"LDAP_"`
+		WebAuthn      WebAuthnConfig         `yaml:"webauthn" envPrefix:"WEBAUTHN_"`
+		LoginThrottle LoginThrottleConfig    `yaml:"login_throttle" envPrefix:"LOGIN_THROTTLE_"`
+		Device        DeviceAuthConfig       `yaml:"device" envPrefix:"DEVICE_"`
+		TrustedDevice TrustedDeviceConfig    `yaml:"trusted_device" envPrefix:"TRUSTED_DEVICE_"`
+		Password      PasswordConfig         `yaml:"password" envPrefix:"PASSWORD_"`
+		// Authenticators is the ordered list of password authenticators
+		// used for the login (local, ldap).
+		Authenticators []string `yaml:"authenticators" env:"AUTHENTICATORS"`
 	}
 )
 
@@ -81,4 +280,229 @@ func (c *ReverseProxyAuthConfig) SetDefaults() {
 	if c.NameHeader == "" {
 		c.NameHeader = defaultReverseProxyAuthNameHeader
 	}
+
+	if c.GroupsHeader == "" {
+		c.GroupsHeader = defaultReverseProxyAuthGroupsHeader
+	}
+}
+
+func (c *StreamSigningConfig) SetDefaults() {
+	if c.TTL == 0 {
+		c.TTL = defaultStreamSigningTTL
+	}
+}
+
+func (c *OIDCConfig) SetDefaults() {
+	if len(c.Scopes) == 0 {
+		c.Scopes = defaultOIDCScopes
+	}
+	if c.UsernameClaim == "" {
+		c.UsernameClaim = defaultOIDCUsernameClaim
+	}
+	if c.GroupsClaim == "" {
+		c.GroupsClaim = defaultOIDCGroupsClaim
+	}
+}
+
+func (c *OIDCConfig) Validate() error {
+	if !c.Enabled {
+		return nil
+	}
+
+	if c.Issuer == "" || c.ClientID == "" || c.RedirectURL == "" {
+		return errors.New("oidc issuer, client id and redirect url must be set")
+	}
+
+	return nil
+}
+
+func (c *LDAPConfig) SetDefaults() {
+	if c.UserFilter == "" {
+		c.UserFilter = defaultLDAPUserFilter
+	}
+	if c.UsernameAttribute == "" {
+		c.UsernameAttribute = defaultLDAPUsernameAttribute
+	}
+	if c.EmailAttribute == "" {
+		c.EmailAttribute = defaultLDAPEmailAttribute
+	}
+	if c.DisplayNameAttribute == "" {
+		c.DisplayNameAttribute = defaultLDAPDisplayNameAttribute
+	}
+	if c.GroupBaseDN == "" {
+		c.GroupBaseDN = c.BaseDN
+	}
+	if c.GroupFilter == "" {
+		c.GroupFilter = defaultLDAPGroupFilter
+	}
+	if c.GroupNameAttribute == "" {
+		c.GroupNameAttribute = defaultLDAPGroupNameAttribute
+	}
+}
+
+func (c *LDAPConfig) Validate() error {
+	if c.URL == "" || c.BaseDN == "" {
+		return errors.New("ldap url and base dn must be set")
+	}
+
+	return nil
+}
+
+func (c *WebAuthnConfig) SetDefaults() {
+	if c.RPDisplayName == "" {
+		c.RPDisplayName = defaultWebAuthnRPDisplayName
+	}
+	if c.Timeout == 0 {
+		c.Timeout = defaultWebAuthnTimeout
+	}
+}
+
+func (c *WebAuthnConfig) Validate() error {
+	if !c.Enabled {
+		return nil
+	}
+
+	if c.RPID == "" || len(c.RPOrigins) == 0 {
+		return errors.New("webauthn rp id and rp origins must be set")
+	}
+
+	return nil
+}
+
+func (c *LoginThrottleConfig) SetDefaults() {
+	if c.Enabled == nil {
+		v := true
+		c.Enabled = &v
+	}
+	if c.MaxAttemptsPerUsername == 0 {
+		c.MaxAttemptsPerUsername = defaultLoginThrottleMaxAttemptsPerUsername
+	}
+	if c.MaxAttemptsPerIP == 0 {
+		c.MaxAttemptsPerIP = defaultLoginThrottleMaxAttemptsPerIP
+	}
+	if c.BaseDelay == 0 {
+		c.BaseDelay = defaultLoginThrottleBaseDelay
+	}
+	if c.MaxDelay == 0 {
+		c.MaxDelay = defaultLoginThrottleMaxDelay
+	}
+	if c.LockoutDuration == 0 {
+		c.LockoutDuration = defaultLoginThrottleLockoutDuration
+	}
+}
+
+func (c *DeviceAuthConfig) SetDefaults() {
+	if c.CodeLifetime == 0 {
+		c.CodeLifetime = defaultDeviceAuthCodeLifetime
+	}
+	if c.PollInterval == 0 {
+		c.PollInterval = defaultDeviceAuthPollInterval
+	}
+	if c.MaxPendingCodes == 0 {
+		c.MaxPendingCodes = defaultDeviceAuthMaxPending
+	}
+	if c.MaxPendingCodesPerIP == 0 {
+		c.MaxPendingCodesPerIP = defaultDeviceAuthMaxPendingIP
+	}
+}
+
+func (c *TrustedDeviceConfig) SetDefaults() {
+	if c.CookieName == "" {
+		c.CookieName = defaultTrustedDeviceCookieName
+	}
+	if c.MaxLifetime == 0 {
+		c.MaxLifetime = defaultTrustedDeviceMaxLifetime
+	}
+}
+
+func (c *DeviceAuthConfig) Validate() error {
+	if !c.Enabled {
+		return nil
+	}
+
+	if c.VerificationURL == "" {
+		return errors.New("device verification url must be set")
+	}
+
+	if c.MaxPendingCodes < 1 || c.MaxPendingCodesPerIP < 1 {
+		return errors.New("device max pending codes must be at least 1")
+	}
+
+	return nil
+}
+
+func (c *PasswordConfig) SetDefaults() {
+	if c.Algorithm == "" {
+		c.Algorithm = PasswordAlgorithmArgon2id
+	}
+	if c.Argon2id.Memory == 0 {
+		c.Argon2id.Memory = defaultPasswordArgon2idMemory
+	}
+	if c.Argon2id.Iterations == 0 {
+		c.Argon2id.Iterations = defaultPasswordArgon2idIterations
+	}
+	if c.Argon2id.Parallelism == 0 {
+		c.Argon2id.Parallelism = defaultPasswordArgon2idParallelism
+	}
+	if c.BcryptCost == 0 {
+		c.BcryptCost = defaultPasswordBcryptCost
+	}
+}
+
+func (c *PasswordConfig) Validate() error {
+	if c.Algorithm != PasswordAlgorithmArgon2id && c.Algorithm != PasswordAlgorithmBcrypt {
+		return fmt.Errorf("unknown password algorithm: %s", c.Algorithm)
+	}
+
+	if c.BcryptCost < 10 || c.BcryptCost > 31 {
+		return errors.New("password bcrypt cost must be between 10 and 31")
+	}
+
+	if c.Argon2id.Memory > PasswordArgon2idMaxMemory {
+		return fmt.Errorf("password argon2id memory must be at most %d", PasswordArgon2idMaxMemory)
+	}
+
+	if c.Argon2id.Iterations > PasswordArgon2idMaxIterations {
+		return fmt.Errorf("password argon2id iterations must be at most %d", PasswordArgon2idMaxIterations)
+	}
+
+	if c.Argon2id.Parallelism > PasswordArgon2idMaxParallelism {
+		return fmt.Errorf("password argon2id parallelism must be at most %d", PasswordArgon2idMaxParallelism)
+	}
+
+	return nil
+}
+
+func (c *AuthConfig) SetDefaults() {
+	if len(c.Authenticators) == 0 {
+		c.Authenticators = defaultAuthenticators
+	}
+}
+
+func (c *AuthConfig) Validate() error {
+	for _, authenticator := range c.Authenticators {
+		switch authenticator {
+		case AuthenticatorLocal:
+		case AuthenticatorLDAP:
+			if err := c.LDAP.Validate(); err != nil {
+				return err
+			}
+		default:
+			return fmt.Errorf("unknown authenticator: %s", authenticator)
+		}
+	}
+
+	if err := c.OIDC.Validate(); err != nil {
+		return err
+	}
+
+	if err := c.WebAuthn.Validate(); err != nil {
+		return err
+	}
+
+	if err := c.Password.Validate(); err != nil {
+		return err
+	}
+
+	return c.Device.Validate()
 }
@@ -17,6 +17,7 @@ type (
 		Database  DatabaseConfig  `yaml:"database"  envPrefix:"DATABASE_"`
 		Metrics   MetricsConfig   `yaml:"metrics"  envPrefix:"METRICS_"`
 		Log       LogConfig       `yaml:"log" envPrefix:"LOG_"`
+		Streaming StreamingConfig `yaml:"streaming" envPrefix:"STREAMING_"`
 	}
 )
 
@@ -117,10 +118,22 @@ func loadFromFile(cfgPath string, cfg *Config) error {
 }
 
 func (c *Config) validate() error {
+	if err := c.Server.Validate(); err != nil {
+		return err
+	}
+
 	if err := c.Tvheadend.Validate(); err != nil {
 		return err
 	}
 
+	if err := c.Auth.Validate(); err != nil {
+		return err
+	}
+
+	if err := c.Database.Encryption.Validate(); err != nil {
+		return err
+	}
+
 	if c.Server.Port == c.Metrics.Port {
 		return errors.New("metrics and server port cannot be the same")
 	}
@@ -134,7 +147,17 @@ func (c *Config) loadDefaults() {
 	c.Auth.Session.SetDefaults()
 	c.Auth.TOTP.SetDefaults()
 	c.Auth.ReverseProxy.SetDefaults()
+	c.Auth.StreamSigning.SetDefaults()
+	c.Auth.OIDC.SetDefaults()
+	c.Auth.LDAP.SetDefaults()
+	c.Auth.WebAuthn.SetDefaults()
+	c.Auth.LoginThrottle.SetDefaults()
+	c.Auth.Device.SetDefaults()
+	c.Auth.TrustedDevice.SetDefaults()
+	c.Auth.Password.SetDefaults()
+	c.Auth.SetDefaults()
 	c.Database.SetDefaults()
 	c.Metrics.SetDefaults()
 	c.Log.SetDefaults()
+	c.Streaming.SetDefaults()
 }
@@ -20,9 +20,27 @@ func TestLoadRequiredConfigFromEnv(t *testing.T) {
 	assert.Equal(t, "http", cfg.Tvheadend.Scheme)
 	assert.Empty(t, cfg.Tvheadend.Username)
 	assert.Empty(t, cfg.Tvheadend.Password)
+	assert.Equal(t, config.TvheadendAuthMethodAuto, cfg.Tvheadend.AuthMethod)
+	assert.Empty(t, cfg.Tvheadend.TLS.CAFile)
+	assert.False(t, cfg.Tvheadend.TLS.InsecureSkipVerify)
+	assert.True(t, *cfg.Tvheadend.Retry.Enabled)
+	assert.Equal(t, 3, cfg.Tvheadend.Retry.MaxAttempts)
+	assert.Equal(t, 200*time.Millisecond, cfg.Tvheadend.Retry.BaseDelay)
+	assert.Equal(t, 2*time.Second, cfg.Tvheadend.Retry.MaxDelay)
+	assert.True(t, *cfg.Tvheadend.CircuitBreaker.Enabled)
+	assert.Equal(t, 5, cfg.Tvheadend.CircuitBreaker.FailureThreshold)
+	assert.Equal(t, 30*time.Second, cfg.Tvheadend.CircuitBreaker.OpenTimeout)
+	assert.Equal(t, 16, cfg.Tvheadend.MaxConcurrentRequests)
 
 	assert.Empty(t, cfg.Server.Host)
 	assert.Equal(t, 8080, cfg.Server.Port)
+	assert.Empty(t, cfg.Server.CORS.AllowedOrigins)
+	assert.True(t, *cfg.Server.CSRF.Enabled)
+	assert.True(t, *cfg.Server.SecurityHeaders.Enabled)
+	assert.Contains(t, cfg.Server.SecurityHeaders.ContentSecurityPolicy, "default-src 'self'")
+	assert.Equal(t, "DENY", cfg.Server.SecurityHeaders.FrameOptions)
+	assert.Zero(t, cfg.Server.SecurityHeaders.HSTSMaxAge)
+	assert.False(t, cfg.Server.SecurityHeaders.TrustForwardedProto)
 
 	assert.Equal(t, "./tvhgo.db", cfg.Database.Path)
 	assert.Equal(t, config.DatabaseTypeSqlite, cfg.Database.Type)
@@ -38,6 +56,12 @@ func TestLoadRequiredConfigFromEnv(t *testing.T) {
 	assert.Equal(t, 30*time.Minute, cfg.Auth.Session.TokenRotationInterval)
 	assert.Equal(t, 12*time.Hour, cfg.Auth.Session.CleanupInterval)
 
+	assert.Equal(t, config.PasswordAlgorithmArgon2id, cfg.Auth.Password.Algorithm)
+	assert.Equal(t, uint32(65536), cfg.Auth.Password.Argon2id.Memory)
+	assert.Equal(t, uint32(3), cfg.Auth.Password.Argon2id.Iterations)
+	assert.Equal(t, uint8(2), cfg.Auth.Password.Argon2id.Parallelism)
+	assert.Equal(t, 12, cfg.Auth.Password.BcryptCost)
+
 	assert.False(t, cfg.Metrics.Enabled)
 	assert.Equal(t, "/metrics", cfg.Metrics.Path)
 	assert.Empty(t, cfg.Metrics.Token)
@@ -51,8 +75,62 @@ func TestLoadRequiredConfigFromEnv(t *testing.T) {
 	assert.Equal(t, "Remote-User", cfg.Auth.ReverseProxy.UserHeader)
 	assert.Equal(t, "Remote-Email", cfg.Auth.ReverseProxy.EmailHeader)
 	assert.Equal(t, "Remote-Name", cfg.Auth.ReverseProxy.NameHeader)
+	assert.Equal(t, "Remote-Groups", cfg.Auth.ReverseProxy.GroupsHeader)
+	assert.Empty(t, cfg.Auth.ReverseProxy.AdminGroups)
+	assert.Empty(t, cfg.Auth.ReverseProxy.GroupRoles)
+	assert.Empty(t, cfg.Auth.ReverseProxy.DefaultRole)
 	assert.Empty(t, cfg.Auth.ReverseProxy.AllowedProxies)
 	assert.False(t, cfg.Auth.ReverseProxy.AllowRegistration)
+
+	assert.Empty(t, cfg.Auth.StreamSigning.Keys)
+	assert.Equal(t, 4*time.Hour, cfg.Auth.StreamSigning.TTL)
+
+	assert.False(t, cfg.Auth.OIDC.Enabled)
+	assert.Equal(t, []string{"openid", "profile", "email"}, cfg.Auth.OIDC.Scopes)
+	assert.Equal(t, "preferred_username", cfg.Auth.OIDC.UsernameClaim)
+	assert.Equal(t, "groups", cfg.Auth.OIDC.GroupsClaim)
+	assert.Empty(t, cfg.Auth.OIDC.AdminGroups)
+	assert.False(t, cfg.Auth.OIDC.AllowRegistration)
+
+	assert.Equal(t, []string{"local"}, cfg.Auth.Authenticators)
+	assert.Equal(t, "(&(objectClass=person)(uid={username}))", cfg.Auth.LDAP.UserFilter)
+	assert.Equal(t, "uid", cfg.Auth.LDAP.UsernameAttribute)
+	assert.Equal(t, "mail", cfg.Auth.LDAP.EmailAttribute)
+	assert.Equal(t, "cn", cfg.Auth.LDAP.DisplayNameAttribute)
+	assert.Equal(t, "(&(objectClass=groupOfNames)(member={dn}))", cfg.Auth.LDAP.GroupFilter)
+	assert.Equal(t, "cn", cfg.Auth.LDAP.GroupNameAttribute)
+	assert.Empty(t, cfg.Auth.LDAP.AdminGroups)
+
+	assert.False(t, cfg.Auth.WebAuthn.Enabled)
+	assert.Equal(t, "tvhgo", cfg.Auth.WebAuthn.RPDisplayName)
+	assert.Equal(t, 5*time.Minute, cfg.Auth.WebAuthn.Timeout)
+
+	assert.True(t, *cfg.Auth.LoginThrottle.Enabled)
+	assert.Equal(t, 5, cfg.Auth.LoginThrottle.MaxAttemptsPerUsername)
+	assert.Equal(t, 20, cfg.Auth.LoginThrottle.MaxAttemptsPerIP)
+	assert.Equal(t, time.Second, cfg.Auth.LoginThrottle.BaseDelay)
+	assert.Equal(t, time.Minute, cfg.Auth.LoginThrottle.MaxDelay)
+	assert.Equal(t, 15*time.Minute, cfg.Auth.LoginThrottle.LockoutDuration)
+	assert.False(t, cfg.Auth.Device.Enabled)
+	assert.Equal(t, 10*time.Minute, cfg.Auth.Device.CodeLifetime)
+	assert.Equal(t, 5*time.Second, cfg.Auth.Device.PollInterval)
+	assert.Equal(t, 1000, cfg.Auth.Device.MaxPendingCodes)
+	assert.Equal(t, 5, cfg.Auth.Device.MaxPendingCodesPerIP)
+
+	assert.False(t, cfg.Auth.TrustedDevice.Enabled)
+	assert.Equal(t, "tvhgo_trusted_device", cfg.Auth.TrustedDevice.CookieName)
+	assert.Equal(t, 30*24*time.Hour, cfg.Auth.TrustedDevice.MaxLifetime)
+
+	assert.Zero(t, cfg.Streaming.MaxStreamsPerUser)
+	assert.Zero(t, cfg.Streaming.MaxStreams)
+	assert.Equal(t, 8<<20, cfg.Streaming.ClientBufferSize)
+	assert.False(t, cfg.Streaming.TunerProtection.Enabled)
+	assert.Equal(t, 30*time.Minute, cfg.Streaming.TunerProtection.Lookahead)
+	assert.Zero(t, cfg.Streaming.TunerProtection.Tuners)
+	assert.Equal(t, 4*time.Second, cfg.Streaming.HLS.SegmentDuration)
+	assert.Equal(t, 6, cfg.Streaming.HLS.PlaylistSize)
+	assert.Equal(t, 30*time.Second, cfg.Streaming.HLS.IdleTimeout)
+	assert.Equal(t, 20*time.Second, cfg.Streaming.HLS.StartTimeout)
 }
 
 func TestLoadFailsForNoTvheadendHost(t *testing.T) {
@@ -63,6 +141,98 @@ func TestLoadFailsForNoTvheadendHost(t *testing.T) {
 	assert.Nil(t, cfg)
 }
 
+func TestLoadFailsForUnknownTvheadendAuthMethod(t *testing.T) {
+	defer os.Clearenv()
+	os.Setenv("TVHGO_TVHEADEND_HOST", "localhost")
+	os.Setenv("TVHGO_TVHEADEND_AUTH_METHOD", "ntlm")
+
+	cfg, err := config.Load("")
+
+	assert.EqualError(t, err, "unknown tvheadend auth method: ntlm")
+	assert.Nil(t, cfg)
+}
+
+func TestLoadFailsForTvheadendClientCertificateWithoutKey(t *testing.T) {
+	defer os.Clearenv()
+	os.Setenv("TVHGO_TVHEADEND_HOST", "localhost")
+	os.Setenv("TVHGO_TVHEADEND_TLS_CERT_FILE", "/etc/tvhgo/client.pem")
+
+	cfg, err := config.Load("")
+
+	assert.EqualError(t, err, "tvheadend tls cert file and key file must be set together")
+	assert.Nil(t, cfg)
+}
+
+func TestLoadFailsForInvalidTvheadendRetryDelays(t *testing.T) {
+	defer os.Clearenv()
+	os.Setenv("TVHGO_TVHEADEND_HOST", "localhost")
+	os.Setenv("TVHGO_TVHEADEND_RETRY_BASE_DELAY", "5s")
+	os.Setenv("TVHGO_TVHEADEND_RETRY_MAX_DELAY", "1s")
+
+	cfg, err := config.Load("")
+
+	assert.EqualError(t, err, "tvheadend retry max delay must not be less than base delay")
+	assert.Nil(t, cfg)
+}
+
+func TestLoadFailsForNegativeTvheadendMaxConcurrentRequests(t *testing.T) {
+	defer os.Clearenv()
+	os.Setenv("TVHGO_TVHEADEND_HOST", "localhost")
+	os.Setenv("TVHGO_TVHEADEND_MAX_CONCURRENT_REQUESTS", "-1")
+
+	cfg, err := config.Load("")
+
+	assert.EqualError(t, err, "tvheadend max concurrent requests must be at least 1")
+	assert.Nil(t, cfg)
+}
+
+func TestLoadFailsForIncompleteOIDCConfig(t *testing.T) {
+	defer os.Clearenv()
+	os.Setenv("TVHGO_TVHEADEND_HOST", "localhost")
+	os.Setenv("TVHGO_AUTH_OIDC_ENABLED", "true")
+	os.Setenv("TVHGO_AUTH_OIDC_ISSUER", "https://auth.example.com")
+
+	cfg, err := config.Load("")
+
+	assert.EqualError(t, err, "oidc issuer, client id and redirect url must be set")
+	assert.Nil(t, cfg)
+}
+
+func TestLoadFailsForIncompleteLDAPConfig(t *testing.T) {
+	defer os.Clearenv()
+	os.Setenv("TVHGO_TVHEADEND_HOST", "localhost")
+	os.Setenv("TVHGO_AUTH_AUTHENTICATORS", "local,ldap")
+	os.Setenv("TVHGO_AUTH_LDAP_URL", "ldap://localhost")
+
+	cfg, err := config.Load("")
+
+	assert.EqualError(t, err, "ldap url and base dn must be set")
+	assert.Nil(t, cfg)
+}
+
+func TestLoadFailsForIncompleteWebAuthnConfig(t *testing.T) {
+	defer os.Clearenv()
+	os.Setenv("TVHGO_TVHEADEND_HOST", "localhost")
+	os.Setenv("TVHGO_AUTH_WEBAUTHN_ENABLED", "true")
+	os.Setenv("TVHGO_AUTH_WEBAUTHN_RP_ID", "tvhgo.example.com")
+
+	cfg, err := config.Load("")
+
+	assert.EqualError(t, err, "webauthn rp id and rp origins must be set")
+	assert.Nil(t, cfg)
+}
+
+func TestLoadFailsForUnknownAuthenticator(t *testing.T) {
+	defer os.Clearenv()
+	os.Setenv("TVHGO_TVHEADEND_HOST", "localhost")
+	os.Setenv("TVHGO_AUTH_AUTHENTICATORS", "local,unknown")
+
+	cfg, err := config.Load("")
+
+	assert.EqualError(t, err, "unknown authenticator: unknown")
+	assert.Nil(t, cfg)
+}
+
 func TestLoadFailsForWhenSamePortForServerAndMetricsIsSet(t *testing.T) {
 	defer os.Clearenv()
 	os.Setenv("TVHGO_TVHEADEND_HOST", "localhost")
@@ -75,17 +245,92 @@ func TestLoadFailsForWhenSamePortForServerAndMetricsIsSet(t *testing.T) {
 	assert.Nil(t, cfg)
 }
 
+func TestLoadPasswordConfig(t *testing.T) {
+	defer os.Clearenv()
+	os.Setenv("TVHGO_TVHEADEND_HOST", "localhost")
+	os.Setenv("TVHGO_AUTH_PASSWORD_ALGORITHM", "bcrypt")
+	os.Setenv("TVHGO_AUTH_PASSWORD_ARGON2ID_MEMORY", "131072")
+	os.Setenv("TVHGO_AUTH_PASSWORD_ARGON2ID_ITERATIONS", "4")
+	os.Setenv("TVHGO_AUTH_PASSWORD_ARGON2ID_PARALLELISM", "1")
+	os.Setenv("TVHGO_AUTH_PASSWORD_BCRYPT_COST", "14")
+
+	cfg, err := config.Load("")
+
+	assert.Nil(t, err)
+	assert.Equal(t, config.PasswordAlgorithmBcrypt, cfg.Auth.Password.Algorithm)
+	assert.Equal(t, uint32(131072), cfg.Auth.Password.Argon2id.Memory)
+	assert.Equal(t, uint32(4), cfg.Auth.Password.Argon2id.Iterations)
+	assert.Equal(t, uint8(1), cfg.Auth.Password.Argon2id.Parallelism)
+	assert.Equal(t, 14, cfg.Auth.Password.BcryptCost)
+}
+
+func TestLoadFailsForUnknownPasswordAlgorithm(t *testing.T) {
+	defer os.Clearenv()
+	os.Setenv("TVHGO_TVHEADEND_HOST", "localhost")
+	os.Setenv("TVHGO_AUTH_PASSWORD_ALGORITHM", "md5")
+
+	cfg, err := config.Load("")
+
+	assert.EqualError(t, err, "unknown password algorithm: md5")
+	assert.Nil(t, cfg)
+}
+
+func TestLoadFailsForTooHighArgon2idMemory(t *testing.T) {
+	defer os.Clearenv()
+	os.Setenv("TVHGO_TVHEADEND_HOST", "localhost")
+	os.Setenv("TVHGO_AUTH_PASSWORD_ARGON2ID_MEMORY", "2097152")
+
+	cfg, err := config.Load("")
+
+	assert.EqualError(t, err, "password argon2id memory must be at most 1048576")
+	assert.Nil(t, cfg)
+}
+
+func TestLoadFailsForInvalidCORSAllowedOrigin(t *testing.T) {
+	defer os.Clearenv()
+	os.Setenv("TVHGO_TVHEADEND_HOST", "localhost")
+	os.Setenv("TVHGO_SERVER_CORS_ALLOWED_ORIGINS", "*")
+
+	cfg, err := config.Load("")
+
+	assert.EqualError(t, err, "cors allowed origins cannot contain a wildcard")
+	assert.Nil(t, cfg)
+
+	os.Setenv("TVHGO_SERVER_CORS_ALLOWED_ORIGINS", "app.example.com")
+
+	cfg, err = config.Load("")
+
+	assert.EqualError(t, err, "invalid cors allowed origin: app.example.com")
+	assert.Nil(t, cfg)
+}
+
 func TestLoadConfigFromEnv(t *testing.T) {
 	defer os.Clearenv()
 	os.Setenv("TVHGO_TVHEADEND_HOST", "localhost")
 	os.Setenv("TVHGO_TVHEADEND_PORT", "1234")
 	os.Setenv("TVHGO_TVHEADEND_SCHEME", "https")
 	os.Setenv("TVHGO_TVHEADEND_USERNAME", "someTvheadendUsername")
+	os.Setenv("TVHGO_TVHEADEND_AUTH_METHOD", "digest")
+	os.Setenv("TVHGO_TVHEADEND_TLS_CA_FILE", "/etc/tvhgo/ca.pem")
+	os.Setenv("TVHGO_TVHEADEND_TLS_CERT_FILE", "/etc/tvhgo/client.pem")
+	os.Setenv("TVHGO_TVHEADEND_TLS_KEY_FILE", "/etc/tvhgo/client-key.pem")
+	os.Setenv("TVHGO_TVHEADEND_TLS_INSECURE_SKIP_VERIFY", "true")
 	os.Setenv("TVHGO_TVHEADEND_PASSWORD", "password")
+	os.Setenv("TVHGO_TVHEADEND_RETRY_ENABLED", "false")
+	os.Setenv("TVHGO_TVHEADEND_RETRY_MAX_ATTEMPTS", "5")
+	os.Setenv("TVHGO_TVHEADEND_CIRCUIT_BREAKER_FAILURE_THRESHOLD", "10")
+	os.Setenv("TVHGO_TVHEADEND_CIRCUIT_BREAKER_OPEN_TIMEOUT", "1m")
+	os.Setenv("TVHGO_TVHEADEND_MAX_CONCURRENT_REQUESTS", "4")
 
 	os.Setenv("TVHGO_SERVER_HOST", "0.0.0.0")
 	os.Setenv("TVHGO_SERVER_PORT", "9999")
 	os.Setenv("TVHGO_SERVER_SWAGGER_UI_ENABLED", "true")
+	os.Setenv("TVHGO_SERVER_CORS_ALLOWED_ORIGINS", "https://app.example.com,http://localhost:3000")
+	os.Setenv("TVHGO_SERVER_CSRF_ENABLED", "false")
+	os.Setenv("TVHGO_SERVER_SECURITY_HEADERS_CONTENT_SECURITY_POLICY", "default-src 'none'")
+	os.Setenv("TVHGO_SERVER_SECURITY_HEADERS_FRAME_OPTIONS", "SAMEORIGIN")
+	os.Setenv("TVHGO_SERVER_SECURITY_HEADERS_HSTS_MAX_AGE", "8760h")
+	os.Setenv("TVHGO_SERVER_SECURITY_HEADERS_TRUST_FORWARDED_PROTO", "true")
 
 	os.Setenv("TVHGO_DATABASE_PATH", "/tmp/tvhgo.db")
 
@@ -110,22 +355,106 @@ func TestLoadConfigFromEnv(t *testing.T) {
 	os.Setenv("TVHGO_AUTH_REVERSE_PROXY_USER_HEADER", "X-Remote-User")
 	os.Setenv("TVHGO_AUTH_REVERSE_PROXY_EMAIL_HEADER", "X-Remote-Email")
 	os.Setenv("TVHGO_AUTH_REVERSE_PROXY_NAME_HEADER", "X-Remote-Name")
+	os.Setenv("TVHGO_AUTH_REVERSE_PROXY_GROUPS_HEADER", "X-Remote-Groups")
+	os.Setenv("TVHGO_AUTH_REVERSE_PROXY_ADMIN_GROUPS", "admins,operators")
+	os.Setenv("TVHGO_AUTH_REVERSE_PROXY_GROUP_ROLES", "family:recorder,guests:viewer")
+	os.Setenv("TVHGO_AUTH_REVERSE_PROXY_DEFAULT_ROLE", "viewer")
 	os.Setenv("TVHGO_AUTH_REVERSE_PROXY_ALLOWED_PROXIES", "127.0.0.1/24,127.0.0.1")
 	os.Setenv("TVHGO_AUTH_REVERSE_PROXY_ALLOW_REGISTRATION", "true")
 
+	os.Setenv("TVHGO_AUTH_STREAM_SIGNING_KEYS", "newKey,oldKey")
+	os.Setenv("TVHGO_AUTH_STREAM_SIGNING_TTL", "1h")
+
+	os.Setenv("TVHGO_AUTH_OIDC_ENABLED", "true")
+	os.Setenv("TVHGO_AUTH_OIDC_ISSUER", "https://auth.example.com")
+	os.Setenv("TVHGO_AUTH_OIDC_CLIENT_ID", "tvhgo")
+	os.Setenv("TVHGO_AUTH_OIDC_CLIENT_SECRET", "secret")
+	os.Setenv("TVHGO_AUTH_OIDC_REDIRECT_URL", "https://tvhgo.example.com/api/auth/oidc/callback")
+	os.Setenv("TVHGO_AUTH_OIDC_SCOPES", "openid,groups")
+	os.Setenv("TVHGO_AUTH_OIDC_USERNAME_CLAIM", "sub")
+	os.Setenv("TVHGO_AUTH_OIDC_GROUPS_CLAIM", "roles")
+	os.Setenv("TVHGO_AUTH_OIDC_ADMIN_GROUPS", "admins,tv-admins")
+	os.Setenv("TVHGO_AUTH_OIDC_ALLOW_REGISTRATION", "true")
+
+	os.Setenv("TVHGO_AUTH_AUTHENTICATORS", "ldap,local")
+	os.Setenv("TVHGO_AUTH_LDAP_URL", "ldaps://ldap.example.com")
+	os.Setenv("TVHGO_AUTH_LDAP_START_TLS", "true")
+	os.Setenv("TVHGO_AUTH_LDAP_INSECURE_SKIP_VERIFY", "true")
+	os.Setenv("TVHGO_AUTH_LDAP_BIND_DN", "cn=tvhgo,dc=example,dc=com")
+	os.Setenv("TVHGO_AUTH_LDAP_BIND_PASSWORD", "secret")
+	os.Setenv("TVHGO_AUTH_LDAP_BASE_DN", "ou=users,dc=example,dc=com")
+	os.Setenv("TVHGO_AUTH_LDAP_USER_FILTER", "(sAMAccountName={username})")
+	os.Setenv("TVHGO_AUTH_LDAP_USERNAME_ATTRIBUTE", "sAMAccountName")
+	os.Setenv("TVHGO_AUTH_LDAP_EMAIL_ATTRIBUTE", "email")
+	os.Setenv("TVHGO_AUTH_LDAP_DISPLAY_NAME_ATTRIBUTE", "displayName")
+	os.Setenv("TVHGO_AUTH_LDAP_GROUP_BASE_DN", "ou=groups,dc=example,dc=com")
+	os.Setenv("TVHGO_AUTH_LDAP_GROUP_FILTER", "(member={dn})")
+	os.Setenv("TVHGO_AUTH_LDAP_GROUP_NAME_ATTRIBUTE", "name")
+	os.Setenv("TVHGO_AUTH_LDAP_ADMIN_GROUPS", "tv-admins")
+
+	os.Setenv("TVHGO_AUTH_WEBAUTHN_ENABLED", "true")
+	os.Setenv("TVHGO_AUTH_WEBAUTHN_RP_ID", "tvhgo.example.com")
+	os.Setenv("TVHGO_AUTH_WEBAUTHN_RP_DISPLAY_NAME", "My tvhgo")
+	os.Setenv("TVHGO_AUTH_WEBAUTHN_RP_ORIGINS", "https://tvhgo.example.com")
+	os.Setenv("TVHGO_AUTH_WEBAUTHN_TIMEOUT", "2m")
+
+	os.Setenv("TVHGO_AUTH_LOGIN_THROTTLE_ENABLED", "false")
+	os.Setenv("TVHGO_AUTH_LOGIN_THROTTLE_MAX_ATTEMPTS_PER_USERNAME", "3")
+	os.Setenv("TVHGO_AUTH_LOGIN_THROTTLE_MAX_ATTEMPTS_PER_IP", "10")
+	os.Setenv("TVHGO_AUTH_LOGIN_THROTTLE_BASE_DELAY", "2s")
+	os.Setenv("TVHGO_AUTH_LOGIN_THROTTLE_MAX_DELAY", "30s")
+	os.Setenv("TVHGO_AUTH_LOGIN_THROTTLE_LOCKOUT_DURATION", "1h")
+	os.Setenv("TVHGO_AUTH_DEVICE_ENABLED", "true")
+	os.Setenv("TVHGO_AUTH_DEVICE_VERIFICATION_URL", "https://tvhgo.example.com/device")
+	os.Setenv("TVHGO_AUTH_DEVICE_CODE_LIFETIME", "5m")
+	os.Setenv("TVHGO_AUTH_DEVICE_POLL_INTERVAL", "10s")
+	os.Setenv("TVHGO_AUTH_DEVICE_MAX_PENDING_CODES", "100")
+	os.Setenv("TVHGO_AUTH_DEVICE_MAX_PENDING_CODES_PER_IP", "2")
+
+	os.Setenv("TVHGO_AUTH_TRUSTED_DEVICE_ENABLED", "true")
+	os.Setenv("TVHGO_AUTH_TRUSTED_DEVICE_COOKIE_NAME", "myTrustedDevice")
+	os.Setenv("TVHGO_AUTH_TRUSTED_DEVICE_MAX_LIFETIME", "168h")
+
+	os.Setenv("TVHGO_STREAMING_MAX_STREAMS_PER_USER", "2")
+	os.Setenv("TVHGO_STREAMING_MAX_STREAMS", "4")
+	os.Setenv("TVHGO_STREAMING_CLIENT_BUFFER_SIZE", "1048576")
+	os.Setenv("TVHGO_STREAMING_TUNER_PROTECTION_ENABLED", "true")
+	os.Setenv("TVHGO_STREAMING_TUNER_PROTECTION_LOOKAHEAD", "1h")
+	os.Setenv("TVHGO_STREAMING_TUNER_PROTECTION_TUNERS", "3")
+	os.Setenv("TVHGO_STREAMING_HLS_SEGMENT_DURATION", "6s")
+	os.Setenv("TVHGO_STREAMING_HLS_PLAYLIST_SIZE", "10")
+	os.Setenv("TVHGO_STREAMING_HLS_IDLE_TIMEOUT", "1m")
+	os.Setenv("TVHGO_STREAMING_HLS_START_TIMEOUT", "10s")
+
 	cfg, err := config.Load("")
 
 	assert.Nil(t, err)
 	assert.Equal(t, "localhost", cfg.Tvheadend.Host)
 	assert.Equal(t, 1234, cfg.Tvheadend.Port)
 	assert.Equal(t, "https", cfg.Tvheadend.Scheme)
+	assert.Equal(t, config.TvheadendAuthMethodDigest, cfg.Tvheadend.AuthMethod)
+	assert.Equal(t, "/etc/tvhgo/ca.pem", cfg.Tvheadend.TLS.CAFile)
+	assert.Equal(t, "/etc/tvhgo/client.pem", cfg.Tvheadend.TLS.CertFile)
+	assert.Equal(t, "/etc/tvhgo/client-key.pem", cfg.Tvheadend.TLS.KeyFile)
+	assert.True(t, cfg.Tvheadend.TLS.InsecureSkipVerify)
 	assert.Equal(t, "someTvheadendUsername", cfg.Tvheadend.Username)
 	assert.Equal(t, "password", cfg.Tvheadend.Password)
+	assert.False(t, *cfg.Tvheadend.Retry.Enabled)
+	assert.Equal(t, 5, cfg.Tvheadend.Retry.MaxAttempts)
+	assert.Equal(t, 10, cfg.Tvheadend.CircuitBreaker.FailureThreshold)
+	assert.Equal(t, time.Minute, cfg.Tvheadend.CircuitBreaker.OpenTimeout)
+	assert.Equal(t, 4, cfg.Tvheadend.MaxConcurrentRequests)
 
 	enableSwaggerUI := true
 	assert.Equal(t, "0.0.0.0", cfg.Server.Host)
 	assert.Equal(t, 9999, cfg.Server.Port)
 	assert.Equal(t, &enableSwaggerUI, cfg.Server.SwaggerUI.Enabled)
+	assert.Equal(t, []string{"https://app.example.com", "http://localhost:3000"}, cfg.Server.CORS.AllowedOrigins)
+	assert.False(t, *cfg.Server.CSRF.Enabled)
+	assert.Equal(t, "default-src 'none'", cfg.Server.SecurityHeaders.ContentSecurityPolicy)
+	assert.Equal(t, "SAMEORIGIN", cfg.Server.SecurityHeaders.FrameOptions)
+	assert.Equal(t, 8760*time.Hour, cfg.Server.SecurityHeaders.HSTSMaxAge)
+	assert.True(t, cfg.Server.SecurityHeaders.TrustForwardedProto)
 
 	assert.Equal(t, "/tmp/tvhgo.db", cfg.Database.Path)
 
@@ -150,8 +479,76 @@ func TestLoadConfigFromEnv(t *testing.T) {
 	assert.Equal(t, "X-Remote-User", cfg.Auth.ReverseProxy.UserHeader)
 	assert.Equal(t, "X-Remote-Email", cfg.Auth.ReverseProxy.EmailHeader)
 	assert.Equal(t, "X-Remote-Name", cfg.Auth.ReverseProxy.NameHeader)
+	assert.Equal(t, "X-Remote-Groups", cfg.Auth.ReverseProxy.GroupsHeader)
+	assert.Equal(t, []string{"admins", "operators"}, cfg.Auth.ReverseProxy.AdminGroups)
+	assert.Equal(t, map[string]string{"family": "recorder", "guests": "viewer"}, cfg.Auth.ReverseProxy.GroupRoles)
+	assert.Equal(t, "viewer", cfg.Auth.ReverseProxy.DefaultRole)
 	assert.Contains(t, cfg.Auth.ReverseProxy.AllowedProxies, "127.0.0.1/24", "127.0.0.1")
 	assert.True(t, cfg.Auth.ReverseProxy.AllowRegistration)
+
+	assert.Equal(t, []string{"newKey", "oldKey"}, cfg.Auth.StreamSigning.Keys)
+	assert.Equal(t, 1*time.Hour, cfg.Auth.StreamSigning.TTL)
+
+	assert.True(t, cfg.Auth.OIDC.Enabled)
+	assert.Equal(t, "https://auth.example.com", cfg.Auth.OIDC.Issuer)
+	assert.Equal(t, "tvhgo", cfg.Auth.OIDC.ClientID)
+	assert.Equal(t, "secret", cfg.Auth.OIDC.ClientSecret)
+	assert.Equal(t, "https://tvhgo.example.com/api/auth/oidc/callback", cfg.Auth.OIDC.RedirectURL)
+	assert.Equal(t, []string{"openid", "groups"}, cfg.Auth.OIDC.Scopes)
+	assert.Equal(t, "sub", cfg.Auth.OIDC.UsernameClaim)
+	assert.Equal(t, "roles", cfg.Auth.OIDC.GroupsClaim)
+	assert.Equal(t, []string{"admins", "tv-admins"}, cfg.Auth.OIDC.AdminGroups)
+	assert.True(t, cfg.Auth.OIDC.AllowRegistration)
+
+	assert.Equal(t, []string{"ldap", "local"}, cfg.Auth.Authenticators)
+	assert.Equal(t, "ldaps://ldap.example.com", cfg.Auth.LDAP.URL)
+	assert.True(t, cfg.Auth.LDAP.StartTLS)
+	assert.True(t, cfg.Auth.LDAP.InsecureSkipVerify)
+	assert.Equal(t, "cn=tvhgo,dc=example,dc=com", cfg.Auth.LDAP.BindDN)
+	assert.Equal(t, "secret", cfg.Auth.LDAP.BindPassword)
+	assert.Equal(t, "ou=users,dc=example,dc=com", cfg.Auth.LDAP.BaseDN)
+	assert.Equal(t, "(sAMAccountName={username})", cfg.Auth.LDAP.UserFilter)
+	assert.Equal(t, "sAMAccountName", cfg.Auth.LDAP.UsernameAttribute)
+	assert.Equal(t, "email", cfg.Auth.LDAP.EmailAttribute)
+	assert.Equal(t, "displayName", cfg.Auth.LDAP.DisplayNameAttribute)
+	assert.Equal(t, "ou=groups,dc=example,dc=com", cfg.Auth.LDAP.GroupBaseDN)
+	assert.Equal(t, "(member={dn})", cfg.Auth.LDAP.GroupFilter)
+	assert.Equal(t, "name", cfg.Auth.LDAP.GroupNameAttribute)
+	assert.Equal(t, []string{"tv-admins"}, cfg.Auth.LDAP.AdminGroups)
+
+	assert.True(t, cfg.Auth.WebAuthn.Enabled)
+	assert.Equal(t, "tvhgo.example.com", cfg.Auth.WebAuthn.RPID)
+	assert.Equal(t, "My tvhgo", cfg.Auth.WebAuthn.RPDisplayName)
+	assert.Equal(t, []string{"https://tvhgo.example.com"}, cfg.Auth.WebAuthn.RPOrigins)
+	assert.Equal(t, 2*time.Minute, cfg.Auth.WebAuthn.Timeout)
+
+	assert.False(t, *cfg.Auth.LoginThrottle.Enabled)
+	assert.Equal(t, 3, cfg.Auth.LoginThrottle.MaxAttemptsPerUsername)
+	assert.Equal(t, 10, cfg.Auth.LoginThrottle.MaxAttemptsPerIP)
+	assert.Equal(t, 2*time.Second, cfg.Auth.LoginThrottle.BaseDelay)
+	assert.Equal(t, 30*time.Second, cfg.Auth.LoginThrottle.MaxDelay)
+	assert.Equal(t, 1*time.Hour, cfg.Auth.LoginThrottle.LockoutDuration)
+	assert.True(t, cfg.Auth.Device.Enabled)
+	assert.Equal(t, "https://tvhgo.example.com/device", cfg.Auth.Device.VerificationURL)
+	assert.Equal(t, 5*time.Minute, cfg.Auth.Device.CodeLifetime)
+	assert.Equal(t, 10*time.Second, cfg.Auth.Device.PollInterval)
+	assert.Equal(t, 100, cfg.Auth.Device.MaxPendingCodes)
+	assert.Equal(t, 2, cfg.Auth.Device.MaxPendingCodesPerIP)
+
+	assert.True(t, cfg.Auth.TrustedDevice.Enabled)
+	assert.Equal(t, "myTrustedDevice", cfg.Auth.TrustedDevice.CookieName)
+	assert.Equal(t, 7*24*time.Hour, cfg.Auth.TrustedDevice.MaxLifetime)
+
+	assert.Equal(t, 2, cfg.Streaming.MaxStreamsPerUser)
+	assert.Equal(t, 4, cfg.Streaming.MaxStreams)
+	assert.Equal(t, 1048576, cfg.Streaming.ClientBufferSize)
+	assert.True(t, cfg.Streaming.TunerProtection.Enabled)
+	assert.Equal(t, 1*time.Hour, cfg.Streaming.TunerProtection.Lookahead)
+	assert.Equal(t, 3, cfg.Streaming.TunerProtection.Tuners)
+	assert.Equal(t, 6*time.Second, cfg.Streaming.HLS.SegmentDuration)
+	assert.Equal(t, 10, cfg.Streaming.HLS.PlaylistSize)
+	assert.Equal(t, 1*time.Minute, cfg.Streaming.HLS.IdleTimeout)
+	assert.Equal(t, 10*time.Second, cfg.Streaming.HLS.StartTimeout)
 }
 
 func TestLoadPostgresDatabaseConfigDefaults(t *testing.T) {
@@ -197,3 +594,32 @@ func TestLoadPostgresDatabaseConfig(t *testing.T) {
 	assert.Equal(t, "myPassword", cfg.Database.Password)
 	assert.Equal(t, "require", cfg.Database.SSLMode)
 }
+
+func TestLoadDatabaseEncryptionConfig(t *testing.T) {
+	defer os.Clearenv()
+	// Set required env variables
+	os.Setenv("TVHGO_TVHEADEND_HOST", "localhost")
+
+	os.Setenv("TVHGO_DATABASE_ENCRYPTION_KEYS", "3Xb4yJt0hN1bqk8m4u5Y7v2cB6n9QeRtYuIoPaSdF0g=,k9O6ZYnWgmq8RkQ4gOZcjX0bYvE2xq6e5OZ0zYcQdS0=")
+	os.Setenv("TVHGO_DATABASE_ENCRYPTION_KEY_FILE", "/run/secrets/tvhgo_keys")
+	cfg, err := config.Load("")
+
+	assert.Nil(t, err)
+
+	assert.Equal(t, []string{
+		"3Xb4yJt0hN1bqk8m4u5Y7v2cB6n9QeRtYuIoPaSdF0g=",
+		"k9O6ZYnWgmq8RkQ4gOZcjX0bYvE2xq6e5OZ0zYcQdS0=",
+	}, cfg.Database.Encryption.Keys)
+	assert.Equal(t, "/run/secrets/tvhgo_keys", cfg.Database.Encryption.KeyFile)
+}
+
+func TestLoadFailsForInvalidDatabaseEncryptionKey(t *testing.T) {
+	defer os.Clearenv()
+	os.Setenv("TVHGO_TVHEADEND_HOST", "localhost")
+	os.Setenv("TVHGO_DATABASE_ENCRYPTION_KEYS", "someKey")
+
+	cfg, err := config.Load("")
+
+	assert.EqualError(t, err, "database encryption keys must be base64 encoded 32 byte keys")
+	assert.Nil(t, cfg)
+}
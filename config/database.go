@@ -1,6 +1,8 @@
 package config
 
 import (
+	"encoding/base64"
+	"errors"
 	"fmt"
 	"net/url"
 )
@@ -37,6 +39,19 @@ type (
 		Database string `yaml:"database" env:"DATABASE"`
 		// SSLMode is the SSL mode for the connection (only for postgres).
 		SSLMode string `yaml:"ssl_mode" env:"SSL_MODE"`
+		// Encryption configures the encryption of sensitive columns.
+		Encryption EncryptionConfig `yaml:"encryption" envPrefix:"ENCRYPTION_"`
+	}
+
+	// EncryptionConfig configures the encryption of sensitive
+	// columns (e.g. totp secrets) at rest with AES-256-GCM.
+	EncryptionConfig struct {
+		// Keys are base64 encoded 32 byte keys. The first key is used to
+		// encrypt new values, all keys are used to decrypt values.
+		Keys []string `yaml:"keys" env:"KEYS"`
+		// KeyFile is the path to a file with one key per line. Its keys
+		// are used after the keys configured by Keys.
+		KeyFile string `yaml:"key_file" env:"KEY_FILE"`
 	}
 )
 
@@ -48,6 +63,18 @@ func (c *DatabaseConfig) Validate() error {
 	return nil
 }
 
+// Validate checks if all configured keys are base64 encoded 32 byte keys.
+func (c *EncryptionConfig) Validate() error {
+	for _, key := range c.Keys {
+		decoded, err := base64.StdEncoding.DecodeString(key)
+		if err != nil || len(decoded) != 32 {
+			return errors.New("database encryption keys must be base64 encoded 32 byte keys")
+		}
+	}
+
+	return nil
+}
+
 func (c *DatabaseConfig) SetDefaults() {
 	if c.Type == "" {
 		c.Type = DatabaseTypeSqlite
@@ -1,12 +1,21 @@
 package config
 
 import (
+	"errors"
+	"fmt"
 	"net"
+	"net/url"
 	"strconv"
+	"time"
 )
 
 const (
 	defaultServerPort = 8080
+
+	defaultContentSecurityPolicy = "default-src 'self'; script-src 'self'; style-src 'self' 'unsafe-inline'; " +
+		"img-src 'self' data: blob:; media-src 'self' blob:; worker-src 'self' blob:; " +
+		"object-src 'none'; base-uri 'self'; frame-ancestors 'none'"
+	defaultFrameOptions = "DENY"
 )
 
 type (
@@ -14,10 +23,39 @@ type (
 		Enabled *bool `yaml:"enabled" env:"ENABLED"`
 	}
 
+	CORSConfig struct {
+		// AllowedOrigins are the origins (e.g. https://app.example.com),
+		// which are allowed to send cross-origin requests with credentials.
+		// Without allowed origins only same-origin requests are allowed.
+		AllowedOrigins []string `yaml:"allowed_origins" env:"ALLOWED_ORIGINS"`
+	}
+
+	CSRFConfig struct {
+		// Enabled rejects state-changing cross-origin requests,
+		// which are authenticated with the session cookie.
+		Enabled *bool `yaml:"enabled" env:"ENABLED"`
+	}
+
+	SecurityHeadersConfig struct {
+		Enabled               *bool  `yaml:"enabled"                 env:"ENABLED"`
+		ContentSecurityPolicy string `yaml:"content_security_policy" env:"CONTENT_SECURITY_POLICY"`
+		FrameOptions          string `yaml:"frame_options"           env:"FRAME_OPTIONS"`
+		// HSTSMaxAge sets the Strict-Transport-Security header.
+		// It is only set if tvhgo is served via https.
+		HSTSMaxAge time.Duration `yaml:"hsts_max_age" env:"HSTS_MAX_AGE"`
+		// TrustForwardedProto trusts the X-Forwarded-Proto header to detect
+		// https. It must only be enabled behind a reverse proxy, which
+		// overwrites the header of clients.
+		TrustForwardedProto bool `yaml:"trust_forwarded_proto" env:"TRUST_FORWARDED_PROTO"`
+	}
+
 	ServerConfig struct {
-		Host      string          `yaml:"host" env:"HOST"`
-		Port      int             `yaml:"port" env:"PORT"`
-		SwaggerUI SwaggerUIConfig `yaml:"swagger_ui" env:"SWAGGER_UI"`
+		Host            string                `yaml:"host" env:"HOST"`
+		Port            int                   `yaml:"port" env:"PORT"`
+		SwaggerUI       SwaggerUIConfig       `yaml:"swagger_ui" env:"SWAGGER_UI"`
+		CORS            CORSConfig            `yaml:"cors" envPrefix:"CORS_"`
+		CSRF            CSRFConfig            `yaml:"csrf" envPrefix:"CSRF_"`
+		SecurityHeaders SecurityHeadersConfig `yaml:"security_headers" envPrefix:"SECURITY_HEADERS_"`
 	}
 )
 
@@ -30,6 +68,29 @@ func (c *ServerConfig) SetDefaults() {
 		v := true
 		c.SwaggerUI.Enabled = &v
 	}
+
+	if c.CSRF.Enabled == nil {
+		v := true
+		c.CSRF.Enabled = &v
+	}
+
+	c.SecurityHeaders.SetDefaults()
+}
+
+func (c *ServerConfig) Validate() error {
+	for _, origin := range c.CORS.AllowedOrigins {
+		if origin == "*" {
+			return errors.New("cors allowed origins cannot contain a wildcard")
+		}
+
+		u, err := url.Parse(origin)
+		if err != nil || (u.Scheme != "http" && u.Scheme != "https") ||
+			u.Host == "" || (u.Path != "" && u.Path != "/") {
+			return fmt.Errorf("invalid cors allowed origin: %s", origin)
+		}
+	}
+
+	return nil
 }
 
 func (c *ServerConfig) Addr() string {
@@ -38,3 +99,16 @@ func (c *ServerConfig) Addr() string {
 		strconv.Itoa(c.Port),
 	)
 }
+
+func (c *SecurityHeadersConfig) SetDefaults() {
+	if c.Enabled == nil {
+		v := true
+		c.Enabled = &v
+	}
+	if c.ContentSecurityPolicy == "" {
+		c.ContentSecurityPolicy = defaultContentSecurityPolicy
+	}
+	if c.FrameOptions == "" {
+		c.FrameOptions = defaultFrameOptions
+	}
+}
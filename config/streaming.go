@@ -0,0 +1,77 @@
+package config
+
+import "time"
+
+const (
+	defaultTunerProtectionLookahead = 30 * time.Minute
+	defaultClientBufferSize         = 8 << 20
+
+	defaultHLSSegmentDuration = 4 * time.Second
+	defaultHLSPlaylistSize    = 6
+	defaultHLSIdleTimeout     = 30 * time.Second
+	defaultHLSStartTimeout    = 20 * time.Second
+)
+
+type (
+	TunerProtectionConfig struct {
+		// Enabled refuses live streams when the free tuners would drop
+		// below the number of tuners needed for upcoming recordings.
+		Enabled bool `yaml:"enabled"   env:"ENABLED"`
+		// Lookahead is the time window in which upcoming recordings are considered.
+		Lookahead time.Duration `yaml:"lookahead" env:"LOOKAHEAD"`
+		// Tuners is the total number of tuners. When not set, the number
+		// of inputs reported by tvheadend is used.
+		Tuners int `yaml:"tuners"    env:"TUNERS"`
+	}
+
+	HLSConfig struct {
+		// SegmentDuration is the target duration of a segment. Segments are
+		// only cut at keyframes, so the actual duration may be longer.
+		SegmentDuration time.Duration `yaml:"segment_duration" env:"SEGMENT_DURATION"`
+		// PlaylistSize is the number of segments in the sliding window playlist.
+		PlaylistSize int `yaml:"playlist_size"    env:"PLAYLIST_SIZE"`
+		// IdleTimeout is the time after which a stream without viewers is stopped.
+		IdleTimeout time.Duration `yaml:"idle_timeout"     env:"IDLE_TIMEOUT"`
+		// StartTimeout is the maximum time to wait for the first segment.
+		StartTimeout time.Duration `yaml:"start_timeout"    env:"START_TIMEOUT"`
+	}
+
+	StreamingConfig struct {
+		// MaxStreamsPerUser limits the concurrent live streams of a user (0 = unlimited).
+		MaxStreamsPerUser int `yaml:"max_streams_per_user" env:"MAX_STREAMS_PER_USER"`
+		// MaxStreams limits the concurrent live streams of all users (0 = unlimited).
+		MaxStreams int `yaml:"max_streams"      env:"MAX_STREAMS"`
+		// ClientBufferSize is the number of bytes buffered for a client of
+		// a shared channel stream before the client is evicted as too slow.
+		ClientBufferSize int                   `yaml:"client_buffer_size" env:"CLIENT_BUFFER_SIZE"`
+		TunerProtection  TunerProtectionConfig `yaml:"tuner_protection" envPrefix:"TUNER_PROTECTION_"`
+		HLS              HLSConfig             `yaml:"hls"              envPrefix:"HLS_"`
+	}
+)
+
+func (c *StreamingConfig) SetDefaults() {
+	if c.ClientBufferSize == 0 {
+		c.ClientBufferSize = defaultClientBufferSize
+	}
+
+	if c.TunerProtection.Lookahead == 0 {
+		c.TunerProtection.Lookahead = defaultTunerProtectionLookahead
+	}
+
+	c.HLS.SetDefaults()
+}
+
+func (c *HLSConfig) SetDefaults() {
+	if c.SegmentDuration == 0 {
+		c.SegmentDuration = defaultHLSSegmentDuration
+	}
+	if c.PlaylistSize == 0 {
+		c.PlaylistSize = defaultHLSPlaylistSize
+	}
+	if c.IdleTimeout == 0 {
+		c.IdleTimeout = defaultHLSIdleTimeout
+	}
+	if c.StartTimeout == 0 {
+		c.StartTimeout = defaultHLSStartTimeout
+	}
+}
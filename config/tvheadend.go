@@ -2,23 +2,77 @@ package config
 
 import (
 	"errors"
+	"fmt"
 	"net"
 	"net/url"
 	"strconv"
+	"time"
 )
 
 const (
-	defaultTvheadendScheme = "http"
-	defaultTvheadendPort   = 9981
+	TvheadendAuthMethodAuto   = "auto"
+	TvheadendAuthMethodBasic  = "basic"
+	TvheadendAuthMethodDigest = "digest"
+
+	defaultTvheadendScheme     = "http"
+	defaultTvheadendPort       = 9981
+	defaultTvheadendAuthMethod = TvheadendAuthMethodAuto
+
+	defaultTvheadendRetryMaxAttempts               = 3
+	defaultTvheadendRetryBaseDelay                 = 200 * time.Millisecond
+	defaultTvheadendRetryMaxDelay                  = 2 * time.Second
+	defaultTvheadendCircuitBreakerFailureThreshold = 5
+	defaultTvheadendCircuitBreakerOpenTimeout      = 30 * time.Second
+	defaultTvheadendMaxConcurrentRequests          = 16
 )
 
 type (
+	TvheadendTLSConfig struct {
+		// CAFile is a pem encoded ca bundle to verify the certificate of tvheadend.
+		CAFile string `yaml:"ca_file" env:"CA_FILE"`
+		// CertFile and KeyFile are a pem encoded client certificate and key.
+		CertFile           string `yaml:"cert_file"            env:"CERT_FILE"`
+		KeyFile            string `yaml:"key_file"             env:"KEY_FILE"`
+		InsecureSkipVerify bool   `yaml:"insecure_skip_verify" env:"INSECURE_SKIP_VERIFY"`
+	}
+
+	TvheadendRetryConfig struct {
+		// Enabled enables the retries of idempotent requests. Defaults to true.
+		Enabled *bool `yaml:"enabled" env:"ENABLED"`
+		// MaxAttempts is the maximum number of attempts including the first request.
+		MaxAttempts int `yaml:"max_attempts" env:"MAX_ATTEMPTS"`
+		// BaseDelay is the maximum delay before the first retry, which
+		// is doubled on each further retry up to MaxDelay.
+		BaseDelay time.Duration `yaml:"base_delay" env:"BASE_DELAY"`
+		MaxDelay  time.Duration `yaml:"max_delay"  env:"MAX_DELAY"`
+	}
+
+	TvheadendCircuitBreakerConfig struct {
+		// Enabled enables the circuit breaker. Defaults to true.
+		Enabled *bool `yaml:"enabled" env:"ENABLED"`
+		// FailureThreshold is the number of consecutive failed
+		// requests after which the circuit breaker opens.
+		FailureThreshold int `yaml:"failure_threshold" env:"FAILURE_THRESHOLD"`
+		// OpenTimeout is the time requests fail fast, before
+		// a request is let through to check tvheadend again.
+		OpenTimeout time.Duration `yaml:"open_timeout" env:"OPEN_TIMEOUT"`
+	}
+
 	TvheadendConfig struct {
 		Scheme   string `yaml:"scheme"   env:"SCHEME"`
 		Host     string `yaml:"host"     env:"HOST"`
 		Port     int    `yaml:"port"     env:"PORT"`
 		Username string `yaml:"username" env:"USERNAME"`
 		Password string `yaml:"password" env:"PASSWORD,unset"`
+		// AuthMethod defines how the credentials are sent (auto, basic, digest).
+		// auto uses digest auth and falls back to basic auth.
+		AuthMethod string             `yaml:"auth_method" env:"AUTH_METHOD"`
+		TLS        TvheadendTLSConfig `yaml:"tls" envPrefix:"TLS_"`
+		// Retry defines the retries of idempotent requests (grid and load calls).
+		Retry          TvheadendRetryConfig          `yaml:"retry"           envPrefix:"RETRY_"`
+		CircuitBreaker TvheadendCircuitBreakerConfig `yaml:"circuit_breaker" envPrefix:"CIRCUIT_BREAKER_"`
+		// MaxConcurrentRequests caps the number of concurrent requests to tvheadend.
+		MaxConcurrentRequests int `yaml:"max_concurrent_requests" env:"MAX_CONCURRENT_REQUESTS"`
 	}
 )
 
@@ -27,6 +81,36 @@ func (c *TvheadendConfig) Validate() error {
 		return errors.New("tvheadend host is not set")
 	}
 
+	switch c.AuthMethod {
+	case TvheadendAuthMethodAuto, TvheadendAuthMethodBasic, TvheadendAuthMethodDigest:
+	default:
+		return fmt.Errorf("unknown tvheadend auth method: %s", c.AuthMethod)
+	}
+
+	if (c.TLS.CertFile == "") != (c.TLS.KeyFile == "") {
+		return errors.New("tvheadend tls cert file and key file must be set together")
+	}
+
+	if c.Retry.MaxAttempts < 1 {
+		return errors.New("tvheadend retry max attempts must be at least 1")
+	}
+
+	if c.Retry.BaseDelay < 0 || c.Retry.MaxDelay < c.Retry.BaseDelay {
+		return errors.New("tvheadend retry max delay must not be less than base delay")
+	}
+
+	if c.CircuitBreaker.FailureThreshold < 1 {
+		return errors.New("tvheadend circuit breaker failure threshold must be at least 1")
+	}
+
+	if c.CircuitBreaker.OpenTimeout < 0 {
+		return errors.New("tvheadend circuit breaker open timeout must not be negative")
+	}
+
+	if c.MaxConcurrentRequests < 1 {
+		return errors.New("tvheadend max concurrent requests must be at least 1")
+	}
+
 	return nil
 }
 
@@ -37,6 +121,44 @@ func (c *TvheadendConfig) SetDefaults() {
 	if c.Port == 0 {
 		c.Port = defaultTvheadendPort
 	}
+	if c.AuthMethod == "" {
+		c.AuthMethod = defaultTvheadendAuthMethod
+	}
+	if c.MaxConcurrentRequests == 0 {
+		c.MaxConcurrentRequests = defaultTvheadendMaxConcurrentRequests
+	}
+
+	c.Retry.SetDefaults()
+	c.CircuitBreaker.SetDefaults()
+}
+
+func (c *TvheadendRetryConfig) SetDefaults() {
+	if c.Enabled == nil {
+		v := true
+		c.Enabled = &v
+	}
+	if c.MaxAttempts == 0 {
+		c.MaxAttempts = defaultTvheadendRetryMaxAttempts
+	}
+	if c.BaseDelay == 0 {
+		c.BaseDelay = defaultTvheadendRetryBaseDelay
+	}
+	if c.MaxDelay == 0 {
+		c.MaxDelay = defaultTvheadendRetryMaxDelay
+	}
+}
+
+func (c *TvheadendCircuitBreakerConfig) SetDefaults() {
+	if c.Enabled == nil {
+		v := true
+		c.Enabled = &v
+	}
+	if c.FailureThreshold == 0 {
+		c.FailureThreshold = defaultTvheadendCircuitBreakerFailureThreshold
+	}
+	if c.OpenTimeout == 0 {
+		c.OpenTimeout = defaultTvheadendCircuitBreakerOpenTimeout
+	}
 }
 
 func (c *TvheadendConfig) URL() string {
@@ -5,11 +5,13 @@ import (
 )
 
 var (
-	ErrInterfaceToStringMap = errors.New("converting interface to map[string]string failed")
-	ErrInterfaceToString    = errors.New("converting interface to string failed")
-	ErrInterfaceToBool      = errors.New("converting interface to bool failed")
-	ErrInterfaceToInt64     = errors.New("converting interface to int64 failed")
-	ErrInterfaceToInt       = errors.New("converting interface to int failed")
+	ErrInterfaceToStringMap   = errors.New("converting interface to map[string]string failed")
+	ErrInterfaceToString      = errors.New("converting interface to string failed")
+	ErrInterfaceToBool        = errors.New("converting interface to bool failed")
+	ErrInterfaceToInt64       = errors.New("converting interface to int64 failed")
+	ErrInterfaceToInt         = errors.New("converting interface to int failed")
+	ErrInterfaceToStringSlice = errors.New("converting interface to []string failed")
+	ErrInterfaceToIntSlice    = errors.New("converting interface to []int failed")
 )
 
 // InterfaceToStringMap converts a interface to map[string]string.
@@ -89,3 +91,51 @@ func InterfaceToInt(in interface{}) (int, error) {
 
 	return int(out), nil
 }
+
+// InterfaceToStringSlice converts a interface from
+// a json un-marshaled struct to a []string.
+func InterfaceToStringSlice(in interface{}) ([]string, error) {
+	if in == nil {
+		return []string{}, nil
+	}
+
+	values, ok := in.([]interface{})
+	if !ok {
+		return nil, ErrInterfaceToStringSlice
+	}
+
+	out := make([]string, 0, len(values))
+	for _, value := range values {
+		str, ok := value.(string)
+		if !ok {
+			return nil, ErrInterfaceToStringSlice
+		}
+		out = append(out, str)
+	}
+
+	return out, nil
+}
+
+// InterfaceToIntSlice converts a interface from
+// a json un-marshaled struct to a []int.
+func InterfaceToIntSlice(in interface{}) ([]int, error) {
+	if in == nil {
+		return []int{}, nil
+	}
+
+	values, ok := in.([]interface{})
+	if !ok {
+		return nil, ErrInterfaceToIntSlice
+	}
+
+	out := make([]int, 0, len(values))
+	for _, value := range values {
+		number, ok := value.(float64)
+		if !ok {
+			return nil, ErrInterfaceToIntSlice
+		}
+		out = append(out, int(number))
+	}
+
+	return out, nil
+}
@@ -117,3 +117,45 @@ func TestInterfaceToIntReturns0ForNilInput(t *testing.T) {
 	assert.Nil(t, err)
 	assert.Equal(t, 0, out)
 }
+
+func TestInterfaceToStringSlice(t *testing.T) {
+	out, err := conv.InterfaceToStringSlice([]interface{}{"a", "b"})
+
+	assert.Nil(t, err)
+	assert.Equal(t, []string{"a", "b"}, out)
+}
+
+func TestInterfaceToStringSliceReturnsError(t *testing.T) {
+	out, err := conv.InterfaceToStringSlice([]interface{}{"a", 1.0})
+
+	assert.Nil(t, out)
+	assert.Equal(t, conv.ErrInterfaceToStringSlice, err)
+}
+
+func TestInterfaceToStringSliceReturnsEmptySliceForNilInput(t *testing.T) {
+	out, err := conv.InterfaceToStringSlice(nil)
+
+	assert.Nil(t, err)
+	assert.Empty(t, out)
+}
+
+func TestInterfaceToIntSlice(t *testing.T) {
+	out, err := conv.InterfaceToIntSlice([]interface{}{16.0, 32.0})
+
+	assert.Nil(t, err)
+	assert.Equal(t, []int{16, 32}, out)
+}
+
+func TestInterfaceToIntSliceReturnsError(t *testing.T) {
+	out, err := conv.InterfaceToIntSlice([]interface{}{16.0, "a"})
+
+	assert.Nil(t, out)
+	assert.Equal(t, conv.ErrInterfaceToIntSlice, err)
+}
+
+func TestInterfaceToIntSliceReturnsEmptySliceForNilInput(t *testing.T) {
+	out, err := conv.InterfaceToIntSlice(nil)
+
+	assert.Nil(t, err)
+	assert.Empty(t, out)
+}
@@ -0,0 +1,160 @@
+package core
+
+import (
+	"context"
+	"errors"
+	"slices"
+)
+
+var ErrAuditActionInvalid = errors.New("invalid audit action")
+
+// AuditAction defines a security relevant action recorded in the audit log.
+type AuditAction string
+
+const (
+	AuditActionLogin                  AuditAction = "login"
+	AuditActionLoginFailed            AuditAction = "login_failed"
+	AuditActionLogout                 AuditAction = "logout"
+	AuditActionSessionRevoke          AuditAction = "session_revoke"
+	AuditActionSessionRevokeAll       AuditAction = "session_revoke_all"
+	AuditActionTokenCreate            AuditAction = "token_create"
+	AuditActionTokenRevoke            AuditAction = "token_revoke"
+	AuditActionTokenRevokeAll         AuditAction = "token_revoke_all"
+	AuditActionPasswordChange         AuditAction = "password_change"
+	AuditActionTwoFactorActivate      AuditAction = "two_factor_activate"
+	AuditActionTwoFactorDeactivate    AuditAction = "two_factor_deactivate"
+	AuditActionTwoFactorRecoveryCodes AuditAction = "two_factor_recovery_codes"
+	AuditActionPasskeyRegister        AuditAction = "passkey_register"
+	AuditActionPasskeyDelete          AuditAction = "passkey_delete"
+	AuditActionDeviceApprove          AuditAction = "device_approve"
+	AuditActionDeviceDeny             AuditAction = "device_deny"
+	AuditActionTrustedDeviceCreate    AuditAction = "trusted_device_create"
+	AuditActionTrustedDeviceRevoke    AuditAction = "trusted_device_revoke"
+	AuditActionTrustedDeviceRevokeAll AuditAction = "trusted_device_revoke_all"
+	AuditActionUserCreate             AuditAction = "user_create"
+	AuditActionUserDelete             AuditAction = "user_delete"
+	AuditActionUserRoleUpdate         AuditAction = "user_role_update"
+	AuditActionUserUpdate             AuditAction = "user_update"
+	AuditActionUserDisable            AuditAction = "user_disable"
+	AuditActionUserEnable             AuditAction = "user_enable"
+	AuditActionPasswordReset          AuditAction = "password_reset"
+	AuditActionParentalControlsUpdate AuditAction = "parental_controls_update"
+	AuditActionParentalControlsDelete AuditAction = "parental_controls_delete"
+	AuditActionLockoutClear           AuditAction = "lockout_clear"
+	AuditActionRecordingCancel        AuditAction = "recording_cancel"
+	AuditActionRecordingRemove        AuditAction = "recording_remove"
+	AuditActionDVRConfigDelete        AuditAction = "dvr_config_delete"
+)
+
+// AuditActions contains all audit actions.
+var AuditActions = []AuditAction{
+	AuditActionLogin,
+	AuditActionLoginFailed,
+	AuditActionLogout,
+	AuditActionSessionRevoke,
+	AuditActionSessionRevokeAll,
+	AuditActionTokenCreate,
+	AuditActionTokenRevoke,
+	AuditActionTokenRevokeAll,
+	AuditActionPasswordChange,
+	AuditActionTwoFactorActivate,
+	AuditActionTwoFactorDeactivate,
+	AuditActionTwoFactorRecoveryCodes,
+	AuditActionPasskeyRegister,
+	AuditActionPasskeyDelete,
+	AuditActionDeviceApprove,
+	AuditActionDeviceDeny,
+	AuditActionTrustedDeviceCreate,
+	AuditActionTrustedDeviceRevoke,
+	AuditActionTrustedDeviceRevokeAll,
+	AuditActionUserCreate,
+	AuditActionUserDelete,
+	AuditActionUserRoleUpdate,
+	AuditActionUserUpdate,
+	AuditActionUserDisable,
+	AuditActionUserEnable,
+	AuditActionPasswordReset,
+	AuditActionParentalControlsUpdate,
+	AuditActionParentalControlsDelete,
+	AuditActionLockoutClear,
+	AuditActionRecordingCancel,
+	AuditActionRecordingRemove,
+	AuditActionDVRConfigDelete,
+}
+
+type (
+	// AuditEntry is an entry of the audit log.
+	AuditEntry struct {
+		ID int64 `json:"id"`
+		// UserID is the id of the acting user. It is nil if the
+		// user is unknown, e.g. for a failed login.
+		UserID *int64 `json:"userId"`
+		// Username is the name of the acting user or the attempted username
+		// of a failed login. It is kept when the user is deleted.
+		Username string      `json:"username"`
+		Action   AuditAction `json:"action"`
+		// Target is the id of the affected object (e.g. a session, token, user or recording).
+		Target string `json:"target"`
+		// Reason is the reason of a failed action.
+		Reason    string `json:"reason"`
+		ClientIP  string `json:"clientIp"`
+		UserAgent string `json:"userAgent"`
+		CreatedAt int64  `json:"createdAt"`
+	}
+
+	AuditListResult ListResult[*AuditEntry]
+
+	// AuditQueryParams defines query params to paginate and filter the audit log.
+	AuditQueryParams struct {
+		PaginationQueryParams
+		// (Optional) Filter by the id of the acting user.
+		UserID *int64 `schema:"user_id"`
+		// (Optional) Filter by the name of the acting user.
+		Username string `schema:"username"`
+		// (Optional) Filter by action.
+		Action AuditAction `schema:"action"`
+		// (Optional) Filter entries created at or after a unix timestamp.
+		From int64 `schema:"from"`
+		// (Optional) Filter entries created before a unix timestamp.
+		To int64 `schema:"to"`
+	}
+
+	// AuditRepository defines operations to append to and read the audit log.
+	// Entries can not be updated or deleted.
+	AuditRepository interface {
+		// Create appends a new entry to the audit log.
+		Create(ctx context.Context, entry *AuditEntry) error
+
+		// Find returns the entries of the audit log filtered and paginated by AuditQueryParams.
+		// The newest entries are returned first.
+		Find(ctx context.Context, params AuditQueryParams) (*AuditListResult, error)
+	}
+
+	// AuditService records security relevant actions in the audit log.
+	AuditService interface {
+		// Record appends an entry to the audit log. The username is resolved
+		// from the user id, if not set. Errors are logged, but not returned to
+		// not fail the audited action.
+		Record(ctx context.Context, entry *AuditEntry)
+
+		// Find returns the entries of the audit log filtered and paginated by AuditQueryParams.
+		Find(ctx context.Context, params AuditQueryParams) (*AuditListResult, error)
+	}
+)
+
+// Valid checks if the audit action is known.
+func (a AuditAction) Valid() bool {
+	return slices.Contains(AuditActions, a)
+}
+
+func (p *AuditQueryParams) Validate() error {
+	if err := p.PaginationQueryParams.Validate(); err != nil {
+		return err
+	}
+
+	if p.Action != "" && !p.Action.Valid() {
+		return ErrAuditActionInvalid
+	}
+
+	return nil
+}
@@ -3,6 +3,7 @@ package core
 import (
 	"context"
 	"errors"
+	"slices"
 )
 
 var (
@@ -21,6 +22,10 @@ var (
 	ErrTwoFactorAuthSetupNotRunning = errors.New("two factor auth setup not running")
 
 	ErrPermissionDenied = errors.New("permission denied")
+
+	ErrOIDCStateInvalid         = errors.New("invalid oidc state")
+	ErrOIDCUsernameMissing      = errors.New("oidc id token contains no username")
+	ErrOIDCRegistrationDisabled = errors.New("user not found and oidc registration is disabled")
 )
 
 type (
@@ -39,6 +44,10 @@ type (
 
 		// ForwardAuth is true if the request was forwarded from a reverse proxy.
 		ForwardAuth bool
+
+		// Scopes restrict the permissions of the user for authorizations via scoped tokens.
+		// If nil, all permissions of the user are granted.
+		Scopes []Permission
 	}
 
 	// SessionManager defines operations to manage a session of a user.
@@ -48,8 +57,19 @@ type (
 		Validate(ctx context.Context, token string) (*AuthContext, *string, error)
 		// Create creates a new session for a user with a client ip and a user agent.
 		Create(ctx context.Context, userId int64, clientIp string, userAgent string) (string, error)
+		// CreateDeviceSession creates a new session for a user on a device authorized via the device flow.
+		CreateDeviceSession(
+			ctx context.Context,
+			userId int64,
+			clientIp string,
+			userAgent string,
+			deviceName string,
+		) (string, error)
 		// Revoke revokes a specific session.
 		Revoke(ctx context.Context, sessionID int64, userID int64) error
+		// RevokeAll revokes all sessions of a user except the optional session
+		// and returns the number of revoked sessions.
+		RevokeAll(ctx context.Context, userID int64, exceptSessionID *int64) (int64, error)
 	}
 
 	// PasswordAuthenticator defines operations to log in users via login and password.
@@ -61,6 +81,26 @@ type (
 		ConfirmPassword(ctx context.Context, userID int64, password string) error
 	}
 
+	// OIDCAuthRequest contains the parameters of a started OIDC login, which
+	// must be kept by the client to verify the callback of the provider.
+	OIDCAuthRequest struct {
+		// URL is the url of the provider to redirect the user to.
+		URL      string `json:"url"`
+		State    string `json:"state"`
+		Nonce    string `json:"nonce"`
+		Verifier string `json:"verifier"`
+	}
+
+	// OIDCAuthenticator defines operations to log in users via OpenID Connect.
+	OIDCAuthenticator interface {
+		// AuthCodeURL starts a new authorization code flow with PKCE.
+		AuthCodeURL(ctx context.Context) (*OIDCAuthRequest, error)
+
+		// Exchange exchanges the authorization code of the callback
+		// and returns the linked or provisioned user.
+		Exchange(ctx context.Context, code string, authRequest OIDCAuthRequest) (*User, error)
+	}
+
 	// TwoFactorAuthService defines operations to manage two factor auth for a user.
 	TwoFactorAuthService interface {
 		// GetSettings returns the current two factor settings for a user.
@@ -72,26 +112,57 @@ type (
 		// Deactivate deactivates two factor auth for a user.
 		Deactivate(ctx context.Context, userId int64, code string) error
 
-		// Activate activates two factor auth for a user.
-		Activate(ctx context.Context, userID int64, code string) error
+		// Activate activates two factor auth for a user and
+		// returns a new set of recovery codes.
+		Activate(ctx context.Context, userID int64, code string) ([]string, error)
 
-		// Verify verifies a two factor code for a user.
+		// Verify verifies a two factor code or a recovery code for a user.
 		Verify(ctx context.Context, userId int64, code *string) error
+
+		// Disable disables two factor auth for a user without a code, e.g. if
+		// an admin resets the second factor of a user.
+		Disable(ctx context.Context, userID int64) error
+
+		// RegenerateRecoveryCodes replaces the recovery codes of a user
+		// with a new set of recovery codes.
+		RegenerateRecoveryCodes(ctx context.Context, userID int64) ([]string, error)
 	}
 
 	// TokenService defines operations to manage tokens for a user.
 	TokenService interface {
 		// Create creates a new token for a user.
-		Create(ctx context.Context, userID int64, name string) (string, error)
+		Create(ctx context.Context, userID int64, opts CreateToken) (string, error)
 
-		// Validate validates a token.
-		Validate(ctx context.Context, token string) (*AuthContext, error)
+		// Validate validates a token and tracks its usage by a client ip.
+		Validate(ctx context.Context, token string, clientIP string) (*AuthContext, error)
 
-		// Revoke revokes a token.
-		Revoke(ctx context.Context, id int64) error
+		// Revoke revokes a token of a user.
+		Revoke(ctx context.Context, id int64, userID int64) error
+
+		// RevokeAll revokes all tokens of a user and returns the number of revoked tokens.
+		RevokeAll(ctx context.Context, userID int64) (int64, error)
 	}
 )
 
+// HasScope checks if the scopes of the auth context grant a permission.
+func (c *AuthContext) HasScope(permission Permission) bool {
+	return c.Scopes == nil || slices.Contains(c.Scopes, permission)
+}
+
+type twoFactorVerifiedKey struct{}
+
+// WithTwoFactorVerified returns a context in which the second factor of a
+// user is already verified by other means than a code (e.g. a passkey).
+func WithTwoFactorVerified(ctx context.Context, userID int64) context.Context {
+	return context.WithValue(ctx, twoFactorVerifiedKey{}, userID)
+}
+
+// IsTwoFactorVerified checks if the second factor of a user is already verified.
+func IsTwoFactorVerified(ctx context.Context, userID int64) bool {
+	verifiedUserID, ok := ctx.Value(twoFactorVerifiedKey{}).(int64)
+	return ok && verifiedUserID == userID
+}
+
 func (InvalidOrExpiredTokenError) Error() string {
 	return "invalid or expired token"
 }
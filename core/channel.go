@@ -22,6 +22,8 @@ type (
 		Name    string `json:"name"`
 		Number  int    `json:"number"`
 		PiconID int    `json:"piconId"`
+		// Tags are the ids of the channel tags.
+		Tags []string `json:"tags"`
 	}
 
 	// ChannelService provides access to channel
@@ -65,6 +67,8 @@ func MapTvheadendIdnodeToChannel(idnode tvheadend.Idnode) (*Channel, error) {
 			var value string
 			value, err = conv.InterfaceToString(p.Value)
 			r.PiconID = MapTvheadendIconUrlToPiconID(value)
+		case "tags":
+			r.Tags, err = conv.InterfaceToStringSlice(p.Value)
 		}
 
 		if err != nil {
@@ -44,6 +44,10 @@ func TestMapTvheadendIdnodeToChannel(t *testing.T) {
 				ID:    "icon_public_url",
 				Value: iconUrl,
 			},
+			{
+				ID:    "tags",
+				Value: []interface{}{"someTag"},
+			},
 		},
 	}
 
@@ -54,6 +58,7 @@ func TestMapTvheadendIdnodeToChannel(t *testing.T) {
 	assert.Equal(t, name, channel.Name)
 	assert.Equal(t, number, channel.Number)
 	assert.Equal(t, 223, channel.PiconID)
+	assert.Equal(t, []string{"someTag"}, channel.Tags)
 }
 
 func TestMapTvheadendIdnodeToChannelFailsForUnexpectedType(t *testing.T) {
@@ -0,0 +1,135 @@
+package core
+
+import (
+	"context"
+	"errors"
+)
+
+var (
+	ErrDeviceNameInvalid     = errors.New("device name must be between 1 and 64 characters")
+	ErrDeviceCodeInvalid     = errors.New("invalid device code")
+	ErrDeviceUserCodeInvalid = errors.New("invalid or expired user code")
+	ErrDeviceCodeLimit       = errors.New("too many pending device codes")
+
+	// Errors returned while a device polls for the authorization (RFC 8628, section 3.5).
+	ErrDeviceAuthPending  = errors.New("authorization pending")
+	ErrDeviceAuthSlowDown = errors.New("polling too fast")
+	ErrDeviceAuthDenied   = errors.New("authorization denied")
+	ErrDeviceAuthExpired  = errors.New("device code expired")
+)
+
+// DeviceAuthStatus defines the status of a device authorization.
+type DeviceAuthStatus string
+
+const (
+	DeviceAuthStatusPending  DeviceAuthStatus = "pending"
+	DeviceAuthStatusApproved DeviceAuthStatus = "approved"
+	DeviceAuthStatusDenied   DeviceAuthStatus = "denied"
+)
+
+const (
+	// DeviceTokenTypeSession is the type of session tokens issued to devices,
+	// which must be sent as session cookie.
+	DeviceTokenTypeSession = "session"
+	// DeviceTokenTypeBearer is the type of api tokens issued to devices,
+	// which must be sent as bearer token.
+	DeviceTokenTypeBearer = "Bearer"
+)
+
+type (
+	// DeviceAuthorization is a pending or decided authorization
+	// request of a device via the device authorization grant.
+	DeviceAuthorization struct {
+		ID               int64            `json:"-"`
+		HashedDeviceCode string           `json:"-"`
+		UserCode         string           `json:"userCode"`
+		DeviceName       string           `json:"deviceName"`
+		ClientIP         string           `json:"clientIp"`
+		Status           DeviceAuthStatus `json:"status"`
+		// UserID is the id of the user, who approved or denied the device.
+		UserID *int64 `json:"-"`
+		// Scopes restrict the api token issued to the device.
+		// If empty, the device gets a session instead.
+		Scopes       []Permission `json:"scopes"`
+		ExpiresAt    int64        `json:"expiresAt"`
+		LastPolledAt int64        `json:"-"`
+		CreatedAt    int64        `json:"createdAt"`
+	}
+
+	// DeviceCode is the response of a started device authorization.
+	DeviceCode struct {
+		DeviceCode string `json:"device_code"`
+		UserCode   string `json:"user_code"`
+		// VerificationURI is the url, where the user enters the user code.
+		VerificationURI string `json:"verification_uri"`
+		// VerificationURIComplete contains the user code and is meant to be shown as qr code.
+		VerificationURIComplete string `json:"verification_uri_complete"`
+		ExpiresIn               int64  `json:"expires_in"`
+		Interval                int64  `json:"interval"`
+	}
+
+	// DeviceToken is issued to a device after the authorization was approved.
+	DeviceToken struct {
+		AccessToken string `json:"access_token"`
+		// TokenType is either DeviceTokenTypeSession or DeviceTokenTypeBearer.
+		TokenType string       `json:"token_type"`
+		Scopes    []Permission `json:"scopes,omitempty"`
+
+		UserID     int64  `json:"-"`
+		DeviceName string `json:"-"`
+	}
+
+	// DeviceAuthRepository defines CRUD operations for working with device authorizations.
+	DeviceAuthRepository interface {
+		// FindByDeviceCode returns a device authorization by the hashed device code.
+		FindByDeviceCode(ctx context.Context, hashedDeviceCode string) (*DeviceAuthorization, error)
+
+		// FindByUserCode returns a device authorization by the user code.
+		FindByUserCode(ctx context.Context, userCode string) (*DeviceAuthorization, error)
+
+		// Create persists a new device authorization.
+		Create(ctx context.Context, auth *DeviceAuthorization) error
+
+		// Update persists the status, user, scopes and last poll of a device authorization.
+		Update(ctx context.Context, auth *DeviceAuthorization) error
+
+		// Delete deletes a device authorization and returns false if it did not exist.
+		Delete(ctx context.Context, id int64) (bool, error)
+
+		// CountPending returns the number of pending device authorizations.
+		CountPending(ctx context.Context) (int64, error)
+
+		// CountPendingByClientIP returns the number of pending device
+		// authorizations started from a client ip.
+		CountPendingByClientIP(ctx context.Context, clientIP string) (int64, error)
+
+		// DeleteExpired deletes all device authorizations expired before a given time.
+		DeleteExpired(ctx context.Context, before int64) (int64, error)
+	}
+
+	// DeviceAuthService implements the device authorization grant (RFC 8628)
+	// for devices with limited input capabilities.
+	DeviceAuthService interface {
+		// Start starts the authorization of a device and returns the device and user code.
+		Start(ctx context.Context, deviceName string, clientIP string) (*DeviceCode, error)
+
+		// Find returns a pending device authorization by the user code.
+		Find(ctx context.Context, userCode string) (*DeviceAuthorization, error)
+
+		// Approve approves a pending device authorization for a user. The device gets
+		// an api token restricted to the scopes or a session if no scopes are set.
+		Approve(
+			ctx context.Context,
+			userCode string,
+			userID int64,
+			scopes []Permission,
+		) (*DeviceAuthorization, error)
+
+		// Deny denies a pending device authorization.
+		Deny(ctx context.Context, userCode string, userID int64) (*DeviceAuthorization, error)
+
+		// Poll exchanges the device code of an approved authorization for a session or token.
+		// Until the authorization is decided, ErrDeviceAuthPending or ErrDeviceAuthSlowDown is returned.
+		Poll(ctx context.Context, deviceCode string, clientIP string, userAgent string) (*DeviceToken, error)
+	}
+)
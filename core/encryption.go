@@ -0,0 +1,32 @@
+package core
+
+import "errors"
+
+var (
+	ErrEncryptionKeyNotFound   = errors.New("encryption key of value not found")
+	ErrEncryptionValueInvalid  = errors.New("encrypted value invalid")
+	ErrEncryptionNotConfigured = errors.New("no encryption keys configured")
+)
+
+// Encryptor encrypts sensitive values (e.g. totp secrets) before
+// they are stored in the database.
+//
+// The associated data binds an encrypted value to its owner (e.g. the
+// id of the user), so that the value can't be decrypted after it was
+// copied to the row of another owner.
+type Encryptor interface {
+	// Enabled checks if encryption keys are configured.
+	Enabled() bool
+
+	// Encrypt encrypts a value with the current key. Values are returned
+	// unchanged, if no keys are configured.
+	Encrypt(value string, associatedData string) (string, error)
+
+	// Decrypt decrypts a value with the key it was encrypted with.
+	// Unencrypted values are returned unchanged.
+	Decrypt(value string, associatedData string) (string, error)
+
+	// NeedsReencryption checks if a value is unencrypted or
+	// encrypted with another key than the current key.
+	NeedsReencryption(value string) bool
+}
@@ -33,6 +33,8 @@ type (
 		Widescreen    bool   `json:"widescreen"`
 		DvrUUID       string `json:"dvrUuid,omitempty"`
 		DvrState      string `json:"dvrState,omitempty"`
+		// ContentTypes are the ids of the epg content types of the event.
+		ContentTypes []int `json:"contentTypes,omitempty"`
 	}
 
 	// EpgEventsResult defines a ListResult of epg events.
@@ -217,6 +219,7 @@ func BuildEpgEvent(src tvheadend.EpgEventGridEntry) EpgEvent {
 		Widescreen:    src.Widescreen == 1,
 		DvrUUID:       src.DvrUUID,
 		DvrState:      src.DvrState,
+		ContentTypes:  src.Genre,
 	}
 }
 
@@ -0,0 +1,36 @@
+package core
+
+import (
+	"context"
+	"errors"
+)
+
+var (
+	ErrHLSStreamNotFound  = errors.New("hls stream not found")
+	ErrHLSSegmentNotFound = errors.New("hls segment not found")
+	ErrHLSStreamTimeout   = errors.New("timeout while waiting for the hls stream")
+)
+
+type (
+	// HLSService remuxes live streams of channels to HLS.
+	HLSService interface {
+		// GetPlaylist returns the sliding window playlist of the channel stream
+		// of a viewer session. The stream is started if it's not already running.
+		// A new viewer is authorized and registered as stream session, which ends
+		// when the viewer is idle. The query is appended to the segment uris of the playlist.
+		GetPlaylist(
+			ctx context.Context,
+			session StreamSession,
+			query string,
+			authorize func() error,
+		) ([]byte, error)
+
+		// GetSegment returns a segment of a running channel stream
+		// to a viewer, which requested the playlist and was authorized.
+		GetSegment(
+			ctx context.Context,
+			session StreamSession,
+			sequence int64,
+		) ([]byte, error)
+	}
+)
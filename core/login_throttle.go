@@ -0,0 +1,103 @@
+package core
+
+import (
+	"context"
+	"errors"
+	"time"
+)
+
+var (
+	ErrLoginThrottled        = errors.New("too many failed login attempts")
+	ErrLoginThrottleNotFound = errors.New("lockout not found")
+)
+
+// LoginThrottleKind defines what is throttled.
+type LoginThrottleKind string
+
+const (
+	// LoginThrottleKindIP throttles logins from a client ip.
+	LoginThrottleKindIP LoginThrottleKind = "ip"
+	// LoginThrottleKindUsername throttles logins for a username.
+	LoginThrottleKindUsername LoginThrottleKind = "username"
+)
+
+type (
+	// LoginThrottle tracks the failed logins of a client ip or a username.
+	LoginThrottle struct {
+		ID             int64             `json:"id"`
+		Kind           LoginThrottleKind `json:"kind"`
+		Key            string            `json:"key"`
+		FailedAttempts int               `json:"failedAttempts"`
+		LastFailedAt   int64             `json:"lastFailedAt"`
+		// BlockedUntil is the time until further logins are rejected.
+		BlockedUntil int64 `json:"blockedUntil"`
+		// Locked is true if the maximum failed attempts were reached
+		// and logins are rejected for the full lockout duration.
+		Locked bool `json:"locked"`
+	}
+
+	// LoginThrottledError is returned when a login is rejected because of too many failed attempts.
+	LoginThrottledError struct {
+		// RetryAfter is the duration after which the login can be retried.
+		RetryAfter time.Duration
+	}
+
+	// LoginThrottleRepository defines CRUD operations for working with login throttles.
+	LoginThrottleRepository interface {
+		// Find returns the login throttle of a client ip or username.
+		Find(ctx context.Context, kind LoginThrottleKind, key string) (*LoginThrottle, error)
+
+		// FindBlocked returns all login throttles which block logins at a given time.
+		FindBlocked(ctx context.Context, now int64) ([]*LoginThrottle, error)
+
+		// RecordFailure increments the failed attempts of a client ip or username
+		// and returns the updated login throttle. Failed attempts before resetBefore are discarded.
+		RecordFailure(
+			ctx context.Context,
+			kind LoginThrottleKind,
+			key string,
+			now int64,
+			resetBefore int64,
+		) (*LoginThrottle, error)
+
+		// Block persists the time until logins are blocked.
+		Block(ctx context.Context, id int64, blockedUntil int64) error
+
+		// Delete deletes the login throttle of a client ip or username.
+		Delete(ctx context.Context, kind LoginThrottleKind, key string) error
+
+		// DeleteByID deletes a login throttle by its id.
+		DeleteByID(ctx context.Context, id int64) (bool, error)
+
+		// DeleteInactive deletes login throttles without failed attempts since a given time.
+		DeleteInactive(ctx context.Context, before int64) error
+	}
+
+	// LoginThrottleService protects logins against brute-force attacks
+	// by rate limiting failed attempts per client ip and username.
+	LoginThrottleService interface {
+		// Check returns a LoginThrottledError if logins for the username or the client ip are blocked.
+		Check(ctx context.Context, username string, clientIP string) error
+
+		// RecordFailure records a failed login and blocks further logins
+		// with an exponential backoff until the lockout.
+		RecordFailure(ctx context.Context, username string, clientIP string) error
+
+		// RecordSuccess resets the failed logins of the username.
+		RecordSuccess(ctx context.Context, username string) error
+
+		// List returns all currently blocked client ips and usernames.
+		List(ctx context.Context) ([]*LoginThrottle, error)
+
+		// Clear removes the block of a client ip or username.
+		Clear(ctx context.Context, id int64) error
+	}
+)
+
+func (LoginThrottledError) Error() string {
+	return ErrLoginThrottled.Error()
+}
+
+func (LoginThrottledError) Unwrap() error {
+	return ErrLoginThrottled
+}
@@ -0,0 +1,225 @@
+package core
+
+import (
+	"context"
+	"errors"
+	"slices"
+	"time"
+)
+
+// viewingTimeLayout is the layout of the viewing window times.
+const viewingTimeLayout = "15:04"
+
+var (
+	ErrContentRestricted        = errors.New("content is restricted by parental controls")
+	ErrParentalControlsNotFound = errors.New("parental controls not found")
+
+	ErrParentalControlsInvalidViewingTime = errors.New("viewing time must be formatted as HH:MM")
+	ErrParentalControlsInvalidContentType = errors.New("content type invalid")
+)
+
+type (
+	// ParentalControls restrict the channels and content a user can access.
+	ParentalControls struct {
+		UserID int64 `json:"-"`
+		// Channels are the ids of allowed channels.
+		Channels []string `json:"channels"`
+		// ChannelTags are the ids of channel tags. Channels with one of
+		// the tags are allowed as well. If neither channels nor tags
+		// are set, all channels are allowed.
+		ChannelTags []string `json:"channelTags"`
+		// BlockedContentTypes are the ids of blocked epg content types.
+		// A main content type (e.g. 16 for "Movie / Drama") blocks all
+		// its sub types.
+		BlockedContentTypes []int `json:"blockedContentTypes"`
+		// ViewingFrom and ViewingTo optionally restrict streaming to a
+		// daily time window in the local time of the server (HH:MM).
+		// The window may span midnight, e.g. from 18:00 to 02:00.
+		ViewingFrom string `json:"viewingFrom"`
+		ViewingTo   string `json:"viewingTo"`
+		CreatedAt   int64  `json:"createdAt"`
+		UpdatedAt   int64  `json:"updatedAt"`
+	}
+
+	// ContentFilter is the resolved form of the parental controls of a user
+	// at a point in time. A nil ContentFilter allows everything.
+	ContentFilter struct {
+		// channels contains the ids of allowed channels or is nil if all channels are allowed.
+		channels map[string]bool
+		// numbers maps the numbers of allowed channels to their ids.
+		numbers             map[int64]string
+		blockedContentTypes []int
+		outsideViewingTime  bool
+	}
+
+	// ParentalControlsRepository defines CRUD operations for working with parental controls.
+	ParentalControlsRepository interface {
+		// Find returns the parental controls of a user.
+		Find(ctx context.Context, userID int64) (*ParentalControls, error)
+
+		// Save creates or updates the parental controls of a user.
+		Save(ctx context.Context, controls *ParentalControls) error
+
+		// Delete deletes the parental controls of a user.
+		Delete(ctx context.Context, userID int64) error
+	}
+
+	// ParentalControlsService manages and resolves the parental controls of users.
+	ParentalControlsService interface {
+		// Get returns the parental controls of a user or nil if the user is not restricted.
+		Get(ctx context.Context, userID int64) (*ParentalControls, error)
+
+		// Save validates and persists the parental controls of a user.
+		Save(ctx context.Context, controls *ParentalControls) error
+
+		// Delete removes all restrictions of a user.
+		Delete(ctx context.Context, userID int64) error
+
+		// Filter returns the content filter of a user for the current time
+		// or nil if the user is not restricted.
+		Filter(ctx context.Context, userID int64) (*ContentFilter, error)
+	}
+)
+
+// Validate validates the content types and the viewing window of parental controls.
+func (c *ParentalControls) Validate() error {
+	for _, contentType := range c.BlockedContentTypes {
+		if contentType <= 0 {
+			return ErrParentalControlsInvalidContentType
+		}
+	}
+
+	if (c.ViewingFrom == "") != (c.ViewingTo == "") {
+		return ErrParentalControlsInvalidViewingTime
+	}
+
+	for _, value := range []string{c.ViewingFrom, c.ViewingTo} {
+		if value == "" {
+			continue
+		}
+
+		if _, err := time.Parse(viewingTimeLayout, value); err != nil {
+			return ErrParentalControlsInvalidViewingTime
+		}
+	}
+
+	return nil
+}
+
+// InViewingTime checks if the time is inside of the viewing window.
+// Without viewing window, every time is inside.
+func (c *ParentalControls) InViewingTime(now time.Time) bool {
+	if c.ViewingFrom == "" || c.ViewingTo == "" {
+		return true
+	}
+
+	from, errFrom := time.Parse(viewingTimeLayout, c.ViewingFrom)
+	to, errTo := time.Parse(viewingTimeLayout, c.ViewingTo)
+	if errFrom != nil || errTo != nil {
+		return false
+	}
+
+	minute := now.Hour()*60 + now.Minute()
+	fromMinute := from.Hour()*60 + from.Minute()
+	toMinute := to.Hour()*60 + to.Minute()
+
+	if fromMinute <= toMinute {
+		return minute >= fromMinute && minute < toMinute
+	}
+
+	return minute >= fromMinute || minute < toMinute
+}
+
+// NewContentFilter resolves parental controls with all channels
+// of tvheadend to a ContentFilter for a given time.
+func NewContentFilter(controls *ParentalControls, channels []*Channel, now time.Time) *ContentFilter {
+	restrictChannels := len(controls.Channels) > 0 || len(controls.ChannelTags) > 0
+
+	f := &ContentFilter{
+		numbers:             make(map[int64]string),
+		blockedContentTypes: controls.BlockedContentTypes,
+		outsideViewingTime:  !controls.InViewingTime(now),
+	}
+
+	if restrictChannels {
+		f.channels = make(map[string]bool)
+	}
+
+	for _, channel := range channels {
+		if restrictChannels && !controls.allowsChannel(channel) {
+			continue
+		}
+
+		if f.channels != nil {
+			f.channels[channel.ID] = true
+		}
+
+		f.numbers[int64(channel.Number)] = channel.ID
+	}
+
+	return f
+}
+
+func (c *ParentalControls) allowsChannel(channel *Channel) bool {
+	if slices.Contains(c.Channels, channel.ID) {
+		return true
+	}
+
+	for _, tag := range channel.Tags {
+		if slices.Contains(c.ChannelTags, tag) {
+			return true
+		}
+	}
+
+	return false
+}
+
+// AllowsChannel checks if a channel is allowed.
+func (f *ContentFilter) AllowsChannel(id string) bool {
+	return f == nil || f.channels == nil || f.channels[id]
+}
+
+// ChannelID returns the id of an allowed channel by its number.
+// It returns false if the channel is unknown or not allowed.
+// Must not be called on a nil ContentFilter.
+func (f *ContentFilter) ChannelID(number int64) (string, bool) {
+	id, ok := f.numbers[number]
+	return id, ok
+}
+
+// AllowsContentTypes checks if none of the content types is blocked.
+func (f *ContentFilter) AllowsContentTypes(contentTypes []int) bool {
+	if f == nil {
+		return true
+	}
+
+	for _, contentType := range contentTypes {
+		for _, blocked := range f.blockedContentTypes {
+			if contentType == blocked || (blocked&0x0f == 0 && contentType&0xf0 == blocked) {
+				return false
+			}
+		}
+	}
+
+	return true
+}
+
+// BlocksContentTypes checks if any content type is blocked.
+func (f *ContentFilter) BlocksContentTypes() bool {
+	return f != nil && len(f.blockedContentTypes) > 0
+}
+
+// AllowsEvent checks if the channel and the content types of an epg event are allowed.
+func (f *ContentFilter) AllowsEvent(event *EpgEvent) bool {
+	return f.AllowsChannel(event.ChannelID) && f.AllowsContentTypes(event.ContentTypes)
+}
+
+// AllowsRecording checks if the channel and the content types of a recording are allowed.
+func (f *ContentFilter) AllowsRecording(recording *Recording) bool {
+	return f.AllowsChannel(recording.ChannelID) && f.AllowsContentTypes(recording.ContentTypes)
+}
+
+// AllowsStreaming checks if streaming is allowed at the time the filter was resolved.
+func (f *ContentFilter) AllowsStreaming() bool {
+	return f == nil || !f.outsideViewingTime
+}
@@ -0,0 +1,120 @@
+package core_test
+
+import (
+	"testing"
+	"time"
+
+	"github.com/davidborzek/tvhgo/core"
+	"github.com/stretchr/testify/assert"
+)
+
+var parentalControlsChannels = []*core.Channel{
+	{ID: "kids", Number: 1, Tags: []string{"kidsTag"}},
+	{ID: "news", Number: 2},
+	{ID: "movies", Number: 3, Tags: []string{"moviesTag"}},
+}
+
+func TestParentalControlsValidate(t *testing.T) {
+	assert.Nil(t, (&core.ParentalControls{}).Validate())
+	assert.Nil(t, (&core.ParentalControls{
+		BlockedContentTypes: []int{16},
+		ViewingFrom:         "06:00",
+		ViewingTo:           "20:30",
+	}).Validate())
+
+	assert.Equal(t, core.ErrParentalControlsInvalidContentType, (&core.ParentalControls{
+		BlockedContentTypes: []int{0},
+	}).Validate())
+
+	assert.Equal(t, core.ErrParentalControlsInvalidViewingTime, (&core.ParentalControls{
+		ViewingFrom: "06:00",
+	}).Validate())
+
+	assert.Equal(t, core.ErrParentalControlsInvalidViewingTime, (&core.ParentalControls{
+		ViewingFrom: "6am",
+		ViewingTo:   "20:00",
+	}).Validate())
+}
+
+func TestParentalControlsInViewingTime(t *testing.T) {
+	at := func(hour int, minute int) time.Time {
+		return time.Date(2023, 1, 1, hour, minute, 0, 0, time.Local)
+	}
+
+	daytime := &core.ParentalControls{ViewingFrom: "06:00", ViewingTo: "20:00"}
+	assert.True(t, daytime.InViewingTime(at(6, 0)))
+	assert.True(t, daytime.InViewingTime(at(19, 59)))
+	assert.False(t, daytime.InViewingTime(at(20, 0)))
+	assert.False(t, daytime.InViewingTime(at(5, 59)))
+
+	overnight := &core.ParentalControls{ViewingFrom: "18:00", ViewingTo: "02:00"}
+	assert.True(t, overnight.InViewingTime(at(23, 0)))
+	assert.True(t, overnight.InViewingTime(at(1, 0)))
+	assert.False(t, overnight.InViewingTime(at(12, 0)))
+
+	assert.True(t, (&core.ParentalControls{}).InViewingTime(at(3, 0)))
+}
+
+func TestContentFilterAllowsChannelsAndTags(t *testing.T) {
+	filter := core.NewContentFilter(&core.ParentalControls{
+		Channels:    []string{"news"},
+		ChannelTags: []string{"kidsTag"},
+	}, parentalControlsChannels, time.Now())
+
+	assert.True(t, filter.AllowsChannel("kids"))
+	assert.True(t, filter.AllowsChannel("news"))
+	assert.False(t, filter.AllowsChannel("movies"))
+
+	id, ok := filter.ChannelID(1)
+	assert.True(t, ok)
+	assert.Equal(t, "kids", id)
+
+	_, ok = filter.ChannelID(3)
+	assert.False(t, ok)
+}
+
+func TestContentFilterWithoutChannelRestrictionAllowsAllChannels(t *testing.T) {
+	filter := core.NewContentFilter(&core.ParentalControls{
+		BlockedContentTypes: []int{16},
+	}, parentalControlsChannels, time.Now())
+
+	assert.True(t, filter.AllowsChannel("movies"))
+
+	id, ok := filter.ChannelID(3)
+	assert.True(t, ok)
+	assert.Equal(t, "movies", id)
+}
+
+func TestContentFilterBlocksContentTypes(t *testing.T) {
+	filter := core.NewContentFilter(&core.ParentalControls{
+		BlockedContentTypes: []int{16, 0x24},
+	}, parentalControlsChannels, time.Now())
+
+	assert.True(t, filter.BlocksContentTypes())
+	assert.False(t, filter.AllowsContentTypes([]int{16}))
+	assert.False(t, filter.AllowsContentTypes([]int{0x13}))
+	assert.False(t, filter.AllowsContentTypes([]int{0x50, 0x24}))
+	assert.True(t, filter.AllowsContentTypes([]int{0x20}))
+	assert.True(t, filter.AllowsContentTypes(nil))
+
+	assert.False(t, filter.AllowsEvent(&core.EpgEvent{ChannelID: "news", ContentTypes: []int{0x11}}))
+	assert.True(t, filter.AllowsRecording(&core.Recording{ChannelID: "news", ContentTypes: []int{0x50}}))
+}
+
+func TestContentFilterAllowsStreamingInViewingTime(t *testing.T) {
+	controls := &core.ParentalControls{ViewingFrom: "06:00", ViewingTo: "20:00"}
+	noon := time.Date(2023, 1, 1, 12, 0, 0, 0, time.Local)
+	midnight := time.Date(2023, 1, 1, 0, 0, 0, 0, time.Local)
+
+	assert.True(t, core.NewContentFilter(controls, nil, noon).AllowsStreaming())
+	assert.False(t, core.NewContentFilter(controls, nil, midnight).AllowsStreaming())
+}
+
+func TestNilContentFilterAllowsEverything(t *testing.T) {
+	var filter *core.ContentFilter
+
+	assert.True(t, filter.AllowsChannel("movies"))
+	assert.True(t, filter.AllowsContentTypes([]int{16}))
+	assert.True(t, filter.AllowsStreaming())
+	assert.False(t, filter.BlocksContentTypes())
+}
@@ -0,0 +1,21 @@
+package core
+
+import "errors"
+
+var (
+	ErrPasswordMismatch    = errors.New("password does not match")
+	ErrPasswordHashInvalid = errors.New("password hash invalid")
+)
+
+// PasswordHasher hashes and verifies passwords of local users.
+type PasswordHasher interface {
+	// Hash hashes a password with the configured algorithm and parameters.
+	Hash(password string) (string, error)
+
+	// Compare compares a password with a hash of any supported algorithm.
+	Compare(password string, hash string) error
+
+	// NeedsRehash checks if a hash uses another algorithm than the
+	// configured algorithm or weaker parameters.
+	NeedsRehash(hash string) bool
+}
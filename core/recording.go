@@ -62,6 +62,8 @@ type (
 		// after the recording ends.
 		EndPadding int    `json:"endPadding"`
 		Status     string `json:"status"`
+		// ContentTypes are the ids of the epg content types of the recording.
+		ContentTypes []int `json:"contentTypes,omitempty"`
 	}
 
 	// GetRecordingsParams defines query params
@@ -137,11 +139,12 @@ type (
 	// RecordingService provides access to recording
 	// resources from the tvheadend server.
 	RecordingService interface {
-		// CreateByEvent creates a new recording by an epg event.
-		CreateByEvent(ctx context.Context, opts CreateRecordingByEvent) error
+		// CreateByEvent creates a new recording by an epg event
+		// and returns the ids of the created recordings.
+		CreateByEvent(ctx context.Context, opts CreateRecordingByEvent) ([]string, error)
 
-		// CreateByEvent creates a new recording by an epg event.
-		Create(ctx context.Context, opts CreateRecording) error
+		// Create creates a new recording and returns its id.
+		Create(ctx context.Context, opts CreateRecording) (string, error)
 
 		// GetAll returns a list of recordings.
 		GetAll(ctx context.Context, params GetRecordingsParams) (*RecordingListResult, error)
@@ -262,6 +265,7 @@ func MapToTvheadendDvrGridEntryToRecording(entry tvheadend.DvrGridEntry) Recordi
 		Description:      entry.DispDescription,
 		EventID:          entry.Broadcast,
 		PiconID:          MapTvheadendIconUrlToPiconID(entry.ChannelIcon),
+		ContentTypes:     entry.Genre,
 	}
 }
 
@@ -317,6 +321,8 @@ func MapTvheadendIdnodeToRecording(idnode tvheadend.Idnode) (*Recording, error)
 			var value string
 			value, err = conv.InterfaceToString(p.Value)
 			r.PiconID = MapTvheadendIconUrlToPiconID(value)
+		case "genre":
+			r.ContentTypes, err = conv.InterfaceToIntSlice(p.Value)
 		}
 
 		if err != nil {
@@ -0,0 +1,135 @@
+package core
+
+import (
+	"context"
+	"errors"
+	"slices"
+)
+
+var (
+	ErrRoleInvalid = errors.New("invalid role")
+)
+
+// Role is a named set of permissions assigned to a user.
+type Role string
+
+const (
+	// RoleViewer may only browse the EPG and watch live streams.
+	RoleViewer Role = "viewer"
+	// RoleRecorder may additionally schedule recordings and manage own recordings.
+	RoleRecorder Role = "recorder"
+	// RoleDVRAdmin may additionally manage all recordings and the dvr config.
+	RoleDVRAdmin Role = "dvr-admin"
+	// RoleAdmin has all permissions including the management of users.
+	RoleAdmin Role = "admin"
+
+	// DefaultRole is the role of new users without an explicitly assigned role.
+	DefaultRole = RoleDVRAdmin
+)
+
+// Permission is a permission to perform a group of operations.
+type Permission string
+
+const (
+	// PermissionAccount allows to manage the own account, sessions, tokens and second factors.
+	PermissionAccount Permission = "account"
+	// PermissionEPGRead allows to read the epg, channels, picons and stream profiles.
+	PermissionEPGRead Permission = "epg:read"
+	// PermissionStream allows to stream channels.
+	PermissionStream Permission = "stream"
+	// PermissionRecordingsRead allows to read and stream recordings.
+	PermissionRecordingsRead Permission = "recordings:read"
+	// PermissionRecordingsWrite allows to schedule recordings and to manage own recordings.
+	PermissionRecordingsWrite Permission = "recordings:write"
+	// PermissionRecordingsManage allows to manage recordings of all users.
+	PermissionRecordingsManage Permission = "recordings:manage"
+	// PermissionDVRConfigManage allows to manage the dvr config.
+	PermissionDVRConfigManage Permission = "dvr-config:manage"
+	// PermissionStreamsManage allows to list and terminate streams of all users.
+	PermissionStreamsManage Permission = "streams:manage"
+	// PermissionAuditRead allows to read the audit log.
+	PermissionAuditRead Permission = "audit:read"
+	// PermissionUsersManage allows to manage users and their roles.
+	PermissionUsersManage Permission = "users:manage"
+)
+
+var (
+	// Roles contains all roles ordered by their amount of permissions.
+	Roles = []Role{RoleViewer, RoleRecorder, RoleDVRAdmin, RoleAdmin}
+
+	viewerPermissions = []Permission{
+		PermissionAccount,
+		PermissionEPGRead,
+		PermissionStream,
+	}
+
+	recorderPermissions = append(slices.Clone(viewerPermissions),
+		PermissionRecordingsRead,
+		PermissionRecordingsWrite,
+	)
+
+	dvrAdminPermissions = append(slices.Clone(recorderPermissions),
+		PermissionRecordingsManage,
+		PermissionDVRConfigManage,
+	)
+
+	adminPermissions = append(slices.Clone(dvrAdminPermissions),
+		PermissionStreamsManage,
+		PermissionAuditRead,
+		PermissionUsersManage,
+	)
+
+	rolePermissions = map[Role][]Permission{
+		RoleViewer:   viewerPermissions,
+		RoleRecorder: recorderPermissions,
+		RoleDVRAdmin: dvrAdminPermissions,
+		RoleAdmin:    adminPermissions,
+	}
+)
+
+// ParseRole parses a role by its name.
+func ParseRole(name string) (Role, error) {
+	role := Role(name)
+	if !role.Valid() {
+		return "", ErrRoleInvalid
+	}
+
+	return role, nil
+}
+
+// Valid checks if the role is known.
+func (r Role) Valid() bool {
+	_, ok := rolePermissions[r]
+	return ok
+}
+
+// Permissions returns the permissions granted by the role.
+func (r Role) Permissions() []Permission {
+	return rolePermissions[r]
+}
+
+// HasPermission checks if the role grants a permission.
+func (r Role) HasPermission(permission Permission) bool {
+	return slices.Contains(rolePermissions[r], permission)
+}
+
+// Valid checks if the permission is known.
+func (p Permission) Valid() bool {
+	return slices.Contains(adminPermissions, p)
+}
+
+type (
+	// RecordingOwnerRepository keeps track of the users who scheduled recordings,
+	// because tvheadend is not aware of tvhgo users.
+	RecordingOwnerRepository interface {
+		// FindUserID returns the id of the user owning a recording
+		// or nil if the owner of the recording is unknown.
+		FindUserID(ctx context.Context, recordingID string) (*int64, error)
+
+		// Create persists the owner of a recording.
+		Create(ctx context.Context, recordingID string, userID int64) error
+
+		// Delete deletes the owner of a recording.
+		Delete(ctx context.Context, recordingID string) error
+	}
+)
@@ -0,0 +1,55 @@
+package core_test
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/davidborzek/tvhgo/core"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestParseRole(t *testing.T) {
+	for _, role := range core.Roles {
+		parsed, err := core.ParseRole(string(role))
+		assert.Nil(t, err)
+		assert.Equal(t, role, parsed)
+	}
+
+	parsed, err := core.ParseRole("superuser")
+	assert.Equal(t, core.ErrRoleInvalid, err)
+	assert.Empty(t, parsed)
+}
+
+func TestRolesInheritPermissionsOfLowerRoles(t *testing.T) {
+	for i := 1; i < len(core.Roles); i++ {
+		for _, permission := range core.Roles[i-1].Permissions() {
+			assert.True(t, core.Roles[i].HasPermission(permission),
+				"%s should have permission %s", core.Roles[i], permission)
+		}
+	}
+}
+
+func TestRoleHasPermission(t *testing.T) {
+	assert.True(t, core.RoleViewer.HasPermission(core.PermissionStream))
+	assert.False(t, core.RoleViewer.HasPermission(core.PermissionRecordingsRead))
+
+	assert.True(t, core.RoleRecorder.HasPermission(core.PermissionRecordingsWrite))
+	assert.False(t, core.RoleRecorder.HasPermission(core.PermissionRecordingsManage))
+
+	assert.True(t, core.RoleDVRAdmin.HasPermission(core.PermissionDVRConfigManage))
+	assert.False(t, core.RoleDVRAdmin.HasPermission(core.PermissionUsersManage))
+
+	assert.True(t, core.RoleAdmin.HasPermission(core.PermissionUsersManage))
+	assert.False(t, core.Role("unknown").HasPermission(core.PermissionEPGRead))
+}
+
+func TestUserMarshalJSONContainsIsAdmin(t *testing.T) {
+	b, err := json.Marshal(&core.User{ID: 1, Role: core.RoleAdmin})
+	assert.Nil(t, err)
+
+	var out map[string]any
+	assert.Nil(t, json.Unmarshal(b, &out))
+	assert.Equal(t, "admin", out["role"])
+	assert.Equal(t, true, out["isAdmin"])
+	assert.Equal(t, float64(1), out["id"])
+}
@@ -15,6 +15,8 @@ type (
 		CreatedAt   int64  `json:"createdAt"`
 		LastUsedAt  int64  `json:"lastUsedAt"`
 		RotatedAt   int64  `json:"-"`
+		// DeviceName is set for sessions of devices authorized via the device flow.
+		DeviceName string `json:"deviceName,omitempty"`
 	}
 
 	// SessionRepository defines CRUD operations for working with sessions.
@@ -34,6 +36,10 @@ type (
 		// Delete deletes a session.
 		Delete(ctx context.Context, sessionID int64, userID int64) error
 
+		// DeleteByUser deletes all sessions of a user except the optional session
+		// and returns the number of deleted sessions.
+		DeleteByUser(ctx context.Context, userID int64, exceptSessionID *int64) (int64, error)
+
 		// DeleteExpired deletes all expired sessions.
 		DeleteExpired(
 			ctx context.Context,
@@ -2,9 +2,43 @@ package core
 
 import (
 	"context"
+	"errors"
+	"fmt"
 	"net/http"
 )
 
+var (
+	ErrStreamSessionNotFound   = errors.New("stream session not found")
+	ErrStreamSessionTerminated = errors.New("stream session was terminated")
+
+	ErrStreamLimitPerUserReached = errors.New("maximum number of concurrent streams per user reached")
+	ErrStreamLimitReached        = errors.New("maximum number of concurrent streams reached")
+	ErrTunersReserved            = errors.New("remaining tuners are reserved for upcoming recordings")
+
+	ErrStreamSignatureInvalid = errors.New("stream signature invalid")
+	ErrStreamSignatureExpired = errors.New("stream signature expired")
+)
+
+// ChannelStreamResource returns the signed stream resource of a channel.
+func ChannelStreamResource(number int64) string {
+	return fmt.Sprintf("channel:%d", number)
+}
+
+// RecordingStreamResource returns the signed stream resource of a recording.
+func RecordingStreamResource(id string) string {
+	return "recording:" + id
+}
+
+// StreamType defines the type of a stream.
+type StreamType string
+
+const (
+	// StreamTypeChannel is a live stream of a channel.
+	StreamTypeChannel StreamType = "channel"
+	// StreamTypeRecording is a stream of a recording.
+	StreamTypeRecording StreamType = "recording"
+)
+
 type (
 	StreamingService interface {
 		// GetChannelStream returns a raw http response of the channel stream.
@@ -14,7 +48,88 @@ type (
 			profile string,
 		) (*http.Response, error)
 
+		// IsChannelStreamRunning returns true, if clients of the channel
+		// and profile already share a running upstream subscription.
+		IsChannelStreamRunning(channelNumber int64, profile string) bool
+
 		// GetRecordingStream returns a raw http response of the recording stream.
 		GetRecordingStream(ctx context.Context, recordingId string) (*http.Response, error)
 	}
+
+	// StreamSession represents an active stream of a user.
+	StreamSession struct {
+		ID     string     `json:"id"`
+		UserID int64      `json:"userId"`
+		Type   StreamType `json:"type"`
+		// ChannelNumber is the number of the streamed channel.
+		// Only set for streams of type channel.
+		ChannelNumber int64 `json:"channelNumber,omitempty"`
+		// RecordingID is the id of the streamed recording.
+		// Only set for streams of type recording.
+		RecordingID      string `json:"recordingId,omitempty"`
+		Profile          string `json:"profile"`
+		ClientIP         string `json:"clientIp"`
+		UserAgent        string `json:"userAgent"`
+		StartedAt        int64  `json:"startedAt"`
+		BytesTransferred int64  `json:"bytesTransferred"`
+	}
+
+	// StreamSessionRegistry keeps track of the active stream sessions.
+	StreamSessionRegistry interface {
+		// Start registers a new stream session and returns its id together with
+		// a context derived from ctx, which is cancelled when the session is terminated.
+		Start(ctx context.Context, session StreamSession) (string, context.Context)
+
+		// Reserve registers a new stream session like Start, if check allows it.
+		// check is called with the active sessions under the same lock as the
+		// registration, so that concurrent streams can't exceed a limit.
+		Reserve(
+			ctx context.Context,
+			session StreamSession,
+			check func(active []*StreamSession) error,
+		) (string, context.Context, error)
+
+		// AddBytes adds the number of transferred bytes to a stream session.
+		AddBytes(id string, n int64)
+
+		// Stop removes a stream session from the registry.
+		Stop(id string)
+
+		// List returns a snapshot of all active stream sessions.
+		List() []*StreamSession
+
+		// Terminate cancels an active stream session.
+		Terminate(id string) error
+	}
+
+	// StreamLimiter decides whether a new stream is allowed to start.
+	StreamLimiter interface {
+		// StartChannelStream checks if the user of the session is allowed to start
+		// a new live stream and registers the session. It returns the session id
+		// together with a context, which is cancelled when the session is terminated.
+		StartChannelStream(ctx context.Context, session StreamSession) (string, context.Context, error)
+	}
+
+	// StreamSignature is a signature which grants
+	// access to a single stream until it expires.
+	StreamSignature struct {
+		UserID int64
+		// Scopes are the scopes of the signer, which restrict the
+		// permissions of the signed stream. If nil, all permissions of the user are granted.
+		Scopes    []Permission
+		Resource  string
+		Profile   string
+		Expires   int64
+		Signature string
+	}
+
+	// StreamSigner signs and verifies stream urls for clients
+	// which can't authenticate via session or token.
+	StreamSigner interface {
+		// Sign creates a signature for a user restricted to scopes, a stream resource and a profile.
+		Sign(userID int64, scopes []Permission, resource string, profile string) StreamSignature
+
+		// Verify verifies a stream signature.
+		Verify(signature StreamSignature) error
+	}
 )
@@ -1,6 +1,14 @@
 package core
 
-import "context"
+import (
+	"context"
+	"errors"
+)
+
+var (
+	ErrTokenScopeInvalid  = errors.New("invalid token scope")
+	ErrTokenExpiryInvalid = errors.New("token expiry must be in the future")
+)
 
 type (
 	Token struct {
@@ -8,8 +16,23 @@ type (
 		UserID      int64  `json:"-"`
 		Name        string `json:"name"`
 		HashedToken string `json:"-"`
-		CreatedAt   int64  `json:"createdAt"`
-		UpdatedAt   int64  `json:"updatedAt"`
+		// ExpiresAt is the time the token expires at.
+		// Tokens without expiry are valid until they are revoked.
+		ExpiresAt *int64 `json:"expiresAt"`
+		// Scopes restrict the token to a subset of the permissions of the user.
+		// Tokens without scopes have all permissions of the user.
+		Scopes     []Permission `json:"scopes"`
+		LastUsedAt *int64       `json:"lastUsedAt"`
+		LastUsedIP *string      `json:"lastUsedIp"`
+		CreatedAt  int64        `json:"createdAt"`
+		UpdatedAt  int64        `json:"updatedAt"`
+	}
+
+	// CreateToken defines the options of a new token.
+	CreateToken struct {
+		Name      string
+		ExpiresAt *int64
+		Scopes    []Permission
 	}
 
 	// TokenRepository defines CRUD operations working with Tokens.
@@ -23,7 +46,28 @@ type (
 		// Create persists a new Token.
 		Create(ctx context.Context, token *Token) error
 
-		// Delete deletes a Token.
+		// UpdateLastUsed persists the last usage of a Token.
+		UpdateLastUsed(ctx context.Context, token *Token) error
+
+		// Delete deletes a Token of a user.
 		Delete(ctx context.Context, token *Token) error
+
+		// DeleteByUser deletes all Tokens of a user and returns the number of deleted Tokens.
+		DeleteByUser(ctx context.Context, userID int64) (int64, error)
 	}
 )
+
+// Validate validates the scopes and the expiry of a new token.
+func (c *CreateToken) Validate(now int64) error {
+	for _, scope := range c.Scopes {
+		if !scope.Valid() {
+			return ErrTokenScopeInvalid
+		}
+	}
+
+	if c.ExpiresAt != nil && *c.ExpiresAt <= now {
+		return ErrTokenExpiryInvalid
+	}
+
+	return nil
+}
@@ -0,0 +1,40 @@
+package core_test
+
+import (
+	"testing"
+
+	"github.com/davidborzek/tvhgo/core"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestCreateTokenValidate(t *testing.T) {
+	now := int64(1672527600)
+	future := now + 1
+	past := now
+
+	assert.Nil(t, (&core.CreateToken{}).Validate(now))
+	assert.Nil(t, (&core.CreateToken{
+		ExpiresAt: &future,
+		Scopes:    []core.Permission{core.PermissionEPGRead, core.PermissionStream},
+	}).Validate(now))
+
+	assert.Equal(t, core.ErrTokenScopeInvalid, (&core.CreateToken{
+		Scopes: []core.Permission{"unknown"},
+	}).Validate(now))
+
+	assert.Equal(t, core.ErrTokenExpiryInvalid, (&core.CreateToken{
+		ExpiresAt: &past,
+	}).Validate(now))
+}
+
+func TestAuthContextHasScope(t *testing.T) {
+	unrestricted := &core.AuthContext{}
+	assert.True(t, unrestricted.HasScope(core.PermissionUsersManage))
+
+	scoped := &core.AuthContext{Scopes: []core.Permission{core.PermissionEPGRead}}
+	assert.True(t, scoped.HasScope(core.PermissionEPGRead))
+	assert.False(t, scoped.HasScope(core.PermissionStream))
+
+	empty := &core.AuthContext{Scopes: []core.Permission{}}
+	assert.False(t, empty.HasScope(core.PermissionEPGRead))
+}
@@ -0,0 +1,79 @@
+package core
+
+import (
+	"context"
+	"errors"
+)
+
+var (
+	ErrTrustedDevicesDisabled       = errors.New("trusted devices are not enabled")
+	ErrTrustedDeviceLifetimeInvalid = errors.New("trusted device lifetime must be between one day and the maximum lifetime")
+)
+
+type (
+	// TrustedDevice is a device on which the second factor
+	// is skipped at login until it expires or is revoked.
+	TrustedDevice struct {
+		ID          int64  `json:"id"`
+		UserID      int64  `json:"userId"`
+		HashedToken string `json:"-"`
+		ClientIP    string `json:"clientIp"`
+		UserAgent   string `json:"userAgent"`
+		ExpiresAt   int64  `json:"expiresAt"`
+		LastUsedAt  int64  `json:"lastUsedAt"`
+		CreatedAt   int64  `json:"createdAt"`
+	}
+
+	// TrustedDeviceRepository defines CRUD operations for working with trusted devices.
+	TrustedDeviceRepository interface {
+		// Find returns a trusted device by its hashed token.
+		Find(ctx context.Context, hashedToken string) (*TrustedDevice, error)
+
+		// FindByUser returns the trusted devices of a user.
+		FindByUser(ctx context.Context, userID int64) ([]*TrustedDevice, error)
+
+		// Create persists a new trusted device.
+		Create(ctx context.Context, device *TrustedDevice) error
+
+		// Update persists the last usage of a trusted device.
+		Update(ctx context.Context, device *TrustedDevice) error
+
+		// Delete deletes a trusted device of a user.
+		Delete(ctx context.Context, id int64, userID int64) error
+
+		// DeleteByUser deletes all trusted devices of a user
+		// and returns the number of deleted devices.
+		DeleteByUser(ctx context.Context, userID int64) (int64, error)
+
+		// DeleteExpired deletes all trusted devices expired before a unix timestamp.
+		DeleteExpired(ctx context.Context, before int64) (int64, error)
+	}
+
+	// TrustedDeviceService manages devices on which users
+	// skip the second factor at login.
+	TrustedDeviceService interface {
+		// Trust creates a trusted device of a user for a number of days
+		// and returns the token, which must be stored in the device cookie.
+		Trust(
+			ctx context.Context,
+			userID int64,
+			days int,
+			clientIP string,
+			userAgent string,
+		) (string, *TrustedDevice, error)
+
+		// Verify returns the trusted device of a token or nil if the
+		// token is unknown or expired.
+		Verify(ctx context.Context, token string) (*TrustedDevice, error)
+
+		// List returns the trusted devices of a user.
+		List(ctx context.Context, userID int64) ([]*TrustedDevice, error)
+
+		// Revoke revokes a trusted device of a user.
+		Revoke(ctx context.Context, id int64, userID int64) error
+
+		// RevokeAll revokes all trusted devices of a user
+		// and returns the number of revoked devices.
+		RevokeAll(ctx context.Context, userID int64) (int64, error)
+	}
+)
@@ -26,5 +26,23 @@ type (
 		Update(ctx context.Context, settings *TwoFactorSettings) error
 
 		Save(ctx context.Context, settings *TwoFactorSettings) error
+
+		// Reencrypt encrypts all secrets, which are unencrypted or encrypted with
+		// an old key, with the current key. It returns the number of updated settings.
+		Reencrypt(ctx context.Context) (int, error)
+	}
+
+	// TwoFactorRecoveryCodeRepository defines operations working with
+	// hashed single-use recovery codes of a user.
+	TwoFactorRecoveryCodeRepository interface {
+		// Replace replaces all recovery codes of a user with new hashed codes.
+		Replace(ctx context.Context, userID int64, hashedCodes []string) error
+
+		// Consume deletes a hashed recovery code of a user and
+		// returns true if the code existed.
+		Consume(ctx context.Context, userID int64, hashedCode string) (bool, error)
+
+		// DeleteByUser deletes all recovery codes of a user.
+		DeleteByUser(ctx context.Context, userID int64) error
 	}
 )
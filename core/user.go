@@ -2,12 +2,14 @@ package core
 
 import (
 	"context"
+	"encoding/json"
 	"errors"
 )
 
 var (
 	ErrUsernameAlreadyExists = errors.New("username already exists")
 	ErrEmailAlreadyExists    = errors.New("email already exists")
+	ErrUserDisabled          = errors.New("user is disabled")
 )
 
 type (
@@ -20,9 +22,11 @@ type (
 		Email        string `json:"email"`
 		DisplayName  string `json:"displayName"`
 		TwoFactor    bool   `json:"twoFactor"`
-		IsAdmin      bool   `json:"isAdmin"`
-		CreatedAt    int64  `json:"createdAt"`
-		UpdatedAt    int64  `json:"updatedAt"`
+		Role         Role   `json:"role"`
+		// Disabled users can't log in and are denied all permissions.
+		Disabled  bool  `json:"disabled"`
+		CreatedAt int64 `json:"createdAt"`
+		UpdatedAt int64 `json:"updatedAt"`
 	}
 
 	UserListResult ListResult[*User]
@@ -56,3 +60,25 @@ type (
 		Delete(ctx context.Context, user *User) error
 	}
 )
+
+// IsAdmin checks if the user has the admin role.
+func (u *User) IsAdmin() bool {
+	return u.Role == RoleAdmin
+}
+
+// HasPermission checks if the role of the user grants a permission.
+func (u *User) HasPermission(permission Permission) bool {
+	return u.Role.HasPermission(permission)
+}
+
+// MarshalJSON adds the isAdmin flag for clients, which are not aware of roles.
+func (u User) MarshalJSON() ([]byte, error) {
+	type user User
+	return json.Marshal(struct {
+		user
+		IsAdmin bool `json:"isAdmin"`
+	}{
+		user:    user(u),
+		IsAdmin: u.IsAdmin(),
+	})
+}
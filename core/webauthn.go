@@ -0,0 +1,89 @@
+package core
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+)
+
+var (
+	ErrWebAuthnCeremonyInvalid    = errors.New("webauthn ceremony is invalid or expired")
+	ErrWebAuthnVerificationFailed = errors.New("webauthn verification failed")
+	ErrWebAuthnCredentialNotFound = errors.New("webauthn credential not found")
+)
+
+type (
+	// WebAuthnCredential is a passkey or security key registered by a user.
+	WebAuthnCredential struct {
+		ID     int64  `json:"id"`
+		UserID int64  `json:"-"`
+		Name   string `json:"name"`
+		// CredentialID is the base64url encoded id of the credential.
+		CredentialID string `json:"-"`
+		// Data is the encoded credential record containing the public key.
+		Data       string `json:"-"`
+		LastUsedAt *int64 `json:"lastUsedAt"`
+		CreatedAt  int64  `json:"createdAt"`
+		UpdatedAt  int64  `json:"updatedAt"`
+	}
+
+	// WebAuthnCredentialRepository defines CRUD operations working with WebAuthnCredentials.
+	WebAuthnCredentialRepository interface {
+		// FindById returns a credential by its id.
+		FindById(ctx context.Context, id int64) (*WebAuthnCredential, error)
+
+		// FindByCredentialID returns a credential by the base64url encoded credential id.
+		FindByCredentialID(ctx context.Context, credentialID string) (*WebAuthnCredential, error)
+
+		// FindByUser returns all credentials of a user.
+		FindByUser(ctx context.Context, userID int64) ([]*WebAuthnCredential, error)
+
+		// Create persists a new credential.
+		Create(ctx context.Context, credential *WebAuthnCredential) error
+
+		// Update updates a credential.
+		Update(ctx context.Context, credential *WebAuthnCredential) error
+
+		// Delete deletes a credential.
+		Delete(ctx context.Context, credential *WebAuthnCredential) error
+	}
+
+	// WebAuthnCeremony is a started registration or login ceremony.
+	WebAuthnCeremony struct {
+		// ID identifies the ceremony when it is finished.
+		ID string `json:"id"`
+		// Options must be passed to navigator.credentials.create()
+		// or navigator.credentials.get() by the client.
+		Options json.RawMessage `json:"options" swaggertype:"object"`
+	}
+
+	// WebAuthnService defines operations to register and log in with WebAuthn credentials.
+	WebAuthnService interface {
+		// BeginRegistration starts the registration of a new credential for a user.
+		BeginRegistration(ctx context.Context, userID int64) (*WebAuthnCeremony, error)
+
+		// FinishRegistration verifies the response of the authenticator and stores the credential.
+		FinishRegistration(
+			ctx context.Context,
+			userID int64,
+			ceremonyID string,
+			name string,
+			response []byte,
+		) (*WebAuthnCredential, error)
+
+		// BeginLogin starts a login, in which the client may choose any discoverable credential.
+		BeginLogin(ctx context.Context) (*WebAuthnCeremony, error)
+
+		// FinishLogin verifies the assertion of the authenticator and returns the user.
+		FinishLogin(ctx context.Context, ceremonyID string, response []byte) (*User, error)
+
+		// GetCredentials returns the credentials of a user.
+		GetCredentials(ctx context.Context, userID int64) ([]*WebAuthnCredential, error)
+
+		// RenameCredential renames a credential of a user.
+		RenameCredential(ctx context.Context, userID int64, id int64, name string) (*WebAuthnCredential, error)
+
+		// DeleteCredential deletes a credential of a user.
+		DeleteCredential(ctx context.Context, userID int64, id int64) error
+	}
+)
@@ -0,0 +1,11 @@
+CREATE TABLE IF NOT EXISTS webauthn_credential (
+    id SERIAL PRIMARY KEY,
+    user_id INTEGER NOT NULL,
+    name TEXT NOT NULL,
+    credential_id TEXT UNIQUE NOT NULL,
+    data TEXT NOT NULL,
+    last_used_at INTEGER,
+    created_at INTEGER NOT NULL,
+    updated_at INTEGER NOT NULL,
+    CONSTRAINT fk_user FOREIGN KEY(user_id) REFERENCES "user"(id) ON DELETE CASCADE
+);
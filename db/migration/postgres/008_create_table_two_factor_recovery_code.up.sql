@@ -0,0 +1,8 @@
+CREATE TABLE IF NOT EXISTS two_factor_recovery_code (
+    id SERIAL PRIMARY KEY,
+    user_id INTEGER NOT NULL,
+    hashed_code TEXT NOT NULL,
+    created_at INTEGER NOT NULL,
+    UNIQUE(user_id, hashed_code),
+    CONSTRAINT fk_user FOREIGN KEY(user_id) REFERENCES "user"(id) ON DELETE CASCADE
+);
@@ -0,0 +1,9 @@
+ALTER TABLE "user"
+ADD COLUMN is_admin BOOLEAN DEFAULT false;
+
+UPDATE "user"
+SET
+    is_admin = role = 'admin';
+
+ALTER TABLE "user"
+DROP COLUMN role;
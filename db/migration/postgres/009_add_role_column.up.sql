@@ -0,0 +1,12 @@
+ALTER TABLE "user"
+ADD COLUMN role TEXT NOT NULL DEFAULT 'dvr-admin';
+
+UPDATE "user"
+SET
+    role = CASE
+        WHEN is_admin THEN 'admin'
+        ELSE 'dvr-admin'
+    END;
+
+ALTER TABLE "user"
+DROP COLUMN is_admin;
@@ -0,0 +1 @@
+DROP TABLE IF EXISTS recording_owner;
@@ -0,0 +1,11 @@
+ALTER TABLE token
+ADD COLUMN expires_at INTEGER;
+
+ALTER TABLE token
+ADD COLUMN scopes TEXT NOT NULL DEFAULT '';
+
+ALTER TABLE token
+ADD COLUMN last_used_at INTEGER;
+
+ALTER TABLE token
+ADD COLUMN last_used_ip TEXT;
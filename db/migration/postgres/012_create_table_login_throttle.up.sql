@@ -0,0 +1,9 @@
+CREATE TABLE IF NOT EXISTS login_throttle (
+    id SERIAL PRIMARY KEY,
+    kind TEXT NOT NULL,
+    key TEXT NOT NULL,
+    failed_attempts INTEGER NOT NULL,
+    last_failed_at INTEGER NOT NULL,
+    blocked_until INTEGER NOT NULL,
+    UNIQUE(kind, key)
+);
@@ -0,0 +1,11 @@
+CREATE TABLE IF NOT EXISTS trusted_device (
+    id SERIAL PRIMARY KEY,
+    user_id INTEGER NOT NULL,
+    hashed_token TEXT UNIQUE NOT NULL,
+    client_ip TEXT NOT NULL,
+    user_agent TEXT NOT NULL,
+    expires_at INTEGER NOT NULL,
+    last_used_at INTEGER NOT NULL,
+    created_at INTEGER NOT NULL,
+    CONSTRAINT fk_user FOREIGN KEY(user_id) REFERENCES "user"(id) ON DELETE CASCADE
+);
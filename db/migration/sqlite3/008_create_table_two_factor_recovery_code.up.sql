@@ -0,0 +1,8 @@
+CREATE TABLE IF NOT EXISTS two_factor_recovery_code (
+    id INTEGER PRIMARY KEY AUTOINCREMENT,
+    user_id INTEGER NOT NULL,
+    hashed_code TEXT NOT NULL,
+    created_at INTEGER NOT NULL,
+    UNIQUE(user_id, hashed_code),
+    FOREIGN KEY(user_id) REFERENCES user(id) ON DELETE CASCADE
+);
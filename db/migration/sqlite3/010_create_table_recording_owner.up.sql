@@ -0,0 +1,6 @@
+CREATE TABLE IF NOT EXISTS recording_owner (
+    recording_id TEXT PRIMARY KEY,
+    user_id INTEGER NOT NULL,
+    created_at INTEGER NOT NULL,
+    FOREIGN KEY(user_id) REFERENCES user(id) ON DELETE CASCADE
+);
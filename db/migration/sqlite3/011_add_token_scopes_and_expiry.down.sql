@@ -0,0 +1,11 @@
+ALTER TABLE token
+DROP COLUMN expires_at;
+
+ALTER TABLE token
+DROP COLUMN scopes;
+
+ALTER TABLE token
+DROP COLUMN last_used_at;
+
+ALTER TABLE token
+DROP COLUMN last_used_ip;
@@ -0,0 +1,2 @@
+ALTER TABLE session
+ADD COLUMN device_name TEXT NOT NULL DEFAULT '';
@@ -0,0 +1,14 @@
+CREATE TABLE IF NOT EXISTS device_authorization (
+    id INTEGER PRIMARY KEY AUTOINCREMENT,
+    hashed_device_code TEXT UNIQUE NOT NULL,
+    user_code TEXT UNIQUE NOT NULL,
+    device_name TEXT NOT NULL,
+    client_ip TEXT NOT NULL,
+    status TEXT NOT NULL,
+    user_id INTEGER,
+    scopes TEXT NOT NULL DEFAULT '',
+    expires_at INTEGER NOT NULL,
+    last_polled_at INTEGER NOT NULL,
+    created_at INTEGER NOT NULL,
+    FOREIGN KEY(user_id) REFERENCES user(id) ON DELETE CASCADE
+);
@@ -0,0 +1,2 @@
+ALTER TABLE user
+ADD COLUMN disabled BOOLEAN NOT NULL DEFAULT FALSE;
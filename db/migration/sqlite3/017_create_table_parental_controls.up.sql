@@ -0,0 +1,11 @@
+CREATE TABLE IF NOT EXISTS parental_controls (
+    user_id INTEGER PRIMARY KEY,
+    channels TEXT NOT NULL DEFAULT '',
+    channel_tags TEXT NOT NULL DEFAULT '',
+    blocked_content_types TEXT NOT NULL DEFAULT '',
+    viewing_from TEXT NOT NULL DEFAULT '',
+    viewing_to TEXT NOT NULL DEFAULT '',
+    created_at INTEGER NOT NULL,
+    updated_at INTEGER NOT NULL,
+    FOREIGN KEY(user_id) REFERENCES user(id) ON DELETE CASCADE
+);
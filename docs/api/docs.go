@@ -1,5 +1,4 @@
-// Code generated by swaggo/swag. DO NOT EDIT.
-
+// Package api Code generated by swaggo/swag. DO NOT EDIT
 package api
 
 import "github.com/swaggo/swag"
@@ -16,7 +15,7 @@ const docTemplate = `{
     "host": "{{.Host}}",
     "basePath": "{{.BasePath}}",
     "paths": {
-        "/channels": {
+        "/audit": {
             "get": {
                 "security": [
                     {
@@ -27,32 +26,118 @@ const docTemplate = `{
                     "application/json"
                 ],
                 "tags": [
-                    "channels"
+                    "audit"
                 ],
-                "summary": "Get list of channels",
+                "summary": "Get the security audit log",
                 "parameters": [
+                    {
+                        "enum": [
+                            "login",
+                            "login_failed",
+                            "logout",
+                            "session_revoke",
+                            "session_revoke_all",
+                            "token_create",
+                            "token_revoke",
+                            "token_revoke_all",
+                            "password_change",
+                            "two_factor_activate",
+                            "two_factor_deactivate",
+                            "two_factor_recovery_codes",
+                            "passkey_register",
+                            "passkey_delete",
+                            "device_approve",
+                            "device_deny",
+                            "trusted_device_create",
+                            "trusted_device_revoke",
+                            "trusted_device_revoke_all",
+                            "user_create",
+                            "user_delete",
+                            "user_role_update",
+                            "user_update",
+                            "user_disable",
+                            "user_enable",
+                            "password_reset",
+                            "parental_controls_update",
+                            "parental_controls_delete",
+                            "lockout_clear",
+                            "recording_cancel",
+                            "recording_remove",
+                            "dvr_config_delete"
+                        ],
+                        "type": "string",
+                        "x-enum-varnames": [
+                            "AuditActionLogin",
+                            "AuditActionLoginFailed",
+                            "AuditActionLogout",
+                            "AuditActionSessionRevoke",
+                            "AuditActionSessionRevokeAll",
+                            "AuditActionTokenCreate",
+                            "AuditActionTokenRevoke",
+                            "AuditActionTokenRevokeAll",
+                            "AuditActionPasswordChange",
+                            "AuditActionTwoFactorActivate",
+                            "AuditActionTwoFactorDeactivate",
+                            "AuditActionTwoFactorRecoveryCodes",
+                            "AuditActionPasskeyRegister",
+                            "AuditActionPasskeyDelete",
+                            "AuditActionDeviceApprove",
+                            "AuditActionDeviceDeny",
+                            "AuditActionTrustedDeviceCreate",
+                            "AuditActionTrustedDeviceRevoke",
+                            "AuditActionTrustedDeviceRevokeAll",
+                            "AuditActionUserCreate",
+                            "AuditActionUserDelete",
+                            "AuditActionUserRoleUpdate",
+                            "AuditActionUserUpdate",
+                            "AuditActionUserDisable",
+                            "AuditActionUserEnable",
+                            "AuditActionPasswordReset",
+                            "AuditActionParentalControlsUpdate",
+                            "AuditActionParentalControlsDelete",
+                            "AuditActionLockoutClear",
+                            "AuditActionRecordingCancel",
+                            "AuditActionRecordingRemove",
+                            "AuditActionDVRConfigDelete"
+                        ],
+                        "description": "(Optional) Filter by action.",
+                        "name": "action",
+                        "in": "query"
+                    },
                     {
                         "type": "integer",
-                        "description": "Limit",
+                        "description": "(Optional) Filter entries created at or after a unix timestamp.",
+                        "name": "from",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "description": "(Optional) Limit the result.",
                         "name": "limit",
                         "in": "query"
                     },
                     {
                         "type": "integer",
-                        "description": "Offset",
+                        "description": "(Optional) Offset the result.",
                         "name": "offset",
                         "in": "query"
                     },
                     {
-                        "type": "string",
-                        "description": "Sort key",
-                        "name": "sort_key",
+                        "type": "integer",
+                        "description": "(Optional) Filter entries created before a unix timestamp.",
+                        "name": "to",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "description": "(Optional) Filter by the id of the acting user.",
+                        "name": "userID",
                         "in": "query"
                     },
                     {
                         "type": "string",
-                        "description": "Sort direction",
-                        "name": "sort_dir",
+                        "description": "(Optional) Filter by the name of the acting user.",
+                        "name": "username",
                         "in": "query"
                     }
                 ],
@@ -60,10 +145,7 @@ const docTemplate = `{
                     "200": {
                         "description": "OK",
                         "schema": {
-                            "type": "array",
-                            "items": {
-                                "$ref": "#/definitions/core.Channel"
-                            }
+                            "$ref": "#/definitions/core.AuditListResult"
                         }
                     },
                     "400": {
@@ -78,6 +160,12 @@ const docTemplate = `{
                             "$ref": "#/definitions/response.ErrorResponse"
                         }
                     },
+                    "403": {
+                        "description": "Forbidden",
+                        "schema": {
+                            "$ref": "#/definitions/response.ErrorResponse"
+                        }
+                    },
                     "500": {
                         "description": "Internal Server Error",
                         "schema": {
@@ -87,26 +175,24 @@ const docTemplate = `{
                 }
             }
         },
-        "/channels/{id}": {
-            "get": {
-                "security": [
-                    {
-                        "JWT": []
-                    }
+        "/auth/device/code": {
+            "post": {
+                "consumes": [
+                    "application/x-www-form-urlencoded"
                 ],
                 "produces": [
                     "application/json"
                 ],
                 "tags": [
-                    "channels"
+                    "device-auth"
                 ],
-                "summary": "Get a channel by id",
+                "summary": "Starts the device authorization grant for a device",
                 "parameters": [
                     {
                         "type": "string",
-                        "description": "Channel id",
-                        "name": "id",
-                        "in": "path",
+                        "description": "Friendly name of the device",
+                        "name": "device_name",
+                        "in": "formData",
                         "required": true
                     }
                 ],
@@ -114,17 +200,17 @@ const docTemplate = `{
                     "200": {
                         "description": "OK",
                         "schema": {
-                            "$ref": "#/definitions/core.Channel"
+                            "$ref": "#/definitions/core.DeviceCode"
                         }
                     },
-                    "401": {
-                        "description": "Unauthorized",
+                    "400": {
+                        "description": "Bad Request",
                         "schema": {
                             "$ref": "#/definitions/response.ErrorResponse"
                         }
                     },
-                    "404": {
-                        "description": "Not Found",
+                    "429": {
+                        "description": "Too Many Requests",
                         "schema": {
                             "$ref": "#/definitions/response.ErrorResponse"
                         }
@@ -138,42 +224,49 @@ const docTemplate = `{
                 }
             }
         },
-        "/channels/{number}/stream": {
-            "get": {
-                "security": [
-                    {
-                        "JWT": []
-                    }
+        "/auth/device/token": {
+            "post": {
+                "consumes": [
+                    "application/x-www-form-urlencoded"
                 ],
                 "produces": [
-                    "video/*",
                     "application/json"
                 ],
                 "tags": [
-                    "channels"
+                    "device-auth"
                 ],
-                "summary": "Stream a channel by channel number",
+                "summary": "Polls for the session or token of an authorized device",
                 "parameters": [
                     {
                         "type": "string",
-                        "description": "Channel number",
-                        "name": "number",
-                        "in": "path",
+                        "description": "urn:ietf:params:oauth:grant-type:device_code",
+                        "name": "grant_type",
+                        "in": "formData",
                         "required": true
                     },
                     {
                         "type": "string",
-                        "description": "Streaming profile",
-                        "name": "profile",
-                        "in": "query"
+                        "description": "Device code",
+                        "name": "device_code",
+                        "in": "formData",
+                        "required": true
                     }
                 ],
                 "responses": {
                     "200": {
-                        "description": "OK"
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/core.DeviceToken"
+                        }
                     },
-                    "401": {
-                        "description": "Unauthorized",
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/api.deviceTokenError"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
                         "schema": {
                             "$ref": "#/definitions/response.ErrorResponse"
                         }
@@ -181,23 +274,34 @@ const docTemplate = `{
                 }
             }
         },
-        "/dvr/config": {
+        "/auth/device/{code}": {
             "get": {
+                "security": [
+                    {
+                        "JWT": []
+                    }
+                ],
                 "produces": [
                     "application/json"
                 ],
                 "tags": [
-                    "dvr"
+                    "device-auth"
+                ],
+                "summary": "Get a pending device authorization by the user code",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "User code",
+                        "name": "code",
+                        "in": "path",
+                        "required": true
+                    }
                 ],
-                "summary": "Get list of dvr configs",
                 "responses": {
                     "200": {
                         "description": "OK",
                         "schema": {
-                            "type": "array",
-                            "items": {
-                                "$ref": "#/definitions/core.DVRConfig"
-                            }
+                            "$ref": "#/definitions/core.DeviceAuthorization"
                         }
                     },
                     "401": {
@@ -206,6 +310,12 @@ const docTemplate = `{
                             "$ref": "#/definitions/response.ErrorResponse"
                         }
                     },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/response.ErrorResponse"
+                        }
+                    },
                     "500": {
                         "description": "Internal Server Error",
                         "schema": {
@@ -215,29 +325,49 @@ const docTemplate = `{
                 }
             }
         },
-        "/dvr/config/{id}": {
-            "get": {
+        "/auth/device/{code}/approve": {
+            "put": {
+                "security": [
+                    {
+                        "JWT": []
+                    }
+                ],
                 "produces": [
                     "application/json"
                 ],
                 "tags": [
-                    "dvr"
+                    "device-auth"
                 ],
-                "summary": "Get a dvr configs by id",
+                "summary": "Approves a pending device authorization",
                 "parameters": [
                     {
                         "type": "string",
-                        "description": "DVR Config ID",
-                        "name": "id",
+                        "description": "User code",
+                        "name": "code",
                         "in": "path",
                         "required": true
+                    },
+                    {
+                        "description": "Body",
+                        "name": "body",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/api.approveDeviceRequest"
+                        }
                     }
                 ],
                 "responses": {
                     "200": {
                         "description": "OK",
                         "schema": {
-                            "$ref": "#/definitions/core.DVRConfig"
+                            "$ref": "#/definitions/core.DeviceAuthorization"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/response.ErrorResponse"
                         }
                     },
                     "401": {
@@ -246,6 +376,18 @@ const docTemplate = `{
                             "$ref": "#/definitions/response.ErrorResponse"
                         }
                     },
+                    "403": {
+                        "description": "Forbidden",
+                        "schema": {
+                            "$ref": "#/definitions/response.ErrorResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/response.ErrorResponse"
+                        }
+                    },
                     "500": {
                         "description": "Internal Server Error",
                         "schema": {
@@ -253,27 +395,37 @@ const docTemplate = `{
                         }
                     }
                 }
-            },
-            "delete": {
+            }
+        },
+        "/auth/device/{code}/deny": {
+            "put": {
+                "security": [
+                    {
+                        "JWT": []
+                    }
+                ],
                 "produces": [
                     "application/json"
                 ],
                 "tags": [
-                    "dvr"
+                    "device-auth"
                 ],
-                "summary": "Deletes a dvr config by id",
+                "summary": "Denies a pending device authorization",
                 "parameters": [
                     {
                         "type": "string",
-                        "description": "DVR config ID",
-                        "name": "id",
+                        "description": "User code",
+                        "name": "code",
                         "in": "path",
                         "required": true
                     }
                 ],
                 "responses": {
-                    "204": {
-                        "description": "No Content"
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/core.DeviceAuthorization"
+                        }
                     },
                     "401": {
                         "description": "Unauthorized",
@@ -296,52 +448,31 @@ const docTemplate = `{
                 }
             }
         },
-        "/epg": {
+        "/auth/oidc/callback": {
             "get": {
-                "security": [
-                    {
-                        "JWT": []
-                    }
-                ],
-                "produces": [
-                    "application/json"
-                ],
                 "tags": [
-                    "epg"
+                    "auth"
                 ],
-                "summary": "Get epg",
+                "summary": "Callback of the OpenID Connect provider",
                 "parameters": [
                     {
                         "type": "string",
-                        "description": "Sort key",
-                        "name": "sort_key",
-                        "in": "query"
+                        "description": "Authorization code",
+                        "name": "code",
+                        "in": "query",
+                        "required": true
                     },
                     {
                         "type": "string",
-                        "description": "Sort direction",
-                        "name": "sort_dir",
-                        "in": "query"
-                    },
-                    {
-                        "type": "integer",
-                        "description": "Start timestamp",
-                        "name": "startsAt",
-                        "in": "query"
-                    },
-                    {
-                        "type": "integer",
-                        "description": "End timestamp",
-                        "name": "endsAt",
-                        "in": "query"
+                        "description": "State",
+                        "name": "state",
+                        "in": "query",
+                        "required": true
                     }
                 ],
                 "responses": {
-                    "200": {
-                        "description": "OK",
-                        "schema": {
-                            "$ref": "#/definitions/core.ListResult-core_EpgChannel"
-                        }
+                    "302": {
+                        "description": "Found"
                     },
                     "401": {
                         "description": "Unauthorized",
@@ -358,34 +489,39 @@ const docTemplate = `{
                 }
             }
         },
-        "/epg/content-types": {
+        "/auth/oidc/login": {
             "get": {
-                "security": [
-                    {
-                        "JWT": []
-                    }
+                "tags": [
+                    "auth"
                 ],
+                "summary": "Starts a login via OpenID Connect",
+                "responses": {
+                    "302": {
+                        "description": "Found"
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/response.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/auth/webauthn/login/begin": {
+            "post": {
                 "produces": [
                     "application/json"
                 ],
                 "tags": [
-                    "epg"
+                    "webauthn"
                 ],
-                "summary": "Get epg content types",
+                "summary": "Starts a login with a passkey",
                 "responses": {
                     "200": {
                         "description": "OK",
                         "schema": {
-                            "type": "array",
-                            "items": {
-                                "$ref": "#/definitions/core.EpgContentType"
-                            }
-                        }
-                    },
-                    "401": {
-                        "description": "Unauthorized",
-                        "schema": {
-                            "$ref": "#/definitions/response.ErrorResponse"
+                            "$ref": "#/definitions/core.WebAuthnCeremony"
                         }
                     },
                     "500": {
@@ -397,103 +533,91 @@ const docTemplate = `{
                 }
             }
         },
-        "/epg/events": {
-            "get": {
-                "security": [
-                    {
-                        "JWT": []
-                    }
-                ],
+        "/auth/webauthn/login/finish": {
+            "post": {
                 "produces": [
                     "application/json"
                 ],
                 "tags": [
-                    "epg"
+                    "webauthn"
                 ],
-                "summary": "Get epg events",
+                "summary": "Finishes a passwordless login with a passkey",
                 "parameters": [
                     {
-                        "type": "integer",
-                        "description": "Limit",
-                        "name": "limit",
-                        "in": "query"
-                    },
-                    {
-                        "type": "integer",
-                        "description": "Offset",
-                        "name": "offset",
-                        "in": "query"
-                    },
-                    {
-                        "type": "string",
-                        "description": "Sort key",
-                        "name": "sort_key",
-                        "in": "query"
-                    },
-                    {
-                        "type": "string",
-                        "description": "Sort direction",
-                        "name": "sort_dir",
-                        "in": "query"
-                    },
-                    {
-                        "type": "string",
-                        "description": "Title",
-                        "name": "title",
-                        "in": "query"
-                    },
-                    {
-                        "type": "boolean",
-                        "description": "Enable full test search",
-                        "name": "fullText",
-                        "in": "query"
-                    },
-                    {
-                        "type": "string",
-                        "description": "Language",
-                        "name": "lang",
-                        "in": "query"
+                        "description": "Body",
+                        "name": "body",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/api.webAuthnAssertion"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/api.loginResponse"
+                        }
                     },
-                    {
-                        "type": "boolean",
-                        "description": "Now playing",
-                        "name": "nowPlaying",
-                        "in": "query"
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/response.ErrorResponse"
+                        }
                     },
-                    {
-                        "type": "string",
-                        "description": "Channel name or channel id",
-                        "name": "channel",
-                        "in": "query"
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "$ref": "#/definitions/response.ErrorResponse"
+                        }
                     },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/response.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/channels": {
+            "get": {
+                "security": [
                     {
-                        "type": "string",
-                        "description": "Content type",
-                        "name": "contentType",
-                        "in": "query"
-                    },
+                        "JWT": []
+                    }
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "channels"
+                ],
+                "summary": "Get list of channels",
+                "parameters": [
                     {
                         "type": "integer",
-                        "description": "Minimum Duration",
-                        "name": "durationMin",
+                        "description": "Limit",
+                        "name": "limit",
                         "in": "query"
                     },
                     {
                         "type": "integer",
-                        "description": "Maximum Duration",
-                        "name": "durationMax",
+                        "description": "Offset",
+                        "name": "offset",
                         "in": "query"
                     },
                     {
-                        "type": "integer",
-                        "description": "Start timestamp",
-                        "name": "startsAt",
+                        "type": "string",
+                        "description": "Sort key",
+                        "name": "sort_key",
                         "in": "query"
                     },
                     {
-                        "type": "integer",
-                        "description": "End timestamp",
-                        "name": "endsAt",
+                        "type": "string",
+                        "description": "Sort direction",
+                        "name": "sort_dir",
                         "in": "query"
                     }
                 ],
@@ -503,10 +627,16 @@ const docTemplate = `{
                         "schema": {
                             "type": "array",
                             "items": {
-                                "$ref": "#/definitions/core.EpgEvent"
+                                "$ref": "#/definitions/core.Channel"
                             }
                         }
                     },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/response.ErrorResponse"
+                        }
+                    },
                     "401": {
                         "description": "Unauthorized",
                         "schema": {
@@ -522,7 +652,7 @@ const docTemplate = `{
                 }
             }
         },
-        "/epg/events/{id}": {
+        "/channels/{id}": {
             "get": {
                 "security": [
                     {
@@ -533,13 +663,13 @@ const docTemplate = `{
                     "application/json"
                 ],
                 "tags": [
-                    "epg"
+                    "channels"
                 ],
-                "summary": "Get a epg event by id",
+                "summary": "Get a channel by id",
                 "parameters": [
                     {
                         "type": "string",
-                        "description": "Event id",
+                        "description": "Channel id",
                         "name": "id",
                         "in": "path",
                         "required": true
@@ -549,7 +679,7 @@ const docTemplate = `{
                     "200": {
                         "description": "OK",
                         "schema": {
-                            "$ref": "#/definitions/core.EpgEvent"
+                            "$ref": "#/definitions/core.Channel"
                         }
                     },
                     "401": {
@@ -573,7 +703,7 @@ const docTemplate = `{
                 }
             }
         },
-        "/epg/events/{id}/related": {
+        "/channels/{number}/hls/index.m3u8": {
             "get": {
                 "security": [
                     {
@@ -581,54 +711,105 @@ const docTemplate = `{
                     }
                 ],
                 "produces": [
+                    "application/vnd.apple.mpegurl",
                     "application/json"
                 ],
                 "tags": [
-                    "epg"
+                    "channels"
                 ],
-                "summary": "Get related epg events",
+                "summary": "Get the HLS playlist of a channel stream",
                 "parameters": [
                     {
                         "type": "string",
-                        "description": "Event id",
-                        "name": "id",
+                        "description": "Channel number",
+                        "name": "number",
                         "in": "path",
                         "required": true
                     },
                     {
-                        "type": "integer",
-                        "description": "Limit",
-                        "name": "limit",
+                        "type": "string",
+                        "description": "Streaming profile",
+                        "name": "profile",
                         "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK"
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "$ref": "#/definitions/response.ErrorResponse"
+                        }
+                    },
+                    "403": {
+                        "description": "Forbidden",
+                        "schema": {
+                            "$ref": "#/definitions/response.ErrorResponse"
+                        }
+                    },
+                    "410": {
+                        "description": "Gone",
+                        "schema": {
+                            "$ref": "#/definitions/response.ErrorResponse"
+                        }
+                    },
+                    "429": {
+                        "description": "Too Many Requests",
+                        "schema": {
+                            "$ref": "#/definitions/response.ErrorResponse"
+                        }
                     },
+                    "503": {
+                        "description": "Service Unavailable",
+                        "schema": {
+                            "$ref": "#/definitions/response.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/channels/{number}/hls/{sequence}.ts": {
+            "get": {
+                "security": [
                     {
-                        "type": "integer",
-                        "description": "Offset",
-                        "name": "offset",
-                        "in": "query"
+                        "JWT": []
+                    }
+                ],
+                "produces": [
+                    "video/mp2t",
+                    "application/json"
+                ],
+                "tags": [
+                    "channels"
+                ],
+                "summary": "Get a HLS segment of a channel stream",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Channel number",
+                        "name": "number",
+                        "in": "path",
+                        "required": true
                     },
                     {
                         "type": "string",
-                        "description": "Sort key",
-                        "name": "sort_key",
-                        "in": "query"
+                        "description": "Segment sequence number",
+                        "name": "sequence",
+                        "in": "path",
+                        "required": true
                     },
                     {
                         "type": "string",
-                        "description": "Sort direction",
-                        "name": "sort_dir",
+                        "description": "Streaming profile",
+                        "name": "profile",
                         "in": "query"
                     }
                 ],
                 "responses": {
                     "200": {
-                        "description": "OK",
-                        "schema": {
-                            "type": "array",
-                            "items": {
-                                "$ref": "#/definitions/core.EpgEvent"
-                            }
-                        }
+                        "description": "OK"
                     },
                     "401": {
                         "description": "Unauthorized",
@@ -636,8 +817,14 @@ const docTemplate = `{
                             "$ref": "#/definitions/response.ErrorResponse"
                         }
                     },
-                    "500": {
-                        "description": "Internal Server Error",
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/response.ErrorResponse"
+                        }
+                    },
+                    "410": {
+                        "description": "Gone",
                         "schema": {
                             "$ref": "#/definitions/response.ErrorResponse"
                         }
@@ -645,7 +832,7 @@ const docTemplate = `{
                 }
             }
         },
-        "/picon/{id}": {
+        "/channels/{number}/stream": {
             "get": {
                 "security": [
                     {
@@ -653,20 +840,26 @@ const docTemplate = `{
                     }
                 ],
                 "produces": [
-                    "image/*",
+                    "video/*",
                     "application/json"
                 ],
                 "tags": [
-                    "picon"
+                    "channels"
                 ],
-                "summary": "Get channel picon",
+                "summary": "Stream a channel by channel number",
                 "parameters": [
                     {
                         "type": "string",
-                        "description": "Picon id",
-                        "name": "id",
+                        "description": "Channel number",
+                        "name": "number",
                         "in": "path",
                         "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "Streaming profile",
+                        "name": "profile",
+                        "in": "query"
                     }
                 ],
                 "responses": {
@@ -679,14 +872,14 @@ const docTemplate = `{
                             "$ref": "#/definitions/response.ErrorResponse"
                         }
                     },
-                    "404": {
-                        "description": "Not Found",
+                    "429": {
+                        "description": "Too Many Requests",
                         "schema": {
                             "$ref": "#/definitions/response.ErrorResponse"
                         }
                     },
-                    "500": {
-                        "description": "Internal Server Error",
+                    "503": {
+                        "description": "Service Unavailable",
                         "schema": {
                             "$ref": "#/definitions/response.ErrorResponse"
                         }
@@ -694,22 +887,22 @@ const docTemplate = `{
                 }
             }
         },
-        "/profiles/stream": {
+        "/dvr/config": {
             "get": {
                 "produces": [
                     "application/json"
                 ],
                 "tags": [
-                    "profiles"
+                    "dvr"
                 ],
-                "summary": "Get list of stream profiles",
+                "summary": "Get list of dvr configs",
                 "responses": {
                     "200": {
                         "description": "OK",
                         "schema": {
                             "type": "array",
                             "items": {
-                                "$ref": "#/definitions/core.StreamProfile"
+                                "$ref": "#/definitions/core.DVRConfig"
                             }
                         }
                     },
@@ -728,9 +921,2058 @@ const docTemplate = `{
                 }
             }
         },
-        "/recordings": {
+        "/dvr/config/{id}": {
             "get": {
-                "security": [
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "dvr"
+                ],
+                "summary": "Get a dvr configs by id",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "DVR Config ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/core.DVRConfig"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "$ref": "#/definitions/response.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/response.ErrorResponse"
+                        }
+                    }
+                }
+            },
+            "delete": {
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "dvr"
+                ],
+                "summary": "Deletes a dvr config by id",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "DVR config ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "204": {
+                        "description": "No Content"
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "$ref": "#/definitions/response.ErrorResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/response.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/response.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/epg": {
+            "get": {
+                "security": [
+                    {
+                        "JWT": []
+                    }
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "epg"
+                ],
+                "summary": "Get epg",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Sort key",
+                        "name": "sort_key",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Sort direction",
+                        "name": "sort_dir",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "format": "int64",
+                        "description": "Start timestamp",
+                        "name": "startsAt",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "format": "int64",
+                        "description": "End timestamp",
+                        "name": "endsAt",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/core.ListResult-core_EpgChannel"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "$ref": "#/definitions/response.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/response.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/epg/content-types": {
+            "get": {
+                "security": [
+                    {
+                        "JWT": []
+                    }
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "epg"
+                ],
+                "summary": "Get epg content types",
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "type": "array",
+                            "items": {
+                                "$ref": "#/definitions/core.EpgContentType"
+                            }
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "$ref": "#/definitions/response.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/response.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/epg/events": {
+            "get": {
+                "security": [
+                    {
+                        "JWT": []
+                    }
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "epg"
+                ],
+                "summary": "Get epg events",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "Limit",
+                        "name": "limit",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Offset",
+                        "name": "offset",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Sort key",
+                        "name": "sort_key",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Sort direction",
+                        "name": "sort_dir",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Title",
+                        "name": "title",
+                        "in": "query"
+                    },
+                    {
+                        "type": "boolean",
+                        "description": "Enable full test search",
+                        "name": "fullText",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Language",
+                        "name": "lang",
+                        "in": "query"
+                    },
+                    {
+                        "type": "boolean",
+                        "description": "Now playing",
+                        "name": "nowPlaying",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Channel name or channel id",
+                        "name": "channel",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Content type",
+                        "name": "contentType",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "format": "int64",
+                        "description": "Minimum Duration",
+                        "name": "durationMin",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "format": "int64",
+                        "description": "Maximum Duration",
+                        "name": "durationMax",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "format": "int64",
+                        "description": "Start timestamp",
+                        "name": "startsAt",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "format": "int64",
+                        "description": "End timestamp",
+                        "name": "endsAt",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "type": "array",
+                            "items": {
+                                "$ref": "#/definitions/core.EpgEvent"
+                            }
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "$ref": "#/definitions/response.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/response.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/epg/events/{id}": {
+            "get": {
+                "security": [
+                    {
+                        "JWT": []
+                    }
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "epg"
+                ],
+                "summary": "Get a epg event by id",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Event id",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/core.EpgEvent"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "$ref": "#/definitions/response.ErrorResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/response.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/response.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/epg/events/{id}/related": {
+            "get": {
+                "security": [
+                    {
+                        "JWT": []
+                    }
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "epg"
+                ],
+                "summary": "Get related epg events",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Event id",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Limit",
+                        "name": "limit",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Offset",
+                        "name": "offset",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Sort key",
+                        "name": "sort_key",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Sort direction",
+                        "name": "sort_dir",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "type": "array",
+                            "items": {
+                                "$ref": "#/definitions/core.EpgEvent"
+                            }
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "$ref": "#/definitions/response.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/response.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/lockouts": {
+            "get": {
+                "security": [
+                    {
+                        "JWT": []
+                    }
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "lockouts"
+                ],
+                "summary": "Get list of client ips and usernames blocked after failed logins",
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "type": "array",
+                            "items": {
+                                "$ref": "#/definitions/core.LoginThrottle"
+                            }
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "$ref": "#/definitions/response.ErrorResponse"
+                        }
+                    },
+                    "403": {
+                        "description": "Forbidden",
+                        "schema": {
+                            "$ref": "#/definitions/response.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/response.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/lockouts/{id}": {
+            "delete": {
+                "security": [
+                    {
+                        "JWT": []
+                    }
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "lockouts"
+                ],
+                "summary": "Clears the lockout of a client ip or username",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "Lockout id",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "204": {
+                        "description": "No Content"
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/response.ErrorResponse"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "$ref": "#/definitions/response.ErrorResponse"
+                        }
+                    },
+                    "403": {
+                        "description": "Forbidden",
+                        "schema": {
+                            "$ref": "#/definitions/response.ErrorResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/response.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/response.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/picon/{id}": {
+            "get": {
+                "security": [
+                    {
+                        "JWT": []
+                    }
+                ],
+                "produces": [
+                    "image/*",
+                    "application/json"
+                ],
+                "tags": [
+                    "picon"
+                ],
+                "summary": "Get channel picon",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Picon id",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK"
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "$ref": "#/definitions/response.ErrorResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/response.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/response.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/profiles/stream": {
+            "get": {
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "profiles"
+                ],
+                "summary": "Get list of stream profiles",
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "type": "array",
+                            "items": {
+                                "$ref": "#/definitions/core.StreamProfile"
+                            }
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "$ref": "#/definitions/response.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/response.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/recordings": {
+            "get": {
+                "security": [
+                    {
+                        "JWT": []
+                    }
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "recordings"
+                ],
+                "summary": "Get list of recordings",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "Limit",
+                        "name": "limit",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Offset",
+                        "name": "offset",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Sort key",
+                        "name": "sort_key",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Sort direction",
+                        "name": "sort_dir",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Recording status",
+                        "name": "status",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "type": "array",
+                            "items": {
+                                "$ref": "#/definitions/core.RecordingListResult"
+                            }
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/response.ErrorResponse"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "$ref": "#/definitions/response.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/response.ErrorResponse"
+                        }
+                    }
+                }
+            },
+            "post": {
+                "security": [
+                    {
+                        "JWT": []
+                    }
+                ],
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "recordings"
+                ],
+                "summary": "Create a recording",
+                "parameters": [
+                    {
+                        "description": "Body",
+                        "name": "body",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/core.CreateRecording"
+                        }
+                    }
+                ],
+                "responses": {
+                    "201": {
+                        "description": "Created"
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/response.ErrorResponse"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "$ref": "#/definitions/response.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/response.ErrorResponse"
+                        }
+                    }
+                }
+            },
+            "delete": {
+                "security": [
+                    {
+                        "JWT": []
+                    }
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "recordings"
+                ],
+                "summary": "Remove multiple recordings",
+                "parameters": [
+                    {
+                        "type": "array",
+                        "items": {
+                            "type": "string"
+                        },
+                        "collectionFormat": "multi",
+                        "description": "recording ids",
+                        "name": "ids",
+                        "in": "query",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "204": {
+                        "description": "No Content"
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/response.ErrorResponse"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "$ref": "#/definitions/response.ErrorResponse"
+                        }
+                    },
+                    "403": {
+                        "description": "Forbidden",
+                        "schema": {
+                            "$ref": "#/definitions/response.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/response.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/recordings/cancel": {
+            "put": {
+                "security": [
+                    {
+                        "JWT": []
+                    }
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "recordings"
+                ],
+                "summary": "Cancel multiple recordings",
+                "parameters": [
+                    {
+                        "type": "array",
+                        "items": {
+                            "type": "string"
+                        },
+                        "collectionFormat": "multi",
+                        "description": "recording ids",
+                        "name": "ids",
+                        "in": "query",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "204": {
+                        "description": "No Content"
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/response.ErrorResponse"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "$ref": "#/definitions/response.ErrorResponse"
+                        }
+                    },
+                    "403": {
+                        "description": "Forbidden",
+                        "schema": {
+                            "$ref": "#/definitions/response.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/response.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/recordings/event": {
+            "post": {
+                "security": [
+                    {
+                        "JWT": []
+                    }
+                ],
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "recordings"
+                ],
+                "summary": "Create a recording by a event",
+                "parameters": [
+                    {
+                        "description": "Body",
+                        "name": "body",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/core.CreateRecordingByEvent"
+                        }
+                    }
+                ],
+                "responses": {
+                    "201": {
+                        "description": "Created"
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/response.ErrorResponse"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "$ref": "#/definitions/response.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/response.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/recordings/stop": {
+            "put": {
+                "security": [
+                    {
+                        "JWT": []
+                    }
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "recordings"
+                ],
+                "summary": "Stop multiple recordings",
+                "parameters": [
+                    {
+                        "type": "array",
+                        "items": {
+                            "type": "string"
+                        },
+                        "collectionFormat": "multi",
+                        "description": "recording ids",
+                        "name": "ids",
+                        "in": "query",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "204": {
+                        "description": "No Content"
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/response.ErrorResponse"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "$ref": "#/definitions/response.ErrorResponse"
+                        }
+                    },
+                    "403": {
+                        "description": "Forbidden",
+                        "schema": {
+                            "$ref": "#/definitions/response.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/response.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/recordings/{id}": {
+            "get": {
+                "security": [
+                    {
+                        "JWT": []
+                    }
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "recordings"
+                ],
+                "summary": "Get a recording by id",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Recording id",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/core.Recording"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/response.ErrorResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/response.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/response.ErrorResponse"
+                        }
+                    }
+                }
+            },
+            "delete": {
+                "security": [
+                    {
+                        "JWT": []
+                    }
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "recordings"
+                ],
+                "summary": "Removes a recording",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Recording id",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "204": {
+                        "description": "No Content"
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "$ref": "#/definitions/response.ErrorResponse"
+                        }
+                    },
+                    "403": {
+                        "description": "Forbidden",
+                        "schema": {
+                            "$ref": "#/definitions/response.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/response.ErrorResponse"
+                        }
+                    }
+                }
+            },
+            "patch": {
+                "security": [
+                    {
+                        "JWT": []
+                    }
+                ],
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "recordings"
+                ],
+                "summary": "Updates a recording",
+                "parameters": [
+                    {
+                        "description": "Body",
+                        "name": "body",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/core.UpdateRecording"
+                        }
+                    }
+                ],
+                "responses": {
+                    "201": {
+                        "description": "Created"
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/response.ErrorResponse"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "$ref": "#/definitions/response.ErrorResponse"
+                        }
+                    },
+                    "403": {
+                        "description": "Forbidden",
+                        "schema": {
+                            "$ref": "#/definitions/response.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/response.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/recordings/{id}/cancel": {
+            "put": {
+                "security": [
+                    {
+                        "JWT": []
+                    }
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "recordings"
+                ],
+                "summary": "Cancels a recording",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Recording id",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "204": {
+                        "description": "No Content"
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "$ref": "#/definitions/response.ErrorResponse"
+                        }
+                    },
+                    "403": {
+                        "description": "Forbidden",
+                        "schema": {
+                            "$ref": "#/definitions/response.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/response.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/recordings/{id}/move/{dest}": {
+            "put": {
+                "security": [
+                    {
+                        "JWT": []
+                    }
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "recordings"
+                ],
+                "summary": "Moves a recording",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Recording id",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "Recording id",
+                        "name": "dest",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "204": {
+                        "description": "No Content"
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/response.ErrorResponse"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "$ref": "#/definitions/response.ErrorResponse"
+                        }
+                    },
+                    "403": {
+                        "description": "Forbidden",
+                        "schema": {
+                            "$ref": "#/definitions/response.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/response.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/recordings/{id}/stop": {
+            "put": {
+                "security": [
+                    {
+                        "JWT": []
+                    }
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "recordings"
+                ],
+                "summary": "Stops a recording",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Recording id",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "204": {
+                        "description": "No Content"
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "$ref": "#/definitions/response.ErrorResponse"
+                        }
+                    },
+                    "403": {
+                        "description": "Forbidden",
+                        "schema": {
+                            "$ref": "#/definitions/response.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/response.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/recordings/{id}/stream": {
+            "get": {
+                "security": [
+                    {
+                        "JWT": []
+                    }
+                ],
+                "produces": [
+                    "video/*",
+                    "application/json"
+                ],
+                "tags": [
+                    "recordings"
+                ],
+                "summary": "Stream a recording",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Recording id",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK"
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "$ref": "#/definitions/response.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/sessions": {
+            "get": {
+                "security": [
+                    {
+                        "JWT": []
+                    }
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "sessions"
+                ],
+                "summary": "Get list of sessions for the current user",
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "type": "array",
+                            "items": {
+                                "$ref": "#/definitions/core.Session"
+                            }
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "$ref": "#/definitions/response.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/response.ErrorResponse"
+                        }
+                    }
+                }
+            },
+            "delete": {
+                "security": [
+                    {
+                        "JWT": []
+                    }
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "sessions"
+                ],
+                "summary": "Revokes all other sessions of the current user",
+                "responses": {
+                    "204": {
+                        "description": "No Content"
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "$ref": "#/definitions/response.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/response.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/sessions/{id}": {
+            "delete": {
+                "security": [
+                    {
+                        "JWT": []
+                    }
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "sessions"
+                ],
+                "summary": "Revokes a session",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "Session id",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "204": {
+                        "description": "No Content"
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "$ref": "#/definitions/response.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/response.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/streams": {
+            "get": {
+                "security": [
+                    {
+                        "JWT": []
+                    }
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "streams"
+                ],
+                "summary": "Get list of active stream sessions",
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "type": "array",
+                            "items": {
+                                "$ref": "#/definitions/core.StreamSession"
+                            }
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "$ref": "#/definitions/response.ErrorResponse"
+                        }
+                    },
+                    "403": {
+                        "description": "Forbidden",
+                        "schema": {
+                            "$ref": "#/definitions/response.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/streams/sign": {
+            "post": {
+                "security": [
+                    {
+                        "JWT": []
+                    }
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "streams"
+                ],
+                "summary": "Creates a signed and short-lived stream url",
+                "parameters": [
+                    {
+                        "description": "Body",
+                        "name": "body",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/api.signStreamRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/api.signedStreamResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/response.ErrorResponse"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "$ref": "#/definitions/response.ErrorResponse"
+                        }
+                    },
+                    "403": {
+                        "description": "Forbidden",
+                        "schema": {
+                            "$ref": "#/definitions/response.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/response.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/streams/{id}": {
+            "delete": {
+                "security": [
+                    {
+                        "JWT": []
+                    }
+                ],
+                "tags": [
+                    "streams"
+                ],
+                "summary": "Terminates an active stream session",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Stream session id",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "204": {
+                        "description": "No Content"
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "$ref": "#/definitions/response.ErrorResponse"
+                        }
+                    },
+                    "403": {
+                        "description": "Forbidden",
+                        "schema": {
+                            "$ref": "#/definitions/response.ErrorResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/response.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/tokens": {
+            "get": {
+                "security": [
+                    {
+                        "JWT": []
+                    }
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "tokens"
+                ],
+                "summary": "Get list of tokens for the current user",
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "type": "array",
+                            "items": {
+                                "$ref": "#/definitions/core.Token"
+                            }
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "$ref": "#/definitions/response.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/response.ErrorResponse"
+                        }
+                    }
+                }
+            },
+            "post": {
+                "security": [
+                    {
+                        "JWT": []
+                    }
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "tokens"
+                ],
+                "summary": "Creates an api token",
+                "parameters": [
+                    {
+                        "description": "Body",
+                        "name": "body",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/api.createTokenRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/api.tokenResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/response.ErrorResponse"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "$ref": "#/definitions/response.ErrorResponse"
+                        }
+                    },
+                    "403": {
+                        "description": "Forbidden",
+                        "schema": {
+                            "$ref": "#/definitions/response.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/response.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/tokens/{id}": {
+            "delete": {
+                "security": [
+                    {
+                        "JWT": []
+                    }
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "tokens"
+                ],
+                "summary": "Revokes a token",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "Token ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "204": {
+                        "description": "No Content"
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "$ref": "#/definitions/response.ErrorResponse"
+                        }
+                    },
+                    "403": {
+                        "description": "Forbidden",
+                        "schema": {
+                            "$ref": "#/definitions/response.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/response.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/trusted-devices": {
+            "get": {
+                "security": [
+                    {
+                        "JWT": []
+                    }
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "sessions"
+                ],
+                "summary": "Get list of trusted devices for the current user",
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "type": "array",
+                            "items": {
+                                "$ref": "#/definitions/core.TrustedDevice"
+                            }
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "$ref": "#/definitions/response.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/response.ErrorResponse"
+                        }
+                    }
+                }
+            },
+            "delete": {
+                "security": [
+                    {
+                        "JWT": []
+                    }
+                ],
+                "tags": [
+                    "sessions"
+                ],
+                "summary": "Revokes all trusted devices of the current user",
+                "responses": {
+                    "204": {
+                        "description": "No Content"
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "$ref": "#/definitions/response.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/response.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/trusted-devices/{id}": {
+            "delete": {
+                "security": [
+                    {
+                        "JWT": []
+                    }
+                ],
+                "tags": [
+                    "sessions"
+                ],
+                "summary": "Revokes a trusted device of the current user",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "Trusted device id",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "204": {
+                        "description": "No Content"
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/response.ErrorResponse"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "$ref": "#/definitions/response.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/response.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/two-factor-auth": {
+            "get": {
+                "security": [
+                    {
+                        "JWT": []
+                    }
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "two-factor-auth"
+                ],
+                "summary": "Get the two factor auth settings for the current user",
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "type": "array",
+                            "items": {
+                                "$ref": "#/definitions/core.TwoFactorSettings"
+                            }
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "$ref": "#/definitions/response.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/response.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/two-factor-auth/activate": {
+            "put": {
+                "security": [
+                    {
+                        "JWT": []
+                    }
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "two-factor-auth"
+                ],
+                "summary": "Activates two factor auth for the current user",
+                "parameters": [
+                    {
+                        "description": "Body",
+                        "name": "body",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/api.twoFactorAuthActivateRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/api.twoFactorAuthRecoveryCodesResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/response.ErrorResponse"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "$ref": "#/definitions/response.ErrorResponse"
+                        }
+                    },
+                    "409": {
+                        "description": "Conflict",
+                        "schema": {
+                            "$ref": "#/definitions/response.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/response.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/two-factor-auth/deactivate": {
+            "put": {
+                "security": [
+                    {
+                        "JWT": []
+                    }
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "two-factor-auth"
+                ],
+                "summary": "Deactivates two factor auth for the current user",
+                "parameters": [
+                    {
+                        "description": "Body",
+                        "name": "body",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/api.twoFactorAuthDeactivateRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "204": {
+                        "description": "No Content"
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/response.ErrorResponse"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "$ref": "#/definitions/response.ErrorResponse"
+                        }
+                    },
+                    "409": {
+                        "description": "Conflict",
+                        "schema": {
+                            "$ref": "#/definitions/response.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/response.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/two-factor-auth/recovery-codes": {
+            "put": {
+                "security": [
+                    {
+                        "JWT": []
+                    }
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "two-factor-auth"
+                ],
+                "summary": "Replaces the two factor auth recovery codes for the current user",
+                "parameters": [
+                    {
+                        "description": "Body",
+                        "name": "body",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/api.twoFactorAuthRecoveryCodesRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/api.twoFactorAuthRecoveryCodesResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/response.ErrorResponse"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "$ref": "#/definitions/response.ErrorResponse"
+                        }
+                    },
+                    "409": {
+                        "description": "Conflict",
+                        "schema": {
+                            "$ref": "#/definitions/response.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/response.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/two-factor-auth/setup": {
+            "put": {
+                "security": [
                     {
                         "JWT": []
                     }
@@ -739,49 +2981,25 @@ const docTemplate = `{
                     "application/json"
                 ],
                 "tags": [
-                    "recordings"
+                    "two-factor-auth"
                 ],
-                "summary": "Get list of recordings",
+                "summary": "Starts the two factor auth setup for the current user",
                 "parameters": [
                     {
-                        "type": "integer",
-                        "description": "Limit",
-                        "name": "limit",
-                        "in": "query"
-                    },
-                    {
-                        "type": "integer",
-                        "description": "Offset",
-                        "name": "offset",
-                        "in": "query"
-                    },
-                    {
-                        "type": "string",
-                        "description": "Sort key",
-                        "name": "sort_key",
-                        "in": "query"
-                    },
-                    {
-                        "type": "string",
-                        "description": "Sort direction",
-                        "name": "sort_dir",
-                        "in": "query"
-                    },
-                    {
-                        "type": "string",
-                        "description": "Recording status",
-                        "name": "status",
-                        "in": "query"
+                        "description": "Body",
+                        "name": "body",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/api.twoFactorAuthSetupRequest"
+                        }
                     }
                 ],
                 "responses": {
                     "200": {
                         "description": "OK",
                         "schema": {
-                            "type": "array",
-                            "items": {
-                                "$ref": "#/definitions/core.RecordingListResult"
-                            }
+                            "$ref": "#/definitions/api.twoFactorAuthSetupResponse"
                         }
                     },
                     "400": {
@@ -796,6 +3014,48 @@ const docTemplate = `{
                             "$ref": "#/definitions/response.ErrorResponse"
                         }
                     },
+                    "409": {
+                        "description": "Conflict",
+                        "schema": {
+                            "$ref": "#/definitions/response.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/response.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/user": {
+            "get": {
+                "security": [
+                    {
+                        "JWT": []
+                    }
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "user"
+                ],
+                "summary": "Get the current user",
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/core.User"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "$ref": "#/definitions/response.ErrorResponse"
+                        }
+                    },
                     "500": {
                         "description": "Internal Server Error",
                         "schema": {
@@ -804,7 +3064,7 @@ const docTemplate = `{
                     }
                 }
             },
-            "post": {
+            "patch": {
                 "security": [
                     {
                         "JWT": []
@@ -817,9 +3077,9 @@ const docTemplate = `{
                     "application/json"
                 ],
                 "tags": [
-                    "recordings"
+                    "user"
                 ],
-                "summary": "Create a recording",
+                "summary": "Updates the current user",
                 "parameters": [
                     {
                         "description": "Body",
@@ -827,13 +3087,16 @@ const docTemplate = `{
                         "in": "body",
                         "required": true,
                         "schema": {
-                            "$ref": "#/definitions/core.CreateRecording"
+                            "$ref": "#/definitions/api.userUpdate"
                         }
                     }
                 ],
                 "responses": {
-                    "201": {
-                        "description": "Created"
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/core.User"
+                        }
                     },
                     "400": {
                         "description": "Bad Request",
@@ -854,8 +3117,10 @@ const docTemplate = `{
                         }
                     }
                 }
-            },
-            "delete": {
+            }
+        },
+        "/user/parental-controls": {
+            "get": {
                 "security": [
                     {
                         "JWT": []
@@ -865,20 +3130,63 @@ const docTemplate = `{
                     "application/json"
                 ],
                 "tags": [
-                    "recordings"
+                    "parental-controls"
                 ],
-                "summary": "Remove multiple recordings",
+                "summary": "Get the parental controls of the current user",
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/core.ParentalControls"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "$ref": "#/definitions/response.ErrorResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/response.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/response.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/user/password": {
+            "patch": {
+                "security": [
+                    {
+                        "JWT": []
+                    }
+                ],
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "user"
+                ],
+                "summary": "Updates the password of the current user",
                 "parameters": [
                     {
-                        "type": "array",
-                        "items": {
-                            "type": "string"
-                        },
-                        "collectionFormat": "multi",
-                        "description": "recording ids",
-                        "name": "ids",
-                        "in": "query",
-                        "required": true
+                        "description": "Body",
+                        "name": "body",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/api.userUpdatePassword"
+                        }
                     }
                 ],
                 "responses": {
@@ -906,36 +3214,67 @@ const docTemplate = `{
                 }
             }
         },
-        "/recordings/cancel": {
-            "put": {
-                "security": [
-                    {
-                        "JWT": []
+        "/users": {
+            "get": {
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "user"
+                ],
+                "summary": "Get a list of users",
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "type": "array",
+                            "items": {
+                                "$ref": "#/definitions/core.UserListResult"
+                            }
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "$ref": "#/definitions/response.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/response.ErrorResponse"
+                        }
                     }
+                }
+            },
+            "post": {
+                "consumes": [
+                    "application/json"
                 ],
                 "produces": [
                     "application/json"
                 ],
                 "tags": [
-                    "recordings"
+                    "user"
                 ],
-                "summary": "Cancel multiple recordings",
-                "parameters": [
-                    {
-                        "type": "array",
-                        "items": {
-                            "type": "string"
-                        },
-                        "collectionFormat": "multi",
-                        "description": "recording ids",
-                        "name": "ids",
-                        "in": "query",
-                        "required": true
+                "summary": "Creates a new user",
+                "parameters": [
+                    {
+                        "description": "Body",
+                        "name": "body",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/api.createUser"
+                        }
                     }
                 ],
                 "responses": {
-                    "204": {
-                        "description": "No Content"
+                    "201": {
+                        "description": "Created",
+                        "schema": {
+                            "$ref": "#/definitions/core.User"
+                        }
                     },
                     "400": {
                         "description": "Bad Request",
@@ -958,37 +3297,30 @@ const docTemplate = `{
                 }
             }
         },
-        "/recordings/event": {
-            "post": {
-                "security": [
-                    {
-                        "JWT": []
-                    }
-                ],
-                "consumes": [
-                    "application/json"
-                ],
+        "/users/{id}": {
+            "get": {
                 "produces": [
                     "application/json"
                 ],
                 "tags": [
-                    "recordings"
+                    "user"
                 ],
-                "summary": "Create a recording by a event",
+                "summary": "Get a user by ID",
                 "parameters": [
                     {
-                        "description": "Body",
-                        "name": "body",
-                        "in": "body",
-                        "required": true,
-                        "schema": {
-                            "$ref": "#/definitions/core.CreateRecordingByEvent"
-                        }
+                        "type": "string",
+                        "description": "User ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
                     }
                 ],
                 "responses": {
-                    "201": {
-                        "description": "Created"
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/core.User"
+                        }
                     },
                     "400": {
                         "description": "Bad Request",
@@ -1002,6 +3334,12 @@ const docTemplate = `{
                             "$ref": "#/definitions/response.ErrorResponse"
                         }
                     },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/response.ErrorResponse"
+                        }
+                    },
                     "500": {
                         "description": "Internal Server Error",
                         "schema": {
@@ -1009,32 +3347,21 @@ const docTemplate = `{
                         }
                     }
                 }
-            }
-        },
-        "/recordings/stop": {
-            "put": {
-                "security": [
-                    {
-                        "JWT": []
-                    }
-                ],
+            },
+            "delete": {
                 "produces": [
                     "application/json"
                 ],
                 "tags": [
-                    "recordings"
+                    "user"
                 ],
-                "summary": "Stop multiple recordings",
+                "summary": "Deletes a user",
                 "parameters": [
                     {
-                        "type": "array",
-                        "items": {
-                            "type": "string"
-                        },
-                        "collectionFormat": "multi",
-                        "description": "recording ids",
-                        "name": "ids",
-                        "in": "query",
+                        "type": "string",
+                        "description": "User ID",
+                        "name": "id",
+                        "in": "path",
                         "required": true
                     }
                 ],
@@ -1054,6 +3381,12 @@ const docTemplate = `{
                             "$ref": "#/definitions/response.ErrorResponse"
                         }
                     },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/response.ErrorResponse"
+                        }
+                    },
                     "500": {
                         "description": "Internal Server Error",
                         "schema": {
@@ -1061,36 +3394,41 @@ const docTemplate = `{
                         }
                     }
                 }
-            }
-        },
-        "/recordings/{id}": {
-            "get": {
-                "security": [
-                    {
-                        "JWT": []
-                    }
+            },
+            "patch": {
+                "consumes": [
+                    "application/json"
                 ],
                 "produces": [
                     "application/json"
                 ],
                 "tags": [
-                    "recordings"
+                    "user"
                 ],
-                "summary": "Get a recording by id",
+                "summary": "Updates a user",
                 "parameters": [
                     {
                         "type": "string",
-                        "description": "Recording id",
+                        "description": "User ID",
                         "name": "id",
                         "in": "path",
                         "required": true
+                    },
+                    {
+                        "description": "Body",
+                        "name": "body",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/api.adminUserUpdate"
+                        }
                     }
                 ],
                 "responses": {
                     "200": {
                         "description": "OK",
                         "schema": {
-                            "$ref": "#/definitions/core.Recording"
+                            "$ref": "#/definitions/core.User"
                         }
                     },
                     "400": {
@@ -1099,6 +3437,18 @@ const docTemplate = `{
                             "$ref": "#/definitions/response.ErrorResponse"
                         }
                     },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "$ref": "#/definitions/response.ErrorResponse"
+                        }
+                    },
+                    "403": {
+                        "description": "Forbidden",
+                        "schema": {
+                            "$ref": "#/definitions/response.ErrorResponse"
+                        }
+                    },
                     "404": {
                         "description": "Not Found",
                         "schema": {
@@ -1112,8 +3462,10 @@ const docTemplate = `{
                         }
                     }
                 }
-            },
-            "delete": {
+            }
+        },
+        "/users/{id}/parental-controls": {
+            "get": {
                 "security": [
                     {
                         "JWT": []
@@ -1123,21 +3475,30 @@ const docTemplate = `{
                     "application/json"
                 ],
                 "tags": [
-                    "recordings"
+                    "parental-controls"
                 ],
-                "summary": "Removes a recording",
+                "summary": "Get the parental controls of a user",
                 "parameters": [
                     {
-                        "type": "string",
-                        "description": "Recording id",
+                        "type": "integer",
+                        "description": "User id",
                         "name": "id",
                         "in": "path",
                         "required": true
                     }
                 ],
                 "responses": {
-                    "204": {
-                        "description": "No Content"
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/core.ParentalControls"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/response.ErrorResponse"
+                        }
                     },
                     "401": {
                         "description": "Unauthorized",
@@ -1145,6 +3506,18 @@ const docTemplate = `{
                             "$ref": "#/definitions/response.ErrorResponse"
                         }
                     },
+                    "403": {
+                        "description": "Forbidden",
+                        "schema": {
+                            "$ref": "#/definitions/response.ErrorResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/response.ErrorResponse"
+                        }
+                    },
                     "500": {
                         "description": "Internal Server Error",
                         "schema": {
@@ -1153,7 +3526,7 @@ const docTemplate = `{
                     }
                 }
             },
-            "patch": {
+            "put": {
                 "security": [
                     {
                         "JWT": []
@@ -1166,23 +3539,33 @@ const docTemplate = `{
                     "application/json"
                 ],
                 "tags": [
-                    "recordings"
+                    "parental-controls"
                 ],
-                "summary": "Updates a recording",
+                "summary": "Sets the parental controls of a user",
                 "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "User id",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
                     {
                         "description": "Body",
                         "name": "body",
                         "in": "body",
                         "required": true,
                         "schema": {
-                            "$ref": "#/definitions/core.UpdateRecording"
+                            "$ref": "#/definitions/core.ParentalControls"
                         }
                     }
                 ],
                 "responses": {
-                    "201": {
-                        "description": "Created"
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/core.ParentalControls"
+                        }
                     },
                     "400": {
                         "description": "Bad Request",
@@ -1196,6 +3579,18 @@ const docTemplate = `{
                             "$ref": "#/definitions/response.ErrorResponse"
                         }
                     },
+                    "403": {
+                        "description": "Forbidden",
+                        "schema": {
+                            "$ref": "#/definitions/response.ErrorResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/response.ErrorResponse"
+                        }
+                    },
                     "500": {
                         "description": "Internal Server Error",
                         "schema": {
@@ -1203,26 +3598,21 @@ const docTemplate = `{
                         }
                     }
                 }
-            }
-        },
-        "/recordings/{id}/cancel": {
-            "put": {
+            },
+            "delete": {
                 "security": [
                     {
                         "JWT": []
                     }
                 ],
-                "produces": [
-                    "application/json"
-                ],
                 "tags": [
-                    "recordings"
+                    "parental-controls"
                 ],
-                "summary": "Cancels a recording",
+                "summary": "Removes the parental controls of a user",
                 "parameters": [
                     {
-                        "type": "string",
-                        "description": "Recording id",
+                        "type": "integer",
+                        "description": "User id",
                         "name": "id",
                         "in": "path",
                         "required": true
@@ -1232,12 +3622,24 @@ const docTemplate = `{
                     "204": {
                         "description": "No Content"
                     },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/response.ErrorResponse"
+                        }
+                    },
                     "401": {
                         "description": "Unauthorized",
                         "schema": {
                             "$ref": "#/definitions/response.ErrorResponse"
                         }
                     },
+                    "403": {
+                        "description": "Forbidden",
+                        "schema": {
+                            "$ref": "#/definitions/response.ErrorResponse"
+                        }
+                    },
                     "500": {
                         "description": "Internal Server Error",
                         "schema": {
@@ -1247,34 +3649,34 @@ const docTemplate = `{
                 }
             }
         },
-        "/recordings/{id}/move/{dest}": {
+        "/users/{id}/password": {
             "put": {
-                "security": [
-                    {
-                        "JWT": []
-                    }
+                "consumes": [
+                    "application/json"
                 ],
                 "produces": [
                     "application/json"
                 ],
                 "tags": [
-                    "recordings"
+                    "user"
                 ],
-                "summary": "Moves a recording",
+                "summary": "Resets the password of a user",
                 "parameters": [
                     {
                         "type": "string",
-                        "description": "Recording id",
+                        "description": "User ID",
                         "name": "id",
                         "in": "path",
                         "required": true
                     },
                     {
-                        "type": "string",
-                        "description": "Recording id",
-                        "name": "dest",
-                        "in": "path",
-                        "required": true
+                        "description": "Body",
+                        "name": "body",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/api.adminUserResetPassword"
+                        }
                     }
                 ],
                 "responses": {
@@ -1287,8 +3689,20 @@ const docTemplate = `{
                             "$ref": "#/definitions/response.ErrorResponse"
                         }
                     },
-                    "401": {
-                        "description": "Unauthorized",
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "$ref": "#/definitions/response.ErrorResponse"
+                        }
+                    },
+                    "403": {
+                        "description": "Forbidden",
+                        "schema": {
+                            "$ref": "#/definitions/response.ErrorResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
                         "schema": {
                             "$ref": "#/definitions/response.ErrorResponse"
                         }
@@ -1302,32 +3716,48 @@ const docTemplate = `{
                 }
             }
         },
-        "/recordings/{id}/stop": {
+        "/users/{id}/role": {
             "put": {
-                "security": [
-                    {
-                        "JWT": []
-                    }
+                "consumes": [
+                    "application/json"
                 ],
                 "produces": [
                     "application/json"
                 ],
                 "tags": [
-                    "recordings"
+                    "user"
                 ],
-                "summary": "Stops a recording",
+                "summary": "Assigns a role to a user",
                 "parameters": [
                     {
                         "type": "string",
-                        "description": "Recording id",
+                        "description": "User ID",
                         "name": "id",
                         "in": "path",
                         "required": true
+                    },
+                    {
+                        "description": "Body",
+                        "name": "body",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/api.userUpdateRole"
+                        }
                     }
                 ],
                 "responses": {
-                    "204": {
-                        "description": "No Content"
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/core.User"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/response.ErrorResponse"
+                        }
                     },
                     "401": {
                         "description": "Unauthorized",
@@ -1335,6 +3765,18 @@ const docTemplate = `{
                             "$ref": "#/definitions/response.ErrorResponse"
                         }
                     },
+                    "403": {
+                        "description": "Forbidden",
+                        "schema": {
+                            "$ref": "#/definitions/response.ErrorResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/response.ErrorResponse"
+                        }
+                    },
                     "500": {
                         "description": "Internal Server Error",
                         "schema": {
@@ -1344,7 +3786,7 @@ const docTemplate = `{
                 }
             }
         },
-        "/recordings/{id}/stream": {
+        "/users/{id}/sessions": {
             "get": {
                 "security": [
                     {
@@ -1352,49 +3794,21 @@ const docTemplate = `{
                     }
                 ],
                 "produces": [
-                    "video/*",
                     "application/json"
                 ],
                 "tags": [
-                    "recordings"
+                    "sessions"
                 ],
-                "summary": "Stream a recording",
+                "summary": "Get list of session for a user",
                 "parameters": [
                     {
-                        "type": "string",
-                        "description": "Recording id",
+                        "type": "integer",
+                        "description": "User id",
                         "name": "id",
                         "in": "path",
                         "required": true
                     }
                 ],
-                "responses": {
-                    "200": {
-                        "description": "OK"
-                    },
-                    "401": {
-                        "description": "Unauthorized",
-                        "schema": {
-                            "$ref": "#/definitions/response.ErrorResponse"
-                        }
-                    }
-                }
-            }
-        },
-        "/sessions": {
-            "get": {
-                "security": [
-                    {
-                        "JWT": []
-                    }
-                ],
-                "produces": [
-                    "application/json"
-                ],
-                "tags": [
-                    "sessions"
-                ],
-                "summary": "Get list of sessions for the current user",
                 "responses": {
                     "200": {
                         "description": "OK",
@@ -1418,9 +3832,7 @@ const docTemplate = `{
                         }
                     }
                 }
-            }
-        },
-        "/sessions/{id}": {
+            },
             "delete": {
                 "security": [
                     {
@@ -1433,11 +3845,11 @@ const docTemplate = `{
                 "tags": [
                     "sessions"
                 ],
-                "summary": "Revokes a session",
+                "summary": "Revokes all sessions of a user",
                 "parameters": [
                     {
                         "type": "integer",
-                        "description": "Session id",
+                        "description": "User id",
                         "name": "id",
                         "in": "path",
                         "required": true
@@ -1447,12 +3859,24 @@ const docTemplate = `{
                     "204": {
                         "description": "No Content"
                     },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/response.ErrorResponse"
+                        }
+                    },
                     "401": {
                         "description": "Unauthorized",
                         "schema": {
                             "$ref": "#/definitions/response.ErrorResponse"
                         }
                     },
+                    "403": {
+                        "description": "Forbidden",
+                        "schema": {
+                            "$ref": "#/definitions/response.ErrorResponse"
+                        }
+                    },
                     "500": {
                         "description": "Internal Server Error",
                         "schema": {
@@ -1462,7 +3886,7 @@ const docTemplate = `{
                 }
             }
         },
-        "/tokens": {
+        "/users/{id}/tokens": {
             "get": {
                 "security": [
                     {
@@ -1475,7 +3899,16 @@ const docTemplate = `{
                 "tags": [
                     "tokens"
                 ],
-                "summary": "Get list of tokens for the current user",
+                "summary": "Get list of tokens for a user",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "User id",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
                 "responses": {
                     "200": {
                         "description": "OK",
@@ -1486,50 +3919,11 @@ const docTemplate = `{
                             }
                         }
                     },
-                    "401": {
-                        "description": "Unauthorized",
-                        "schema": {
-                            "$ref": "#/definitions/response.ErrorResponse"
-                        }
-                    },
-                    "500": {
-                        "description": "Internal Server Error",
+                    "400": {
+                        "description": "Bad Request",
                         "schema": {
                             "$ref": "#/definitions/response.ErrorResponse"
                         }
-                    }
-                }
-            },
-            "post": {
-                "security": [
-                    {
-                        "JWT": []
-                    }
-                ],
-                "produces": [
-                    "application/json"
-                ],
-                "tags": [
-                    "tokens"
-                ],
-                "summary": "Creates an api token",
-                "parameters": [
-                    {
-                        "description": "Body",
-                        "name": "body",
-                        "in": "body",
-                        "required": true,
-                        "schema": {
-                            "$ref": "#/definitions/api.createTokenRequest"
-                        }
-                    }
-                ],
-                "responses": {
-                    "200": {
-                        "description": "OK",
-                        "schema": {
-                            "$ref": "#/definitions/api.tokenResponse"
-                        }
                     },
                     "401": {
                         "description": "Unauthorized",
@@ -1550,9 +3944,7 @@ const docTemplate = `{
                         }
                     }
                 }
-            }
-        },
-        "/tokens/{id}": {
+            },
             "delete": {
                 "security": [
                     {
@@ -1565,11 +3957,11 @@ const docTemplate = `{
                 "tags": [
                     "tokens"
                 ],
-                "summary": "Revokes a token",
+                "summary": "Revokes all tokens of a user",
                 "parameters": [
                     {
                         "type": "integer",
-                        "description": "Token ID",
+                        "description": "User id",
                         "name": "id",
                         "in": "path",
                         "required": true
@@ -1579,6 +3971,12 @@ const docTemplate = `{
                     "204": {
                         "description": "No Content"
                     },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/response.ErrorResponse"
+                        }
+                    },
                     "401": {
                         "description": "Unauthorized",
                         "schema": {
@@ -1600,7 +3998,7 @@ const docTemplate = `{
                 }
             }
         },
-        "/two-factor-auth": {
+        "/users/{id}/trusted-devices": {
             "get": {
                 "security": [
                     {
@@ -1611,63 +4009,28 @@ const docTemplate = `{
                     "application/json"
                 ],
                 "tags": [
-                    "two-factor-auth"
+                    "sessions"
+                ],
+                "summary": "Get list of trusted devices for a user",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "User id",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
                 ],
-                "summary": "Get the two factor auth settings for the current user",
                 "responses": {
                     "200": {
                         "description": "OK",
                         "schema": {
                             "type": "array",
                             "items": {
-                                "$ref": "#/definitions/core.TwoFactorSettings"
+                                "$ref": "#/definitions/core.TrustedDevice"
                             }
                         }
                     },
-                    "401": {
-                        "description": "Unauthorized",
-                        "schema": {
-                            "$ref": "#/definitions/response.ErrorResponse"
-                        }
-                    },
-                    "500": {
-                        "description": "Internal Server Error",
-                        "schema": {
-                            "$ref": "#/definitions/response.ErrorResponse"
-                        }
-                    }
-                }
-            }
-        },
-        "/two-factor-auth/activate": {
-            "put": {
-                "security": [
-                    {
-                        "JWT": []
-                    }
-                ],
-                "produces": [
-                    "application/json"
-                ],
-                "tags": [
-                    "two-factor-auth"
-                ],
-                "summary": "Activates two factor auth for the current user",
-                "parameters": [
-                    {
-                        "description": "Body",
-                        "name": "body",
-                        "in": "body",
-                        "required": true,
-                        "schema": {
-                            "$ref": "#/definitions/api.twoFactorAuthActivateRequest"
-                        }
-                    }
-                ],
-                "responses": {
-                    "204": {
-                        "description": "No Content"
-                    },
                     "400": {
                         "description": "Bad Request",
                         "schema": {
@@ -1680,8 +4043,8 @@ const docTemplate = `{
                             "$ref": "#/definitions/response.ErrorResponse"
                         }
                     },
-                    "409": {
-                        "description": "Conflict",
+                    "403": {
+                        "description": "Forbidden",
                         "schema": {
                             "$ref": "#/definitions/response.ErrorResponse"
                         }
@@ -1693,31 +4056,24 @@ const docTemplate = `{
                         }
                     }
                 }
-            }
-        },
-        "/two-factor-auth/deactivate": {
-            "put": {
+            },
+            "delete": {
                 "security": [
                     {
                         "JWT": []
                     }
                 ],
-                "produces": [
-                    "application/json"
-                ],
                 "tags": [
-                    "two-factor-auth"
+                    "sessions"
                 ],
-                "summary": "Deactivates two factor auth for the current user",
-                "parameters": [
-                    {
-                        "description": "Body",
-                        "name": "body",
-                        "in": "body",
-                        "required": true,
-                        "schema": {
-                            "$ref": "#/definitions/api.twoFactorAuthDeactivateRequest"
-                        }
+                "summary": "Revokes all trusted devices of a user",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "User id",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
                     }
                 ],
                 "responses": {
@@ -1736,8 +4092,8 @@ const docTemplate = `{
                             "$ref": "#/definitions/response.ErrorResponse"
                         }
                     },
-                    "409": {
-                        "description": "Conflict",
+                    "403": {
+                        "description": "Forbidden",
                         "schema": {
                             "$ref": "#/definitions/response.ErrorResponse"
                         }
@@ -1751,8 +4107,8 @@ const docTemplate = `{
                 }
             }
         },
-        "/two-factor-auth/setup": {
-            "put": {
+        "/users/{id}/two-factor-auth": {
+            "delete": {
                 "security": [
                     {
                         "JWT": []
@@ -1764,24 +4120,19 @@ const docTemplate = `{
                 "tags": [
                     "two-factor-auth"
                 ],
-                "summary": "Starts the two factor auth setup for the current user",
+                "summary": "Disables two factor auth for a user",
                 "parameters": [
                     {
-                        "description": "Body",
-                        "name": "body",
-                        "in": "body",
-                        "required": true,
-                        "schema": {
-                            "$ref": "#/definitions/api.twoFactorAuthSetupRequest"
-                        }
+                        "type": "integer",
+                        "description": "User id",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
                     }
                 ],
                 "responses": {
-                    "200": {
-                        "description": "OK",
-                        "schema": {
-                            "$ref": "#/definitions/api.twoFactorAuthSetupResponse"
-                        }
+                    "204": {
+                        "description": "No Content"
                     },
                     "400": {
                         "description": "Bad Request",
@@ -1795,6 +4146,12 @@ const docTemplate = `{
                             "$ref": "#/definitions/response.ErrorResponse"
                         }
                     },
+                    "403": {
+                        "description": "Forbidden",
+                        "schema": {
+                            "$ref": "#/definitions/response.ErrorResponse"
+                        }
+                    },
                     "409": {
                         "description": "Conflict",
                         "schema": {
@@ -1810,8 +4167,8 @@ const docTemplate = `{
                 }
             }
         },
-        "/user": {
-            "get": {
+        "/users/{userId}/sessions/{id}": {
+            "delete": {
                 "security": [
                     {
                         "JWT": []
@@ -1821,15 +4178,28 @@ const docTemplate = `{
                     "application/json"
                 ],
                 "tags": [
-                    "user"
+                    "sessions"
+                ],
+                "summary": "Revokes a session for a user",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "Session id",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "integer",
+                        "description": "User id",
+                        "name": "userId",
+                        "in": "path",
+                        "required": true
+                    }
                 ],
-                "summary": "Get the current user",
                 "responses": {
-                    "200": {
-                        "description": "OK",
-                        "schema": {
-                            "$ref": "#/definitions/core.User"
-                        }
+                    "204": {
+                        "description": "No Content"
                     },
                     "401": {
                         "description": "Unauthorized",
@@ -1844,40 +4214,41 @@ const docTemplate = `{
                         }
                     }
                 }
-            },
-            "patch": {
+            }
+        },
+        "/users/{userId}/tokens/{id}": {
+            "delete": {
                 "security": [
                     {
                         "JWT": []
                     }
                 ],
-                "consumes": [
-                    "application/json"
-                ],
                 "produces": [
                     "application/json"
                 ],
                 "tags": [
-                    "user"
+                    "tokens"
                 ],
-                "summary": "Updates the current user",
+                "summary": "Revokes a token of a user",
                 "parameters": [
                     {
-                        "description": "Body",
-                        "name": "body",
-                        "in": "body",
-                        "required": true,
-                        "schema": {
-                            "$ref": "#/definitions/api.userUpdate"
-                        }
+                        "type": "integer",
+                        "description": "Token id",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "integer",
+                        "description": "User id",
+                        "name": "userId",
+                        "in": "path",
+                        "required": true
                     }
                 ],
                 "responses": {
-                    "200": {
-                        "description": "OK",
-                        "schema": {
-                            "$ref": "#/definitions/core.User"
-                        }
+                    "204": {
+                        "description": "No Content"
                     },
                     "400": {
                         "description": "Bad Request",
@@ -1891,6 +4262,12 @@ const docTemplate = `{
                             "$ref": "#/definitions/response.ErrorResponse"
                         }
                     },
+                    "403": {
+                        "description": "Forbidden",
+                        "schema": {
+                            "$ref": "#/definitions/response.ErrorResponse"
+                        }
+                    },
                     "500": {
                         "description": "Internal Server Error",
                         "schema": {
@@ -1900,32 +4277,31 @@ const docTemplate = `{
                 }
             }
         },
-        "/user/password": {
-            "patch": {
+        "/users/{userId}/trusted-devices/{id}": {
+            "delete": {
                 "security": [
                     {
                         "JWT": []
                     }
                 ],
-                "consumes": [
-                    "application/json"
-                ],
-                "produces": [
-                    "application/json"
-                ],
                 "tags": [
-                    "user"
+                    "sessions"
                 ],
-                "summary": "Updates the password of the current user",
+                "summary": "Revokes a trusted device of a user",
                 "parameters": [
                     {
-                        "description": "Body",
-                        "name": "body",
-                        "in": "body",
-                        "required": true,
-                        "schema": {
-                            "$ref": "#/definitions/api.userUpdatePassword"
-                        }
+                        "type": "integer",
+                        "description": "Trusted device id",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "integer",
+                        "description": "User id",
+                        "name": "userId",
+                        "in": "path",
+                        "required": true
                     }
                 ],
                 "responses": {
@@ -1944,6 +4320,12 @@ const docTemplate = `{
                             "$ref": "#/definitions/response.ErrorResponse"
                         }
                     },
+                    "403": {
+                        "description": "Forbidden",
+                        "schema": {
+                            "$ref": "#/definitions/response.ErrorResponse"
+                        }
+                    },
                     "500": {
                         "description": "Internal Server Error",
                         "schema": {
@@ -1953,22 +4335,27 @@ const docTemplate = `{
                 }
             }
         },
-        "/users": {
+        "/webauthn/credentials": {
             "get": {
+                "security": [
+                    {
+                        "JWT": []
+                    }
+                ],
                 "produces": [
                     "application/json"
                 ],
                 "tags": [
-                    "user"
+                    "webauthn"
                 ],
-                "summary": "Get a list of users",
+                "summary": "Get list of passkeys for the current user",
                 "responses": {
                     "200": {
                         "description": "OK",
                         "schema": {
                             "type": "array",
                             "items": {
-                                "$ref": "#/definitions/core.UserListResult"
+                                "$ref": "#/definitions/core.WebAuthnCredential"
                             }
                         }
                     },
@@ -1985,81 +4372,34 @@ const docTemplate = `{
                         }
                     }
                 }
-            },
-            "post": {
-                "consumes": [
-                    "application/json"
-                ],
-                "produces": [
-                    "application/json"
-                ],
-                "tags": [
-                    "user"
-                ],
-                "summary": "Creates a new user",
-                "parameters": [
+            }
+        },
+        "/webauthn/credentials/{id}": {
+            "delete": {
+                "security": [
                     {
-                        "description": "Body",
-                        "name": "body",
-                        "in": "body",
-                        "required": true,
-                        "schema": {
-                            "$ref": "#/definitions/api.createUser"
-                        }
+                        "JWT": []
                     }
                 ],
-                "responses": {
-                    "201": {
-                        "description": "Created",
-                        "schema": {
-                            "$ref": "#/definitions/core.User"
-                        }
-                    },
-                    "400": {
-                        "description": "Bad Request",
-                        "schema": {
-                            "$ref": "#/definitions/response.ErrorResponse"
-                        }
-                    },
-                    "401": {
-                        "description": "Unauthorized",
-                        "schema": {
-                            "$ref": "#/definitions/response.ErrorResponse"
-                        }
-                    },
-                    "500": {
-                        "description": "Internal Server Error",
-                        "schema": {
-                            "$ref": "#/definitions/response.ErrorResponse"
-                        }
-                    }
-                }
-            }
-        },
-        "/users/{id}": {
-            "get": {
                 "produces": [
                     "application/json"
                 ],
                 "tags": [
-                    "user"
+                    "webauthn"
                 ],
-                "summary": "Get a user by ID",
+                "summary": "Deletes a passkey of the current user",
                 "parameters": [
                     {
-                        "type": "string",
-                        "description": "User ID",
+                        "type": "integer",
+                        "description": "Credential ID",
                         "name": "id",
                         "in": "path",
                         "required": true
                     }
                 ],
                 "responses": {
-                    "200": {
-                        "description": "OK",
-                        "schema": {
-                            "$ref": "#/definitions/core.User"
-                        }
+                    "204": {
+                        "description": "No Content"
                     },
                     "400": {
                         "description": "Bad Request",
@@ -2073,6 +4413,12 @@ const docTemplate = `{
                             "$ref": "#/definitions/response.ErrorResponse"
                         }
                     },
+                    "403": {
+                        "description": "Forbidden",
+                        "schema": {
+                            "$ref": "#/definitions/response.ErrorResponse"
+                        }
+                    },
                     "404": {
                         "description": "Not Found",
                         "schema": {
@@ -2087,26 +4433,43 @@ const docTemplate = `{
                     }
                 }
             },
-            "delete": {
+            "patch": {
+                "security": [
+                    {
+                        "JWT": []
+                    }
+                ],
                 "produces": [
                     "application/json"
                 ],
                 "tags": [
-                    "user"
+                    "webauthn"
                 ],
-                "summary": "Deletes a user",
+                "summary": "Renames a passkey of the current user",
                 "parameters": [
                     {
-                        "type": "string",
-                        "description": "User ID",
+                        "type": "integer",
+                        "description": "Credential ID",
                         "name": "id",
                         "in": "path",
                         "required": true
+                    },
+                    {
+                        "description": "Body",
+                        "name": "body",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/api.webAuthnCredentialUpdateRequest"
+                        }
                     }
                 ],
                 "responses": {
-                    "204": {
-                        "description": "No Content"
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/core.WebAuthnCredential"
+                        }
                     },
                     "400": {
                         "description": "Bad Request",
@@ -2135,8 +4498,8 @@ const docTemplate = `{
                 }
             }
         },
-        "/users/{id}/sessions": {
-            "get": {
+        "/webauthn/registration/begin": {
+            "post": {
                 "security": [
                     {
                         "JWT": []
@@ -2146,26 +4509,31 @@ const docTemplate = `{
                     "application/json"
                 ],
                 "tags": [
-                    "sessions"
+                    "webauthn"
                 ],
-                "summary": "Get list of session for a user",
+                "summary": "Starts the registration of a passkey for the current user",
                 "parameters": [
                     {
-                        "type": "integer",
-                        "description": "User id",
-                        "name": "id",
-                        "in": "path",
-                        "required": true
+                        "description": "Body",
+                        "name": "body",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/api.webAuthnRegistrationBeginRequest"
+                        }
                     }
                 ],
                 "responses": {
                     "200": {
                         "description": "OK",
                         "schema": {
-                            "type": "array",
-                            "items": {
-                                "$ref": "#/definitions/core.Session"
-                            }
+                            "$ref": "#/definitions/core.WebAuthnCeremony"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/response.ErrorResponse"
                         }
                     },
                     "401": {
@@ -2174,6 +4542,12 @@ const docTemplate = `{
                             "$ref": "#/definitions/response.ErrorResponse"
                         }
                     },
+                    "403": {
+                        "description": "Forbidden",
+                        "schema": {
+                            "$ref": "#/definitions/response.ErrorResponse"
+                        }
+                    },
                     "500": {
                         "description": "Internal Server Error",
                         "schema": {
@@ -2183,8 +4557,8 @@ const docTemplate = `{
                 }
             }
         },
-        "/users/{userId}/sessions/{id}": {
-            "delete": {
+        "/webauthn/registration/finish": {
+            "post": {
                 "security": [
                     {
                         "JWT": []
@@ -2194,28 +4568,32 @@ const docTemplate = `{
                     "application/json"
                 ],
                 "tags": [
-                    "sessions"
+                    "webauthn"
                 ],
-                "summary": "Revokes a session for a user",
+                "summary": "Finishes the registration of a passkey for the current user",
                 "parameters": [
                     {
-                        "type": "integer",
-                        "description": "Session id",
-                        "name": "id",
-                        "in": "path",
-                        "required": true
-                    },
-                    {
-                        "type": "integer",
-                        "description": "User id",
-                        "name": "userId",
-                        "in": "path",
-                        "required": true
+                        "description": "Body",
+                        "name": "body",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/api.webAuthnRegistrationFinishRequest"
+                        }
                     }
                 ],
                 "responses": {
-                    "204": {
-                        "description": "No Content"
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/core.WebAuthnCredential"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/response.ErrorResponse"
+                        }
                     },
                     "401": {
                         "description": "Unauthorized",
@@ -2223,6 +4601,12 @@ const docTemplate = `{
                             "$ref": "#/definitions/response.ErrorResponse"
                         }
                     },
+                    "403": {
+                        "description": "Forbidden",
+                        "schema": {
+                            "$ref": "#/definitions/response.ErrorResponse"
+                        }
+                    },
                     "500": {
                         "description": "Internal Server Error",
                         "schema": {
@@ -2234,11 +4618,68 @@ const docTemplate = `{
         }
     },
     "definitions": {
+        "api.adminUserResetPassword": {
+            "type": "object",
+            "properties": {
+                "password": {
+                    "type": "string"
+                },
+                "revokeSessions": {
+                    "description": "RevokeSessions revokes all sessions and tokens of the user.",
+                    "type": "boolean"
+                }
+            }
+        },
+        "api.adminUserUpdate": {
+            "type": "object",
+            "properties": {
+                "disabled": {
+                    "description": "Disabled disables the user and revokes all sessions and tokens.",
+                    "type": "boolean"
+                },
+                "displayName": {
+                    "type": "string"
+                },
+                "email": {
+                    "type": "string"
+                },
+                "isAdmin": {
+                    "description": "IsAdmin grants the admin role or replaces it by the default role.",
+                    "type": "boolean"
+                },
+                "username": {
+                    "type": "string"
+                }
+            }
+        },
+        "api.approveDeviceRequest": {
+            "type": "object",
+            "properties": {
+                "scopes": {
+                    "description": "Scopes restrict the api token issued to the device.\nWithout scopes the device gets a session.",
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/core.Permission"
+                    }
+                }
+            }
+        },
         "api.createTokenRequest": {
             "type": "object",
             "properties": {
+                "expiresAt": {
+                    "description": "ExpiresAt is an optional unix timestamp after which the token is invalid.",
+                    "type": "integer"
+                },
                 "name": {
                     "type": "string"
+                },
+                "scopes": {
+                    "description": "Scopes optionally restrict the token to a subset of the permissions of the user.",
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/core.Permission"
+                    }
                 }
             }
         },
@@ -2251,14 +4692,72 @@ const docTemplate = `{
                 "email": {
                     "type": "string"
                 },
+                "isAdmin": {
+                    "description": "IsAdmin is only used if no role is set.",
+                    "type": "boolean"
+                },
                 "password": {
                     "type": "string"
                 },
+                "role": {
+                    "$ref": "#/definitions/core.Role"
+                },
                 "username": {
                     "type": "string"
                 }
             }
         },
+        "api.deviceTokenError": {
+            "type": "object",
+            "properties": {
+                "error": {
+                    "type": "string"
+                },
+                "error_description": {
+                    "type": "string"
+                }
+            }
+        },
+        "api.loginResponse": {
+            "type": "object",
+            "properties": {
+                "token": {
+                    "type": "string"
+                }
+            }
+        },
+        "api.signStreamRequest": {
+            "type": "object",
+            "properties": {
+                "channelNumber": {
+                    "description": "ChannelNumber is the number of the channel to sign.\nEither ChannelNumber or RecordingID must be set.",
+                    "type": "integer"
+                },
+                "hls": {
+                    "description": "HLS signs the HLS playlist instead of the raw stream of a channel.",
+                    "type": "boolean"
+                },
+                "profile": {
+                    "description": "Profile is the streaming profile of a channel stream.",
+                    "type": "string"
+                },
+                "recordingId": {
+                    "description": "RecordingID is the id of the recording to sign.\nEither ChannelNumber or RecordingID must be set.",
+                    "type": "string"
+                }
+            }
+        },
+        "api.signedStreamResponse": {
+            "type": "object",
+            "properties": {
+                "expiresAt": {
+                    "type": "integer"
+                },
+                "url": {
+                    "type": "string"
+                }
+            }
+        },
         "api.tokenResponse": {
             "type": "object",
             "properties": {
@@ -2275,6 +4774,10 @@ const docTemplate = `{
                 },
                 "password": {
                     "type": "string"
+                },
+                "revokeSessions": {
+                    "description": "RevokeSessions revokes all other sessions and all tokens of the user.",
+                    "type": "boolean"
                 }
             }
         },
@@ -2286,6 +4789,26 @@ const docTemplate = `{
                 }
             }
         },
+        "api.twoFactorAuthRecoveryCodesRequest": {
+            "type": "object",
+            "properties": {
+                "password": {
+                    "type": "string"
+                }
+            }
+        },
+        "api.twoFactorAuthRecoveryCodesResponse": {
+            "type": "object",
+            "properties": {
+                "recovery_codes": {
+                    "description": "RecoveryCodes are single-use codes, which can be used instead of a totp code.\nThey are only returned once and cannot be retrieved later.",
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    }
+                }
+            }
+        },
         "api.twoFactorAuthSetupRequest": {
             "type": "object",
             "properties": {
@@ -2308,22 +4831,199 @@ const docTemplate = `{
                 "displayName": {
                     "type": "string"
                 },
-                "email": {
-                    "type": "string"
+                "email": {
+                    "type": "string"
+                },
+                "username": {
+                    "type": "string"
+                }
+            }
+        },
+        "api.userUpdatePassword": {
+            "type": "object",
+            "properties": {
+                "currentPassword": {
+                    "type": "string"
+                },
+                "password": {
+                    "type": "string"
+                },
+                "revokeSessions": {
+                    "description": "RevokeSessions revokes all other sessions and all tokens of the user.",
+                    "type": "boolean"
+                }
+            }
+        },
+        "api.userUpdateRole": {
+            "type": "object",
+            "properties": {
+                "role": {
+                    "$ref": "#/definitions/core.Role"
+                }
+            }
+        },
+        "api.webAuthnAssertion": {
+            "type": "object",
+            "properties": {
+                "credential": {
+                    "type": "object"
+                },
+                "id": {
+                    "type": "string"
+                }
+            }
+        },
+        "api.webAuthnCredentialUpdateRequest": {
+            "type": "object",
+            "properties": {
+                "name": {
+                    "type": "string"
+                }
+            }
+        },
+        "api.webAuthnRegistrationBeginRequest": {
+            "type": "object",
+            "properties": {
+                "password": {
+                    "type": "string"
+                }
+            }
+        },
+        "api.webAuthnRegistrationFinishRequest": {
+            "type": "object",
+            "properties": {
+                "credential": {
+                    "type": "object"
+                },
+                "id": {
+                    "type": "string"
+                },
+                "name": {
+                    "type": "string"
+                }
+            }
+        },
+        "core.AuditAction": {
+            "type": "string",
+            "enum": [
+                "login",
+                "login_failed",
+                "logout",
+                "session_revoke",
+                "session_revoke_all",
+                "token_create",
+                "token_revoke",
+                "token_revoke_all",
+                "password_change",
+                "two_factor_activate",
+                "two_factor_deactivate",
+                "two_factor_recovery_codes",
+                "passkey_register",
+                "passkey_delete",
+                "device_approve",
+                "device_deny",
+                "trusted_device_create",
+                "trusted_device_revoke",
+                "trusted_device_revoke_all",
+                "user_create",
+                "user_delete",
+                "user_role_update",
+                "user_update",
+                "user_disable",
+                "user_enable",
+                "password_reset",
+                "parental_controls_update",
+                "parental_controls_delete",
+                "lockout_clear",
+                "recording_cancel",
+                "recording_remove",
+                "dvr_config_delete"
+            ],
+            "x-enum-varnames": [
+                "AuditActionLogin",
+                "AuditActionLoginFailed",
+                "AuditActionLogout",
+                "AuditActionSessionRevoke",
+                "AuditActionSessionRevokeAll",
+                "AuditActionTokenCreate",
+                "AuditActionTokenRevoke",
+                "AuditActionTokenRevokeAll",
+                "AuditActionPasswordChange",
+                "AuditActionTwoFactorActivate",
+                "AuditActionTwoFactorDeactivate",
+                "AuditActionTwoFactorRecoveryCodes",
+                "AuditActionPasskeyRegister",
+                "AuditActionPasskeyDelete",
+                "AuditActionDeviceApprove",
+                "AuditActionDeviceDeny",
+                "AuditActionTrustedDeviceCreate",
+                "AuditActionTrustedDeviceRevoke",
+                "AuditActionTrustedDeviceRevokeAll",
+                "AuditActionUserCreate",
+                "AuditActionUserDelete",
+                "AuditActionUserRoleUpdate",
+                "AuditActionUserUpdate",
+                "AuditActionUserDisable",
+                "AuditActionUserEnable",
+                "AuditActionPasswordReset",
+                "AuditActionParentalControlsUpdate",
+                "AuditActionParentalControlsDelete",
+                "AuditActionLockoutClear",
+                "AuditActionRecordingCancel",
+                "AuditActionRecordingRemove",
+                "AuditActionDVRConfigDelete"
+            ]
+        },
+        "core.AuditEntry": {
+            "type": "object",
+            "properties": {
+                "action": {
+                    "$ref": "#/definitions/core.AuditAction"
+                },
+                "clientIp": {
+                    "type": "string"
+                },
+                "createdAt": {
+                    "type": "integer"
+                },
+                "id": {
+                    "type": "integer"
+                },
+                "reason": {
+                    "description": "Reason is the reason of a failed action.",
+                    "type": "string"
+                },
+                "target": {
+                    "description": "Target is the id of the affected object (e.g. a session, token, user or recording).",
+                    "type": "string"
+                },
+                "userAgent": {
+                    "type": "string"
+                },
+                "userId": {
+                    "description": "UserID is the id of the acting user. It is nil if the\nuser is unknown, e.g. for a failed login.",
+                    "type": "integer"
                 },
                 "username": {
+                    "description": "Username is the name of the acting user or the attempted username\nof a failed login. It is kept when the user is deleted.",
                     "type": "string"
                 }
             }
         },
-        "api.userUpdatePassword": {
+        "core.AuditListResult": {
             "type": "object",
             "properties": {
-                "currentPassword": {
-                    "type": "string"
+                "entries": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/core.AuditEntry"
+                    }
                 },
-                "password": {
-                    "type": "string"
+                "offset": {
+                    "type": "integer"
+                },
+                "total": {
+                    "type": "integer"
                 }
             }
         },
@@ -2344,6 +5044,13 @@ const docTemplate = `{
                 },
                 "piconId": {
                     "type": "integer"
+                },
+                "tags": {
+                    "description": "Tags are the ids of the channel tags.",
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    }
                 }
             }
         },
@@ -2822,6 +5529,92 @@ const docTemplate = `{
                 }
             }
         },
+        "core.DeviceAuthStatus": {
+            "type": "string",
+            "enum": [
+                "pending",
+                "approved",
+                "denied"
+            ],
+            "x-enum-varnames": [
+                "DeviceAuthStatusPending",
+                "DeviceAuthStatusApproved",
+                "DeviceAuthStatusDenied"
+            ]
+        },
+        "core.DeviceAuthorization": {
+            "type": "object",
+            "properties": {
+                "clientIp": {
+                    "type": "string"
+                },
+                "createdAt": {
+                    "type": "integer"
+                },
+                "deviceName": {
+                    "type": "string"
+                },
+                "expiresAt": {
+                    "type": "integer"
+                },
+                "scopes": {
+                    "description": "Scopes restrict the api token issued to the device.\nIf empty, the device gets a session instead.",
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/core.Permission"
+                    }
+                },
+                "status": {
+                    "$ref": "#/definitions/core.DeviceAuthStatus"
+                },
+                "userCode": {
+                    "type": "string"
+                }
+            }
+        },
+        "core.DeviceCode": {
+            "type": "object",
+            "properties": {
+                "device_code": {
+                    "type": "string"
+                },
+                "expires_in": {
+                    "type": "integer"
+                },
+                "interval": {
+                    "type": "integer"
+                },
+                "user_code": {
+                    "type": "string"
+                },
+                "verification_uri": {
+                    "description": "VerificationURI is the url, where the user enters the user code.",
+                    "type": "string"
+                },
+                "verification_uri_complete": {
+                    "description": "VerificationURIComplete contains the user code and is meant to be shown as qr code.",
+                    "type": "string"
+                }
+            }
+        },
+        "core.DeviceToken": {
+            "type": "object",
+            "properties": {
+                "access_token": {
+                    "type": "string"
+                },
+                "scopes": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/core.Permission"
+                    }
+                },
+                "token_type": {
+                    "description": "TokenType is either DeviceTokenTypeSession or DeviceTokenTypeBearer.",
+                    "type": "string"
+                }
+            }
+        },
         "core.EpgChannel": {
             "type": "object",
             "properties": {
@@ -2871,6 +5664,13 @@ const docTemplate = `{
                 "channelNumber": {
                     "type": "integer"
                 },
+                "contentTypes": {
+                    "description": "ContentTypes are the ids of the epg content types of the event.",
+                    "type": "array",
+                    "items": {
+                        "type": "integer"
+                    }
+                },
                 "description": {
                     "type": "string"
                 },
@@ -2929,6 +5729,111 @@ const docTemplate = `{
                 }
             }
         },
+        "core.LoginThrottle": {
+            "type": "object",
+            "properties": {
+                "blockedUntil": {
+                    "description": "BlockedUntil is the time until further logins are rejected.",
+                    "type": "integer"
+                },
+                "failedAttempts": {
+                    "type": "integer"
+                },
+                "id": {
+                    "type": "integer"
+                },
+                "key": {
+                    "type": "string"
+                },
+                "kind": {
+                    "$ref": "#/definitions/core.LoginThrottleKind"
+                },
+                "lastFailedAt": {
+                    "type": "integer"
+                },
+                "locked": {
+                    "description": "Locked is true if the maximum failed attempts were reached\nand logins are rejected for the full lockout duration.",
+                    "type": "boolean"
+                }
+            }
+        },
+        "core.LoginThrottleKind": {
+            "type": "string",
+            "enum": [
+                "ip",
+                "username"
+            ],
+            "x-enum-varnames": [
+                "LoginThrottleKindIP",
+                "LoginThrottleKindUsername"
+            ]
+        },
+        "core.ParentalControls": {
+            "type": "object",
+            "properties": {
+                "blockedContentTypes": {
+                    "description": "BlockedContentTypes are the ids of blocked epg content types.\nA main content type (e.g. 16 for \"Movie / Drama\") blocks all\nits sub types.",
+                    "type": "array",
+                    "items": {
+                        "type": "integer"
+                    }
+                },
+                "channelTags": {
+                    "description": "ChannelTags are the ids of channel tags. Channels with one of\nthe tags are allowed as well. If neither channels nor tags\nare set, all channels are allowed.",
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    }
+                },
+                "channels": {
+                    "description": "Channels are the ids of allowed channels.",
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    }
+                },
+                "createdAt": {
+                    "type": "integer"
+                },
+                "updatedAt": {
+                    "type": "integer"
+                },
+                "viewingFrom": {
+                    "description": "ViewingFrom and ViewingTo optionally restrict streaming to a\ndaily time window in the local time of the server (HH:MM).\nThe window may span midnight, e.g. from 18:00 to 02:00.",
+                    "type": "string"
+                },
+                "viewingTo": {
+                    "type": "string"
+                }
+            }
+        },
+        "core.Permission": {
+            "type": "string",
+            "enum": [
+                "account",
+                "epg:read",
+                "stream",
+                "recordings:read",
+                "recordings:write",
+                "recordings:manage",
+                "dvr-config:manage",
+                "streams:manage",
+                "audit:read",
+                "users:manage"
+            ],
+            "x-enum-varnames": [
+                "PermissionAccount",
+                "PermissionEPGRead",
+                "PermissionStream",
+                "PermissionRecordingsRead",
+                "PermissionRecordingsWrite",
+                "PermissionRecordingsManage",
+                "PermissionDVRConfigManage",
+                "PermissionStreamsManage",
+                "PermissionAuditRead",
+                "PermissionUsersManage"
+            ]
+        },
         "core.Recording": {
             "type": "object",
             "properties": {
@@ -2938,6 +5843,13 @@ const docTemplate = `{
                 "channelName": {
                     "type": "string"
                 },
+                "contentTypes": {
+                    "description": "ContentTypes are the ids of the epg content types of the recording.",
+                    "type": "array",
+                    "items": {
+                        "type": "integer"
+                    }
+                },
                 "createdAt": {
                     "type": "integer"
                 },
@@ -3024,6 +5936,23 @@ const docTemplate = `{
                 }
             }
         },
+        "core.Role": {
+            "type": "string",
+            "enum": [
+                "viewer",
+                "recorder",
+                "dvr-admin",
+                "admin",
+                "dvr-admin"
+            ],
+            "x-enum-varnames": [
+                "RoleViewer",
+                "RoleRecorder",
+                "RoleDVRAdmin",
+                "RoleAdmin",
+                "DefaultRole"
+            ]
+        },
         "core.Session": {
             "type": "object",
             "properties": {
@@ -3033,6 +5962,10 @@ const docTemplate = `{
                 "createdAt": {
                     "type": "integer"
                 },
+                "deviceName": {
+                    "description": "DeviceName is set for sessions of devices authorized via the device flow.",
+                    "type": "string"
+                },
                 "id": {
                     "type": "integer"
                 },
@@ -3058,23 +5991,114 @@ const docTemplate = `{
                 }
             }
         },
+        "core.StreamSession": {
+            "type": "object",
+            "properties": {
+                "bytesTransferred": {
+                    "type": "integer"
+                },
+                "channelNumber": {
+                    "description": "ChannelNumber is the number of the streamed channel.\nOnly set for streams of type channel.",
+                    "type": "integer"
+                },
+                "clientIp": {
+                    "type": "string"
+                },
+                "id": {
+                    "type": "string"
+                },
+                "profile": {
+                    "type": "string"
+                },
+                "recordingId": {
+                    "description": "RecordingID is the id of the streamed recording.\nOnly set for streams of type recording.",
+                    "type": "string"
+                },
+                "startedAt": {
+                    "type": "integer"
+                },
+                "type": {
+                    "$ref": "#/definitions/core.StreamType"
+                },
+                "userAgent": {
+                    "type": "string"
+                },
+                "userId": {
+                    "type": "integer"
+                }
+            }
+        },
+        "core.StreamType": {
+            "type": "string",
+            "enum": [
+                "channel",
+                "recording"
+            ],
+            "x-enum-varnames": [
+                "StreamTypeChannel",
+                "StreamTypeRecording"
+            ]
+        },
         "core.Token": {
             "type": "object",
             "properties": {
                 "createdAt": {
                     "type": "integer"
                 },
+                "expiresAt": {
+                    "description": "ExpiresAt is the time the token expires at.\nTokens without expiry are valid until they are revoked.",
+                    "type": "integer"
+                },
                 "id": {
                     "type": "integer"
                 },
+                "lastUsedAt": {
+                    "type": "integer"
+                },
+                "lastUsedIp": {
+                    "type": "string"
+                },
                 "name": {
                     "type": "string"
                 },
+                "scopes": {
+                    "description": "Scopes restrict the token to a subset of the permissions of the user.\nTokens without scopes have all permissions of the user.",
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/core.Permission"
+                    }
+                },
                 "updatedAt": {
                     "type": "integer"
                 }
             }
         },
+        "core.TrustedDevice": {
+            "type": "object",
+            "properties": {
+                "clientIp": {
+                    "type": "string"
+                },
+                "createdAt": {
+                    "type": "integer"
+                },
+                "expiresAt": {
+                    "type": "integer"
+                },
+                "id": {
+                    "type": "integer"
+                },
+                "lastUsedAt": {
+                    "type": "integer"
+                },
+                "userAgent": {
+                    "type": "string"
+                },
+                "userId": {
+                    "type": "integer"
+                }
+            }
+        },
         "core.TwoFactorSettings": {
             "type": "object",
             "properties": {
@@ -3134,6 +6158,10 @@ const docTemplate = `{
                 "createdAt": {
                     "type": "integer"
                 },
+                "disabled": {
+                    "description": "Disabled users can't log in and are denied all permissions.",
+                    "type": "boolean"
+                },
                 "displayName": {
                     "type": "string"
                 },
@@ -3143,6 +6171,9 @@ const docTemplate = `{
                 "id": {
                     "type": "integer"
                 },
+                "role": {
+                    "$ref": "#/definitions/core.Role"
+                },
                 "twoFactor": {
                     "type": "boolean"
                 },
@@ -3171,9 +6202,46 @@ const docTemplate = `{
                 }
             }
         },
+        "core.WebAuthnCeremony": {
+            "type": "object",
+            "properties": {
+                "id": {
+                    "description": "ID identifies the ceremony when it is finished.",
+                    "type": "string"
+                },
+                "options": {
+                    "description": "Options must be passed to navigator.credentials.create()\nor navigator.credentials.get() by the client.",
+                    "type": "object"
+                }
+            }
+        },
+        "core.WebAuthnCredential": {
+            "type": "object",
+            "properties": {
+                "createdAt": {
+                    "type": "integer"
+                },
+                "id": {
+                    "type": "integer"
+                },
+                "lastUsedAt": {
+                    "type": "integer"
+                },
+                "name": {
+                    "type": "string"
+                },
+                "updatedAt": {
+                    "type": "integer"
+                }
+            }
+        },
         "response.ErrorResponse": {
             "type": "object",
             "properties": {
+                "code": {
+                    "description": "Code is an optional machine readable error code.",
+                    "type": "string"
+                },
                 "message": {
                     "type": "string"
                 }
@@ -36,6 +36,10 @@ func (h *healthRouter) Handler() http.Handler {
 			Name:  "tvheadend",
 			Check: h.tvheadendCheck,
 		}),
+		health.WithCheck(health.Check{
+			Name:  "tvheadend_circuit_breaker",
+			Check: h.tvheadendCircuitBreakerCheck,
+		}),
 	)
 
 	r.Handle("/liveness", health.NewHandler(livenessChecker))
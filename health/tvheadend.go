@@ -3,6 +3,8 @@ package health
 import (
 	"context"
 	"fmt"
+
+	"github.com/davidborzek/tvhgo/tvheadend"
 )
 
 func (r *healthRouter) tvheadendCheck(ctx context.Context) error {
@@ -10,6 +12,7 @@ func (r *healthRouter) tvheadendCheck(ctx context.Context) error {
 	if err != nil {
 		return err
 	}
+	defer res.Body.Close()
 
 	if res.StatusCode >= 400 {
 		return fmt.Errorf("tvheadend returned erroneous status code: %d", res.StatusCode)
@@ -17,3 +20,13 @@ func (r *healthRouter) tvheadendCheck(ctx context.Context) error {
 
 	return nil
 }
+
+// tvheadendCircuitBreakerCheck fails while the circuit breaker is open
+// and requests to tvheadend fail fast.
+func (r *healthRouter) tvheadendCircuitBreakerCheck(_ context.Context) error {
+	if state := r.tvhc.CircuitState(); state == tvheadend.CircuitOpen {
+		return fmt.Errorf("tvheadend circuit breaker is %s", state)
+	}
+
+	return nil
+}
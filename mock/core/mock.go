@@ -3,4 +3,4 @@
 
 package mock_core
 
-//go:generate mockgen -destination=mock_gen.go github.com/davidborzek/tvhgo/core UserRepository,SessionRepository,Clock,TwoFactorAuthService,TwoFactorSettingsRepository,TokenRepository,TokenService,SessionManager,ChannelService
+//go:generate mockgen -destination=mock_gen.go github.com/davidborzek/tvhgo/core UserRepository,SessionRepository,Clock,TwoFactorAuthService,TwoFactorSettingsRepository,TwoFactorRecoveryCodeRepository,TokenRepository,TokenService,SessionManager,ChannelService,StreamSessionRegistry,StreamLimiter,StreamSigner,StreamingService,HLSService,OIDCAuthenticator,PasswordAuthenticator,WebAuthnCredentialRepository,WebAuthnService,RecordingOwnerRepository,LoginThrottleRepository,LoginThrottleService,AuditRepository,AuditService,DeviceAuthRepository,DeviceAuthService,ParentalControlsRepository,ParentalControlsService,TrustedDeviceRepository,TrustedDeviceService,PasswordHasher
@@ -1,9 +1,9 @@
 // Code generated by MockGen. DO NOT EDIT.
-// Source: github.com/davidborzek/tvhgo/core (interfaces: UserRepository,SessionRepository,Clock,TwoFactorAuthService,TwoFactorSettingsRepository,TokenRepository,TokenService,SessionManager,ChannelService)
+// Source: github.com/davidborzek/tvhgo/core (interfaces: UserRepository,SessionRepository,Clock,TwoFactorAuthService,TwoFactorSettingsRepository,TwoFactorRecoveryCodeRepository,TokenRepository,TokenService,SessionManager,ChannelService,StreamSessionRegistry,StreamLimiter,StreamSigner,StreamingService,HLSService,OIDCAuthenticator,PasswordAuthenticator,WebAuthnCredentialRepository,WebAuthnService,RecordingOwnerRepository,LoginThrottleRepository,LoginThrottleService,AuditRepository,AuditService,DeviceAuthRepository,DeviceAuthService,ParentalControlsRepository,ParentalControlsService,TrustedDeviceRepository,TrustedDeviceService,PasswordHasher)
 //
 // Generated by this command:
 //
-//	mockgen -destination=mock_gen.go github.com/davidborzek/tvhgo/core UserRepository,SessionRepository,Clock,TwoFactorAuthService,TwoFactorSettingsRepository,TokenRepository,TokenService,SessionManager,ChannelService
+//	mockgen -destination=mock_gen.go github.com/davidborzek/tvhgo/core UserRepository,SessionRepository,Clock,TwoFactorAuthService,TwoFactorSettingsRepository,TwoFactorRecoveryCodeRepository,TokenRepository,TokenService,SessionManager,ChannelService,StreamSessionRegistry,StreamLimiter,StreamSigner,StreamingService,HLSService,OIDCAuthenticator,PasswordAuthenticator,WebAuthnCredentialRepository,WebAuthnService,RecordingOwnerRepository,LoginThrottleRepository,LoginThrottleService,AuditRepository,AuditService,DeviceAuthRepository,DeviceAuthService,ParentalControlsRepository,ParentalControlsService,TrustedDeviceRepository,TrustedDeviceService,PasswordHasher
 //
 
 // Package mock_core is a generated GoMock package.
@@ -11,6 +11,7 @@ package mock_core
 
 import (
 	context "context"
+	http "net/http"
 	reflect "reflect"
 	time "time"
 
@@ -179,6 +180,21 @@ func (mr *MockSessionRepositoryMockRecorder) Delete(arg0, arg1, arg2 any) *gomoc
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Delete", reflect.TypeOf((*MockSessionRepository)(nil).Delete), arg0, arg1, arg2)
 }
 
+// DeleteByUser mocks base method.
+func (m *MockSessionRepository) DeleteByUser(arg0 context.Context, arg1 int64, arg2 *int64) (int64, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "DeleteByUser", arg0, arg1, arg2)
+	ret0, _ := ret[0].(int64)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// DeleteByUser indicates an expected call of DeleteByUser.
+func (mr *MockSessionRepositoryMockRecorder) DeleteByUser(arg0, arg1, arg2 any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DeleteByUser", reflect.TypeOf((*MockSessionRepository)(nil).DeleteByUser), arg0, arg1, arg2)
+}
+
 // DeleteExpired mocks base method.
 func (m *MockSessionRepository) DeleteExpired(arg0 context.Context, arg1, arg2 int64) (int64, error) {
 	m.ctrl.T.Helper()
@@ -299,11 +315,12 @@ func (m *MockTwoFactorAuthService) EXPECT() *MockTwoFactorAuthServiceMockRecorde
 }
 
 // Activate mocks base method.
-func (m *MockTwoFactorAuthService) Activate(arg0 context.Context, arg1 int64, arg2 string) error {
+func (m *MockTwoFactorAuthService) Activate(arg0 context.Context, arg1 int64, arg2 string) ([]string, error) {
 	m.ctrl.T.Helper()
 	ret := m.ctrl.Call(m, "Activate", arg0, arg1, arg2)
-	ret0, _ := ret[0].(error)
-	return ret0
+	ret0, _ := ret[0].([]string)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
 }
 
 // Activate indicates an expected call of Activate.
@@ -326,6 +343,20 @@ func (mr *MockTwoFactorAuthServiceMockRecorder) Deactivate(arg0, arg1, arg2 any)
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Deactivate", reflect.TypeOf((*MockTwoFactorAuthService)(nil).Deactivate), arg0, arg1, arg2)
 }
 
+// Disable mocks base method.
+func (m *MockTwoFactorAuthService) Disable(arg0 context.Context, arg1 int64) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Disable", arg0, arg1)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// Disable indicates an expected call of Disable.
+func (mr *MockTwoFactorAuthServiceMockRecorder) Disable(arg0, arg1 any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Disable", reflect.TypeOf((*MockTwoFactorAuthService)(nil).Disable), arg0, arg1)
+}
+
 // GetSettings mocks base method.
 func (m *MockTwoFactorAuthService) GetSettings(arg0 context.Context, arg1 int64) (*core.TwoFactorSettings, error) {
 	m.ctrl.T.Helper()
@@ -341,6 +372,21 @@ func (mr *MockTwoFactorAuthServiceMockRecorder) GetSettings(arg0, arg1 any) *gom
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetSettings", reflect.TypeOf((*MockTwoFactorAuthService)(nil).GetSettings), arg0, arg1)
 }
 
+// RegenerateRecoveryCodes mocks base method.
+func (m *MockTwoFactorAuthService) RegenerateRecoveryCodes(arg0 context.Context, arg1 int64) ([]string, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "RegenerateRecoveryCodes", arg0, arg1)
+	ret0, _ := ret[0].([]string)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// RegenerateRecoveryCodes indicates an expected call of RegenerateRecoveryCodes.
+func (mr *MockTwoFactorAuthServiceMockRecorder) RegenerateRecoveryCodes(arg0, arg1 any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "RegenerateRecoveryCodes", reflect.TypeOf((*MockTwoFactorAuthService)(nil).RegenerateRecoveryCodes), arg0, arg1)
+}
+
 // Setup mocks base method.
 func (m *MockTwoFactorAuthService) Setup(arg0 context.Context, arg1 int64) (string, error) {
 	m.ctrl.T.Helper()
@@ -436,6 +482,21 @@ func (mr *MockTwoFactorSettingsRepositoryMockRecorder) Find(arg0, arg1 any) *gom
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Find", reflect.TypeOf((*MockTwoFactorSettingsRepository)(nil).Find), arg0, arg1)
 }
 
+// Reencrypt mocks base method.
+func (m *MockTwoFactorSettingsRepository) Reencrypt(arg0 context.Context) (int, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Reencrypt", arg0)
+	ret0, _ := ret[0].(int)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Reencrypt indicates an expected call of Reencrypt.
+func (mr *MockTwoFactorSettingsRepositoryMockRecorder) Reencrypt(arg0 any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Reencrypt", reflect.TypeOf((*MockTwoFactorSettingsRepository)(nil).Reencrypt), arg0)
+}
+
 // Save mocks base method.
 func (m *MockTwoFactorSettingsRepository) Save(arg0 context.Context, arg1 *core.TwoFactorSettings) error {
 	m.ctrl.T.Helper()
@@ -464,6 +525,72 @@ func (mr *MockTwoFactorSettingsRepositoryMockRecorder) Update(arg0, arg1 any) *g
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Update", reflect.TypeOf((*MockTwoFactorSettingsRepository)(nil).Update), arg0, arg1)
 }
 
+// MockTwoFactorRecoveryCodeRepository is a mock of TwoFactorRecoveryCodeRepository interface.
+type MockTwoFactorRecoveryCodeRepository struct {
+	ctrl     *gomock.Controller
+	recorder *MockTwoFactorRecoveryCodeRepositoryMockRecorder
+}
+
+// MockTwoFactorRecoveryCodeRepositoryMockRecorder is the mock recorder for MockTwoFactorRecoveryCodeRepository.
+type MockTwoFactorRecoveryCodeRepositoryMockRecorder struct {
+	mock *MockTwoFactorRecoveryCodeRepository
+}
+
+// NewMockTwoFactorRecoveryCodeRepository creates a new mock instance.
+func NewMockTwoFactorRecoveryCodeRepository(ctrl *gomock.Controller) *MockTwoFactorRecoveryCodeRepository {
+	mock := &MockTwoFactorRecoveryCodeRepository{ctrl: ctrl}
+	mock.recorder = &MockTwoFactorRecoveryCodeRepositoryMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockTwoFactorRecoveryCodeRepository) EXPECT() *MockTwoFactorRecoveryCodeRepositoryMockRecorder {
+	return m.recorder
+}
+
+// Consume mocks base method.
+func (m *MockTwoFactorRecoveryCodeRepository) Consume(arg0 context.Context, arg1 int64, arg2 string) (bool, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Consume", arg0, arg1, arg2)
+	ret0, _ := ret[0].(bool)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Consume indicates an expected call of Consume.
+func (mr *MockTwoFactorRecoveryCodeRepositoryMockRecorder) Consume(arg0, arg1, arg2 any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Consume", reflect.TypeOf((*MockTwoFactorRecoveryCodeRepository)(nil).Consume), arg0, arg1, arg2)
+}
+
+// DeleteByUser mocks base method.
+func (m *MockTwoFactorRecoveryCodeRepository) DeleteByUser(arg0 context.Context, arg1 int64) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "DeleteByUser", arg0, arg1)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// DeleteByUser indicates an expected call of DeleteByUser.
+func (mr *MockTwoFactorRecoveryCodeRepositoryMockRecorder) DeleteByUser(arg0, arg1 any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DeleteByUser", reflect.TypeOf((*MockTwoFactorRecoveryCodeRepository)(nil).DeleteByUser), arg0, arg1)
+}
+
+// Replace mocks base method.
+func (m *MockTwoFactorRecoveryCodeRepository) Replace(arg0 context.Context, arg1 int64, arg2 []string) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Replace", arg0, arg1, arg2)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// Replace indicates an expected call of Replace.
+func (mr *MockTwoFactorRecoveryCodeRepositoryMockRecorder) Replace(arg0, arg1, arg2 any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Replace", reflect.TypeOf((*MockTwoFactorRecoveryCodeRepository)(nil).Replace), arg0, arg1, arg2)
+}
+
 // MockTokenRepository is a mock of TokenRepository interface.
 type MockTokenRepository struct {
 	ctrl     *gomock.Controller
@@ -515,6 +642,21 @@ func (mr *MockTokenRepositoryMockRecorder) Delete(arg0, arg1 any) *gomock.Call {
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Delete", reflect.TypeOf((*MockTokenRepository)(nil).Delete), arg0, arg1)
 }
 
+// DeleteByUser mocks base method.
+func (m *MockTokenRepository) DeleteByUser(arg0 context.Context, arg1 int64) (int64, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "DeleteByUser", arg0, arg1)
+	ret0, _ := ret[0].(int64)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// DeleteByUser indicates an expected call of DeleteByUser.
+func (mr *MockTokenRepositoryMockRecorder) DeleteByUser(arg0, arg1 any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DeleteByUser", reflect.TypeOf((*MockTokenRepository)(nil).DeleteByUser), arg0, arg1)
+}
+
 // FindByToken mocks base method.
 func (m *MockTokenRepository) FindByToken(arg0 context.Context, arg1 string) (*core.Token, error) {
 	m.ctrl.T.Helper()
@@ -545,6 +687,20 @@ func (mr *MockTokenRepositoryMockRecorder) FindByUser(arg0, arg1 any) *gomock.Ca
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "FindByUser", reflect.TypeOf((*MockTokenRepository)(nil).FindByUser), arg0, arg1)
 }
 
+// UpdateLastUsed mocks base method.
+func (m *MockTokenRepository) UpdateLastUsed(arg0 context.Context, arg1 *core.Token) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "UpdateLastUsed", arg0, arg1)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// UpdateLastUsed indicates an expected call of UpdateLastUsed.
+func (mr *MockTokenRepositoryMockRecorder) UpdateLastUsed(arg0, arg1 any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UpdateLastUsed", reflect.TypeOf((*MockTokenRepository)(nil).UpdateLastUsed), arg0, arg1)
+}
+
 // MockTokenService is a mock of TokenService interface.
 type MockTokenService struct {
 	ctrl     *gomock.Controller
@@ -569,7 +725,7 @@ func (m *MockTokenService) EXPECT() *MockTokenServiceMockRecorder {
 }
 
 // Create mocks base method.
-func (m *MockTokenService) Create(arg0 context.Context, arg1 int64, arg2 string) (string, error) {
+func (m *MockTokenService) Create(arg0 context.Context, arg1 int64, arg2 core.CreateToken) (string, error) {
 	m.ctrl.T.Helper()
 	ret := m.ctrl.Call(m, "Create", arg0, arg1, arg2)
 	ret0, _ := ret[0].(string)
@@ -584,32 +740,47 @@ func (mr *MockTokenServiceMockRecorder) Create(arg0, arg1, arg2 any) *gomock.Cal
 }
 
 // Revoke mocks base method.
-func (m *MockTokenService) Revoke(arg0 context.Context, arg1 int64) error {
+func (m *MockTokenService) Revoke(arg0 context.Context, arg1, arg2 int64) error {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "Revoke", arg0, arg1)
+	ret := m.ctrl.Call(m, "Revoke", arg0, arg1, arg2)
 	ret0, _ := ret[0].(error)
 	return ret0
 }
 
 // Revoke indicates an expected call of Revoke.
-func (mr *MockTokenServiceMockRecorder) Revoke(arg0, arg1 any) *gomock.Call {
+func (mr *MockTokenServiceMockRecorder) Revoke(arg0, arg1, arg2 any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Revoke", reflect.TypeOf((*MockTokenService)(nil).Revoke), arg0, arg1, arg2)
+}
+
+// RevokeAll mocks base method.
+func (m *MockTokenService) RevokeAll(arg0 context.Context, arg1 int64) (int64, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "RevokeAll", arg0, arg1)
+	ret0, _ := ret[0].(int64)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// RevokeAll indicates an expected call of RevokeAll.
+func (mr *MockTokenServiceMockRecorder) RevokeAll(arg0, arg1 any) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Revoke", reflect.TypeOf((*MockTokenService)(nil).Revoke), arg0, arg1)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "RevokeAll", reflect.TypeOf((*MockTokenService)(nil).RevokeAll), arg0, arg1)
 }
 
 // Validate mocks base method.
-func (m *MockTokenService) Validate(arg0 context.Context, arg1 string) (*core.AuthContext, error) {
+func (m *MockTokenService) Validate(arg0 context.Context, arg1, arg2 string) (*core.AuthContext, error) {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "Validate", arg0, arg1)
+	ret := m.ctrl.Call(m, "Validate", arg0, arg1, arg2)
 	ret0, _ := ret[0].(*core.AuthContext)
 	ret1, _ := ret[1].(error)
 	return ret0, ret1
 }
 
 // Validate indicates an expected call of Validate.
-func (mr *MockTokenServiceMockRecorder) Validate(arg0, arg1 any) *gomock.Call {
+func (mr *MockTokenServiceMockRecorder) Validate(arg0, arg1, arg2 any) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Validate", reflect.TypeOf((*MockTokenService)(nil).Validate), arg0, arg1)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Validate", reflect.TypeOf((*MockTokenService)(nil).Validate), arg0, arg1, arg2)
 }
 
 // MockSessionManager is a mock of SessionManager interface.
@@ -650,6 +821,21 @@ func (mr *MockSessionManagerMockRecorder) Create(arg0, arg1, arg2, arg3 any) *go
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Create", reflect.TypeOf((*MockSessionManager)(nil).Create), arg0, arg1, arg2, arg3)
 }
 
+// CreateDeviceSession mocks base method.
+func (m *MockSessionManager) CreateDeviceSession(arg0 context.Context, arg1 int64, arg2, arg3, arg4 string) (string, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CreateDeviceSession", arg0, arg1, arg2, arg3, arg4)
+	ret0, _ := ret[0].(string)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// CreateDeviceSession indicates an expected call of CreateDeviceSession.
+func (mr *MockSessionManagerMockRecorder) CreateDeviceSession(arg0, arg1, arg2, arg3, arg4 any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CreateDeviceSession", reflect.TypeOf((*MockSessionManager)(nil).CreateDeviceSession), arg0, arg1, arg2, arg3, arg4)
+}
+
 // Revoke mocks base method.
 func (m *MockSessionManager) Revoke(arg0 context.Context, arg1, arg2 int64) error {
 	m.ctrl.T.Helper()
@@ -664,6 +850,21 @@ func (mr *MockSessionManagerMockRecorder) Revoke(arg0, arg1, arg2 any) *gomock.C
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Revoke", reflect.TypeOf((*MockSessionManager)(nil).Revoke), arg0, arg1, arg2)
 }
 
+// RevokeAll mocks base method.
+func (m *MockSessionManager) RevokeAll(arg0 context.Context, arg1 int64, arg2 *int64) (int64, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "RevokeAll", arg0, arg1, arg2)
+	ret0, _ := ret[0].(int64)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// RevokeAll indicates an expected call of RevokeAll.
+func (mr *MockSessionManagerMockRecorder) RevokeAll(arg0, arg1, arg2 any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "RevokeAll", reflect.TypeOf((*MockSessionManager)(nil).RevokeAll), arg0, arg1, arg2)
+}
+
 // Validate mocks base method.
 func (m *MockSessionManager) Validate(arg0 context.Context, arg1 string) (*core.AuthContext, *string, error) {
 	m.ctrl.T.Helper()
@@ -732,3 +933,1723 @@ func (mr *MockChannelServiceMockRecorder) GetAll(arg0, arg1 any) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetAll", reflect.TypeOf((*MockChannelService)(nil).GetAll), arg0, arg1)
 }
+
+// MockStreamSessionRegistry is a mock of StreamSessionRegistry interface.
+type MockStreamSessionRegistry struct {
+	ctrl     *gomock.Controller
+	recorder *MockStreamSessionRegistryMockRecorder
+}
+
+// MockStreamSessionRegistryMockRecorder is the mock recorder for MockStreamSessionRegistry.
+type MockStreamSessionRegistryMockRecorder struct {
+	mock *MockStreamSessionRegistry
+}
+
+// NewMockStreamSessionRegistry creates a new mock instance.
+func NewMockStreamSessionRegistry(ctrl *gomock.Controller) *MockStreamSessionRegistry {
+	mock := &MockStreamSessionRegistry{ctrl: ctrl}
+	mock.recorder = &MockStreamSessionRegistryMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockStreamSessionRegistry) EXPECT() *MockStreamSessionRegistryMockRecorder {
+	return m.recorder
+}
+
+// AddBytes mocks base method.
+func (m *MockStreamSessionRegistry) AddBytes(arg0 string, arg1 int64) {
+	m.ctrl.T.Helper()
+	m.ctrl.Call(m, "AddBytes", arg0, arg1)
+}
+
+// AddBytes indicates an expected call of AddBytes.
+func (mr *MockStreamSessionRegistryMockRecorder) AddBytes(arg0, arg1 any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "AddBytes", reflect.TypeOf((*MockStreamSessionRegistry)(nil).AddBytes), arg0, arg1)
+}
+
+// List mocks base method.
+func (m *MockStreamSessionRegistry) List() []*core.StreamSession {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "List")
+	ret0, _ := ret[0].([]*core.StreamSession)
+	return ret0
+}
+
+// List indicates an expected call of List.
+func (mr *MockStreamSessionRegistryMockRecorder) List() *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "List", reflect.TypeOf((*MockStreamSessionRegistry)(nil).List))
+}
+
+// Reserve mocks base method.
+func (m *MockStreamSessionRegistry) Reserve(arg0 context.Context, arg1 core.StreamSession, arg2 func([]*core.StreamSession) error) (string, context.Context, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Reserve", arg0, arg1, arg2)
+	ret0, _ := ret[0].(string)
+	ret1, _ := ret[1].(context.Context)
+	ret2, _ := ret[2].(error)
+	return ret0, ret1, ret2
+}
+
+// Reserve indicates an expected call of Reserve.
+func (mr *MockStreamSessionRegistryMockRecorder) Reserve(arg0, arg1, arg2 any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Reserve", reflect.TypeOf((*MockStreamSessionRegistry)(nil).Reserve), arg0, arg1, arg2)
+}
+
+// Start mocks base method.
+func (m *MockStreamSessionRegistry) Start(arg0 context.Context, arg1 core.StreamSession) (string, context.Context) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Start", arg0, arg1)
+	ret0, _ := ret[0].(string)
+	ret1, _ := ret[1].(context.Context)
+	return ret0, ret1
+}
+
+// Start indicates an expected call of Start.
+func (mr *MockStreamSessionRegistryMockRecorder) Start(arg0, arg1 any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Start", reflect.TypeOf((*MockStreamSessionRegistry)(nil).Start), arg0, arg1)
+}
+
+// Stop mocks base method.
+func (m *MockStreamSessionRegistry) Stop(arg0 string) {
+	m.ctrl.T.Helper()
+	m.ctrl.Call(m, "Stop", arg0)
+}
+
+// Stop indicates an expected call of Stop.
+func (mr *MockStreamSessionRegistryMockRecorder) Stop(arg0 any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Stop", reflect.TypeOf((*MockStreamSessionRegistry)(nil).Stop), arg0)
+}
+
+// Terminate mocks base method.
+func (m *MockStreamSessionRegistry) Terminate(arg0 string) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Terminate", arg0)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// Terminate indicates an expected call of Terminate.
+func (mr *MockStreamSessionRegistryMockRecorder) Terminate(arg0 any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Terminate", reflect.TypeOf((*MockStreamSessionRegistry)(nil).Terminate), arg0)
+}
+
+// MockStreamLimiter is a mock of StreamLimiter interface.
+type MockStreamLimiter struct {
+	ctrl     *gomock.Controller
+	recorder *MockStreamLimiterMockRecorder
+}
+
+// MockStreamLimiterMockRecorder is the mock recorder for MockStreamLimiter.
+type MockStreamLimiterMockRecorder struct {
+	mock *MockStreamLimiter
+}
+
+// NewMockStreamLimiter creates a new mock instance.
+func NewMockStreamLimiter(ctrl *gomock.Controller) *MockStreamLimiter {
+	mock := &MockStreamLimiter{ctrl: ctrl}
+	mock.recorder = &MockStreamLimiterMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockStreamLimiter) EXPECT() *MockStreamLimiterMockRecorder {
+	return m.recorder
+}
+
+// StartChannelStream mocks base method.
+func (m *MockStreamLimiter) StartChannelStream(arg0 context.Context, arg1 core.StreamSession) (string, context.Context, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "StartChannelStream", arg0, arg1)
+	ret0, _ := ret[0].(string)
+	ret1, _ := ret[1].(context.Context)
+	ret2, _ := ret[2].(error)
+	return ret0, ret1, ret2
+}
+
+// StartChannelStream indicates an expected call of StartChannelStream.
+func (mr *MockStreamLimiterMockRecorder) StartChannelStream(arg0, arg1 any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "StartChannelStream", reflect.TypeOf((*MockStreamLimiter)(nil).StartChannelStream), arg0, arg1)
+}
+
+// MockStreamSigner is a mock of StreamSigner interface.
+type MockStreamSigner struct {
+	ctrl     *gomock.Controller
+	recorder *MockStreamSignerMockRecorder
+}
+
+// MockStreamSignerMockRecorder is the mock recorder for MockStreamSigner.
+type MockStreamSignerMockRecorder struct {
+	mock *MockStreamSigner
+}
+
+// NewMockStreamSigner creates a new mock instance.
+func NewMockStreamSigner(ctrl *gomock.Controller) *MockStreamSigner {
+	mock := &MockStreamSigner{ctrl: ctrl}
+	mock.recorder = &MockStreamSignerMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockStreamSigner) EXPECT() *MockStreamSignerMockRecorder {
+	return m.recorder
+}
+
+// Sign mocks base method.
+func (m *MockStreamSigner) Sign(arg0 int64, arg1 []core.Permission, arg2, arg3 string) core.StreamSignature {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Sign", arg0, arg1, arg2, arg3)
+	ret0, _ := ret[0].(core.StreamSignature)
+	return ret0
+}
+
+// Sign indicates an expected call of Sign.
+func (mr *MockStreamSignerMockRecorder) Sign(arg0, arg1, arg2, arg3 any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Sign", reflect.TypeOf((*MockStreamSigner)(nil).Sign), arg0, arg1, arg2, arg3)
+}
+
+// Verify mocks base method.
+func (m *MockStreamSigner) Verify(arg0 core.StreamSignature) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Verify", arg0)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// Verify indicates an expected call of Verify.
+func (mr *MockStreamSignerMockRecorder) Verify(arg0 any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Verify", reflect.TypeOf((*MockStreamSigner)(nil).Verify), arg0)
+}
+
+// MockStreamingService is a mock of StreamingService interface.
+type MockStreamingService struct {
+	ctrl     *gomock.Controller
+	recorder *MockStreamingServiceMockRecorder
+}
+
+// MockStreamingServiceMockRecorder is the mock recorder for MockStreamingService.
+type MockStreamingServiceMockRecorder struct {
+	mock *MockStreamingService
+}
+
+// NewMockStreamingService creates a new mock instance.
+func NewMockStreamingService(ctrl *gomock.Controller) *MockStreamingService {
+	mock := &MockStreamingService{ctrl: ctrl}
+	mock.recorder = &MockStreamingServiceMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockStreamingService) EXPECT() *MockStreamingServiceMockRecorder {
+	return m.recorder
+}
+
+// GetChannelStream mocks base method.
+func (m *MockStreamingService) GetChannelStream(arg0 context.Context, arg1 int64, arg2 string) (*http.Response, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetChannelStream", arg0, arg1, arg2)
+	ret0, _ := ret[0].(*http.Response)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetChannelStream indicates an expected call of GetChannelStream.
+func (mr *MockStreamingServiceMockRecorder) GetChannelStream(arg0, arg1, arg2 any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetChannelStream", reflect.TypeOf((*MockStreamingService)(nil).GetChannelStream), arg0, arg1, arg2)
+}
+
+// GetRecordingStream mocks base method.
+func (m *MockStreamingService) GetRecordingStream(arg0 context.Context, arg1 string) (*http.Response, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetRecordingStream", arg0, arg1)
+	ret0, _ := ret[0].(*http.Response)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetRecordingStream indicates an expected call of GetRecordingStream.
+func (mr *MockStreamingServiceMockRecorder) GetRecordingStream(arg0, arg1 any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetRecordingStream", reflect.TypeOf((*MockStreamingService)(nil).GetRecordingStream), arg0, arg1)
+}
+
+// IsChannelStreamRunning mocks base method.
+func (m *MockStreamingService) IsChannelStreamRunning(arg0 int64, arg1 string) bool {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "IsChannelStreamRunning", arg0, arg1)
+	ret0, _ := ret[0].(bool)
+	return ret0
+}
+
+// IsChannelStreamRunning indicates an expected call of IsChannelStreamRunning.
+func (mr *MockStreamingServiceMockRecorder) IsChannelStreamRunning(arg0, arg1 any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "IsChannelStreamRunning", reflect.TypeOf((*MockStreamingService)(nil).IsChannelStreamRunning), arg0, arg1)
+}
+
+// MockHLSService is a mock of HLSService interface.
+type MockHLSService struct {
+	ctrl     *gomock.Controller
+	recorder *MockHLSServiceMockRecorder
+}
+
+// MockHLSServiceMockRecorder is the mock recorder for MockHLSService.
+type MockHLSServiceMockRecorder struct {
+	mock *MockHLSService
+}
+
+// NewMockHLSService creates a new mock instance.
+func NewMockHLSService(ctrl *gomock.Controller) *MockHLSService {
+	mock := &MockHLSService{ctrl: ctrl}
+	mock.recorder = &MockHLSServiceMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockHLSService) EXPECT() *MockHLSServiceMockRecorder {
+	return m.recorder
+}
+
+// GetPlaylist mocks base method.
+func (m *MockHLSService) GetPlaylist(arg0 context.Context, arg1 core.StreamSession, arg2 string, arg3 func() error) ([]byte, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetPlaylist", arg0, arg1, arg2, arg3)
+	ret0, _ := ret[0].([]byte)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetPlaylist indicates an expected call of GetPlaylist.
+func (mr *MockHLSServiceMockRecorder) GetPlaylist(arg0, arg1, arg2, arg3 any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetPlaylist", reflect.TypeOf((*MockHLSService)(nil).GetPlaylist), arg0, arg1, arg2, arg3)
+}
+
+// GetSegment mocks base method.
+func (m *MockHLSService) GetSegment(arg0 context.Context, arg1 core.StreamSession, arg2 int64) ([]byte, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetSegment", arg0, arg1, arg2)
+	ret0, _ := ret[0].([]byte)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetSegment indicates an expected call of GetSegment.
+func (mr *MockHLSServiceMockRecorder) GetSegment(arg0, arg1, arg2 any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetSegment", reflect.TypeOf((*MockHLSService)(nil).GetSegment), arg0, arg1, arg2)
+}
+
+// MockOIDCAuthenticator is a mock of OIDCAuthenticator interface.
+type MockOIDCAuthenticator struct {
+	ctrl     *gomock.Controller
+	recorder *MockOIDCAuthenticatorMockRecorder
+}
+
+// MockOIDCAuthenticatorMockRecorder is the mock recorder for MockOIDCAuthenticator.
+type MockOIDCAuthenticatorMockRecorder struct {
+	mock *MockOIDCAuthenticator
+}
+
+// NewMockOIDCAuthenticator creates a new mock instance.
+func NewMockOIDCAuthenticator(ctrl *gomock.Controller) *MockOIDCAuthenticator {
+	mock := &MockOIDCAuthenticator{ctrl: ctrl}
+	mock.recorder = &MockOIDCAuthenticatorMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockOIDCAuthenticator) EXPECT() *MockOIDCAuthenticatorMockRecorder {
+	return m.recorder
+}
+
+// AuthCodeURL mocks base method.
+func (m *MockOIDCAuthenticator) AuthCodeURL(arg0 context.Context) (*core.OIDCAuthRequest, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "AuthCodeURL", arg0)
+	ret0, _ := ret[0].(*core.OIDCAuthRequest)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// AuthCodeURL indicates an expected call of AuthCodeURL.
+func (mr *MockOIDCAuthenticatorMockRecorder) AuthCodeURL(arg0 any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "AuthCodeURL", reflect.TypeOf((*MockOIDCAuthenticator)(nil).AuthCodeURL), arg0)
+}
+
+// Exchange mocks base method.
+func (m *MockOIDCAuthenticator) Exchange(arg0 context.Context, arg1 string, arg2 core.OIDCAuthRequest) (*core.User, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Exchange", arg0, arg1, arg2)
+	ret0, _ := ret[0].(*core.User)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Exchange indicates an expected call of Exchange.
+func (mr *MockOIDCAuthenticatorMockRecorder) Exchange(arg0, arg1, arg2 any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Exchange", reflect.TypeOf((*MockOIDCAuthenticator)(nil).Exchange), arg0, arg1, arg2)
+}
+
+// MockPasswordAuthenticator is a mock of PasswordAuthenticator interface.
+type MockPasswordAuthenticator struct {
+	ctrl     *gomock.Controller
+	recorder *MockPasswordAuthenticatorMockRecorder
+}
+
+// MockPasswordAuthenticatorMockRecorder is the mock recorder for MockPasswordAuthenticator.
+type MockPasswordAuthenticatorMockRecorder struct {
+	mock *MockPasswordAuthenticator
+}
+
+// NewMockPasswordAuthenticator creates a new mock instance.
+func NewMockPasswordAuthenticator(ctrl *gomock.Controller) *MockPasswordAuthenticator {
+	mock := &MockPasswordAuthenticator{ctrl: ctrl}
+	mock.recorder = &MockPasswordAuthenticatorMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockPasswordAuthenticator) EXPECT() *MockPasswordAuthenticatorMockRecorder {
+	return m.recorder
+}
+
+// ConfirmPassword mocks base method.
+func (m *MockPasswordAuthenticator) ConfirmPassword(arg0 context.Context, arg1 int64, arg2 string) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ConfirmPassword", arg0, arg1, arg2)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// ConfirmPassword indicates an expected call of ConfirmPassword.
+func (mr *MockPasswordAuthenticatorMockRecorder) ConfirmPassword(arg0, arg1, arg2 any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ConfirmPassword", reflect.TypeOf((*MockPasswordAuthenticator)(nil).ConfirmPassword), arg0, arg1, arg2)
+}
+
+// Login mocks base method.
+func (m *MockPasswordAuthenticator) Login(arg0 context.Context, arg1, arg2 string, arg3 *string) (*core.User, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Login", arg0, arg1, arg2, arg3)
+	ret0, _ := ret[0].(*core.User)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Login indicates an expected call of Login.
+func (mr *MockPasswordAuthenticatorMockRecorder) Login(arg0, arg1, arg2, arg3 any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Login", reflect.TypeOf((*MockPasswordAuthenticator)(nil).Login), arg0, arg1, arg2, arg3)
+}
+
+// MockWebAuthnCredentialRepository is a mock of WebAuthnCredentialRepository interface.
+type MockWebAuthnCredentialRepository struct {
+	ctrl     *gomock.Controller
+	recorder *MockWebAuthnCredentialRepositoryMockRecorder
+}
+
+// MockWebAuthnCredentialRepositoryMockRecorder is the mock recorder for MockWebAuthnCredentialRepository.
+type MockWebAuthnCredentialRepositoryMockRecorder struct {
+	mock *MockWebAuthnCredentialRepository
+}
+
+// NewMockWebAuthnCredentialRepository creates a new mock instance.
+func NewMockWebAuthnCredentialRepository(ctrl *gomock.Controller) *MockWebAuthnCredentialRepository {
+	mock := &MockWebAuthnCredentialRepository{ctrl: ctrl}
+	mock.recorder = &MockWebAuthnCredentialRepositoryMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockWebAuthnCredentialRepository) EXPECT() *MockWebAuthnCredentialRepositoryMockRecorder {
+	return m.recorder
+}
+
+// Create mocks base method.
+func (m *MockWebAuthnCredentialRepository) Create(arg0 context.Context, arg1 *core.WebAuthnCredential) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Create", arg0, arg1)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// Create indicates an expected call of Create.
+func (mr *MockWebAuthnCredentialRepositoryMockRecorder) Create(arg0, arg1 any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Create", reflect.TypeOf((*MockWebAuthnCredentialRepository)(nil).Create), arg0, arg1)
+}
+
+// Delete mocks base method.
+func (m *MockWebAuthnCredentialRepository) Delete(arg0 context.Context, arg1 *core.WebAuthnCredential) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Delete", arg0, arg1)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// Delete indicates an expected call of Delete.
+func (mr *MockWebAuthnCredentialRepositoryMockRecorder) Delete(arg0, arg1 any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Delete", reflect.TypeOf((*MockWebAuthnCredentialRepository)(nil).Delete), arg0, arg1)
+}
+
+// FindByCredentialID mocks base method.
+func (m *MockWebAuthnCredentialRepository) FindByCredentialID(arg0 context.Context, arg1 string) (*core.WebAuthnCredential, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "FindByCredentialID", arg0, arg1)
+	ret0, _ := ret[0].(*core.WebAuthnCredential)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// FindByCredentialID indicates an expected call of FindByCredentialID.
+func (mr *MockWebAuthnCredentialRepositoryMockRecorder) FindByCredentialID(arg0, arg1 any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "FindByCredentialID", reflect.TypeOf((*MockWebAuthnCredentialRepository)(nil).FindByCredentialID), arg0, arg1)
+}
+
+// FindById mocks base method.
+func (m *MockWebAuthnCredentialRepository) FindById(arg0 context.Context, arg1 int64) (*core.WebAuthnCredential, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "FindById", arg0, arg1)
+	ret0, _ := ret[0].(*core.WebAuthnCredential)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// FindById indicates an expected call of FindById.
+func (mr *MockWebAuthnCredentialRepositoryMockRecorder) FindById(arg0, arg1 any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "FindById", reflect.TypeOf((*MockWebAuthnCredentialRepository)(nil).FindById), arg0, arg1)
+}
+
+// FindByUser mocks base method.
+func (m *MockWebAuthnCredentialRepository) FindByUser(arg0 context.Context, arg1 int64) ([]*core.WebAuthnCredential, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "FindByUser", arg0, arg1)
+	ret0, _ := ret[0].([]*core.WebAuthnCredential)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// FindByUser indicates an expected call of FindByUser.
+func (mr *MockWebAuthnCredentialRepositoryMockRecorder) FindByUser(arg0, arg1 any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "FindByUser", reflect.TypeOf((*MockWebAuthnCredentialRepository)(nil).FindByUser), arg0, arg1)
+}
+
+// Update mocks base method.
+func (m *MockWebAuthnCredentialRepository) Update(arg0 context.Context, arg1 *core.WebAuthnCredential) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Update", arg0, arg1)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// Update indicates an expected call of Update.
+func (mr *MockWebAuthnCredentialRepositoryMockRecorder) Update(arg0, arg1 any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Update", reflect.TypeOf((*MockWebAuthnCredentialRepository)(nil).Update), arg0, arg1)
+}
+
+// MockWebAuthnService is a mock of WebAuthnService interface.
+type MockWebAuthnService struct {
+	ctrl     *gomock.Controller
+	recorder *MockWebAuthnServiceMockRecorder
+}
+
+// MockWebAuthnServiceMockRecorder is the mock recorder for MockWebAuthnService.
+type MockWebAuthnServiceMockRecorder struct {
+	mock *MockWebAuthnService
+}
+
+// NewMockWebAuthnService creates a new mock instance.
+func NewMockWebAuthnService(ctrl *gomock.Controller) *MockWebAuthnService {
+	mock := &MockWebAuthnService{ctrl: ctrl}
+	mock.recorder = &MockWebAuthnServiceMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockWebAuthnService) EXPECT() *MockWebAuthnServiceMockRecorder {
+	return m.recorder
+}
+
+// BeginLogin mocks base method.
+func (m *MockWebAuthnService) BeginLogin(arg0 context.Context) (*core.WebAuthnCeremony, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "BeginLogin", arg0)
+	ret0, _ := ret[0].(*core.WebAuthnCeremony)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// BeginLogin indicates an expected call of BeginLogin.
+func (mr *MockWebAuthnServiceMockRecorder) BeginLogin(arg0 any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "BeginLogin", reflect.TypeOf((*MockWebAuthnService)(nil).BeginLogin), arg0)
+}
+
+// BeginRegistration mocks base method.
+func (m *MockWebAuthnService) BeginRegistration(arg0 context.Context, arg1 int64) (*core.WebAuthnCeremony, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "BeginRegistration", arg0, arg1)
+	ret0, _ := ret[0].(*core.WebAuthnCeremony)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// BeginRegistration indicates an expected call of BeginRegistration.
+func (mr *MockWebAuthnServiceMockRecorder) BeginRegistration(arg0, arg1 any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "BeginRegistration", reflect.TypeOf((*MockWebAuthnService)(nil).BeginRegistration), arg0, arg1)
+}
+
+// DeleteCredential mocks base method.
+func (m *MockWebAuthnService) DeleteCredential(arg0 context.Context, arg1, arg2 int64) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "DeleteCredential", arg0, arg1, arg2)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// DeleteCredential indicates an expected call of DeleteCredential.
+func (mr *MockWebAuthnServiceMockRecorder) DeleteCredential(arg0, arg1, arg2 any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DeleteCredential", reflect.TypeOf((*MockWebAuthnService)(nil).DeleteCredential), arg0, arg1, arg2)
+}
+
+// FinishLogin mocks base method.
+func (m *MockWebAuthnService) FinishLogin(arg0 context.Context, arg1 string, arg2 []byte) (*core.User, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "FinishLogin", arg0, arg1, arg2)
+	ret0, _ := ret[0].(*core.User)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// FinishLogin indicates an expected call of FinishLogin.
+func (mr *MockWebAuthnServiceMockRecorder) FinishLogin(arg0, arg1, arg2 any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "FinishLogin", reflect.TypeOf((*MockWebAuthnService)(nil).FinishLogin), arg0, arg1, arg2)
+}
+
+// FinishRegistration mocks base method.
+func (m *MockWebAuthnService) FinishRegistration(arg0 context.Context, arg1 int64, arg2, arg3 string, arg4 []byte) (*core.WebAuthnCredential, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "FinishRegistration", arg0, arg1, arg2, arg3, arg4)
+	ret0, _ := ret[0].(*core.WebAuthnCredential)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// FinishRegistration indicates an expected call of FinishRegistration.
+func (mr *MockWebAuthnServiceMockRecorder) FinishRegistration(arg0, arg1, arg2, arg3, arg4 any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "FinishRegistration", reflect.TypeOf((*MockWebAuthnService)(nil).FinishRegistration), arg0, arg1, arg2, arg3, arg4)
+}
+
+// GetCredentials mocks base method.
+func (m *MockWebAuthnService) GetCredentials(arg0 context.Context, arg1 int64) ([]*core.WebAuthnCredential, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetCredentials", arg0, arg1)
+	ret0, _ := ret[0].([]*core.WebAuthnCredential)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetCredentials indicates an expected call of GetCredentials.
+func (mr *MockWebAuthnServiceMockRecorder) GetCredentials(arg0, arg1 any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetCredentials", reflect.TypeOf((*MockWebAuthnService)(nil).GetCredentials), arg0, arg1)
+}
+
+// RenameCredential mocks base method.
+func (m *MockWebAuthnService) RenameCredential(arg0 context.Context, arg1, arg2 int64, arg3 string) (*core.WebAuthnCredential, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "RenameCredential", arg0, arg1, arg2, arg3)
+	ret0, _ := ret[0].(*core.WebAuthnCredential)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// RenameCredential indicates an expected call of RenameCredential.
+func (mr *MockWebAuthnServiceMockRecorder) RenameCredential(arg0, arg1, arg2, arg3 any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "RenameCredential", reflect.TypeOf((*MockWebAuthnService)(nil).RenameCredential), arg0, arg1, arg2, arg3)
+}
+
+// MockRecordingOwnerRepository is a mock of RecordingOwnerRepository interface.
+type MockRecordingOwnerRepository struct {
+	ctrl     *gomock.Controller
+	recorder *MockRecordingOwnerRepositoryMockRecorder
+}
+
+// MockRecordingOwnerRepositoryMockRecorder is the mock recorder for MockRecordingOwnerRepository.
+type MockRecordingOwnerRepositoryMockRecorder struct {
+	mock *MockRecordingOwnerRepository
+}
+
+// NewMockRecordingOwnerRepository creates a new mock instance.
+func NewMockRecordingOwnerRepository(ctrl *gomock.Controller) *MockRecordingOwnerRepository {
+	mock := &MockRecordingOwnerRepository{ctrl: ctrl}
+	mock.recorder = &MockRecordingOwnerRepositoryMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockRecordingOwnerRepository) EXPECT() *MockRecordingOwnerRepositoryMockRecorder {
+	return m.recorder
+}
+
+// Create mocks base method.
+func (m *MockRecordingOwnerRepository) Create(arg0 context.Context, arg1 string, arg2 int64) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Create", arg0, arg1, arg2)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// Create indicates an expected call of Create.
+func (mr *MockRecordingOwnerRepositoryMockRecorder) Create(arg0, arg1, arg2 any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Create", reflect.TypeOf((*MockRecordingOwnerRepository)(nil).Create), arg0, arg1, arg2)
+}
+
+// Delete mocks base method.
+func (m *MockRecordingOwnerRepository) Delete(arg0 context.Context, arg1 string) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Delete", arg0, arg1)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// Delete indicates an expected call of Delete.
+func (mr *MockRecordingOwnerRepositoryMockRecorder) Delete(arg0, arg1 any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Delete", reflect.TypeOf((*MockRecordingOwnerRepository)(nil).Delete), arg0, arg1)
+}
+
+// FindUserID mocks base method.
+func (m *MockRecordingOwnerRepository) FindUserID(arg0 context.Context, arg1 string) (*int64, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "FindUserID", arg0, arg1)
+	ret0, _ := ret[0].(*int64)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// FindUserID indicates an expected call of FindUserID.
+func (mr *MockRecordingOwnerRepositoryMockRecorder) FindUserID(arg0, arg1 any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "FindUserID", reflect.TypeOf((*MockRecordingOwnerRepository)(nil).FindUserID), arg0, arg1)
+}
+
+// MockLoginThrottleRepository is a mock of LoginThrottleRepository interface.
+type MockLoginThrottleRepository struct {
+	ctrl     *gomock.Controller
+	recorder *MockLoginThrottleRepositoryMockRecorder
+}
+
+// MockLoginThrottleRepositoryMockRecorder is the mock recorder for MockLoginThrottleRepository.
+type MockLoginThrottleRepositoryMockRecorder struct {
+	mock *MockLoginThrottleRepository
+}
+
+// NewMockLoginThrottleRepository creates a new mock instance.
+func NewMockLoginThrottleRepository(ctrl *gomock.Controller) *MockLoginThrottleRepository {
+	mock := &MockLoginThrottleRepository{ctrl: ctrl}
+	mock.recorder = &MockLoginThrottleRepositoryMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockLoginThrottleRepository) EXPECT() *MockLoginThrottleRepositoryMockRecorder {
+	return m.recorder
+}
+
+// Block mocks base method.
+func (m *MockLoginThrottleRepository) Block(arg0 context.Context, arg1, arg2 int64) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Block", arg0, arg1, arg2)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// Block indicates an expected call of Block.
+func (mr *MockLoginThrottleRepositoryMockRecorder) Block(arg0, arg1, arg2 any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Block", reflect.TypeOf((*MockLoginThrottleRepository)(nil).Block), arg0, arg1, arg2)
+}
+
+// Delete mocks base method.
+func (m *MockLoginThrottleRepository) Delete(arg0 context.Context, arg1 core.LoginThrottleKind, arg2 string) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Delete", arg0, arg1, arg2)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// Delete indicates an expected call of Delete.
+func (mr *MockLoginThrottleRepositoryMockRecorder) Delete(arg0, arg1, arg2 any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Delete", reflect.TypeOf((*MockLoginThrottleRepository)(nil).Delete), arg0, arg1, arg2)
+}
+
+// DeleteByID mocks base method.
+func (m *MockLoginThrottleRepository) DeleteByID(arg0 context.Context, arg1 int64) (bool, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "DeleteByID", arg0, arg1)
+	ret0, _ := ret[0].(bool)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// DeleteByID indicates an expected call of DeleteByID.
+func (mr *MockLoginThrottleRepositoryMockRecorder) DeleteByID(arg0, arg1 any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DeleteByID", reflect.TypeOf((*MockLoginThrottleRepository)(nil).DeleteByID), arg0, arg1)
+}
+
+// DeleteInactive mocks base method.
+func (m *MockLoginThrottleRepository) DeleteInactive(arg0 context.Context, arg1 int64) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "DeleteInactive", arg0, arg1)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// DeleteInactive indicates an expected call of DeleteInactive.
+func (mr *MockLoginThrottleRepositoryMockRecorder) DeleteInactive(arg0, arg1 any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DeleteInactive", reflect.TypeOf((*MockLoginThrottleRepository)(nil).DeleteInactive), arg0, arg1)
+}
+
+// Find mocks base method.
+func (m *MockLoginThrottleRepository) Find(arg0 context.Context, arg1 core.LoginThrottleKind, arg2 string) (*core.LoginThrottle, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Find", arg0, arg1, arg2)
+	ret0, _ := ret[0].(*core.LoginThrottle)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Find indicates an expected call of Find.
+func (mr *MockLoginThrottleRepositoryMockRecorder) Find(arg0, arg1, arg2 any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Find", reflect.TypeOf((*MockLoginThrottleRepository)(nil).Find), arg0, arg1, arg2)
+}
+
+// FindBlocked mocks base method.
+func (m *MockLoginThrottleRepository) FindBlocked(arg0 context.Context, arg1 int64) ([]*core.LoginThrottle, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "FindBlocked", arg0, arg1)
+	ret0, _ := ret[0].([]*core.LoginThrottle)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// FindBlocked indicates an expected call of FindBlocked.
+func (mr *MockLoginThrottleRepositoryMockRecorder) FindBlocked(arg0, arg1 any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "FindBlocked", reflect.TypeOf((*MockLoginThrottleRepository)(nil).FindBlocked), arg0, arg1)
+}
+
+// RecordFailure mocks base method.
+func (m *MockLoginThrottleRepository) RecordFailure(arg0 context.Context, arg1 core.LoginThrottleKind, arg2 string, arg3, arg4 int64) (*core.LoginThrottle, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "RecordFailure", arg0, arg1, arg2, arg3, arg4)
+	ret0, _ := ret[0].(*core.LoginThrottle)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// RecordFailure indicates an expected call of RecordFailure.
+func (mr *MockLoginThrottleRepositoryMockRecorder) RecordFailure(arg0, arg1, arg2, arg3, arg4 any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "RecordFailure", reflect.TypeOf((*MockLoginThrottleRepository)(nil).RecordFailure), arg0, arg1, arg2, arg3, arg4)
+}
+
+// MockLoginThrottleService is a mock of LoginThrottleService interface.
+type MockLoginThrottleService struct {
+	ctrl     *gomock.Controller
+	recorder *MockLoginThrottleServiceMockRecorder
+}
+
+// MockLoginThrottleServiceMockRecorder is the mock recorder for MockLoginThrottleService.
+type MockLoginThrottleServiceMockRecorder struct {
+	mock *MockLoginThrottleService
+}
+
+// NewMockLoginThrottleService creates a new mock instance.
+func NewMockLoginThrottleService(ctrl *gomock.Controller) *MockLoginThrottleService {
+	mock := &MockLoginThrottleService{ctrl: ctrl}
+	mock.recorder = &MockLoginThrottleServiceMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockLoginThrottleService) EXPECT() *MockLoginThrottleServiceMockRecorder {
+	return m.recorder
+}
+
+// Check mocks base method.
+func (m *MockLoginThrottleService) Check(arg0 context.Context, arg1, arg2 string) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Check", arg0, arg1, arg2)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// Check indicates an expected call of Check.
+func (mr *MockLoginThrottleServiceMockRecorder) Check(arg0, arg1, arg2 any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Check", reflect.TypeOf((*MockLoginThrottleService)(nil).Check), arg0, arg1, arg2)
+}
+
+// Clear mocks base method.
+func (m *MockLoginThrottleService) Clear(arg0 context.Context, arg1 int64) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Clear", arg0, arg1)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// Clear indicates an expected call of Clear.
+func (mr *MockLoginThrottleServiceMockRecorder) Clear(arg0, arg1 any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Clear", reflect.TypeOf((*MockLoginThrottleService)(nil).Clear), arg0, arg1)
+}
+
+// List mocks base method.
+func (m *MockLoginThrottleService) List(arg0 context.Context) ([]*core.LoginThrottle, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "List", arg0)
+	ret0, _ := ret[0].([]*core.LoginThrottle)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// List indicates an expected call of List.
+func (mr *MockLoginThrottleServiceMockRecorder) List(arg0 any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "List", reflect.TypeOf((*MockLoginThrottleService)(nil).List), arg0)
+}
+
+// RecordFailure mocks base method.
+func (m *MockLoginThrottleService) RecordFailure(arg0 context.Context, arg1, arg2 string) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "RecordFailure", arg0, arg1, arg2)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// RecordFailure indicates an expected call of RecordFailure.
+func (mr *MockLoginThrottleServiceMockRecorder) RecordFailure(arg0, arg1, arg2 any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "RecordFailure", reflect.TypeOf((*MockLoginThrottleService)(nil).RecordFailure), arg0, arg1, arg2)
+}
+
+// RecordSuccess mocks base method.
+func (m *MockLoginThrottleService) RecordSuccess(arg0 context.Context, arg1 string) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "RecordSuccess", arg0, arg1)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// RecordSuccess indicates an expected call of RecordSuccess.
+func (mr *MockLoginThrottleServiceMockRecorder) RecordSuccess(arg0, arg1 any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "RecordSuccess", reflect.TypeOf((*MockLoginThrottleService)(nil).RecordSuccess), arg0, arg1)
+}
+
+// MockAuditRepository is a mock of AuditRepository interface.
+type MockAuditRepository struct {
+	ctrl     *gomock.Controller
+	recorder *MockAuditRepositoryMockRecorder
+}
+
+// MockAuditRepositoryMockRecorder is the mock recorder for MockAuditRepository.
+type MockAuditRepositoryMockRecorder struct {
+	mock *MockAuditRepository
+}
+
+// NewMockAuditRepository creates a new mock instance.
+func NewMockAuditRepository(ctrl *gomock.Controller) *MockAuditRepository {
+	mock := &MockAuditRepository{ctrl: ctrl}
+	mock.recorder = &MockAuditRepositoryMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockAuditRepository) EXPECT() *MockAuditRepositoryMockRecorder {
+	return m.recorder
+}
+
+// Create mocks base method.
+func (m *MockAuditRepository) Create(arg0 context.Context, arg1 *core.AuditEntry) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Create", arg0, arg1)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// Create indicates an expected call of Create.
+func (mr *MockAuditRepositoryMockRecorder) Create(arg0, arg1 any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Create", reflect.TypeOf((*MockAuditRepository)(nil).Create), arg0, arg1)
+}
+
+// Find mocks base method.
+func (m *MockAuditRepository) Find(arg0 context.Context, arg1 core.AuditQueryParams) (*core.AuditListResult, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Find", arg0, arg1)
+	ret0, _ := ret[0].(*core.AuditListResult)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Find indicates an expected call of Find.
+func (mr *MockAuditRepositoryMockRecorder) Find(arg0, arg1 any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Find", reflect.TypeOf((*MockAuditRepository)(nil).Find), arg0, arg1)
+}
+
+// MockAuditService is a mock of AuditService interface.
+type MockAuditService struct {
+	ctrl     *gomock.Controller
+	recorder *MockAuditServiceMockRecorder
+}
+
+// MockAuditServiceMockRecorder is the mock recorder for MockAuditService.
+type MockAuditServiceMockRecorder struct {
+	mock *MockAuditService
+}
+
+// NewMockAuditService creates a new mock instance.
+func NewMockAuditService(ctrl *gomock.Controller) *MockAuditService {
+	mock := &MockAuditService{ctrl: ctrl}
+	mock.recorder = &MockAuditServiceMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockAuditService) EXPECT() *MockAuditServiceMockRecorder {
+	return m.recorder
+}
+
+// Find mocks base method.
+func (m *MockAuditService) Find(arg0 context.Context, arg1 core.AuditQueryParams) (*core.AuditListResult, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Find", arg0, arg1)
+	ret0, _ := ret[0].(*core.AuditListResult)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Find indicates an expected call of Find.
+func (mr *MockAuditServiceMockRecorder) Find(arg0, arg1 any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Find", reflect.TypeOf((*MockAuditService)(nil).Find), arg0, arg1)
+}
+
+// Record mocks base method.
+func (m *MockAuditService) Record(arg0 context.Context, arg1 *core.AuditEntry) {
+	m.ctrl.T.Helper()
+	m.ctrl.Call(m, "Record", arg0, arg1)
+}
+
+// Record indicates an expected call of Record.
+func (mr *MockAuditServiceMockRecorder) Record(arg0, arg1 any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Record", reflect.TypeOf((*MockAuditService)(nil).Record), arg0, arg1)
+}
+
+// MockDeviceAuthRepository is a mock of DeviceAuthRepository interface.
+type MockDeviceAuthRepository struct {
+	ctrl     *gomock.Controller
+	recorder *MockDeviceAuthRepositoryMockRecorder
+}
+
+// MockDeviceAuthRepositoryMockRecorder is the mock recorder for MockDeviceAuthRepository.
+type MockDeviceAuthRepositoryMockRecorder struct {
+	mock *MockDeviceAuthRepository
+}
+
+// NewMockDeviceAuthRepository creates a new mock instance.
+func NewMockDeviceAuthRepository(ctrl *gomock.Controller) *MockDeviceAuthRepository {
+	mock := &MockDeviceAuthRepository{ctrl: ctrl}
+	mock.recorder = &MockDeviceAuthRepositoryMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockDeviceAuthRepository) EXPECT() *MockDeviceAuthRepositoryMockRecorder {
+	return m.recorder
+}
+
+// CountPending mocks base method.
+func (m *MockDeviceAuthRepository) CountPending(arg0 context.Context) (int64, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CountPending", arg0)
+	ret0, _ := ret[0].(int64)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// CountPending indicates an expected call of CountPending.
+func (mr *MockDeviceAuthRepositoryMockRecorder) CountPending(arg0 any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CountPending", reflect.TypeOf((*MockDeviceAuthRepository)(nil).CountPending), arg0)
+}
+
+// CountPendingByClientIP mocks base method.
+func (m *MockDeviceAuthRepository) CountPendingByClientIP(arg0 context.Context, arg1 string) (int64, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CountPendingByClientIP", arg0, arg1)
+	ret0, _ := ret[0].(int64)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// CountPendingByClientIP indicates an expected call of CountPendingByClientIP.
+func (mr *MockDeviceAuthRepositoryMockRecorder) CountPendingByClientIP(arg0, arg1 any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CountPendingByClientIP", reflect.TypeOf((*MockDeviceAuthRepository)(nil).CountPendingByClientIP), arg0, arg1)
+}
+
+// Create mocks base method.
+func (m *MockDeviceAuthRepository) Create(arg0 context.Context, arg1 *core.DeviceAuthorization) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Create", arg0, arg1)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// Create indicates an expected call of Create.
+func (mr *MockDeviceAuthRepositoryMockRecorder) Create(arg0, arg1 any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Create", reflect.TypeOf((*MockDeviceAuthRepository)(nil).Create), arg0, arg1)
+}
+
+// Delete mocks base method.
+func (m *MockDeviceAuthRepository) Delete(arg0 context.Context, arg1 int64) (bool, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Delete", arg0, arg1)
+	ret0, _ := ret[0].(bool)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Delete indicates an expected call of Delete.
+func (mr *MockDeviceAuthRepositoryMockRecorder) Delete(arg0, arg1 any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Delete", reflect.TypeOf((*MockDeviceAuthRepository)(nil).Delete), arg0, arg1)
+}
+
+// DeleteExpired mocks base method.
+func (m *MockDeviceAuthRepository) DeleteExpired(arg0 context.Context, arg1 int64) (int64, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "DeleteExpired", arg0, arg1)
+	ret0, _ := ret[0].(int64)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// DeleteExpired indicates an expected call of DeleteExpired.
+func (mr *MockDeviceAuthRepositoryMockRecorder) DeleteExpired(arg0, arg1 any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DeleteExpired", reflect.TypeOf((*MockDeviceAuthRepository)(nil).DeleteExpired), arg0, arg1)
+}
+
+// FindByDeviceCode mocks base method.
+func (m *MockDeviceAuthRepository) FindByDeviceCode(arg0 context.Context, arg1 string) (*core.DeviceAuthorization, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "FindByDeviceCode", arg0, arg1)
+	ret0, _ := ret[0].(*core.DeviceAuthorization)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// FindByDeviceCode indicates an expected call of FindByDeviceCode.
+func (mr *MockDeviceAuthRepositoryMockRecorder) FindByDeviceCode(arg0, arg1 any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "FindByDeviceCode", reflect.TypeOf((*MockDeviceAuthRepository)(nil).FindByDeviceCode), arg0, arg1)
+}
+
+// FindByUserCode mocks base method.
+func (m *MockDeviceAuthRepository) FindByUserCode(arg0 context.Context, arg1 string) (*core.DeviceAuthorization, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "FindByUserCode", arg0, arg1)
+	ret0, _ := ret[0].(*core.DeviceAuthorization)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// FindByUserCode indicates an expected call of FindByUserCode.
+func (mr *MockDeviceAuthRepositoryMockRecorder) FindByUserCode(arg0, arg1 any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "FindByUserCode", reflect.TypeOf((*MockDeviceAuthRepository)(nil).FindByUserCode), arg0, arg1)
+}
+
+// Update mocks base method.
+func (m *MockDeviceAuthRepository) Update(arg0 context.Context, arg1 *core.DeviceAuthorization) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Update", arg0, arg1)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// Update indicates an expected call of Update.
+func (mr *MockDeviceAuthRepositoryMockRecorder) Update(arg0, arg1 any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Update", reflect.TypeOf((*MockDeviceAuthRepository)(nil).Update), arg0, arg1)
+}
+
+// MockDeviceAuthService is a mock of DeviceAuthService interface.
+type MockDeviceAuthService struct {
+	ctrl     *gomock.Controller
+	recorder *MockDeviceAuthServiceMockRecorder
+}
+
+// MockDeviceAuthServiceMockRecorder is the mock recorder for MockDeviceAuthService.
+type MockDeviceAuthServiceMockRecorder struct {
+	mock *MockDeviceAuthService
+}
+
+// NewMockDeviceAuthService creates a new mock instance.
+func NewMockDeviceAuthService(ctrl *gomock.Controller) *MockDeviceAuthService {
+	mock := &MockDeviceAuthService{ctrl: ctrl}
+	mock.recorder = &MockDeviceAuthServiceMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockDeviceAuthService) EXPECT() *MockDeviceAuthServiceMockRecorder {
+	return m.recorder
+}
+
+// Approve mocks base method.
+func (m *MockDeviceAuthService) Approve(arg0 context.Context, arg1 string, arg2 int64, arg3 []core.Permission) (*core.DeviceAuthorization, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Approve", arg0, arg1, arg2, arg3)
+	ret0, _ := ret[0].(*core.DeviceAuthorization)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Approve indicates an expected call of Approve.
+func (mr *MockDeviceAuthServiceMockRecorder) Approve(arg0, arg1, arg2, arg3 any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Approve", reflect.TypeOf((*MockDeviceAuthService)(nil).Approve), arg0, arg1, arg2, arg3)
+}
+
+// Deny mocks base method.
+func (m *MockDeviceAuthService) Deny(arg0 context.Context, arg1 string, arg2 int64) (*core.DeviceAuthorization, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Deny", arg0, arg1, arg2)
+	ret0, _ := ret[0].(*core.DeviceAuthorization)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Deny indicates an expected call of Deny.
+func (mr *MockDeviceAuthServiceMockRecorder) Deny(arg0, arg1, arg2 any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Deny", reflect.TypeOf((*MockDeviceAuthService)(nil).Deny), arg0, arg1, arg2)
+}
+
+// Find mocks base method.
+func (m *MockDeviceAuthService) Find(arg0 context.Context, arg1 string) (*core.DeviceAuthorization, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Find", arg0, arg1)
+	ret0, _ := ret[0].(*core.DeviceAuthorization)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Find indicates an expected call of Find.
+func (mr *MockDeviceAuthServiceMockRecorder) Find(arg0, arg1 any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Find", reflect.TypeOf((*MockDeviceAuthService)(nil).Find), arg0, arg1)
+}
+
+// Poll mocks base method.
+func (m *MockDeviceAuthService) Poll(arg0 context.Context, arg1, arg2, arg3 string) (*core.DeviceToken, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Poll", arg0, arg1, arg2, arg3)
+	ret0, _ := ret[0].(*core.DeviceToken)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Poll indicates an expected call of Poll.
+func (mr *MockDeviceAuthServiceMockRecorder) Poll(arg0, arg1, arg2, arg3 any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Poll", reflect.TypeOf((*MockDeviceAuthService)(nil).Poll), arg0, arg1, arg2, arg3)
+}
+
+// Start mocks base method.
+func (m *MockDeviceAuthService) Start(arg0 context.Context, arg1, arg2 string) (*core.DeviceCode, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Start", arg0, arg1, arg2)
+	ret0, _ := ret[0].(*core.DeviceCode)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Start indicates an expected call of Start.
+func (mr *MockDeviceAuthServiceMockRecorder) Start(arg0, arg1, arg2 any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Start", reflect.TypeOf((*MockDeviceAuthService)(nil).Start), arg0, arg1, arg2)
+}
+
+// MockParentalControlsRepository is a mock of ParentalControlsRepository interface.
+type MockParentalControlsRepository struct {
+	ctrl     *gomock.Controller
+	recorder *MockParentalControlsRepositoryMockRecorder
+}
+
+// MockParentalControlsRepositoryMockRecorder is the mock recorder for MockParentalControlsRepository.
+type MockParentalControlsRepositoryMockRecorder struct {
+	mock *MockParentalControlsRepository
+}
+
+// NewMockParentalControlsRepository creates a new mock instance.
+func NewMockParentalControlsRepository(ctrl *gomock.Controller) *MockParentalControlsRepository {
+	mock := &MockParentalControlsRepository{ctrl: ctrl}
+	mock.recorder = &MockParentalControlsRepositoryMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockParentalControlsRepository) EXPECT() *MockParentalControlsRepositoryMockRecorder {
+	return m.recorder
+}
+
+// Delete mocks base method.
+func (m *MockParentalControlsRepository) Delete(arg0 context.Context, arg1 int64) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Delete", arg0, arg1)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// Delete indicates an expected call of Delete.
+func (mr *MockParentalControlsRepositoryMockRecorder) Delete(arg0, arg1 any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Delete", reflect.TypeOf((*MockParentalControlsRepository)(nil).Delete), arg0, arg1)
+}
+
+// Find mocks base method.
+func (m *MockParentalControlsRepository) Find(arg0 context.Context, arg1 int64) (*core.ParentalControls, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Find", arg0, arg1)
+	ret0, _ := ret[0].(*core.ParentalControls)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Find indicates an expected call of Find.
+func (mr *MockParentalControlsRepositoryMockRecorder) Find(arg0, arg1 any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Find", reflect.TypeOf((*MockParentalControlsRepository)(nil).Find), arg0, arg1)
+}
+
+// Save mocks base method.
+func (m *MockParentalControlsRepository) Save(arg0 context.Context, arg1 *core.ParentalControls) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Save", arg0, arg1)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// Save indicates an expected call of Save.
+func (mr *MockParentalControlsRepositoryMockRecorder) Save(arg0, arg1 any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Save", reflect.TypeOf((*MockParentalControlsRepository)(nil).Save), arg0, arg1)
+}
+
+// MockParentalControlsService is a mock of ParentalControlsService interface.
+type MockParentalControlsService struct {
+	ctrl     *gomock.Controller
+	recorder *MockParentalControlsServiceMockRecorder
+}
+
+// MockParentalControlsServiceMockRecorder is the mock recorder for MockParentalControlsService.
+type MockParentalControlsServiceMockRecorder struct {
+	mock *MockParentalControlsService
+}
+
+// NewMockParentalControlsService creates a new mock instance.
+func NewMockParentalControlsService(ctrl *gomock.Controller) *MockParentalControlsService {
+	mock := &MockParentalControlsService{ctrl: ctrl}
+	mock.recorder = &MockParentalControlsServiceMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockParentalControlsService) EXPECT() *MockParentalControlsServiceMockRecorder {
+	return m.recorder
+}
+
+// Delete mocks base method.
+func (m *MockParentalControlsService) Delete(arg0 context.Context, arg1 int64) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Delete", arg0, arg1)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// Delete indicates an expected call of Delete.
+func (mr *MockParentalControlsServiceMockRecorder) Delete(arg0, arg1 any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Delete", reflect.TypeOf((*MockParentalControlsService)(nil).Delete), arg0, arg1)
+}
+
+// Filter mocks base method.
+func (m *MockParentalControlsService) Filter(arg0 context.Context, arg1 int64) (*core.ContentFilter, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Filter", arg0, arg1)
+	ret0, _ := ret[0].(*core.ContentFilter)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Filter indicates an expected call of Filter.
+func (mr *MockParentalControlsServiceMockRecorder) Filter(arg0, arg1 any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Filter", reflect.TypeOf((*MockParentalControlsService)(nil).Filter), arg0, arg1)
+}
+
+// Get mocks base method.
+func (m *MockParentalControlsService) Get(arg0 context.Context, arg1 int64) (*core.ParentalControls, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Get", arg0, arg1)
+	ret0, _ := ret[0].(*core.ParentalControls)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Get indicates an expected call of Get.
+func (mr *MockParentalControlsServiceMockRecorder) Get(arg0, arg1 any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Get", reflect.TypeOf((*MockParentalControlsService)(nil).Get), arg0, arg1)
+}
+
+// Save mocks base method.
+func (m *MockParentalControlsService) Save(arg0 context.Context, arg1 *core.ParentalControls) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Save", arg0, arg1)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// Save indicates an expected call of Save.
+func (mr *MockParentalControlsServiceMockRecorder) Save(arg0, arg1 any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Save", reflect.TypeOf((*MockParentalControlsService)(nil).Save), arg0, arg1)
+}
+
+// MockTrustedDeviceRepository is a mock of TrustedDeviceRepository interface.
+type MockTrustedDeviceRepository struct {
+	ctrl     *gomock.Controller
+	recorder *MockTrustedDeviceRepositoryMockRecorder
+}
+
+// MockTrustedDeviceRepositoryMockRecorder is the mock recorder for MockTrustedDeviceRepository.
+type MockTrustedDeviceRepositoryMockRecorder struct {
+	mock *MockTrustedDeviceRepository
+}
+
+// NewMockTrustedDeviceRepository creates a new mock instance.
+func NewMockTrustedDeviceRepository(ctrl *gomock.Controller) *MockTrustedDeviceRepository {
+	mock := &MockTrustedDeviceRepository{ctrl: ctrl}
+	mock.recorder = &MockTrustedDeviceRepositoryMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockTrustedDeviceRepository) EXPECT() *MockTrustedDeviceRepositoryMockRecorder {
+	return m.recorder
+}
+
+// Create mocks base method.
+func (m *MockTrustedDeviceRepository) Create(arg0 context.Context, arg1 *core.TrustedDevice) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Create", arg0, arg1)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// Create indicates an expected call of Create.
+func (mr *MockTrustedDeviceRepositoryMockRecorder) Create(arg0, arg1 any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Create", reflect.TypeOf((*MockTrustedDeviceRepository)(nil).Create), arg0, arg1)
+}
+
+// Delete mocks base method.
+func (m *MockTrustedDeviceRepository) Delete(arg0 context.Context, arg1, arg2 int64) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Delete", arg0, arg1, arg2)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// Delete indicates an expected call of Delete.
+func (mr *MockTrustedDeviceRepositoryMockRecorder) Delete(arg0, arg1, arg2 any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Delete", reflect.TypeOf((*MockTrustedDeviceRepository)(nil).Delete), arg0, arg1, arg2)
+}
+
+// DeleteByUser mocks base method.
+func (m *MockTrustedDeviceRepository) DeleteByUser(arg0 context.Context, arg1 int64) (int64, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "DeleteByUser", arg0, arg1)
+	ret0, _ := ret[0].(int64)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// DeleteByUser indicates an expected call of DeleteByUser.
+func (mr *MockTrustedDeviceRepositoryMockRecorder) DeleteByUser(arg0, arg1 any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DeleteByUser", reflect.TypeOf((*MockTrustedDeviceRepository)(nil).DeleteByUser), arg0, arg1)
+}
+
+// DeleteExpired mocks base method.
+func (m *MockTrustedDeviceRepository) DeleteExpired(arg0 context.Context, arg1 int64) (int64, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "DeleteExpired", arg0, arg1)
+	ret0, _ := ret[0].(int64)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// DeleteExpired indicates an expected call of DeleteExpired.
+func (mr *MockTrustedDeviceRepositoryMockRecorder) DeleteExpired(arg0, arg1 any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DeleteExpired", reflect.TypeOf((*MockTrustedDeviceRepository)(nil).DeleteExpired), arg0, arg1)
+}
+
+// Find mocks base method.
+func (m *MockTrustedDeviceRepository) Find(arg0 context.Context, arg1 string) (*core.TrustedDevice, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Find", arg0, arg1)
+	ret0, _ := ret[0].(*core.TrustedDevice)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Find indicates an expected call of Find.
+func (mr *MockTrustedDeviceRepositoryMockRecorder) Find(arg0, arg1 any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Find", reflect.TypeOf((*MockTrustedDeviceRepository)(nil).Find), arg0, arg1)
+}
+
+// FindByUser mocks base method.
+func (m *MockTrustedDeviceRepository) FindByUser(arg0 context.Context, arg1 int64) ([]*core.TrustedDevice, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "FindByUser", arg0, arg1)
+	ret0, _ := ret[0].([]*core.TrustedDevice)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// FindByUser indicates an expected call of FindByUser.
+func (mr *MockTrustedDeviceRepositoryMockRecorder) FindByUser(arg0, arg1 any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "FindByUser", reflect.TypeOf((*MockTrustedDeviceRepository)(nil).FindByUser), arg0, arg1)
+}
+
+// Update mocks base method.
+func (m *MockTrustedDeviceRepository) Update(arg0 context.Context, arg1 *core.TrustedDevice) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Update", arg0, arg1)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// Update indicates an expected call of Update.
+func (mr *MockTrustedDeviceRepositoryMockRecorder) Update(arg0, arg1 any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Update", reflect.TypeOf((*MockTrustedDeviceRepository)(nil).Update), arg0, arg1)
+}
+
+// MockTrustedDeviceService is a mock of TrustedDeviceService interface.
+type MockTrustedDeviceService struct {
+	ctrl     *gomock.Controller
+	recorder *MockTrustedDeviceServiceMockRecorder
+}
+
+// MockTrustedDeviceServiceMockRecorder is the mock recorder for MockTrustedDeviceService.
+type MockTrustedDeviceServiceMockRecorder struct {
+	mock *MockTrustedDeviceService
+}
+
+// NewMockTrustedDeviceService creates a new mock instance.
+func NewMockTrustedDeviceService(ctrl *gomock.Controller) *MockTrustedDeviceService {
+	mock := &MockTrustedDeviceService{ctrl: ctrl}
+	mock.recorder = &MockTrustedDeviceServiceMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockTrustedDeviceService) EXPECT() *MockTrustedDeviceServiceMockRecorder {
+	return m.recorder
+}
+
+// List mocks base method.
+func (m *MockTrustedDeviceService) List(arg0 context.Context, arg1 int64) ([]*core.TrustedDevice, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "List", arg0, arg1)
+	ret0, _ := ret[0].([]*core.TrustedDevice)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// List indicates an expected call of List.
+func (mr *MockTrustedDeviceServiceMockRecorder) List(arg0, arg1 any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "List", reflect.TypeOf((*MockTrustedDeviceService)(nil).List), arg0, arg1)
+}
+
+// Revoke mocks base method.
+func (m *MockTrustedDeviceService) Revoke(arg0 context.Context, arg1, arg2 int64) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Revoke", arg0, arg1, arg2)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// Revoke indicates an expected call of Revoke.
+func (mr *MockTrustedDeviceServiceMockRecorder) Revoke(arg0, arg1, arg2 any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Revoke", reflect.TypeOf((*MockTrustedDeviceService)(nil).Revoke), arg0, arg1, arg2)
+}
+
+// RevokeAll mocks base method.
+func (m *MockTrustedDeviceService) RevokeAll(arg0 context.Context, arg1 int64) (int64, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "RevokeAll", arg0, arg1)
+	ret0, _ := ret[0].(int64)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// RevokeAll indicates an expected call of RevokeAll.
+func (mr *MockTrustedDeviceServiceMockRecorder) RevokeAll(arg0, arg1 any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "RevokeAll", reflect.TypeOf((*MockTrustedDeviceService)(nil).RevokeAll), arg0, arg1)
+}
+
+// Trust mocks base method.
+func (m *MockTrustedDeviceService) Trust(arg0 context.Context, arg1 int64, arg2 int, arg3, arg4 string) (string, *core.TrustedDevice, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Trust", arg0, arg1, arg2, arg3, arg4)
+	ret0, _ := ret[0].(string)
+	ret1, _ := ret[1].(*core.TrustedDevice)
+	ret2, _ := ret[2].(error)
+	return ret0, ret1, ret2
+}
+
+// Trust indicates an expected call of Trust.
+func (mr *MockTrustedDeviceServiceMockRecorder) Trust(arg0, arg1, arg2, arg3, arg4 any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Trust", reflect.TypeOf((*MockTrustedDeviceService)(nil).Trust), arg0, arg1, arg2, arg3, arg4)
+}
+
+// Verify mocks base method.
+func (m *MockTrustedDeviceService) Verify(arg0 context.Context, arg1 string) (*core.TrustedDevice, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Verify", arg0, arg1)
+	ret0, _ := ret[0].(*core.TrustedDevice)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Verify indicates an expected call of Verify.
+func (mr *MockTrustedDeviceServiceMockRecorder) Verify(arg0, arg1 any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Verify", reflect.TypeOf((*MockTrustedDeviceService)(nil).Verify), arg0, arg1)
+}
+
+// MockPasswordHasher is a mock of PasswordHasher interface.
+type MockPasswordHasher struct {
+	ctrl     *gomock.Controller
+	recorder *MockPasswordHasherMockRecorder
+}
+
+// MockPasswordHasherMockRecorder is the mock recorder for MockPasswordHasher.
+type MockPasswordHasherMockRecorder struct {
+	mock *MockPasswordHasher
+}
+
+// NewMockPasswordHasher creates a new mock instance.
+func NewMockPasswordHasher(ctrl *gomock.Controller) *MockPasswordHasher {
+	mock := &MockPasswordHasher{ctrl: ctrl}
+	mock.recorder = &MockPasswordHasherMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockPasswordHasher) EXPECT() *MockPasswordHasherMockRecorder {
+	return m.recorder
+}
+
+// Compare mocks base method.
+func (m *MockPasswordHasher) Compare(arg0, arg1 string) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Compare", arg0, arg1)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// Compare indicates an expected call of Compare.
+func (mr *MockPasswordHasherMockRecorder) Compare(arg0, arg1 any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Compare", reflect.TypeOf((*MockPasswordHasher)(nil).Compare), arg0, arg1)
+}
+
+// Hash mocks base method.
+func (m *MockPasswordHasher) Hash(arg0 string) (string, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Hash", arg0)
+	ret0, _ := ret[0].(string)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Hash indicates an expected call of Hash.
+func (mr *MockPasswordHasherMockRecorder) Hash(arg0 any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Hash", reflect.TypeOf((*MockPasswordHasher)(nil).Hash), arg0)
+}
+
+// NeedsRehash mocks base method.
+func (m *MockPasswordHasher) NeedsRehash(arg0 string) bool {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "NeedsRehash", arg0)
+	ret0, _ := ret[0].(bool)
+	return ret0
+}
+
+// NeedsRehash indicates an expected call of NeedsRehash.
+func (mr *MockPasswordHasherMockRecorder) NeedsRehash(arg0 any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "NeedsRehash", reflect.TypeOf((*MockPasswordHasher)(nil).NeedsRehash), arg0)
+}
@@ -40,6 +40,20 @@ func (m *MockClient) EXPECT() *MockClientMockRecorder {
 	return m.recorder
 }
 
+// CircuitState mocks base method.
+func (m *MockClient) CircuitState() tvheadend.CircuitState {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CircuitState")
+	ret0, _ := ret[0].(tvheadend.CircuitState)
+	return ret0
+}
+
+// CircuitState indicates an expected call of CircuitState.
+func (mr *MockClientMockRecorder) CircuitState() *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CircuitState", reflect.TypeOf((*MockClient)(nil).CircuitState))
+}
+
 // Exec mocks base method.
 func (m *MockClient) Exec(arg0 context.Context, arg1 string, arg2 any, arg3 ...tvheadend.Query) (*tvheadend.Response, error) {
 	m.ctrl.T.Helper()
@@ -0,0 +1,134 @@
+package auditlog
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"github.com/davidborzek/tvhgo/config"
+	"github.com/davidborzek/tvhgo/core"
+	"github.com/davidborzek/tvhgo/db"
+)
+
+type sqlRepository struct {
+	db    *db.DB
+	clock core.Clock
+}
+
+func New(db *db.DB, clock core.Clock) core.AuditRepository {
+	return &sqlRepository{
+		db:    db,
+		clock: clock,
+	}
+}
+
+func (s *sqlRepository) Create(ctx context.Context, entry *core.AuditEntry) error {
+	entry.CreatedAt = s.clock.Now().Unix()
+
+	args := []any{
+		entry.UserID,
+		entry.Username,
+		entry.Action,
+		entry.Target,
+		entry.Reason,
+		entry.ClientIP,
+		entry.UserAgent,
+		entry.CreatedAt,
+	}
+
+	if s.db.Type == config.DatabaseTypePostgres {
+		return s.db.QueryRowContext(ctx, stmtInsertPostgres, args...).
+			Scan(&entry.ID)
+	}
+
+	res, err := s.db.ExecContext(ctx, stmtInsert, args...)
+	if err != nil {
+		return err
+	}
+
+	id, err := res.LastInsertId()
+	if err != nil {
+		return err
+	}
+
+	entry.ID = id
+	return nil
+}
+
+func (s *sqlRepository) Find(
+	ctx context.Context,
+	params core.AuditQueryParams,
+) (*core.AuditListResult, error) {
+	where, args := buildWhere(params)
+
+	var count int64
+	err := s.db.QueryRowContext(ctx, queryCount+where, args...).
+		Scan(&count)
+	if err != nil {
+		return nil, err
+	}
+
+	query := queryBase + where + queryOrder
+	if params.Limit > 0 {
+		args = append(args, params.Limit)
+		query += fmt.Sprintf(" LIMIT $%d", len(args))
+	}
+
+	if params.Limit > 0 && params.Offset > 0 {
+		args = append(args, params.Offset)
+		query += fmt.Sprintf(" OFFSET $%d", len(args))
+	}
+
+	rows, err := s.db.QueryContext(ctx, query, args...)
+	if err != nil {
+		return nil, err
+	}
+
+	entries, err := scanRows(rows)
+	if err != nil {
+		return nil, err
+	}
+
+	return &core.AuditListResult{
+		Entries: entries,
+		Total:   count,
+		Offset:  params.Offset,
+	}, nil
+}
+
+// Internal helper to build the where clause of the filters.
+func buildWhere(params core.AuditQueryParams) (string, []any) {
+	conditions := []string{}
+	args := []any{}
+
+	add := func(condition string, arg any) {
+		args = append(args, arg)
+		conditions = append(conditions, fmt.Sprintf(condition, len(args)))
+	}
+
+	if params.UserID != nil {
+		add("audit_log.user_id = $%d", *params.UserID)
+	}
+
+	if params.Username != "" {
+		add("audit_log.username = $%d", params.Username)
+	}
+
+	if params.Action != "" {
+		add("audit_log.action = $%d", params.Action)
+	}
+
+	if params.From > 0 {
+		add("audit_log.created_at >= $%d", params.From)
+	}
+
+	if params.To > 0 {
+		add("audit_log.created_at < $%d", params.To)
+	}
+
+	if len(conditions) == 0 {
+		return "", args
+	}
+
+	return "WHERE " + strings.Join(conditions, " AND "), args
+}
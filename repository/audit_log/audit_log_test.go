@@ -0,0 +1,98 @@
+package auditlog_test
+
+import (
+	"context"
+	"os"
+	"testing"
+
+	"github.com/davidborzek/tvhgo/core"
+	"github.com/davidborzek/tvhgo/db/testdb"
+	auditlog "github.com/davidborzek/tvhgo/repository/audit_log"
+	"github.com/davidborzek/tvhgo/services/clock"
+	"github.com/stretchr/testify/assert"
+)
+
+var (
+	noCtx      = context.TODO()
+	repository core.AuditRepository
+)
+
+func TestMain(m *testing.M) {
+	db, err := testdb.Setup()
+	if err != nil {
+		panic(err)
+	}
+	defer testdb.Close(db)
+
+	repository = auditlog.New(db, clock.NewClock())
+	code := m.Run()
+
+	err = testdb.TruncateTables(db, "audit_log")
+	if err != nil {
+		panic(err)
+	}
+
+	testdb.Close(db)
+
+	os.Exit(code)
+}
+
+func TestCreateAndFind(t *testing.T) {
+	userID := int64(1)
+
+	login := &core.AuditEntry{
+		UserID:    &userID,
+		Username:  "jdoe",
+		Action:    core.AuditActionLogin,
+		ClientIP:  "192.168.1.1",
+		UserAgent: "someAgent",
+	}
+	assert.Nil(t, repository.Create(noCtx, login))
+	assert.NotZero(t, login.ID)
+	assert.NotZero(t, login.CreatedAt)
+
+	failed := &core.AuditEntry{
+		Username: "unknown",
+		Action:   core.AuditActionLoginFailed,
+		Reason:   "invalid username or password",
+		ClientIP: "192.168.1.2",
+	}
+	assert.Nil(t, repository.Create(noCtx, failed))
+
+	result, err := repository.Find(noCtx, core.AuditQueryParams{})
+	assert.Nil(t, err)
+	assert.Equal(t, int64(2), result.Total)
+	assert.Equal(t, []*core.AuditEntry{failed, login}, result.Entries)
+
+	result, err = repository.Find(noCtx, core.AuditQueryParams{
+		UserID: &userID,
+	})
+	assert.Nil(t, err)
+	assert.Equal(t, []*core.AuditEntry{login}, result.Entries)
+
+	result, err = repository.Find(noCtx, core.AuditQueryParams{
+		Username: "unknown",
+		Action:   core.AuditActionLoginFailed,
+	})
+	assert.Nil(t, err)
+	assert.Equal(t, []*core.AuditEntry{failed}, result.Entries)
+
+	result, err = repository.Find(noCtx, core.AuditQueryParams{
+		From: login.CreatedAt,
+		To:   login.CreatedAt,
+	})
+	assert.Nil(t, err)
+	assert.Equal(t, int64(0), result.Total)
+	assert.Empty(t, result.Entries)
+
+	result, err = repository.Find(noCtx, core.AuditQueryParams{
+		PaginationQueryParams: core.PaginationQueryParams{
+			Limit:  1,
+			Offset: 1,
+		},
+	})
+	assert.Nil(t, err)
+	assert.Equal(t, int64(2), result.Total)
+	assert.Equal(t, int64(1), result.Offset)
+	assert.Equal(t, []*core.AuditEntry{login}, result.Entries)
+}
@@ -0,0 +1,53 @@
+package auditlog
+
+const queryCount = `
+SELECT COUNT(*) FROM audit_log
+`
+
+const queryBase = `
+SELECT
+audit_log.id,
+audit_log.user_id,
+audit_log.username,
+audit_log.action,
+audit_log.target,
+audit_log.reason,
+audit_log.client_ip,
+audit_log.user_agent,
+audit_log.created_at
+FROM audit_log
+`
+
+const queryOrder = `
+ORDER BY audit_log.created_at DESC, audit_log.id DESC
+`
+
+const stmtInsert = `
+INSERT INTO audit_log (
+user_id,
+username,
+action,
+target,
+reason,
+client_ip,
+user_agent,
+created_at
+) VALUES (
+$1, $2, $3, $4, $5, $6, $7, $8
+)
+`
+
+const stmtInsertPostgres = `
+INSERT INTO audit_log (
+user_id,
+username,
+action,
+target,
+reason,
+client_ip,
+user_agent,
+created_at
+) VALUES (
+$1, $2, $3, $4, $5, $6, $7, $8
+) RETURNING id
+`
@@ -0,0 +1,38 @@
+package auditlog
+
+import (
+	"database/sql"
+
+	"github.com/davidborzek/tvhgo/core"
+	"github.com/davidborzek/tvhgo/repository"
+)
+
+// Internal helper to scan a sql.Row into an audit entry model.
+func scanRow(scanner repository.Scanner, dest *core.AuditEntry) error {
+	return scanner.Scan(
+		&dest.ID,
+		&dest.UserID,
+		&dest.Username,
+		&dest.Action,
+		&dest.Target,
+		&dest.Reason,
+		&dest.ClientIP,
+		&dest.UserAgent,
+		&dest.CreatedAt,
+	)
+}
+
+// Internal helper to scan sql.Rows into an array of audit entry models.
+func scanRows(rows *sql.Rows) ([]*core.AuditEntry, error) {
+	defer rows.Close()
+
+	entries := []*core.AuditEntry{}
+	for rows.Next() {
+		entry := new(core.AuditEntry)
+		if err := scanRow(rows, entry); err != nil {
+			return nil, err
+		}
+		entries = append(entries, entry)
+	}
+	return entries, nil
+}
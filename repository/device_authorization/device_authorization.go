@@ -0,0 +1,137 @@
+package deviceauthorization
+
+import (
+	"context"
+	"database/sql"
+
+	"github.com/davidborzek/tvhgo/config"
+	"github.com/davidborzek/tvhgo/core"
+	"github.com/davidborzek/tvhgo/db"
+)
+
+type sqlRepository struct {
+	db    *db.DB
+	clock core.Clock
+}
+
+func New(db *db.DB, clock core.Clock) core.DeviceAuthRepository {
+	return &sqlRepository{db: db, clock: clock}
+}
+
+func (s *sqlRepository) FindByDeviceCode(
+	ctx context.Context,
+	hashedDeviceCode string,
+) (*core.DeviceAuthorization, error) {
+	return s.find(ctx, queryByDeviceCode, hashedDeviceCode)
+}
+
+func (s *sqlRepository) FindByUserCode(
+	ctx context.Context,
+	userCode string,
+) (*core.DeviceAuthorization, error) {
+	return s.find(ctx, queryByUserCode, userCode)
+}
+
+func (s *sqlRepository) find(
+	ctx context.Context,
+	query string,
+	code string,
+) (*core.DeviceAuthorization, error) {
+	row := s.db.QueryRowContext(ctx, query, code)
+
+	auth := new(core.DeviceAuthorization)
+	if err := scanRow(row, auth); err != nil {
+		if err == sql.ErrNoRows {
+			return nil, nil
+		}
+
+		return nil, err
+	}
+	return auth, nil
+}
+
+func (s *sqlRepository) Create(ctx context.Context, auth *core.DeviceAuthorization) error {
+	auth.CreatedAt = s.clock.Now().Unix()
+
+	args := []any{
+		auth.HashedDeviceCode,
+		auth.UserCode,
+		auth.DeviceName,
+		auth.ClientIP,
+		auth.Status,
+		auth.UserID,
+		formatScopes(auth.Scopes),
+		auth.ExpiresAt,
+		auth.LastPolledAt,
+		auth.CreatedAt,
+	}
+
+	if s.db.Type == config.DatabaseTypePostgres {
+		return s.db.QueryRowContext(ctx, stmtInsertPostgres, args...).
+			Scan(&auth.ID)
+	}
+
+	res, err := s.db.ExecContext(ctx, stmtInsert, args...)
+	if err != nil {
+		return err
+	}
+
+	id, err := res.LastInsertId()
+	if err != nil {
+		return err
+	}
+
+	auth.ID = id
+	return nil
+}
+
+func (s *sqlRepository) Update(ctx context.Context, auth *core.DeviceAuthorization) error {
+	_, err := s.db.ExecContext(ctx, stmtUpdate,
+		auth.Status,
+		auth.UserID,
+		formatScopes(auth.Scopes),
+		auth.LastPolledAt,
+		auth.ID,
+	)
+	return err
+}
+
+func (s *sqlRepository) Delete(ctx context.Context, id int64) (bool, error) {
+	res, err := s.db.ExecContext(ctx, stmtDelete, id)
+	if err != nil {
+		return false, err
+	}
+
+	rows, err := res.RowsAffected()
+	if err != nil {
+		return false, err
+	}
+
+	return rows > 0, nil
+}
+
+func (s *sqlRepository) CountPending(ctx context.Context) (int64, error) {
+	var count int64
+	err := s.db.QueryRowContext(ctx, queryCountPending, core.DeviceAuthStatusPending).
+		Scan(&count)
+	return count, err
+}
+
+func (s *sqlRepository) CountPendingByClientIP(
+	ctx context.Context,
+	clientIP string,
+) (int64, error) {
+	var count int64
+	err := s.db.QueryRowContext(ctx, queryCountPendingByClientIP, core.DeviceAuthStatusPending, clientIP).
+		Scan(&count)
+	return count, err
+}
+
+func (s *sqlRepository) DeleteExpired(ctx context.Context, before int64) (int64, error) {
+	res, err := s.db.ExecContext(ctx, stmtDeleteExpired, before)
+	if err != nil {
+		return 0, err
+	}
+
+	return res.RowsAffected()
+}
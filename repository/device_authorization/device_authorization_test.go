@@ -0,0 +1,208 @@
+package deviceauthorization_test
+
+import (
+	"context"
+	"os"
+	"testing"
+
+	"github.com/davidborzek/tvhgo/core"
+	database "github.com/davidborzek/tvhgo/db"
+	"github.com/davidborzek/tvhgo/db/testdb"
+	deviceauthorization "github.com/davidborzek/tvhgo/repository/device_authorization"
+	"github.com/davidborzek/tvhgo/repository/user"
+	"github.com/davidborzek/tvhgo/services/clock"
+	"github.com/stretchr/testify/assert"
+)
+
+var (
+	noCtx      = context.TODO()
+	repository core.DeviceAuthRepository
+
+	testUser = &core.User{
+		ID:          1234,
+		Username:    "testuser",
+		Email:       "testuser@example.com",
+		DisplayName: "Test user",
+	}
+)
+
+func initTestUser(db *database.DB) error {
+	return user.New(db, clock.NewClock()).
+		Create(noCtx, testUser)
+}
+
+func TestMain(m *testing.M) {
+	db, err := testdb.Setup()
+	if err != nil {
+		panic(err)
+	}
+	defer testdb.Close(db)
+
+	if err := initTestUser(db); err != nil {
+		panic(err)
+	}
+
+	repository = deviceauthorization.New(db, clock.NewClock())
+	code := m.Run()
+
+	err = testdb.TruncateTables(db, "device_authorization", "user")
+	if err != nil {
+		panic(err)
+	}
+
+	testdb.Close(db)
+
+	os.Exit(code)
+}
+
+func TestFindReturnsNil(t *testing.T) {
+	auth, err := repository.FindByDeviceCode(noCtx, "unknownCode")
+	assert.Nil(t, err)
+	assert.Nil(t, auth)
+
+	auth, err = repository.FindByUserCode(noCtx, "unknownCode")
+	assert.Nil(t, err)
+	assert.Nil(t, auth)
+}
+
+func TestCreate(t *testing.T) {
+	auth := &core.DeviceAuthorization{
+		HashedDeviceCode: "someHashedDeviceCode",
+		UserCode:         "BCDFGHJK",
+		DeviceName:       "Living Room TV",
+		ClientIP:         "192.168.1.10",
+		Status:           core.DeviceAuthStatusPending,
+		ExpiresAt:        1000,
+		LastPolledAt:     400,
+	}
+
+	err := repository.Create(noCtx, auth)
+	assert.Nil(t, err)
+	assert.NotEmpty(t, auth.ID)
+	assert.NotEmpty(t, auth.CreatedAt)
+
+	t.Run("Find", testFind(auth))
+	t.Run("Update", testUpdate(auth))
+	t.Run("Delete", testDelete(auth))
+}
+
+func testFind(created *core.DeviceAuthorization) func(t *testing.T) {
+	return func(t *testing.T) {
+		auth, err := repository.FindByDeviceCode(noCtx, created.HashedDeviceCode)
+		assert.Nil(t, err)
+		assert.Equal(t, created, auth)
+
+		auth, err = repository.FindByUserCode(noCtx, created.UserCode)
+		assert.Nil(t, err)
+		assert.Equal(t, created, auth)
+	}
+}
+
+func testUpdate(created *core.DeviceAuthorization) func(t *testing.T) {
+	return func(t *testing.T) {
+		created.Status = core.DeviceAuthStatusApproved
+		created.UserID = &testUser.ID
+		created.Scopes = []core.Permission{core.PermissionEPGRead, core.PermissionStream}
+		created.LastPolledAt = 500
+
+		err := repository.Update(noCtx, created)
+		assert.Nil(t, err)
+
+		auth, err := repository.FindByUserCode(noCtx, created.UserCode)
+		assert.Nil(t, err)
+		assert.Equal(t, created, auth)
+	}
+}
+
+func testDelete(created *core.DeviceAuthorization) func(t *testing.T) {
+	return func(t *testing.T) {
+		deleted, err := repository.Delete(noCtx, created.ID)
+		assert.Nil(t, err)
+		assert.True(t, deleted)
+
+		deleted, err = repository.Delete(noCtx, created.ID)
+		assert.Nil(t, err)
+		assert.False(t, deleted)
+	}
+}
+
+func TestDeleteExpired(t *testing.T) {
+	expired := &core.DeviceAuthorization{
+		HashedDeviceCode: "expiredDeviceCode",
+		UserCode:         "BBBBBBBB",
+		DeviceName:       "Old TV",
+		Status:           core.DeviceAuthStatusPending,
+		ExpiresAt:        100,
+	}
+	valid := &core.DeviceAuthorization{
+		HashedDeviceCode: "validDeviceCode",
+		UserCode:         "CCCCCCCC",
+		DeviceName:       "New TV",
+		Status:           core.DeviceAuthStatusPending,
+		ExpiresAt:        300,
+	}
+	assert.Nil(t, repository.Create(noCtx, expired))
+	assert.Nil(t, repository.Create(noCtx, valid))
+
+	deleted, err := repository.DeleteExpired(noCtx, 200)
+	assert.Nil(t, err)
+	assert.Equal(t, int64(1), deleted)
+
+	auth, err := repository.FindByUserCode(noCtx, expired.UserCode)
+	assert.Nil(t, err)
+	assert.Nil(t, auth)
+
+	auth, err = repository.FindByUserCode(noCtx, valid.UserCode)
+	assert.Nil(t, err)
+	assert.Equal(t, valid, auth)
+}
+
+func TestCountPending(t *testing.T) {
+	pending := &core.DeviceAuthorization{
+		HashedDeviceCode: "pendingDeviceCode",
+		UserCode:         "DDDDDDDD",
+		DeviceName:       "Kitchen TV",
+		ClientIP:         "192.168.1.20",
+		Status:           core.DeviceAuthStatusPending,
+		ExpiresAt:        1000,
+	}
+	otherIP := &core.DeviceAuthorization{
+		HashedDeviceCode: "otherIPDeviceCode",
+		UserCode:         "FFFFFFFF",
+		DeviceName:       "Bedroom TV",
+		ClientIP:         "192.168.1.21",
+		Status:           core.DeviceAuthStatusPending,
+		ExpiresAt:        1000,
+	}
+	denied := &core.DeviceAuthorization{
+		HashedDeviceCode: "deniedDeviceCode",
+		UserCode:         "GGGGGGGG",
+		DeviceName:       "Office TV",
+		ClientIP:         "192.168.1.20",
+		Status:           core.DeviceAuthStatusDenied,
+		ExpiresAt:        1000,
+	}
+	for _, auth := range []*core.DeviceAuthorization{pending, otherIP, denied} {
+		assert.Nil(t, repository.Create(noCtx, auth))
+		defer repository.Delete(noCtx, auth.ID)
+	}
+
+	before, err := repository.CountPending(noCtx)
+	assert.Nil(t, err)
+	assert.GreaterOrEqual(t, before, int64(2))
+
+	count, err := repository.CountPendingByClientIP(noCtx, "192.168.1.20")
+	assert.Nil(t, err)
+	assert.Equal(t, int64(1), count)
+
+	count, err = repository.CountPendingByClientIP(noCtx, "192.168.1.22")
+	assert.Nil(t, err)
+	assert.Zero(t, count)
+
+	_, err = repository.Delete(noCtx, otherIP.ID)
+	assert.Nil(t, err)
+
+	after, err := repository.CountPending(noCtx)
+	assert.Nil(t, err)
+	assert.Equal(t, before-1, after)
+}
@@ -0,0 +1,86 @@
+package deviceauthorization
+
+// Base select query
+const queryBase = `
+SELECT
+device_authorization.id,
+device_authorization.hashed_device_code,
+device_authorization.user_code,
+device_authorization.device_name,
+device_authorization.client_ip,
+device_authorization.status,
+device_authorization.user_id,
+device_authorization.scopes,
+device_authorization.expires_at,
+device_authorization.last_polled_at,
+device_authorization.created_at
+FROM device_authorization
+`
+
+// Select device authorization by hashed device code
+const queryByDeviceCode = queryBase + `
+WHERE
+device_authorization.hashed_device_code = $1
+`
+
+// Select device authorization by user code
+const queryByUserCode = queryBase + `
+WHERE
+device_authorization.user_code = $1
+`
+
+// Count pending device authorizations
+const queryCountPending = `
+SELECT COUNT(*)
+FROM device_authorization
+WHERE device_authorization.status = $1
+`
+
+// Count pending device authorizations by client ip
+const queryCountPendingByClientIP = queryCountPending + `
+AND device_authorization.client_ip = $2
+`
+
+// Insert device authorization statement
+const stmtInsert = `
+INSERT INTO device_authorization (
+hashed_device_code,
+user_code,
+device_name,
+client_ip,
+status,
+user_id,
+scopes,
+expires_at,
+last_polled_at,
+created_at
+) VALUES (
+$1, $2, $3, $4, $5, $6, $7, $8, $9, $10
+)
+`
+
+const stmtInsertPostgres = stmtInsert + `
+RETURNING id
+`
+
+// Update device authorization statement
+const stmtUpdate = `
+UPDATE device_authorization SET
+status = $1,
+user_id = $2,
+scopes = $3,
+last_polled_at = $4
+WHERE id = $5
+`
+
+// Delete device authorization statement
+const stmtDelete = `
+DELETE FROM device_authorization
+WHERE device_authorization.id = $1
+`
+
+// Delete expired device authorizations statement
+const stmtDeleteExpired = `
+DELETE FROM device_authorization
+WHERE device_authorization.expires_at < $1
+`
@@ -0,0 +1,58 @@
+package deviceauthorization
+
+import (
+	"strings"
+
+	"github.com/davidborzek/tvhgo/core"
+	"github.com/davidborzek/tvhgo/repository"
+)
+
+const scopeSeparator = ","
+
+// Internal helper to scan a sql.Row into a device authorization model.
+func scanRow(scanner repository.Scanner, dest *core.DeviceAuthorization) error {
+	var scopes string
+
+	err := scanner.Scan(
+		&dest.ID,
+		&dest.HashedDeviceCode,
+		&dest.UserCode,
+		&dest.DeviceName,
+		&dest.ClientIP,
+		&dest.Status,
+		&dest.UserID,
+		&scopes,
+		&dest.ExpiresAt,
+		&dest.LastPolledAt,
+		&dest.CreatedAt,
+	)
+	if err != nil {
+		return err
+	}
+
+	dest.Scopes = parseScopes(scopes)
+	return nil
+}
+
+// Internal helper to parse the stored scopes of a device authorization.
+func parseScopes(value string) []core.Permission {
+	if value == "" {
+		return nil
+	}
+
+	parts := strings.Split(value, scopeSeparator)
+	scopes := make([]core.Permission, 0, len(parts))
+	for _, part := range parts {
+		scopes = append(scopes, core.Permission(part))
+	}
+	return scopes
+}
+
+// Internal helper to format the scopes of a device authorization for storage.
+func formatScopes(scopes []core.Permission) string {
+	parts := make([]string, 0, len(scopes))
+	for _, scope := range scopes {
+		parts = append(parts, string(scope))
+	}
+	return strings.Join(parts, scopeSeparator)
+}
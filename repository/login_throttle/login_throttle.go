@@ -0,0 +1,89 @@
+package loginthrottle
+
+import (
+	"context"
+	"database/sql"
+
+	"github.com/davidborzek/tvhgo/core"
+	"github.com/davidborzek/tvhgo/db"
+)
+
+type sqlRepository struct {
+	db *db.DB
+}
+
+func New(db *db.DB) core.LoginThrottleRepository {
+	return &sqlRepository{db: db}
+}
+
+func (s *sqlRepository) Find(
+	ctx context.Context,
+	kind core.LoginThrottleKind,
+	key string,
+) (*core.LoginThrottle, error) {
+	row := s.db.QueryRowContext(ctx, queryByKindAndKey, kind, key)
+
+	throttle := new(core.LoginThrottle)
+	if err := scanRow(row, throttle); err != nil {
+		if err == sql.ErrNoRows {
+			return nil, nil
+		}
+
+		return nil, err
+	}
+	return throttle, nil
+}
+
+func (s *sqlRepository) FindBlocked(ctx context.Context, now int64) ([]*core.LoginThrottle, error) {
+	rows, err := s.db.QueryContext(ctx, queryBlocked, now)
+	if err != nil {
+		return nil, err
+	}
+
+	return scanRows(rows)
+}
+
+func (s *sqlRepository) RecordFailure(
+	ctx context.Context,
+	kind core.LoginThrottleKind,
+	key string,
+	now int64,
+	resetBefore int64,
+) (*core.LoginThrottle, error) {
+	row := s.db.QueryRowContext(ctx, stmtRecordFailure, kind, key, now, resetBefore)
+
+	throttle := new(core.LoginThrottle)
+	if err := scanRow(row, throttle); err != nil {
+		return nil, err
+	}
+	return throttle, nil
+}
+
+func (s *sqlRepository) Block(ctx context.Context, id int64, blockedUntil int64) error {
+	_, err := s.db.ExecContext(ctx, stmtBlock, blockedUntil, id)
+	return err
+}
+
+func (s *sqlRepository) Delete(ctx context.Context, kind core.LoginThrottleKind, key string) error {
+	_, err := s.db.ExecContext(ctx, stmtDelete, kind, key)
+	return err
+}
+
+func (s *sqlRepository) DeleteByID(ctx context.Context, id int64) (bool, error) {
+	res, err := s.db.ExecContext(ctx, stmtDeleteByID, id)
+	if err != nil {
+		return false, err
+	}
+
+	rows, err := res.RowsAffected()
+	if err != nil {
+		return false, err
+	}
+
+	return rows > 0, nil
+}
+
+func (s *sqlRepository) DeleteInactive(ctx context.Context, before int64) error {
+	_, err := s.db.ExecContext(ctx, stmtDeleteInactive, before)
+	return err
+}
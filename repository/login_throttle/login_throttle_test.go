@@ -0,0 +1,127 @@
+package loginthrottle_test
+
+import (
+	"context"
+	"os"
+	"testing"
+
+	"github.com/davidborzek/tvhgo/core"
+	"github.com/davidborzek/tvhgo/db/testdb"
+	loginthrottle "github.com/davidborzek/tvhgo/repository/login_throttle"
+	"github.com/stretchr/testify/assert"
+)
+
+var (
+	noCtx      = context.TODO()
+	repository core.LoginThrottleRepository
+)
+
+func TestMain(m *testing.M) {
+	db, err := testdb.Setup()
+	if err != nil {
+		panic(err)
+	}
+	defer testdb.Close(db)
+
+	repository = loginthrottle.New(db)
+	code := m.Run()
+
+	err = testdb.TruncateTables(db, "login_throttle")
+	if err != nil {
+		panic(err)
+	}
+
+	testdb.Close(db)
+
+	os.Exit(code)
+}
+
+func TestFindReturnsNil(t *testing.T) {
+	throttle, err := repository.Find(noCtx, core.LoginThrottleKindIP, "unknown")
+
+	assert.Nil(t, err)
+	assert.Nil(t, throttle)
+}
+
+func TestRecordFailure(t *testing.T) {
+	kind := core.LoginThrottleKindUsername
+
+	throttle, err := repository.RecordFailure(noCtx, kind, "jdoe", 100, 0)
+	assert.Nil(t, err)
+	assert.Equal(t, 1, throttle.FailedAttempts)
+	assert.Equal(t, int64(100), throttle.LastFailedAt)
+
+	throttle, err = repository.RecordFailure(noCtx, kind, "jdoe", 200, 0)
+	assert.Nil(t, err)
+	assert.Equal(t, 2, throttle.FailedAttempts)
+	assert.Equal(t, int64(200), throttle.LastFailedAt)
+
+	err = repository.Block(noCtx, throttle.ID, 300)
+	assert.Nil(t, err)
+
+	found, err := repository.Find(noCtx, kind, "jdoe")
+	assert.Nil(t, err)
+	assert.Equal(t, &core.LoginThrottle{
+		ID:             throttle.ID,
+		Kind:           kind,
+		Key:            "jdoe",
+		FailedAttempts: 2,
+		LastFailedAt:   200,
+		BlockedUntil:   300,
+	}, found)
+
+	blocked, err := repository.FindBlocked(noCtx, 299)
+	assert.Nil(t, err)
+	assert.Len(t, blocked, 1)
+
+	blocked, err = repository.FindBlocked(noCtx, 300)
+	assert.Nil(t, err)
+	assert.Empty(t, blocked)
+
+	// Failed attempts before the reset are discarded.
+	throttle, err = repository.RecordFailure(noCtx, kind, "jdoe", 1000, 500)
+	assert.Nil(t, err)
+	assert.Equal(t, 1, throttle.FailedAttempts)
+
+	err = repository.Delete(noCtx, kind, "jdoe")
+	assert.Nil(t, err)
+
+	found, err = repository.Find(noCtx, kind, "jdoe")
+	assert.Nil(t, err)
+	assert.Nil(t, found)
+}
+
+func TestDeleteByID(t *testing.T) {
+	throttle, err := repository.RecordFailure(noCtx, core.LoginThrottleKindIP, "192.168.1.1", 100, 0)
+	assert.Nil(t, err)
+
+	deleted, err := repository.DeleteByID(noCtx, throttle.ID)
+	assert.Nil(t, err)
+	assert.True(t, deleted)
+
+	deleted, err = repository.DeleteByID(noCtx, throttle.ID)
+	assert.Nil(t, err)
+	assert.False(t, deleted)
+}
+
+func TestDeleteInactive(t *testing.T) {
+	kind := core.LoginThrottleKindIP
+
+	_, err := repository.RecordFailure(noCtx, kind, "192.168.1.2", 100, 0)
+	assert.Nil(t, err)
+
+	active, err := repository.RecordFailure(noCtx, kind, "192.168.1.3", 100, 0)
+	assert.Nil(t, err)
+	assert.Nil(t, repository.Block(noCtx, active.ID, 1000))
+
+	err = repository.DeleteInactive(noCtx, 500)
+	assert.Nil(t, err)
+
+	found, err := repository.Find(noCtx, kind, "192.168.1.2")
+	assert.Nil(t, err)
+	assert.Nil(t, found)
+
+	found, err = repository.Find(noCtx, kind, "192.168.1.3")
+	assert.Nil(t, err)
+	assert.NotNil(t, found)
+}
@@ -0,0 +1,71 @@
+package loginthrottle
+
+const queryBase = `
+SELECT
+login_throttle.id,
+login_throttle.kind,
+login_throttle.key,
+login_throttle.failed_attempts,
+login_throttle.last_failed_at,
+login_throttle.blocked_until
+FROM login_throttle
+`
+
+const queryByKindAndKey = queryBase + `
+WHERE login_throttle.kind = $1
+AND login_throttle.key = $2
+`
+
+const queryBlocked = queryBase + `
+WHERE login_throttle.blocked_until > $1
+ORDER BY login_throttle.blocked_until DESC
+`
+
+// Inserts a login throttle with a single failed attempt or increments
+// the failed attempts of an existing one. Failed attempts before $4 are discarded.
+const stmtRecordFailure = `
+INSERT INTO login_throttle (
+kind,
+key,
+failed_attempts,
+last_failed_at,
+blocked_until
+) VALUES (
+$1, $2, 1, $3, 0
+)
+ON CONFLICT (kind, key) DO UPDATE SET
+failed_attempts = CASE
+    WHEN login_throttle.last_failed_at < $4 THEN 1
+    ELSE login_throttle.failed_attempts + 1
+END,
+last_failed_at = $3
+RETURNING
+id,
+kind,
+key,
+failed_attempts,
+last_failed_at,
+blocked_until
+`
+
+const stmtBlock = `
+UPDATE login_throttle SET
+blocked_until = $1
+WHERE id = $2
+`
+
+const stmtDelete = `
+DELETE FROM login_throttle
+WHERE kind = $1
+AND key = $2
+`
+
+const stmtDeleteByID = `
+DELETE FROM login_throttle WHERE id = $1
+`
+
+const stmtDeleteInactive = `
+DELETE FROM login_throttle
+WHERE last_failed_at < $1
+AND blocked_until < $1
+`
@@ -0,0 +1,35 @@
+package loginthrottle
+
+import (
+	"database/sql"
+
+	"github.com/davidborzek/tvhgo/core"
+	"github.com/davidborzek/tvhgo/repository"
+)
+
+// Internal helper to scan a sql.Row into a login throttle model.
+func scanRow(scanner repository.Scanner, dest *core.LoginThrottle) error {
+	return scanner.Scan(
+		&dest.ID,
+		&dest.Kind,
+		&dest.Key,
+		&dest.FailedAttempts,
+		&dest.LastFailedAt,
+		&dest.BlockedUntil,
+	)
+}
+
+// Internal helper to scan sql.Rows into an array of login throttle models.
+func scanRows(rows *sql.Rows) ([]*core.LoginThrottle, error) {
+	defer rows.Close()
+
+	throttles := []*core.LoginThrottle{}
+	for rows.Next() {
+		throttle := new(core.LoginThrottle)
+		if err := scanRow(rows, throttle); err != nil {
+			return nil, err
+		}
+		throttles = append(throttles, throttle)
+	}
+	return throttles, nil
+}
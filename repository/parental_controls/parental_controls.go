@@ -0,0 +1,56 @@
+package parentalcontrols
+
+import (
+	"context"
+	"database/sql"
+	"strings"
+
+	"github.com/davidborzek/tvhgo/core"
+	"github.com/davidborzek/tvhgo/db"
+)
+
+type sqlRepository struct {
+	db    *db.DB
+	clock core.Clock
+}
+
+func New(db *db.DB, clock core.Clock) core.ParentalControlsRepository {
+	return &sqlRepository{
+		db:    db,
+		clock: clock,
+	}
+}
+
+func (s *sqlRepository) Find(ctx context.Context, userID int64) (*core.ParentalControls, error) {
+	row := s.db.QueryRowContext(ctx, queryByUserID, userID)
+
+	controls := new(core.ParentalControls)
+	if err := scanRow(row, controls); err != nil {
+		if err == sql.ErrNoRows {
+			return nil, nil
+		}
+
+		return nil, err
+	}
+	return controls, nil
+}
+
+func (s *sqlRepository) Save(ctx context.Context, controls *core.ParentalControls) error {
+	return s.db.QueryRowContext(ctx, stmtUpsert,
+		controls.UserID,
+		strings.Join(controls.Channels, listSeparator),
+		strings.Join(controls.ChannelTags, listSeparator),
+		formatContentTypes(controls.BlockedContentTypes),
+		controls.ViewingFrom,
+		controls.ViewingTo,
+		s.clock.Now().Unix(),
+	).Scan(
+		&controls.CreatedAt,
+		&controls.UpdatedAt,
+	)
+}
+
+func (s *sqlRepository) Delete(ctx context.Context, userID int64) error {
+	_, err := s.db.ExecContext(ctx, stmtDelete, userID)
+	return err
+}
@@ -0,0 +1,105 @@
+package parentalcontrols_test
+
+import (
+	"context"
+	"os"
+	"testing"
+
+	"github.com/davidborzek/tvhgo/core"
+	database "github.com/davidborzek/tvhgo/db"
+	"github.com/davidborzek/tvhgo/db/testdb"
+	parentalcontrols "github.com/davidborzek/tvhgo/repository/parental_controls"
+	"github.com/davidborzek/tvhgo/repository/user"
+	"github.com/davidborzek/tvhgo/services/clock"
+	"github.com/stretchr/testify/assert"
+)
+
+var (
+	noCtx      = context.TODO()
+	repository core.ParentalControlsRepository
+
+	testUser = &core.User{
+		Username:    "testuser",
+		Email:       "testuser@example.com",
+		DisplayName: "Test user",
+	}
+)
+
+func initTestUser(db *database.DB) error {
+	return user.New(db, clock.NewClock()).
+		Create(noCtx, testUser)
+}
+
+func TestMain(m *testing.M) {
+	db, err := testdb.Setup()
+	if err != nil {
+		panic(err)
+	}
+	defer testdb.Close(db)
+
+	if err := initTestUser(db); err != nil {
+		panic(err)
+	}
+
+	repository = parentalcontrols.New(db, clock.NewClock())
+	code := m.Run()
+
+	err = testdb.TruncateTables(db, "parental_controls", "user")
+	if err != nil {
+		panic(err)
+	}
+
+	testdb.Close(db)
+
+	os.Exit(code)
+}
+
+func TestFindReturnsNil(t *testing.T) {
+	controls, err := repository.Find(noCtx, 0)
+
+	assert.Nil(t, err)
+	assert.Nil(t, controls)
+}
+
+func TestSaveFindAndDelete(t *testing.T) {
+	controls := &core.ParentalControls{
+		UserID:              testUser.ID,
+		Channels:            []string{"someChannel"},
+		ChannelTags:         []string{"someTag", "otherTag"},
+		BlockedContentTypes: []int{16, 36},
+		ViewingFrom:         "06:00",
+		ViewingTo:           "20:00",
+	}
+
+	err := repository.Save(noCtx, controls)
+	assert.Nil(t, err)
+	assert.NotZero(t, controls.CreatedAt)
+	assert.NotZero(t, controls.UpdatedAt)
+
+	found, err := repository.Find(noCtx, testUser.ID)
+	assert.Nil(t, err)
+	assert.Equal(t, controls, found)
+
+	updated := &core.ParentalControls{
+		UserID:              testUser.ID,
+		BlockedContentTypes: []int{16},
+	}
+
+	err = repository.Save(noCtx, updated)
+	assert.Nil(t, err)
+	assert.Equal(t, controls.CreatedAt, updated.CreatedAt)
+
+	found, err = repository.Find(noCtx, testUser.ID)
+	assert.Nil(t, err)
+	assert.Equal(t, []string{}, found.Channels)
+	assert.Equal(t, []string{}, found.ChannelTags)
+	assert.Equal(t, []int{16}, found.BlockedContentTypes)
+	assert.Empty(t, found.ViewingFrom)
+
+	err = repository.Delete(noCtx, testUser.ID)
+	assert.Nil(t, err)
+
+	found, err = repository.Find(noCtx, testUser.ID)
+	assert.Nil(t, err)
+	assert.Nil(t, found)
+}
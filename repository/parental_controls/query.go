@@ -0,0 +1,46 @@
+package parentalcontrols
+
+const queryByUserID = `
+SELECT
+parental_controls.user_id,
+parental_controls.channels,
+parental_controls.channel_tags,
+parental_controls.blocked_content_types,
+parental_controls.viewing_from,
+parental_controls.viewing_to,
+parental_controls.created_at,
+parental_controls.updated_at
+FROM parental_controls
+WHERE parental_controls.user_id = $1
+`
+
+// stmtUpsert creates the parental controls of a user or
+// replaces the existing ones and keeps their creation time.
+const stmtUpsert = `
+INSERT INTO parental_controls (
+user_id,
+channels,
+channel_tags,
+blocked_content_types,
+viewing_from,
+viewing_to,
+created_at,
+updated_at
+) VALUES (
+$1, $2, $3, $4, $5, $6, $7, $7
+)
+ON CONFLICT (user_id) DO UPDATE SET
+channels = $2,
+channel_tags = $3,
+blocked_content_types = $4,
+viewing_from = $5,
+viewing_to = $6,
+updated_at = $7
+RETURNING
+created_at,
+updated_at
+`
+
+const stmtDelete = `
+DELETE FROM parental_controls WHERE user_id = $1
+`
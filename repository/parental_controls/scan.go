@@ -0,0 +1,62 @@
+package parentalcontrols
+
+import (
+	"strconv"
+	"strings"
+
+	"github.com/davidborzek/tvhgo/core"
+	"github.com/davidborzek/tvhgo/repository"
+)
+
+const listSeparator = ","
+
+// Internal helper to scan a sql.Row into a parental controls model.
+func scanRow(scanner repository.Scanner, dest *core.ParentalControls) error {
+	var channels, channelTags, blockedContentTypes string
+
+	err := scanner.Scan(
+		&dest.UserID,
+		&channels,
+		&channelTags,
+		&blockedContentTypes,
+		&dest.ViewingFrom,
+		&dest.ViewingTo,
+		&dest.CreatedAt,
+		&dest.UpdatedAt,
+	)
+	if err != nil {
+		return err
+	}
+
+	dest.Channels = parseList(channels)
+	dest.ChannelTags = parseList(channelTags)
+
+	dest.BlockedContentTypes = []int{}
+	for _, value := range parseList(blockedContentTypes) {
+		contentType, err := strconv.Atoi(value)
+		if err != nil {
+			return err
+		}
+		dest.BlockedContentTypes = append(dest.BlockedContentTypes, contentType)
+	}
+
+	return nil
+}
+
+// Internal helper to parse a stored list.
+func parseList(value string) []string {
+	if value == "" {
+		return []string{}
+	}
+
+	return strings.Split(value, listSeparator)
+}
+
+// Internal helper to format the blocked content types for storage.
+func formatContentTypes(contentTypes []int) string {
+	parts := make([]string, 0, len(contentTypes))
+	for _, contentType := range contentTypes {
+		parts = append(parts, strconv.Itoa(contentType))
+	}
+	return strings.Join(parts, listSeparator)
+}
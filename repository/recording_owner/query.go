@@ -0,0 +1,21 @@
+package recordingowner
+
+const queryUserIDByRecording = `
+SELECT recording_owner.user_id
+FROM recording_owner
+WHERE recording_owner.recording_id = $1
+`
+
+const stmtInsert = `
+INSERT INTO recording_owner (
+recording_id,
+user_id,
+created_at
+) VALUES (
+$1, $2, $3
+)
+`
+
+const stmtDelete = `
+DELETE FROM recording_owner WHERE recording_id = $1
+`
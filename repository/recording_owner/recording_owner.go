@@ -0,0 +1,51 @@
+package recordingowner
+
+import (
+	"context"
+	"database/sql"
+
+	"github.com/davidborzek/tvhgo/core"
+	"github.com/davidborzek/tvhgo/db"
+)
+
+type sqlRepository struct {
+	db    *db.DB
+	clock core.Clock
+}
+
+func New(db *db.DB, clock core.Clock) core.RecordingOwnerRepository {
+	return &sqlRepository{
+		db:    db,
+		clock: clock,
+	}
+}
+
+func (s *sqlRepository) FindUserID(ctx context.Context, recordingID string) (*int64, error) {
+	var userID int64
+
+	err := s.db.QueryRowContext(ctx, queryUserIDByRecording, recordingID).
+		Scan(&userID)
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return nil, nil
+		}
+
+		return nil, err
+	}
+
+	return &userID, nil
+}
+
+func (s *sqlRepository) Create(ctx context.Context, recordingID string, userID int64) error {
+	_, err := s.db.ExecContext(ctx, stmtInsert,
+		recordingID,
+		userID,
+		s.clock.Now().Unix(),
+	)
+	return err
+}
+
+func (s *sqlRepository) Delete(ctx context.Context, recordingID string) error {
+	_, err := s.db.ExecContext(ctx, stmtDelete, recordingID)
+	return err
+}
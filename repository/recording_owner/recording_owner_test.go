@@ -0,0 +1,78 @@
+package recordingowner_test
+
+import (
+	"context"
+	"os"
+	"testing"
+
+	"github.com/davidborzek/tvhgo/core"
+	database "github.com/davidborzek/tvhgo/db"
+	"github.com/davidborzek/tvhgo/db/testdb"
+	recordingowner "github.com/davidborzek/tvhgo/repository/recording_owner"
+	"github.com/davidborzek/tvhgo/repository/user"
+	"github.com/davidborzek/tvhgo/services/clock"
+	"github.com/stretchr/testify/assert"
+)
+
+var (
+	noCtx      = context.TODO()
+	repository core.RecordingOwnerRepository
+
+	testUser = &core.User{
+		Username:    "testuser",
+		Email:       "testuser@example.com",
+		DisplayName: "Test user",
+	}
+)
+
+func initTestUser(db *database.DB) error {
+	return user.New(db, clock.NewClock()).
+		Create(noCtx, testUser)
+}
+
+func TestMain(m *testing.M) {
+	db, err := testdb.Setup()
+	if err != nil {
+		panic(err)
+	}
+	defer testdb.Close(db)
+
+	if err := initTestUser(db); err != nil {
+		panic(err)
+	}
+
+	repository = recordingowner.New(db, clock.NewClock())
+	code := m.Run()
+
+	err = testdb.TruncateTables(db, "recording_owner", "user")
+	if err != nil {
+		panic(err)
+	}
+
+	testdb.Close(db)
+
+	os.Exit(code)
+}
+
+func TestFindUserIDReturnsNil(t *testing.T) {
+	userID, err := repository.FindUserID(noCtx, "unknown")
+
+	assert.Nil(t, err)
+	assert.Nil(t, userID)
+}
+
+func TestCreate(t *testing.T) {
+	err := repository.Create(noCtx, "someRecording", testUser.ID)
+	assert.Nil(t, err)
+
+	userID, err := repository.FindUserID(noCtx, "someRecording")
+	assert.Nil(t, err)
+	assert.Equal(t, testUser.ID, *userID)
+
+	err = repository.Delete(noCtx, "someRecording")
+	assert.Nil(t, err)
+
+	userID, err = repository.FindUserID(noCtx, "someRecording")
+	assert.Nil(t, err)
+	assert.Nil(t, userID)
+}
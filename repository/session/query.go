@@ -10,7 +10,8 @@ SELECT
 "session".user_agent,
 "session".created_at,
 "session".last_used_at,
-"session".rotated_at
+"session".rotated_at,
+"session".device_name
 FROM "session"
 `
 
@@ -36,9 +37,10 @@ client_ip,
 user_agent,
 created_at,
 last_used_at,
-rotated_at
+rotated_at,
+device_name
 ) VALUES (
-$1, $2, $3, $4, $5, $6, $7
+$1, $2, $3, $4, $5, $6, $7, $8
 )
 `
 
@@ -50,9 +52,10 @@ client_ip,
 user_agent,
 created_at,
 last_used_at,
-rotated_at
+rotated_at,
+device_name
 ) VALUES (
-$1, $2, $3, $4, $5, $6, $7
+$1, $2, $3, $4, $5, $6, $7, $8
 ) RETURNING id
 `
 
@@ -74,6 +77,13 @@ WHERE "session".id=$1 AND
 "session".user_id=$2
 `
 
+// Delete sessions of a user statement
+const stmtDeleteByUser = `
+DELETE FROM "session"
+WHERE "session".user_id=$1 AND
+"session".id<>$2
+`
+
 // Delete expired sessions statement
 const stmtDeleteExpired = `
 DELETE FROM "session"
@@ -18,6 +18,7 @@ func scanRow(scanner repository.Scanner, dest *core.Session) error {
 		&dest.CreatedAt,
 		&dest.LastUsedAt,
 		&dest.RotatedAt,
+		&dest.DeviceName,
 	)
 }
 
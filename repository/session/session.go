@@ -64,6 +64,7 @@ func (s *sqlRepository) create(ctx context.Context, session *core.Session) error
 		now,
 		now,
 		now,
+		session.DeviceName,
 	)
 
 	if err != nil {
@@ -93,6 +94,7 @@ func (s *sqlRepository) createPostgres(ctx context.Context, session *core.Sessio
 		now,
 		now,
 		now,
+		session.DeviceName,
 	).Scan(&session.ID)
 
 	if err != nil {
@@ -132,6 +134,30 @@ func (s *sqlRepository) Delete(ctx context.Context, sessionID int64, userID int6
 	return nil
 }
 
+func (s *sqlRepository) DeleteByUser(
+	ctx context.Context,
+	userID int64,
+	exceptSessionID *int64,
+) (int64, error) {
+	// Session ids start at 1, so no session is excluded by 0.
+	var except int64
+	if exceptSessionID != nil {
+		except = *exceptSessionID
+	}
+
+	res, err := s.db.ExecContext(ctx, stmtDeleteByUser, userID, except)
+	if err != nil {
+		return 0, fmt.Errorf("failed to exec session delete by user: %w", err)
+	}
+
+	rows, err := res.RowsAffected()
+	if err != nil {
+		return 0, fmt.Errorf("failed get rows affected count for session delete by user: %w", err)
+	}
+
+	return rows, nil
+}
+
 func (s *sqlRepository) DeleteExpired(
 	ctx context.Context,
 	expirationDate int64,
@@ -76,6 +76,7 @@ func TestCreate(t *testing.T) {
 		HashedToken: "someHashedToken",
 		ClientIP:    "127.0.0.1",
 		UserAgent:   "someUserAgent",
+		DeviceName:  "Living Room TV",
 	}
 
 	err := repository.Create(noCtx, session)
@@ -179,3 +180,28 @@ func TestUniqueSessionTokenHash(t *testing.T) {
 	err = repository.Create(noCtx, session)
 	assert.Contains(t, err.Error(), "UNIQUE constraint failed")
 }
+
+func TestDeleteByUser(t *testing.T) {
+	kept := &core.Session{
+		UserId:      testUser.ID,
+		HashedToken: "keptHashedToken",
+	}
+	assert.Nil(t, repository.Create(noCtx, kept))
+
+	assert.Nil(t, repository.Create(noCtx, &core.Session{
+		UserId:      testUser.ID,
+		HashedToken: "revokedHashedToken",
+	}))
+
+	_, err := repository.DeleteByUser(noCtx, testUser.ID, &kept.ID)
+	assert.Nil(t, err)
+
+	sessions, err := repository.FindByUser(noCtx, testUser.ID)
+	assert.Nil(t, err)
+	assert.Len(t, sessions, 1)
+	assert.Equal(t, kept.ID, sessions[0].ID)
+
+	rows, err := repository.DeleteByUser(noCtx, testUser.ID, nil)
+	assert.Nil(t, err)
+	assert.Equal(t, int64(1), rows)
+}
@@ -6,6 +6,10 @@ token.id,
 token.user_id,
 token.name,
 token.hashed_token,
+token.expires_at,
+token.scopes,
+token.last_used_at,
+token.last_used_ip,
 token.created_at,
 token.updated_at
 FROM token
@@ -24,6 +28,8 @@ INSERT INTO token (
 user_id,
 hashed_token,
 name,
+expires_at,
+scopes,
 created_at,
 updated_at
 ) VALUES (
@@ -31,7 +37,9 @@ $1,
 $2,
 $3,
 $4,
-$5
+$5,
+$6,
+$7
 )
 `
 
@@ -40,6 +48,8 @@ INSERT INTO token (
 user_id,
 hashed_token,
 name,
+expires_at,
+scopes,
 created_at,
 updated_at
 ) VALUES (
@@ -47,10 +57,23 @@ $1,
 $2,
 $3,
 $4,
-$5
+$5,
+$6,
+$7
 ) RETURNING id
 `
 
+const stmtUpdateLastUsed = `
+UPDATE token SET
+last_used_at = $1,
+last_used_ip = $2
+WHERE id = $3
+`
+
 const stmtDelete = `
-DELETE FROM token WHERE id = $1
+DELETE FROM token WHERE id = $1 AND user_id = $2
+`
+
+const stmtDeleteByUser = `
+DELETE FROM token WHERE user_id = $1
 `
@@ -2,21 +2,36 @@ package token
 
 import (
 	"database/sql"
+	"strings"
 
 	"github.com/davidborzek/tvhgo/core"
 	"github.com/davidborzek/tvhgo/repository"
 )
 
+const scopeSeparator = ","
+
 // Internal helper to scan a sql.Row into a token model.
 func scanRow(scanner repository.Scanner, dest *core.Token) error {
-	return scanner.Scan(
+	var scopes string
+
+	err := scanner.Scan(
 		&dest.ID,
 		&dest.UserID,
 		&dest.Name,
 		&dest.HashedToken,
+		&dest.ExpiresAt,
+		&scopes,
+		&dest.LastUsedAt,
+		&dest.LastUsedIP,
 		&dest.CreatedAt,
 		&dest.UpdatedAt,
 	)
+	if err != nil {
+		return err
+	}
+
+	dest.Scopes = parseScopes(scopes)
+	return nil
 }
 
 // Internal helper to scan sql.Rows into an array of user models.
@@ -33,3 +48,27 @@ func scanRows(rows *sql.Rows) ([]*core.Token, error) {
 	}
 	return tokens, nil
 }
+
+// Internal helper to parse the stored scopes of a token.
+// An empty value results in an unrestricted token.
+func parseScopes(value string) []core.Permission {
+	if value == "" {
+		return nil
+	}
+
+	parts := strings.Split(value, scopeSeparator)
+	scopes := make([]core.Permission, 0, len(parts))
+	for _, part := range parts {
+		scopes = append(scopes, core.Permission(part))
+	}
+	return scopes
+}
+
+// Internal helper to format the scopes of a token for storage.
+func formatScopes(scopes []core.Permission) string {
+	parts := make([]string, 0, len(scopes))
+	for _, scope := range scopes {
+		parts = append(parts, string(scope))
+	}
+	return strings.Join(parts, scopeSeparator)
+}
@@ -3,7 +3,6 @@ package token
 import (
 	"context"
 	"database/sql"
-	"time"
 
 	"github.com/davidborzek/tvhgo/config"
 	"github.com/davidborzek/tvhgo/core"
@@ -11,11 +10,12 @@ import (
 )
 
 type sqlRepository struct {
-	db *db.DB
+	db    *db.DB
+	clock core.Clock
 }
 
-func New(db *db.DB) core.TokenRepository {
-	return &sqlRepository{db: db}
+func New(db *db.DB, clock core.Clock) core.TokenRepository {
+	return &sqlRepository{db: db, clock: clock}
 }
 
 func (s *sqlRepository) FindByToken(ctx context.Context, hashedToken string) (*core.Token, error) {
@@ -50,12 +50,14 @@ func (s *sqlRepository) Create(ctx context.Context, token *core.Token) error {
 }
 
 func (s *sqlRepository) create(ctx context.Context, token *core.Token) error {
-	now := time.Now().Unix()
+	now := s.clock.Now().Unix()
 
 	res, err := s.db.ExecContext(ctx, stmtInsert,
 		token.UserID,
 		token.HashedToken,
 		token.Name,
+		token.ExpiresAt,
+		formatScopes(token.Scopes),
 		now,
 		now,
 	)
@@ -76,12 +78,14 @@ func (s *sqlRepository) create(ctx context.Context, token *core.Token) error {
 }
 
 func (s *sqlRepository) createPostgres(ctx context.Context, token *core.Token) error {
-	now := time.Now().Unix()
+	now := s.clock.Now().Unix()
 
 	err := s.db.QueryRowContext(ctx, stmtInsertPostgres,
 		token.UserID,
 		token.HashedToken,
 		token.Name,
+		token.ExpiresAt,
+		formatScopes(token.Scopes),
 		now,
 		now,
 	).Scan(&token.ID)
@@ -95,7 +99,25 @@ func (s *sqlRepository) createPostgres(ctx context.Context, token *core.Token) e
 	return nil
 }
 
+func (s *sqlRepository) UpdateLastUsed(ctx context.Context, token *core.Token) error {
+	_, err := s.db.ExecContext(ctx, stmtUpdateLastUsed,
+		token.LastUsedAt,
+		token.LastUsedIP,
+		token.ID,
+	)
+	return err
+}
+
 func (s *sqlRepository) Delete(ctx context.Context, token *core.Token) error {
-	_, err := s.db.ExecContext(ctx, stmtDelete, token.ID)
+	_, err := s.db.ExecContext(ctx, stmtDelete, token.ID, token.UserID)
 	return err
 }
+
+func (s *sqlRepository) DeleteByUser(ctx context.Context, userID int64) (int64, error) {
+	res, err := s.db.ExecContext(ctx, stmtDeleteByUser, userID)
+	if err != nil {
+		return 0, err
+	}
+
+	return res.RowsAffected()
+}
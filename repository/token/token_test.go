@@ -42,7 +42,7 @@ func TestMain(m *testing.M) {
 		panic(err)
 	}
 
-	repository = token.New(db)
+	repository = token.New(db, clock.NewClock())
 	code := m.Run()
 
 	err = testdb.TruncateTables(db, "token", "user")
@@ -81,9 +81,51 @@ func TestCreate(t *testing.T) {
 
 	t.Run("Find", testFind(token))
 	t.Run("FindByUser", testFindByUser(token))
+	t.Run("UpdateLastUsed", testUpdateLastUsed(token))
 	t.Run("Delete", testDelete(token))
 }
 
+func TestCreateWithScopesAndExpiry(t *testing.T) {
+	expiresAt := int64(1672527600)
+	token := &core.Token{
+		UserID:      testUser.ID,
+		HashedToken: "someScopedToken",
+		Name:        "someName",
+		ExpiresAt:   &expiresAt,
+		Scopes: []core.Permission{
+			core.PermissionEPGRead,
+			core.PermissionRecordingsWrite,
+		},
+	}
+	err := repository.Create(noCtx, token)
+	assert.Nil(t, err)
+
+	found, err := repository.FindByToken(noCtx, token.HashedToken)
+	assert.Nil(t, err)
+	assert.Equal(t, token, found)
+
+	assert.Nil(t, repository.Delete(noCtx, token))
+}
+
+func TestDeleteByUser(t *testing.T) {
+	for _, hashedToken := range []string{"someToken1", "someToken2"} {
+		err := repository.Create(noCtx, &core.Token{
+			UserID:      testUser.ID,
+			HashedToken: hashedToken,
+			Name:        "someName",
+		})
+		assert.Nil(t, err)
+	}
+
+	rows, err := repository.DeleteByUser(noCtx, testUser.ID)
+	assert.Nil(t, err)
+	assert.Equal(t, int64(2), rows)
+
+	tokens, err := repository.FindByUser(noCtx, testUser.ID)
+	assert.Nil(t, err)
+	assert.Empty(t, tokens)
+}
+
 func testFind(created *core.Token) func(t *testing.T) {
 	return func(t *testing.T) {
 		token, err := repository.FindByToken(noCtx, created.HashedToken)
@@ -103,9 +145,33 @@ func testFindByUser(created *core.Token) func(t *testing.T) {
 	}
 }
 
+func testUpdateLastUsed(created *core.Token) func(t *testing.T) {
+	return func(t *testing.T) {
+		lastUsedAt := int64(1672527600)
+		lastUsedIP := "192.168.1.1"
+		created.LastUsedAt = &lastUsedAt
+		created.LastUsedIP = &lastUsedIP
+
+		err := repository.UpdateLastUsed(noCtx, created)
+		assert.Nil(t, err)
+
+		token, err := repository.FindByToken(noCtx, created.HashedToken)
+		assert.Nil(t, err)
+		assert.Equal(t, created, token)
+	}
+}
+
 func testDelete(created *core.Token) func(t *testing.T) {
 	return func(t *testing.T) {
-		err := repository.Delete(noCtx, created)
+		// Tokens of other users are not deleted.
+		err := repository.Delete(noCtx, &core.Token{ID: created.ID, UserID: created.UserID + 1})
+		assert.Nil(t, err)
+
+		token, err := repository.FindByToken(noCtx, created.HashedToken)
+		assert.Nil(t, err)
+		assert.NotNil(t, token)
+
+		err = repository.Delete(noCtx, created)
 
 		assert.Nil(t, err)
 
@@ -0,0 +1,74 @@
+package trusteddevice
+
+// Base select query
+const queryBase = `
+SELECT
+trusted_device.id,
+trusted_device.user_id,
+trusted_device.hashed_token,
+trusted_device.client_ip,
+trusted_device.user_agent,
+trusted_device.expires_at,
+trusted_device.last_used_at,
+trusted_device.created_at
+FROM trusted_device
+`
+
+// Select trusted device by hashed token
+const queryByToken = queryBase + `
+WHERE
+trusted_device.hashed_token = $1
+`
+
+// Select trusted devices of a user
+const queryByUserID = queryBase + `
+WHERE
+trusted_device.user_id = $1
+ORDER BY trusted_device.last_used_at DESC
+`
+
+// Insert trusted device statement
+const stmtInsert = `
+INSERT INTO trusted_device (
+user_id,
+hashed_token,
+client_ip,
+user_agent,
+expires_at,
+last_used_at,
+created_at
+) VALUES (
+$1, $2, $3, $4, $5, $6, $7
+)
+`
+
+const stmtInsertPostgres = stmtInsert + `
+RETURNING id
+`
+
+// Update trusted device statement
+const stmtUpdate = `
+UPDATE trusted_device SET
+client_ip = $1,
+user_agent = $2,
+last_used_at = $3
+WHERE id = $4
+`
+
+// Delete trusted device of a user statement
+const stmtDelete = `
+DELETE FROM trusted_device
+WHERE trusted_device.id = $1 AND trusted_device.user_id = $2
+`
+
+// Delete trusted devices of a user statement
+const stmtDeleteByUser = `
+DELETE FROM trusted_device
+WHERE trusted_device.user_id = $1
+`
+
+// Delete expired trusted devices statement
+const stmtDeleteExpired = `
+DELETE FROM trusted_device
+WHERE trusted_device.expires_at < $1
+`
@@ -0,0 +1,37 @@
+package trusteddevice
+
+import (
+	"database/sql"
+
+	"github.com/davidborzek/tvhgo/core"
+	"github.com/davidborzek/tvhgo/repository"
+)
+
+// Internal helper to scan a sql.Row into a trusted device model.
+func scanRow(scanner repository.Scanner, dest *core.TrustedDevice) error {
+	return scanner.Scan(
+		&dest.ID,
+		&dest.UserID,
+		&dest.HashedToken,
+		&dest.ClientIP,
+		&dest.UserAgent,
+		&dest.ExpiresAt,
+		&dest.LastUsedAt,
+		&dest.CreatedAt,
+	)
+}
+
+// Internal helper to scan sql.Rows into an array of trusted device models.
+func scanRows(rows *sql.Rows) ([]*core.TrustedDevice, error) {
+	defer rows.Close()
+
+	devices := []*core.TrustedDevice{}
+	for rows.Next() {
+		device := new(core.TrustedDevice)
+		if err := scanRow(rows, device); err != nil {
+			return nil, err
+		}
+		devices = append(devices, device)
+	}
+	return devices, nil
+}
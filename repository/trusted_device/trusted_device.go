@@ -0,0 +1,110 @@
+package trusteddevice
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+
+	"github.com/davidborzek/tvhgo/config"
+	"github.com/davidborzek/tvhgo/core"
+	"github.com/davidborzek/tvhgo/db"
+)
+
+type sqlRepository struct {
+	db    *db.DB
+	clock core.Clock
+}
+
+func New(db *db.DB, clock core.Clock) core.TrustedDeviceRepository {
+	return &sqlRepository{db: db, clock: clock}
+}
+
+func (s *sqlRepository) Find(ctx context.Context, hashedToken string) (*core.TrustedDevice, error) {
+	row := s.db.QueryRowContext(ctx, queryByToken, hashedToken)
+
+	device := new(core.TrustedDevice)
+	if err := scanRow(row, device); err != nil {
+		if err == sql.ErrNoRows {
+			return nil, nil
+		}
+
+		return nil, err
+	}
+	return device, nil
+}
+
+func (s *sqlRepository) FindByUser(ctx context.Context, userID int64) ([]*core.TrustedDevice, error) {
+	rows, err := s.db.QueryContext(ctx, queryByUserID, userID)
+	if err != nil {
+		return nil, fmt.Errorf("trusted device FindByUser query failed: %w", err)
+	}
+
+	return scanRows(rows)
+}
+
+func (s *sqlRepository) Create(ctx context.Context, device *core.TrustedDevice) error {
+	now := s.clock.Now().Unix()
+	device.CreatedAt = now
+	device.LastUsedAt = now
+
+	args := []any{
+		device.UserID,
+		device.HashedToken,
+		device.ClientIP,
+		device.UserAgent,
+		device.ExpiresAt,
+		device.LastUsedAt,
+		device.CreatedAt,
+	}
+
+	if s.db.Type == config.DatabaseTypePostgres {
+		return s.db.QueryRowContext(ctx, stmtInsertPostgres, args...).
+			Scan(&device.ID)
+	}
+
+	res, err := s.db.ExecContext(ctx, stmtInsert, args...)
+	if err != nil {
+		return err
+	}
+
+	id, err := res.LastInsertId()
+	if err != nil {
+		return err
+	}
+
+	device.ID = id
+	return nil
+}
+
+func (s *sqlRepository) Update(ctx context.Context, device *core.TrustedDevice) error {
+	_, err := s.db.ExecContext(ctx, stmtUpdate,
+		device.ClientIP,
+		device.UserAgent,
+		device.LastUsedAt,
+		device.ID,
+	)
+	return err
+}
+
+func (s *sqlRepository) Delete(ctx context.Context, id int64, userID int64) error {
+	_, err := s.db.ExecContext(ctx, stmtDelete, id, userID)
+	return err
+}
+
+func (s *sqlRepository) DeleteByUser(ctx context.Context, userID int64) (int64, error) {
+	res, err := s.db.ExecContext(ctx, stmtDeleteByUser, userID)
+	if err != nil {
+		return 0, err
+	}
+
+	return res.RowsAffected()
+}
+
+func (s *sqlRepository) DeleteExpired(ctx context.Context, before int64) (int64, error) {
+	res, err := s.db.ExecContext(ctx, stmtDeleteExpired, before)
+	if err != nil {
+		return 0, err
+	}
+
+	return res.RowsAffected()
+}
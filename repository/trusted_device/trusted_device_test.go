@@ -0,0 +1,172 @@
+package trusteddevice_test
+
+import (
+	"context"
+	"os"
+	"testing"
+
+	"github.com/davidborzek/tvhgo/core"
+	database "github.com/davidborzek/tvhgo/db"
+	"github.com/davidborzek/tvhgo/db/testdb"
+	trusteddevice "github.com/davidborzek/tvhgo/repository/trusted_device"
+	"github.com/davidborzek/tvhgo/repository/user"
+	"github.com/davidborzek/tvhgo/services/clock"
+	"github.com/stretchr/testify/assert"
+)
+
+var (
+	noCtx      = context.TODO()
+	repository core.TrustedDeviceRepository
+
+	testUser = &core.User{
+		ID:          1234,
+		Username:    "testuser",
+		Email:       "testuser@example.com",
+		DisplayName: "Test user",
+	}
+)
+
+func initTestUser(db *database.DB) error {
+	return user.New(db, clock.NewClock()).
+		Create(noCtx, testUser)
+}
+
+func TestMain(m *testing.M) {
+	db, err := testdb.Setup()
+	if err != nil {
+		panic(err)
+	}
+	defer testdb.Close(db)
+
+	if err := initTestUser(db); err != nil {
+		panic(err)
+	}
+
+	repository = trusteddevice.New(db, clock.NewClock())
+	code := m.Run()
+
+	err = testdb.TruncateTables(db, "trusted_device", "user")
+	if err != nil {
+		panic(err)
+	}
+
+	testdb.Close(db)
+
+	os.Exit(code)
+}
+
+func TestFindReturnsNil(t *testing.T) {
+	device, err := repository.Find(noCtx, "unknownToken")
+	assert.Nil(t, err)
+	assert.Nil(t, device)
+}
+
+func TestCreate(t *testing.T) {
+	device := &core.TrustedDevice{
+		UserID:      testUser.ID,
+		HashedToken: "someHashedToken",
+		ClientIP:    "192.168.1.10",
+		UserAgent:   "Living Room TV",
+		ExpiresAt:   1000,
+	}
+
+	err := repository.Create(noCtx, device)
+	assert.Nil(t, err)
+	assert.NotEmpty(t, device.ID)
+	assert.NotEmpty(t, device.CreatedAt)
+	assert.Equal(t, device.CreatedAt, device.LastUsedAt)
+
+	t.Run("Find", testFind(device))
+	t.Run("Update", testUpdate(device))
+	t.Run("Delete", testDelete(device))
+}
+
+func testFind(created *core.TrustedDevice) func(t *testing.T) {
+	return func(t *testing.T) {
+		device, err := repository.Find(noCtx, created.HashedToken)
+		assert.Nil(t, err)
+		assert.Equal(t, created, device)
+
+		devices, err := repository.FindByUser(noCtx, testUser.ID)
+		assert.Nil(t, err)
+		assert.Equal(t, []*core.TrustedDevice{created}, devices)
+	}
+}
+
+func testUpdate(created *core.TrustedDevice) func(t *testing.T) {
+	return func(t *testing.T) {
+		created.ClientIP = "192.168.1.11"
+		created.UserAgent = "Kitchen TV"
+		created.LastUsedAt = 500
+
+		err := repository.Update(noCtx, created)
+		assert.Nil(t, err)
+
+		device, err := repository.Find(noCtx, created.HashedToken)
+		assert.Nil(t, err)
+		assert.Equal(t, created, device)
+	}
+}
+
+func testDelete(created *core.TrustedDevice) func(t *testing.T) {
+	return func(t *testing.T) {
+		err := repository.Delete(noCtx, created.ID, testUser.ID+1)
+		assert.Nil(t, err)
+
+		device, err := repository.Find(noCtx, created.HashedToken)
+		assert.Nil(t, err)
+		assert.NotNil(t, device)
+
+		err = repository.Delete(noCtx, created.ID, testUser.ID)
+		assert.Nil(t, err)
+
+		device, err = repository.Find(noCtx, created.HashedToken)
+		assert.Nil(t, err)
+		assert.Nil(t, device)
+	}
+}
+
+func TestDeleteByUser(t *testing.T) {
+	for _, token := range []string{"firstToken", "secondToken"} {
+		assert.Nil(t, repository.Create(noCtx, &core.TrustedDevice{
+			UserID:      testUser.ID,
+			HashedToken: token,
+			ExpiresAt:   1000,
+		}))
+	}
+
+	deleted, err := repository.DeleteByUser(noCtx, testUser.ID)
+	assert.Nil(t, err)
+	assert.Equal(t, int64(2), deleted)
+
+	devices, err := repository.FindByUser(noCtx, testUser.ID)
+	assert.Nil(t, err)
+	assert.Empty(t, devices)
+}
+
+func TestDeleteExpired(t *testing.T) {
+	expired := &core.TrustedDevice{
+		UserID:      testUser.ID,
+		HashedToken: "expiredToken",
+		ExpiresAt:   100,
+	}
+	valid := &core.TrustedDevice{
+		UserID:      testUser.ID,
+		HashedToken: "validToken",
+		ExpiresAt:   300,
+	}
+	assert.Nil(t, repository.Create(noCtx, expired))
+	assert.Nil(t, repository.Create(noCtx, valid))
+
+	deleted, err := repository.DeleteExpired(noCtx, 200)
+	assert.Nil(t, err)
+	assert.Equal(t, int64(1), deleted)
+
+	device, err := repository.Find(noCtx, expired.HashedToken)
+	assert.Nil(t, err)
+	assert.Nil(t, device)
+
+	device, err = repository.Find(noCtx, valid.HashedToken)
+	assert.Nil(t, err)
+	assert.Equal(t, valid, device)
+}
@@ -0,0 +1,19 @@
+package twofactorrecoverycode
+
+const stmtInsert = `
+INSERT INTO two_factor_recovery_code (
+user_id,
+hashed_code,
+created_at
+) VALUES (
+$1, $2, $3
+)
+`
+
+const stmtDelete = `
+DELETE FROM two_factor_recovery_code WHERE user_id = $1 AND hashed_code = $2
+`
+
+const stmtDeleteByUser = `
+DELETE FROM two_factor_recovery_code WHERE user_id = $1
+`
@@ -0,0 +1,60 @@
+package twofactorrecoverycode
+
+import (
+	"context"
+
+	"github.com/davidborzek/tvhgo/core"
+	"github.com/davidborzek/tvhgo/db"
+)
+
+type sqlRepository struct {
+	db    *db.DB
+	clock core.Clock
+}
+
+func New(db *db.DB, clock core.Clock) core.TwoFactorRecoveryCodeRepository {
+	return &sqlRepository{
+		db:    db,
+		clock: clock,
+	}
+}
+
+func (s *sqlRepository) Replace(ctx context.Context, userID int64, hashedCodes []string) error {
+	tx, err := s.db.BeginTx(ctx, nil)
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	if _, err := tx.ExecContext(ctx, stmtDeleteByUser, userID); err != nil {
+		return err
+	}
+
+	now := s.clock.Now().Unix()
+	for _, hashedCode := range hashedCodes {
+		if _, err := tx.ExecContext(ctx, stmtInsert, userID, hashedCode, now); err != nil {
+			return err
+		}
+	}
+
+	return tx.Commit()
+}
+
+func (s *sqlRepository) Consume(ctx context.Context, userID int64, hashedCode string) (bool, error) {
+	res, err := s.db.ExecContext(ctx, stmtDelete, userID, hashedCode)
+	if err != nil {
+		return false, err
+	}
+
+	rows, err := res.RowsAffected()
+	if err != nil {
+		return false, err
+	}
+
+	return rows > 0, nil
+}
+
+func (s *sqlRepository) DeleteByUser(ctx context.Context, userID int64) error {
+	_, err := s.db.ExecContext(ctx, stmtDeleteByUser, userID)
+	return err
+}
@@ -0,0 +1,112 @@
+package twofactorrecoverycode_test
+
+import (
+	"context"
+	"os"
+	"testing"
+
+	"github.com/davidborzek/tvhgo/core"
+	database "github.com/davidborzek/tvhgo/db"
+	"github.com/davidborzek/tvhgo/db/testdb"
+	twofactorrecoverycode "github.com/davidborzek/tvhgo/repository/two_factor_recovery_code"
+	"github.com/davidborzek/tvhgo/repository/user"
+	"github.com/davidborzek/tvhgo/services/clock"
+	"github.com/stretchr/testify/assert"
+)
+
+var (
+	noCtx      = context.TODO()
+	repository core.TwoFactorRecoveryCodeRepository
+
+	testUser = &core.User{
+		Username:    "testuser",
+		Email:       "testuser@example.com",
+		DisplayName: "Test user",
+	}
+)
+
+func initTestUser(db *database.DB) error {
+	return user.New(db, clock.NewClock()).
+		Create(noCtx, testUser)
+}
+
+func TestMain(m *testing.M) {
+	db, err := testdb.Setup()
+	if err != nil {
+		panic(err)
+	}
+	defer testdb.Close(db)
+
+	if err := initTestUser(db); err != nil {
+		panic(err)
+	}
+
+	repository = twofactorrecoverycode.New(db, clock.NewClock())
+	code := m.Run()
+
+	err = testdb.TruncateTables(db, "two_factor_recovery_code", "user")
+	if err != nil {
+		panic(err)
+	}
+
+	testdb.Close(db)
+
+	os.Exit(code)
+}
+
+func TestConsumeReturnsFalseForUnknownCode(t *testing.T) {
+	ok, err := repository.Consume(noCtx, testUser.ID, "unknown")
+
+	assert.Nil(t, err)
+	assert.False(t, ok)
+}
+
+func TestReplace(t *testing.T) {
+	err := repository.Replace(noCtx, testUser.ID, []string{"first", "second"})
+	assert.Nil(t, err)
+
+	err = repository.Replace(noCtx, testUser.ID, []string{"third"})
+	assert.Nil(t, err)
+
+	ok, err := repository.Consume(noCtx, testUser.ID, "first")
+	assert.Nil(t, err)
+	assert.False(t, ok)
+
+	ok, err = repository.Consume(noCtx, testUser.ID, "third")
+	assert.Nil(t, err)
+	assert.True(t, ok)
+}
+
+func TestConsumeOnlyOnce(t *testing.T) {
+	err := repository.Replace(noCtx, testUser.ID, []string{"someCode"})
+	assert.Nil(t, err)
+
+	ok, err := repository.Consume(noCtx, testUser.ID, "someCode")
+	assert.Nil(t, err)
+	assert.True(t, ok)
+
+	ok, err = repository.Consume(noCtx, testUser.ID, "someCode")
+	assert.Nil(t, err)
+	assert.False(t, ok)
+}
+
+func TestConsumeRequiresMatchingUser(t *testing.T) {
+	err := repository.Replace(noCtx, testUser.ID, []string{"someCode"})
+	assert.Nil(t, err)
+
+	ok, err := repository.Consume(noCtx, testUser.ID+1, "someCode")
+	assert.Nil(t, err)
+	assert.False(t, ok)
+}
+
+func TestDeleteByUser(t *testing.T) {
+	err := repository.Replace(noCtx, testUser.ID, []string{"someCode"})
+	assert.Nil(t, err)
+
+	err = repository.DeleteByUser(noCtx, testUser.ID)
+	assert.Nil(t, err)
+
+	ok, err := repository.Consume(noCtx, testUser.ID, "someCode")
+	assert.Nil(t, err)
+	assert.False(t, ok)
+}
@@ -33,6 +33,19 @@ updated_at = $3
 WHERE user_id = $4
 `
 
+const querySecrets = `
+SELECT
+two_factor_settings.user_id,
+two_factor_settings.secret
+FROM two_factor_settings
+`
+
+const stmtUpdateSecret = `
+UPDATE two_factor_settings SET
+secret = $1
+WHERE user_id = $2
+`
+
 const stmtDelete = `
 DELETE FROM two_factor_settings WHERE user_id = $1
 `
@@ -3,6 +3,7 @@ package twofactorsettings
 import (
 	"context"
 	"database/sql"
+	"strconv"
 	"time"
 
 	"github.com/davidborzek/tvhgo/core"
@@ -10,15 +11,25 @@ import (
 )
 
 type sqlRepository struct {
-	db *db.DB
+	db        *db.DB
+	encryptor core.Encryptor
 }
 
-func New(db *db.DB) core.TwoFactorSettingsRepository {
+// New creates a new TwoFactorSettingsRepository, which
+// encrypts the secrets with the encryptor.
+func New(db *db.DB, encryptor core.Encryptor) core.TwoFactorSettingsRepository {
 	return &sqlRepository{
-		db: db,
+		db:        db,
+		encryptor: encryptor,
 	}
 }
 
+// secretOwner returns the associated data of the secret of a user,
+// so that a secret can't be decrypted in the row of another user.
+func secretOwner(userID int64) string {
+	return "two_factor_settings:" + strconv.FormatInt(userID, 10)
+}
+
 func (s *sqlRepository) Find(ctx context.Context, userID int64) (*core.TwoFactorSettings, error) {
 	row := s.db.QueryRowContext(ctx, queryByUserID, userID)
 	settings := new(core.TwoFactorSettings)
@@ -29,15 +40,27 @@ func (s *sqlRepository) Find(ctx context.Context, userID int64) (*core.TwoFactor
 
 		return nil, err
 	}
+
+	secret, err := s.encryptor.Decrypt(settings.Secret, secretOwner(settings.UserID))
+	if err != nil {
+		return nil, err
+	}
+
+	settings.Secret = secret
 	return settings, nil
 }
 
 func (s *sqlRepository) Create(ctx context.Context, settings *core.TwoFactorSettings) error {
 	createdAt := time.Now().Unix()
 
-	_, err := s.db.ExecContext(ctx, stmtInsert,
+	secret, err := s.encryptor.Encrypt(settings.Secret, secretOwner(settings.UserID))
+	if err != nil {
+		return err
+	}
+
+	_, err = s.db.ExecContext(ctx, stmtInsert,
 		settings.UserID,
-		settings.Secret,
+		secret,
 		settings.Enabled,
 		createdAt,
 		createdAt,
@@ -60,8 +83,13 @@ func (s *sqlRepository) Delete(ctx context.Context, settings *core.TwoFactorSett
 func (s *sqlRepository) Update(ctx context.Context, settings *core.TwoFactorSettings) error {
 	updatedAt := time.Now().Unix()
 
-	_, err := s.db.ExecContext(ctx, stmtUpdate,
-		settings.Secret,
+	secret, err := s.encryptor.Encrypt(settings.Secret, secretOwner(settings.UserID))
+	if err != nil {
+		return err
+	}
+
+	_, err = s.db.ExecContext(ctx, stmtUpdate,
+		secret,
 		settings.Enabled,
 		updatedAt,
 		settings.UserID,
@@ -87,3 +115,57 @@ func (s *sqlRepository) Save(ctx context.Context, settings *core.TwoFactorSettin
 
 	return s.Update(ctx, settings)
 }
+
+func (s *sqlRepository) Reencrypt(ctx context.Context) (int, error) {
+	tx, err := s.db.BeginTx(ctx, nil)
+	if err != nil {
+		return 0, err
+	}
+	defer tx.Rollback()
+
+	rows, err := tx.QueryContext(ctx, querySecrets)
+	if err != nil {
+		return 0, err
+	}
+
+	secrets := make(map[int64]string)
+	for rows.Next() {
+		var userID int64
+		var secret string
+		if err := rows.Scan(&userID, &secret); err != nil {
+			rows.Close()
+			return 0, err
+		}
+
+		if s.encryptor.NeedsReencryption(secret) {
+			secrets[userID] = secret
+		}
+	}
+	rows.Close()
+
+	if err := rows.Err(); err != nil {
+		return 0, err
+	}
+
+	for userID, secret := range secrets {
+		plaintext, err := s.encryptor.Decrypt(secret, secretOwner(userID))
+		if err != nil {
+			return 0, err
+		}
+
+		encrypted, err := s.encryptor.Encrypt(plaintext, secretOwner(userID))
+		if err != nil {
+			return 0, err
+		}
+
+		if _, err := tx.ExecContext(ctx, stmtUpdateSecret, encrypted, userID); err != nil {
+			return 0, err
+		}
+	}
+
+	if err := tx.Commit(); err != nil {
+		return 0, err
+	}
+
+	return len(secrets), nil
+}
@@ -5,17 +5,25 @@ import (
 	"os"
 	"testing"
 
+	"github.com/davidborzek/tvhgo/config"
 	"github.com/davidborzek/tvhgo/core"
 	database "github.com/davidborzek/tvhgo/db"
 	"github.com/davidborzek/tvhgo/db/testdb"
 	twofactorsettings "github.com/davidborzek/tvhgo/repository/two_factor_settings"
 	"github.com/davidborzek/tvhgo/repository/user"
 	"github.com/davidborzek/tvhgo/services/clock"
+	"github.com/davidborzek/tvhgo/services/encryption"
 	"github.com/stretchr/testify/assert"
 )
 
+const (
+	oldKey     = "k9O6ZYnWgmq8RkQ4gOZcjX0bYvE2xq6e5OZ0zYcQdS0="
+	currentKey = "3Xb4yJt0hN1bqk8m4u5Y7v2cB6n9QeRtYuIoPaSdF0g="
+)
+
 var (
 	noCtx      = context.TODO()
+	db         *database.DB
 	repository core.TwoFactorSettingsRepository
 
 	testUser = &core.User{
@@ -31,7 +39,8 @@ func initTestUser(db *database.DB) error {
 }
 
 func TestMain(m *testing.M) {
-	db, err := testdb.Setup()
+	var err error
+	db, err = testdb.Setup()
 	if err != nil {
 		panic(err)
 	}
@@ -41,7 +50,7 @@ func TestMain(m *testing.M) {
 		panic(err)
 	}
 
-	repository = twofactorsettings.New(db)
+	repository = twofactorsettings.New(db, newEncryptor(currentKey))
 	code := m.Run()
 
 	err = testdb.TruncateTables(db, "two_factor_settings", "user")
@@ -54,6 +63,23 @@ func TestMain(m *testing.M) {
 	os.Exit(code)
 }
 
+func newEncryptor(keys ...string) core.Encryptor {
+	encryptor, err := encryption.New(&config.EncryptionConfig{Keys: keys})
+	if err != nil {
+		panic(err)
+	}
+	return encryptor
+}
+
+func findStoredSecret(userID int64) string {
+	var secret string
+	row := db.QueryRow("SELECT secret FROM two_factor_settings WHERE user_id = $1", userID)
+	if err := row.Scan(&secret); err != nil {
+		panic(err)
+	}
+	return secret
+}
+
 func TestFindReturnsNil(t *testing.T) {
 	settings, err := repository.Find(noCtx, 0)
 
@@ -70,6 +96,7 @@ func TestCreate(t *testing.T) {
 	err := repository.Save(noCtx, settings)
 
 	assert.Nil(t, err)
+	assert.NotContains(t, findStoredSecret(testUser.ID), "someSecret")
 
 	t.Run("Find", testFind(settings))
 	t.Run("Update", testUpdate(settings))
@@ -119,3 +146,78 @@ func testDelete(created *core.TwoFactorSettings) func(t *testing.T) {
 		assert.Nil(t, settings)
 	}
 }
+
+func TestFindRejectsSecretOfAnotherUser(t *testing.T) {
+	otherUser := &core.User{
+		Username:    "otheruser",
+		Email:       "otheruser@example.com",
+		DisplayName: "Other user",
+	}
+	users := user.New(db, clock.NewClock())
+	assert.Nil(t, users.Create(noCtx, otherUser))
+	defer users.Delete(noCtx, otherUser)
+
+	for _, settings := range []*core.TwoFactorSettings{
+		{UserID: testUser.ID, Secret: "someSecret", Enabled: true},
+		{UserID: otherUser.ID, Secret: "otherSecret", Enabled: true},
+	} {
+		assert.Nil(t, repository.Save(noCtx, settings))
+		defer repository.Delete(noCtx, settings)
+	}
+
+	_, err := db.Exec("UPDATE two_factor_settings SET secret = $1 WHERE user_id = $2",
+		findStoredSecret(testUser.ID), otherUser.ID)
+	assert.Nil(t, err)
+
+	_, err = repository.Find(noCtx, otherUser.ID)
+	assert.Equal(t, core.ErrEncryptionValueInvalid, err)
+}
+
+func TestReencrypt(t *testing.T) {
+	oldRepository := twofactorsettings.New(db, newEncryptor(oldKey))
+	err := oldRepository.Save(noCtx, &core.TwoFactorSettings{
+		UserID:  testUser.ID,
+		Secret:  "someSecret",
+		Enabled: true,
+	})
+	assert.Nil(t, err)
+	defer repository.Delete(noCtx, &core.TwoFactorSettings{UserID: testUser.ID})
+
+	_, err = repository.Find(noCtx, testUser.ID)
+	assert.Equal(t, core.ErrEncryptionKeyNotFound, err)
+
+	rotatingRepository := twofactorsettings.New(db, newEncryptor(currentKey, oldKey))
+	count, err := rotatingRepository.Reencrypt(noCtx)
+	assert.Nil(t, err)
+	assert.Equal(t, 1, count)
+
+	settings, err := repository.Find(noCtx, testUser.ID)
+	assert.Nil(t, err)
+	assert.Equal(t, "someSecret", settings.Secret)
+
+	count, err = rotatingRepository.Reencrypt(noCtx)
+	assert.Nil(t, err)
+	assert.Equal(t, 0, count)
+}
+
+func TestReencryptUnencryptedSecrets(t *testing.T) {
+	plainRepository := twofactorsettings.New(db, newEncryptor())
+	err := plainRepository.Save(noCtx, &core.TwoFactorSettings{
+		UserID:  testUser.ID,
+		Secret:  "someSecret",
+		Enabled: true,
+	})
+	assert.Nil(t, err)
+	defer repository.Delete(noCtx, &core.TwoFactorSettings{UserID: testUser.ID})
+
+	assert.Equal(t, "someSecret", findStoredSecret(testUser.ID))
+
+	count, err := repository.Reencrypt(noCtx)
+	assert.Nil(t, err)
+	assert.Equal(t, 1, count)
+	assert.NotEqual(t, "someSecret", findStoredSecret(testUser.ID))
+
+	settings, err := repository.Find(noCtx, testUser.ID)
+	assert.Nil(t, err)
+	assert.Equal(t, "someSecret", settings.Secret)
+}
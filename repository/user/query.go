@@ -13,7 +13,8 @@ SELECT
 "user".password_hash,
 "user".email,
 "user".display_name,
-"user".is_admin,
+"user".role,
+"user".disabled,
 "user".created_at,
 "user".updated_at,
 two_factor_settings.enabled
@@ -46,11 +47,12 @@ username,
 password_hash,
 email,
 display_name,
-is_admin,
+role,
+disabled,
 created_at,
 updated_at
 ) VALUES (
-$1, $2, $3, $4, $5, $6, $7
+$1, $2, $3, $4, $5, $6, $7, $8
 )
 `
 
@@ -61,11 +63,12 @@ username,
 password_hash,
 email,
 display_name,
-is_admin,
+role,
+disabled,
 created_at,
 updated_at
 ) VALUES (
-$1, $2, $3, $4, $5, $6, $7
+$1, $2, $3, $4, $5, $6, $7, $8
 ) RETURNING id
 `
 
@@ -82,7 +85,8 @@ username = $1,
 password_hash = $2,
 email = $3,
 display_name = $4,
-is_admin = $5,
-updated_at = $6
-WHERE id = $7
+role = $5,
+disabled = $6,
+updated_at = $7
+WHERE id = $8
 `
@@ -19,7 +19,8 @@ func scanRow(scanner repository.Scanner, dest *core.User) error {
 		&dest.PasswordHash,
 		&dest.Email,
 		&dest.DisplayName,
-		&dest.IsAdmin,
+		&dest.Role,
+		&dest.Disabled,
 		&dest.CreatedAt,
 		&dest.UpdatedAt,
 		&twoFactor,
@@ -78,6 +78,10 @@ func (s *sqlRepository) Create(ctx context.Context, user *core.User) error {
 		return err
 	}
 
+	if user.Role == "" {
+		user.Role = core.DefaultRole
+	}
+
 	if s.db.Type == config.DatabaseTypePostgres {
 		return s.createPostgres(ctx, user)
 	}
@@ -93,7 +97,8 @@ func (s *sqlRepository) create(ctx context.Context, user *core.User) error {
 		user.PasswordHash,
 		user.Email,
 		user.DisplayName,
-		user.IsAdmin,
+		user.Role,
+		user.Disabled,
 		createdAt,
 		createdAt,
 	)
@@ -122,7 +127,8 @@ func (s *sqlRepository) createPostgres(ctx context.Context, user *core.User) err
 		user.PasswordHash,
 		user.Email,
 		user.DisplayName,
-		user.IsAdmin,
+		user.Role,
+		user.Disabled,
 		createdAt,
 		createdAt,
 	).Scan(&user.ID)
@@ -149,7 +155,8 @@ func (s *sqlRepository) Update(ctx context.Context, user *core.User) error {
 		user.PasswordHash,
 		user.Email,
 		user.DisplayName,
-		user.IsAdmin,
+		user.Role,
+		user.Disabled,
 		updatedAt,
 		user.ID,
 	)
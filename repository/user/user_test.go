@@ -64,7 +64,7 @@ func TestCreate(t *testing.T) {
 		PasswordHash: "somePasswordHash",
 		Email:        "test@example.com",
 		DisplayName:  "Test User",
-		IsAdmin:      true,
+		Role:         core.RoleAdmin,
 	}
 	err := repository.Create(noCtx, user)
 	assert.Nil(t, err)
@@ -154,7 +154,8 @@ func testUpdate(created *core.User) func(t *testing.T) {
 				PasswordHash: "updatedPasswordHash",
 				DisplayName:  "Updated User",
 				Email:        "updated@example.com",
-				IsAdmin:      false,
+				Role:         core.RoleViewer,
+				Disabled:     true,
 			},
 		)
 
@@ -167,7 +168,8 @@ func testUpdate(created *core.User) func(t *testing.T) {
 		assert.Equal(t, "updatedPasswordHash", user.PasswordHash)
 		assert.Equal(t, "Updated User", user.DisplayName)
 		assert.Equal(t, "updated@example.com", user.Email)
-		assert.False(t, user.IsAdmin)
+		assert.Equal(t, core.RoleViewer, user.Role)
+		assert.True(t, user.Disabled)
 
 		assert.NotEqual(t, 0, user.UpdatedAt)
 	}
@@ -183,3 +185,21 @@ func testDelete(created *core.User) func(t *testing.T) {
 		assert.Nil(t, err)
 	}
 }
+
+func TestCreateAssignsDefaultRole(t *testing.T) {
+	user := &core.User{
+		Username:     "default_role_user",
+		PasswordHash: "somePasswordHash",
+		Email:        "default@example.com",
+		DisplayName:  "Default Role User",
+	}
+	err := repository.Create(noCtx, user)
+	assert.Nil(t, err)
+	defer repository.Delete(noCtx, user)
+
+	assert.Equal(t, core.DefaultRole, user.Role)
+
+	found, err := repository.FindById(noCtx, user.ID)
+	assert.Nil(t, err)
+	assert.Equal(t, core.DefaultRole, found.Role)
+}
@@ -0,0 +1,62 @@
+package webauthncredential
+
+const queryBase = `
+SELECT
+webauthn_credential.id,
+webauthn_credential.user_id,
+webauthn_credential.name,
+webauthn_credential.credential_id,
+webauthn_credential.data,
+webauthn_credential.last_used_at,
+webauthn_credential.created_at,
+webauthn_credential.updated_at
+FROM webauthn_credential
+`
+
+const queryById = queryBase + `
+WHERE webauthn_credential.id = $1
+`
+
+const queryByCredentialID = queryBase + `
+WHERE webauthn_credential.credential_id = $1
+`
+
+const queryByUser = queryBase + `
+WHERE webauthn_credential.user_id = $1
+ORDER BY webauthn_credential.id
+`
+
+const stmtInsert = `
+INSERT INTO webauthn_credential (
+user_id,
+name,
+credential_id,
+data,
+created_at,
+updated_at
+) VALUES (
+$1,
+$2,
+$3,
+$4,
+$5,
+$6
+)
+`
+
+const stmtInsertPostgres = stmtInsert + `
+RETURNING id
+`
+
+const stmtUpdate = `
+UPDATE webauthn_credential SET
+name = $1,
+data = $2,
+last_used_at = $3,
+updated_at = $4
+WHERE id = $5
+`
+
+const stmtDelete = `
+DELETE FROM webauthn_credential WHERE id = $1
+`
@@ -0,0 +1,45 @@
+package webauthncredential
+
+import (
+	"database/sql"
+
+	"github.com/davidborzek/tvhgo/core"
+	"github.com/davidborzek/tvhgo/repository"
+)
+
+// Internal helper to scan a sql.Row into a webauthn credential model.
+func scanRow(scanner repository.Scanner, dest *core.WebAuthnCredential) error {
+	var lastUsedAt sql.NullInt64
+
+	err := scanner.Scan(
+		&dest.ID,
+		&dest.UserID,
+		&dest.Name,
+		&dest.CredentialID,
+		&dest.Data,
+		&lastUsedAt,
+		&dest.CreatedAt,
+		&dest.UpdatedAt,
+	)
+
+	if lastUsedAt.Valid {
+		dest.LastUsedAt = &lastUsedAt.Int64
+	}
+
+	return err
+}
+
+// Internal helper to scan sql.Rows into an array of webauthn credential models.
+func scanRows(rows *sql.Rows) ([]*core.WebAuthnCredential, error) {
+	defer rows.Close()
+
+	credentials := []*core.WebAuthnCredential{}
+	for rows.Next() {
+		credential := new(core.WebAuthnCredential)
+		if err := scanRow(rows, credential); err != nil {
+			return nil, err
+		}
+		credentials = append(credentials, credential)
+	}
+	return credentials, nil
+}
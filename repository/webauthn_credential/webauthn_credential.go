@@ -0,0 +1,140 @@
+package webauthncredential
+
+import (
+	"context"
+	"database/sql"
+
+	"github.com/davidborzek/tvhgo/config"
+	"github.com/davidborzek/tvhgo/core"
+	"github.com/davidborzek/tvhgo/db"
+)
+
+type sqlRepository struct {
+	db    *db.DB
+	clock core.Clock
+}
+
+func New(db *db.DB, clock core.Clock) core.WebAuthnCredentialRepository {
+	return &sqlRepository{
+		db:    db,
+		clock: clock,
+	}
+}
+
+func (s *sqlRepository) FindById(ctx context.Context, id int64) (*core.WebAuthnCredential, error) {
+	return s.findBy(ctx, queryById, id)
+}
+
+func (s *sqlRepository) FindByCredentialID(
+	ctx context.Context,
+	credentialID string,
+) (*core.WebAuthnCredential, error) {
+	return s.findBy(ctx, queryByCredentialID, credentialID)
+}
+
+func (s *sqlRepository) FindByUser(ctx context.Context, userID int64) ([]*core.WebAuthnCredential, error) {
+	rows, err := s.db.QueryContext(ctx, queryByUser, userID)
+	if err != nil {
+		return nil, err
+	}
+
+	return scanRows(rows)
+}
+
+func (s *sqlRepository) Create(ctx context.Context, credential *core.WebAuthnCredential) error {
+	if s.db.Type == config.DatabaseTypePostgres {
+		return s.createPostgres(ctx, credential)
+	}
+
+	return s.create(ctx, credential)
+}
+
+func (s *sqlRepository) create(ctx context.Context, credential *core.WebAuthnCredential) error {
+	now := s.clock.Now().Unix()
+
+	res, err := s.db.ExecContext(ctx, stmtInsert,
+		credential.UserID,
+		credential.Name,
+		credential.CredentialID,
+		credential.Data,
+		now,
+		now,
+	)
+
+	if err != nil {
+		return err
+	}
+
+	id, err := res.LastInsertId()
+	if err != nil {
+		return err
+	}
+
+	credential.ID = id
+	credential.UpdatedAt = now
+	credential.CreatedAt = now
+	return nil
+}
+
+func (s *sqlRepository) createPostgres(ctx context.Context, credential *core.WebAuthnCredential) error {
+	now := s.clock.Now().Unix()
+
+	err := s.db.QueryRowContext(ctx, stmtInsertPostgres,
+		credential.UserID,
+		credential.Name,
+		credential.CredentialID,
+		credential.Data,
+		now,
+		now,
+	).Scan(&credential.ID)
+
+	if err != nil {
+		return err
+	}
+
+	credential.UpdatedAt = now
+	credential.CreatedAt = now
+	return nil
+}
+
+func (s *sqlRepository) Update(ctx context.Context, credential *core.WebAuthnCredential) error {
+	updatedAt := s.clock.Now().Unix()
+
+	_, err := s.db.ExecContext(ctx, stmtUpdate,
+		credential.Name,
+		credential.Data,
+		credential.LastUsedAt,
+		updatedAt,
+		credential.ID,
+	)
+
+	if err != nil {
+		return err
+	}
+
+	credential.UpdatedAt = updatedAt
+	return nil
+}
+
+func (s *sqlRepository) Delete(ctx context.Context, credential *core.WebAuthnCredential) error {
+	_, err := s.db.ExecContext(ctx, stmtDelete, credential.ID)
+	return err
+}
+
+func (s *sqlRepository) findBy(
+	ctx context.Context,
+	query string,
+	args ...interface{},
+) (*core.WebAuthnCredential, error) {
+	row := s.db.QueryRowContext(ctx, query, args...)
+
+	credential := new(core.WebAuthnCredential)
+	if err := scanRow(row, credential); err != nil {
+		if err == sql.ErrNoRows {
+			return nil, nil
+		}
+
+		return nil, err
+	}
+	return credential, nil
+}
@@ -0,0 +1,154 @@
+package webauthncredential_test
+
+import (
+	"context"
+	"os"
+	"testing"
+
+	"github.com/davidborzek/tvhgo/core"
+	database "github.com/davidborzek/tvhgo/db"
+	"github.com/davidborzek/tvhgo/db/testdb"
+	"github.com/davidborzek/tvhgo/repository/user"
+	webauthncredential "github.com/davidborzek/tvhgo/repository/webauthn_credential"
+	"github.com/davidborzek/tvhgo/services/clock"
+	"github.com/stretchr/testify/assert"
+)
+
+var (
+	noCtx      = context.TODO()
+	repository core.WebAuthnCredentialRepository
+
+	testUser = &core.User{
+		ID:          1234,
+		Username:    "testuser",
+		Email:       "testuser@example.com",
+		DisplayName: "Test user",
+	}
+)
+
+func initTestUser(db *database.DB) error {
+	return user.New(db, clock.NewClock()).
+		Create(noCtx, testUser)
+}
+
+func TestMain(m *testing.M) {
+	db, err := testdb.Setup()
+	if err != nil {
+		panic(err)
+	}
+	defer testdb.Close(db)
+
+	if err := initTestUser(db); err != nil {
+		panic(err)
+	}
+
+	repository = webauthncredential.New(db, clock.NewClock())
+	code := m.Run()
+
+	err = testdb.TruncateTables(db, "webauthn_credential", "user")
+	if err != nil {
+		panic(err)
+	}
+
+	testdb.Close(db)
+
+	os.Exit(code)
+}
+
+func TestFindByIdReturnsNil(t *testing.T) {
+	credential, err := repository.FindById(noCtx, 1)
+
+	assert.Nil(t, credential)
+	assert.Nil(t, err)
+}
+
+func TestFindByCredentialIDReturnsNil(t *testing.T) {
+	credential, err := repository.FindByCredentialID(noCtx, "unknown")
+
+	assert.Nil(t, credential)
+	assert.Nil(t, err)
+}
+
+func TestFindByUserReturnsEmptyArray(t *testing.T) {
+	credentials, err := repository.FindByUser(noCtx, testUser.ID)
+
+	assert.Empty(t, credentials)
+	assert.Nil(t, err)
+}
+
+func TestCreate(t *testing.T) {
+	credential := &core.WebAuthnCredential{
+		UserID:       testUser.ID,
+		Name:         "someName",
+		CredentialID: "someCredentialID",
+		Data:         "{}",
+	}
+	err := repository.Create(noCtx, credential)
+
+	assert.Nil(t, err)
+	assert.Nil(t, credential.LastUsedAt)
+
+	t.Run("FindById", testFindById(credential))
+	t.Run("FindByCredentialID", testFindByCredentialID(credential))
+	t.Run("FindByUser", testFindByUser(credential))
+	t.Run("Update", testUpdate(credential))
+	t.Run("Delete", testDelete(credential))
+}
+
+func testFindById(created *core.WebAuthnCredential) func(t *testing.T) {
+	return func(t *testing.T) {
+		credential, err := repository.FindById(noCtx, created.ID)
+
+		assert.Nil(t, err)
+		assert.Equal(t, created, credential)
+	}
+}
+
+func testFindByCredentialID(created *core.WebAuthnCredential) func(t *testing.T) {
+	return func(t *testing.T) {
+		credential, err := repository.FindByCredentialID(noCtx, created.CredentialID)
+
+		assert.Nil(t, err)
+		assert.Equal(t, created, credential)
+	}
+}
+
+func testFindByUser(created *core.WebAuthnCredential) func(t *testing.T) {
+	return func(t *testing.T) {
+		credentials, err := repository.FindByUser(noCtx, testUser.ID)
+
+		assert.Nil(t, err)
+		assert.Len(t, credentials, 1)
+		assert.Equal(t, created, credentials[0])
+	}
+}
+
+func testUpdate(created *core.WebAuthnCredential) func(t *testing.T) {
+	return func(t *testing.T) {
+		lastUsedAt := int64(1234)
+		created.Name = "newName"
+		created.Data = `{"id":"updated"}`
+		created.LastUsedAt = &lastUsedAt
+
+		err := repository.Update(noCtx, created)
+		assert.Nil(t, err)
+
+		credential, err := repository.FindById(noCtx, created.ID)
+
+		assert.Nil(t, err)
+		assert.Equal(t, created, credential)
+	}
+}
+
+func testDelete(created *core.WebAuthnCredential) func(t *testing.T) {
+	return func(t *testing.T) {
+		err := repository.Delete(noCtx, created)
+
+		assert.Nil(t, err)
+
+		credential, err := repository.FindById(noCtx, created.ID)
+
+		assert.Nil(t, err)
+		assert.Nil(t, credential)
+	}
+}
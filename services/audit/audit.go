@@ -0,0 +1,65 @@
+package audit
+
+import (
+	"context"
+
+	"github.com/davidborzek/tvhgo/core"
+	"github.com/rs/zerolog/log"
+)
+
+// defaultLimit is the number of returned entries when no limit is requested.
+const defaultLimit = 100
+
+type service struct {
+	repository core.AuditRepository
+	users      core.UserRepository
+}
+
+func New(repository core.AuditRepository, users core.UserRepository) core.AuditService {
+	return &service{
+		repository: repository,
+		users:      users,
+	}
+}
+
+func (s *service) Record(ctx context.Context, entry *core.AuditEntry) {
+	if entry.Username == "" && entry.UserID != nil {
+		user, err := s.users.FindById(ctx, *entry.UserID)
+		if err != nil {
+			log.Error().Err(err).Int64("userId", *entry.UserID).
+				Msg("could not get user of audit entry")
+		}
+
+		if user != nil {
+			entry.Username = user.Username
+		}
+	}
+
+	if err := s.repository.Create(ctx, entry); err != nil {
+		log.Error().Err(err).Str("action", string(entry.Action)).
+			Str("username", entry.Username).
+			Msg("could not record audit entry")
+	}
+}
+
+func (s *service) Find(
+	ctx context.Context,
+	params core.AuditQueryParams,
+) (*core.AuditListResult, error) {
+	if err := params.Validate(); err != nil {
+		return nil, err
+	}
+
+	if params.Limit == 0 {
+		params.Limit = defaultLimit
+	}
+
+	result, err := s.repository.Find(ctx, params)
+	if err != nil {
+		log.Error().Err(err).Msg("could not get audit log")
+
+		return nil, core.ErrUnexpectedError
+	}
+
+	return result, nil
+}
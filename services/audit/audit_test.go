@@ -0,0 +1,108 @@
+package audit_test
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/davidborzek/tvhgo/core"
+	mock_core "github.com/davidborzek/tvhgo/mock/core"
+	"github.com/davidborzek/tvhgo/services/audit"
+	"github.com/stretchr/testify/assert"
+	"go.uber.org/mock/gomock"
+)
+
+var ctx = context.TODO()
+
+func TestRecordResolvesUsername(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	userID := int64(1)
+
+	mockUsers := mock_core.NewMockUserRepository(ctrl)
+	mockUsers.EXPECT().
+		FindById(ctx, userID).
+		Return(&core.User{ID: userID, Username: "jdoe"}, nil)
+
+	mockRepository := mock_core.NewMockAuditRepository(ctrl)
+	mockRepository.EXPECT().
+		Create(ctx, &core.AuditEntry{
+			UserID:   &userID,
+			Username: "jdoe",
+			Action:   core.AuditActionLogout,
+		}).
+		Return(nil)
+
+	s := audit.New(mockRepository, mockUsers)
+	s.Record(ctx, &core.AuditEntry{
+		UserID: &userID,
+		Action: core.AuditActionLogout,
+	})
+}
+
+func TestRecordKeepsUsername(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	entry := &core.AuditEntry{
+		Username: "unknown",
+		Action:   core.AuditActionLoginFailed,
+	}
+
+	mockRepository := mock_core.NewMockAuditRepository(ctrl)
+	mockRepository.EXPECT().
+		Create(ctx, entry).
+		Return(errors.New("some error"))
+
+	s := audit.New(mockRepository, mock_core.NewMockUserRepository(ctrl))
+	s.Record(ctx, entry)
+}
+
+func TestFindAppliesDefaultLimit(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	expected := &core.AuditListResult{}
+
+	mockRepository := mock_core.NewMockAuditRepository(ctrl)
+	mockRepository.EXPECT().
+		Find(ctx, core.AuditQueryParams{
+			PaginationQueryParams: core.PaginationQueryParams{Limit: 100},
+			Action:                core.AuditActionLogin,
+		}).
+		Return(expected, nil)
+
+	s := audit.New(mockRepository, mock_core.NewMockUserRepository(ctrl))
+
+	result, err := s.Find(ctx, core.AuditQueryParams{Action: core.AuditActionLogin})
+	assert.Nil(t, err)
+	assert.Equal(t, expected, result)
+}
+
+func TestFindReturnsErrAuditActionInvalid(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	s := audit.New(mock_core.NewMockAuditRepository(ctrl), mock_core.NewMockUserRepository(ctrl))
+
+	result, err := s.Find(ctx, core.AuditQueryParams{Action: "unknown"})
+	assert.Nil(t, result)
+	assert.Equal(t, core.ErrAuditActionInvalid, err)
+}
+
+func TestFindReturnsErrUnexpectedError(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockRepository := mock_core.NewMockAuditRepository(ctrl)
+	mockRepository.EXPECT().
+		Find(ctx, gomock.Any()).
+		Return(nil, errors.New("some error"))
+
+	s := audit.New(mockRepository, mock_core.NewMockUserRepository(ctrl))
+
+	result, err := s.Find(ctx, core.AuditQueryParams{})
+	assert.Nil(t, result)
+	assert.Equal(t, core.ErrUnexpectedError, err)
+}
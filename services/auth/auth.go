@@ -1,19 +1,24 @@
 package auth
 
-import "golang.org/x/crypto/bcrypt"
+import "github.com/davidborzek/tvhgo/core"
 
-const bcryptCost = 12
-
-// HashPassword hashes the password using bcrypt.
-func HashPassword(password string) (string, error) {
-	hash, err := bcrypt.GenerateFromPassword([]byte(password), bcryptCost)
-	if err != nil {
-		return "", err
+// adminRole returns the role of a user, which is managed
+// by the admin groups of an identity provider.
+func adminRole(isAdmin bool) core.Role {
+	if isAdmin {
+		return core.RoleAdmin
 	}
 
-	return string(hash), nil
+	return core.DefaultRole
 }
 
-func ComparePassword(password string, hash string) error {
-	return bcrypt.CompareHashAndPassword([]byte(hash), []byte(password))
+// syncAdminRole grants or revokes the admin role of a user and returns true
+// if the role was changed. Other roles are kept, unless the admin role is revoked.
+func syncAdminRole(user *core.User, isAdmin bool) bool {
+	if user.IsAdmin() == isAdmin {
+		return false
+	}
+
+	user.Role = adminRole(isAdmin)
+	return true
 }
@@ -0,0 +1,75 @@
+package auth
+
+import (
+	"context"
+	"errors"
+
+	"github.com/davidborzek/tvhgo/core"
+)
+
+type chainPasswordAuthenticator struct {
+	authenticators []core.PasswordAuthenticator
+}
+
+// NewChainPasswordAuthenticator creates a new PasswordAuthenticator which
+// tries the authenticators in order. The next authenticator is only tried
+// when the credentials are invalid or an authenticator failed unexpectedly,
+// so errors like a missing two factor code are returned immediately.
+func NewChainPasswordAuthenticator(
+	authenticators ...core.PasswordAuthenticator,
+) core.PasswordAuthenticator {
+	return &chainPasswordAuthenticator{
+		authenticators: authenticators,
+	}
+}
+
+func (s *chainPasswordAuthenticator) Login(
+	ctx context.Context,
+	login string,
+	password string,
+	totp *string,
+) (*core.User, error) {
+	err := core.ErrInvalidUsernameOrPassword
+	for _, authenticator := range s.authenticators {
+		user, loginErr := authenticator.Login(ctx, login, password, totp)
+		if loginErr == nil {
+			return user, nil
+		}
+
+		if !errors.Is(loginErr, core.ErrInvalidUsernameOrPassword) &&
+			!errors.Is(loginErr, core.ErrUnexpectedError) {
+			return nil, loginErr
+		}
+
+		if errors.Is(loginErr, core.ErrUnexpectedError) {
+			err = loginErr
+		}
+	}
+
+	return nil, err
+}
+
+func (s *chainPasswordAuthenticator) ConfirmPassword(
+	ctx context.Context,
+	userID int64,
+	password string,
+) error {
+	err := core.ErrConfirmationPasswordInvalid
+	for _, authenticator := range s.authenticators {
+		confirmErr := authenticator.ConfirmPassword(ctx, userID, password)
+		if confirmErr == nil {
+			return nil
+		}
+
+		if !errors.Is(confirmErr, core.ErrConfirmationPasswordInvalid) &&
+			!errors.Is(confirmErr, core.ErrUnexpectedError) {
+			return confirmErr
+		}
+
+		if errors.Is(confirmErr, core.ErrUnexpectedError) {
+			err = confirmErr
+		}
+	}
+
+	return err
+}
@@ -0,0 +1,112 @@
+package auth_test
+
+import (
+	"testing"
+
+	"github.com/davidborzek/tvhgo/core"
+	mock_core "github.com/davidborzek/tvhgo/mock/core"
+	"github.com/davidborzek/tvhgo/services/auth"
+	"github.com/stretchr/testify/assert"
+	"go.uber.org/mock/gomock"
+)
+
+func TestChainPasswordAuthenticatorLoginTriesNextAuthenticator(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	first := mock_core.NewMockPasswordAuthenticator(ctrl)
+	first.EXPECT().
+		Login(ctx, username, password, nil).
+		Return(nil, core.ErrInvalidUsernameOrPassword)
+
+	second := mock_core.NewMockPasswordAuthenticator(ctrl)
+	second.EXPECT().
+		Login(ctx, username, password, nil).
+		Return(&expectedUser, nil)
+
+	authenticator := auth.NewChainPasswordAuthenticator(first, second)
+
+	user, err := authenticator.Login(ctx, username, password, nil)
+	assert.Nil(t, err)
+	assert.Equal(t, &expectedUser, user)
+}
+
+func TestChainPasswordAuthenticatorLoginTriesNextAuthenticatorOnUnexpectedError(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	first := mock_core.NewMockPasswordAuthenticator(ctrl)
+	first.EXPECT().
+		Login(ctx, username, password, nil).
+		Return(nil, core.ErrUnexpectedError)
+
+	second := mock_core.NewMockPasswordAuthenticator(ctrl)
+	second.EXPECT().
+		Login(ctx, username, password, nil).
+		Return(nil, core.ErrInvalidUsernameOrPassword)
+
+	authenticator := auth.NewChainPasswordAuthenticator(first, second)
+
+	user, err := authenticator.Login(ctx, username, password, nil)
+	assert.Nil(t, user)
+	assert.Equal(t, core.ErrUnexpectedError, err)
+}
+
+func TestChainPasswordAuthenticatorLoginStopsOnTwoFactorError(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	first := mock_core.NewMockPasswordAuthenticator(ctrl)
+	first.EXPECT().
+		Login(ctx, username, password, nil).
+		Return(nil, core.ErrTwoFactorRequired)
+
+	second := mock_core.NewMockPasswordAuthenticator(ctrl)
+
+	authenticator := auth.NewChainPasswordAuthenticator(first, second)
+
+	user, err := authenticator.Login(ctx, username, password, nil)
+	assert.Nil(t, user)
+	assert.Equal(t, core.ErrTwoFactorRequired, err)
+}
+
+func TestChainPasswordAuthenticatorLoginReturnsInvalidUsernameOrPassword(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	first := mock_core.NewMockPasswordAuthenticator(ctrl)
+	first.EXPECT().
+		Login(ctx, username, password, nil).
+		Return(nil, core.ErrInvalidUsernameOrPassword)
+
+	authenticator := auth.NewChainPasswordAuthenticator(first)
+
+	user, err := authenticator.Login(ctx, username, password, nil)
+	assert.Nil(t, user)
+	assert.Equal(t, core.ErrInvalidUsernameOrPassword, err)
+}
+
+func TestChainPasswordAuthenticatorConfirmPassword(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	first := mock_core.NewMockPasswordAuthenticator(ctrl)
+	first.EXPECT().
+		ConfirmPassword(ctx, expectedUser.ID, password).
+		Return(core.ErrConfirmationPasswordInvalid).
+		Times(2)
+
+	second := mock_core.NewMockPasswordAuthenticator(ctrl)
+	second.EXPECT().
+		ConfirmPassword(ctx, expectedUser.ID, password).
+		Return(nil)
+	second.EXPECT().
+		ConfirmPassword(ctx, expectedUser.ID, password).
+		Return(core.ErrConfirmationPasswordInvalid)
+
+	authenticator := auth.NewChainPasswordAuthenticator(first, second)
+
+	assert.Nil(t, authenticator.ConfirmPassword(ctx, expectedUser.ID, password))
+	assert.Equal(t, core.ErrConfirmationPasswordInvalid,
+		authenticator.ConfirmPassword(ctx, expectedUser.ID, password))
+}
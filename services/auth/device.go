@@ -0,0 +1,384 @@
+package auth
+
+import (
+	"context"
+	"crypto/rand"
+	"math/big"
+	"net/url"
+	"strings"
+	"unicode/utf8"
+
+	"github.com/davidborzek/tvhgo/config"
+	"github.com/davidborzek/tvhgo/core"
+	"github.com/rs/zerolog/log"
+)
+
+const (
+	// userCodeAlphabet contains only consonants without ambiguous
+	// characters, so that user codes are easy to type and spell no words.
+	userCodeAlphabet = "BCDFGHJKLMNPQRSTVWXZ"
+	userCodeLength   = 8
+
+	maxDeviceNameLength = 64
+)
+
+type deviceAuthService struct {
+	repository     core.DeviceAuthRepository
+	sessionManager core.SessionManager
+	tokenService   core.TokenService
+	clock          core.Clock
+	cfg            *config.DeviceAuthConfig
+}
+
+func NewDeviceAuthService(
+	repository core.DeviceAuthRepository,
+	sessionManager core.SessionManager,
+	tokenService core.TokenService,
+	clock core.Clock,
+	cfg *config.DeviceAuthConfig,
+) core.DeviceAuthService {
+	return &deviceAuthService{
+		repository:     repository,
+		sessionManager: sessionManager,
+		tokenService:   tokenService,
+		clock:          clock,
+		cfg:            cfg,
+	}
+}
+
+func (s *deviceAuthService) Start(
+	ctx context.Context,
+	deviceName string,
+	clientIP string,
+) (*core.DeviceCode, error) {
+	deviceName = strings.TrimSpace(deviceName)
+	if deviceName == "" || utf8.RuneCountInString(deviceName) > maxDeviceNameLength {
+		return nil, core.ErrDeviceNameInvalid
+	}
+
+	now := s.clock.Now().Unix()
+	if _, err := s.repository.DeleteExpired(ctx, now); err != nil {
+		log.Error().Err(err).Msg("could not delete expired device authorizations")
+	}
+
+	if err := s.checkPendingLimit(ctx, clientIP); err != nil {
+		return nil, err
+	}
+
+	deviceCode, err := generateToken()
+	if err != nil {
+		log.Error().Err(err).Msg("could not generate device code")
+
+		return nil, core.ErrUnexpectedError
+	}
+
+	userCode, err := generateUserCode()
+	if err != nil {
+		log.Error().Err(err).Msg("could not generate user code")
+
+		return nil, core.ErrUnexpectedError
+	}
+
+	auth := &core.DeviceAuthorization{
+		HashedDeviceCode: hashToken(deviceCode),
+		UserCode:         userCode,
+		DeviceName:       deviceName,
+		ClientIP:         clientIP,
+		Status:           core.DeviceAuthStatusPending,
+		ExpiresAt:        now + int64(s.cfg.CodeLifetime.Seconds()),
+		LastPolledAt:     now,
+	}
+
+	if err := s.repository.Create(ctx, auth); err != nil {
+		log.Error().Err(err).Str("device", deviceName).
+			Msg("could not persist device authorization")
+
+		return nil, core.ErrUnexpectedError
+	}
+
+	return &core.DeviceCode{
+		DeviceCode:              deviceCode,
+		UserCode:                formatUserCode(userCode),
+		VerificationURI:         s.cfg.VerificationURL,
+		VerificationURIComplete: s.verificationURIComplete(userCode),
+		ExpiresIn:               int64(s.cfg.CodeLifetime.Seconds()),
+		Interval:                int64(s.cfg.PollInterval.Seconds()),
+	}, nil
+}
+
+// checkPendingLimit returns ErrDeviceCodeLimit, if there are too many
+// pending device codes in total or from the client ip, since the
+// code endpoint is unauthenticated.
+func (s *deviceAuthService) checkPendingLimit(ctx context.Context, clientIP string) error {
+	count, err := s.repository.CountPendingByClientIP(ctx, clientIP)
+	if err != nil {
+		log.Error().Err(err).Msg("could not count pending device authorizations")
+
+		return core.ErrUnexpectedError
+	}
+
+	if count >= int64(s.cfg.MaxPendingCodesPerIP) {
+		log.Warn().Str("ip", clientIP).
+			Msg("too many pending device authorizations from ip")
+
+		return core.ErrDeviceCodeLimit
+	}
+
+	count, err = s.repository.CountPending(ctx)
+	if err != nil {
+		log.Error().Err(err).Msg("could not count pending device authorizations")
+
+		return core.ErrUnexpectedError
+	}
+
+	if count >= int64(s.cfg.MaxPendingCodes) {
+		log.Warn().Msg("too many pending device authorizations")
+
+		return core.ErrDeviceCodeLimit
+	}
+
+	return nil
+}
+
+func (s *deviceAuthService) Find(
+	ctx context.Context,
+	userCode string,
+) (*core.DeviceAuthorization, error) {
+	auth, err := s.findPending(ctx, userCode)
+	if err != nil {
+		return nil, err
+	}
+
+	auth.UserCode = formatUserCode(auth.UserCode)
+	return auth, nil
+}
+
+func (s *deviceAuthService) Approve(
+	ctx context.Context,
+	userCode string,
+	userID int64,
+	scopes []core.Permission,
+) (*core.DeviceAuthorization, error) {
+	for _, scope := range scopes {
+		if !scope.Valid() {
+			return nil, core.ErrTokenScopeInvalid
+		}
+	}
+
+	if len(scopes) == 0 {
+		scopes = nil
+	}
+
+	return s.decide(ctx, userCode, userID, core.DeviceAuthStatusApproved, scopes)
+}
+
+func (s *deviceAuthService) Deny(
+	ctx context.Context,
+	userCode string,
+	userID int64,
+) (*core.DeviceAuthorization, error) {
+	return s.decide(ctx, userCode, userID, core.DeviceAuthStatusDenied, nil)
+}
+
+func (s *deviceAuthService) decide(
+	ctx context.Context,
+	userCode string,
+	userID int64,
+	status core.DeviceAuthStatus,
+	scopes []core.Permission,
+) (*core.DeviceAuthorization, error) {
+	auth, err := s.findPending(ctx, userCode)
+	if err != nil {
+		return nil, err
+	}
+
+	auth.Status = status
+	auth.UserID = &userID
+	auth.Scopes = scopes
+
+	if err := s.repository.Update(ctx, auth); err != nil {
+		log.Error().Err(err).Int64("user", userID).
+			Msg("could not update device authorization")
+
+		return nil, core.ErrUnexpectedError
+	}
+
+	auth.UserCode = formatUserCode(auth.UserCode)
+	return auth, nil
+}
+
+func (s *deviceAuthService) Poll(
+	ctx context.Context,
+	deviceCode string,
+	clientIP string,
+	userAgent string,
+) (*core.DeviceToken, error) {
+	auth, err := s.repository.FindByDeviceCode(ctx, hashToken(deviceCode))
+	if err != nil {
+		log.Error().Err(err).Msg("could not get device authorization")
+
+		return nil, core.ErrUnexpectedError
+	}
+
+	if auth == nil {
+		return nil, core.ErrDeviceCodeInvalid
+	}
+
+	now := s.clock.Now().Unix()
+	if now >= auth.ExpiresAt {
+		s.delete(ctx, auth)
+		return nil, core.ErrDeviceAuthExpired
+	}
+
+	switch auth.Status {
+	case core.DeviceAuthStatusApproved:
+		return s.issue(ctx, auth, clientIP, userAgent)
+	case core.DeviceAuthStatusDenied:
+		s.delete(ctx, auth)
+		return nil, core.ErrDeviceAuthDenied
+	}
+
+	tooFast := now < auth.LastPolledAt+int64(s.cfg.PollInterval.Seconds())
+	auth.LastPolledAt = now
+
+	if err := s.repository.Update(ctx, auth); err != nil {
+		log.Error().Err(err).Int64("deviceAuthorization", auth.ID).
+			Msg("could not update device authorization")
+
+		return nil, core.ErrUnexpectedError
+	}
+
+	if tooFast {
+		return nil, core.ErrDeviceAuthSlowDown
+	}
+
+	return nil, core.ErrDeviceAuthPending
+}
+
+// issue consumes an approved device authorization and
+// creates an api token or a session for the device.
+func (s *deviceAuthService) issue(
+	ctx context.Context,
+	auth *core.DeviceAuthorization,
+	clientIP string,
+	userAgent string,
+) (*core.DeviceToken, error) {
+	// The authorization is deleted first, so that
+	// concurrent polls can't consume it twice.
+	deleted, err := s.repository.Delete(ctx, auth.ID)
+	if err != nil {
+		log.Error().Err(err).Int64("deviceAuthorization", auth.ID).
+			Msg("could not delete device authorization")
+
+		return nil, core.ErrUnexpectedError
+	}
+
+	if !deleted {
+		return nil, core.ErrDeviceCodeInvalid
+	}
+
+	token := &core.DeviceToken{
+		UserID:     *auth.UserID,
+		DeviceName: auth.DeviceName,
+	}
+
+	if len(auth.Scopes) > 0 {
+		token.TokenType = core.DeviceTokenTypeBearer
+		token.Scopes = auth.Scopes
+		token.AccessToken, err = s.tokenService.Create(ctx, *auth.UserID, core.CreateToken{
+			Name:   auth.DeviceName,
+			Scopes: auth.Scopes,
+		})
+	} else {
+		token.TokenType = core.DeviceTokenTypeSession
+		token.AccessToken, err = s.sessionManager.CreateDeviceSession(
+			ctx,
+			*auth.UserID,
+			clientIP,
+			userAgent,
+			auth.DeviceName,
+		)
+	}
+
+	if err != nil {
+		return nil, err
+	}
+
+	return token, nil
+}
+
+// findPending returns a pending and not expired device authorization by the user code.
+func (s *deviceAuthService) findPending(
+	ctx context.Context,
+	userCode string,
+) (*core.DeviceAuthorization, error) {
+	auth, err := s.repository.FindByUserCode(ctx, normalizeUserCode(userCode))
+	if err != nil {
+		log.Error().Err(err).Msg("could not get device authorization")
+
+		return nil, core.ErrUnexpectedError
+	}
+
+	if auth == nil ||
+		auth.Status != core.DeviceAuthStatusPending ||
+		s.clock.Now().Unix() >= auth.ExpiresAt {
+		return nil, core.ErrDeviceUserCodeInvalid
+	}
+
+	return auth, nil
+}
+
+func (s *deviceAuthService) delete(ctx context.Context, auth *core.DeviceAuthorization) {
+	if _, err := s.repository.Delete(ctx, auth.ID); err != nil {
+		log.Error().Err(err).Int64("deviceAuthorization", auth.ID).
+			Msg("could not delete device authorization")
+	}
+}
+
+func (s *deviceAuthService) verificationURIComplete(userCode string) string {
+	u, err := url.Parse(s.cfg.VerificationURL)
+	if err != nil {
+		return s.cfg.VerificationURL
+	}
+
+	q := u.Query()
+	q.Set("code", formatUserCode(userCode))
+	u.RawQuery = q.Encode()
+	return u.String()
+}
+
+// generateUserCode generates a random user code.
+func generateUserCode() (string, error) {
+	max := big.NewInt(int64(len(userCodeAlphabet)))
+
+	code := make([]byte, userCodeLength)
+	for i := range code {
+		n, err := rand.Int(rand.Reader, max)
+		if err != nil {
+			return "", err
+		}
+		code[i] = userCodeAlphabet[n.Int64()]
+	}
+
+	return string(code), nil
+}
+
+// formatUserCode formats a user code as XXXX-XXXX for display.
+func formatUserCode(code string) string {
+	if len(code) != userCodeLength {
+		return code
+	}
+
+	return code[:userCodeLength/2] + "-" + code[userCodeLength/2:]
+}
+
+// normalizeUserCode removes separators from an entered user code
+// and converts it to upper case.
+func normalizeUserCode(code string) string {
+	return strings.Map(func(r rune) rune {
+		if r == '-' || r == ' ' {
+			return -1
+		}
+		return r
+	}, strings.ToUpper(code))
+}
@@ -0,0 +1,409 @@
+package auth_test
+
+import (
+	"regexp"
+	"testing"
+	"time"
+
+	"github.com/davidborzek/tvhgo/config"
+	"github.com/davidborzek/tvhgo/core"
+	mock_core "github.com/davidborzek/tvhgo/mock/core"
+	"github.com/davidborzek/tvhgo/services/auth"
+	"github.com/stretchr/testify/assert"
+	"go.uber.org/mock/gomock"
+)
+
+const (
+	deviceName     = "Living Room TV"
+	deviceUserCode = "BCDFGHJK"
+)
+
+var (
+	deviceNow    = time.Unix(1672527600, 0)
+	deviceUserID = userID
+)
+
+type deviceAuthMocks struct {
+	repository     *mock_core.MockDeviceAuthRepository
+	sessionManager *mock_core.MockSessionManager
+	tokenService   *mock_core.MockTokenService
+}
+
+func newDeviceAuthService(ctrl *gomock.Controller) (core.DeviceAuthService, deviceAuthMocks) {
+	mocks := deviceAuthMocks{
+		repository:     mock_core.NewMockDeviceAuthRepository(ctrl),
+		sessionManager: mock_core.NewMockSessionManager(ctrl),
+		tokenService:   mock_core.NewMockTokenService(ctrl),
+	}
+
+	mockClock := mock_core.NewMockClock(ctrl)
+	mockClock.EXPECT().
+		Now().
+		Return(deviceNow).
+		AnyTimes()
+
+	cfg := &config.DeviceAuthConfig{
+		Enabled:         true,
+		VerificationURL: "https://tvhgo.example.com/device",
+	}
+	cfg.SetDefaults()
+
+	s := auth.NewDeviceAuthService(mocks.repository, mocks.sessionManager, mocks.tokenService, mockClock, cfg)
+	return s, mocks
+}
+
+func pendingDeviceAuth() *core.DeviceAuthorization {
+	return &core.DeviceAuthorization{
+		ID:           1,
+		UserCode:     deviceUserCode,
+		DeviceName:   deviceName,
+		Status:       core.DeviceAuthStatusPending,
+		ExpiresAt:    deviceNow.Unix() + 300,
+		LastPolledAt: deviceNow.Unix() - 10,
+	}
+}
+
+func TestDeviceAuthStartReturnsDeviceCode(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	s, mocks := newDeviceAuthService(ctrl)
+
+	mocks.repository.EXPECT().
+		DeleteExpired(ctx, deviceNow.Unix()).
+		Return(int64(0), nil)
+
+	mocks.repository.EXPECT().
+		CountPendingByClientIP(ctx, "192.168.1.10").
+		Return(int64(4), nil)
+
+	mocks.repository.EXPECT().
+		CountPending(ctx).
+		Return(int64(999), nil)
+
+	var created *core.DeviceAuthorization
+	mocks.repository.EXPECT().
+		Create(ctx, gomock.Any()).
+		DoAndReturn(func(_ any, auth *core.DeviceAuthorization) error {
+			created = auth
+			return nil
+		})
+
+	code, err := s.Start(ctx, "  "+deviceName+" ", "192.168.1.10")
+
+	assert.Nil(t, err)
+	assert.Regexp(t, regexp.MustCompile(`^[BCDFGHJKLMNPQRSTVWXZ]{4}-[BCDFGHJKLMNPQRSTVWXZ]{4}$`), code.UserCode)
+	assert.NotEmpty(t, code.DeviceCode)
+	assert.Equal(t, "https://tvhgo.example.com/device", code.VerificationURI)
+	assert.Equal(t, "https://tvhgo.example.com/device?code="+code.UserCode, code.VerificationURIComplete)
+	assert.Equal(t, int64(600), code.ExpiresIn)
+	assert.Equal(t, int64(5), code.Interval)
+
+	assert.Equal(t, deviceName, created.DeviceName)
+	assert.Equal(t, "192.168.1.10", created.ClientIP)
+	assert.Equal(t, core.DeviceAuthStatusPending, created.Status)
+	assert.Equal(t, deviceNow.Unix()+600, created.ExpiresAt)
+	assert.NotEqual(t, code.DeviceCode, created.HashedDeviceCode)
+	assert.NotContains(t, created.UserCode, "-")
+}
+
+func TestDeviceAuthStartReturnsErrDeviceNameInvalid(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	s, _ := newDeviceAuthService(ctrl)
+
+	for _, name := range []string{"", "   ", string(make([]byte, 65))} {
+		code, err := s.Start(ctx, name, "192.168.1.10")
+
+		assert.Equal(t, core.ErrDeviceNameInvalid, err)
+		assert.Nil(t, code)
+	}
+}
+
+func TestDeviceAuthStartReturnsErrDeviceCodeLimitForClientIP(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	s, mocks := newDeviceAuthService(ctrl)
+
+	mocks.repository.EXPECT().
+		DeleteExpired(ctx, deviceNow.Unix()).
+		Return(int64(0), nil)
+
+	mocks.repository.EXPECT().
+		CountPendingByClientIP(ctx, "192.168.1.10").
+		Return(int64(5), nil)
+
+	code, err := s.Start(ctx, deviceName, "192.168.1.10")
+
+	assert.Equal(t, core.ErrDeviceCodeLimit, err)
+	assert.Nil(t, code)
+}
+
+func TestDeviceAuthStartReturnsErrDeviceCodeLimit(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	s, mocks := newDeviceAuthService(ctrl)
+
+	mocks.repository.EXPECT().
+		DeleteExpired(ctx, deviceNow.Unix()).
+		Return(int64(0), nil)
+
+	mocks.repository.EXPECT().
+		CountPendingByClientIP(ctx, "192.168.1.10").
+		Return(int64(0), nil)
+
+	mocks.repository.EXPECT().
+		CountPending(ctx).
+		Return(int64(1000), nil)
+
+	code, err := s.Start(ctx, deviceName, "192.168.1.10")
+
+	assert.Equal(t, core.ErrDeviceCodeLimit, err)
+	assert.Nil(t, code)
+}
+
+func TestDeviceAuthApproveNormalizesUserCode(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	s, mocks := newDeviceAuthService(ctrl)
+
+	mocks.repository.EXPECT().
+		FindByUserCode(ctx, deviceUserCode).
+		Return(pendingDeviceAuth(), nil)
+
+	expected := pendingDeviceAuth()
+	expected.Status = core.DeviceAuthStatusApproved
+	expected.UserID = &deviceUserID
+	expected.Scopes = []core.Permission{core.PermissionStream}
+
+	mocks.repository.EXPECT().
+		Update(ctx, expected).
+		Return(nil)
+
+	auth, err := s.Approve(ctx, "bcdf-ghjk", userID, []core.Permission{core.PermissionStream})
+
+	assert.Nil(t, err)
+	assert.Equal(t, "BCDF-GHJK", auth.UserCode)
+	assert.Equal(t, core.DeviceAuthStatusApproved, auth.Status)
+}
+
+func TestDeviceAuthApproveReturnsErrTokenScopeInvalid(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	s, _ := newDeviceAuthService(ctrl)
+
+	auth, err := s.Approve(ctx, deviceUserCode, userID, []core.Permission{"unknown"})
+
+	assert.Equal(t, core.ErrTokenScopeInvalid, err)
+	assert.Nil(t, auth)
+}
+
+func TestDeviceAuthDenyReturnsErrDeviceUserCodeInvalid(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	expired := pendingDeviceAuth()
+	expired.ExpiresAt = deviceNow.Unix()
+
+	approved := pendingDeviceAuth()
+	approved.Status = core.DeviceAuthStatusApproved
+
+	for _, found := range []*core.DeviceAuthorization{nil, expired, approved} {
+		s, mocks := newDeviceAuthService(ctrl)
+
+		mocks.repository.EXPECT().
+			FindByUserCode(ctx, deviceUserCode).
+			Return(found, nil)
+
+		auth, err := s.Deny(ctx, deviceUserCode, userID)
+
+		assert.Equal(t, core.ErrDeviceUserCodeInvalid, err)
+		assert.Nil(t, auth)
+	}
+}
+
+func TestDeviceAuthPollReturnsErrDeviceCodeInvalid(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	s, mocks := newDeviceAuthService(ctrl)
+
+	mocks.repository.EXPECT().
+		FindByDeviceCode(ctx, gomock.Any()).
+		Return(nil, nil)
+
+	token, err := s.Poll(ctx, "someDeviceCode", clientIp, userAgent)
+
+	assert.Equal(t, core.ErrDeviceCodeInvalid, err)
+	assert.Nil(t, token)
+}
+
+func TestDeviceAuthPollReturnsErrDeviceAuthPending(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	s, mocks := newDeviceAuthService(ctrl)
+
+	mocks.repository.EXPECT().
+		FindByDeviceCode(ctx, gomock.Any()).
+		Return(pendingDeviceAuth(), nil)
+
+	expected := pendingDeviceAuth()
+	expected.LastPolledAt = deviceNow.Unix()
+	mocks.repository.EXPECT().
+		Update(ctx, expected).
+		Return(nil)
+
+	token, err := s.Poll(ctx, "someDeviceCode", clientIp, userAgent)
+
+	assert.Equal(t, core.ErrDeviceAuthPending, err)
+	assert.Nil(t, token)
+}
+
+func TestDeviceAuthPollReturnsErrDeviceAuthSlowDown(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	s, mocks := newDeviceAuthService(ctrl)
+
+	auth := pendingDeviceAuth()
+	auth.LastPolledAt = deviceNow.Unix() - 2
+	mocks.repository.EXPECT().
+		FindByDeviceCode(ctx, gomock.Any()).
+		Return(auth, nil)
+
+	mocks.repository.EXPECT().
+		Update(ctx, gomock.Any()).
+		Return(nil)
+
+	token, err := s.Poll(ctx, "someDeviceCode", clientIp, userAgent)
+
+	assert.Equal(t, core.ErrDeviceAuthSlowDown, err)
+	assert.Nil(t, token)
+}
+
+func TestDeviceAuthPollDeletesExpiredAndDeniedAuthorizations(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	expired := pendingDeviceAuth()
+	expired.ExpiresAt = deviceNow.Unix() - 1
+
+	denied := pendingDeviceAuth()
+	denied.Status = core.DeviceAuthStatusDenied
+
+	for auth, expectedErr := range map[*core.DeviceAuthorization]error{
+		expired: core.ErrDeviceAuthExpired,
+		denied:  core.ErrDeviceAuthDenied,
+	} {
+		s, mocks := newDeviceAuthService(ctrl)
+
+		mocks.repository.EXPECT().
+			FindByDeviceCode(ctx, gomock.Any()).
+			Return(auth, nil)
+
+		mocks.repository.EXPECT().
+			Delete(ctx, auth.ID).
+			Return(true, nil)
+
+		token, err := s.Poll(ctx, "someDeviceCode", clientIp, userAgent)
+
+		assert.Equal(t, expectedErr, err)
+		assert.Nil(t, token)
+	}
+}
+
+func TestDeviceAuthPollReturnsSession(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	s, mocks := newDeviceAuthService(ctrl)
+
+	auth := pendingDeviceAuth()
+	auth.Status = core.DeviceAuthStatusApproved
+	auth.UserID = &deviceUserID
+	mocks.repository.EXPECT().
+		FindByDeviceCode(ctx, gomock.Any()).
+		Return(auth, nil)
+
+	mocks.repository.EXPECT().
+		Delete(ctx, auth.ID).
+		Return(true, nil)
+
+	mocks.sessionManager.EXPECT().
+		CreateDeviceSession(ctx, userID, clientIp, userAgent, deviceName).
+		Return("someSessionToken", nil)
+
+	token, err := s.Poll(ctx, "someDeviceCode", clientIp, userAgent)
+
+	assert.Nil(t, err)
+	assert.Equal(t, &core.DeviceToken{
+		AccessToken: "someSessionToken",
+		TokenType:   core.DeviceTokenTypeSession,
+		UserID:      userID,
+		DeviceName:  deviceName,
+	}, token)
+}
+
+func TestDeviceAuthPollReturnsScopedToken(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	s, mocks := newDeviceAuthService(ctrl)
+
+	scopes := []core.Permission{core.PermissionEPGRead, core.PermissionStream}
+	auth := pendingDeviceAuth()
+	auth.Status = core.DeviceAuthStatusApproved
+	auth.UserID = &deviceUserID
+	auth.Scopes = scopes
+	mocks.repository.EXPECT().
+		FindByDeviceCode(ctx, gomock.Any()).
+		Return(auth, nil)
+
+	mocks.repository.EXPECT().
+		Delete(ctx, auth.ID).
+		Return(true, nil)
+
+	mocks.tokenService.EXPECT().
+		Create(ctx, userID, core.CreateToken{Name: deviceName, Scopes: scopes}).
+		Return("someApiToken", nil)
+
+	token, err := s.Poll(ctx, "someDeviceCode", clientIp, userAgent)
+
+	assert.Nil(t, err)
+	assert.Equal(t, &core.DeviceToken{
+		AccessToken: "someApiToken",
+		TokenType:   core.DeviceTokenTypeBearer,
+		Scopes:      scopes,
+		UserID:      userID,
+		DeviceName:  deviceName,
+	}, token)
+}
+
+func TestDeviceAuthPollDoesNotIssueTwice(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	s, mocks := newDeviceAuthService(ctrl)
+
+	auth := pendingDeviceAuth()
+	auth.Status = core.DeviceAuthStatusApproved
+	auth.UserID = &deviceUserID
+	mocks.repository.EXPECT().
+		FindByDeviceCode(ctx, gomock.Any()).
+		Return(auth, nil)
+
+	mocks.repository.EXPECT().
+		Delete(ctx, auth.ID).
+		Return(false, nil)
+
+	token, err := s.Poll(ctx, "someDeviceCode", clientIp, userAgent)
+
+	assert.Equal(t, core.ErrDeviceCodeInvalid, err)
+	assert.Nil(t, token)
+}
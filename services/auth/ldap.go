@@ -0,0 +1,382 @@
+package auth
+
+import (
+	"context"
+	"crypto/tls"
+	"fmt"
+	"net/url"
+	"slices"
+	"strings"
+
+	"github.com/davidborzek/tvhgo/config"
+	"github.com/davidborzek/tvhgo/core"
+	"github.com/go-ldap/ldap/v3"
+	"github.com/rs/zerolog/log"
+)
+
+type (
+	// ldapConn is the subset of an ldap connection used by the authenticator.
+	ldapConn interface {
+		Bind(username, password string) error
+		Search(req *ldap.SearchRequest) (*ldap.SearchResult, error)
+		Close() error
+	}
+
+	ldapDialFunc func(cfg *config.LDAPConfig) (ldapConn, error)
+
+	// ldapEntry is a user found in the directory.
+	ldapEntry struct {
+		dn          string
+		username    string
+		email       string
+		displayName string
+	}
+)
+
+type ldapPasswordAuthenticator struct {
+	cfg              *config.LDAPConfig
+	userRepository   core.UserRepository
+	twoFactorService core.TwoFactorAuthService
+	dial             ldapDialFunc
+}
+
+// NewLDAPPasswordAuthenticator creates a new PasswordAuthenticator which
+// verifies the credentials by a bind against an ldap server. Users are
+// created or updated locally on login.
+func NewLDAPPasswordAuthenticator(
+	cfg *config.LDAPConfig,
+	userRepository core.UserRepository,
+	twoFactorService core.TwoFactorAuthService,
+) *ldapPasswordAuthenticator {
+	return &ldapPasswordAuthenticator{
+		cfg:              cfg,
+		userRepository:   userRepository,
+		twoFactorService: twoFactorService,
+		dial:             dialLDAP,
+	}
+}
+
+func (s *ldapPasswordAuthenticator) Login(
+	ctx context.Context,
+	login string,
+	password string,
+	totp *string,
+) (*core.User, error) {
+	// An empty password results in an unauthenticated bind,
+	// which succeeds on most servers.
+	if password == "" {
+		return nil, core.ErrInvalidUsernameOrPassword
+	}
+
+	conn, err := s.dial(s.cfg)
+	if err != nil {
+		log.Error().Err(err).Msg("[ldap] failed to connect")
+
+		return nil, core.ErrUnexpectedError
+	}
+	defer conn.Close()
+
+	entry, err := s.authenticate(conn, login, password)
+	if err != nil {
+		return nil, err
+	}
+
+	groups, err := s.findGroups(conn, entry)
+	if err != nil {
+		log.Error().Str("user", login).
+			Err(err).Msg("[ldap] failed to find groups of user")
+
+		return nil, core.ErrUnexpectedError
+	}
+
+	// The user is only resolved before the second factor and the disabled
+	// check, so that the ldap password alone can't change the local user.
+	user, err := s.userRepository.FindByUsername(ctx, entry.username)
+	if err != nil {
+		log.Error().Str("user", login).
+			Err(err).Msg("[ldap] failed to find local user")
+
+		return nil, core.ErrUnexpectedError
+	}
+
+	if user != nil {
+		// Disabled users are rejected before the second factor, so
+		// that their recovery codes are not consumed.
+		if user.Disabled {
+			return nil, core.ErrUserDisabled
+		}
+
+		if err := s.twoFactorService.Verify(ctx, user.ID, totp); err != nil {
+			return nil, err
+		}
+	}
+
+	user, err = s.syncUser(ctx, user, entry, groups)
+	if err != nil {
+		log.Error().Str("user", login).
+			Err(err).Msg("[ldap] failed to sync user")
+
+		return nil, core.ErrUnexpectedError
+	}
+
+	return user, nil
+}
+
+func (s *ldapPasswordAuthenticator) ConfirmPassword(
+	ctx context.Context,
+	userID int64,
+	password string,
+) error {
+	if password == "" {
+		return core.ErrConfirmationPasswordInvalid
+	}
+
+	user, err := s.userRepository.FindById(ctx, userID)
+	if err != nil {
+		log.Error().Int64("userId", userID).
+			Err(err).Msg("failed to find user for password confirmation")
+
+		return core.ErrUnexpectedError
+	}
+
+	if user == nil {
+		return core.ErrConfirmationPasswordInvalid
+	}
+
+	conn, err := s.dial(s.cfg)
+	if err != nil {
+		log.Error().Err(err).Msg("[ldap] failed to connect")
+
+		return core.ErrUnexpectedError
+	}
+	defer conn.Close()
+
+	_, err = s.authenticate(conn, user.Username, password)
+	if err == core.ErrInvalidUsernameOrPassword {
+		return core.ErrConfirmationPasswordInvalid
+	}
+
+	return err
+}
+
+// authenticate searches the user with the service account
+// and verifies the password by a bind as the user.
+func (s *ldapPasswordAuthenticator) authenticate(
+	conn ldapConn,
+	login string,
+	password string,
+) (*ldapEntry, error) {
+	if s.cfg.BindDN != "" {
+		if err := conn.Bind(s.cfg.BindDN, s.cfg.BindPassword); err != nil {
+			log.Error().Err(err).Msg("[ldap] failed to bind with service account")
+
+			return nil, core.ErrUnexpectedError
+		}
+	}
+
+	entry, err := s.findUser(conn, login)
+	if err != nil {
+		log.Error().Str("user", login).
+			Err(err).Msg("[ldap] failed to find user")
+
+		return nil, core.ErrUnexpectedError
+	}
+
+	if entry == nil {
+		return nil, core.ErrInvalidUsernameOrPassword
+	}
+
+	if err := conn.Bind(entry.dn, password); err != nil {
+		if ldap.IsErrorWithCode(err, ldap.LDAPResultInvalidCredentials) {
+			return nil, core.ErrInvalidUsernameOrPassword
+		}
+
+		log.Error().Str("user", login).
+			Err(err).Msg("[ldap] failed to bind as user")
+
+		return nil, core.ErrUnexpectedError
+	}
+
+	return entry, nil
+}
+
+// findUser searches a user by the login and returns
+// nil when no or more than one user was found.
+func (s *ldapPasswordAuthenticator) findUser(conn ldapConn, login string) (*ldapEntry, error) {
+	filter := strings.ReplaceAll(s.cfg.UserFilter, "{username}", ldap.EscapeFilter(login))
+
+	res, err := conn.Search(ldap.NewSearchRequest(
+		s.cfg.BaseDN,
+		ldap.ScopeWholeSubtree,
+		ldap.NeverDerefAliases,
+		2,
+		0,
+		false,
+		filter,
+		[]string{
+			s.cfg.UsernameAttribute,
+			s.cfg.EmailAttribute,
+			s.cfg.DisplayNameAttribute,
+		},
+		nil,
+	))
+	if err != nil {
+		if ldap.IsErrorWithCode(err, ldap.LDAPResultNoSuchObject) {
+			return nil, nil
+		}
+		return nil, err
+	}
+
+	if len(res.Entries) != 1 {
+		if len(res.Entries) > 1 {
+			log.Warn().Str("user", login).
+				Msg("[ldap] user filter matches more than one user")
+		}
+		return nil, nil
+	}
+
+	e := res.Entries[0]
+	entry := &ldapEntry{
+		dn:          e.DN,
+		username:    e.GetAttributeValue(s.cfg.UsernameAttribute),
+		email:       e.GetAttributeValue(s.cfg.EmailAttribute),
+		displayName: e.GetAttributeValue(s.cfg.DisplayNameAttribute),
+	}
+
+	if entry.username == "" {
+		entry.username = login
+	}
+
+	return entry, nil
+}
+
+// findGroups returns the names of the groups of a user. Groups
+// are only looked up when admin groups are configured.
+func (s *ldapPasswordAuthenticator) findGroups(conn ldapConn, entry *ldapEntry) ([]string, error) {
+	if len(s.cfg.AdminGroups) == 0 {
+		return nil, nil
+	}
+
+	filter := strings.NewReplacer(
+		"{dn}", ldap.EscapeFilter(entry.dn),
+		"{username}", ldap.EscapeFilter(entry.username),
+	).Replace(s.cfg.GroupFilter)
+
+	res, err := conn.Search(ldap.NewSearchRequest(
+		s.cfg.GroupBaseDN,
+		ldap.ScopeWholeSubtree,
+		ldap.NeverDerefAliases,
+		0,
+		0,
+		false,
+		filter,
+		[]string{s.cfg.GroupNameAttribute},
+		nil,
+	))
+	if err != nil {
+		return nil, err
+	}
+
+	groups := make([]string, 0, len(res.Entries))
+	for _, e := range res.Entries {
+		groups = append(groups, e.GetAttributeValue(s.cfg.GroupNameAttribute))
+	}
+
+	return groups, nil
+}
+
+// syncUser creates the local user of an ldap user, if user is nil, or updates it.
+func (s *ldapPasswordAuthenticator) syncUser(
+	ctx context.Context,
+	user *core.User,
+	entry *ldapEntry,
+	groups []string,
+) (*core.User, error) {
+	displayName := entry.displayName
+	if displayName == "" {
+		displayName = entry.username
+	}
+
+	email := entry.email
+	if email == "" {
+		email = fmt.Sprintf("%s@tvhgo.local", entry.username)
+	}
+
+	if user == nil {
+		log.Debug().Str("username", entry.username).
+			Str("display_name", displayName).
+			Str("email", email).
+			Msg("[ldap] creating new user")
+
+		user = &core.User{
+			Username:    entry.username,
+			DisplayName: displayName,
+			Email:       email,
+			Role:        adminRole(s.isAdmin(groups)),
+		}
+
+		if err := s.userRepository.Create(ctx, user); err != nil {
+			return nil, err
+		}
+
+		return user, nil
+	}
+
+	changed := user.DisplayName != displayName || user.Email != email
+	user.DisplayName = displayName
+	user.Email = email
+
+	if len(s.cfg.AdminGroups) > 0 {
+		changed = syncAdminRole(user, s.isAdmin(groups)) || changed
+	}
+
+	if !changed {
+		return user, nil
+	}
+
+	log.Debug().Str("username", user.Username).
+		Msg("[ldap] updating user")
+
+	if err := s.userRepository.Update(ctx, user); err != nil {
+		return nil, err
+	}
+
+	return user, nil
+}
+
+// isAdmin checks if the groups contain one of the admin groups.
+func (s *ldapPasswordAuthenticator) isAdmin(groups []string) bool {
+	for _, group := range groups {
+		if slices.Contains(s.cfg.AdminGroups, group) {
+			return true
+		}
+	}
+
+	return false
+}
+
+func dialLDAP(cfg *config.LDAPConfig) (ldapConn, error) {
+	u, err := url.Parse(cfg.URL)
+	if err != nil {
+		return nil, err
+	}
+
+	tlsConfig := &tls.Config{
+		ServerName:         u.Hostname(),
+		InsecureSkipVerify: cfg.InsecureSkipVerify,
+	}
+
+	conn, err := ldap.DialURL(cfg.URL, ldap.DialWithTLSConfig(tlsConfig))
+	if err != nil {
+		return nil, err
+	}
+
+	if cfg.StartTLS {
+		if err := conn.StartTLS(tlsConfig); err != nil {
+			conn.Close()
+			return nil, err
+		}
+	}
+
+	return conn, nil
+}
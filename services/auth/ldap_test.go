@@ -0,0 +1,415 @@
+package auth
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/davidborzek/tvhgo/config"
+	"github.com/davidborzek/tvhgo/core"
+	mock_core "github.com/davidborzek/tvhgo/mock/core"
+	"github.com/go-ldap/ldap/v3"
+	"github.com/stretchr/testify/assert"
+	"go.uber.org/mock/gomock"
+)
+
+const (
+	ldapServiceDN       = "cn=tvhgo,dc=example,dc=com"
+	ldapServicePassword = "servicePassword"
+	ldapUserDN          = "uid=jdoe,ou=users,dc=example,dc=com"
+	ldapUserPassword    = "userPassword"
+)
+
+// fakeLDAPConn is a fake ldap server with a single user.
+type fakeLDAPConn struct {
+	passwords map[string]string
+	users     []*ldap.Entry
+	groups    []*ldap.Entry
+	searchErr error
+
+	bound    string
+	searches []*ldap.SearchRequest
+	closed   bool
+}
+
+func newFakeLDAPConn() *fakeLDAPConn {
+	return &fakeLDAPConn{
+		passwords: map[string]string{
+			ldapServiceDN: ldapServicePassword,
+			ldapUserDN:    ldapUserPassword,
+		},
+		users: []*ldap.Entry{
+			ldap.NewEntry(ldapUserDN, map[string][]string{
+				"uid":  {"jdoe"},
+				"mail": {"jdoe@example.com"},
+				"cn":   {"John Doe"},
+			}),
+		},
+		groups: []*ldap.Entry{
+			ldap.NewEntry("cn=tv-admins,ou=groups,dc=example,dc=com", map[string][]string{
+				"cn": {"tv-admins"},
+			}),
+		},
+	}
+}
+
+func (c *fakeLDAPConn) Bind(username, password string) error {
+	if p, ok := c.passwords[username]; !ok || p != password {
+		return ldap.NewError(ldap.LDAPResultInvalidCredentials, errors.New("invalid credentials"))
+	}
+
+	c.bound = username
+	return nil
+}
+
+func (c *fakeLDAPConn) Search(req *ldap.SearchRequest) (*ldap.SearchResult, error) {
+	c.searches = append(c.searches, req)
+
+	if c.searchErr != nil {
+		return nil, c.searchErr
+	}
+
+	if req.BaseDN == "ou=groups,dc=example,dc=com" {
+		return &ldap.SearchResult{Entries: c.groups}, nil
+	}
+
+	if req.Filter != "(&(objectClass=person)(uid=jdoe))" {
+		return &ldap.SearchResult{}, nil
+	}
+
+	return &ldap.SearchResult{Entries: c.users}, nil
+}
+
+func (c *fakeLDAPConn) Close() error {
+	c.closed = true
+	return nil
+}
+
+func newLDAPConfig() *config.LDAPConfig {
+	cfg := &config.LDAPConfig{
+		URL:          "ldap://localhost",
+		BindDN:       ldapServiceDN,
+		BindPassword: ldapServicePassword,
+		BaseDN:       "ou=users,dc=example,dc=com",
+		GroupBaseDN:  "ou=groups,dc=example,dc=com",
+		AdminGroups:  []string{"tv-admins"},
+	}
+	cfg.SetDefaults()
+	return cfg
+}
+
+func newTestLDAPAuthenticator(
+	cfg *config.LDAPConfig,
+	conn *fakeLDAPConn,
+	users core.UserRepository,
+	twoFactor core.TwoFactorAuthService,
+) *ldapPasswordAuthenticator {
+	a := NewLDAPPasswordAuthenticator(cfg, users, twoFactor)
+	a.dial = func(*config.LDAPConfig) (ldapConn, error) {
+		return conn, nil
+	}
+	return a
+}
+
+func TestLDAPLoginCreatesUser(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	ctx := context.TODO()
+	conn := newFakeLDAPConn()
+
+	mockUsers := mock_core.NewMockUserRepository(ctrl)
+	mockUsers.EXPECT().
+		FindByUsername(ctx, "jdoe").
+		Return(nil, nil)
+
+	mockUsers.EXPECT().
+		Create(ctx, &core.User{
+			Username:    "jdoe",
+			DisplayName: "John Doe",
+			Email:       "jdoe@example.com",
+			Role:        core.RoleAdmin,
+		}).
+		DoAndReturn(func(_ context.Context, user *core.User) error {
+			user.ID = 1
+			return nil
+		})
+
+	// New users can't have two factor auth enabled.
+	mockTwoFactor := mock_core.NewMockTwoFactorAuthService(ctrl)
+
+	a := newTestLDAPAuthenticator(newLDAPConfig(), conn, mockUsers, mockTwoFactor)
+
+	user, err := a.Login(ctx, "jdoe", ldapUserPassword, nil)
+	assert.Nil(t, err)
+	assert.Equal(t, int64(1), user.ID)
+	assert.Equal(t, ldapUserDN, conn.bound)
+	assert.True(t, conn.closed)
+
+	assert.Equal(t, "(&(objectClass=groupOfNames)(member=uid=jdoe,ou=users,dc=example,dc=com))",
+		conn.searches[1].Filter)
+}
+
+func TestLDAPLoginUpdatesExistingUser(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	ctx := context.TODO()
+	conn := newFakeLDAPConn()
+	conn.groups = nil
+
+	mockUsers := mock_core.NewMockUserRepository(ctrl)
+	mockUsers.EXPECT().
+		FindByUsername(ctx, "jdoe").
+		Return(&core.User{
+			ID:          1,
+			Username:    "jdoe",
+			DisplayName: "Old Name",
+			Email:       "jdoe@example.com",
+			Role:        core.RoleAdmin,
+		}, nil)
+
+	mockUsers.EXPECT().
+		Update(ctx, &core.User{
+			ID:          1,
+			Username:    "jdoe",
+			DisplayName: "John Doe",
+			Email:       "jdoe@example.com",
+			Role:        core.DefaultRole,
+		}).
+		Return(nil)
+
+	mockTwoFactor := mock_core.NewMockTwoFactorAuthService(ctrl)
+	mockTwoFactor.EXPECT().
+		Verify(ctx, int64(1), nil).
+		Return(nil)
+
+	a := newTestLDAPAuthenticator(newLDAPConfig(), conn, mockUsers, mockTwoFactor)
+
+	user, err := a.Login(ctx, "jdoe", ldapUserPassword, nil)
+	assert.Nil(t, err)
+	assert.Equal(t, core.DefaultRole, user.Role)
+}
+
+func TestLDAPLoginDoesNotManageAdminWithoutAdminGroups(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	ctx := context.TODO()
+	conn := newFakeLDAPConn()
+
+	existing := &core.User{
+		ID:          1,
+		Username:    "jdoe",
+		DisplayName: "John Doe",
+		Email:       "jdoe@example.com",
+		Role:        core.RoleAdmin,
+	}
+
+	mockUsers := mock_core.NewMockUserRepository(ctrl)
+	mockUsers.EXPECT().
+		FindByUsername(ctx, "jdoe").
+		Return(existing, nil)
+
+	mockTwoFactor := mock_core.NewMockTwoFactorAuthService(ctrl)
+	mockTwoFactor.EXPECT().
+		Verify(ctx, int64(1), nil).
+		Return(nil)
+
+	cfg := newLDAPConfig()
+	cfg.AdminGroups = nil
+
+	a := newTestLDAPAuthenticator(cfg, conn, mockUsers, mockTwoFactor)
+
+	user, err := a.Login(ctx, "jdoe", ldapUserPassword, nil)
+	assert.Nil(t, err)
+	assert.Equal(t, core.RoleAdmin, user.Role)
+	assert.Len(t, conn.searches, 1)
+}
+
+func TestLDAPLoginReturnsTwoFactorErrorWithoutSyncingUser(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	ctx := context.TODO()
+	conn := newFakeLDAPConn()
+
+	// The user would be promoted to admin by the sync.
+	mockUsers := mock_core.NewMockUserRepository(ctrl)
+	mockUsers.EXPECT().
+		FindByUsername(ctx, "jdoe").
+		Return(&core.User{
+			ID:          1,
+			Username:    "jdoe",
+			DisplayName: "John Doe",
+			Email:       "jdoe@example.com",
+			Role:        core.DefaultRole,
+		}, nil)
+
+	mockTwoFactor := mock_core.NewMockTwoFactorAuthService(ctrl)
+	mockTwoFactor.EXPECT().
+		Verify(ctx, int64(1), nil).
+		Return(core.ErrTwoFactorRequired)
+
+	a := newTestLDAPAuthenticator(newLDAPConfig(), conn, mockUsers, mockTwoFactor)
+
+	user, err := a.Login(ctx, "jdoe", ldapUserPassword, nil)
+	assert.Nil(t, user)
+	assert.Equal(t, core.ErrTwoFactorRequired, err)
+}
+
+func TestLDAPLoginReturnsUserDisabledBeforeSecondFactor(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	ctx := context.TODO()
+	conn := newFakeLDAPConn()
+
+	mockUsers := mock_core.NewMockUserRepository(ctrl)
+	mockUsers.EXPECT().
+		FindByUsername(ctx, "jdoe").
+		Return(&core.User{
+			ID:          1,
+			Username:    "jdoe",
+			DisplayName: "Old Name",
+			Email:       "jdoe@example.com",
+			Role:        core.DefaultRole,
+			Disabled:    true,
+		}, nil)
+
+	// The second factor is not verified for disabled users.
+	mockTwoFactor := mock_core.NewMockTwoFactorAuthService(ctrl)
+
+	a := newTestLDAPAuthenticator(newLDAPConfig(), conn, mockUsers, mockTwoFactor)
+
+	user, err := a.Login(ctx, "jdoe", ldapUserPassword, nil)
+	assert.Nil(t, user)
+	assert.Equal(t, core.ErrUserDisabled, err)
+}
+
+func TestLDAPLoginReturnsInvalidUsernameOrPassword(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	ctx := context.TODO()
+
+	a := newTestLDAPAuthenticator(
+		newLDAPConfig(),
+		newFakeLDAPConn(),
+		mock_core.NewMockUserRepository(ctrl),
+		mock_core.NewMockTwoFactorAuthService(ctrl),
+	)
+
+	for _, tc := range []struct {
+		login    string
+		password string
+	}{
+		{"jdoe", "wrongPassword"},
+		{"jdoe", ""},
+		{"unknown", ldapUserPassword},
+		{"jdoe)(uid=*", ldapUserPassword},
+	} {
+		user, err := a.Login(ctx, tc.login, tc.password, nil)
+		assert.Nil(t, user)
+		assert.Equal(t, core.ErrInvalidUsernameOrPassword, err)
+	}
+}
+
+func TestLDAPLoginEscapesUsername(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	conn := newFakeLDAPConn()
+	a := newTestLDAPAuthenticator(
+		newLDAPConfig(),
+		conn,
+		mock_core.NewMockUserRepository(ctrl),
+		mock_core.NewMockTwoFactorAuthService(ctrl),
+	)
+
+	_, err := a.Login(context.TODO(), "jdoe)(uid=*", ldapUserPassword, nil)
+	assert.Equal(t, core.ErrInvalidUsernameOrPassword, err)
+	assert.Equal(t, `(&(objectClass=person)(uid=jdoe\29\28uid=\2a))`, conn.searches[0].Filter)
+}
+
+func TestLDAPLoginReturnsUnexpectedErrorWhenServiceBindFails(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	cfg := newLDAPConfig()
+	cfg.BindPassword = "wrong"
+
+	a := newTestLDAPAuthenticator(
+		cfg,
+		newFakeLDAPConn(),
+		mock_core.NewMockUserRepository(ctrl),
+		mock_core.NewMockTwoFactorAuthService(ctrl),
+	)
+
+	user, err := a.Login(context.TODO(), "jdoe", ldapUserPassword, nil)
+	assert.Nil(t, user)
+	assert.Equal(t, core.ErrUnexpectedError, err)
+}
+
+func TestLDAPLoginReturnsUnexpectedErrorWhenSearchFails(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	conn := newFakeLDAPConn()
+	conn.searchErr = errors.New("error")
+
+	a := newTestLDAPAuthenticator(
+		newLDAPConfig(),
+		conn,
+		mock_core.NewMockUserRepository(ctrl),
+		mock_core.NewMockTwoFactorAuthService(ctrl),
+	)
+
+	user, err := a.Login(context.TODO(), "jdoe", ldapUserPassword, nil)
+	assert.Nil(t, user)
+	assert.Equal(t, core.ErrUnexpectedError, err)
+}
+
+func TestLDAPConfirmPassword(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	ctx := context.TODO()
+
+	mockUsers := mock_core.NewMockUserRepository(ctrl)
+	mockUsers.EXPECT().
+		FindById(ctx, int64(1)).
+		Return(&core.User{ID: 1, Username: "jdoe"}, nil).
+		Times(2)
+
+	a := newTestLDAPAuthenticator(
+		newLDAPConfig(),
+		newFakeLDAPConn(),
+		mockUsers,
+		mock_core.NewMockTwoFactorAuthService(ctrl),
+	)
+
+	assert.Nil(t, a.ConfirmPassword(ctx, 1, ldapUserPassword))
+	assert.Equal(t, core.ErrConfirmationPasswordInvalid, a.ConfirmPassword(ctx, 1, "wrongPassword"))
+}
+
+func TestLDAPConfirmPasswordReturnsInvalidForUnknownUser(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	ctx := context.TODO()
+
+	mockUsers := mock_core.NewMockUserRepository(ctrl)
+	mockUsers.EXPECT().
+		FindById(ctx, int64(1)).
+		Return(nil, nil)
+
+	a := newTestLDAPAuthenticator(
+		newLDAPConfig(),
+		newFakeLDAPConn(),
+		mockUsers,
+		mock_core.NewMockTwoFactorAuthService(ctrl),
+	)
+
+	assert.Equal(t, core.ErrConfirmationPasswordInvalid, a.ConfirmPassword(ctx, 1, ldapUserPassword))
+}
@@ -10,16 +10,19 @@ import (
 func NewLocalPasswordAuthenticator(
 	userRepository core.UserRepository,
 	twoFactorService core.TwoFactorAuthService,
+	passwordHasher core.PasswordHasher,
 ) *localPasswordAuthenticator {
 	return &localPasswordAuthenticator{
 		userRepository:   userRepository,
 		twoFactorService: twoFactorService,
+		passwordHasher:   passwordHasher,
 	}
 }
 
 type localPasswordAuthenticator struct {
 	userRepository   core.UserRepository
 	twoFactorService core.TwoFactorAuthService
+	passwordHasher   core.PasswordHasher
 }
 
 func (s *localPasswordAuthenticator) Login(
@@ -44,17 +47,56 @@ func (s *localPasswordAuthenticator) Login(
 		return nil, core.ErrInvalidUsernameOrPassword
 	}
 
-	if err := ComparePassword(password, user.PasswordHash); err != nil {
+	if err := s.passwordHasher.Compare(password, user.PasswordHash); err != nil {
 		return nil, core.ErrInvalidUsernameOrPassword
 	}
 
+	// Disabled users are rejected before the second factor, so that
+	// their recovery codes are not consumed.
+	if user.Disabled {
+		return nil, core.ErrUserDisabled
+	}
+
 	if err := s.twoFactorService.Verify(ctx, user.ID, totp); err != nil {
 		return nil, err
 	}
 
+	s.rehashPassword(ctx, user, password)
+
 	return user, nil
 }
 
+// rehashPassword rehashes the password of a user, if the hash uses an
+// older algorithm or weaker parameters. Errors are only logged, since
+// the login itself succeeded.
+func (s *localPasswordAuthenticator) rehashPassword(
+	ctx context.Context,
+	user *core.User,
+	password string,
+) {
+	if !s.passwordHasher.NeedsRehash(user.PasswordHash) {
+		return
+	}
+
+	hash, err := s.passwordHasher.Hash(password)
+	if err != nil {
+		log.Error().Int64("userId", user.ID).
+			Err(err).Msg("failed to rehash password")
+
+		return
+	}
+
+	user.PasswordHash = hash
+	if err := s.userRepository.Update(ctx, user); err != nil {
+		log.Error().Int64("userId", user.ID).
+			Err(err).Msg("failed to update rehashed password")
+
+		return
+	}
+
+	log.Info().Int64("userId", user.ID).Msg("rehashed password")
+}
+
 func (s *localPasswordAuthenticator) ConfirmPassword(
 	ctx context.Context,
 	userID int64,
@@ -68,7 +110,11 @@ func (s *localPasswordAuthenticator) ConfirmPassword(
 		return core.ErrUnexpectedError
 	}
 
-	if err := ComparePassword(password, user.PasswordHash); err != nil {
+	if user == nil {
+		return core.ErrConfirmationPasswordInvalid
+	}
+
+	if err := s.passwordHasher.Compare(password, user.PasswordHash); err != nil {
 		return core.ErrConfirmationPasswordInvalid
 	}
 
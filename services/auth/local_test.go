@@ -3,8 +3,10 @@ package auth_test
 import (
 	"context"
 	"errors"
+	"strings"
 	"testing"
 
+	"github.com/davidborzek/tvhgo/config"
 	"github.com/davidborzek/tvhgo/core"
 	mock_core "github.com/davidborzek/tvhgo/mock/core"
 	"github.com/davidborzek/tvhgo/services/auth"
@@ -21,6 +23,11 @@ const (
 var (
 	ctx = context.TODO()
 
+	bcryptHasher = auth.NewPasswordHasher(&config.PasswordConfig{
+		Algorithm:  config.PasswordAlgorithmBcrypt,
+		BcryptCost: 12,
+	})
+
 	expectedUser = core.User{
 		ID:           1234,
 		Username:     username,
@@ -46,7 +53,7 @@ func TestLocalPasswordAuthenticatorLoginReturnsUser(t *testing.T) {
 		Return(nil).
 		Times(1)
 
-	authenticator := auth.NewLocalPasswordAuthenticator(mockRepository, mockTwoFactorAuthService)
+	authenticator := auth.NewLocalPasswordAuthenticator(mockRepository, mockTwoFactorAuthService, bcryptHasher)
 
 	user, err := authenticator.Login(ctx, username, password, &totp)
 
@@ -54,6 +61,46 @@ func TestLocalPasswordAuthenticatorLoginReturnsUser(t *testing.T) {
 	assert.Equal(t, expectedUser, *user)
 }
 
+func TestLocalPasswordAuthenticatorLoginRehashesLegacyPassword(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	user := expectedUser
+
+	mockRepository := mock_core.NewMockUserRepository(ctrl)
+	mockRepository.EXPECT().
+		FindByUsername(ctx, username).
+		Return(&user, nil)
+
+	mockRepository.EXPECT().
+		Update(ctx, gomock.Any()).
+		DoAndReturn(func(_ context.Context, updated *core.User) error {
+			assert.True(t, strings.HasPrefix(updated.PasswordHash, "$argon2id$"))
+			return nil
+		})
+
+	mockTwoFactorAuthService := mock_core.NewMockTwoFactorAuthService(ctrl)
+	mockTwoFactorAuthService.EXPECT().
+		Verify(ctx, expectedUser.ID, nil).
+		Return(nil)
+
+	hasher := auth.NewPasswordHasher(&config.PasswordConfig{
+		Algorithm: config.PasswordAlgorithmArgon2id,
+		Argon2id: config.Argon2idConfig{
+			Memory:      1024,
+			Iterations:  1,
+			Parallelism: 1,
+		},
+	})
+
+	authenticator := auth.NewLocalPasswordAuthenticator(mockRepository, mockTwoFactorAuthService, hasher)
+
+	loggedIn, err := authenticator.Login(ctx, username, password, nil)
+
+	assert.Nil(t, err)
+	assert.Nil(t, hasher.Compare(password, loggedIn.PasswordHash))
+}
+
 func TestLocalPasswordAuthenticatorLoginReturnsErrWhenTwoFactorVerifyFails(t *testing.T) {
 	ctrl := gomock.NewController(t)
 	defer ctrl.Finish()
@@ -72,7 +119,7 @@ func TestLocalPasswordAuthenticatorLoginReturnsErrWhenTwoFactorVerifyFails(t *te
 		Return(errors.New("some error")).
 		Times(1)
 
-	authenticator := auth.NewLocalPasswordAuthenticator(mockRepository, mockTwoFactorAuthService)
+	authenticator := auth.NewLocalPasswordAuthenticator(mockRepository, mockTwoFactorAuthService, bcryptHasher)
 
 	user, err := authenticator.Login(ctx, username, password, &totp)
 
@@ -80,6 +127,30 @@ func TestLocalPasswordAuthenticatorLoginReturnsErrWhenTwoFactorVerifyFails(t *te
 	assert.Nil(t, user)
 }
 
+func TestLocalPasswordAuthenticatorLoginReturnsErrUserDisabled(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	disabledUser := expectedUser
+	disabledUser.Disabled = true
+
+	mockRepository := mock_core.NewMockUserRepository(ctrl)
+	mockRepository.EXPECT().
+		FindByUsername(ctx, username).
+		Return(&disabledUser, nil).
+		Times(1)
+
+	// The second factor is not verified for disabled users.
+	mockTwoFactorAuthService := mock_core.NewMockTwoFactorAuthService(ctrl)
+
+	authenticator := auth.NewLocalPasswordAuthenticator(mockRepository, mockTwoFactorAuthService, bcryptHasher)
+
+	user, err := authenticator.Login(ctx, username, password, nil)
+
+	assert.Equal(t, core.ErrUserDisabled, err)
+	assert.Nil(t, user)
+}
+
 func TestLocalPasswordAuthenticatorLoginReturnsErrInvalidUsernameOrPasswordWhenUserWasNotFound(
 	t *testing.T,
 ) {
@@ -93,7 +164,7 @@ func TestLocalPasswordAuthenticatorLoginReturnsErrInvalidUsernameOrPasswordWhenU
 		Times(1)
 
 	mockTwoFactorAuthService := mock_core.NewMockTwoFactorAuthService(ctrl)
-	authenticator := auth.NewLocalPasswordAuthenticator(mockRepository, mockTwoFactorAuthService)
+	authenticator := auth.NewLocalPasswordAuthenticator(mockRepository, mockTwoFactorAuthService, bcryptHasher)
 
 	user, err := authenticator.Login(ctx, username, password, nil)
 
@@ -112,7 +183,7 @@ func TestLocalPasswordAuthenticatorLoginReturnsErrUnexpectedError(t *testing.T)
 		Times(1)
 
 	mockTwoFactorAuthService := mock_core.NewMockTwoFactorAuthService(ctrl)
-	authenticator := auth.NewLocalPasswordAuthenticator(mockRepository, mockTwoFactorAuthService)
+	authenticator := auth.NewLocalPasswordAuthenticator(mockRepository, mockTwoFactorAuthService, bcryptHasher)
 
 	user, err := authenticator.Login(ctx, username, password, nil)
 
@@ -134,7 +205,7 @@ func TestLocalPasswordAuthenticatorLoginReturnsErrInvalidUsernameOrPasswordForIn
 
 	mockTwoFactorAuthService := mock_core.NewMockTwoFactorAuthService(ctrl)
 
-	authenticator := auth.NewLocalPasswordAuthenticator(mockRepository, mockTwoFactorAuthService)
+	authenticator := auth.NewLocalPasswordAuthenticator(mockRepository, mockTwoFactorAuthService, bcryptHasher)
 
 	user, err := authenticator.Login(ctx, username, "invalid password", nil)
 
@@ -154,7 +225,7 @@ func TestLocalPasswordAuthenticatorConfirmPasswordSucceeds(t *testing.T) {
 
 	mockTwoFactorAuthService := mock_core.NewMockTwoFactorAuthService(ctrl)
 
-	authenticator := auth.NewLocalPasswordAuthenticator(mockRepository, mockTwoFactorAuthService)
+	authenticator := auth.NewLocalPasswordAuthenticator(mockRepository, mockTwoFactorAuthService, bcryptHasher)
 
 	err := authenticator.ConfirmPassword(ctx, expectedUser.ID, password)
 
@@ -173,13 +244,32 @@ func TestLocalPasswordAuthenticatorConfirmPasswordReturnsErrConfirmationPassword
 
 	mockTwoFactorAuthService := mock_core.NewMockTwoFactorAuthService(ctrl)
 
-	authenticator := auth.NewLocalPasswordAuthenticator(mockRepository, mockTwoFactorAuthService)
+	authenticator := auth.NewLocalPasswordAuthenticator(mockRepository, mockTwoFactorAuthService, bcryptHasher)
 
 	err := authenticator.ConfirmPassword(ctx, expectedUser.ID, "invalid")
 
 	assert.Equal(t, core.ErrConfirmationPasswordInvalid, err)
 }
 
+func TestLocalPasswordAuthenticatorConfirmPasswordReturnsErrConfirmationPasswordInvalidForUnknownUser(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockRepository := mock_core.NewMockUserRepository(ctrl)
+	mockRepository.EXPECT().
+		FindById(ctx, expectedUser.ID).
+		Return(nil, nil).
+		Times(1)
+
+	mockTwoFactorAuthService := mock_core.NewMockTwoFactorAuthService(ctrl)
+
+	authenticator := auth.NewLocalPasswordAuthenticator(mockRepository, mockTwoFactorAuthService, bcryptHasher)
+
+	err := authenticator.ConfirmPassword(ctx, expectedUser.ID, password)
+
+	assert.Equal(t, core.ErrConfirmationPasswordInvalid, err)
+}
+
 func TestLocalPasswordAuthenticatorConfirmPasswordReturnsErrUnexpectedError(t *testing.T) {
 	ctrl := gomock.NewController(t)
 	defer ctrl.Finish()
@@ -192,7 +282,7 @@ func TestLocalPasswordAuthenticatorConfirmPasswordReturnsErrUnexpectedError(t *t
 
 	mockTwoFactorAuthService := mock_core.NewMockTwoFactorAuthService(ctrl)
 
-	authenticator := auth.NewLocalPasswordAuthenticator(mockRepository, mockTwoFactorAuthService)
+	authenticator := auth.NewLocalPasswordAuthenticator(mockRepository, mockTwoFactorAuthService, bcryptHasher)
 
 	err := authenticator.ConfirmPassword(ctx, expectedUser.ID, password)
 
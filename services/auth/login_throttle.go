@@ -0,0 +1,210 @@
+package auth
+
+import (
+	"context"
+	"math"
+	"strings"
+	"time"
+
+	"github.com/davidborzek/tvhgo/config"
+	"github.com/davidborzek/tvhgo/core"
+	"github.com/rs/zerolog/log"
+)
+
+type loginThrottleService struct {
+	repository core.LoginThrottleRepository
+	clock      core.Clock
+	cfg        *config.LoginThrottleConfig
+}
+
+type loginThrottleKey struct {
+	kind core.LoginThrottleKind
+	key  string
+}
+
+func NewLoginThrottleService(
+	repository core.LoginThrottleRepository,
+	clock core.Clock,
+	cfg *config.LoginThrottleConfig,
+) core.LoginThrottleService {
+	return &loginThrottleService{
+		repository: repository,
+		clock:      clock,
+		cfg:        cfg,
+	}
+}
+
+func (s *loginThrottleService) Check(ctx context.Context, username string, clientIP string) error {
+	if !s.enabled() {
+		return nil
+	}
+
+	var blockedUntil int64
+	for _, k := range loginThrottleKeys(username, clientIP) {
+		throttle, err := s.repository.Find(ctx, k.kind, k.key)
+		if err != nil {
+			log.Error().Err(err).Str("kind", string(k.kind)).
+				Msg("could not get login throttle")
+
+			return core.ErrUnexpectedError
+		}
+
+		if throttle != nil && throttle.BlockedUntil > blockedUntil {
+			blockedUntil = throttle.BlockedUntil
+		}
+	}
+
+	now := s.clock.Now().Unix()
+	if blockedUntil > now {
+		return core.LoginThrottledError{
+			RetryAfter: time.Duration(blockedUntil-now) * time.Second,
+		}
+	}
+
+	return nil
+}
+
+func (s *loginThrottleService) RecordFailure(ctx context.Context, username string, clientIP string) error {
+	if !s.enabled() {
+		return nil
+	}
+
+	now := s.clock.Now().Unix()
+	resetBefore := now - int64(s.cfg.LockoutDuration.Seconds())
+
+	if err := s.repository.DeleteInactive(ctx, resetBefore); err != nil {
+		log.Error().Err(err).Msg("could not delete inactive login throttles")
+	}
+
+	for _, k := range loginThrottleKeys(username, clientIP) {
+		throttle, err := s.repository.RecordFailure(ctx, k.kind, k.key, now, resetBefore)
+		if err != nil {
+			log.Error().Err(err).Str("kind", string(k.kind)).
+				Msg("could not record failed login")
+
+			return core.ErrUnexpectedError
+		}
+
+		blockedUntil := now + s.blockDuration(throttle)
+		if err := s.repository.Block(ctx, throttle.ID, blockedUntil); err != nil {
+			log.Error().Err(err).Int64("id", throttle.ID).
+				Msg("could not block login")
+
+			return core.ErrUnexpectedError
+		}
+
+		if s.isLocked(throttle) {
+			log.Warn().Str("kind", string(k.kind)).Str("key", k.key).
+				Int("attempts", throttle.FailedAttempts).
+				Msg("login locked out after too many failed attempts")
+		}
+	}
+
+	return nil
+}
+
+func (s *loginThrottleService) RecordSuccess(ctx context.Context, username string) error {
+	if !s.enabled() {
+		return nil
+	}
+
+	err := s.repository.Delete(ctx, core.LoginThrottleKindUsername, normalizeUsername(username))
+	if err != nil {
+		log.Error().Err(err).Msg("could not reset login throttle")
+
+		return core.ErrUnexpectedError
+	}
+
+	return nil
+}
+
+func (s *loginThrottleService) List(ctx context.Context) ([]*core.LoginThrottle, error) {
+	throttles, err := s.repository.FindBlocked(ctx, s.clock.Now().Unix())
+	if err != nil {
+		log.Error().Err(err).Msg("could not get login throttles")
+
+		return nil, core.ErrUnexpectedError
+	}
+
+	for _, throttle := range throttles {
+		throttle.Locked = s.isLocked(throttle)
+	}
+
+	return throttles, nil
+}
+
+func (s *loginThrottleService) Clear(ctx context.Context, id int64) error {
+	deleted, err := s.repository.DeleteByID(ctx, id)
+	if err != nil {
+		log.Error().Err(err).Int64("id", id).
+			Msg("could not delete login throttle")
+
+		return core.ErrUnexpectedError
+	}
+
+	if !deleted {
+		return core.ErrLoginThrottleNotFound
+	}
+
+	return nil
+}
+
+func (s *loginThrottleService) enabled() bool {
+	return s.cfg.Enabled == nil || *s.cfg.Enabled
+}
+
+// maxAttempts returns the failed attempts after which the client ip or username is locked out.
+func (s *loginThrottleService) maxAttempts(kind core.LoginThrottleKind) int {
+	if kind == core.LoginThrottleKindIP {
+		return s.cfg.MaxAttemptsPerIP
+	}
+
+	return s.cfg.MaxAttemptsPerUsername
+}
+
+func (s *loginThrottleService) isLocked(throttle *core.LoginThrottle) bool {
+	return throttle.FailedAttempts >= s.maxAttempts(throttle.Kind)
+}
+
+// blockDuration returns the seconds further logins are blocked after a failed attempt.
+// The duration is doubled on each failed attempt until the lockout.
+func (s *loginThrottleService) blockDuration(throttle *core.LoginThrottle) int64 {
+	if s.isLocked(throttle) {
+		return int64(s.cfg.LockoutDuration.Seconds())
+	}
+
+	delay := s.cfg.BaseDelay
+	for i := 1; i < throttle.FailedAttempts && delay < s.cfg.MaxDelay; i++ {
+		delay *= 2
+	}
+	delay = min(delay, s.cfg.MaxDelay)
+
+	return int64(math.Ceil(delay.Seconds()))
+}
+
+// loginThrottleKeys returns the throttled keys of a login attempt.
+func loginThrottleKeys(username string, clientIP string) []loginThrottleKey {
+	keys := make([]loginThrottleKey, 0, 2)
+
+	if username != "" {
+		keys = append(keys, loginThrottleKey{
+			kind: core.LoginThrottleKindUsername,
+			key:  normalizeUsername(username),
+		})
+	}
+
+	if clientIP != "" {
+		keys = append(keys, loginThrottleKey{
+			kind: core.LoginThrottleKindIP,
+			key:  clientIP,
+		})
+	}
+
+	return keys
+}
+
+// normalizeUsername prevents to bypass the throttling of a username
+// with a different case, because ldap usernames are case insensitive.
+func normalizeUsername(username string) string {
+	return strings.ToLower(strings.TrimSpace(username))
+}
@@ -0,0 +1,255 @@
+package auth_test
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/davidborzek/tvhgo/config"
+	"github.com/davidborzek/tvhgo/core"
+	mock_core "github.com/davidborzek/tvhgo/mock/core"
+	"github.com/davidborzek/tvhgo/services/auth"
+	"github.com/stretchr/testify/assert"
+	"go.uber.org/mock/gomock"
+)
+
+const (
+	throttleUsername = "jdoe"
+	throttleIP       = "192.168.1.1"
+)
+
+var throttleNow = time.Unix(1672527600, 0)
+
+func newLoginThrottleConfig() *config.LoginThrottleConfig {
+	cfg := &config.LoginThrottleConfig{}
+	cfg.SetDefaults()
+	return cfg
+}
+
+func newLoginThrottleClock(ctrl *gomock.Controller) core.Clock {
+	mockClock := mock_core.NewMockClock(ctrl)
+	mockClock.EXPECT().
+		Now().
+		Return(throttleNow).
+		AnyTimes()
+
+	return mockClock
+}
+
+func TestLoginThrottleCheckReturnsNilWhenNotBlocked(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockRepository := mock_core.NewMockLoginThrottleRepository(ctrl)
+	mockRepository.EXPECT().
+		Find(ctx, core.LoginThrottleKindUsername, throttleUsername).
+		Return(nil, nil)
+	mockRepository.EXPECT().
+		Find(ctx, core.LoginThrottleKindIP, throttleIP).
+		Return(&core.LoginThrottle{BlockedUntil: throttleNow.Unix()}, nil)
+
+	s := auth.NewLoginThrottleService(mockRepository, newLoginThrottleClock(ctrl), newLoginThrottleConfig())
+
+	assert.Nil(t, s.Check(ctx, throttleUsername, throttleIP))
+}
+
+func TestLoginThrottleCheckReturnsLongestRetryAfter(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockRepository := mock_core.NewMockLoginThrottleRepository(ctrl)
+	mockRepository.EXPECT().
+		Find(ctx, core.LoginThrottleKindUsername, throttleUsername).
+		Return(&core.LoginThrottle{BlockedUntil: throttleNow.Unix() + 900}, nil)
+	mockRepository.EXPECT().
+		Find(ctx, core.LoginThrottleKindIP, throttleIP).
+		Return(&core.LoginThrottle{BlockedUntil: throttleNow.Unix() + 4}, nil)
+
+	s := auth.NewLoginThrottleService(mockRepository, newLoginThrottleClock(ctrl), newLoginThrottleConfig())
+
+	err := s.Check(ctx, "JDoe", throttleIP)
+	assert.Equal(t, core.LoginThrottledError{RetryAfter: 15 * time.Minute}, err)
+	assert.ErrorIs(t, err, core.ErrLoginThrottled)
+}
+
+func TestLoginThrottleCheckReturnsErrUnexpectedError(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockRepository := mock_core.NewMockLoginThrottleRepository(ctrl)
+	mockRepository.EXPECT().
+		Find(ctx, core.LoginThrottleKindUsername, throttleUsername).
+		Return(nil, errors.New("some error"))
+
+	s := auth.NewLoginThrottleService(mockRepository, newLoginThrottleClock(ctrl), newLoginThrottleConfig())
+
+	assert.Equal(t, core.ErrUnexpectedError, s.Check(ctx, throttleUsername, throttleIP))
+}
+
+func TestLoginThrottleIsNoopWhenDisabled(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	cfg := newLoginThrottleConfig()
+	disabled := false
+	cfg.Enabled = &disabled
+
+	s := auth.NewLoginThrottleService(
+		mock_core.NewMockLoginThrottleRepository(ctrl),
+		mock_core.NewMockClock(ctrl),
+		cfg,
+	)
+
+	assert.Nil(t, s.Check(ctx, throttleUsername, throttleIP))
+	assert.Nil(t, s.RecordFailure(ctx, throttleUsername, throttleIP))
+	assert.Nil(t, s.RecordSuccess(ctx, throttleUsername))
+}
+
+func TestLoginThrottleRecordFailureBlocksWithExponentialBackoff(t *testing.T) {
+	for _, tc := range []struct {
+		attempts int
+		blocked  int64
+	}{
+		{1, 1},
+		{2, 2},
+		{3, 4},
+		{4, 8},
+	} {
+		ctrl := gomock.NewController(t)
+
+		now := throttleNow.Unix()
+		resetBefore := now - 900
+
+		mockRepository := mock_core.NewMockLoginThrottleRepository(ctrl)
+		mockRepository.EXPECT().
+			DeleteInactive(ctx, resetBefore).
+			Return(nil)
+		mockRepository.EXPECT().
+			RecordFailure(ctx, core.LoginThrottleKindUsername, throttleUsername, now, resetBefore).
+			Return(&core.LoginThrottle{
+				ID:             1,
+				Kind:           core.LoginThrottleKindUsername,
+				FailedAttempts: tc.attempts,
+			}, nil)
+		mockRepository.EXPECT().
+			Block(ctx, int64(1), now+tc.blocked).
+			Return(nil)
+		mockRepository.EXPECT().
+			RecordFailure(ctx, core.LoginThrottleKindIP, throttleIP, now, resetBefore).
+			Return(&core.LoginThrottle{
+				ID:             2,
+				Kind:           core.LoginThrottleKindIP,
+				FailedAttempts: tc.attempts,
+			}, nil)
+		mockRepository.EXPECT().
+			Block(ctx, int64(2), now+tc.blocked).
+			Return(nil)
+
+		s := auth.NewLoginThrottleService(mockRepository, newLoginThrottleClock(ctrl), newLoginThrottleConfig())
+
+		assert.Nil(t, s.RecordFailure(ctx, throttleUsername, throttleIP))
+		ctrl.Finish()
+	}
+}
+
+func TestLoginThrottleRecordFailureLocksOutAfterMaxAttempts(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	now := throttleNow.Unix()
+	resetBefore := now - 900
+
+	mockRepository := mock_core.NewMockLoginThrottleRepository(ctrl)
+	mockRepository.EXPECT().
+		DeleteInactive(ctx, resetBefore).
+		Return(nil)
+	mockRepository.EXPECT().
+		RecordFailure(ctx, core.LoginThrottleKindUsername, throttleUsername, now, resetBefore).
+		Return(&core.LoginThrottle{
+			ID:             1,
+			Kind:           core.LoginThrottleKindUsername,
+			FailedAttempts: 5,
+		}, nil)
+	mockRepository.EXPECT().
+		Block(ctx, int64(1), now+900).
+		Return(nil)
+
+	s := auth.NewLoginThrottleService(mockRepository, newLoginThrottleClock(ctrl), newLoginThrottleConfig())
+
+	assert.Nil(t, s.RecordFailure(ctx, throttleUsername, ""))
+}
+
+func TestLoginThrottleRecordFailureCapsDelay(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	now := throttleNow.Unix()
+	resetBefore := now - 900
+
+	mockRepository := mock_core.NewMockLoginThrottleRepository(ctrl)
+	mockRepository.EXPECT().
+		DeleteInactive(ctx, resetBefore).
+		Return(nil)
+	mockRepository.EXPECT().
+		RecordFailure(ctx, core.LoginThrottleKindIP, throttleIP, now, resetBefore).
+		Return(&core.LoginThrottle{
+			ID:             2,
+			Kind:           core.LoginThrottleKindIP,
+			FailedAttempts: 19,
+		}, nil)
+	mockRepository.EXPECT().
+		Block(ctx, int64(2), now+60).
+		Return(nil)
+
+	s := auth.NewLoginThrottleService(mockRepository, newLoginThrottleClock(ctrl), newLoginThrottleConfig())
+
+	assert.Nil(t, s.RecordFailure(ctx, "", throttleIP))
+}
+
+func TestLoginThrottleRecordSuccessResetsUsername(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockRepository := mock_core.NewMockLoginThrottleRepository(ctrl)
+	mockRepository.EXPECT().
+		Delete(ctx, core.LoginThrottleKindUsername, throttleUsername).
+		Return(nil)
+
+	s := auth.NewLoginThrottleService(mockRepository, newLoginThrottleClock(ctrl), newLoginThrottleConfig())
+
+	assert.Nil(t, s.RecordSuccess(ctx, "JDoe"))
+}
+
+func TestLoginThrottleListMarksLockouts(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockRepository := mock_core.NewMockLoginThrottleRepository(ctrl)
+	mockRepository.EXPECT().
+		FindBlocked(ctx, throttleNow.Unix()).
+		Return([]*core.LoginThrottle{
+			{ID: 1, Kind: core.LoginThrottleKindUsername, FailedAttempts: 5},
+			{ID: 2, Kind: core.LoginThrottleKindIP, FailedAttempts: 5},
+		}, nil)
+
+	s := auth.NewLoginThrottleService(mockRepository, newLoginThrottleClock(ctrl), newLoginThrottleConfig())
+
+	throttles, err := s.List(ctx)
+	assert.Nil(t, err)
+	assert.True(t, throttles[0].Locked)
+	assert.False(t, throttles[1].Locked)
+}
+
+func TestLoginThrottleClearReturnsNotFound(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockRepository := mock_core.NewMockLoginThrottleRepository(ctrl)
+	mockRepository.EXPECT().
+		DeleteByID(ctx, int64(1)).
+		Return(false, nil)
+
+	s := auth.NewLoginThrottleService(mockRepository, newLoginThrottleClock(ctrl), newLoginThrottleConfig())
+
+	assert.Equal(t, core.ErrLoginThrottleNotFound, s.Clear(ctx, 1))
+}
@@ -0,0 +1,220 @@
+package auth
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/base64"
+	"fmt"
+	"slices"
+	"sync"
+
+	"github.com/coreos/go-oidc/v3/oidc"
+	"github.com/davidborzek/tvhgo/config"
+	"github.com/davidborzek/tvhgo/core"
+	"github.com/rs/zerolog/log"
+	"golang.org/x/oauth2"
+)
+
+type oidcAuthenticator struct {
+	cfg   *config.OIDCConfig
+	users core.UserRepository
+
+	mu       sync.Mutex
+	provider *oidc.Provider
+}
+
+// NewOIDCAuthenticator creates a new OIDCAuthenticator. The provider
+// is discovered on the first login, so tvhgo can start while the
+// provider is unavailable.
+func NewOIDCAuthenticator(
+	cfg *config.OIDCConfig,
+	users core.UserRepository,
+) core.OIDCAuthenticator {
+	return &oidcAuthenticator{
+		cfg:   cfg,
+		users: users,
+	}
+}
+
+func (a *oidcAuthenticator) AuthCodeURL(ctx context.Context) (*core.OIDCAuthRequest, error) {
+	oauth2Config, err := a.oauth2Config(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	req := &core.OIDCAuthRequest{
+		State:    randomToken(),
+		Nonce:    randomToken(),
+		Verifier: oauth2.GenerateVerifier(),
+	}
+
+	req.URL = oauth2Config.AuthCodeURL(
+		req.State,
+		oidc.Nonce(req.Nonce),
+		oauth2.S256ChallengeOption(req.Verifier),
+	)
+
+	return req, nil
+}
+
+func (a *oidcAuthenticator) Exchange(
+	ctx context.Context,
+	code string,
+	authRequest core.OIDCAuthRequest,
+) (*core.User, error) {
+	oauth2Config, err := a.oauth2Config(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	token, err := oauth2Config.Exchange(ctx, code, oauth2.VerifierOption(authRequest.Verifier))
+	if err != nil {
+		return nil, err
+	}
+
+	rawIDToken, ok := token.Extra("id_token").(string)
+	if !ok {
+		return nil, fmt.Errorf("oidc token response contains no id token")
+	}
+
+	idToken, err := a.provider.
+		Verifier(&oidc.Config{ClientID: a.cfg.ClientID}).
+		Verify(ctx, rawIDToken)
+	if err != nil {
+		return nil, err
+	}
+
+	if idToken.Nonce != authRequest.Nonce {
+		return nil, core.ErrOIDCStateInvalid
+	}
+
+	var claims map[string]any
+	if err := idToken.Claims(&claims); err != nil {
+		return nil, err
+	}
+
+	username, _ := claims[a.cfg.UsernameClaim].(string)
+	if username == "" {
+		return nil, core.ErrOIDCUsernameMissing
+	}
+
+	user, err := a.users.FindByUsername(ctx, username)
+	if err != nil {
+		return nil, err
+	}
+
+	if user == nil {
+		if !a.cfg.AllowRegistration {
+			return nil, core.ErrOIDCRegistrationDisabled
+		}
+
+		user, err = a.createUser(ctx, username, claims)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	if user.Disabled {
+		return nil, core.ErrUserDisabled
+	}
+
+	if err := a.syncAdmin(ctx, user, claims); err != nil {
+		return nil, err
+	}
+
+	return user, nil
+}
+
+// createUser provisions a new user from the id token claims.
+func (a *oidcAuthenticator) createUser(
+	ctx context.Context,
+	username string,
+	claims map[string]any,
+) (*core.User, error) {
+	displayName, _ := claims["name"].(string)
+	if displayName == "" {
+		displayName = username
+	}
+
+	email, _ := claims["email"].(string)
+	if email == "" {
+		email = fmt.Sprintf("%s@tvhgo.local", username)
+	}
+
+	log.Debug().Str("username", username).
+		Str("display_name", displayName).
+		Str("email", email).
+		Msg("[oidc] creating new user")
+
+	user := &core.User{
+		Username:    username,
+		DisplayName: displayName,
+		Email:       email,
+		Role:        adminRole(a.isAdmin(claims)),
+	}
+
+	if err := a.users.Create(ctx, user); err != nil {
+		return nil, err
+	}
+
+	return user, nil
+}
+
+// syncAdmin updates the admin role of a user from the groups claim.
+func (a *oidcAuthenticator) syncAdmin(ctx context.Context, user *core.User, claims map[string]any) error {
+	if len(a.cfg.AdminGroups) == 0 {
+		return nil
+	}
+
+	if !syncAdminRole(user, a.isAdmin(claims)) {
+		return nil
+	}
+
+	log.Info().Str("username", user.Username).
+		Str("role", string(user.Role)).
+		Msg("[oidc] updating role of user")
+
+	return a.users.Update(ctx, user)
+}
+
+// isAdmin checks if the groups claim contains one of the admin groups.
+func (a *oidcAuthenticator) isAdmin(claims map[string]any) bool {
+	groups, _ := claims[a.cfg.GroupsClaim].([]any)
+	for _, group := range groups {
+		if g, ok := group.(string); ok && slices.Contains(a.cfg.AdminGroups, g) {
+			return true
+		}
+	}
+
+	return false
+}
+
+// oauth2Config returns the oauth2 config of the discovered provider.
+func (a *oidcAuthenticator) oauth2Config(ctx context.Context) (*oauth2.Config, error) {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+
+	if a.provider == nil {
+		provider, err := oidc.NewProvider(ctx, a.cfg.Issuer)
+		if err != nil {
+			return nil, err
+		}
+
+		a.provider = provider
+	}
+
+	return &oauth2.Config{
+		ClientID:     a.cfg.ClientID,
+		ClientSecret: a.cfg.ClientSecret,
+		RedirectURL:  a.cfg.RedirectURL,
+		Endpoint:     a.provider.Endpoint(),
+		Scopes:       a.cfg.Scopes,
+	}, nil
+}
+
+// randomToken generates a random url safe token.
+func randomToken() string {
+	b := make([]byte, 32)
+	rand.Read(b)
+	return base64.RawURLEncoding.EncodeToString(b)
+}
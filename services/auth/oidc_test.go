@@ -0,0 +1,373 @@
+package auth_test
+
+import (
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"testing"
+	"time"
+
+	"github.com/davidborzek/tvhgo/config"
+	"github.com/davidborzek/tvhgo/core"
+	mock_core "github.com/davidborzek/tvhgo/mock/core"
+	"github.com/davidborzek/tvhgo/services/auth"
+	"github.com/go-jose/go-jose/v4"
+	"github.com/stretchr/testify/assert"
+	"go.uber.org/mock/gomock"
+)
+
+const (
+	oidcClientID    = "tvhgo"
+	oidcCode        = "someCode"
+	oidcRedirectURL = "https://tvhgo.example.com/api/auth/oidc/callback"
+)
+
+// mockOIDCProvider is a minimal OIDC provider which issues
+// an id token with the configured claims for a single code.
+type mockOIDCProvider struct {
+	*httptest.Server
+
+	t      *testing.T
+	key    *rsa.PrivateKey
+	claims map[string]any
+
+	// challenge is the PKCE challenge of the last authorization request.
+	challenge string
+}
+
+func newMockOIDCProvider(t *testing.T, claims map[string]any) *mockOIDCProvider {
+	key, err := rsa.GenerateKey(rand.Reader, 2048)
+	assert.Nil(t, err)
+
+	p := &mockOIDCProvider{t: t, key: key, claims: claims}
+
+	mux := http.NewServeMux()
+	mux.HandleFunc("/.well-known/openid-configuration", p.discovery)
+	mux.HandleFunc("/jwks", p.jwks)
+	mux.HandleFunc("/token", p.token)
+
+	p.Server = httptest.NewServer(mux)
+	t.Cleanup(p.Close)
+
+	return p
+}
+
+func (p *mockOIDCProvider) discovery(w http.ResponseWriter, r *http.Request) {
+	json.NewEncoder(w).Encode(map[string]any{
+		"issuer":                                p.URL,
+		"authorization_endpoint":                p.URL + "/authorize",
+		"token_endpoint":                        p.URL + "/token",
+		"jwks_uri":                              p.URL + "/jwks",
+		"id_token_signing_alg_values_supported": []string{"RS256"},
+	})
+}
+
+func (p *mockOIDCProvider) jwks(w http.ResponseWriter, r *http.Request) {
+	json.NewEncoder(w).Encode(jose.JSONWebKeySet{Keys: []jose.JSONWebKey{{
+		Key:       &p.key.PublicKey,
+		KeyID:     "1",
+		Algorithm: "RS256",
+		Use:       "sig",
+	}}})
+}
+
+func (p *mockOIDCProvider) token(w http.ResponseWriter, r *http.Request) {
+	r.ParseForm()
+
+	hash := sha256.Sum256([]byte(r.Form.Get("code_verifier")))
+	if r.Form.Get("code") != oidcCode ||
+		base64.RawURLEncoding.EncodeToString(hash[:]) != p.challenge {
+		w.Header().Set("Content-Type", "application/json")
+		w.WriteHeader(400)
+		w.Write([]byte(`{"error":"invalid_grant"}`))
+		return
+	}
+
+	claims := map[string]any{
+		"iss": p.URL,
+		"aud": oidcClientID,
+		"sub": "someSubject",
+		"iat": time.Now().Unix(),
+		"exp": time.Now().Add(time.Hour).Unix(),
+	}
+	for k, v := range p.claims {
+		claims[k] = v
+	}
+
+	signer, err := jose.NewSigner(
+		jose.SigningKey{Algorithm: jose.RS256, Key: p.key},
+		(&jose.SignerOptions{}).WithType("JWT").WithHeader("kid", "1"),
+	)
+	assert.Nil(p.t, err)
+
+	payload, _ := json.Marshal(claims)
+	jws, err := signer.Sign(payload)
+	assert.Nil(p.t, err)
+
+	idToken, err := jws.CompactSerialize()
+	assert.Nil(p.t, err)
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(map[string]any{
+		"access_token": "someAccessToken",
+		"token_type":   "Bearer",
+		"expires_in":   3600,
+		"id_token":     idToken,
+	})
+}
+
+func newOIDCConfig(p *mockOIDCProvider) *config.OIDCConfig {
+	cfg := &config.OIDCConfig{
+		Enabled:      true,
+		Issuer:       p.URL,
+		ClientID:     oidcClientID,
+		ClientSecret: "someSecret",
+		RedirectURL:  oidcRedirectURL,
+	}
+	cfg.SetDefaults()
+
+	return cfg
+}
+
+// startOIDCLogin starts a login and records the PKCE challenge at the provider.
+func startOIDCLogin(
+	t *testing.T,
+	p *mockOIDCProvider,
+	authenticator core.OIDCAuthenticator,
+) *core.OIDCAuthRequest {
+	req, err := authenticator.AuthCodeURL(ctx)
+	assert.Nil(t, err)
+
+	u, err := url.Parse(req.URL)
+	assert.Nil(t, err)
+
+	p.challenge = u.Query().Get("code_challenge")
+	if nonce, ok := p.claims["nonce"]; !ok || nonce == "" {
+		p.claims["nonce"] = req.Nonce
+	}
+
+	return req
+}
+
+func TestOIDCAuthenticatorAuthCodeURL(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	p := newMockOIDCProvider(t, map[string]any{})
+	authenticator := auth.NewOIDCAuthenticator(newOIDCConfig(p), mock_core.NewMockUserRepository(ctrl))
+
+	req, err := authenticator.AuthCodeURL(ctx)
+	assert.Nil(t, err)
+
+	u, err := url.Parse(req.URL)
+	assert.Nil(t, err)
+
+	q := u.Query()
+	assert.Equal(t, p.URL+"/authorize", u.Scheme+"://"+u.Host+u.Path)
+	assert.Equal(t, "code", q.Get("response_type"))
+	assert.Equal(t, oidcClientID, q.Get("client_id"))
+	assert.Equal(t, oidcRedirectURL, q.Get("redirect_uri"))
+	assert.Equal(t, "openid profile email", q.Get("scope"))
+	assert.Equal(t, req.State, q.Get("state"))
+	assert.Equal(t, req.Nonce, q.Get("nonce"))
+	assert.Equal(t, "S256", q.Get("code_challenge_method"))
+
+	hash := sha256.Sum256([]byte(req.Verifier))
+	assert.Equal(t, base64.RawURLEncoding.EncodeToString(hash[:]), q.Get("code_challenge"))
+}
+
+func TestOIDCAuthenticatorExchangeReturnsExistingUser(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	p := newMockOIDCProvider(t, map[string]any{
+		"preferred_username": username,
+		"groups":             []string{"admins"},
+	})
+
+	mockRepository := mock_core.NewMockUserRepository(ctrl)
+	mockRepository.EXPECT().
+		FindByUsername(gomock.Any(), username).
+		Return(&expectedUser, nil).
+		Times(1)
+
+	authenticator := auth.NewOIDCAuthenticator(newOIDCConfig(p), mockRepository)
+	req := startOIDCLogin(t, p, authenticator)
+
+	user, err := authenticator.Exchange(ctx, oidcCode, *req)
+
+	assert.Nil(t, err)
+	assert.Equal(t, expectedUser, *user)
+}
+
+func TestOIDCAuthenticatorExchangeProvisionsUser(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	p := newMockOIDCProvider(t, map[string]any{
+		"preferred_username": username,
+		"name":               "Some Name",
+		"email":              "some@example.com",
+		"groups":             []string{"users", "admins"},
+	})
+
+	mockRepository := mock_core.NewMockUserRepository(ctrl)
+	mockRepository.EXPECT().
+		FindByUsername(gomock.Any(), username).
+		Return(nil, nil).
+		Times(1)
+
+	mockRepository.EXPECT().
+		Create(gomock.Any(), &core.User{
+			Username:    username,
+			DisplayName: "Some Name",
+			Email:       "some@example.com",
+			Role:        core.RoleAdmin,
+		}).
+		Return(nil).
+		Times(1)
+
+	cfg := newOIDCConfig(p)
+	cfg.AllowRegistration = true
+	cfg.AdminGroups = []string{"admins"}
+
+	authenticator := auth.NewOIDCAuthenticator(cfg, mockRepository)
+	req := startOIDCLogin(t, p, authenticator)
+
+	user, err := authenticator.Exchange(ctx, oidcCode, *req)
+
+	assert.Nil(t, err)
+	assert.Equal(t, username, user.Username)
+	assert.Equal(t, core.RoleAdmin, user.Role)
+}
+
+func TestOIDCAuthenticatorExchangeFailsWhenRegistrationIsDisabled(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	p := newMockOIDCProvider(t, map[string]any{
+		"preferred_username": username,
+	})
+
+	mockRepository := mock_core.NewMockUserRepository(ctrl)
+	mockRepository.EXPECT().
+		FindByUsername(gomock.Any(), username).
+		Return(nil, nil).
+		Times(1)
+
+	authenticator := auth.NewOIDCAuthenticator(newOIDCConfig(p), mockRepository)
+	req := startOIDCLogin(t, p, authenticator)
+
+	user, err := authenticator.Exchange(ctx, oidcCode, *req)
+
+	assert.Equal(t, core.ErrOIDCRegistrationDisabled, err)
+	assert.Nil(t, user)
+}
+
+func TestOIDCAuthenticatorExchangeSyncsAdminFromGroups(t *testing.T) {
+	tests := []struct {
+		name    string
+		role    core.Role
+		updated core.Role
+		groups  []string
+	}{
+		{"grants admin", core.RoleViewer, core.RoleAdmin, []string{"admins"}},
+		{"revokes admin", core.RoleAdmin, core.DefaultRole, []string{"users"}},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+
+			p := newMockOIDCProvider(t, map[string]any{
+				"preferred_username": username,
+				"groups":             tc.groups,
+			})
+
+			existing := expectedUser
+			existing.Role = tc.role
+
+			updated := existing
+			updated.Role = tc.updated
+
+			mockRepository := mock_core.NewMockUserRepository(ctrl)
+			mockRepository.EXPECT().
+				FindByUsername(gomock.Any(), username).
+				Return(&existing, nil).
+				Times(1)
+
+			mockRepository.EXPECT().
+				Update(gomock.Any(), &updated).
+				Return(nil).
+				Times(1)
+
+			cfg := newOIDCConfig(p)
+			cfg.AdminGroups = []string{"admins"}
+
+			authenticator := auth.NewOIDCAuthenticator(cfg, mockRepository)
+			req := startOIDCLogin(t, p, authenticator)
+
+			user, err := authenticator.Exchange(ctx, oidcCode, *req)
+
+			assert.Nil(t, err)
+			assert.Equal(t, tc.updated, user.Role)
+		})
+	}
+}
+
+func TestOIDCAuthenticatorExchangeFailsForInvalidNonce(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	p := newMockOIDCProvider(t, map[string]any{
+		"preferred_username": username,
+		"nonce":              "otherNonce",
+	})
+
+	authenticator := auth.NewOIDCAuthenticator(newOIDCConfig(p), mock_core.NewMockUserRepository(ctrl))
+	req := startOIDCLogin(t, p, authenticator)
+
+	user, err := authenticator.Exchange(ctx, oidcCode, *req)
+
+	assert.Equal(t, core.ErrOIDCStateInvalid, err)
+	assert.Nil(t, user)
+}
+
+func TestOIDCAuthenticatorExchangeFailsForInvalidVerifier(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	p := newMockOIDCProvider(t, map[string]any{
+		"preferred_username": username,
+	})
+
+	authenticator := auth.NewOIDCAuthenticator(newOIDCConfig(p), mock_core.NewMockUserRepository(ctrl))
+	req := startOIDCLogin(t, p, authenticator)
+	req.Verifier = "otherVerifier"
+
+	user, err := authenticator.Exchange(ctx, oidcCode, *req)
+
+	assert.ErrorContains(t, err, "invalid_grant")
+	assert.Nil(t, user)
+}
+
+func TestOIDCAuthenticatorExchangeFailsWithoutUsernameClaim(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	p := newMockOIDCProvider(t, map[string]any{})
+
+	authenticator := auth.NewOIDCAuthenticator(newOIDCConfig(p), mock_core.NewMockUserRepository(ctrl))
+	req := startOIDCLogin(t, p, authenticator)
+
+	user, err := authenticator.Exchange(ctx, oidcCode, *req)
+
+	assert.Equal(t, core.ErrOIDCUsernameMissing, err)
+	assert.Nil(t, user)
+}
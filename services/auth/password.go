@@ -0,0 +1,180 @@
+package auth
+
+import (
+	"crypto/rand"
+	"crypto/subtle"
+	"encoding/base64"
+	"errors"
+	"fmt"
+	"strings"
+
+	"github.com/davidborzek/tvhgo/config"
+	"github.com/davidborzek/tvhgo/core"
+	"golang.org/x/crypto/argon2"
+	"golang.org/x/crypto/bcrypt"
+)
+
+const (
+	argon2idPrefix    = "$argon2id$"
+	argon2idSaltLen   = 16
+	argon2idKeyLen    = 32
+	argon2idHashParts = 6
+)
+
+// argon2idHash is a parsed argon2id hash in the PHC string format:
+// $argon2id$v=19$m=65536,t=3,p=2$<salt>$<key>
+type argon2idHash struct {
+	memory      uint32
+	iterations  uint32
+	parallelism uint8
+	salt        []byte
+	key         []byte
+}
+
+type passwordHasher struct {
+	cfg *config.PasswordConfig
+}
+
+// NewPasswordHasher creates a new PasswordHasher, which hashes passwords with
+// the configured algorithm and verifies argon2id and bcrypt hashes.
+func NewPasswordHasher(cfg *config.PasswordConfig) core.PasswordHasher {
+	return &passwordHasher{
+		cfg: cfg,
+	}
+}
+
+func (h *passwordHasher) Hash(password string) (string, error) {
+	if h.cfg.Algorithm == config.PasswordAlgorithmBcrypt {
+		hash, err := bcrypt.GenerateFromPassword([]byte(password), h.cfg.BcryptCost)
+		if err != nil {
+			return "", err
+		}
+
+		return string(hash), nil
+	}
+
+	salt := make([]byte, argon2idSaltLen)
+	if _, err := rand.Read(salt); err != nil {
+		return "", err
+	}
+
+	key := argon2.IDKey(
+		[]byte(password),
+		salt,
+		h.cfg.Argon2id.Iterations,
+		h.cfg.Argon2id.Memory,
+		h.cfg.Argon2id.Parallelism,
+		argon2idKeyLen,
+	)
+
+	return fmt.Sprintf(
+		"%sv=%d$m=%d,t=%d,p=%d$%s$%s",
+		argon2idPrefix,
+		argon2.Version,
+		h.cfg.Argon2id.Memory,
+		h.cfg.Argon2id.Iterations,
+		h.cfg.Argon2id.Parallelism,
+		base64.RawStdEncoding.EncodeToString(salt),
+		base64.RawStdEncoding.EncodeToString(key),
+	), nil
+}
+
+func (h *passwordHasher) Compare(password string, hash string) error {
+	if !strings.HasPrefix(hash, argon2idPrefix) {
+		err := bcrypt.CompareHashAndPassword([]byte(hash), []byte(password))
+		if errors.Is(err, bcrypt.ErrMismatchedHashAndPassword) {
+			return core.ErrPasswordMismatch
+		}
+		if err != nil {
+			return core.ErrPasswordHashInvalid
+		}
+
+		return nil
+	}
+
+	parsed, err := parseArgon2idHash(hash)
+	if err != nil {
+		return err
+	}
+
+	key := argon2.IDKey(
+		[]byte(password),
+		parsed.salt,
+		parsed.iterations,
+		parsed.memory,
+		parsed.parallelism,
+		uint32(len(parsed.key)),
+	)
+
+	if subtle.ConstantTimeCompare(key, parsed.key) != 1 {
+		return core.ErrPasswordMismatch
+	}
+
+	return nil
+}
+
+func (h *passwordHasher) NeedsRehash(hash string) bool {
+	if h.cfg.Algorithm == config.PasswordAlgorithmBcrypt {
+		if strings.HasPrefix(hash, argon2idPrefix) {
+			return true
+		}
+
+		cost, err := bcrypt.Cost([]byte(hash))
+		return err == nil && cost < h.cfg.BcryptCost
+	}
+
+	if !strings.HasPrefix(hash, argon2idPrefix) {
+		return true
+	}
+
+	parsed, err := parseArgon2idHash(hash)
+	if err != nil {
+		return false
+	}
+
+	return parsed.memory < h.cfg.Argon2id.Memory ||
+		parsed.iterations < h.cfg.Argon2id.Iterations ||
+		parsed.parallelism < h.cfg.Argon2id.Parallelism
+}
+
+func parseArgon2idHash(hash string) (*argon2idHash, error) {
+	parts := strings.Split(hash, "$")
+	if len(parts) != argon2idHashParts {
+		return nil, core.ErrPasswordHashInvalid
+	}
+
+	var version int
+	if _, err := fmt.Sscanf(parts[2], "v=%d", &version); err != nil || version != argon2.Version {
+		return nil, core.ErrPasswordHashInvalid
+	}
+
+	parsed := &argon2idHash{}
+	if _, err := fmt.Sscanf(
+		parts[3],
+		"m=%d,t=%d,p=%d",
+		&parsed.memory,
+		&parsed.iterations,
+		&parsed.parallelism,
+	); err != nil {
+		return nil, core.ErrPasswordHashInvalid
+	}
+
+	// argon2 panics for 0 iterations or threads and allocates the
+	// memory of the hash, so tampered hashes must not exceed the bounds.
+	if parsed.memory < 1 || parsed.memory > config.PasswordArgon2idMaxMemory ||
+		parsed.iterations < 1 || parsed.iterations > config.PasswordArgon2idMaxIterations ||
+		parsed.parallelism < 1 || parsed.parallelism > config.PasswordArgon2idMaxParallelism {
+		return nil, core.ErrPasswordHashInvalid
+	}
+
+	var err error
+	if parsed.salt, err = base64.RawStdEncoding.DecodeString(parts[4]); err != nil {
+		return nil, core.ErrPasswordHashInvalid
+	}
+
+	if parsed.key, err = base64.RawStdEncoding.DecodeString(parts[5]); err != nil || len(parsed.key) == 0 {
+		return nil, core.ErrPasswordHashInvalid
+	}
+
+	return parsed, nil
+}
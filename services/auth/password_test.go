@@ -0,0 +1,113 @@
+package auth_test
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/davidborzek/tvhgo/config"
+	"github.com/davidborzek/tvhgo/core"
+	"github.com/davidborzek/tvhgo/services/auth"
+	"github.com/stretchr/testify/assert"
+)
+
+func newArgon2idHasher(memory uint32, iterations uint32, parallelism uint8) core.PasswordHasher {
+	return auth.NewPasswordHasher(&config.PasswordConfig{
+		Algorithm: config.PasswordAlgorithmArgon2id,
+		Argon2id: config.Argon2idConfig{
+			Memory:      memory,
+			Iterations:  iterations,
+			Parallelism: parallelism,
+		},
+	})
+}
+
+func TestPasswordHasherHashesWithArgon2id(t *testing.T) {
+	hasher := newArgon2idHasher(1024, 1, 1)
+
+	hash, err := hasher.Hash(password)
+
+	assert.Nil(t, err)
+	assert.True(t, strings.HasPrefix(hash, "$argon2id$v=19$m=1024,t=1,p=1$"))
+	assert.Nil(t, hasher.Compare(password, hash))
+	assert.Equal(t, core.ErrPasswordMismatch, hasher.Compare("otherPassword", hash))
+	assert.False(t, hasher.NeedsRehash(hash))
+}
+
+func TestPasswordHasherUsesRandomSalt(t *testing.T) {
+	hasher := newArgon2idHasher(1024, 1, 1)
+
+	first, _ := hasher.Hash(password)
+	second, _ := hasher.Hash(password)
+
+	assert.NotEqual(t, first, second)
+}
+
+func TestPasswordHasherComparesBcryptHashes(t *testing.T) {
+	hasher := newArgon2idHasher(1024, 1, 1)
+
+	assert.Nil(t, hasher.Compare(password, passwordHash))
+	assert.Equal(t, core.ErrPasswordMismatch, hasher.Compare("otherPassword", passwordHash))
+	assert.True(t, hasher.NeedsRehash(passwordHash))
+}
+
+func TestPasswordHasherNeedsRehashForWeakerParameters(t *testing.T) {
+	hash, _ := newArgon2idHasher(1024, 1, 1).Hash(password)
+
+	assert.True(t, newArgon2idHasher(2048, 1, 1).NeedsRehash(hash))
+	assert.True(t, newArgon2idHasher(1024, 2, 1).NeedsRehash(hash))
+	assert.True(t, newArgon2idHasher(1024, 1, 2).NeedsRehash(hash))
+	assert.False(t, newArgon2idHasher(512, 1, 1).NeedsRehash(hash))
+}
+
+func TestPasswordHasherHashesWithBcrypt(t *testing.T) {
+	hasher := auth.NewPasswordHasher(&config.PasswordConfig{
+		Algorithm:  config.PasswordAlgorithmBcrypt,
+		BcryptCost: 10,
+	})
+
+	hash, err := hasher.Hash(password)
+
+	assert.Nil(t, err)
+	assert.True(t, strings.HasPrefix(hash, "$2a$10$"))
+	assert.Nil(t, hasher.Compare(password, hash))
+	assert.False(t, hasher.NeedsRehash(hash))
+	assert.False(t, hasher.NeedsRehash(passwordHash))
+
+	argon2idHash, _ := newArgon2idHasher(1024, 1, 1).Hash(password)
+	assert.True(t, hasher.NeedsRehash(argon2idHash))
+}
+
+func TestPasswordHasherReturnsErrPasswordHashInvalid(t *testing.T) {
+	hasher := newArgon2idHasher(1024, 1, 1)
+
+	assert.Equal(t, core.ErrPasswordHashInvalid, hasher.Compare(password, "$argon2id$v=19$invalid"))
+	assert.Equal(t, core.ErrPasswordHashInvalid, hasher.Compare(password, "invalid"))
+}
+
+func TestPasswordHasherRejectsArgon2idParametersOutOfBounds(t *testing.T) {
+	const saltAndKey = "$c29tZVNhbHRTb21lU2FsdA$c29tZUtleVNvbWVLZXlTb21lS2V5U29tZUtleQ"
+
+	tests := []struct {
+		name   string
+		params string
+	}{
+		{name: "zero memory", params: "m=0,t=1,p=1"},
+		{name: "zero iterations", params: "m=1024,t=0,p=1"},
+		{name: "zero parallelism", params: "m=1024,t=1,p=0"},
+		{name: "memory too high", params: "m=4294967295,t=1,p=1"},
+		{name: "iterations too high", params: "m=1024,t=4294967295,p=1"},
+		{name: "parallelism too high", params: "m=1024,t=1,p=255"},
+		{name: "parallelism overflow", params: "m=1024,t=1,p=256"},
+	}
+
+	hasher := newArgon2idHasher(1024, 1, 1)
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			hash := "$argon2id$v=19$" + tt.params + saltAndKey
+
+			assert.Equal(t, core.ErrPasswordHashInvalid, hasher.Compare(password, hash))
+			assert.False(t, hasher.NeedsRehash(hash))
+		})
+	}
+}
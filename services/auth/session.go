@@ -39,6 +39,29 @@ func (s *sessionManager) Create(
 	clientIp string,
 	userAgent string,
 ) (string, error) {
+	return s.create(ctx, &core.Session{
+		UserId:    userId,
+		ClientIP:  clientIp,
+		UserAgent: userAgent,
+	})
+}
+
+func (s *sessionManager) CreateDeviceSession(
+	ctx context.Context,
+	userId int64,
+	clientIp string,
+	userAgent string,
+	deviceName string,
+) (string, error) {
+	return s.create(ctx, &core.Session{
+		UserId:     userId,
+		ClientIP:   clientIp,
+		UserAgent:  userAgent,
+		DeviceName: deviceName,
+	})
+}
+
+func (s *sessionManager) create(ctx context.Context, session *core.Session) (string, error) {
 	token, err := generateToken()
 	if err != nil {
 		log.Error().Err(err).Msg("could not generate session token")
@@ -46,16 +69,10 @@ func (s *sessionManager) Create(
 		return "", core.ErrUnexpectedError
 	}
 
-	hashedToken := hashToken(token)
-	session := &core.Session{
-		UserId:      userId,
-		HashedToken: hashedToken,
-		ClientIP:    clientIp,
-		UserAgent:   userAgent,
-	}
+	session.HashedToken = hashToken(token)
 
 	if err := s.sessionRepository.Create(ctx, session); err != nil {
-		log.Error().Err(err).Int64("user", userId).
+		log.Error().Err(err).Int64("user", session.UserId).
 			Msg("could not persist session")
 
 		return "", core.ErrUnexpectedError
@@ -75,6 +92,21 @@ func (s *sessionManager) Revoke(ctx context.Context, sessionID int64, userID int
 	return nil
 }
 
+func (s *sessionManager) RevokeAll(
+	ctx context.Context,
+	userID int64,
+	exceptSessionID *int64,
+) (int64, error) {
+	revoked, err := s.sessionRepository.DeleteByUser(ctx, userID, exceptSessionID)
+	if err != nil {
+		log.Error().Err(err).Int64("user", userID).
+			Msg("could not delete sessions")
+
+		return 0, core.ErrUnexpectedError
+	}
+	return revoked, nil
+}
+
 func (s *sessionManager) Validate(
 	ctx context.Context,
 	token string,
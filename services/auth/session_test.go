@@ -88,6 +88,28 @@ func TestSessionManagerCreateReturnsErrUnexpectedErrWhenPersistingSessionFails(t
 	assert.Empty(t, token)
 }
 
+func TestSessionManagerCreateDeviceSessionReturnsToken(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockRepository := mock_core.NewMockSessionRepository(ctrl)
+	mockRepository.EXPECT().
+		Create(ctx, newEqSessionMatcher(&core.Session{
+			UserId:     userID,
+			ClientIP:   clientIp,
+			UserAgent:  userAgent,
+			DeviceName: "Living Room TV",
+		})).
+		Return(nil).
+		Times(1)
+
+	sessionManager := auth.NewSessionManager(mockRepository, mock_core.NewMockClock(ctrl), 0, 0, 0)
+	token, err := sessionManager.CreateDeviceSession(ctx, userID, clientIp, userAgent, "Living Room TV")
+
+	assert.Nil(t, err)
+	assert.NotEmpty(t, token)
+}
+
 func TestSessionManagerRevokeSucceeds(t *testing.T) {
 	ctrl := gomock.NewController(t)
 	defer ctrl.Finish()
@@ -120,6 +142,41 @@ func TestSessionManagerRevokeReturnsErrUnexpectedError(t *testing.T) {
 	assert.Equal(t, core.ErrUnexpectedError, err)
 }
 
+func TestSessionManagerRevokeAllSucceeds(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	currentSessionID := sessionID
+
+	mockRepository := mock_core.NewMockSessionRepository(ctrl)
+	mockRepository.EXPECT().
+		DeleteByUser(ctx, userID, &currentSessionID).
+		Return(int64(2), nil).
+		Times(1)
+
+	sessionManager := auth.NewSessionManager(mockRepository, mock_core.NewMockClock(ctrl), 0, 0, 0)
+	revoked, err := sessionManager.RevokeAll(ctx, userID, &currentSessionID)
+
+	assert.Nil(t, err)
+	assert.Equal(t, int64(2), revoked)
+}
+
+func TestSessionManagerRevokeAllReturnsErrUnexpectedError(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockRepository := mock_core.NewMockSessionRepository(ctrl)
+	mockRepository.EXPECT().
+		DeleteByUser(ctx, userID, nil).
+		Return(int64(0), errors.New("some unexpected error")).
+		Times(1)
+
+	sessionManager := auth.NewSessionManager(mockRepository, mock_core.NewMockClock(ctrl), 0, 0, 0)
+	_, err := sessionManager.RevokeAll(ctx, userID, nil)
+
+	assert.Equal(t, core.ErrUnexpectedError, err)
+}
+
 func TestSessionManagerValidateReturnsErrUnexpectedErrorWhenFindingSessionFails(t *testing.T) {
 	ctrl := gomock.NewController(t)
 	defer ctrl.Finish()
@@ -13,15 +13,25 @@ import (
 
 type tokenService struct {
 	tokenRepository core.TokenRepository
+	clock           core.Clock
 }
 
-func NewTokenService(tokenRepository core.TokenRepository) core.TokenService {
+func NewTokenService(tokenRepository core.TokenRepository, clock core.Clock) core.TokenService {
 	return &tokenService{
 		tokenRepository: tokenRepository,
+		clock:           clock,
 	}
 }
 
-func (s *tokenService) Create(ctx context.Context, userID int64, name string) (string, error) {
+func (s *tokenService) Create(
+	ctx context.Context,
+	userID int64,
+	opts core.CreateToken,
+) (string, error) {
+	if err := opts.Validate(s.clock.Now().Unix()); err != nil {
+		return "", err
+	}
+
 	token, err := generateToken()
 	if err != nil {
 		log.Error().Err(err).Int64("user", userID).
@@ -30,11 +40,18 @@ func (s *tokenService) Create(ctx context.Context, userID int64, name string) (s
 		return "", core.ErrUnexpectedError
 	}
 
+	// An empty set of scopes is stored like no scopes and grants all permissions.
+	if len(opts.Scopes) == 0 {
+		opts.Scopes = nil
+	}
+
 	hashedToken := hashToken(token)
 	tokenEntity := &core.Token{
-		Name:        name,
+		Name:        opts.Name,
 		UserID:      userID,
 		HashedToken: hashedToken,
+		ExpiresAt:   opts.ExpiresAt,
+		Scopes:      opts.Scopes,
 	}
 
 	if err := s.tokenRepository.Create(ctx, tokenEntity); err != nil {
@@ -47,8 +64,8 @@ func (s *tokenService) Create(ctx context.Context, userID int64, name string) (s
 	return token, nil
 }
 
-func (s *tokenService) Revoke(ctx context.Context, id int64) error {
-	err := s.tokenRepository.Delete(ctx, &core.Token{ID: id})
+func (s *tokenService) Revoke(ctx context.Context, id int64, userID int64) error {
+	err := s.tokenRepository.Delete(ctx, &core.Token{ID: id, UserID: userID})
 	if err != nil {
 		log.Error().Err(err).Int64("token", id).
 			Msg("could not revoke token")
@@ -58,9 +75,21 @@ func (s *tokenService) Revoke(ctx context.Context, id int64) error {
 	return nil
 }
 
+func (s *tokenService) RevokeAll(ctx context.Context, userID int64) (int64, error) {
+	revoked, err := s.tokenRepository.DeleteByUser(ctx, userID)
+	if err != nil {
+		log.Error().Err(err).Int64("user", userID).
+			Msg("could not revoke tokens")
+
+		return 0, core.ErrUnexpectedError
+	}
+	return revoked, nil
+}
+
 func (s *tokenService) Validate(
 	ctx context.Context,
 	token string,
+	clientIP string,
 ) (*core.AuthContext, error) {
 	hashedToken := hashToken(token)
 	tokenEntity, err := s.tokenRepository.FindByToken(ctx, hashedToken)
@@ -77,12 +106,30 @@ func (s *tokenService) Validate(
 		}
 	}
 
+	now := s.clock.Now().Unix()
+
+	if tokenEntity.ExpiresAt != nil && *tokenEntity.ExpiresAt <= now {
+		return nil, core.InvalidOrExpiredTokenError{
+			Reason: core.ErrExpiredTokenLifetime,
+		}
+	}
+
+	tokenEntity.LastUsedAt = &now
+	tokenEntity.LastUsedIP = &clientIP
+
+	if err := s.tokenRepository.UpdateLastUsed(ctx, tokenEntity); err != nil {
+		log.Error().Err(err).Int64("token", tokenEntity.ID).
+			Msg("could not update token")
+
+		return nil, core.ErrUnexpectedError
+	}
+
 	authCtx := core.AuthContext{
 		UserID: tokenEntity.UserID,
+		Scopes: tokenEntity.Scopes,
 	}
 
 	return &authCtx, nil
-
 }
 
 // Generates a random 256-bit token.
@@ -5,6 +5,7 @@ import (
 	"fmt"
 	"reflect"
 	"testing"
+	"time"
 
 	"github.com/davidborzek/tvhgo/core"
 	mock_core "github.com/davidborzek/tvhgo/mock/core"
@@ -18,15 +19,28 @@ const (
 	tokenName = "My Token"
 	tokenVal  = "b29f4163d2427ee4c91ed5c993576948666b1832960fbdb57d86965238619716"
 	tokenHash = "256475849de6aecfb0434623bde0b6f16a89722ffd86994b6094101ac044762b"
+	tokenIP   = "192.168.1.1"
 )
 
 var (
+	tokenNow = time.Unix(1672527600, 0)
+
 	expectedCreatedToken = core.Token{
 		UserID: userID,
 		Name:   tokenName,
 	}
 )
 
+func newTokenClock(ctrl *gomock.Controller) core.Clock {
+	mockClock := mock_core.NewMockClock(ctrl)
+	mockClock.EXPECT().
+		Now().
+		Return(tokenNow).
+		AnyTimes()
+
+	return mockClock
+}
+
 func TestTokenServiceCreateReturnsToken(t *testing.T) {
 	ctrl := gomock.NewController(t)
 	defer ctrl.Finish()
@@ -37,14 +51,70 @@ func TestTokenServiceCreateReturnsToken(t *testing.T) {
 		Return(nil).
 		Times(1)
 
-	tokenService := auth.NewTokenService(mockRepository)
+	tokenService := auth.NewTokenService(mockRepository, newTokenClock(ctrl))
+
+	token, err := tokenService.Create(ctx, userID, core.CreateToken{Name: tokenName})
+
+	assert.Nil(t, err)
+	assert.NotEmpty(t, token)
+}
+
+func TestTokenServiceCreateReturnsScopedAndExpiringToken(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	expiresAt := tokenNow.Add(time.Hour).Unix()
+	scopes := []core.Permission{core.PermissionEPGRead, core.PermissionStream}
+
+	mockRepository := mock_core.NewMockTokenRepository(ctrl)
+	mockRepository.EXPECT().
+		Create(ctx, newEqTokenMatcher(&core.Token{
+			UserID:    userID,
+			Name:      tokenName,
+			ExpiresAt: &expiresAt,
+			Scopes:    scopes,
+		})).
+		Return(nil).
+		Times(1)
+
+	tokenService := auth.NewTokenService(mockRepository, newTokenClock(ctrl))
 
-	token, err := tokenService.Create(ctx, userID, tokenName)
+	token, err := tokenService.Create(ctx, userID, core.CreateToken{
+		Name:      tokenName,
+		ExpiresAt: &expiresAt,
+		Scopes:    scopes,
+	})
 
 	assert.Nil(t, err)
 	assert.NotEmpty(t, token)
 }
 
+func TestTokenServiceCreateReturnsErrorForInvalidOptions(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	expiresAt := tokenNow.Unix()
+
+	tokenService := auth.NewTokenService(
+		mock_core.NewMockTokenRepository(ctrl),
+		newTokenClock(ctrl),
+	)
+
+	token, err := tokenService.Create(ctx, userID, core.CreateToken{
+		Name:   tokenName,
+		Scopes: []core.Permission{"unknown"},
+	})
+	assert.Equal(t, core.ErrTokenScopeInvalid, err)
+	assert.Empty(t, token)
+
+	token, err = tokenService.Create(ctx, userID, core.CreateToken{
+		Name:      tokenName,
+		ExpiresAt: &expiresAt,
+	})
+	assert.Equal(t, core.ErrTokenExpiryInvalid, err)
+	assert.Empty(t, token)
+}
+
 func TestTokenServiceCreateErrUnexpectedErrorWhenPersistingFails(t *testing.T) {
 	ctrl := gomock.NewController(t)
 	defer ctrl.Finish()
@@ -55,9 +125,9 @@ func TestTokenServiceCreateErrUnexpectedErrorWhenPersistingFails(t *testing.T) {
 		Return(errors.New("some unexpected error")).
 		Times(1)
 
-	tokenService := auth.NewTokenService(mockRepository)
+	tokenService := auth.NewTokenService(mockRepository, newTokenClock(ctrl))
 
-	token, err := tokenService.Create(ctx, userID, tokenName)
+	token, err := tokenService.Create(ctx, userID, core.CreateToken{Name: tokenName})
 
 	assert.Equal(t, core.ErrUnexpectedError, err)
 	assert.Empty(t, token)
@@ -69,13 +139,13 @@ func TestTokenServiceRevokeSucceeds(t *testing.T) {
 
 	mockRepository := mock_core.NewMockTokenRepository(ctrl)
 	mockRepository.EXPECT().
-		Delete(ctx, &core.Token{ID: tokenID}).
+		Delete(ctx, &core.Token{ID: tokenID, UserID: userID}).
 		Return(nil).
 		Times(1)
 
-	tokenService := auth.NewTokenService(mockRepository)
+	tokenService := auth.NewTokenService(mockRepository, newTokenClock(ctrl))
 
-	err := tokenService.Revoke(ctx, tokenID)
+	err := tokenService.Revoke(ctx, tokenID, userID)
 
 	assert.Nil(t, err)
 }
@@ -86,16 +156,33 @@ func TestTokenServiceRevokeFails(t *testing.T) {
 
 	mockRepository := mock_core.NewMockTokenRepository(ctrl)
 	mockRepository.EXPECT().
-		Delete(ctx, &core.Token{ID: tokenID}).
+		Delete(ctx, &core.Token{ID: tokenID, UserID: userID}).
 		Return(errors.New("some unexpected error")).
 		Times(1)
 
-	tokenService := auth.NewTokenService(mockRepository)
+	tokenService := auth.NewTokenService(mockRepository, newTokenClock(ctrl))
 
-	err := tokenService.Revoke(ctx, tokenID)
+	err := tokenService.Revoke(ctx, tokenID, userID)
 	assert.Equal(t, core.ErrUnexpectedError, err)
 }
 
+func TestTokenServiceRevokeAllSucceeds(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockRepository := mock_core.NewMockTokenRepository(ctrl)
+	mockRepository.EXPECT().
+		DeleteByUser(ctx, userID).
+		Return(int64(3), nil).
+		Times(1)
+
+	tokenService := auth.NewTokenService(mockRepository, newTokenClock(ctrl))
+
+	revoked, err := tokenService.RevokeAll(ctx, userID)
+	assert.Nil(t, err)
+	assert.Equal(t, int64(3), revoked)
+}
+
 func TestTokenServiceValidateReturnsAuthContext(t *testing.T) {
 	ctrl := gomock.NewController(t)
 	defer ctrl.Finish()
@@ -113,13 +200,87 @@ func TestTokenServiceValidateReturnsAuthContext(t *testing.T) {
 		Return(tokenEntity, nil).
 		Times(1)
 
-	tokenService := auth.NewTokenService(mockRepository)
+	lastUsedAt := tokenNow.Unix()
+	lastUsedIP := tokenIP
+	mockRepository.EXPECT().
+		UpdateLastUsed(ctx, &core.Token{
+			ID:          tokenID,
+			UserID:      userID,
+			Name:        tokenName,
+			HashedToken: tokenHash,
+			LastUsedAt:  &lastUsedAt,
+			LastUsedIP:  &lastUsedIP,
+		}).
+		Return(nil).
+		Times(1)
+
+	tokenService := auth.NewTokenService(mockRepository, newTokenClock(ctrl))
 
-	authCtx, err := tokenService.Validate(ctx, tokenVal)
+	authCtx, err := tokenService.Validate(ctx, tokenVal, tokenIP)
 
 	assert.Equal(t, authCtx.UserID, userID)
 	assert.Nil(t, authCtx.SessionID)
+	assert.Nil(t, authCtx.Scopes)
+	assert.Nil(t, err)
+}
+
+func TestTokenServiceValidateReturnsScopes(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	scopes := []core.Permission{core.PermissionEPGRead}
+
+	mockRepository := mock_core.NewMockTokenRepository(ctrl)
+	mockRepository.EXPECT().
+		FindByToken(ctx, tokenHash).
+		Return(&core.Token{
+			ID:          tokenID,
+			UserID:      userID,
+			HashedToken: tokenHash,
+			Scopes:      scopes,
+		}, nil).
+		Times(1)
+
+	mockRepository.EXPECT().
+		UpdateLastUsed(ctx, gomock.Any()).
+		Return(nil).
+		Times(1)
+
+	tokenService := auth.NewTokenService(mockRepository, newTokenClock(ctrl))
+
+	authCtx, err := tokenService.Validate(ctx, tokenVal, tokenIP)
+
 	assert.Nil(t, err)
+	assert.Equal(t, scopes, authCtx.Scopes)
+	assert.True(t, authCtx.HasScope(core.PermissionEPGRead))
+	assert.False(t, authCtx.HasScope(core.PermissionRecordingsWrite))
+}
+
+func TestTokenServiceValidateReturnsErrorForExpiredToken(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	expiresAt := tokenNow.Unix()
+
+	mockRepository := mock_core.NewMockTokenRepository(ctrl)
+	mockRepository.EXPECT().
+		FindByToken(ctx, tokenHash).
+		Return(&core.Token{
+			ID:          tokenID,
+			UserID:      userID,
+			HashedToken: tokenHash,
+			ExpiresAt:   &expiresAt,
+		}, nil).
+		Times(1)
+
+	tokenService := auth.NewTokenService(mockRepository, newTokenClock(ctrl))
+
+	authCtx, err := tokenService.Validate(ctx, tokenVal, tokenIP)
+
+	assert.Nil(t, authCtx)
+	assert.Equal(t, core.InvalidOrExpiredTokenError{
+		Reason: core.ErrExpiredTokenLifetime,
+	}, err)
 }
 
 func TestTokenServiceValidateReturnsErrUnexpectedError(t *testing.T) {
@@ -132,9 +293,9 @@ func TestTokenServiceValidateReturnsErrUnexpectedError(t *testing.T) {
 		Return(nil, errors.New("some unexpected error")).
 		Times(1)
 
-	tokenService := auth.NewTokenService(mockRepository)
+	tokenService := auth.NewTokenService(mockRepository, newTokenClock(ctrl))
 
-	authCtx, err := tokenService.Validate(ctx, tokenVal)
+	authCtx, err := tokenService.Validate(ctx, tokenVal, tokenIP)
 
 	assert.Nil(t, authCtx)
 	assert.Equal(t, err, core.ErrUnexpectedError)
@@ -150,9 +311,9 @@ func TestTokenServiceValidateReturnsInvalidOrExpiredTokenError(t *testing.T) {
 		Return(nil, nil).
 		Times(1)
 
-	tokenService := auth.NewTokenService(mockRepository)
+	tokenService := auth.NewTokenService(mockRepository, newTokenClock(ctrl))
 
-	authCtx, err := tokenService.Validate(ctx, tokenVal)
+	authCtx, err := tokenService.Validate(ctx, tokenVal, tokenIP)
 
 	assert.Nil(t, authCtx)
 	assert.Equal(t, err, core.InvalidOrExpiredTokenError{
@@ -0,0 +1,132 @@
+package auth
+
+import (
+	"context"
+	"time"
+
+	"github.com/davidborzek/tvhgo/config"
+	"github.com/davidborzek/tvhgo/core"
+	"github.com/rs/zerolog/log"
+)
+
+type trustedDeviceService struct {
+	repository core.TrustedDeviceRepository
+	clock      core.Clock
+	cfg        *config.TrustedDeviceConfig
+}
+
+// NewTrustedDeviceService creates a new TrustedDeviceService. Only hashes
+// of the device tokens are stored, so that a token can't be restored
+// from the database.
+func NewTrustedDeviceService(
+	repository core.TrustedDeviceRepository,
+	clock core.Clock,
+	cfg *config.TrustedDeviceConfig,
+) core.TrustedDeviceService {
+	return &trustedDeviceService{
+		repository: repository,
+		clock:      clock,
+		cfg:        cfg,
+	}
+}
+
+func (s *trustedDeviceService) Trust(
+	ctx context.Context,
+	userID int64,
+	days int,
+	clientIP string,
+	userAgent string,
+) (string, *core.TrustedDevice, error) {
+	if !s.cfg.Enabled {
+		return "", nil, core.ErrTrustedDevicesDisabled
+	}
+
+	lifetime := time.Duration(days) * 24 * time.Hour
+	if days <= 0 || lifetime > s.cfg.MaxLifetime {
+		return "", nil, core.ErrTrustedDeviceLifetimeInvalid
+	}
+
+	now := s.clock.Now().Unix()
+	if _, err := s.repository.DeleteExpired(ctx, now); err != nil {
+		log.Error().Err(err).Msg("could not delete expired trusted devices")
+	}
+
+	token, err := generateToken()
+	if err != nil {
+		log.Error().Err(err).Msg("could not generate trusted device token")
+
+		return "", nil, core.ErrUnexpectedError
+	}
+
+	device := &core.TrustedDevice{
+		UserID:      userID,
+		HashedToken: hashToken(token),
+		ClientIP:    clientIP,
+		UserAgent:   userAgent,
+		ExpiresAt:   now + int64(lifetime.Seconds()),
+	}
+
+	if err := s.repository.Create(ctx, device); err != nil {
+		log.Error().Err(err).Int64("user", userID).
+			Msg("could not persist trusted device")
+
+		return "", nil, core.ErrUnexpectedError
+	}
+
+	return token, device, nil
+}
+
+func (s *trustedDeviceService) Verify(ctx context.Context, token string) (*core.TrustedDevice, error) {
+	if !s.cfg.Enabled || token == "" {
+		return nil, nil
+	}
+
+	device, err := s.repository.Find(ctx, hashToken(token))
+	if err != nil {
+		log.Error().Err(err).Msg("could not get trusted device")
+
+		return nil, core.ErrUnexpectedError
+	}
+
+	if device == nil {
+		return nil, nil
+	}
+
+	now := s.clock.Now().Unix()
+	if now >= device.ExpiresAt {
+		if err := s.repository.Delete(ctx, device.ID, device.UserID); err != nil {
+			log.Error().Err(err).Int64("id", device.ID).
+				Msg("could not delete expired trusted device")
+		}
+
+		return nil, nil
+	}
+
+	device.LastUsedAt = now
+	if err := s.repository.Update(ctx, device); err != nil {
+		log.Error().Err(err).Int64("id", device.ID).
+			Msg("could not update trusted device")
+	}
+
+	return device, nil
+}
+
+func (s *trustedDeviceService) List(ctx context.Context, userID int64) ([]*core.TrustedDevice, error) {
+	devices, err := s.repository.FindByUser(ctx, userID)
+	if err != nil {
+		log.Error().Err(err).Int64("user", userID).
+			Msg("could not get trusted devices")
+
+		return nil, core.ErrUnexpectedError
+	}
+
+	return devices, nil
+}
+
+func (s *trustedDeviceService) Revoke(ctx context.Context, id int64, userID int64) error {
+	return s.repository.Delete(ctx, id, userID)
+}
+
+func (s *trustedDeviceService) RevokeAll(ctx context.Context, userID int64) (int64, error) {
+	return s.repository.DeleteByUser(ctx, userID)
+}
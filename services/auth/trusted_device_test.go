@@ -0,0 +1,182 @@
+package auth_test
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/davidborzek/tvhgo/config"
+	"github.com/davidborzek/tvhgo/core"
+	mock_core "github.com/davidborzek/tvhgo/mock/core"
+	"github.com/davidborzek/tvhgo/services/auth"
+	"github.com/stretchr/testify/assert"
+	"go.uber.org/mock/gomock"
+)
+
+var trustedDeviceNow = time.Unix(1672527600, 0)
+
+func newTrustedDeviceService(
+	ctrl *gomock.Controller,
+	enabled bool,
+) (core.TrustedDeviceService, *mock_core.MockTrustedDeviceRepository) {
+	repository := mock_core.NewMockTrustedDeviceRepository(ctrl)
+
+	mockClock := mock_core.NewMockClock(ctrl)
+	mockClock.EXPECT().
+		Now().
+		Return(trustedDeviceNow).
+		AnyTimes()
+
+	cfg := &config.TrustedDeviceConfig{Enabled: enabled}
+	cfg.SetDefaults()
+
+	return auth.NewTrustedDeviceService(repository, mockClock, cfg), repository
+}
+
+func TestTrustedDeviceTrustCreatesDevice(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	s, repository := newTrustedDeviceService(ctrl, true)
+
+	repository.EXPECT().
+		DeleteExpired(gomock.Any(), trustedDeviceNow.Unix()).
+		Return(int64(0), nil)
+
+	var created *core.TrustedDevice
+	repository.EXPECT().
+		Create(gomock.Any(), gomock.Any()).
+		DoAndReturn(func(_ context.Context, device *core.TrustedDevice) error {
+			created = device
+			return nil
+		})
+
+	token, device, err := s.Trust(context.TODO(), userID, 7, "192.168.1.10", "Living Room TV")
+
+	assert.Nil(t, err)
+	assert.Len(t, token, 64)
+	assert.Equal(t, created, device)
+	assert.Equal(t, userID, device.UserID)
+	assert.NotEqual(t, token, device.HashedToken)
+	assert.Equal(t, "192.168.1.10", device.ClientIP)
+	assert.Equal(t, "Living Room TV", device.UserAgent)
+	assert.Equal(t, trustedDeviceNow.Add(7*24*time.Hour).Unix(), device.ExpiresAt)
+}
+
+func TestTrustedDeviceTrustFailsForInvalidLifetime(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	s, _ := newTrustedDeviceService(ctrl, true)
+
+	for _, days := range []int{0, -1, 31} {
+		_, _, err := s.Trust(context.TODO(), userID, days, "", "")
+		assert.Equal(t, core.ErrTrustedDeviceLifetimeInvalid, err)
+	}
+}
+
+func TestTrustedDeviceTrustFailsWhenDisabled(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	s, _ := newTrustedDeviceService(ctrl, false)
+
+	_, _, err := s.Trust(context.TODO(), userID, 7, "", "")
+	assert.Equal(t, core.ErrTrustedDevicesDisabled, err)
+}
+
+func TestTrustedDeviceVerifyReturnsDevice(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	s, repository := newTrustedDeviceService(ctrl, true)
+
+	device := &core.TrustedDevice{
+		ID:        1,
+		UserID:    userID,
+		ExpiresAt: trustedDeviceNow.Unix() + 100,
+	}
+
+	repository.EXPECT().
+		Find(gomock.Any(), gomock.Not("someToken")).
+		Return(device, nil)
+
+	repository.EXPECT().
+		Update(gomock.Any(), device).
+		Return(nil)
+
+	verified, err := s.Verify(context.TODO(), "someToken")
+
+	assert.Nil(t, err)
+	assert.Equal(t, device, verified)
+	assert.Equal(t, trustedDeviceNow.Unix(), verified.LastUsedAt)
+}
+
+func TestTrustedDeviceVerifyDeletesExpiredDevice(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	s, repository := newTrustedDeviceService(ctrl, true)
+
+	repository.EXPECT().
+		Find(gomock.Any(), gomock.Any()).
+		Return(&core.TrustedDevice{
+			ID:        1,
+			UserID:    userID,
+			ExpiresAt: trustedDeviceNow.Unix(),
+		}, nil)
+
+	repository.EXPECT().
+		Delete(gomock.Any(), int64(1), userID).
+		Return(nil)
+
+	verified, err := s.Verify(context.TODO(), "someToken")
+
+	assert.Nil(t, err)
+	assert.Nil(t, verified)
+}
+
+func TestTrustedDeviceVerifyReturnsNilForUnknownToken(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	s, repository := newTrustedDeviceService(ctrl, true)
+
+	repository.EXPECT().
+		Find(gomock.Any(), gomock.Any()).
+		Return(nil, nil)
+
+	verified, err := s.Verify(context.TODO(), "someToken")
+
+	assert.Nil(t, err)
+	assert.Nil(t, verified)
+}
+
+func TestTrustedDeviceVerifyReturnsNilWhenDisabled(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	s, _ := newTrustedDeviceService(ctrl, false)
+
+	verified, err := s.Verify(context.TODO(), "someToken")
+
+	assert.Nil(t, err)
+	assert.Nil(t, verified)
+}
+
+func TestTrustedDeviceVerifyReturnsErrUnexpectedError(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	s, repository := newTrustedDeviceService(ctrl, true)
+
+	repository.EXPECT().
+		Find(gomock.Any(), gomock.Any()).
+		Return(nil, errors.New("unexpected error"))
+
+	verified, err := s.Verify(context.TODO(), "someToken")
+
+	assert.Equal(t, core.ErrUnexpectedError, err)
+	assert.Nil(t, verified)
+}
@@ -2,30 +2,49 @@ package auth
 
 import (
 	"context"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/base32"
+	"encoding/hex"
 	"errors"
+	"strings"
 
 	"github.com/davidborzek/tvhgo/config"
 	"github.com/davidborzek/tvhgo/core"
 	"github.com/pquerna/otp/totp"
 )
 
+const (
+	// recoveryCodeCount is the number of recovery codes generated for a user.
+	recoveryCodeCount = 10
+	// recoveryCodeLength is the number of characters of a recovery code
+	// without separators, which equals 80 bits of entropy.
+	recoveryCodeLength = 16
+)
+
 var (
 	errTwoFactorServiceUserNotFound = errors.New("two factor service: user not found")
+
+	recoveryCodeEncoding = base32.NewEncoding("abcdefghijklmnopqrstuvwxyz234567").
+				WithPadding(base32.NoPadding)
 )
 
 type twoFactorAuthService struct {
 	userRepository              core.UserRepository
 	twoFactorSettingsRepository core.TwoFactorSettingsRepository
+	recoveryCodeRepository      core.TwoFactorRecoveryCodeRepository
 	cfg                         *config.TOTPConfig
 }
 
 func NewTwoFactorAuthService(
 	twoFactorSettingsRepository core.TwoFactorSettingsRepository,
+	recoveryCodeRepository core.TwoFactorRecoveryCodeRepository,
 	userRepository core.UserRepository,
 	cfg *config.TOTPConfig,
 ) core.TwoFactorAuthService {
 	return &twoFactorAuthService{
 		twoFactorSettingsRepository: twoFactorSettingsRepository,
+		recoveryCodeRepository:      recoveryCodeRepository,
 		userRepository:              userRepository,
 		cfg:                         cfg,
 	}
@@ -82,18 +101,39 @@ func (s *twoFactorAuthService) Deactivate(ctx context.Context, userID int64, cod
 		return core.ErrTwoFactorAuthNotEnabled
 	}
 
-	if !totp.Validate(code, settings.Secret) {
-		return core.ErrTwoFactorCodeInvalid
+	if err := s.validateCode(ctx, settings, code); err != nil {
+		return err
 	}
 
 	if err := s.twoFactorSettingsRepository.Delete(ctx, settings); err != nil {
 		return err
 	}
 
-	return nil
+	return s.recoveryCodeRepository.DeleteByUser(ctx, userID)
+}
+
+func (s *twoFactorAuthService) Disable(ctx context.Context, userID int64) error {
+	settings, err := s.twoFactorSettingsRepository.Find(ctx, userID)
+	if err != nil {
+		return err
+	}
+
+	if settings == nil {
+		return core.ErrTwoFactorAuthNotEnabled
+	}
+
+	if err := s.twoFactorSettingsRepository.Delete(ctx, settings); err != nil {
+		return err
+	}
+
+	return s.recoveryCodeRepository.DeleteByUser(ctx, userID)
 }
 
 func (s *twoFactorAuthService) Verify(ctx context.Context, userID int64, code *string) error {
+	if core.IsTwoFactorVerified(ctx, userID) {
+		return nil
+	}
+
 	settings, err := s.twoFactorSettingsRepository.Find(ctx, userID)
 	if err != nil {
 		return err
@@ -107,34 +147,155 @@ func (s *twoFactorAuthService) Verify(ctx context.Context, userID int64, code *s
 		return core.ErrTwoFactorRequired
 	}
 
-	if totp.Validate(*code, settings.Secret) {
-		return nil
-	}
-
-	return core.ErrTwoFactorCodeInvalid
+	return s.validateCode(ctx, settings, *code)
 }
 
-func (s *twoFactorAuthService) Activate(ctx context.Context, userID int64, code string) error {
+func (s *twoFactorAuthService) Activate(
+	ctx context.Context,
+	userID int64,
+	code string,
+) ([]string, error) {
 	settings, err := s.twoFactorSettingsRepository.Find(ctx, userID)
 	if err != nil {
-		return err
+		return nil, err
 	}
 
 	if settings == nil {
-		return core.ErrTwoFactorAuthSetupNotRunning
+		return nil, core.ErrTwoFactorAuthSetupNotRunning
 	}
 
 	if settings.Enabled {
-		return core.ErrTwoFactorAuthAlreadyEnabled
+		return nil, core.ErrTwoFactorAuthAlreadyEnabled
 	}
 
 	if !totp.Validate(code, settings.Secret) {
-		return core.ErrTwoFactorCodeInvalid
+		return nil, core.ErrTwoFactorCodeInvalid
+	}
+
+	recoveryCodes, err := s.replaceRecoveryCodes(ctx, userID)
+	if err != nil {
+		return nil, err
 	}
 
 	settings.Enabled = true
 
-	return s.twoFactorSettingsRepository.Update(ctx, settings)
+	if err := s.twoFactorSettingsRepository.Update(ctx, settings); err != nil {
+		return nil, err
+	}
+
+	return recoveryCodes, nil
+}
+
+func (s *twoFactorAuthService) RegenerateRecoveryCodes(
+	ctx context.Context,
+	userID int64,
+) ([]string, error) {
+	settings, err := s.twoFactorSettingsRepository.Find(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+
+	if settings == nil || !settings.Enabled {
+		return nil, core.ErrTwoFactorAuthNotEnabled
+	}
+
+	return s.replaceRecoveryCodes(ctx, userID)
+}
+
+// validateCode validates a totp code and falls back to
+// consume a recovery code if the totp code is invalid.
+func (s *twoFactorAuthService) validateCode(
+	ctx context.Context,
+	settings *core.TwoFactorSettings,
+	code string,
+) error {
+	if totp.Validate(code, settings.Secret) {
+		return nil
+	}
+
+	recoveryCode := normalizeRecoveryCode(code)
+	if len(recoveryCode) != recoveryCodeLength {
+		return core.ErrTwoFactorCodeInvalid
+	}
+
+	ok, err := s.recoveryCodeRepository.Consume(
+		ctx,
+		settings.UserID,
+		hashRecoveryCode(recoveryCode),
+	)
+	if err != nil {
+		return err
+	}
+
+	if !ok {
+		return core.ErrTwoFactorCodeInvalid
+	}
+
+	return nil
+}
+
+// replaceRecoveryCodes generates a new set of recovery codes for
+// a user and replaces the stored ones by their hashes.
+func (s *twoFactorAuthService) replaceRecoveryCodes(
+	ctx context.Context,
+	userID int64,
+) ([]string, error) {
+	codes := make([]string, recoveryCodeCount)
+	hashedCodes := make([]string, recoveryCodeCount)
+
+	for i := range codes {
+		code, err := generateRecoveryCode()
+		if err != nil {
+			return nil, err
+		}
+
+		codes[i] = formatRecoveryCode(code)
+		hashedCodes[i] = hashRecoveryCode(code)
+	}
+
+	if err := s.recoveryCodeRepository.Replace(ctx, userID, hashedCodes); err != nil {
+		return nil, err
+	}
+
+	return codes, nil
+}
+
+// Generates a random recovery code without separators.
+func generateRecoveryCode() (string, error) {
+	b := make([]byte, recoveryCodeLength*5/8)
+	if _, err := rand.Read(b); err != nil {
+		return "", err
+	}
+
+	return recoveryCodeEncoding.EncodeToString(b), nil
+}
+
+// Formats a recovery code into groups of four characters
+// (e.g. abcd-efgh-ijkl-mnop) to make it easier to type.
+func formatRecoveryCode(code string) string {
+	groups := make([]string, 0, len(code)/4)
+	for i := 0; i < len(code); i += 4 {
+		groups = append(groups, code[i:i+4])
+	}
+
+	return strings.Join(groups, "-")
+}
+
+// Removes separators and whitespace from a recovery code
+// entered by a user and converts it to lower case.
+func normalizeRecoveryCode(code string) string {
+	return strings.Map(func(r rune) rune {
+		if r == '-' || r == ' ' {
+			return -1
+		}
+		return r
+	}, strings.ToLower(strings.TrimSpace(code)))
+}
+
+// Hashes a normalized recovery code with SHA256.
+func hashRecoveryCode(code string) string {
+	hash := sha256.Sum256([]byte(code))
+	return hex.EncodeToString(hash[:])
 }
 
 func (s *twoFactorAuthService) GetSettings(
@@ -1,7 +1,9 @@
 package auth_test
 
 import (
+	"context"
 	"errors"
+	"strings"
 	"testing"
 	"time"
 
@@ -34,6 +36,7 @@ func TestTwoFactorServiceVerify(t *testing.T) {
 
 	mockUserRepository := mock_core.NewMockUserRepository(ctrl)
 	mockTwoFactorSettingsRepository := mock_core.NewMockTwoFactorSettingsRepository(ctrl)
+	mockRecoveryCodeRepository := mock_core.NewMockTwoFactorRecoveryCodeRepository(ctrl)
 
 	mockTwoFactorSettingsRepository.EXPECT().
 		Find(ctx, userID).
@@ -44,6 +47,7 @@ func TestTwoFactorServiceVerify(t *testing.T) {
 
 	twoFactorService := auth.NewTwoFactorAuthService(
 		mockTwoFactorSettingsRepository,
+		mockRecoveryCodeRepository,
 		mockUserRepository,
 		cfg,
 	)
@@ -58,6 +62,7 @@ func TestTwoFactorServiceVerifyWhenTwoFactorIsNotEnabled(t *testing.T) {
 
 	mockUserRepository := mock_core.NewMockUserRepository(ctrl)
 	mockTwoFactorSettingsRepository := mock_core.NewMockTwoFactorSettingsRepository(ctrl)
+	mockRecoveryCodeRepository := mock_core.NewMockTwoFactorRecoveryCodeRepository(ctrl)
 
 	mockTwoFactorSettingsRepository.EXPECT().
 		Find(ctx, userID).
@@ -66,6 +71,7 @@ func TestTwoFactorServiceVerifyWhenTwoFactorIsNotEnabled(t *testing.T) {
 
 	twoFactorService := auth.NewTwoFactorAuthService(
 		mockTwoFactorSettingsRepository,
+		mockRecoveryCodeRepository,
 		mockUserRepository,
 		cfg,
 	)
@@ -74,12 +80,50 @@ func TestTwoFactorServiceVerifyWhenTwoFactorIsNotEnabled(t *testing.T) {
 	assert.Nil(t, err)
 }
 
+func TestTwoFactorServiceVerifyWhenTwoFactorIsAlreadyVerified(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	twoFactorService := auth.NewTwoFactorAuthService(
+		mock_core.NewMockTwoFactorSettingsRepository(ctrl),
+		mock_core.NewMockTwoFactorRecoveryCodeRepository(ctrl),
+		mock_core.NewMockUserRepository(ctrl),
+		cfg,
+	)
+
+	err := twoFactorService.Verify(core.WithTwoFactorVerified(ctx, userID), userID, nil)
+	assert.Nil(t, err)
+}
+
+func TestTwoFactorServiceVerifyIgnoresVerificationOfOtherUser(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockTwoFactorSettingsRepository := mock_core.NewMockTwoFactorSettingsRepository(ctrl)
+	mockTwoFactorSettingsRepository.EXPECT().
+		Find(gomock.Any(), userID).
+		Return(&core.TwoFactorSettings{
+			Enabled: true,
+		}, nil)
+
+	twoFactorService := auth.NewTwoFactorAuthService(
+		mockTwoFactorSettingsRepository,
+		mock_core.NewMockTwoFactorRecoveryCodeRepository(ctrl),
+		mock_core.NewMockUserRepository(ctrl),
+		cfg,
+	)
+
+	err := twoFactorService.Verify(core.WithTwoFactorVerified(ctx, userID+1), userID, nil)
+	assert.ErrorIs(t, err, core.ErrTwoFactorRequired)
+}
+
 func TestTwoFactorServiceVerifyReturnsErrTwoFactorRequired(t *testing.T) {
 	ctrl := gomock.NewController(t)
 	defer ctrl.Finish()
 
 	mockUserRepository := mock_core.NewMockUserRepository(ctrl)
 	mockTwoFactorSettingsRepository := mock_core.NewMockTwoFactorSettingsRepository(ctrl)
+	mockRecoveryCodeRepository := mock_core.NewMockTwoFactorRecoveryCodeRepository(ctrl)
 	mockTwoFactorSettingsRepository.EXPECT().
 		Find(ctx, userID).
 		Return(&core.TwoFactorSettings{
@@ -89,6 +133,7 @@ func TestTwoFactorServiceVerifyReturnsErrTwoFactorRequired(t *testing.T) {
 
 	twoFactorService := auth.NewTwoFactorAuthService(
 		mockTwoFactorSettingsRepository,
+		mockRecoveryCodeRepository,
 		mockUserRepository,
 		cfg,
 	)
@@ -103,6 +148,7 @@ func TestTwoFactorServiceVerifyReturnsErrTwoFactorCodeInvalid(t *testing.T) {
 
 	mockUserRepository := mock_core.NewMockUserRepository(ctrl)
 	mockTwoFactorSettingsRepository := mock_core.NewMockTwoFactorSettingsRepository(ctrl)
+	mockRecoveryCodeRepository := mock_core.NewMockTwoFactorRecoveryCodeRepository(ctrl)
 
 	mockTwoFactorSettingsRepository.EXPECT().
 		Find(ctx, userID).
@@ -114,6 +160,7 @@ func TestTwoFactorServiceVerifyReturnsErrTwoFactorCodeInvalid(t *testing.T) {
 
 	twoFactorService := auth.NewTwoFactorAuthService(
 		mockTwoFactorSettingsRepository,
+		mockRecoveryCodeRepository,
 		mockUserRepository,
 		cfg,
 	)
@@ -130,6 +177,7 @@ func TestGetSettingsReturnsTwoFactorSettings(t *testing.T) {
 
 	mockUserRepository := mock_core.NewMockUserRepository(ctrl)
 	mockTwoFactorSettingsRepository := mock_core.NewMockTwoFactorSettingsRepository(ctrl)
+	mockRecoveryCodeRepository := mock_core.NewMockTwoFactorRecoveryCodeRepository(ctrl)
 
 	expectedSettings := &core.TwoFactorSettings{
 		Enabled: true,
@@ -143,6 +191,7 @@ func TestGetSettingsReturnsTwoFactorSettings(t *testing.T) {
 
 	twoFactorService := auth.NewTwoFactorAuthService(
 		mockTwoFactorSettingsRepository,
+		mockRecoveryCodeRepository,
 		mockUserRepository,
 		cfg,
 	)
@@ -158,6 +207,7 @@ func TestGetSettingsReturnsError(t *testing.T) {
 
 	mockUserRepository := mock_core.NewMockUserRepository(ctrl)
 	mockTwoFactorSettingsRepository := mock_core.NewMockTwoFactorSettingsRepository(ctrl)
+	mockRecoveryCodeRepository := mock_core.NewMockTwoFactorRecoveryCodeRepository(ctrl)
 
 	mockTwoFactorSettingsRepository.EXPECT().
 		Find(ctx, userID).
@@ -166,6 +216,7 @@ func TestGetSettingsReturnsError(t *testing.T) {
 
 	twoFactorService := auth.NewTwoFactorAuthService(
 		mockTwoFactorSettingsRepository,
+		mockRecoveryCodeRepository,
 		mockUserRepository,
 		cfg,
 	)
@@ -181,6 +232,7 @@ func TestGetSettingsReturnsDefaultWhenNoSettingsArePresent(t *testing.T) {
 
 	mockUserRepository := mock_core.NewMockUserRepository(ctrl)
 	mockTwoFactorSettingsRepository := mock_core.NewMockTwoFactorSettingsRepository(ctrl)
+	mockRecoveryCodeRepository := mock_core.NewMockTwoFactorRecoveryCodeRepository(ctrl)
 
 	mockTwoFactorSettingsRepository.EXPECT().
 		Find(ctx, userID).
@@ -189,6 +241,7 @@ func TestGetSettingsReturnsDefaultWhenNoSettingsArePresent(t *testing.T) {
 
 	twoFactorService := auth.NewTwoFactorAuthService(
 		mockTwoFactorSettingsRepository,
+		mockRecoveryCodeRepository,
 		mockUserRepository,
 		cfg,
 	)
@@ -204,6 +257,7 @@ func TestSetupGeneratesAndPersistTOTPSecret(t *testing.T) {
 
 	mockUserRepository := mock_core.NewMockUserRepository(ctrl)
 	mockTwoFactorSettingsRepository := mock_core.NewMockTwoFactorSettingsRepository(ctrl)
+	mockRecoveryCodeRepository := mock_core.NewMockTwoFactorRecoveryCodeRepository(ctrl)
 
 	mockUserRepository.EXPECT().
 		FindById(ctx, userID).
@@ -225,6 +279,7 @@ func TestSetupGeneratesAndPersistTOTPSecret(t *testing.T) {
 
 	twoFactorService := auth.NewTwoFactorAuthService(
 		mockTwoFactorSettingsRepository,
+		mockRecoveryCodeRepository,
 		mockUserRepository,
 		cfg,
 	)
@@ -241,6 +296,7 @@ func TestSetupReturnsErrTwoFactorAuthAlreadyEnabled(t *testing.T) {
 
 	mockUserRepository := mock_core.NewMockUserRepository(ctrl)
 	mockTwoFactorSettingsRepository := mock_core.NewMockTwoFactorSettingsRepository(ctrl)
+	mockRecoveryCodeRepository := mock_core.NewMockTwoFactorRecoveryCodeRepository(ctrl)
 
 	mockTwoFactorSettingsRepository.EXPECT().
 		Find(ctx, userID).
@@ -251,6 +307,7 @@ func TestSetupReturnsErrTwoFactorAuthAlreadyEnabled(t *testing.T) {
 
 	twoFactorService := auth.NewTwoFactorAuthService(
 		mockTwoFactorSettingsRepository,
+		mockRecoveryCodeRepository,
 		mockUserRepository,
 		cfg,
 	)
@@ -266,6 +323,7 @@ func TestDeactivate(t *testing.T) {
 
 	mockUserRepository := mock_core.NewMockUserRepository(ctrl)
 	mockTwoFactorSettingsRepository := mock_core.NewMockTwoFactorSettingsRepository(ctrl)
+	mockRecoveryCodeRepository := mock_core.NewMockTwoFactorRecoveryCodeRepository(ctrl)
 
 	settings := &core.TwoFactorSettings{
 		UserID: userID,
@@ -282,8 +340,14 @@ func TestDeactivate(t *testing.T) {
 		Return(nil).
 		Times(1)
 
+	mockRecoveryCodeRepository.EXPECT().
+		DeleteByUser(ctx, userID).
+		Return(nil).
+		Times(1)
+
 	twoFactorService := auth.NewTwoFactorAuthService(
 		mockTwoFactorSettingsRepository,
+		mockRecoveryCodeRepository,
 		mockUserRepository,
 		cfg,
 	)
@@ -298,12 +362,74 @@ func TestDeactivate(t *testing.T) {
 	assert.Nil(t, err)
 }
 
+func TestDisable(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockUserRepository := mock_core.NewMockUserRepository(ctrl)
+	mockTwoFactorSettingsRepository := mock_core.NewMockTwoFactorSettingsRepository(ctrl)
+	mockRecoveryCodeRepository := mock_core.NewMockTwoFactorRecoveryCodeRepository(ctrl)
+
+	settings := &core.TwoFactorSettings{
+		UserID:  userID,
+		Secret:  totpSecret,
+		Enabled: true,
+	}
+
+	mockTwoFactorSettingsRepository.EXPECT().
+		Find(ctx, userID).
+		Return(settings, nil).
+		Times(1)
+
+	mockTwoFactorSettingsRepository.EXPECT().
+		Delete(ctx, settings).
+		Return(nil).
+		Times(1)
+
+	mockRecoveryCodeRepository.EXPECT().
+		DeleteByUser(ctx, userID).
+		Return(nil).
+		Times(1)
+
+	twoFactorService := auth.NewTwoFactorAuthService(
+		mockTwoFactorSettingsRepository,
+		mockRecoveryCodeRepository,
+		mockUserRepository,
+		cfg,
+	)
+
+	err := twoFactorService.Disable(ctx, userID)
+	assert.Nil(t, err)
+}
+
+func TestDisableReturnsErrTwoFactorAuthNotEnabled(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockTwoFactorSettingsRepository := mock_core.NewMockTwoFactorSettingsRepository(ctrl)
+	mockTwoFactorSettingsRepository.EXPECT().
+		Find(ctx, userID).
+		Return(nil, nil).
+		Times(1)
+
+	twoFactorService := auth.NewTwoFactorAuthService(
+		mockTwoFactorSettingsRepository,
+		mock_core.NewMockTwoFactorRecoveryCodeRepository(ctrl),
+		mock_core.NewMockUserRepository(ctrl),
+		cfg,
+	)
+
+	err := twoFactorService.Disable(ctx, userID)
+	assert.Equal(t, core.ErrTwoFactorAuthNotEnabled, err)
+}
+
 func TestDeactivateReturnsErrTwoFactorCodeInvalid(t *testing.T) {
 	ctrl := gomock.NewController(t)
 	defer ctrl.Finish()
 
 	mockUserRepository := mock_core.NewMockUserRepository(ctrl)
 	mockTwoFactorSettingsRepository := mock_core.NewMockTwoFactorSettingsRepository(ctrl)
+	mockRecoveryCodeRepository := mock_core.NewMockTwoFactorRecoveryCodeRepository(ctrl)
 
 	settings := &core.TwoFactorSettings{
 		UserID: userID,
@@ -317,6 +443,7 @@ func TestDeactivateReturnsErrTwoFactorCodeInvalid(t *testing.T) {
 
 	twoFactorService := auth.NewTwoFactorAuthService(
 		mockTwoFactorSettingsRepository,
+		mockRecoveryCodeRepository,
 		mockUserRepository,
 		cfg,
 	)
@@ -331,6 +458,7 @@ func TestActivate(t *testing.T) {
 
 	mockUserRepository := mock_core.NewMockUserRepository(ctrl)
 	mockTwoFactorSettingsRepository := mock_core.NewMockTwoFactorSettingsRepository(ctrl)
+	mockRecoveryCodeRepository := mock_core.NewMockTwoFactorRecoveryCodeRepository(ctrl)
 
 	settings := &core.TwoFactorSettings{
 		UserID: userID,
@@ -347,8 +475,14 @@ func TestActivate(t *testing.T) {
 		Return(nil).
 		Times(1)
 
+	mockRecoveryCodeRepository.EXPECT().
+		Replace(ctx, userID, gomock.Len(10)).
+		Return(nil).
+		Times(1)
+
 	twoFactorService := auth.NewTwoFactorAuthService(
 		mockTwoFactorSettingsRepository,
+		mockRecoveryCodeRepository,
 		mockUserRepository,
 		cfg,
 	)
@@ -359,8 +493,10 @@ func TestActivate(t *testing.T) {
 		return
 	}
 
-	err = twoFactorService.Activate(ctx, userID, code)
+	recoveryCodes, err := twoFactorService.Activate(ctx, userID, code)
 	assert.Nil(t, err)
+	assert.Len(t, recoveryCodes, 10)
+	assert.True(t, settings.Enabled)
 }
 
 func TestActivateReturnsErrTwoFactorCodeInvalid(t *testing.T) {
@@ -369,6 +505,7 @@ func TestActivateReturnsErrTwoFactorCodeInvalid(t *testing.T) {
 
 	mockUserRepository := mock_core.NewMockUserRepository(ctrl)
 	mockTwoFactorSettingsRepository := mock_core.NewMockTwoFactorSettingsRepository(ctrl)
+	mockRecoveryCodeRepository := mock_core.NewMockTwoFactorRecoveryCodeRepository(ctrl)
 
 	settings := &core.TwoFactorSettings{
 		UserID: userID,
@@ -382,11 +519,13 @@ func TestActivateReturnsErrTwoFactorCodeInvalid(t *testing.T) {
 
 	twoFactorService := auth.NewTwoFactorAuthService(
 		mockTwoFactorSettingsRepository,
+		mockRecoveryCodeRepository,
 		mockUserRepository,
 		cfg,
 	)
 
-	err := twoFactorService.Activate(ctx, userID, "invalid")
+	recoveryCodes, err := twoFactorService.Activate(ctx, userID, "invalid")
+	assert.Nil(t, recoveryCodes)
 	assert.ErrorIs(t, err, core.ErrTwoFactorCodeInvalid)
 }
 
@@ -396,6 +535,7 @@ func TestActivateReturnsErrTwoFactorAuthAlreadyEnabled(t *testing.T) {
 
 	mockUserRepository := mock_core.NewMockUserRepository(ctrl)
 	mockTwoFactorSettingsRepository := mock_core.NewMockTwoFactorSettingsRepository(ctrl)
+	mockRecoveryCodeRepository := mock_core.NewMockTwoFactorRecoveryCodeRepository(ctrl)
 
 	settings := &core.TwoFactorSettings{
 		UserID:  userID,
@@ -410,10 +550,124 @@ func TestActivateReturnsErrTwoFactorAuthAlreadyEnabled(t *testing.T) {
 
 	twoFactorService := auth.NewTwoFactorAuthService(
 		mockTwoFactorSettingsRepository,
+		mockRecoveryCodeRepository,
 		mockUserRepository,
 		cfg,
 	)
 
-	err := twoFactorService.Activate(ctx, userID, "ignored")
+	recoveryCodes, err := twoFactorService.Activate(ctx, userID, "ignored")
+	assert.Nil(t, recoveryCodes)
 	assert.ErrorIs(t, err, core.ErrTwoFactorAuthAlreadyEnabled)
 }
+
+func TestTwoFactorServiceVerifyAcceptsRecoveryCode(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockUserRepository := mock_core.NewMockUserRepository(ctrl)
+	mockTwoFactorSettingsRepository := mock_core.NewMockTwoFactorSettingsRepository(ctrl)
+	mockRecoveryCodeRepository := mock_core.NewMockTwoFactorRecoveryCodeRepository(ctrl)
+
+	mockTwoFactorSettingsRepository.EXPECT().
+		Find(ctx, userID).
+		Return(&core.TwoFactorSettings{
+			UserID:  userID,
+			Enabled: true,
+			Secret:  totpSecret,
+		}, nil).
+		Times(2)
+
+	var hashedCodes []string
+	mockRecoveryCodeRepository.EXPECT().
+		Replace(ctx, userID, gomock.Len(10)).
+		DoAndReturn(func(_ context.Context, _ int64, codes []string) error {
+			hashedCodes = codes
+			return nil
+		}).
+		Times(1)
+
+	twoFactorService := auth.NewTwoFactorAuthService(
+		mockTwoFactorSettingsRepository,
+		mockRecoveryCodeRepository,
+		mockUserRepository,
+		cfg,
+	)
+
+	recoveryCodes, err := twoFactorService.RegenerateRecoveryCodes(ctx, userID)
+	assert.Nil(t, err)
+	assert.Len(t, recoveryCodes, 10)
+	assert.Regexp(t, "^[a-z2-7]{4}-[a-z2-7]{4}-[a-z2-7]{4}-[a-z2-7]{4}$", recoveryCodes[0])
+
+	mockRecoveryCodeRepository.EXPECT().
+		Consume(ctx, userID, hashedCodes[0]).
+		Return(true, nil).
+		Times(1)
+
+	code := " " + strings.ToUpper(recoveryCodes[0]) + " "
+
+	err = twoFactorService.Verify(ctx, userID, &code)
+	assert.Nil(t, err)
+}
+
+func TestTwoFactorServiceVerifyReturnsErrTwoFactorCodeInvalidForUsedRecoveryCode(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockUserRepository := mock_core.NewMockUserRepository(ctrl)
+	mockTwoFactorSettingsRepository := mock_core.NewMockTwoFactorSettingsRepository(ctrl)
+	mockRecoveryCodeRepository := mock_core.NewMockTwoFactorRecoveryCodeRepository(ctrl)
+
+	mockTwoFactorSettingsRepository.EXPECT().
+		Find(ctx, userID).
+		Return(&core.TwoFactorSettings{
+			UserID:  userID,
+			Enabled: true,
+			Secret:  totpSecret,
+		}, nil).
+		Times(1)
+
+	mockRecoveryCodeRepository.EXPECT().
+		Consume(ctx, userID, gomock.Any()).
+		Return(false, nil).
+		Times(1)
+
+	twoFactorService := auth.NewTwoFactorAuthService(
+		mockTwoFactorSettingsRepository,
+		mockRecoveryCodeRepository,
+		mockUserRepository,
+		cfg,
+	)
+
+	code := "abcd-efgh-ijkl-mnop"
+
+	err := twoFactorService.Verify(ctx, userID, &code)
+	assert.ErrorIs(t, err, core.ErrTwoFactorCodeInvalid)
+}
+
+func TestRegenerateRecoveryCodesReturnsErrTwoFactorAuthNotEnabled(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockUserRepository := mock_core.NewMockUserRepository(ctrl)
+	mockTwoFactorSettingsRepository := mock_core.NewMockTwoFactorSettingsRepository(ctrl)
+	mockRecoveryCodeRepository := mock_core.NewMockTwoFactorRecoveryCodeRepository(ctrl)
+
+	mockTwoFactorSettingsRepository.EXPECT().
+		Find(ctx, userID).
+		Return(&core.TwoFactorSettings{
+			UserID: userID,
+			Secret: totpSecret,
+		}, nil).
+		Times(1)
+
+	twoFactorService := auth.NewTwoFactorAuthService(
+		mockTwoFactorSettingsRepository,
+		mockRecoveryCodeRepository,
+		mockUserRepository,
+		cfg,
+	)
+
+	recoveryCodes, err := twoFactorService.RegenerateRecoveryCodes(ctx, userID)
+	assert.Nil(t, recoveryCodes)
+	assert.ErrorIs(t, err, core.ErrTwoFactorAuthNotEnabled)
+}
@@ -0,0 +1,457 @@
+package auth
+
+import (
+	"bytes"
+	"context"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"strconv"
+	"sync"
+	"time"
+
+	"github.com/davidborzek/tvhgo/config"
+	"github.com/davidborzek/tvhgo/core"
+	"github.com/go-webauthn/webauthn/protocol"
+	"github.com/go-webauthn/webauthn/webauthn"
+	"github.com/rs/zerolog/log"
+)
+
+const (
+	// maxWebAuthnCeremonies limits the number of pending ceremonies,
+	// since logins can be started without authentication.
+	maxWebAuthnCeremonies = 1000
+
+	defaultWebAuthnCredentialName = "Passkey"
+)
+
+var errTooManyWebAuthnCeremonies = errors.New("too many pending webauthn ceremonies")
+
+type (
+	webAuthnCeremonyKind int
+
+	// webAuthnCeremony is the server side state of a started ceremony.
+	webAuthnCeremony struct {
+		kind    webAuthnCeremonyKind
+		userID  int64
+		session webauthn.SessionData
+		expires time.Time
+	}
+
+	// webAuthnUser adapts a user and its credentials to a webauthn.User.
+	webAuthnUser struct {
+		user        *core.User
+		credentials []webauthn.Credential
+	}
+)
+
+const (
+	webAuthnRegistration webAuthnCeremonyKind = iota
+	webAuthnLogin
+)
+
+type webAuthnService struct {
+	cfg         *config.WebAuthnConfig
+	webAuthn    *webauthn.WebAuthn
+	users       core.UserRepository
+	credentials core.WebAuthnCredentialRepository
+	clock       core.Clock
+
+	mu         sync.Mutex
+	ceremonies map[string]*webAuthnCeremony
+}
+
+// NewWebAuthnService creates a new WebAuthnService. Pending ceremonies are
+// kept in memory and can only be finished once.
+func NewWebAuthnService(
+	cfg *config.WebAuthnConfig,
+	users core.UserRepository,
+	credentials core.WebAuthnCredentialRepository,
+	clock core.Clock,
+) (core.WebAuthnService, error) {
+	timeout := webauthn.TimeoutConfig{
+		Enforce:    true,
+		Timeout:    cfg.Timeout,
+		TimeoutUVD: cfg.Timeout,
+	}
+
+	w, err := webauthn.New(&webauthn.Config{
+		RPID:          cfg.RPID,
+		RPDisplayName: cfg.RPDisplayName,
+		RPOrigins:     cfg.RPOrigins,
+		Timeouts: webauthn.TimeoutsConfig{
+			Login:        timeout,
+			Registration: timeout,
+		},
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return &webAuthnService{
+		cfg:         cfg,
+		webAuthn:    w,
+		users:       users,
+		credentials: credentials,
+		clock:       clock,
+		ceremonies:  make(map[string]*webAuthnCeremony),
+	}, nil
+}
+
+func (s *webAuthnService) BeginRegistration(
+	ctx context.Context,
+	userID int64,
+) (*core.WebAuthnCeremony, error) {
+	user, err := s.findUser(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+
+	creation, session, err := s.webAuthn.BeginRegistration(
+		user,
+		webauthn.WithExclusions(webauthn.Credentials(user.credentials).CredentialDescriptors()),
+		webauthn.WithResidentKeyRequirement(protocol.ResidentKeyRequirementRequired),
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	return s.begin(webAuthnRegistration, userID, session, creation)
+}
+
+func (s *webAuthnService) FinishRegistration(
+	ctx context.Context,
+	userID int64,
+	ceremonyID string,
+	name string,
+	response []byte,
+) (*core.WebAuthnCredential, error) {
+	ceremony := s.finish(ceremonyID, webAuthnRegistration)
+	if ceremony == nil || ceremony.userID != userID {
+		return nil, core.ErrWebAuthnCeremonyInvalid
+	}
+
+	parsed, err := protocol.ParseCredentialCreationResponseBytes(response)
+	if err != nil {
+		return nil, verificationFailed(userID, err)
+	}
+
+	user, err := s.findUser(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+
+	credential, err := s.webAuthn.CreateCredential(user, ceremony.session, parsed)
+	if err != nil {
+		return nil, verificationFailed(userID, err)
+	}
+
+	data, err := json.Marshal(credential)
+	if err != nil {
+		return nil, err
+	}
+
+	if name == "" {
+		name = defaultWebAuthnCredentialName
+	}
+
+	c := &core.WebAuthnCredential{
+		UserID:       userID,
+		Name:         name,
+		CredentialID: encodeCredentialID(credential.ID),
+		Data:         string(data),
+	}
+
+	if err := s.credentials.Create(ctx, c); err != nil {
+		return nil, err
+	}
+
+	return c, nil
+}
+
+// BeginLogin always starts a discoverable login, so that the response
+// doesn't reveal which users exist or have registered passkeys.
+func (s *webAuthnService) BeginLogin(ctx context.Context) (*core.WebAuthnCeremony, error) {
+	assertion, session, err := s.webAuthn.BeginDiscoverableLogin(
+		webauthn.WithUserVerification(protocol.VerificationRequired),
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	return s.begin(webAuthnLogin, 0, session, assertion)
+}
+
+func (s *webAuthnService) FinishLogin(
+	ctx context.Context,
+	ceremonyID string,
+	response []byte,
+) (*core.User, error) {
+	ceremony := s.finish(ceremonyID, webAuthnLogin)
+	if ceremony == nil {
+		return nil, core.ErrWebAuthnCeremonyInvalid
+	}
+
+	parsed, err := protocol.ParseCredentialRequestResponseBytes(response)
+	if err != nil {
+		return nil, verificationFailed(0, err)
+	}
+
+	u, credential, err := s.webAuthn.ValidatePasskeyLogin(s.discoverUser(ctx), ceremony.session, parsed)
+	if err != nil {
+		return nil, verificationFailed(0, err)
+	}
+
+	user := u.(*webAuthnUser)
+
+	if credential.Authenticator.CloneWarning {
+		log.Warn().Int64("userId", user.user.ID).
+			Msg("[webauthn] sign count of credential decreased, the authenticator may be cloned")
+
+		return nil, core.ErrWebAuthnVerificationFailed
+	}
+
+	if err := s.updateCredential(ctx, credential); err != nil {
+		return nil, err
+	}
+
+	if user.user.Disabled {
+		return nil, core.ErrUserDisabled
+	}
+
+	return user.user, nil
+}
+
+func (s *webAuthnService) GetCredentials(
+	ctx context.Context,
+	userID int64,
+) ([]*core.WebAuthnCredential, error) {
+	return s.credentials.FindByUser(ctx, userID)
+}
+
+func (s *webAuthnService) RenameCredential(
+	ctx context.Context,
+	userID int64,
+	id int64,
+	name string,
+) (*core.WebAuthnCredential, error) {
+	credential, err := s.findCredential(ctx, userID, id)
+	if err != nil {
+		return nil, err
+	}
+
+	credential.Name = name
+	if err := s.credentials.Update(ctx, credential); err != nil {
+		return nil, err
+	}
+
+	return credential, nil
+}
+
+func (s *webAuthnService) DeleteCredential(ctx context.Context, userID int64, id int64) error {
+	credential, err := s.findCredential(ctx, userID, id)
+	if err != nil {
+		return err
+	}
+
+	return s.credentials.Delete(ctx, credential)
+}
+
+// begin stores the session of a started ceremony and returns it with the options for the client.
+func (s *webAuthnService) begin(
+	kind webAuthnCeremonyKind,
+	userID int64,
+	session *webauthn.SessionData,
+	options any,
+) (*core.WebAuthnCeremony, error) {
+	encoded, err := json.Marshal(options)
+	if err != nil {
+		return nil, err
+	}
+
+	now := s.clock.Now()
+	id := randomToken()
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	for key, ceremony := range s.ceremonies {
+		if now.After(ceremony.expires) {
+			delete(s.ceremonies, key)
+		}
+	}
+
+	if len(s.ceremonies) >= maxWebAuthnCeremonies {
+		return nil, errTooManyWebAuthnCeremonies
+	}
+
+	s.ceremonies[id] = &webAuthnCeremony{
+		kind:    kind,
+		userID:  userID,
+		session: *session,
+		expires: now.Add(s.cfg.Timeout),
+	}
+
+	return &core.WebAuthnCeremony{
+		ID:      id,
+		Options: encoded,
+	}, nil
+}
+
+// finish removes a pending ceremony and returns it,
+// if it's of the expected kind and not expired.
+func (s *webAuthnService) finish(id string, kind webAuthnCeremonyKind) *webAuthnCeremony {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	ceremony, ok := s.ceremonies[id]
+	if !ok {
+		return nil
+	}
+
+	delete(s.ceremonies, id)
+
+	if ceremony.kind != kind || s.clock.Now().After(ceremony.expires) {
+		return nil
+	}
+
+	return ceremony
+}
+
+// discoverUser returns a handler which finds the owner of a discoverable credential.
+func (s *webAuthnService) discoverUser(ctx context.Context) webauthn.DiscoverableUserHandler {
+	return func(rawID, userHandle []byte) (webauthn.User, error) {
+		credential, err := s.credentials.FindByCredentialID(ctx, encodeCredentialID(rawID))
+		if err != nil {
+			return nil, err
+		}
+
+		if credential == nil {
+			return nil, core.ErrWebAuthnCredentialNotFound
+		}
+
+		user, err := s.findUser(ctx, credential.UserID)
+		if err != nil {
+			return nil, err
+		}
+
+		if !bytes.Equal(user.WebAuthnID(), userHandle) {
+			return nil, core.ErrWebAuthnCredentialNotFound
+		}
+
+		return user, nil
+	}
+}
+
+// updateCredential stores the sign count and flags of a used credential.
+func (s *webAuthnService) updateCredential(ctx context.Context, credential *webauthn.Credential) error {
+	stored, err := s.credentials.FindByCredentialID(ctx, encodeCredentialID(credential.ID))
+	if err != nil {
+		return err
+	}
+
+	if stored == nil {
+		return core.ErrWebAuthnCredentialNotFound
+	}
+
+	data, err := json.Marshal(credential)
+	if err != nil {
+		return err
+	}
+
+	lastUsedAt := s.clock.Now().Unix()
+	stored.Data = string(data)
+	stored.LastUsedAt = &lastUsedAt
+
+	return s.credentials.Update(ctx, stored)
+}
+
+func (s *webAuthnService) findCredential(
+	ctx context.Context,
+	userID int64,
+	id int64,
+) (*core.WebAuthnCredential, error) {
+	credential, err := s.credentials.FindById(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+
+	if credential == nil || credential.UserID != userID {
+		return nil, core.ErrWebAuthnCredentialNotFound
+	}
+
+	return credential, nil
+}
+
+func (s *webAuthnService) findUser(ctx context.Context, userID int64) (*webAuthnUser, error) {
+	user, err := s.users.FindById(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+
+	if user == nil {
+		return nil, core.ErrWebAuthnCeremonyInvalid
+	}
+
+	return s.loadUser(ctx, user)
+}
+
+// loadUser loads the credentials of a user.
+func (s *webAuthnService) loadUser(ctx context.Context, user *core.User) (*webAuthnUser, error) {
+	stored, err := s.credentials.FindByUser(ctx, user.ID)
+	if err != nil {
+		return nil, err
+	}
+
+	credentials := make([]webauthn.Credential, 0, len(stored))
+	for _, c := range stored {
+		var credential webauthn.Credential
+		if err := json.Unmarshal([]byte(c.Data), &credential); err != nil {
+			return nil, err
+		}
+
+		credentials = append(credentials, credential)
+	}
+
+	return &webAuthnUser{
+		user:        user,
+		credentials: credentials,
+	}, nil
+}
+
+func (u *webAuthnUser) WebAuthnID() []byte {
+	return []byte(strconv.FormatInt(u.user.ID, 10))
+}
+
+func (u *webAuthnUser) WebAuthnName() string {
+	return u.user.Username
+}
+
+func (u *webAuthnUser) WebAuthnDisplayName() string {
+	return u.user.DisplayName
+}
+
+func (u *webAuthnUser) WebAuthnCredentials() []webauthn.Credential {
+	return u.credentials
+}
+
+func encodeCredentialID(id []byte) string {
+	return base64.RawURLEncoding.EncodeToString(id)
+}
+
+// verificationFailed logs the reason of a failed verification,
+// which is not exposed to the client.
+func verificationFailed(userID int64, err error) error {
+	var protocolErr *protocol.Error
+	if errors.As(err, &protocolErr) {
+		log.Debug().Int64("userId", userID).
+			Str("details", protocolErr.Details).
+			Str("info", protocolErr.DevInfo).
+			Msg("[webauthn] verification failed")
+	} else {
+		log.Debug().Int64("userId", userID).
+			Err(err).Msg("[webauthn] verification failed")
+	}
+
+	return core.ErrWebAuthnVerificationFailed
+}
@@ -0,0 +1,459 @@
+package auth_test
+
+import (
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/binary"
+	"encoding/json"
+	"slices"
+	"testing"
+	"time"
+
+	"github.com/davidborzek/tvhgo/config"
+	"github.com/davidborzek/tvhgo/core"
+	mock_core "github.com/davidborzek/tvhgo/mock/core"
+	"github.com/davidborzek/tvhgo/services/auth"
+	"github.com/go-webauthn/webauthn/protocol/webauthncbor"
+	"github.com/go-webauthn/webauthn/protocol/webauthncose"
+	"github.com/stretchr/testify/assert"
+	"go.uber.org/mock/gomock"
+)
+
+const (
+	webAuthnRPID   = "tvhgo.example.com"
+	webAuthnOrigin = "https://tvhgo.example.com"
+)
+
+var webAuthnUser = &core.User{
+	ID:          1,
+	Username:    "jdoe",
+	DisplayName: "John Doe",
+}
+
+// softAuthenticator is a software authenticator with a single credential.
+type softAuthenticator struct {
+	key          *ecdsa.PrivateKey
+	credentialID []byte
+	userHandle   []byte
+	signCount    uint32
+}
+
+func newSoftAuthenticator(t *testing.T) *softAuthenticator {
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	assert.Nil(t, err)
+
+	return &softAuthenticator{
+		key:          key,
+		credentialID: []byte("someCredentialID"),
+	}
+}
+
+type webAuthnOptions struct {
+	PublicKey struct {
+		Challenge string `json:"challenge"`
+		User      struct {
+			ID string `json:"id"`
+		} `json:"user"`
+	} `json:"publicKey"`
+}
+
+func parseOptions(t *testing.T, ceremony *core.WebAuthnCeremony) webAuthnOptions {
+	var options webAuthnOptions
+	assert.Nil(t, json.Unmarshal(ceremony.Options, &options))
+	return options
+}
+
+func (a *softAuthenticator) clientData(typ string, challenge string) []byte {
+	data, _ := json.Marshal(map[string]string{
+		"type":      typ,
+		"challenge": challenge,
+		"origin":    webAuthnOrigin,
+	})
+	return data
+}
+
+func (a *softAuthenticator) authData(flags byte, attestedCredential []byte) []byte {
+	rpIDHash := sha256.Sum256([]byte(webAuthnRPID))
+
+	data := append([]byte{}, rpIDHash[:]...)
+	data = append(data, flags)
+	data = binary.BigEndian.AppendUint32(data, a.signCount)
+	return append(data, attestedCredential...)
+}
+
+// create returns the response of navigator.credentials.create().
+func (a *softAuthenticator) create(t *testing.T, ceremony *core.WebAuthnCeremony) []byte {
+	options := parseOptions(t, ceremony)
+
+	userHandle, err := base64.RawURLEncoding.DecodeString(options.PublicKey.User.ID)
+	assert.Nil(t, err)
+	a.userHandle = userHandle
+
+	publicKey, err := webauthncbor.Marshal(webauthncose.EC2PublicKeyData{
+		PublicKeyData: webauthncose.PublicKeyData{
+			KeyType:   int64(webauthncose.EllipticKey),
+			Algorithm: int64(webauthncose.AlgES256),
+		},
+		Curve:  int64(webauthncose.P256),
+		XCoord: a.key.X.FillBytes(make([]byte, 32)),
+		YCoord: a.key.Y.FillBytes(make([]byte, 32)),
+	})
+	assert.Nil(t, err)
+
+	attested := make([]byte, 16)
+	attested = binary.BigEndian.AppendUint16(attested, uint16(len(a.credentialID)))
+	attested = append(attested, a.credentialID...)
+	attested = append(attested, publicKey...)
+
+	attestationObject, err := webauthncbor.Marshal(map[string]any{
+		"fmt":      "none",
+		"attStmt":  map[string]any{},
+		"authData": a.authData(0x45, attested),
+	})
+	assert.Nil(t, err)
+
+	return a.response(map[string]string{
+		"clientDataJSON":    encode(a.clientData("webauthn.create", options.PublicKey.Challenge)),
+		"attestationObject": encode(attestationObject),
+	})
+}
+
+// get returns the response of navigator.credentials.get().
+func (a *softAuthenticator) get(t *testing.T, ceremony *core.WebAuthnCeremony) []byte {
+	options := parseOptions(t, ceremony)
+
+	a.signCount++
+	authData := a.authData(0x05, nil)
+	clientData := a.clientData("webauthn.get", options.PublicKey.Challenge)
+
+	clientDataHash := sha256.Sum256(clientData)
+	digest := sha256.Sum256(append(append([]byte{}, authData...), clientDataHash[:]...))
+
+	signature, err := ecdsa.SignASN1(rand.Reader, a.key, digest[:])
+	assert.Nil(t, err)
+
+	return a.response(map[string]string{
+		"clientDataJSON":    encode(clientData),
+		"authenticatorData": encode(authData),
+		"signature":         encode(signature),
+		"userHandle":        encode(a.userHandle),
+	})
+}
+
+func (a *softAuthenticator) response(response map[string]string) []byte {
+	data, _ := json.Marshal(map[string]any{
+		"id":       encode(a.credentialID),
+		"rawId":    encode(a.credentialID),
+		"type":     "public-key",
+		"response": response,
+	})
+	return data
+}
+
+func encode(b []byte) string {
+	return base64.RawURLEncoding.EncodeToString(b)
+}
+
+// fakeCredentialStore keeps the credentials of the mocked repository.
+type fakeCredentialStore struct {
+	credentials []*core.WebAuthnCredential
+}
+
+func (f *fakeCredentialStore) expect(mock *mock_core.MockWebAuthnCredentialRepository) {
+	mock.EXPECT().
+		FindByUser(gomock.Any(), gomock.Any()).
+		DoAndReturn(func(_ any, userID int64) ([]*core.WebAuthnCredential, error) {
+			var found []*core.WebAuthnCredential
+			for _, c := range f.credentials {
+				if c.UserID == userID {
+					found = append(found, c)
+				}
+			}
+			return found, nil
+		}).
+		AnyTimes()
+
+	mock.EXPECT().
+		FindByCredentialID(gomock.Any(), gomock.Any()).
+		DoAndReturn(func(_ any, credentialID string) (*core.WebAuthnCredential, error) {
+			for _, c := range f.credentials {
+				if c.CredentialID == credentialID {
+					return c, nil
+				}
+			}
+			return nil, nil
+		}).
+		AnyTimes()
+
+	mock.EXPECT().
+		Create(gomock.Any(), gomock.Any()).
+		DoAndReturn(func(_ any, c *core.WebAuthnCredential) error {
+			c.ID = int64(len(f.credentials) + 1)
+			f.credentials = append(f.credentials, c)
+			return nil
+		}).
+		AnyTimes()
+
+	mock.EXPECT().
+		FindById(gomock.Any(), gomock.Any()).
+		DoAndReturn(func(_ any, id int64) (*core.WebAuthnCredential, error) {
+			for _, c := range f.credentials {
+				if c.ID == id {
+					return c, nil
+				}
+			}
+			return nil, nil
+		}).
+		AnyTimes()
+
+	mock.EXPECT().
+		Update(gomock.Any(), gomock.Any()).
+		Return(nil).
+		AnyTimes()
+
+	mock.EXPECT().
+		Delete(gomock.Any(), gomock.Any()).
+		DoAndReturn(func(_ any, c *core.WebAuthnCredential) error {
+			f.credentials = slices.DeleteFunc(f.credentials, func(e *core.WebAuthnCredential) bool {
+				return e.ID == c.ID
+			})
+			return nil
+		}).
+		AnyTimes()
+}
+
+func newWebAuthnService(
+	t *testing.T,
+	ctrl *gomock.Controller,
+	now *time.Time,
+) (core.WebAuthnService, *fakeCredentialStore) {
+	mockUsers := mock_core.NewMockUserRepository(ctrl)
+	mockUsers.EXPECT().
+		FindById(gomock.Any(), webAuthnUser.ID).
+		Return(webAuthnUser, nil).
+		AnyTimes()
+	mockUsers.EXPECT().
+		FindByUsername(gomock.Any(), gomock.Any()).
+		DoAndReturn(func(_ any, username string) (*core.User, error) {
+			if username == webAuthnUser.Username {
+				return webAuthnUser, nil
+			}
+			return nil, nil
+		}).
+		AnyTimes()
+
+	store := &fakeCredentialStore{}
+	mockCredentials := mock_core.NewMockWebAuthnCredentialRepository(ctrl)
+	store.expect(mockCredentials)
+
+	mockClock := mock_core.NewMockClock(ctrl)
+	mockClock.EXPECT().
+		Now().
+		DoAndReturn(func() time.Time { return *now }).
+		AnyTimes()
+
+	service, err := auth.NewWebAuthnService(&config.WebAuthnConfig{
+		RPID:          webAuthnRPID,
+		RPDisplayName: "tvhgo",
+		RPOrigins:     []string{webAuthnOrigin},
+		Timeout:       5 * time.Minute,
+	}, mockUsers, mockCredentials, mockClock)
+	assert.Nil(t, err)
+
+	return service, store
+}
+
+func register(
+	t *testing.T,
+	service core.WebAuthnService,
+	authenticator *softAuthenticator,
+) *core.WebAuthnCredential {
+	ceremony, err := service.BeginRegistration(ctx, webAuthnUser.ID)
+	assert.Nil(t, err)
+
+	credential, err := service.FinishRegistration(
+		ctx, webAuthnUser.ID, ceremony.ID, "", authenticator.create(t, ceremony))
+	assert.Nil(t, err)
+
+	return credential
+}
+
+func TestWebAuthnRegistration(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	now := time.Now()
+	service, store := newWebAuthnService(t, ctrl, &now)
+	authenticator := newSoftAuthenticator(t)
+
+	credential := register(t, service, authenticator)
+	assert.Equal(t, "Passkey", credential.Name)
+	assert.Equal(t, webAuthnUser.ID, credential.UserID)
+	assert.Equal(t, encode(authenticator.credentialID), credential.CredentialID)
+	assert.Len(t, store.credentials, 1)
+}
+
+func TestWebAuthnBeginRegistrationRequiresDiscoverableCredential(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	now := time.Now()
+	service, _ := newWebAuthnService(t, ctrl, &now)
+
+	ceremony, err := service.BeginRegistration(ctx, webAuthnUser.ID)
+	assert.Nil(t, err)
+	assert.Contains(t, string(ceremony.Options), `"residentKey":"required"`)
+}
+
+func TestWebAuthnRegistrationFailsForCeremonyOfOtherUser(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	now := time.Now()
+	service, _ := newWebAuthnService(t, ctrl, &now)
+
+	ceremony, err := service.BeginRegistration(ctx, webAuthnUser.ID)
+	assert.Nil(t, err)
+
+	_, err = service.FinishRegistration(ctx, 2, ceremony.ID, "", nil)
+	assert.Equal(t, core.ErrWebAuthnCeremonyInvalid, err)
+}
+
+func TestWebAuthnDiscoverableLogin(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	now := time.Now()
+	service, store := newWebAuthnService(t, ctrl, &now)
+	authenticator := newSoftAuthenticator(t)
+	register(t, service, authenticator)
+
+	ceremony, err := service.BeginLogin(ctx)
+	assert.Nil(t, err)
+
+	user, err := service.FinishLogin(ctx, ceremony.ID, authenticator.get(t, ceremony))
+	assert.Nil(t, err)
+	assert.Equal(t, webAuthnUser, user)
+	assert.NotNil(t, store.credentials[0].LastUsedAt)
+}
+
+func TestWebAuthnBeginLoginDoesNotRevealCredentials(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	now := time.Now()
+	service, _ := newWebAuthnService(t, ctrl, &now)
+	authenticator := newSoftAuthenticator(t)
+	register(t, service, authenticator)
+
+	ceremony, err := service.BeginLogin(ctx)
+	assert.Nil(t, err)
+	assert.NotContains(t, string(ceremony.Options), "allowCredentials")
+	assert.NotContains(t, string(ceremony.Options), encode(authenticator.credentialID))
+}
+
+func TestWebAuthnLoginCeremonyCanOnlyBeUsedOnce(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	now := time.Now()
+	service, _ := newWebAuthnService(t, ctrl, &now)
+	authenticator := newSoftAuthenticator(t)
+	register(t, service, authenticator)
+
+	ceremony, err := service.BeginLogin(ctx)
+	assert.Nil(t, err)
+
+	_, err = service.FinishLogin(ctx, ceremony.ID, authenticator.get(t, ceremony))
+	assert.Nil(t, err)
+
+	_, err = service.FinishLogin(ctx, ceremony.ID, authenticator.get(t, ceremony))
+	assert.Equal(t, core.ErrWebAuthnCeremonyInvalid, err)
+}
+
+func TestWebAuthnLoginFailsForExpiredCeremony(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	now := time.Now()
+	service, _ := newWebAuthnService(t, ctrl, &now)
+	authenticator := newSoftAuthenticator(t)
+	register(t, service, authenticator)
+
+	ceremony, err := service.BeginLogin(ctx)
+	assert.Nil(t, err)
+
+	now = now.Add(6 * time.Minute)
+
+	_, err = service.FinishLogin(ctx, ceremony.ID, authenticator.get(t, ceremony))
+	assert.Equal(t, core.ErrWebAuthnCeremonyInvalid, err)
+}
+
+func TestWebAuthnLoginFailsForInvalidSignature(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	now := time.Now()
+	service, _ := newWebAuthnService(t, ctrl, &now)
+	authenticator := newSoftAuthenticator(t)
+	register(t, service, authenticator)
+
+	ceremony, err := service.BeginLogin(ctx)
+	assert.Nil(t, err)
+
+	// Sign with another key for the same credential id.
+	other := newSoftAuthenticator(t)
+	other.userHandle = authenticator.userHandle
+
+	_, err = service.FinishLogin(ctx, ceremony.ID, other.get(t, ceremony))
+	assert.Equal(t, core.ErrWebAuthnVerificationFailed, err)
+}
+
+func TestWebAuthnLoginFailsForCeremonyOfRegistration(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	now := time.Now()
+	service, _ := newWebAuthnService(t, ctrl, &now)
+
+	ceremony, err := service.BeginRegistration(ctx, webAuthnUser.ID)
+	assert.Nil(t, err)
+
+	_, err = service.FinishLogin(ctx, ceremony.ID, nil)
+	assert.Equal(t, core.ErrWebAuthnCeremonyInvalid, err)
+}
+
+func TestWebAuthnRenameCredential(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	now := time.Now()
+	service, _ := newWebAuthnService(t, ctrl, &now)
+	credential := register(t, service, newSoftAuthenticator(t))
+
+	renamed, err := service.RenameCredential(ctx, webAuthnUser.ID, credential.ID, "Living room")
+	assert.Nil(t, err)
+	assert.Equal(t, "Living room", renamed.Name)
+
+	_, err = service.RenameCredential(ctx, 2, credential.ID, "Hijacked")
+	assert.Equal(t, core.ErrWebAuthnCredentialNotFound, err)
+}
+
+func TestWebAuthnDeleteCredential(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	now := time.Now()
+	service, store := newWebAuthnService(t, ctrl, &now)
+	credential := register(t, service, newSoftAuthenticator(t))
+
+	err := service.DeleteCredential(ctx, 2, credential.ID)
+	assert.Equal(t, core.ErrWebAuthnCredentialNotFound, err)
+
+	err = service.DeleteCredential(ctx, webAuthnUser.ID, credential.ID)
+	assert.Nil(t, err)
+	assert.Empty(t, store.credentials)
+}
@@ -53,6 +53,7 @@ func (s *service) GetAll(
 			Enabled: entry.Enabled,
 			Number:  entry.Number,
 			PiconID: core.MapTvheadendIconUrlToPiconID(entry.IconPublicURL),
+			Tags:    entry.Tags,
 		}
 
 		channels = append(channels, c)
@@ -0,0 +1,163 @@
+package encryption
+
+import (
+	"crypto/aes"
+	"crypto/cipher"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/hex"
+	"fmt"
+	"os"
+	"strings"
+
+	"github.com/davidborzek/tvhgo/config"
+	"github.com/davidborzek/tvhgo/core"
+)
+
+// prefix marks encrypted values. It is followed by the
+// id of the key and the base64 encoded nonce and ciphertext.
+const prefix = "enc:v1:"
+
+type key struct {
+	id   string
+	aead cipher.AEAD
+}
+
+type encryptor struct {
+	keys []key
+}
+
+// New creates a new Encryptor, which encrypts values with AES-256-GCM.
+// Without configured keys, values are stored unencrypted.
+func New(cfg *config.EncryptionConfig) (core.Encryptor, error) {
+	encoded := cfg.Keys
+
+	if cfg.KeyFile != "" {
+		content, err := os.ReadFile(cfg.KeyFile)
+		if err != nil {
+			return nil, fmt.Errorf("failed to read encryption key file: %w", err)
+		}
+
+		for _, line := range strings.Split(string(content), "\n") {
+			line = strings.TrimSpace(line)
+			if line != "" && !strings.HasPrefix(line, "#") {
+				encoded = append(encoded, line)
+			}
+		}
+	}
+
+	e := &encryptor{
+		keys: make([]key, 0, len(encoded)),
+	}
+
+	for _, value := range encoded {
+		k, err := newKey(value)
+		if err != nil {
+			return nil, err
+		}
+
+		e.keys = append(e.keys, k)
+	}
+
+	return e, nil
+}
+
+func newKey(encoded string) (key, error) {
+	raw, err := base64.StdEncoding.DecodeString(encoded)
+	if err != nil || len(raw) != 32 {
+		return key{}, fmt.Errorf("encryption keys must be base64 encoded 32 byte keys")
+	}
+
+	block, err := aes.NewCipher(raw)
+	if err != nil {
+		return key{}, err
+	}
+
+	aead, err := cipher.NewGCM(block)
+	if err != nil {
+		return key{}, err
+	}
+
+	sum := sha256.Sum256(raw)
+
+	return key{
+		id:   hex.EncodeToString(sum[:4]),
+		aead: aead,
+	}, nil
+}
+
+func (e *encryptor) Enabled() bool {
+	return len(e.keys) > 0
+}
+
+func (e *encryptor) Encrypt(value string, associatedData string) (string, error) {
+	if !e.Enabled() {
+		return value, nil
+	}
+
+	k := e.keys[0]
+
+	nonce := make([]byte, k.aead.NonceSize())
+	if _, err := rand.Read(nonce); err != nil {
+		return "", err
+	}
+
+	sealed := k.aead.Seal(nonce, nonce, []byte(value), additionalData(k.id, associatedData))
+
+	return prefix + k.id + ":" + base64.RawStdEncoding.EncodeToString(sealed), nil
+}
+
+func (e *encryptor) Decrypt(value string, associatedData string) (string, error) {
+	id, data, ok := parse(value)
+	if !ok {
+		return value, nil
+	}
+
+	for _, k := range e.keys {
+		if k.id != id {
+			continue
+		}
+
+		sealed, err := base64.RawStdEncoding.DecodeString(data)
+		if err != nil || len(sealed) < k.aead.NonceSize() {
+			return "", core.ErrEncryptionValueInvalid
+		}
+
+		nonce, ciphertext := sealed[:k.aead.NonceSize()], sealed[k.aead.NonceSize():]
+		plaintext, err := k.aead.Open(nil, nonce, ciphertext, additionalData(k.id, associatedData))
+		if err != nil {
+			return "", core.ErrEncryptionValueInvalid
+		}
+
+		return string(plaintext), nil
+	}
+
+	return "", core.ErrEncryptionKeyNotFound
+}
+
+func (e *encryptor) NeedsReencryption(value string) bool {
+	if !e.Enabled() {
+		return false
+	}
+
+	id, _, ok := parse(value)
+	return !ok || id != e.keys[0].id
+}
+
+// additionalData returns the data authenticated with a value,
+// which binds it to the key and to the associated data.
+func additionalData(id string, associatedData string) []byte {
+	return []byte(id + ":" + associatedData)
+}
+
+// parse splits an encrypted value into the key id and the encoded
+// data. It returns false if the value is not encrypted.
+func parse(value string) (string, string, bool) {
+	rest, ok := strings.CutPrefix(value, prefix)
+	if !ok {
+		return "", "", false
+	}
+
+	return strings.Cut(rest, ":")
+}
@@ -0,0 +1,132 @@
+package encryption_test
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/davidborzek/tvhgo/config"
+	"github.com/davidborzek/tvhgo/core"
+	"github.com/davidborzek/tvhgo/services/encryption"
+	"github.com/stretchr/testify/assert"
+)
+
+const (
+	oldKey     = "k9O6ZYnWgmq8RkQ4gOZcjX0bYvE2xq6e5OZ0zYcQdS0="
+	currentKey = "3Xb4yJt0hN1bqk8m4u5Y7v2cB6n9QeRtYuIoPaSdF0g="
+
+	owner = "two_factor_settings:1"
+)
+
+func newEncryptor(t *testing.T, keys ...string) core.Encryptor {
+	encryptor, err := encryption.New(&config.EncryptionConfig{Keys: keys})
+	assert.Nil(t, err)
+	return encryptor
+}
+
+func TestEncryptAndDecrypt(t *testing.T) {
+	encryptor := newEncryptor(t, currentKey)
+
+	encrypted, err := encryptor.Encrypt("someSecret", owner)
+	assert.Nil(t, err)
+	assert.True(t, strings.HasPrefix(encrypted, "enc:v1:"))
+	assert.NotContains(t, encrypted, "someSecret")
+	assert.False(t, encryptor.NeedsReencryption(encrypted))
+
+	decrypted, err := encryptor.Decrypt(encrypted, owner)
+	assert.Nil(t, err)
+	assert.Equal(t, "someSecret", decrypted)
+}
+
+func TestEncryptUsesRandomNonce(t *testing.T) {
+	encryptor := newEncryptor(t, currentKey)
+
+	first, _ := encryptor.Encrypt("someSecret", owner)
+	second, _ := encryptor.Encrypt("someSecret", owner)
+
+	assert.NotEqual(t, first, second)
+}
+
+func TestDecryptWithOldKey(t *testing.T) {
+	encrypted, err := newEncryptor(t, oldKey).Encrypt("someSecret", owner)
+	assert.Nil(t, err)
+
+	encryptor := newEncryptor(t, currentKey, oldKey)
+	assert.True(t, encryptor.NeedsReencryption(encrypted))
+
+	decrypted, err := encryptor.Decrypt(encrypted, owner)
+	assert.Nil(t, err)
+	assert.Equal(t, "someSecret", decrypted)
+}
+
+func TestDecryptReturnsErrEncryptionKeyNotFound(t *testing.T) {
+	encrypted, _ := newEncryptor(t, oldKey).Encrypt("someSecret", owner)
+
+	_, err := newEncryptor(t, currentKey).Decrypt(encrypted, owner)
+	assert.Equal(t, core.ErrEncryptionKeyNotFound, err)
+}
+
+func TestDecryptReturnsErrEncryptionValueInvalid(t *testing.T) {
+	encryptor := newEncryptor(t, currentKey)
+	encrypted, _ := encryptor.Encrypt("someSecret", owner)
+
+	_, err := encryptor.Decrypt(encrypted[:len(encrypted)-4]+"AAAA", owner)
+	assert.Equal(t, core.ErrEncryptionValueInvalid, err)
+}
+
+func TestDecryptReturnsErrEncryptionValueInvalidForOtherAssociatedData(t *testing.T) {
+	encryptor := newEncryptor(t, currentKey)
+	encrypted, _ := encryptor.Encrypt("someSecret", owner)
+
+	_, err := encryptor.Decrypt(encrypted, "two_factor_settings:2")
+	assert.Equal(t, core.ErrEncryptionValueInvalid, err)
+}
+
+func TestUnencryptedValues(t *testing.T) {
+	encryptor := newEncryptor(t, currentKey)
+
+	decrypted, err := encryptor.Decrypt("someSecret", owner)
+	assert.Nil(t, err)
+	assert.Equal(t, "someSecret", decrypted)
+	assert.True(t, encryptor.NeedsReencryption("someSecret"))
+}
+
+func TestWithoutKeysValuesAreNotEncrypted(t *testing.T) {
+	encryptor := newEncryptor(t)
+
+	encrypted, err := encryptor.Encrypt("someSecret", owner)
+	assert.Nil(t, err)
+	assert.Equal(t, "someSecret", encrypted)
+	assert.False(t, encryptor.Enabled())
+	assert.False(t, encryptor.NeedsReencryption("someSecret"))
+}
+
+func TestNewLoadsKeysFromKeyFile(t *testing.T) {
+	keyFile := filepath.Join(t.TempDir(), "keys")
+	err := os.WriteFile(keyFile, []byte("# rotated on 2024-01-01\n"+oldKey+"\n\n"), 0o600)
+	assert.Nil(t, err)
+
+	encrypted, _ := newEncryptor(t, oldKey).Encrypt("someSecret", owner)
+
+	encryptor, err := encryption.New(&config.EncryptionConfig{
+		Keys:    []string{currentKey},
+		KeyFile: keyFile,
+	})
+	assert.Nil(t, err)
+
+	decrypted, err := encryptor.Decrypt(encrypted, owner)
+	assert.Nil(t, err)
+	assert.Equal(t, "someSecret", decrypted)
+	assert.True(t, encryptor.NeedsReencryption(encrypted))
+}
+
+func TestNewFailsForInvalidKey(t *testing.T) {
+	_, err := encryption.New(&config.EncryptionConfig{Keys: []string{"tooShort"}})
+	assert.EqualError(t, err, "encryption keys must be base64 encoded 32 byte keys")
+}
+
+func TestNewFailsForMissingKeyFile(t *testing.T) {
+	_, err := encryption.New(&config.EncryptionConfig{KeyFile: "/nonexistent/keys"})
+	assert.ErrorContains(t, err, "failed to read encryption key file")
+}
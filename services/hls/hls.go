@@ -0,0 +1,449 @@
+package hls
+
+import (
+	"context"
+	"fmt"
+	"math"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/davidborzek/tvhgo/config"
+	"github.com/davidborzek/tvhgo/core"
+	"github.com/rs/zerolog/log"
+)
+
+// defaultProfile is used when no profile is requested, because
+// the segmenter requires a mpeg-ts stream.
+const defaultProfile = "pass"
+
+type (
+	// liveStream is a running channel stream which is
+	// shared by all viewers of the same channel and profile.
+	liveStream struct {
+		key    string
+		cancel context.CancelFunc
+
+		mu         sync.RWMutex
+		segments   []segment
+		lastAccess time.Time
+		err        error
+
+		ready     chan struct{}
+		readyOnce sync.Once
+	}
+
+	// viewer is a client watching a stream, which is
+	// registered as stream session on its first request.
+	viewer struct {
+		sessionID string
+		// ctx is cancelled when the session is terminated.
+		ctx        context.Context
+		lastAccess time.Time
+	}
+
+	service struct {
+		cfg       *config.HLSConfig
+		streaming core.StreamingService
+		limiter   core.StreamLimiter
+		sessions  core.StreamSessionRegistry
+		clock     core.Clock
+
+		mu      sync.Mutex
+		streams map[string]*liveStream
+		viewers map[string]*viewer
+
+		// joinMu serializes the registration of new viewers,
+		// so that a viewer is only registered once.
+		joinMu sync.Mutex
+
+		stop context.CancelFunc
+	}
+)
+
+// New creates a new HLSService which remuxes channel streams
+// of the streaming service to HLS. Viewers are registered as
+// stream sessions and are subject to the stream limits.
+func New(
+	cfg *config.HLSConfig,
+	streaming core.StreamingService,
+	limiter core.StreamLimiter,
+	sessions core.StreamSessionRegistry,
+	clock core.Clock,
+) *service {
+	return &service{
+		cfg:       cfg,
+		streaming: streaming,
+		limiter:   limiter,
+		sessions:  sessions,
+		clock:     clock,
+		streams:   make(map[string]*liveStream),
+		viewers:   make(map[string]*viewer),
+	}
+}
+
+// Start starts the cleanup of idle streams and viewers.
+func (s *service) Start() {
+	ctx, cancel := context.WithCancel(context.Background())
+	s.stop = cancel
+
+	ticker := time.NewTicker(s.cfg.IdleTimeout / 2)
+
+	go func() {
+		defer ticker.Stop()
+
+		for {
+			select {
+			case <-ticker.C:
+				s.RunCleanup()
+			case <-ctx.Done():
+				return
+			}
+		}
+	}()
+}
+
+// Stop stops the cleanup, all running streams and the sessions of all viewers.
+func (s *service) Stop() {
+	if s.stop != nil {
+		s.stop()
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	for key, stream := range s.streams {
+		stream.cancel()
+		delete(s.streams, key)
+	}
+
+	for key, v := range s.viewers {
+		s.sessions.Stop(v.sessionID)
+		delete(s.viewers, key)
+	}
+}
+
+// RunCleanup stops all streams which were not accessed within the idle
+// timeout and ends the sessions of viewers which did not request them.
+func (s *service) RunCleanup() {
+	deadline := s.clock.Now().Add(-s.cfg.IdleTimeout)
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	for key, v := range s.viewers {
+		if !v.lastAccess.Before(deadline) {
+			continue
+		}
+
+		log.Debug().Str("session", v.sessionID).Msg("ending idle hls viewer session")
+
+		s.sessions.Stop(v.sessionID)
+		delete(s.viewers, key)
+	}
+
+	for key, stream := range s.streams {
+		stream.mu.RLock()
+		idle := stream.lastAccess.Before(deadline)
+		stream.mu.RUnlock()
+
+		if !idle {
+			continue
+		}
+
+		log.Debug().Str("stream", key).Msg("stopping idle hls stream")
+
+		stream.cancel()
+		delete(s.streams, key)
+	}
+}
+
+func (s *service) GetPlaylist(
+	ctx context.Context,
+	session core.StreamSession,
+	query string,
+	authorize func() error,
+) ([]byte, error) {
+	if _, err := s.join(ctx, session, authorize); err != nil {
+		return nil, err
+	}
+
+	stream := s.getOrStart(session.ChannelNumber, session.Profile)
+
+	timeout := time.NewTimer(s.cfg.StartTimeout)
+	defer timeout.Stop()
+
+	select {
+	case <-stream.ready:
+	case <-timeout.C:
+		return nil, core.ErrHLSStreamTimeout
+	case <-ctx.Done():
+		return nil, ctx.Err()
+	}
+
+	stream.mu.Lock()
+	defer stream.mu.Unlock()
+
+	if stream.err != nil {
+		return nil, stream.err
+	}
+
+	stream.lastAccess = s.clock.Now()
+
+	window := stream.segments
+	if len(window) > s.cfg.PlaylistSize {
+		window = window[len(window)-s.cfg.PlaylistSize:]
+	}
+
+	return renderPlaylist(window, query), nil
+}
+
+func (s *service) GetSegment(
+	ctx context.Context,
+	session core.StreamSession,
+	sequence int64,
+) ([]byte, error) {
+	// Segments are only served to viewers, which requested the playlist.
+	v, err := s.lookupViewer(viewerKey(session))
+	if err != nil {
+		return nil, err
+	}
+
+	if v == nil {
+		return nil, core.ErrHLSStreamNotFound
+	}
+
+	s.mu.Lock()
+	stream, ok := s.streams[streamKey(session.ChannelNumber, session.Profile)]
+	s.mu.Unlock()
+
+	if !ok {
+		return nil, core.ErrHLSStreamNotFound
+	}
+
+	stream.mu.Lock()
+	defer stream.mu.Unlock()
+
+	stream.lastAccess = s.clock.Now()
+
+	for _, seg := range stream.segments {
+		if seg.sequence == sequence {
+			s.sessions.AddBytes(v.sessionID, int64(len(seg.data)))
+			return seg.data, nil
+		}
+	}
+
+	return nil, core.ErrHLSSegmentNotFound
+}
+
+// join returns the viewer of a session or registers a new stream
+// session, if the viewer is authorized and the stream limits allow it.
+// Known viewers are not authorized again.
+func (s *service) join(
+	ctx context.Context,
+	session core.StreamSession,
+	authorize func() error,
+) (*viewer, error) {
+	key := viewerKey(session)
+
+	if v, err := s.lookupViewer(key); v != nil || err != nil {
+		return v, err
+	}
+
+	s.joinMu.Lock()
+	defer s.joinMu.Unlock()
+
+	if v, err := s.lookupViewer(key); v != nil || err != nil {
+		return v, err
+	}
+
+	if err := authorize(); err != nil {
+		return nil, err
+	}
+
+	if session.Profile == "" {
+		session.Profile = defaultProfile
+	}
+
+	// The session outlives the request of the playlist.
+	id, sessionCtx, err := s.limiter.StartChannelStream(context.WithoutCancel(ctx), session)
+	if err != nil {
+		return nil, err
+	}
+
+	// A terminated session is removed right away, but the viewer
+	// is kept to reject its requests until it is idle.
+	context.AfterFunc(sessionCtx, func() {
+		s.sessions.Stop(id)
+	})
+
+	v := &viewer{
+		sessionID:  id,
+		ctx:        sessionCtx,
+		lastAccess: s.clock.Now(),
+	}
+
+	s.mu.Lock()
+	s.viewers[key] = v
+	s.mu.Unlock()
+
+	log.Debug().Str("session", id).Str("stream", streamKey(session.ChannelNumber, session.Profile)).
+		Msg("registered hls viewer session")
+
+	return v, nil
+}
+
+// lookupViewer returns a registered viewer and refreshes its last access.
+func (s *service) lookupViewer(key string) (*viewer, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	v, ok := s.viewers[key]
+	if !ok {
+		return nil, nil
+	}
+
+	v.lastAccess = s.clock.Now()
+
+	if v.ctx.Err() != nil {
+		return nil, core.ErrStreamSessionTerminated
+	}
+
+	return v, nil
+}
+
+// getOrStart returns the running stream of a channel
+// and profile or starts a new one.
+func (s *service) getOrStart(channelNumber int64, profile string) *liveStream {
+	if profile == "" {
+		profile = defaultProfile
+	}
+
+	key := streamKey(channelNumber, profile)
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if stream, ok := s.streams[key]; ok {
+		return stream
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	stream := &liveStream{
+		key:        key,
+		cancel:     cancel,
+		lastAccess: s.clock.Now(),
+		ready:      make(chan struct{}),
+	}
+	s.streams[key] = stream
+
+	log.Debug().Str("stream", key).Msg("starting hls stream")
+
+	go s.run(ctx, stream, channelNumber, profile)
+
+	return stream
+}
+
+// run reads the upstream and segments it until the stream fails or is cancelled.
+func (s *service) run(ctx context.Context, stream *liveStream, channelNumber int64, profile string) {
+	err := s.segment(ctx, stream, channelNumber, profile)
+	if err == nil {
+		err = fmt.Errorf("hls stream %s ended", stream.key)
+	}
+
+	if ctx.Err() == nil {
+		log.Error().Str("stream", stream.key).
+			Err(err).Msg("hls stream failed")
+	}
+
+	stream.mu.Lock()
+	stream.err = err
+	stream.mu.Unlock()
+	stream.markReady()
+
+	s.mu.Lock()
+	if s.streams[stream.key] == stream {
+		delete(s.streams, stream.key)
+	}
+	s.mu.Unlock()
+
+	stream.cancel()
+}
+
+func (s *service) segment(ctx context.Context, stream *liveStream, channelNumber int64, profile string) error {
+	res, err := s.streaming.GetChannelStream(ctx, channelNumber, profile)
+	if err != nil {
+		return err
+	}
+	defer res.Body.Close()
+
+	// Keep some segments behind the window for slow viewers.
+	retain := s.cfg.PlaylistSize * 2
+
+	seg := newSegmenter(s.cfg.SegmentDuration, func(seg segment) {
+		stream.mu.Lock()
+		stream.segments = append(stream.segments, seg)
+		if len(stream.segments) > retain {
+			stream.segments = stream.segments[len(stream.segments)-retain:]
+		}
+		stream.mu.Unlock()
+
+		stream.markReady()
+	})
+
+	return seg.run(res.Body)
+}
+
+func (l *liveStream) markReady() {
+	l.readyOnce.Do(func() {
+		close(l.ready)
+	})
+}
+
+func streamKey(channelNumber int64, profile string) string {
+	if profile == "" {
+		profile = defaultProfile
+	}
+
+	return fmt.Sprintf("%d/%s", channelNumber, profile)
+}
+
+// viewerKey identifies a viewer by the user, the stream and the client.
+func viewerKey(session core.StreamSession) string {
+	return fmt.Sprintf("%d|%s|%s|%s",
+		session.UserID,
+		streamKey(session.ChannelNumber, session.Profile),
+		session.ClientIP,
+		session.UserAgent,
+	)
+}
+
+// renderPlaylist renders a live playlist of the segments.
+func renderPlaylist(segments []segment, query string) []byte {
+	var target float64
+	for _, seg := range segments {
+		target = math.Max(target, math.Ceil(seg.duration.Seconds()))
+	}
+
+	var sequence int64
+	if len(segments) > 0 {
+		sequence = segments[0].sequence
+	}
+
+	var b strings.Builder
+	b.WriteString("#EXTM3U\n")
+	b.WriteString("#EXT-X-VERSION:3\n")
+	fmt.Fprintf(&b, "#EXT-X-TARGETDURATION:%d\n", int64(target))
+	fmt.Fprintf(&b, "#EXT-X-MEDIA-SEQUENCE:%d\n", sequence)
+
+	for _, seg := range segments {
+		fmt.Fprintf(&b, "#EXTINF:%.3f,\n", seg.duration.Seconds())
+		fmt.Fprintf(&b, "%d.ts", seg.sequence)
+		if query != "" {
+			b.WriteString("?" + query)
+		}
+		b.WriteString("\n")
+	}
+
+	return []byte(b.String())
+}
@@ -0,0 +1,393 @@
+package hls
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"io"
+	"net/http"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/davidborzek/tvhgo/config"
+	"github.com/davidborzek/tvhgo/core"
+	mock_core "github.com/davidborzek/tvhgo/mock/core"
+	"github.com/davidborzek/tvhgo/services/streaming"
+	"github.com/stretchr/testify/assert"
+	"go.uber.org/mock/gomock"
+)
+
+var (
+	ctx = context.TODO()
+
+	now = time.Unix(1700000000, 0)
+)
+
+type testClock struct {
+	mu  sync.Mutex
+	now time.Time
+}
+
+func (c *testClock) Now() time.Time {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return c.now
+}
+
+func (c *testClock) Advance(d time.Duration) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.now = c.now.Add(d)
+}
+
+func newTestConfig() *config.HLSConfig {
+	return &config.HLSConfig{
+		SegmentDuration: time.Second,
+		PlaylistSize:    2,
+		IdleTimeout:     30 * time.Second,
+		StartTimeout:    5 * time.Second,
+	}
+}
+
+// newUpstream returns a channel stream response which emits
+// the given number of one second segments and stays open
+// until the request context is cancelled.
+func newUpstream(ctx context.Context, segments int) *http.Response {
+	pr, pw := io.Pipe()
+
+	go func() {
+		packets := [][]byte{
+			patPacket(),
+			pmtPacket([2]int{streamTypeH264, testVideoPID}),
+		}
+		for i := 0; i <= segments; i++ {
+			packets = append(packets, h264Frame(time.Duration(i)*time.Second, true))
+		}
+
+		pw.Write(bytes.Join(packets, nil))
+
+		<-ctx.Done()
+		pw.CloseWithError(ctx.Err())
+	}()
+
+	return &http.Response{StatusCode: 200, Body: pr}
+}
+
+// newTestService creates a service, which registers viewers in a new session registry.
+func newTestService(
+	cfg *config.HLSConfig,
+	streamingConfig *config.StreamingConfig,
+	mockStreaming core.StreamingService,
+	clock core.Clock,
+) (*service, core.StreamSessionRegistry) {
+	sessions := streaming.NewSessionRegistry(clock)
+	limiter := streaming.NewLimiter(streamingConfig, sessions, mockStreaming, nil, nil, clock)
+
+	return New(cfg, mockStreaming, limiter, sessions, clock), sessions
+}
+
+// newViewer returns the stream session of a viewer of channel 1.
+func newViewer(profile string) core.StreamSession {
+	return core.StreamSession{
+		UserID:        1,
+		ChannelNumber: 1,
+		Profile:       profile,
+		ClientIP:      "192.168.1.10",
+		UserAgent:     "someAgent",
+	}
+}
+
+// allow authorizes every viewer.
+func allow() error {
+	return nil
+}
+
+func expectUpstream(mockStreaming *mock_core.MockStreamingService, segments int) *gomock.Call {
+	return mockStreaming.EXPECT().
+		GetChannelStream(gomock.Any(), int64(1), "pass").
+		DoAndReturn(func(ctx context.Context, _ int64, _ string) (*http.Response, error) {
+			return newUpstream(ctx, segments), nil
+		})
+}
+
+func TestGetPlaylistSharesUpstreamAndRendersSlidingWindow(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockStreaming := mock_core.NewMockStreamingService(ctrl)
+	expectUpstream(mockStreaming, 3).Times(1)
+
+	service, _ := newTestService(newTestConfig(), &config.StreamingConfig{}, mockStreaming, &testClock{now: now})
+
+	expected := "#EXTM3U\n" +
+		"#EXT-X-VERSION:3\n" +
+		"#EXT-X-TARGETDURATION:1\n" +
+		"#EXT-X-MEDIA-SEQUENCE:1\n" +
+		"#EXTINF:1.000,\n" +
+		"1.ts?sig=abc\n" +
+		"#EXTINF:1.000,\n" +
+		"2.ts?sig=abc\n"
+
+	assert.Eventually(t, func() bool {
+		playlist, err := service.GetPlaylist(ctx, newViewer(""), "sig=abc", allow)
+		return err == nil && string(playlist) == expected
+	}, time.Second, 10*time.Millisecond)
+
+	playlist, err := service.GetPlaylist(ctx, newViewer("pass"), "sig=abc", allow)
+	assert.Nil(t, err)
+	assert.Equal(t, expected, string(playlist))
+
+	segment, err := service.GetSegment(ctx, newViewer(""), 0)
+	assert.Nil(t, err)
+	assert.Equal(t, patPacket(), segment[:tsPacketSize])
+
+	_, err = service.GetSegment(ctx, newViewer("pass"), 5)
+	assert.Equal(t, core.ErrHLSSegmentNotFound, err)
+
+	other := newViewer("pass")
+	other.ChannelNumber = 2
+
+	_, err = service.GetSegment(ctx, other, 0)
+	assert.Equal(t, core.ErrHLSStreamNotFound, err)
+}
+
+func TestRunCleanupStopsIdleStreams(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	clock := &testClock{now: now}
+
+	var upstreamCtx context.Context
+	mockStreaming := mock_core.NewMockStreamingService(ctrl)
+	expectUpstream(mockStreaming, 1).
+		Do(func(ctx context.Context, _ int64, _ string) {
+			upstreamCtx = ctx
+		}).
+		Times(2)
+
+	service, _ := newTestService(newTestConfig(), &config.StreamingConfig{}, mockStreaming, clock)
+
+	_, err := service.GetPlaylist(ctx, newViewer(""), "", allow)
+	assert.Nil(t, err)
+
+	clock.Advance(20 * time.Second)
+	service.RunCleanup()
+
+	_, err = service.GetSegment(ctx, newViewer(""), 0)
+	assert.Nil(t, err)
+
+	clock.Advance(31 * time.Second)
+	service.RunCleanup()
+
+	_, err = service.GetSegment(ctx, newViewer(""), 0)
+	assert.Equal(t, core.ErrHLSStreamNotFound, err)
+	assert.Eventually(t, func() bool {
+		return upstreamCtx.Err() != nil
+	}, time.Second, 10*time.Millisecond)
+
+	_, err = service.GetPlaylist(ctx, newViewer(""), "", allow)
+	assert.Nil(t, err)
+}
+
+func TestGetPlaylistReturnsUpstreamError(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockStreaming := mock_core.NewMockStreamingService(ctrl)
+	mockStreaming.EXPECT().
+		GetChannelStream(gomock.Any(), int64(1), "webtv").
+		Return(nil, errors.New("error"))
+
+	service, _ := newTestService(newTestConfig(), &config.StreamingConfig{}, mockStreaming, &testClock{now: now})
+
+	_, err := service.GetPlaylist(ctx, newViewer("webtv"), "", allow)
+	assert.EqualError(t, err, "error")
+
+	_, err = service.GetSegment(ctx, newViewer("webtv"), 0)
+	assert.Equal(t, core.ErrHLSStreamNotFound, err)
+}
+
+func TestGetPlaylistTimesOutWithoutSegments(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockStreaming := mock_core.NewMockStreamingService(ctrl)
+	expectUpstream(mockStreaming, 0)
+
+	cfg := newTestConfig()
+	cfg.StartTimeout = 50 * time.Millisecond
+
+	service, _ := newTestService(cfg, &config.StreamingConfig{}, mockStreaming, &testClock{now: now})
+	defer func() {
+		service.clock.(*testClock).Advance(time.Hour)
+		service.RunCleanup()
+	}()
+
+	_, err := service.GetPlaylist(ctx, newViewer(""), "", allow)
+	assert.Equal(t, core.ErrHLSStreamTimeout, err)
+}
+
+func TestGetPlaylistRegistersViewerSession(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	clock := &testClock{now: now}
+
+	mockStreaming := mock_core.NewMockStreamingService(ctrl)
+	expectUpstream(mockStreaming, 1).Times(1)
+
+	service, sessions := newTestService(newTestConfig(), &config.StreamingConfig{}, mockStreaming, clock)
+	defer service.Stop()
+
+	_, err := service.GetPlaylist(ctx, newViewer(""), "", allow)
+	assert.Nil(t, err)
+
+	_, err = service.GetPlaylist(ctx, newViewer("pass"), "", allow)
+	assert.Nil(t, err)
+
+	segment, err := service.GetSegment(ctx, newViewer(""), 0)
+	assert.Nil(t, err)
+
+	list := sessions.List()
+	assert.Len(t, list, 1)
+	assert.Equal(t, core.StreamTypeChannel, list[0].Type)
+	assert.Equal(t, int64(1), list[0].ChannelNumber)
+	assert.Equal(t, "pass", list[0].Profile)
+	assert.Equal(t, "192.168.1.10", list[0].ClientIP)
+	assert.Equal(t, int64(len(segment)), list[0].BytesTransferred)
+
+	// The session ends when the viewer is idle.
+	clock.Advance(31 * time.Second)
+	service.RunCleanup()
+
+	assert.Empty(t, sessions.List())
+}
+
+func TestGetPlaylistAuthorizesNewViewersOnce(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockStreaming := mock_core.NewMockStreamingService(ctrl)
+	expectUpstream(mockStreaming, 1).Times(1)
+
+	service, sessions := newTestService(newTestConfig(), &config.StreamingConfig{}, mockStreaming, &testClock{now: now})
+	defer service.Stop()
+
+	authorized := 0
+	authorize := func() error {
+		authorized++
+		return nil
+	}
+
+	_, err := service.GetPlaylist(ctx, newViewer(""), "", authorize)
+	assert.Nil(t, err)
+
+	_, err = service.GetPlaylist(ctx, newViewer(""), "", authorize)
+	assert.Nil(t, err)
+
+	_, err = service.GetSegment(ctx, newViewer(""), 0)
+	assert.Nil(t, err)
+
+	assert.Equal(t, 1, authorized)
+	assert.Len(t, sessions.List(), 1)
+}
+
+func TestGetPlaylistRejectsUnauthorizedViewer(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockStreaming := mock_core.NewMockStreamingService(ctrl)
+
+	service, sessions := newTestService(newTestConfig(), &config.StreamingConfig{}, mockStreaming, &testClock{now: now})
+	defer service.Stop()
+
+	_, err := service.GetPlaylist(ctx, newViewer(""), "", func() error {
+		return core.ErrContentRestricted
+	})
+	assert.Equal(t, core.ErrContentRestricted, err)
+
+	_, err = service.GetSegment(ctx, newViewer(""), 0)
+	assert.Equal(t, core.ErrHLSStreamNotFound, err)
+
+	assert.Empty(t, sessions.List())
+}
+
+func TestGetPlaylistEnforcesStreamLimits(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockStreaming := mock_core.NewMockStreamingService(ctrl)
+	expectUpstream(mockStreaming, 1).Times(1)
+
+	service, sessions := newTestService(newTestConfig(), &config.StreamingConfig{
+		MaxStreamsPerUser: 1,
+	}, mockStreaming, &testClock{now: now})
+	defer service.Stop()
+
+	_, err := service.GetPlaylist(ctx, newViewer(""), "", allow)
+	assert.Nil(t, err)
+
+	other := newViewer("")
+	other.UserAgent = "otherAgent"
+
+	_, err = service.GetPlaylist(ctx, other, "", allow)
+	assert.Equal(t, core.ErrStreamLimitPerUserReached, err)
+
+	_, err = service.GetSegment(ctx, other, 0)
+	assert.Equal(t, core.ErrHLSStreamNotFound, err)
+
+	assert.Len(t, sessions.List(), 1)
+}
+
+func TestGetPlaylistRejectsTerminatedViewer(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockStreaming := mock_core.NewMockStreamingService(ctrl)
+	expectUpstream(mockStreaming, 1).Times(1)
+
+	service, sessions := newTestService(newTestConfig(), &config.StreamingConfig{}, mockStreaming, &testClock{now: now})
+	defer service.Stop()
+
+	_, err := service.GetPlaylist(ctx, newViewer(""), "", allow)
+	assert.Nil(t, err)
+
+	assert.Nil(t, sessions.Terminate(sessions.List()[0].ID))
+
+	_, err = service.GetPlaylist(ctx, newViewer(""), "", allow)
+	assert.Equal(t, core.ErrStreamSessionTerminated, err)
+
+	_, err = service.GetSegment(ctx, newViewer(""), 0)
+	assert.Equal(t, core.ErrStreamSessionTerminated, err)
+
+	assert.Eventually(t, func() bool {
+		return len(sessions.List()) == 0
+	}, time.Second, 10*time.Millisecond)
+}
+
+func TestStopStopsStreamsAndViewerSessions(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	var upstreamCtx context.Context
+	mockStreaming := mock_core.NewMockStreamingService(ctrl)
+	expectUpstream(mockStreaming, 1).
+		Do(func(ctx context.Context, _ int64, _ string) {
+			upstreamCtx = ctx
+		}).
+		Times(1)
+
+	service, sessions := newTestService(newTestConfig(), &config.StreamingConfig{}, mockStreaming, &testClock{now: now})
+	service.Start()
+
+	_, err := service.GetPlaylist(ctx, newViewer(""), "", allow)
+	assert.Nil(t, err)
+
+	service.Stop()
+
+	assert.Empty(t, sessions.List())
+	assert.Eventually(t, func() bool {
+		return upstreamCtx.Err() != nil
+	}, time.Second, 10*time.Millisecond)
+}
@@ -0,0 +1,334 @@
+package hls
+
+import (
+	"bufio"
+	"bytes"
+	"errors"
+	"io"
+	"time"
+)
+
+const (
+	tsPacketSize = 188
+	tsSyncByte   = 0x47
+
+	patPID = 0x0000
+
+	// ptsClock is the frequency of the mpeg-ts presentation timestamps.
+	ptsClock = 90000
+	// ptsMask masks the 33 bit presentation timestamps.
+	ptsMask = 1<<33 - 1
+
+	// maxSegmentSize bounds the size of a segment when no cut point is found.
+	maxSegmentSize = 64 << 20
+)
+
+var errSegmentTooLarge = errors.New("segment exceeds the maximum size")
+
+// Stream types of the program map table.
+const (
+	streamTypeMPEG1Video = 0x01
+	streamTypeMPEG2Video = 0x02
+	streamTypeH264       = 0x1b
+	streamTypeHEVC       = 0x24
+)
+
+type (
+	// segment is a part of the stream which starts at a keyframe
+	// and can be decoded on its own.
+	segment struct {
+		sequence int64
+		duration time.Duration
+		data     []byte
+	}
+
+	// segmenter cuts a mpeg-ts stream into segments. A segment is cut at
+	// the first keyframe after the target duration. Streams without video
+	// are cut at the first audio packet after the target duration.
+	segmenter struct {
+		target    int64
+		onSegment func(segment)
+
+		pmtPID   int
+		timePID  int
+		timeType byte
+
+		pat []byte
+		pmt []byte
+
+		buf      bytes.Buffer
+		started  bool
+		startPTS int64
+		sequence int64
+	}
+)
+
+// newSegmenter creates a new segmenter which calls onSegment for every complete segment.
+func newSegmenter(target time.Duration, onSegment func(segment)) *segmenter {
+	return &segmenter{
+		target:    int64(target.Seconds() * ptsClock),
+		onSegment: onSegment,
+		pmtPID:    -1,
+		timePID:   -1,
+	}
+}
+
+// run reads mpeg-ts packets from r until r is exhausted or fails.
+func (s *segmenter) run(r io.Reader) error {
+	br := bufio.NewReaderSize(r, 64*tsPacketSize)
+	pkt := make([]byte, tsPacketSize)
+
+	for {
+		if err := readPacket(br, pkt); err != nil {
+			if errors.Is(err, io.EOF) || errors.Is(err, io.ErrUnexpectedEOF) {
+				return nil
+			}
+			return err
+		}
+
+		if err := s.write(pkt); err != nil {
+			return err
+		}
+	}
+}
+
+// readPacket reads the next packet into pkt and resynchronizes
+// on the sync byte when the stream is corrupted.
+func readPacket(r *bufio.Reader, pkt []byte) error {
+	for {
+		b, err := r.ReadByte()
+		if err != nil {
+			return err
+		}
+
+		if b == tsSyncByte {
+			break
+		}
+	}
+
+	pkt[0] = tsSyncByte
+	_, err := io.ReadFull(r, pkt[1:])
+	return err
+}
+
+func (s *segmenter) write(pkt []byte) error {
+	pid := int(pkt[1]&0x1f)<<8 | int(pkt[2])
+	unitStart := pkt[1]&0x40 != 0
+
+	payload, randomAccess := packetPayload(pkt)
+
+	switch {
+	case pid == patPID && unitStart:
+		s.parsePAT(payload)
+		s.pat = append(s.pat[:0], pkt...)
+	case pid == s.pmtPID && unitStart:
+		s.parsePMT(payload)
+		s.pmt = append(s.pmt[:0], pkt...)
+	case pid == s.timePID && unitStart:
+		if pts, data, ok := parsePES(payload); ok {
+			cut := !isVideo(s.timeType) || randomAccess || containsKeyframe(data, s.timeType)
+			if cut {
+				s.cut(pts)
+			}
+		}
+	}
+
+	if !s.started {
+		return nil
+	}
+
+	if s.buf.Len()+len(pkt) > maxSegmentSize {
+		return errSegmentTooLarge
+	}
+
+	s.buf.Write(pkt)
+	return nil
+}
+
+// cut starts a new segment at a cut point with the given pts, when
+// the current segment reached the target duration.
+func (s *segmenter) cut(pts int64) {
+	if s.pat == nil || s.pmt == nil {
+		return
+	}
+
+	if s.started {
+		duration := (pts - s.startPTS) & ptsMask
+		if duration < s.target {
+			return
+		}
+
+		s.emit(duration)
+	}
+
+	s.started = true
+	s.startPTS = pts
+
+	// Every segment starts with the program tables to be decodable on its own.
+	s.buf.Write(s.pat)
+	s.buf.Write(s.pmt)
+}
+
+func (s *segmenter) emit(duration int64) {
+	data := make([]byte, s.buf.Len())
+	copy(data, s.buf.Bytes())
+	s.buf.Reset()
+
+	s.onSegment(segment{
+		sequence: s.sequence,
+		duration: time.Duration(duration) * time.Second / ptsClock,
+		data:     data,
+	})
+
+	s.sequence++
+}
+
+// packetPayload returns the payload of a packet and whether
+// the random access indicator of the adaptation field is set.
+func packetPayload(pkt []byte) ([]byte, bool) {
+	control := (pkt[3] >> 4) & 0x03
+	offset := 4
+	randomAccess := false
+
+	if control&0x02 != 0 {
+		length := int(pkt[4])
+		if length > 0 {
+			randomAccess = pkt[5]&0x40 != 0
+		}
+		offset += 1 + length
+	}
+
+	if control&0x01 == 0 || offset >= len(pkt) {
+		return nil, randomAccess
+	}
+
+	return pkt[offset:], randomAccess
+}
+
+// psiSection returns the section of a psi payload without the crc.
+func psiSection(payload []byte) []byte {
+	if len(payload) < 1 {
+		return nil
+	}
+
+	pointer := int(payload[0])
+	if 1+pointer+3 > len(payload) {
+		return nil
+	}
+
+	section := payload[1+pointer:]
+	length := int(section[1]&0x0f)<<8 | int(section[2])
+	if length < 4 || 3+length > len(section) {
+		return nil
+	}
+
+	return section[:3+length-4]
+}
+
+// parsePAT picks the pmt pid of the first program.
+func (s *segmenter) parsePAT(payload []byte) {
+	section := psiSection(payload)
+	if len(section) < 8 || section[0] != 0x00 {
+		return
+	}
+
+	for i := 8; i+4 <= len(section); i += 4 {
+		program := int(section[i])<<8 | int(section[i+1])
+		if program == 0 {
+			continue
+		}
+
+		s.pmtPID = int(section[i+2]&0x1f)<<8 | int(section[i+3])
+		return
+	}
+}
+
+// parsePMT picks the pid used for timing and cutting the segments.
+// The video stream is preferred, otherwise the first stream is used.
+func (s *segmenter) parsePMT(payload []byte) {
+	section := psiSection(payload)
+	if len(section) < 12 || section[0] != 0x02 {
+		return
+	}
+
+	infoLength := int(section[10]&0x0f)<<8 | int(section[11])
+
+	pid, streamType := -1, byte(0)
+	for i := 12 + infoLength; i+5 <= len(section); {
+		t := section[i]
+		p := int(section[i+1]&0x1f)<<8 | int(section[i+2])
+		i += 5 + (int(section[i+3]&0x0f)<<8 | int(section[i+4]))
+
+		if isVideo(t) {
+			pid, streamType = p, t
+			break
+		}
+
+		if pid == -1 {
+			pid, streamType = p, t
+		}
+	}
+
+	s.timePID = pid
+	s.timeType = streamType
+}
+
+// parsePES returns the pts and the elementary stream data of a pes packet.
+func parsePES(payload []byte) (int64, []byte, bool) {
+	if len(payload) < 9 || payload[0] != 0 || payload[1] != 0 || payload[2] != 1 {
+		return 0, nil, false
+	}
+
+	headerLength := int(payload[8])
+	if payload[7]&0x80 == 0 || len(payload) < 14 || 9+headerLength > len(payload) {
+		return 0, nil, false
+	}
+
+	p := payload[9:14]
+	pts := int64(p[0]>>1&0x07)<<30 |
+		int64(p[1])<<22 |
+		int64(p[2]>>1)<<15 |
+		int64(p[3])<<7 |
+		int64(p[4]>>1)
+
+	return pts, payload[9+headerLength:], true
+}
+
+func isVideo(streamType byte) bool {
+	switch streamType {
+	case streamTypeMPEG1Video, streamTypeMPEG2Video, streamTypeH264, streamTypeHEVC:
+		return true
+	}
+	return false
+}
+
+// containsKeyframe checks the start of an elementary stream
+// for units which start a keyframe.
+func containsKeyframe(data []byte, streamType byte) bool {
+	for i := 0; i+3 < len(data); i++ {
+		if data[i] != 0 || data[i+1] != 0 || data[i+2] != 1 {
+			continue
+		}
+
+		b := data[i+3]
+		switch streamType {
+		case streamTypeH264:
+			// IDR slice or sequence parameter set
+			if t := b & 0x1f; t == 5 || t == 7 {
+				return true
+			}
+		case streamTypeHEVC:
+			// IRAP pictures or video / sequence parameter sets
+			if t := (b >> 1) & 0x3f; (t >= 16 && t <= 21) || t == 32 || t == 33 {
+				return true
+			}
+		case streamTypeMPEG1Video, streamTypeMPEG2Video:
+			// sequence header or group of pictures
+			if b == 0xb3 || b == 0xb8 {
+				return true
+			}
+		}
+	}
+
+	return false
+}
@@ -0,0 +1,223 @@
+package hls
+
+import (
+	"bytes"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+const (
+	testPMTPID   = 0x100
+	testVideoPID = 0x101
+	testAudioPID = 0x102
+)
+
+func newPacket(pid int, unitStart bool, payload []byte) []byte {
+	pkt := make([]byte, tsPacketSize)
+	pkt[0] = tsSyncByte
+	pkt[1] = byte(pid>>8) & 0x1f
+	if unitStart {
+		pkt[1] |= 0x40
+	}
+	pkt[2] = byte(pid)
+	pkt[3] = 0x10
+
+	n := copy(pkt[4:], payload)
+	for i := 4 + n; i < tsPacketSize; i++ {
+		pkt[i] = 0xff
+	}
+
+	return pkt
+}
+
+func newSection(tableID byte, body []byte) []byte {
+	length := len(body) + 4
+	section := []byte{0x00, tableID, 0xb0 | byte(length>>8), byte(length)}
+	section = append(section, body...)
+	return append(section, 0, 0, 0, 0)
+}
+
+func patPacket() []byte {
+	return newPacket(patPID, true, newSection(0x00, []byte{
+		0x00, 0x01, 0xc1, 0x00, 0x00,
+		0x00, 0x01, 0xe0 | testPMTPID>>8, testPMTPID & 0xff,
+	}))
+}
+
+func pmtPacket(streams ...[2]int) []byte {
+	body := []byte{0x00, 0x01, 0xc1, 0x00, 0x00, 0xe1, 0x01, 0xf0, 0x00}
+	for _, s := range streams {
+		body = append(body, byte(s[0]), 0xe0|byte(s[1]>>8), byte(s[1]), 0xf0, 0x00)
+	}
+
+	return newPacket(testPMTPID, true, newSection(0x02, body))
+}
+
+func pesPacket(pid int, pts time.Duration, data ...byte) []byte {
+	p := int64(pts.Seconds()*ptsClock) & ptsMask
+
+	payload := []byte{
+		0x00, 0x00, 0x01, 0xe0, 0x00, 0x00, 0x80, 0x80, 0x05,
+		byte(0x21 | (p>>29)&0x0e),
+		byte(p >> 22),
+		byte(0x01 | (p>>14)&0xfe),
+		byte(p >> 7),
+		byte(0x01 | (p<<1)&0xfe),
+	}
+
+	return newPacket(pid, true, append(payload, data...))
+}
+
+func h264Frame(pts time.Duration, keyframe bool) []byte {
+	if keyframe {
+		return pesPacket(testVideoPID, pts, 0x00, 0x00, 0x00, 0x01, 0x09, 0xf0, 0x00, 0x00, 0x01, 0x65)
+	}
+
+	return pesPacket(testVideoPID, pts, 0x00, 0x00, 0x00, 0x01, 0x09, 0xf0, 0x00, 0x00, 0x01, 0x41)
+}
+
+func runSegmenter(t *testing.T, target time.Duration, packets ...[]byte) []segment {
+	var segments []segment
+	s := newSegmenter(target, func(seg segment) {
+		segments = append(segments, seg)
+	})
+
+	assert.Nil(t, s.run(bytes.NewReader(bytes.Join(packets, nil))))
+
+	return segments
+}
+
+func TestSegmenterCutsAtKeyframesAfterTargetDuration(t *testing.T) {
+	segments := runSegmenter(t, 2*time.Second,
+		patPacket(),
+		pmtPacket([2]int{streamTypeH264, testVideoPID}, [2]int{0x0f, testAudioPID}),
+		h264Frame(0, true),
+		h264Frame(1*time.Second, false),
+		h264Frame(2*time.Second, false),
+		h264Frame(3*time.Second, true),
+		h264Frame(4*time.Second, false),
+		h264Frame(5*time.Second, true),
+		h264Frame(6*time.Second, true),
+	)
+
+	assert.Len(t, segments, 2)
+
+	assert.Equal(t, int64(0), segments[0].sequence)
+	assert.Equal(t, 3*time.Second, segments[0].duration)
+	assert.Len(t, segments[0].data, 5*tsPacketSize)
+
+	assert.Equal(t, int64(1), segments[1].sequence)
+	assert.Equal(t, 2*time.Second, segments[1].duration)
+	assert.Len(t, segments[1].data, 4*tsPacketSize)
+
+	for _, seg := range segments {
+		assert.Equal(t, patPacket(), seg.data[:tsPacketSize])
+		assert.Equal(t, testPMTPID, int(seg.data[tsPacketSize+1]&0x1f)<<8|int(seg.data[tsPacketSize+2]))
+	}
+}
+
+func TestSegmenterDropsPacketsBeforeFirstKeyframe(t *testing.T) {
+	segments := runSegmenter(t, time.Second,
+		h264Frame(0, true),
+		patPacket(),
+		pmtPacket([2]int{streamTypeH264, testVideoPID}),
+		h264Frame(1*time.Second, false),
+		h264Frame(2*time.Second, true),
+		h264Frame(3*time.Second, false),
+		h264Frame(4*time.Second, true),
+	)
+
+	assert.Len(t, segments, 1)
+	assert.Equal(t, 2*time.Second, segments[0].duration)
+	assert.Equal(t, h264Frame(2*time.Second, true), segments[0].data[2*tsPacketSize:3*tsPacketSize])
+}
+
+func TestSegmenterCutsAtRandomAccessIndicator(t *testing.T) {
+	keyframe := pesPacket(testVideoPID, 2*time.Second)
+	// Move the payload behind an adaptation field with the random access indicator.
+	keyframe = append(keyframe[:4], append([]byte{0x01, 0x40}, keyframe[4:tsPacketSize-2]...)...)
+	keyframe[3] = 0x30
+
+	segments := runSegmenter(t, time.Second,
+		patPacket(),
+		pmtPacket([2]int{streamTypeH264, testVideoPID}),
+		h264Frame(0, true),
+		keyframe,
+		h264Frame(3*time.Second, true),
+	)
+
+	assert.Len(t, segments, 2)
+	assert.Equal(t, 2*time.Second, segments[0].duration)
+	assert.Equal(t, 1*time.Second, segments[1].duration)
+}
+
+func TestSegmenterCutsAudioOnlyStreams(t *testing.T) {
+	segments := runSegmenter(t, 2*time.Second,
+		patPacket(),
+		pmtPacket([2]int{0x0f, testAudioPID}),
+		pesPacket(testAudioPID, 0),
+		pesPacket(testAudioPID, 1*time.Second),
+		pesPacket(testAudioPID, 2*time.Second),
+		pesPacket(testAudioPID, 3*time.Second),
+		pesPacket(testAudioPID, 4*time.Second),
+	)
+
+	assert.Len(t, segments, 2)
+	assert.Equal(t, 2*time.Second, segments[0].duration)
+	assert.Equal(t, 2*time.Second, segments[1].duration)
+}
+
+func TestSegmenterHandlesTimestampWraparound(t *testing.T) {
+	start := time.Duration(ptsMask) * time.Second / ptsClock
+
+	segments := runSegmenter(t, time.Second,
+		patPacket(),
+		pmtPacket([2]int{streamTypeH264, testVideoPID}),
+		h264Frame(start-time.Second, true),
+		h264Frame(start+time.Second, true),
+		h264Frame(start+2*time.Second, true),
+	)
+
+	assert.Len(t, segments, 2)
+	assert.InDelta(t, 2*time.Second, segments[0].duration, float64(time.Millisecond))
+}
+
+func TestSegmenterResynchronizesOnCorruptedStream(t *testing.T) {
+	segments := runSegmenter(t, time.Second,
+		patPacket(),
+		[]byte{0x00, 0x01, 0x02},
+		pmtPacket([2]int{streamTypeH264, testVideoPID}),
+		h264Frame(0, true),
+		h264Frame(1*time.Second, true),
+	)
+
+	assert.Len(t, segments, 1)
+	assert.Equal(t, time.Second, segments[0].duration)
+}
+
+func TestContainsKeyframe(t *testing.T) {
+	tests := []struct {
+		name       string
+		streamType byte
+		data       []byte
+		expected   bool
+	}{
+		{"h264 idr", streamTypeH264, []byte{0, 0, 1, 0x65}, true},
+		{"h264 sps", streamTypeH264, []byte{0, 0, 0, 1, 0x67}, true},
+		{"h264 non idr", streamTypeH264, []byte{0, 0, 1, 0x41}, false},
+		{"hevc idr", streamTypeHEVC, []byte{0, 0, 1, 19 << 1}, true},
+		{"hevc vps", streamTypeHEVC, []byte{0, 0, 1, 32 << 1}, true},
+		{"hevc trail", streamTypeHEVC, []byte{0, 0, 1, 1 << 1}, false},
+		{"mpeg2 sequence header", streamTypeMPEG2Video, []byte{0, 0, 1, 0xb3}, true},
+		{"mpeg2 picture", streamTypeMPEG2Video, []byte{0, 0, 1, 0x00}, false},
+		{"no start code", streamTypeH264, []byte{0x65, 0x65}, false},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			assert.Equal(t, tc.expected, containsKeyframe(tc.data, tc.streamType))
+		})
+	}
+}
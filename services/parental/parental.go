@@ -0,0 +1,148 @@
+package parental
+
+import (
+	"context"
+	"sync"
+	"time"
+
+	"github.com/davidborzek/tvhgo/core"
+	"github.com/rs/zerolog/log"
+)
+
+// channelLimit is the maximum number of channels loaded to resolve
+// the allowed channels, since tvheadend paginates channels by default.
+const channelLimit = 10000
+
+// filterCacheTTL is the time the resolved parental controls of a user
+// are cached, since they are checked on every stream and segment request.
+const filterCacheTTL = 30 * time.Second
+
+// cachedFilter holds the parental controls and channels a content
+// filter is built from. The controls are nil for unrestricted users.
+type cachedFilter struct {
+	controls  *core.ParentalControls
+	channels  []*core.Channel
+	expiresAt time.Time
+}
+
+type service struct {
+	repository core.ParentalControlsRepository
+	channels   core.ChannelService
+	clock      core.Clock
+
+	mu    sync.Mutex
+	cache map[int64]cachedFilter
+}
+
+func New(
+	repository core.ParentalControlsRepository,
+	channels core.ChannelService,
+	clock core.Clock,
+) core.ParentalControlsService {
+	return &service{
+		repository: repository,
+		channels:   channels,
+		clock:      clock,
+		cache:      make(map[int64]cachedFilter),
+	}
+}
+
+func (s *service) Get(ctx context.Context, userID int64) (*core.ParentalControls, error) {
+	controls, err := s.repository.Find(ctx, userID)
+	if err != nil {
+		log.Error().Err(err).Int64("user", userID).
+			Msg("could not get parental controls")
+
+		return nil, core.ErrUnexpectedError
+	}
+
+	return controls, nil
+}
+
+func (s *service) Save(ctx context.Context, controls *core.ParentalControls) error {
+	if err := controls.Validate(); err != nil {
+		return err
+	}
+
+	if err := s.repository.Save(ctx, controls); err != nil {
+		log.Error().Err(err).Int64("user", controls.UserID).
+			Msg("could not save parental controls")
+
+		return core.ErrUnexpectedError
+	}
+
+	s.invalidate(controls.UserID)
+	return nil
+}
+
+func (s *service) Delete(ctx context.Context, userID int64) error {
+	if err := s.repository.Delete(ctx, userID); err != nil {
+		log.Error().Err(err).Int64("user", userID).
+			Msg("could not delete parental controls")
+
+		return core.ErrUnexpectedError
+	}
+
+	s.invalidate(userID)
+	return nil
+}
+
+func (s *service) Filter(ctx context.Context, userID int64) (*core.ContentFilter, error) {
+	now := s.clock.Now()
+
+	s.mu.Lock()
+	cached, ok := s.cache[userID]
+	s.mu.Unlock()
+
+	if !ok || !now.Before(cached.expiresAt) {
+		var err error
+		cached, err = s.resolve(ctx, userID)
+		if err != nil {
+			return nil, err
+		}
+
+		cached.expiresAt = now.Add(filterCacheTTL)
+
+		s.mu.Lock()
+		s.cache[userID] = cached
+		s.mu.Unlock()
+	}
+
+	if cached.controls == nil {
+		return nil, nil
+	}
+
+	// The filter is built on every call, so that the viewing time
+	// is checked against the current time.
+	return core.NewContentFilter(cached.controls, cached.channels, now.Local()), nil
+}
+
+// resolve loads the parental controls of a user and the channels
+// to resolve the allowed channels from.
+func (s *service) resolve(ctx context.Context, userID int64) (cachedFilter, error) {
+	controls, err := s.Get(ctx, userID)
+	if err != nil || controls == nil {
+		return cachedFilter{}, err
+	}
+
+	params := core.PaginationSortQueryParams{}
+	params.Limit = channelLimit
+
+	channels, err := s.channels.GetAll(ctx, params)
+	if err != nil {
+		log.Error().Err(err).Int64("user", userID).
+			Msg("could not get channels for parental controls")
+
+		return cachedFilter{}, core.ErrUnexpectedError
+	}
+
+	return cachedFilter{controls: controls, channels: channels}, nil
+}
+
+// invalidate removes the cached parental controls of a user.
+func (s *service) invalidate(userID int64) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	delete(s.cache, userID)
+}
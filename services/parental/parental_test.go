@@ -0,0 +1,320 @@
+package parental_test
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/davidborzek/tvhgo/core"
+	mock_core "github.com/davidborzek/tvhgo/mock/core"
+	"github.com/davidborzek/tvhgo/services/parental"
+	"github.com/stretchr/testify/assert"
+	"go.uber.org/mock/gomock"
+)
+
+const userID = int64(1)
+
+var (
+	ctx = context.TODO()
+	now = time.Date(2023, 1, 1, 12, 0, 0, 0, time.Local)
+)
+
+func TestFilterReturnsNilWithoutParentalControls(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockRepository := mock_core.NewMockParentalControlsRepository(ctrl)
+	mockRepository.EXPECT().
+		Find(ctx, userID).
+		Return(nil, nil)
+
+	mockClock := mock_core.NewMockClock(ctrl)
+	mockClock.EXPECT().
+		Now().
+		Return(now)
+
+	s := parental.New(mockRepository, mock_core.NewMockChannelService(ctrl), mockClock)
+
+	filter, err := s.Filter(ctx, userID)
+
+	assert.Nil(t, err)
+	assert.Nil(t, filter)
+}
+
+func TestFilterResolvesAllowedChannels(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockRepository := mock_core.NewMockParentalControlsRepository(ctrl)
+	mockRepository.EXPECT().
+		Find(ctx, userID).
+		Return(&core.ParentalControls{
+			UserID:      userID,
+			ChannelTags: []string{"kidsTag"},
+			ViewingFrom: "06:00",
+			ViewingTo:   "20:00",
+		}, nil)
+
+	mockChannels := mock_core.NewMockChannelService(ctrl)
+	mockChannels.EXPECT().
+		GetAll(ctx, gomock.Any()).
+		Return([]*core.Channel{
+			{ID: "kids", Number: 1, Tags: []string{"kidsTag"}},
+			{ID: "news", Number: 2},
+		}, nil)
+
+	mockClock := mock_core.NewMockClock(ctrl)
+	mockClock.EXPECT().
+		Now().
+		Return(time.Date(2023, 1, 1, 22, 0, 0, 0, time.Local))
+
+	s := parental.New(mockRepository, mockChannels, mockClock)
+
+	filter, err := s.Filter(ctx, userID)
+
+	assert.Nil(t, err)
+	assert.True(t, filter.AllowsChannel("kids"))
+	assert.False(t, filter.AllowsChannel("news"))
+	assert.False(t, filter.AllowsStreaming())
+}
+
+func TestFilterReturnsErrUnexpectedErrorWhenChannelsFail(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockRepository := mock_core.NewMockParentalControlsRepository(ctrl)
+	mockRepository.EXPECT().
+		Find(ctx, userID).
+		Return(&core.ParentalControls{UserID: userID}, nil)
+
+	mockChannels := mock_core.NewMockChannelService(ctrl)
+	mockChannels.EXPECT().
+		GetAll(ctx, gomock.Any()).
+		Return(nil, errors.New("some error"))
+
+	mockClock := mock_core.NewMockClock(ctrl)
+	mockClock.EXPECT().
+		Now().
+		Return(now)
+
+	s := parental.New(mockRepository, mockChannels, mockClock)
+
+	filter, err := s.Filter(ctx, userID)
+
+	assert.Equal(t, core.ErrUnexpectedError, err)
+	assert.Nil(t, filter)
+}
+
+func TestFilterCachesParentalControls(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockRepository := mock_core.NewMockParentalControlsRepository(ctrl)
+	mockRepository.EXPECT().
+		Find(ctx, userID).
+		Return(&core.ParentalControls{UserID: userID, ViewingFrom: "06:00", ViewingTo: "20:00"}, nil)
+
+	mockChannels := mock_core.NewMockChannelService(ctrl)
+	mockChannels.EXPECT().
+		GetAll(ctx, gomock.Any()).
+		Return([]*core.Channel{{ID: "kids", Number: 1}}, nil)
+
+	mockClock := mock_core.NewMockClock(ctrl)
+	gomock.InOrder(
+		mockClock.EXPECT().Now().Return(now),
+		mockClock.EXPECT().Now().Return(now.Add(29*time.Second)),
+	)
+
+	s := parental.New(mockRepository, mockChannels, mockClock)
+
+	filter, err := s.Filter(ctx, userID)
+	assert.Nil(t, err)
+	assert.True(t, filter.AllowsStreaming())
+
+	filter, err = s.Filter(ctx, userID)
+	assert.Nil(t, err)
+	assert.True(t, filter.AllowsChannel("kids"))
+}
+
+func TestFilterChecksViewingTimeOfCachedParentalControls(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockRepository := mock_core.NewMockParentalControlsRepository(ctrl)
+	mockRepository.EXPECT().
+		Find(ctx, userID).
+		Return(&core.ParentalControls{UserID: userID, ViewingFrom: "06:00", ViewingTo: "12:00"}, nil)
+
+	mockChannels := mock_core.NewMockChannelService(ctrl)
+	mockChannels.EXPECT().
+		GetAll(ctx, gomock.Any()).
+		Return([]*core.Channel{}, nil)
+
+	mockClock := mock_core.NewMockClock(ctrl)
+	gomock.InOrder(
+		mockClock.EXPECT().Now().Return(now.Add(-time.Second)),
+		mockClock.EXPECT().Now().Return(now),
+	)
+
+	s := parental.New(mockRepository, mockChannels, mockClock)
+
+	filter, err := s.Filter(ctx, userID)
+	assert.Nil(t, err)
+	assert.True(t, filter.AllowsStreaming())
+
+	filter, err = s.Filter(ctx, userID)
+	assert.Nil(t, err)
+	assert.False(t, filter.AllowsStreaming())
+}
+
+func TestFilterReloadsExpiredParentalControls(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockRepository := mock_core.NewMockParentalControlsRepository(ctrl)
+	gomock.InOrder(
+		mockRepository.EXPECT().
+			Find(ctx, userID).
+			Return(nil, nil),
+		mockRepository.EXPECT().
+			Find(ctx, userID).
+			Return(&core.ParentalControls{UserID: userID}, nil),
+	)
+
+	mockChannels := mock_core.NewMockChannelService(ctrl)
+	mockChannels.EXPECT().
+		GetAll(ctx, gomock.Any()).
+		Return([]*core.Channel{}, nil)
+
+	mockClock := mock_core.NewMockClock(ctrl)
+	gomock.InOrder(
+		mockClock.EXPECT().Now().Return(now),
+		mockClock.EXPECT().Now().Return(now.Add(30*time.Second)),
+	)
+
+	s := parental.New(mockRepository, mockChannels, mockClock)
+
+	filter, err := s.Filter(ctx, userID)
+	assert.Nil(t, err)
+	assert.Nil(t, filter)
+
+	filter, err = s.Filter(ctx, userID)
+	assert.Nil(t, err)
+	assert.NotNil(t, filter)
+}
+
+func TestSaveInvalidatesCachedParentalControls(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	controls := &core.ParentalControls{UserID: userID}
+
+	mockRepository := mock_core.NewMockParentalControlsRepository(ctrl)
+	gomock.InOrder(
+		mockRepository.EXPECT().
+			Find(ctx, userID).
+			Return(nil, nil),
+		mockRepository.EXPECT().
+			Save(ctx, controls).
+			Return(nil),
+		mockRepository.EXPECT().
+			Find(ctx, userID).
+			Return(controls, nil),
+	)
+
+	mockChannels := mock_core.NewMockChannelService(ctrl)
+	mockChannels.EXPECT().
+		GetAll(ctx, gomock.Any()).
+		Return([]*core.Channel{}, nil)
+
+	mockClock := mock_core.NewMockClock(ctrl)
+	mockClock.EXPECT().
+		Now().
+		Return(now).
+		Times(2)
+
+	s := parental.New(mockRepository, mockChannels, mockClock)
+
+	filter, err := s.Filter(ctx, userID)
+	assert.Nil(t, err)
+	assert.Nil(t, filter)
+
+	assert.Nil(t, s.Save(ctx, controls))
+
+	filter, err = s.Filter(ctx, userID)
+	assert.Nil(t, err)
+	assert.NotNil(t, filter)
+}
+
+func TestDeleteInvalidatesCachedParentalControls(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockRepository := mock_core.NewMockParentalControlsRepository(ctrl)
+	gomock.InOrder(
+		mockRepository.EXPECT().
+			Find(ctx, userID).
+			Return(&core.ParentalControls{UserID: userID}, nil),
+		mockRepository.EXPECT().
+			Delete(ctx, userID).
+			Return(nil),
+		mockRepository.EXPECT().
+			Find(ctx, userID).
+			Return(nil, nil),
+	)
+
+	mockChannels := mock_core.NewMockChannelService(ctrl)
+	mockChannels.EXPECT().
+		GetAll(ctx, gomock.Any()).
+		Return([]*core.Channel{}, nil)
+
+	mockClock := mock_core.NewMockClock(ctrl)
+	mockClock.EXPECT().
+		Now().
+		Return(now).
+		Times(2)
+
+	s := parental.New(mockRepository, mockChannels, mockClock)
+
+	filter, err := s.Filter(ctx, userID)
+	assert.Nil(t, err)
+	assert.NotNil(t, filter)
+
+	assert.Nil(t, s.Delete(ctx, userID))
+
+	filter, err = s.Filter(ctx, userID)
+	assert.Nil(t, err)
+	assert.Nil(t, filter)
+}
+
+func TestSaveValidatesParentalControls(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	s := parental.New(
+		mock_core.NewMockParentalControlsRepository(ctrl),
+		mock_core.NewMockChannelService(ctrl),
+		mock_core.NewMockClock(ctrl),
+	)
+
+	err := s.Save(ctx, &core.ParentalControls{UserID: userID, ViewingFrom: "25:00", ViewingTo: "06:00"})
+
+	assert.Equal(t, core.ErrParentalControlsInvalidViewingTime, err)
+}
+
+func TestSaveSucceeds(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	controls := &core.ParentalControls{UserID: userID, BlockedContentTypes: []int{16}}
+
+	mockRepository := mock_core.NewMockParentalControlsRepository(ctrl)
+	mockRepository.EXPECT().
+		Save(ctx, controls).
+		Return(nil)
+
+	s := parental.New(mockRepository, mock_core.NewMockChannelService(ctrl), mock_core.NewMockClock(ctrl))
+
+	assert.Nil(t, s.Save(ctx, controls))
+}
@@ -35,43 +35,46 @@ func New(tvh tvheadend.Client) core.RecordingService {
 	}
 }
 
-func (s *service) CreateByEvent(ctx context.Context, opts core.CreateRecordingByEvent) error {
+func (s *service) CreateByEvent(
+	ctx context.Context,
+	opts core.CreateRecordingByEvent,
+) ([]string, error) {
 	q := tvheadend.NewQuery()
 	q.SetInt("event_id", opts.EventID)
 	q.Set("config_uuid", opts.ConfigID)
 
-	res, err := s.tvh.Exec(ctx, "/api/dvr/entry/create_by_event", nil, q)
+	var created tvheadend.DvrRecordingsCreatedByEvent
+	res, err := s.tvh.Exec(ctx, "/api/dvr/entry/create_by_event", &created, q)
 	if err != nil {
-		return err
+		return nil, err
 	}
-	defer res.Body.Close()
 
 	if res.StatusCode >= 400 {
-		return ErrRequestFailed
+		return nil, ErrRequestFailed
 	}
 
-	return nil
+	return created.UUID, nil
 }
 
-func (s *service) Create(ctx context.Context, opts core.CreateRecording) error {
+func (s *service) Create(ctx context.Context, opts core.CreateRecording) (string, error) {
 	q := tvheadend.NewQuery()
 	conf := opts.MapToTvheadendOpts()
 
 	if err := q.Conf(&conf); err != nil {
-		return err
+		return "", err
 	}
 
 	var created tvheadend.DvrRecordingCreated
 	res, err := s.tvh.Exec(ctx, "/api/dvr/entry/create", &created, q)
 	if err != nil {
-		return err
+		return "", err
 	}
 
 	if res.StatusCode >= 400 {
-		return ErrRequestFailed
+		return "", ErrRequestFailed
 	}
 
-	return nil
+	return created.UUID, nil
 }
 
 func (s *service) GetAll(
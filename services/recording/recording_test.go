@@ -171,8 +171,9 @@ func TestCreateReturnsError(t *testing.T) {
 		Times(1)
 
 	service := recording.New(mockClient)
-	err := service.Create(ctx, core.CreateRecording{})
+	id, err := service.Create(ctx, core.CreateRecording{})
 
+	assert.Empty(t, id)
 	assert.EqualError(t, err, "error")
 }
 
@@ -187,8 +188,9 @@ func TestCreateRequestFailedError(t *testing.T) {
 		Times(1)
 
 	service := recording.New(mockClient)
-	err := service.Create(ctx, core.CreateRecording{})
+	id, err := service.Create(ctx, core.CreateRecording{})
 
+	assert.Empty(t, id)
 	assert.Equal(t, err, recording.ErrRequestFailed)
 }
 func TestCreateSucceeds(t *testing.T) {
@@ -213,17 +215,21 @@ func TestCreateSucceeds(t *testing.T) {
 	mockClient := mock_tvheadend.NewMockClient(ctrl)
 	mockClient.EXPECT().
 		Exec(ctx, "/api/dvr/entry/create", gomock.Any(), tvhq).
-		Return(&tvheadend.Response{
-			Response: &http.Response{
-				StatusCode: 200,
-				Body:       http.NoBody,
-			}}, nil).
+		DoAndReturn(func(_ context.Context, _ string, dst any, _ ...tvheadend.Query) (*tvheadend.Response, error) {
+			dst.(*tvheadend.DvrRecordingCreated).UUID = "someID"
+			return &tvheadend.Response{
+				Response: &http.Response{
+					StatusCode: 200,
+					Body:       http.NoBody,
+				}}, nil
+		}).
 		Times(1)
 
 	service := recording.New(mockClient)
-	err := service.Create(ctx, opts)
+	id, err := service.Create(ctx, opts)
 
 	assert.Nil(t, err)
+	assert.Equal(t, "someID", id)
 }
 
 func TestCreateByEventReturnsError(t *testing.T) {
@@ -237,8 +243,9 @@ func TestCreateByEventReturnsError(t *testing.T) {
 		Times(1)
 
 	service := recording.New(mockClient)
-	err := service.CreateByEvent(ctx, core.CreateRecordingByEvent{})
+	ids, err := service.CreateByEvent(ctx, core.CreateRecordingByEvent{})
 
+	assert.Nil(t, ids)
 	assert.EqualError(t, err, "error")
 }
 
@@ -253,8 +260,9 @@ func TestCreateByEventReturnsRequestFailedError(t *testing.T) {
 		Times(1)
 
 	service := recording.New(mockClient)
-	err := service.CreateByEvent(ctx, core.CreateRecordingByEvent{})
+	ids, err := service.CreateByEvent(ctx, core.CreateRecordingByEvent{})
 
+	assert.Nil(t, ids)
 	assert.Equal(t, err, recording.ErrRequestFailed)
 }
 
@@ -274,17 +282,21 @@ func TestCreateByEventSucceeds(t *testing.T) {
 	mockClient := mock_tvheadend.NewMockClient(ctrl)
 	mockClient.EXPECT().
 		Exec(ctx, "/api/dvr/entry/create_by_event", gomock.Any(), tvhq).
-		Return(&tvheadend.Response{
-			Response: &http.Response{
-				StatusCode: 200,
-				Body:       http.NoBody,
-			}}, nil).
+		DoAndReturn(func(_ context.Context, _ string, dst any, _ ...tvheadend.Query) (*tvheadend.Response, error) {
+			dst.(*tvheadend.DvrRecordingsCreatedByEvent).UUID = []string{"someID"}
+			return &tvheadend.Response{
+				Response: &http.Response{
+					StatusCode: 200,
+					Body:       http.NoBody,
+				}}, nil
+		}).
 		Times(1)
 
 	service := recording.New(mockClient)
-	err := service.CreateByEvent(ctx, opts)
+	ids, err := service.CreateByEvent(ctx, opts)
 
 	assert.Nil(t, err)
+	assert.Equal(t, []string{"someID"}, ids)
 }
 
 func TestGetAllReturnsError(t *testing.T) {
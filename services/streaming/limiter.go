@@ -0,0 +1,175 @@
+package streaming
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/davidborzek/tvhgo/config"
+	"github.com/davidborzek/tvhgo/core"
+	"github.com/davidborzek/tvhgo/tvheadend"
+	"github.com/rs/zerolog/log"
+)
+
+type limiter struct {
+	cfg        *config.StreamingConfig
+	sessions   core.StreamSessionRegistry
+	streaming  core.StreamingService
+	tvh        tvheadend.Client
+	recordings core.RecordingService
+	clock      core.Clock
+}
+
+// NewLimiter creates a new StreamLimiter which enforces the concurrent
+// stream limits and protects tuners needed for upcoming recordings.
+func NewLimiter(
+	cfg *config.StreamingConfig,
+	sessions core.StreamSessionRegistry,
+	streaming core.StreamingService,
+	tvh tvheadend.Client,
+	recordings core.RecordingService,
+	clock core.Clock,
+) core.StreamLimiter {
+	return &limiter{
+		cfg:        cfg,
+		sessions:   sessions,
+		streaming:  streaming,
+		tvh:        tvh,
+		recordings: recordings,
+		clock:      clock,
+	}
+}
+
+func (l *limiter) StartChannelStream(
+	ctx context.Context,
+	session core.StreamSession,
+) (string, context.Context, error) {
+	// The tuners are checked before the registration, since the
+	// requests to tvheadend must not block other streams.
+	if err := l.checkTuners(ctx, session); err != nil {
+		return "", nil, err
+	}
+
+	session.Type = core.StreamTypeChannel
+
+	return l.sessions.Reserve(ctx, session, func(active []*core.StreamSession) error {
+		return l.checkLimits(active, session.UserID)
+	})
+}
+
+// checkLimits checks the concurrent stream limits against the active sessions.
+func (l *limiter) checkLimits(active []*core.StreamSession, userID int64) error {
+	var total, user int
+	for _, s := range active {
+		if s.Type != core.StreamTypeChannel {
+			continue
+		}
+
+		total++
+		if s.UserID == userID {
+			user++
+		}
+	}
+
+	if l.cfg.MaxStreamsPerUser > 0 && user >= l.cfg.MaxStreamsPerUser {
+		return core.ErrStreamLimitPerUserReached
+	}
+
+	if l.cfg.MaxStreams > 0 && total >= l.cfg.MaxStreams {
+		return core.ErrStreamLimitReached
+	}
+
+	return nil
+}
+
+// checkTuners checks if a free tuner remains for upcoming recordings.
+func (l *limiter) checkTuners(ctx context.Context, session core.StreamSession) error {
+	if !l.cfg.TunerProtection.Enabled {
+		return nil
+	}
+
+	// Viewers joining a running upstream don't need another tuner.
+	if l.streaming.IsChannelStreamRunning(session.ChannelNumber, session.Profile) {
+		return nil
+	}
+
+	needed, err := l.neededTuners(ctx)
+	if err != nil {
+		return err
+	}
+
+	if needed == 0 {
+		return nil
+	}
+
+	free, err := l.freeTuners(ctx)
+	if err != nil {
+		return err
+	}
+
+	if free <= needed {
+		log.Debug().Int64("user", session.UserID).
+			Int("free", free).
+			Int("needed", needed).
+			Msg("refusing live stream to protect tuners for upcoming recordings")
+
+		return core.ErrTunersReserved
+	}
+
+	return nil
+}
+
+// freeTuners returns the number of tuners without active subscriptions.
+func (l *limiter) freeTuners(ctx context.Context) (int, error) {
+	var inputs tvheadend.Status[tvheadend.InputStatus]
+	res, err := l.tvh.Exec(ctx, "/api/status/inputs", &inputs)
+	if err != nil {
+		return 0, err
+	}
+
+	if res.StatusCode >= 400 {
+		return 0, fmt.Errorf("unexpected status code: %d", res.StatusCode)
+	}
+
+	tuners := l.cfg.TunerProtection.Tuners
+	if tuners == 0 {
+		tuners = len(inputs.Entries)
+	}
+
+	busy := 0
+	for _, input := range inputs.Entries {
+		if input.Subs > 0 {
+			busy++
+		}
+	}
+
+	return tuners - busy, nil
+}
+
+// neededTuners returns the number of upcoming recordings
+// starting within the configured lookahead.
+func (l *limiter) neededTuners(ctx context.Context) (int, error) {
+	recordings, err := l.recordings.GetAll(ctx, core.GetRecordingsParams{
+		Status: "upcoming",
+	})
+	if err != nil {
+		return 0, err
+	}
+
+	now := l.clock.Now()
+	until := now.Add(l.cfg.TunerProtection.Lookahead).Unix()
+
+	needed := 0
+	for _, r := range recordings.Entries {
+		// Running recordings already occupy a tuner.
+		if !r.Enabled || r.Status != "scheduled" {
+			continue
+		}
+
+		startsAt := r.StartsAt - int64(r.StartPadding*60)
+		if startsAt <= until && r.EndsAt > now.Unix() {
+			needed++
+		}
+	}
+
+	return needed, nil
+}
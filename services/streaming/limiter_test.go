@@ -0,0 +1,295 @@
+package streaming_test
+
+import (
+	"context"
+	"net/http"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/davidborzek/tvhgo/config"
+	"github.com/davidborzek/tvhgo/core"
+	mock_core "github.com/davidborzek/tvhgo/mock/core"
+	mock_tvheadend "github.com/davidborzek/tvhgo/mock/tvheadend"
+	"github.com/davidborzek/tvhgo/services/recording"
+	"github.com/davidborzek/tvhgo/services/streaming"
+	"github.com/davidborzek/tvhgo/tvheadend"
+	"github.com/stretchr/testify/assert"
+	"go.uber.org/mock/gomock"
+)
+
+func mockInputs(subs ...int) func(
+	ctx context.Context,
+	path string,
+	dst interface{},
+	query ...tvheadend.Query,
+) (*tvheadend.Response, error) {
+	return func(
+		ctx context.Context,
+		path string,
+		dst interface{},
+		query ...tvheadend.Query,
+	) (*tvheadend.Response, error) {
+		s := dst.(*tvheadend.Status[tvheadend.InputStatus])
+		for _, sub := range subs {
+			s.Entries = append(s.Entries, tvheadend.InputStatus{Subs: sub})
+		}
+
+		return &tvheadend.Response{Response: &http.Response{StatusCode: 200}}, nil
+	}
+}
+
+func mockUpcomingRecordings(entries ...tvheadend.DvrGridEntry) func(
+	ctx context.Context,
+	path string,
+	dst interface{},
+	query ...tvheadend.Query,
+) (*tvheadend.Response, error) {
+	return func(
+		ctx context.Context,
+		path string,
+		dst interface{},
+		query ...tvheadend.Query,
+	) (*tvheadend.Response, error) {
+		g := dst.(*tvheadend.DvrGrid)
+		g.Entries = entries
+		g.Total = int64(len(entries))
+
+		return &tvheadend.Response{Response: &http.Response{StatusCode: 200}}, nil
+	}
+}
+
+func newTestLimiter(
+	ctrl *gomock.Controller,
+	cfg *config.StreamingConfig,
+	sessions core.StreamSessionRegistry,
+	tvh tvheadend.Client,
+) core.StreamLimiter {
+	return streaming.NewLimiter(cfg, sessions, streaming.New(cfg, tvh), tvh, recording.New(tvh), newMockClock(ctrl))
+}
+
+// startChannelStream starts a channel stream of a user and returns the error.
+func startChannelStream(limiter core.StreamLimiter, userID int64) error {
+	_, _, err := limiter.StartChannelStream(ctx, core.StreamSession{UserID: userID})
+	return err
+}
+
+func TestLimiterAllowsStreamWithoutLimits(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	sessions := streaming.NewSessionRegistry(newMockClock(ctrl))
+	sessions.Start(ctx, core.StreamSession{UserID: 1, Type: core.StreamTypeChannel})
+
+	limiter := newTestLimiter(ctrl, &config.StreamingConfig{}, sessions, nil)
+
+	assert.Nil(t, startChannelStream(limiter, 1))
+}
+
+func TestLimiterRejectsWhenUserLimitIsReached(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	sessions := streaming.NewSessionRegistry(newMockClock(ctrl))
+	sessions.Start(ctx, core.StreamSession{UserID: 1, Type: core.StreamTypeChannel})
+	sessions.Start(ctx, core.StreamSession{UserID: 1, Type: core.StreamTypeRecording})
+	sessions.Start(ctx, core.StreamSession{UserID: 2, Type: core.StreamTypeChannel})
+
+	limiter := newTestLimiter(ctrl, &config.StreamingConfig{
+		MaxStreamsPerUser: 1,
+	}, sessions, nil)
+
+	assert.Equal(t, core.ErrStreamLimitPerUserReached, startChannelStream(limiter, 1))
+	assert.Nil(t, startChannelStream(limiter, 3))
+}
+
+func TestLimiterRejectsWhenGlobalLimitIsReached(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	sessions := streaming.NewSessionRegistry(newMockClock(ctrl))
+	sessions.Start(ctx, core.StreamSession{UserID: 1, Type: core.StreamTypeChannel})
+	sessions.Start(ctx, core.StreamSession{UserID: 2, Type: core.StreamTypeChannel})
+
+	limiter := newTestLimiter(ctrl, &config.StreamingConfig{
+		MaxStreamsPerUser: 2,
+		MaxStreams:        2,
+	}, sessions, nil)
+
+	assert.Equal(t, core.ErrStreamLimitReached, startChannelStream(limiter, 3))
+}
+
+func TestLimiterRegistersStartedStream(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	sessions := streaming.NewSessionRegistry(newMockClock(ctrl))
+	limiter := newTestLimiter(ctrl, &config.StreamingConfig{}, sessions, nil)
+
+	id, _, err := limiter.StartChannelStream(ctx, core.StreamSession{UserID: 1, ChannelNumber: 5})
+
+	assert.Nil(t, err)
+	assert.Len(t, sessions.List(), 1)
+	assert.Equal(t, id, sessions.List()[0].ID)
+	assert.Equal(t, core.StreamTypeChannel, sessions.List()[0].Type)
+	assert.Equal(t, int64(5), sessions.List()[0].ChannelNumber)
+}
+
+func TestLimiterEnforcesLimitsForConcurrentStreams(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	sessions := streaming.NewSessionRegistry(newMockClock(ctrl))
+	limiter := newTestLimiter(ctrl, &config.StreamingConfig{
+		MaxStreams: 5,
+	}, sessions, nil)
+
+	var wg sync.WaitGroup
+	for i := range 50 {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			startChannelStream(limiter, int64(i))
+		}()
+	}
+	wg.Wait()
+
+	assert.Len(t, sessions.List(), 5)
+}
+
+func TestLimiterTunerProtection(t *testing.T) {
+	soon := tvheadend.DvrGridEntry{
+		Enabled:     true,
+		SchedStatus: "scheduled",
+		Start:       now.Add(20 * time.Minute).Unix(),
+		Stop:        now.Add(80 * time.Minute).Unix(),
+	}
+
+	withinPadding := tvheadend.DvrGridEntry{
+		Enabled:     true,
+		SchedStatus: "scheduled",
+		Start:       now.Add(35 * time.Minute).Unix(),
+		StartExtra:  10,
+		Stop:        now.Add(80 * time.Minute).Unix(),
+	}
+
+	later := tvheadend.DvrGridEntry{
+		Enabled:     true,
+		SchedStatus: "scheduled",
+		Start:       now.Add(2 * time.Hour).Unix(),
+		Stop:        now.Add(3 * time.Hour).Unix(),
+	}
+
+	running := tvheadend.DvrGridEntry{
+		Enabled:     true,
+		SchedStatus: "recording",
+		Start:       now.Add(-10 * time.Minute).Unix(),
+		Stop:        now.Add(50 * time.Minute).Unix(),
+	}
+
+	disabled := soon
+	disabled.Enabled = false
+
+	tests := []struct {
+		name       string
+		tuners     int
+		inputs     []int
+		recordings []tvheadend.DvrGridEntry
+		expected   error
+	}{
+		{"free tuners without recordings", 0, []int{0, 0}, nil, nil},
+		{"all tuners busy without recordings", 0, []int{1, 1}, nil, nil},
+		{"free tuner needed for recording", 0, []int{0, 1}, []tvheadend.DvrGridEntry{soon}, core.ErrTunersReserved},
+		{"free tuner needed for padded recording", 0, []int{0, 1}, []tvheadend.DvrGridEntry{withinPadding}, core.ErrTunersReserved},
+		{"enough tuners for recording", 0, []int{0, 0}, []tvheadend.DvrGridEntry{soon}, nil},
+		{"ignores later recordings", 0, []int{0, 1}, []tvheadend.DvrGridEntry{later}, nil},
+		{"ignores running recordings", 0, []int{0, 1}, []tvheadend.DvrGridEntry{running}, nil},
+		{"ignores disabled recordings", 0, []int{0, 1}, []tvheadend.DvrGridEntry{disabled}, nil},
+		{"configured tuners", 3, []int{1}, []tvheadend.DvrGridEntry{soon}, nil},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+
+			mockClient := mock_tvheadend.NewMockClient(ctrl)
+			mockClient.EXPECT().
+				Exec(ctx, "/api/status/inputs", gomock.Any()).
+				DoAndReturn(mockInputs(tc.inputs...)).
+				AnyTimes()
+
+			mockClient.EXPECT().
+				Exec(ctx, "/api/dvr/entry/grid_upcoming", gomock.Any(), gomock.Any()).
+				DoAndReturn(mockUpcomingRecordings(tc.recordings...)).
+				Times(1)
+
+			limiter := newTestLimiter(ctrl, &config.StreamingConfig{
+				TunerProtection: config.TunerProtectionConfig{
+					Enabled:   true,
+					Lookahead: 30 * time.Minute,
+					Tuners:    tc.tuners,
+				},
+			}, streaming.NewSessionRegistry(newMockClock(ctrl)), mockClient)
+
+			assert.Equal(t, tc.expected, startChannelStream(limiter, 1))
+		})
+	}
+}
+
+func TestLimiterTunerProtectionSkipsRunningUpstream(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockStreaming := mock_core.NewMockStreamingService(ctrl)
+	mockStreaming.EXPECT().
+		IsChannelStreamRunning(int64(1), "pass").
+		Return(true)
+
+	// The tuners and recordings are not requested from tvheadend.
+	mockClient := mock_tvheadend.NewMockClient(ctrl)
+
+	limiter := streaming.NewLimiter(&config.StreamingConfig{
+		TunerProtection: config.TunerProtectionConfig{
+			Enabled:   true,
+			Lookahead: 30 * time.Minute,
+		},
+	}, streaming.NewSessionRegistry(newMockClock(ctrl)), mockStreaming, mockClient, recording.New(mockClient), newMockClock(ctrl))
+
+	_, _, err := limiter.StartChannelStream(ctx, core.StreamSession{
+		UserID:        1,
+		ChannelNumber: 1,
+		Profile:       "pass",
+	})
+	assert.Nil(t, err)
+}
+
+func TestLimiterTunerProtectionReturnsErrorWhenInputsRequestFails(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockClient := mock_tvheadend.NewMockClient(ctrl)
+	mockClient.EXPECT().
+		Exec(ctx, "/api/dvr/entry/grid_upcoming", gomock.Any(), gomock.Any()).
+		DoAndReturn(mockUpcomingRecordings(tvheadend.DvrGridEntry{
+			Enabled:     true,
+			SchedStatus: "scheduled",
+			Start:       now.Add(10 * time.Minute).Unix(),
+			Stop:        now.Add(20 * time.Minute).Unix(),
+		})).
+		Times(1)
+
+	mockClient.EXPECT().
+		Exec(ctx, "/api/status/inputs", gomock.Any()).
+		DoAndReturn(mock_tvheadend.MockClientExecReturnsError).
+		Times(1)
+
+	limiter := newTestLimiter(ctrl, &config.StreamingConfig{
+		TunerProtection: config.TunerProtectionConfig{
+			Enabled:   true,
+			Lookahead: 30 * time.Minute,
+		},
+	}, streaming.NewSessionRegistry(newMockClock(ctrl)), mockClient)
+
+	assert.EqualError(t, startChannelStream(limiter, 1), "error")
+}
@@ -0,0 +1,407 @@
+package streaming
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"mime"
+	"net/http"
+	"sync"
+
+	"github.com/rs/zerolog/log"
+)
+
+const (
+	tsPacketSize = 188
+	// chunkSize is the maximum size of a chunk read from the upstream.
+	chunkSize = 64 * tsPacketSize
+)
+
+var errSlowConsumer = errors.New("stream client is too slow and has been evicted")
+
+type (
+	// openFunc opens an upstream channel stream.
+	openFunc func(ctx context.Context, channelNumber int64, profile string) (*http.Response, error)
+
+	// multiplexer shares one upstream channel stream
+	// between all clients of the same channel and profile.
+	multiplexer struct {
+		open       openFunc
+		bufferSize int
+
+		mu        sync.Mutex
+		upstreams map[string]*upstream
+	}
+
+	upstream struct {
+		key    string
+		cancel context.CancelFunc
+
+		// ready is closed when the upstream is opened or failed to open.
+		ready     chan struct{}
+		err       error
+		shareable bool
+		res       *http.Response
+
+		mu      sync.Mutex
+		clients map[*subscriber]struct{}
+		closed  bool
+	}
+
+	// subscriber is a client of an upstream with its own buffer.
+	subscriber struct {
+		ctx context.Context
+		m   *multiplexer
+		up  *upstream
+
+		mu     sync.Mutex
+		queue  [][]byte
+		queued int
+		limit  int
+		err    error
+		done   bool
+		notify chan struct{}
+
+		pending   []byte
+		closeOnce sync.Once
+	}
+)
+
+func newMultiplexer(open openFunc, bufferSize int) *multiplexer {
+	return &multiplexer{
+		open:       open,
+		bufferSize: bufferSize,
+		upstreams:  make(map[string]*upstream),
+	}
+}
+
+// subscribe returns a response whose body is fed by the shared
+// upstream of the channel and profile. The upstream is opened if
+// it's not already running.
+func (m *multiplexer) subscribe(
+	ctx context.Context,
+	channelNumber int64,
+	profile string,
+) (*http.Response, error) {
+	key := upstreamKey(channelNumber, profile)
+
+	m.mu.Lock()
+	up, running := m.upstreams[key]
+	if !running {
+		upstreamCtx, cancel := context.WithCancel(context.Background())
+		up = &upstream{
+			key:     key,
+			cancel:  cancel,
+			ready:   make(chan struct{}),
+			clients: make(map[*subscriber]struct{}),
+		}
+		m.upstreams[key] = up
+
+		go m.run(upstreamCtx, up, channelNumber, profile)
+	}
+	sub := up.add(ctx, m, m.bufferSize)
+	m.mu.Unlock()
+
+	select {
+	case <-up.ready:
+	case <-ctx.Done():
+		sub.Close()
+		return nil, ctx.Err()
+	}
+
+	if up.err != nil {
+		sub.Close()
+		return nil, up.err
+	}
+
+	// Streams which can't be joined in the middle are only
+	// served to the client which opened them.
+	if !up.shareable && running {
+		sub.Close()
+		return m.open(ctx, channelNumber, profile)
+	}
+
+	return &http.Response{
+		Status:     up.res.Status,
+		StatusCode: up.res.StatusCode,
+		Header:     up.res.Header.Clone(),
+		Body:       sub,
+	}, nil
+}
+
+// running returns true, if an upstream of the channel and profile is running.
+func (m *multiplexer) running(channelNumber int64, profile string) bool {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	_, ok := m.upstreams[upstreamKey(channelNumber, profile)]
+	return ok
+}
+
+// run opens the upstream and broadcasts it to all subscribers until
+// the upstream ends or the last subscriber leaves.
+func (m *multiplexer) run(ctx context.Context, up *upstream, channelNumber int64, profile string) {
+	res, err := m.open(ctx, channelNumber, profile)
+	if err != nil {
+		up.err = err
+		close(up.ready)
+		m.remove(up, err)
+		return
+	}
+	defer res.Body.Close()
+
+	up.res = res
+	up.shareable = isShareable(res)
+	close(up.ready)
+
+	if !up.shareable {
+		log.Debug().Str("stream", up.key).
+			Str("content_type", res.Header.Get("Content-Type")).
+			Msg("stream can't be shared between clients")
+
+		// Prevent further clients from joining. Clients which
+		// already joined will open their own upstream.
+		m.mu.Lock()
+		if m.upstreams[up.key] == up {
+			delete(m.upstreams, up.key)
+		}
+		m.mu.Unlock()
+	}
+
+	err = up.pump(res.Body)
+	if ctx.Err() == nil && err != nil && !errors.Is(err, io.EOF) {
+		log.Error().Str("stream", up.key).
+			Err(err).Msg("shared upstream failed")
+	}
+
+	if err == nil {
+		err = io.EOF
+	}
+
+	m.remove(up, err)
+}
+
+// remove removes an upstream, closes it and ends all subscribers with err.
+func (m *multiplexer) remove(up *upstream, err error) {
+	m.mu.Lock()
+	m.detach(up)
+	m.mu.Unlock()
+
+	up.close(err)
+}
+
+// detach removes an upstream from the running upstreams, so that
+// no further clients join it. m.mu must be held.
+func (m *multiplexer) detach(up *upstream) {
+	if m.upstreams[up.key] == up {
+		delete(m.upstreams, up.key)
+	}
+}
+
+// leave removes a subscriber from an upstream and closes
+// the upstream when the last subscriber left.
+func (m *multiplexer) leave(up *upstream, sub *subscriber) {
+	m.mu.Lock()
+	up.mu.Lock()
+
+	delete(up.clients, sub)
+	last := len(up.clients) == 0 && !up.closed
+
+	up.mu.Unlock()
+
+	// The upstream is detached while m.mu is still held, so that
+	// clients joining concurrently open a new upstream instead of
+	// joining the one which is about to be closed.
+	if last {
+		m.detach(up)
+	}
+	m.mu.Unlock()
+
+	if last {
+		log.Debug().Str("stream", up.key).
+			Msg("last client left, closing shared upstream")
+
+		up.close(io.EOF)
+	}
+}
+
+// close closes the upstream and ends all subscribers with err.
+func (up *upstream) close(err error) {
+	up.mu.Lock()
+	up.closed = true
+	clients := up.clients
+	up.clients = make(map[*subscriber]struct{})
+	up.mu.Unlock()
+
+	up.cancel()
+
+	for sub := range clients {
+		sub.finish(err)
+	}
+}
+
+func (up *upstream) add(ctx context.Context, m *multiplexer, limit int) *subscriber {
+	sub := &subscriber{
+		ctx:    ctx,
+		m:      m,
+		up:     up,
+		limit:  limit,
+		notify: make(chan struct{}, 1),
+	}
+
+	up.mu.Lock()
+	up.clients[sub] = struct{}{}
+	up.mu.Unlock()
+
+	return sub
+}
+
+// pump reads chunks of whole mpeg-ts packets from r and
+// broadcasts them to the subscribers.
+func (up *upstream) pump(r io.Reader) error {
+	buf := make([]byte, chunkSize)
+	n := 0
+
+	for {
+		read, err := r.Read(buf[n:])
+		n += read
+
+		// Only whole packets are broadcasted, so that clients
+		// joining later start at a packet boundary.
+		aligned := n
+		if up.shareable {
+			aligned -= n % tsPacketSize
+		}
+
+		if aligned > 0 {
+			chunk := make([]byte, aligned)
+			copy(chunk, buf[:aligned])
+			n = copy(buf, buf[aligned:n])
+
+			up.broadcast(chunk)
+		}
+
+		if err != nil {
+			return err
+		}
+	}
+}
+
+func (up *upstream) broadcast(chunk []byte) {
+	up.mu.Lock()
+	var evicted []*subscriber
+	for sub := range up.clients {
+		if !sub.push(chunk) {
+			evicted = append(evicted, sub)
+		}
+	}
+	up.mu.Unlock()
+
+	for _, sub := range evicted {
+		log.Warn().Str("stream", up.key).
+			Msg("evicting slow stream client")
+
+		sub.finish(errSlowConsumer)
+		sub.m.leave(up, sub)
+	}
+}
+
+// push queues a chunk for the subscriber and reports
+// false when the buffer of the subscriber is full.
+func (s *subscriber) push(chunk []byte) bool {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if s.done {
+		return true
+	}
+
+	if s.queued+len(chunk) > s.limit {
+		return false
+	}
+
+	s.queue = append(s.queue, chunk)
+	s.queued += len(chunk)
+	s.signal()
+
+	return true
+}
+
+// finish ends the subscriber after the queued chunks with err.
+func (s *subscriber) finish(err error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if s.done {
+		return
+	}
+
+	s.done = true
+	s.err = err
+	s.signal()
+}
+
+func (s *subscriber) signal() {
+	select {
+	case s.notify <- struct{}{}:
+	default:
+	}
+}
+
+func (s *subscriber) Read(p []byte) (int, error) {
+	for {
+		if len(s.pending) > 0 {
+			n := copy(p, s.pending)
+			s.pending = s.pending[n:]
+			return n, nil
+		}
+
+		s.mu.Lock()
+		if len(s.queue) > 0 {
+			s.pending = s.queue[0]
+			s.queue[0] = nil
+			s.queue = s.queue[1:]
+			s.queued -= len(s.pending)
+			s.mu.Unlock()
+			continue
+		}
+
+		if s.done {
+			err := s.err
+			s.mu.Unlock()
+			return 0, err
+		}
+		s.mu.Unlock()
+
+		select {
+		case <-s.notify:
+		case <-s.ctx.Done():
+			return 0, s.ctx.Err()
+		}
+	}
+}
+
+func (s *subscriber) Close() error {
+	s.closeOnce.Do(func() {
+		s.finish(io.EOF)
+		s.m.leave(s.up, s)
+	})
+
+	return nil
+}
+
+// isShareable checks if a stream can be joined by clients in the middle,
+// which is only the case for mpeg-ts streams.
+func upstreamKey(channelNumber int64, profile string) string {
+	return fmt.Sprintf("%d/%s", channelNumber, profile)
+}
+
+func isShareable(res *http.Response) bool {
+	mediaType, _, err := mime.ParseMediaType(res.Header.Get("Content-Type"))
+	if err != nil {
+		return false
+	}
+
+	return mediaType == "video/mp2t"
+}
@@ -0,0 +1,134 @@
+package streaming
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"sort"
+	"sync"
+	"sync/atomic"
+
+	"github.com/davidborzek/tvhgo/core"
+)
+
+type (
+	activeSession struct {
+		session core.StreamSession
+		bytes   atomic.Int64
+		cancel  context.CancelFunc
+	}
+
+	sessionRegistry struct {
+		mu       sync.RWMutex
+		sessions map[string]*activeSession
+		clock    core.Clock
+	}
+)
+
+// NewSessionRegistry creates a new in-memory registry for stream sessions.
+func NewSessionRegistry(clock core.Clock) core.StreamSessionRegistry {
+	return &sessionRegistry{
+		sessions: make(map[string]*activeSession),
+		clock:    clock,
+	}
+}
+
+func (r *sessionRegistry) Start(
+	ctx context.Context,
+	session core.StreamSession,
+) (string, context.Context) {
+	id, ctx, _ := r.Reserve(ctx, session, nil)
+	return id, ctx
+}
+
+func (r *sessionRegistry) Reserve(
+	ctx context.Context,
+	session core.StreamSession,
+	check func(active []*core.StreamSession) error,
+) (string, context.Context, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if check != nil {
+		if err := check(r.list()); err != nil {
+			return "", nil, err
+		}
+	}
+
+	ctx, cancel := context.WithCancel(ctx)
+
+	session.ID = generateSessionID()
+	session.StartedAt = r.clock.Now().Unix()
+	session.BytesTransferred = 0
+
+	r.sessions[session.ID] = &activeSession{
+		session: session,
+		cancel:  cancel,
+	}
+
+	return session.ID, ctx, nil
+}
+
+func (r *sessionRegistry) AddBytes(id string, n int64) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	if s, ok := r.sessions[id]; ok {
+		s.bytes.Add(n)
+	}
+}
+
+func (r *sessionRegistry) Stop(id string) {
+	r.mu.Lock()
+	s, ok := r.sessions[id]
+	delete(r.sessions, id)
+	r.mu.Unlock()
+
+	if ok {
+		s.cancel()
+	}
+}
+
+func (r *sessionRegistry) List() []*core.StreamSession {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	return r.list()
+}
+
+// list returns a snapshot of all active stream sessions.
+// The caller must hold the lock.
+func (r *sessionRegistry) list() []*core.StreamSession {
+	sessions := make([]*core.StreamSession, 0, len(r.sessions))
+	for _, s := range r.sessions {
+		session := s.session
+		session.BytesTransferred = s.bytes.Load()
+		sessions = append(sessions, &session)
+	}
+
+	sort.Slice(sessions, func(i, j int) bool {
+		return sessions[i].StartedAt < sessions[j].StartedAt
+	})
+
+	return sessions
+}
+
+func (r *sessionRegistry) Terminate(id string) error {
+	r.mu.RLock()
+	s, ok := r.sessions[id]
+	r.mu.RUnlock()
+
+	if !ok {
+		return core.ErrStreamSessionNotFound
+	}
+
+	s.cancel()
+	return nil
+}
+
+// Generates a random 128-bit session id.
+func generateSessionID() string {
+	b := make([]byte, 16)
+	rand.Read(b)
+	return hex.EncodeToString(b)
+}
@@ -0,0 +1,130 @@
+package streaming_test
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/davidborzek/tvhgo/core"
+	mock_core "github.com/davidborzek/tvhgo/mock/core"
+	"github.com/davidborzek/tvhgo/services/streaming"
+	"github.com/stretchr/testify/assert"
+	"go.uber.org/mock/gomock"
+)
+
+var (
+	ctx = context.TODO()
+
+	now = time.Unix(1700000000, 0)
+)
+
+func newMockClock(ctrl *gomock.Controller) core.Clock {
+	mockClock := mock_core.NewMockClock(ctrl)
+	mockClock.EXPECT().
+		Now().
+		Return(now).
+		AnyTimes()
+
+	return mockClock
+}
+
+func TestSessionRegistryStartRegistersSession(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	registry := streaming.NewSessionRegistry(newMockClock(ctrl))
+
+	id, _ := registry.Start(ctx, core.StreamSession{
+		UserID:        1,
+		Type:          core.StreamTypeChannel,
+		ChannelNumber: 5,
+		Profile:       "pass",
+		ClientIP:      "127.0.0.1",
+	})
+
+	registry.AddBytes(id, 100)
+	registry.AddBytes(id, 50)
+
+	sessions := registry.List()
+
+	assert.NotEmpty(t, id)
+	assert.Len(t, sessions, 1)
+	assert.Equal(t, &core.StreamSession{
+		ID:               id,
+		UserID:           1,
+		Type:             core.StreamTypeChannel,
+		ChannelNumber:    5,
+		Profile:          "pass",
+		ClientIP:         "127.0.0.1",
+		StartedAt:        now.Unix(),
+		BytesTransferred: 150,
+	}, sessions[0])
+}
+
+func TestSessionRegistryStopRemovesSessionAndCancelsContext(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	registry := streaming.NewSessionRegistry(newMockClock(ctrl))
+
+	id, sessionCtx := registry.Start(ctx, core.StreamSession{UserID: 1})
+	registry.Stop(id)
+
+	assert.Empty(t, registry.List())
+	assert.ErrorIs(t, sessionCtx.Err(), context.Canceled)
+}
+
+func TestSessionRegistryTerminateCancelsContext(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	registry := streaming.NewSessionRegistry(newMockClock(ctrl))
+
+	id, sessionCtx := registry.Start(ctx, core.StreamSession{UserID: 1})
+
+	err := registry.Terminate(id)
+
+	assert.Nil(t, err)
+	assert.ErrorIs(t, sessionCtx.Err(), context.Canceled)
+}
+
+func TestSessionRegistryTerminateReturnsErrStreamSessionNotFound(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	registry := streaming.NewSessionRegistry(newMockClock(ctrl))
+
+	err := registry.Terminate("unknown")
+
+	assert.Equal(t, core.ErrStreamSessionNotFound, err)
+}
+
+func TestSessionRegistryContextIsCancelledWithParent(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	registry := streaming.NewSessionRegistry(newMockClock(ctrl))
+
+	parent, cancel := context.WithCancel(ctx)
+	_, sessionCtx := registry.Start(parent, core.StreamSession{UserID: 1})
+	cancel()
+
+	assert.ErrorIs(t, sessionCtx.Err(), context.Canceled)
+}
+
+func TestSessionRegistryReserveDoesNotRegisterRejectedSession(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	registry := streaming.NewSessionRegistry(newMockClock(ctrl))
+	registry.Start(ctx, core.StreamSession{UserID: 1})
+
+	_, _, err := registry.Reserve(ctx, core.StreamSession{UserID: 2},
+		func(active []*core.StreamSession) error {
+			assert.Len(t, active, 1)
+			return core.ErrStreamLimitReached
+		})
+
+	assert.Equal(t, core.ErrStreamLimitReached, err)
+	assert.Len(t, registry.List(), 1)
+}
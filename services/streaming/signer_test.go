@@ -0,0 +1,154 @@
+package streaming_test
+
+import (
+	"testing"
+	"time"
+
+	"github.com/davidborzek/tvhgo/config"
+	"github.com/davidborzek/tvhgo/core"
+	mock_core "github.com/davidborzek/tvhgo/mock/core"
+	"github.com/davidborzek/tvhgo/services/streaming"
+	"github.com/stretchr/testify/assert"
+	"go.uber.org/mock/gomock"
+)
+
+func TestSignerSignsAndVerifiesStream(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	signer := streaming.NewSigner(&config.StreamSigningConfig{
+		Keys: []string{"someKey"},
+		TTL:  time.Hour,
+	}, newMockClock(ctrl))
+
+	sig := signer.Sign(1, nil, core.ChannelStreamResource(5), "pass")
+
+	assert.Equal(t, int64(1), sig.UserID)
+	assert.Equal(t, "channel:5", sig.Resource)
+	assert.Equal(t, "pass", sig.Profile)
+	assert.Equal(t, now.Add(time.Hour).Unix(), sig.Expires)
+	assert.NotEmpty(t, sig.Signature)
+
+	assert.Nil(t, signer.Verify(sig))
+}
+
+func TestSignerVerifyRejectsTamperedSignature(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	signer := streaming.NewSigner(&config.StreamSigningConfig{
+		Keys: []string{"someKey"},
+		TTL:  time.Hour,
+	}, newMockClock(ctrl))
+
+	sig := signer.Sign(1, nil, core.ChannelStreamResource(5), "pass")
+
+	tests := []struct {
+		name   string
+		tamper func(s *core.StreamSignature)
+	}{
+		{"user", func(s *core.StreamSignature) { s.UserID = 2 }},
+		{"scopes", func(s *core.StreamSignature) { s.Scopes = []core.Permission{core.PermissionStream} }},
+		{"empty scopes", func(s *core.StreamSignature) { s.Scopes = []core.Permission{} }},
+		{"resource", func(s *core.StreamSignature) { s.Resource = core.ChannelStreamResource(6) }},
+		{"profile", func(s *core.StreamSignature) { s.Profile = "webtv-h264-aac-matroska" }},
+		{"expires", func(s *core.StreamSignature) { s.Expires += 3600 }},
+		{"signature", func(s *core.StreamSignature) { s.Signature = "invalid" }},
+		{"malformed signature", func(s *core.StreamSignature) { s.Signature = "%%%" }},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			tampered := sig
+			tc.tamper(&tampered)
+
+			assert.Equal(t, core.ErrStreamSignatureInvalid, signer.Verify(tampered))
+		})
+	}
+}
+
+func TestSignerVerifyRejectsShiftedFieldBoundaries(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	signer := streaming.NewSigner(&config.StreamSigningConfig{
+		Keys: []string{"someKey"},
+		TTL:  time.Hour,
+	}, newMockClock(ctrl))
+
+	sig := signer.Sign(1, nil, core.RecordingStreamResource("someId\nsomeProfile"), "")
+
+	shifted := sig
+	shifted.Resource = core.RecordingStreamResource("someId")
+	shifted.Profile = "someProfile\n"
+
+	assert.Equal(t, core.ErrStreamSignatureInvalid, signer.Verify(shifted))
+}
+
+func TestSignerVerifyRejectsExpiredSignature(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockClock := mock_core.NewMockClock(ctrl)
+	mockClock.EXPECT().
+		Now().
+		Return(now)
+
+	mockClock.EXPECT().
+		Now().
+		Return(now.Add(2 * time.Hour))
+
+	signer := streaming.NewSigner(&config.StreamSigningConfig{
+		Keys: []string{"someKey"},
+		TTL:  time.Hour,
+	}, mockClock)
+
+	sig := signer.Sign(1, nil, core.RecordingStreamResource("abc"), "")
+
+	assert.Equal(t, core.ErrStreamSignatureExpired, signer.Verify(sig))
+}
+
+func TestSignerVerifyAcceptsRotatedKeys(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	oldSigner := streaming.NewSigner(&config.StreamSigningConfig{
+		Keys: []string{"oldKey"},
+		TTL:  time.Hour,
+	}, newMockClock(ctrl))
+
+	signer := streaming.NewSigner(&config.StreamSigningConfig{
+		Keys: []string{"newKey", "oldKey"},
+		TTL:  time.Hour,
+	}, newMockClock(ctrl))
+
+	unknownSigner := streaming.NewSigner(&config.StreamSigningConfig{
+		Keys: []string{"unknownKey"},
+		TTL:  time.Hour,
+	}, newMockClock(ctrl))
+
+	assert.Nil(t, signer.Verify(oldSigner.Sign(1, nil, "channel:1", "")))
+	assert.Nil(t, signer.Verify(signer.Sign(1, nil, "channel:1", "")))
+	assert.Equal(t,
+		core.ErrStreamSignatureInvalid,
+		signer.Verify(unknownSigner.Sign(1, nil, "channel:1", "")),
+	)
+}
+
+func TestSignerGeneratesRandomKeyWithoutConfiguredKeys(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	signer := streaming.NewSigner(&config.StreamSigningConfig{
+		TTL: time.Hour,
+	}, newMockClock(ctrl))
+
+	otherSigner := streaming.NewSigner(&config.StreamSigningConfig{
+		TTL: time.Hour,
+	}, newMockClock(ctrl))
+
+	sig := signer.Sign(1, nil, "channel:1", "")
+
+	assert.Nil(t, signer.Verify(sig))
+	assert.Equal(t, core.ErrStreamSignatureInvalid, otherSigner.Verify(sig))
+}
@@ -5,24 +5,44 @@ import (
 	"fmt"
 	"net/http"
 
+	"github.com/davidborzek/tvhgo/config"
 	"github.com/davidborzek/tvhgo/core"
 	"github.com/davidborzek/tvhgo/tvheadend"
 )
 
 type service struct {
 	tvh tvheadend.Client
+	mux *multiplexer
 }
 
-func New(tvh tvheadend.Client) core.StreamingService {
-	return &service{
+// New creates a new StreamingService. Clients of the same channel
+// and profile share a single upstream subscription.
+func New(cfg *config.StreamingConfig, tvh tvheadend.Client) core.StreamingService {
+	s := &service{
 		tvh: tvh,
 	}
+	s.mux = newMultiplexer(s.openChannelStream, cfg.ClientBufferSize)
+
+	return s
 }
 
 func (s *service) GetChannelStream(
 	ctx context.Context,
 	channelNumber int64,
 	profile string,
+) (*http.Response, error) {
+	return s.mux.subscribe(ctx, channelNumber, profile)
+}
+
+func (s *service) IsChannelStreamRunning(channelNumber int64, profile string) bool {
+	return s.mux.running(channelNumber, profile)
+}
+
+// openChannelStream opens a new upstream channel stream.
+func (s *service) openChannelStream(
+	ctx context.Context,
+	channelNumber int64,
+	profile string,
 ) (*http.Response, error) {
 	q := tvheadend.NewQuery()
 
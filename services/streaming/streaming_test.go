@@ -0,0 +1,322 @@
+package streaming_test
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"io"
+	"net/http"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/davidborzek/tvhgo/config"
+	"github.com/davidborzek/tvhgo/core"
+	mock_tvheadend "github.com/davidborzek/tvhgo/mock/tvheadend"
+	"github.com/davidborzek/tvhgo/services/streaming"
+	"github.com/davidborzek/tvhgo/tvheadend"
+	"github.com/rs/zerolog"
+	"github.com/rs/zerolog/log"
+	"github.com/stretchr/testify/assert"
+	"go.uber.org/mock/gomock"
+)
+
+// upstream is a fake tvheadend channel stream.
+type upstream struct {
+	mu  sync.Mutex
+	ctx context.Context
+	pw  *io.PipeWriter
+}
+
+func (u *upstream) context() context.Context {
+	u.mu.Lock()
+	defer u.mu.Unlock()
+	return u.ctx
+}
+
+func expectChannelStream(
+	mockClient *mock_tvheadend.MockClient,
+	contentType string,
+	upstreams chan<- *upstream,
+) *gomock.Call {
+	return mockClient.EXPECT().
+		Exec(gomock.Any(), "/stream/channelnumber/1", nil, gomock.Any()).
+		DoAndReturn(func(
+			ctx context.Context,
+			path string,
+			dst interface{},
+			query ...tvheadend.Query,
+		) (*tvheadend.Response, error) {
+			pr, pw := io.Pipe()
+			go func() {
+				<-ctx.Done()
+				pw.CloseWithError(ctx.Err())
+			}()
+
+			upstreams <- &upstream{ctx: ctx, pw: pw}
+
+			return &tvheadend.Response{Response: &http.Response{
+				StatusCode: 200,
+				Header:     http.Header{"Content-Type": []string{contentType}},
+				Body:       pr,
+			}}, nil
+		})
+}
+
+func newStreamingService(mockClient tvheadend.Client, bufferSize int) core.StreamingService {
+	return streaming.New(&config.StreamingConfig{
+		ClientBufferSize: bufferSize,
+	}, mockClient)
+}
+
+func packets(n int) []byte {
+	return bytes.Repeat([]byte{0x47}, n*188)
+}
+
+func readFull(t *testing.T, r io.Reader, n int) []byte {
+	buf := make([]byte, n)
+	_, err := io.ReadFull(r, buf)
+	assert.Nil(t, err)
+	return buf
+}
+
+func TestGetChannelStreamSharesUpstreamBetweenClients(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	upstreams := make(chan *upstream, 1)
+	mockClient := mock_tvheadend.NewMockClient(ctrl)
+	expectChannelStream(mockClient, "video/mp2t", upstreams).Times(1)
+
+	service := newStreamingService(mockClient, 1<<20)
+
+	first, err := service.GetChannelStream(ctx, 1, "pass")
+	assert.Nil(t, err)
+	assert.Equal(t, 200, first.StatusCode)
+	assert.Equal(t, "video/mp2t", first.Header.Get("Content-Type"))
+
+	second, err := service.GetChannelStream(ctx, 1, "pass")
+	assert.Nil(t, err)
+
+	up := <-upstreams
+	data := packets(3)
+	up.pw.Write(data)
+
+	assert.Equal(t, data, readFull(t, first.Body, len(data)))
+	assert.Equal(t, data, readFull(t, second.Body, len(data)))
+
+	first.Body.Close()
+	assert.Nil(t, up.context().Err())
+
+	second.Body.Close()
+	assert.Eventually(t, func() bool {
+		return up.context().Err() != nil
+	}, time.Second, 10*time.Millisecond)
+}
+
+func TestGetChannelStreamOpensNewUpstreamAfterLastClientLeft(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	upstreams := make(chan *upstream, 2)
+	mockClient := mock_tvheadend.NewMockClient(ctrl)
+	expectChannelStream(mockClient, "video/mp2t", upstreams).Times(2)
+
+	service := newStreamingService(mockClient, 1<<20)
+
+	res, err := service.GetChannelStream(ctx, 1, "pass")
+	assert.Nil(t, err)
+	res.Body.Close()
+
+	res, err = service.GetChannelStream(ctx, 1, "pass")
+	assert.Nil(t, err)
+	defer res.Body.Close()
+
+	first, second := <-upstreams, <-upstreams
+	assert.NotNil(t, first.context().Err())
+	assert.Nil(t, second.context().Err())
+}
+
+// blockingWriter blocks writing the first log message
+// containing msg, until resume is closed.
+type blockingWriter struct {
+	msg     string
+	once    sync.Once
+	blocked chan struct{}
+	resume  chan struct{}
+}
+
+func (w *blockingWriter) Write(p []byte) (int, error) {
+	if bytes.Contains(p, []byte(w.msg)) {
+		w.once.Do(func() {
+			close(w.blocked)
+			<-w.resume
+		})
+	}
+
+	return len(p), nil
+}
+
+func TestGetChannelStreamDoesNotJoinUpstreamOfLeavingLastClient(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	upstreams := make(chan *upstream, 2)
+	mockClient := mock_tvheadend.NewMockClient(ctrl)
+	expectChannelStream(mockClient, "video/mp2t", upstreams).Times(2)
+
+	service := newStreamingService(mockClient, 1<<20)
+
+	// Pause the last client while it leaves, after it decided to close the upstream.
+	w := &blockingWriter{
+		msg:     "last client left",
+		blocked: make(chan struct{}),
+		resume:  make(chan struct{}),
+	}
+	logger := log.Logger
+	log.Logger = zerolog.New(w).Level(zerolog.DebugLevel)
+	defer func() { log.Logger = logger }()
+
+	first, err := service.GetChannelStream(ctx, 1, "pass")
+	assert.Nil(t, err)
+
+	go first.Body.Close()
+	<-w.blocked
+
+	second, err := service.GetChannelStream(ctx, 1, "pass")
+	assert.Nil(t, err)
+	defer second.Body.Close()
+
+	close(w.resume)
+
+	firstUp := <-upstreams
+
+	var secondUp *upstream
+	select {
+	case secondUp = <-upstreams:
+	case <-time.After(time.Second):
+		t.Fatal("client joined the upstream of the leaving client")
+	}
+
+	assert.Eventually(t, func() bool {
+		return firstUp.context().Err() != nil
+	}, time.Second, 10*time.Millisecond)
+
+	go secondUp.pw.Write(packets(1))
+	assert.Equal(t, packets(1), readFull(t, second.Body, 188))
+}
+
+func TestGetChannelStreamAlignsLateClientsToPackets(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	upstreams := make(chan *upstream, 1)
+	mockClient := mock_tvheadend.NewMockClient(ctrl)
+	expectChannelStream(mockClient, "video/mp2t; charset=binary", upstreams).Times(1)
+
+	service := newStreamingService(mockClient, 1<<20)
+
+	first, err := service.GetChannelStream(ctx, 1, "pass")
+	assert.Nil(t, err)
+	defer first.Body.Close()
+
+	up := <-upstreams
+	data := packets(2)
+	data[188] = 0x01
+
+	// Write one and a half packet before the second client joins.
+	up.pw.Write(data[:282])
+	assert.Equal(t, data[:188], readFull(t, first.Body, 188))
+
+	second, err := service.GetChannelStream(ctx, 1, "pass")
+	assert.Nil(t, err)
+	defer second.Body.Close()
+
+	up.pw.Write(data[282:])
+
+	assert.Equal(t, data[188:], readFull(t, first.Body, 188))
+	assert.Equal(t, data[188:], readFull(t, second.Body, 188))
+}
+
+func TestGetChannelStreamEvictsSlowClients(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	upstreams := make(chan *upstream, 1)
+	mockClient := mock_tvheadend.NewMockClient(ctrl)
+	expectChannelStream(mockClient, "video/mp2t", upstreams).Times(1)
+
+	service := newStreamingService(mockClient, 2*188)
+
+	fast, err := service.GetChannelStream(ctx, 1, "pass")
+	assert.Nil(t, err)
+	defer fast.Body.Close()
+
+	slow, err := service.GetChannelStream(ctx, 1, "pass")
+	assert.Nil(t, err)
+	defer slow.Body.Close()
+
+	up := <-upstreams
+	for i := 0; i < 3; i++ {
+		up.pw.Write(packets(1))
+		readFull(t, fast.Body, 188)
+	}
+
+	assert.Equal(t, packets(2), readFull(t, slow.Body, 2*188))
+
+	_, err = slow.Body.Read(make([]byte, 188))
+	assert.EqualError(t, err, "stream client is too slow and has been evicted")
+
+	up.pw.Write(packets(1))
+	assert.Equal(t, packets(1), readFull(t, fast.Body, 188))
+	assert.Nil(t, up.context().Err())
+}
+
+func TestGetChannelStreamDoesNotShareNonTransportStreams(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	upstreams := make(chan *upstream, 2)
+	mockClient := mock_tvheadend.NewMockClient(ctrl)
+	expectChannelStream(mockClient, "video/x-matroska", upstreams).Times(2)
+
+	service := newStreamingService(mockClient, 1<<20)
+
+	first, err := service.GetChannelStream(ctx, 1, "webtv-h264-aac-matroska")
+	assert.Nil(t, err)
+	defer first.Body.Close()
+
+	second, err := service.GetChannelStream(ctx, 1, "webtv-h264-aac-matroska")
+	assert.Nil(t, err)
+	defer second.Body.Close()
+
+	firstUp, secondUp := <-upstreams, <-upstreams
+
+	firstUp.pw.Write([]byte("first"))
+	secondUp.pw.Write([]byte("second"))
+
+	assert.Equal(t, []byte("first"), readFull(t, first.Body, 5))
+	assert.Equal(t, []byte("second"), readFull(t, second.Body, 6))
+}
+
+func TestGetChannelStreamReturnsUpstreamError(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockClient := mock_tvheadend.NewMockClient(ctrl)
+	mockClient.EXPECT().
+		Exec(gomock.Any(), "/stream/channelnumber/1", nil, gomock.Any()).
+		Return(nil, errors.New("error"))
+
+	mockClient.EXPECT().
+		Exec(gomock.Any(), "/stream/channelnumber/1", nil, gomock.Any()).
+		Return(&tvheadend.Response{Response: &http.Response{StatusCode: 404}}, nil)
+
+	service := newStreamingService(mockClient, 1<<20)
+
+	_, err := service.GetChannelStream(ctx, 1, "")
+	assert.EqualError(t, err, "error")
+
+	_, err = service.GetChannelStream(ctx, 1, "")
+	assert.EqualError(t, err, "unexpected status code: 404")
+}
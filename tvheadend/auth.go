@@ -0,0 +1,247 @@
+package tvheadend
+
+import (
+	"crypto/md5"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"hash"
+	"net/http"
+	"strings"
+	"sync"
+)
+
+// AuthMethod defines how the client authenticates against tvheadend.
+type AuthMethod string
+
+const (
+	// AuthMethodAuto uses digest auth and falls back to basic auth,
+	// if tvheadend only offers basic auth.
+	AuthMethodAuto AuthMethod = "auto"
+	// AuthMethodBasic sends the credentials with basic auth on each request.
+	AuthMethodBasic AuthMethod = "basic"
+	// AuthMethodDigest only uses digest auth.
+	AuthMethodDigest AuthMethod = "digest"
+)
+
+// digestChallenge is a parsed digest challenge of the WWW-Authenticate header.
+type digestChallenge struct {
+	realm     string
+	nonce     string
+	opaque    string
+	algorithm string
+	qop       string
+	nc        int
+}
+
+// authenticator remembers the auth scheme offered by tvheadend
+// and the last digest challenge to authorize further requests
+// without an additional round trip.
+type authenticator struct {
+	method   AuthMethod
+	username string
+	password string
+
+	mu     sync.Mutex
+	basic  bool
+	digest *digestChallenge
+}
+
+func newAuthenticator(opts ClientOpts) *authenticator {
+	method := opts.AuthMethod
+	if method == "" {
+		method = AuthMethodAuto
+	}
+
+	return &authenticator{
+		method:   method,
+		username: opts.Username,
+		password: opts.Password,
+		basic:    method == AuthMethodBasic,
+	}
+}
+
+func (a *authenticator) enabled() bool {
+	return a.username != "" && a.password != ""
+}
+
+// authorize sets the authorization header of a request, if
+// the auth scheme of tvheadend is known.
+func (a *authenticator) authorize(req *http.Request) {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+
+	if a.basic {
+		req.SetBasicAuth(a.username, a.password)
+		return
+	}
+
+	if a.digest == nil {
+		return
+	}
+
+	a.digest.nc++
+	header, err := a.digest.authorization(req, a.username, a.password)
+	if err != nil {
+		return
+	}
+
+	req.Header.Set("Authorization", header)
+}
+
+// challenge handles the WWW-Authenticate headers of an unauthorized
+// response and returns true, if the request should be retried.
+func (a *authenticator) challenge(res *http.Response) bool {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+
+	for _, value := range res.Header.Values("WWW-Authenticate") {
+		scheme, params, _ := strings.Cut(value, " ")
+
+		switch {
+		case strings.EqualFold(scheme, "Digest") && a.method != AuthMethodBasic:
+			a.digest = parseDigestChallenge(params)
+			return true
+		case strings.EqualFold(scheme, "Basic") && a.method == AuthMethodAuto && !a.basic:
+			a.basic = true
+			return true
+		}
+	}
+
+	return false
+}
+
+func parseDigestChallenge(params string) *digestChallenge {
+	c := &digestChallenge{}
+
+	for _, param := range splitParams(params) {
+		key, value, ok := strings.Cut(param, "=")
+		if !ok {
+			continue
+		}
+
+		value = strings.Trim(strings.TrimSpace(value), `"`)
+
+		switch strings.ToLower(strings.TrimSpace(key)) {
+		case "realm":
+			c.realm = value
+		case "nonce":
+			c.nonce = value
+		case "opaque":
+			c.opaque = value
+		case "algorithm":
+			c.algorithm = value
+		case "qop":
+			// Prefer auth, since auth-int is not supported.
+			for _, qop := range strings.Split(value, ",") {
+				if strings.TrimSpace(qop) == "auth" {
+					c.qop = "auth"
+				}
+			}
+		}
+	}
+
+	return c
+}
+
+// splitParams splits the comma separated parameters
+// of a challenge and respects quoted values.
+func splitParams(params string) []string {
+	var (
+		result []string
+		quoted bool
+		start  int
+	)
+
+	for i, r := range params {
+		switch {
+		case r == '"':
+			quoted = !quoted
+		case r == ',' && !quoted:
+			result = append(result, params[start:i])
+			start = i + 1
+		}
+	}
+
+	return append(result, params[start:])
+}
+
+// authorization creates the digest authorization header
+// for a request as defined in RFC 7616.
+func (c *digestChallenge) authorization(req *http.Request, username string, password string) (string, error) {
+	name := c.algorithm
+	if name == "" {
+		name = "MD5"
+	}
+	algorithm := strings.ToUpper(name)
+
+	var h func() hash.Hash
+	switch strings.TrimSuffix(algorithm, "-SESS") {
+	case "MD5":
+		h = md5.New
+	case "SHA-256":
+		h = sha256.New
+	default:
+		return "", fmt.Errorf("unsupported digest algorithm: %s", c.algorithm)
+	}
+
+	digest := func(s string) string {
+		d := h()
+		d.Write([]byte(s))
+		return hex.EncodeToString(d.Sum(nil))
+	}
+
+	cnonce, err := newCnonce()
+	if err != nil {
+		return "", err
+	}
+
+	uri := req.URL.RequestURI()
+	nc := fmt.Sprintf("%08x", c.nc)
+
+	ha1 := digest(username + ":" + c.realm + ":" + password)
+	if strings.HasSuffix(algorithm, "-SESS") {
+		ha1 = digest(ha1 + ":" + c.nonce + ":" + cnonce)
+	}
+	ha2 := digest(req.Method + ":" + uri)
+
+	var response string
+	if c.qop != "" {
+		response = digest(strings.Join([]string{ha1, c.nonce, nc, cnonce, c.qop, ha2}, ":"))
+	} else {
+		response = digest(ha1 + ":" + c.nonce + ":" + ha2)
+	}
+
+	params := []string{
+		fmt.Sprintf(`username="%s"`, username),
+		fmt.Sprintf(`realm="%s"`, c.realm),
+		fmt.Sprintf(`nonce="%s"`, c.nonce),
+		fmt.Sprintf(`uri="%s"`, uri),
+		fmt.Sprintf(`algorithm=%s`, name),
+		fmt.Sprintf(`response="%s"`, response),
+	}
+
+	if c.qop != "" {
+		params = append(params,
+			fmt.Sprintf(`qop=%s`, c.qop),
+			fmt.Sprintf(`nc=%s`, nc),
+			fmt.Sprintf(`cnonce="%s"`, cnonce),
+		)
+	}
+
+	if c.opaque != "" {
+		params = append(params, fmt.Sprintf(`opaque="%s"`, c.opaque))
+	}
+
+	return "Digest " + strings.Join(params, ", "), nil
+}
+
+func newCnonce() (string, error) {
+	b := make([]byte, 16)
+	if _, err := rand.Read(b); err != nil {
+		return "", err
+	}
+
+	return hex.EncodeToString(b), nil
+}
@@ -0,0 +1,175 @@
+package tvheadend_test
+
+import (
+	"context"
+	"crypto/md5"
+	"encoding/hex"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/davidborzek/tvhgo/tvheadend"
+	"github.com/stretchr/testify/assert"
+)
+
+const (
+	digestRealm = "tvheadend"
+	digestNonce = "someNonce"
+)
+
+func md5Hex(s string) string {
+	sum := md5.Sum([]byte(s))
+	return hex.EncodeToString(sum[:])
+}
+
+// parseDigestAuthorization parses the parameters of a digest authorization header.
+func parseDigestAuthorization(header string) map[string]string {
+	params := make(map[string]string)
+
+	for _, param := range strings.Split(strings.TrimPrefix(header, "Digest "), ", ") {
+		key, value, _ := strings.Cut(param, "=")
+		params[key] = strings.Trim(value, `"`)
+	}
+
+	return params
+}
+
+// newDigestServer creates a server, which only accepts digest auth
+// and counts the requests it received.
+func newDigestServer(t *testing.T, requests *int) *httptest.Server {
+	return httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		*requests++
+
+		header := r.Header.Get("Authorization")
+		if !strings.HasPrefix(header, "Digest ") {
+			w.Header().Set("WWW-Authenticate",
+				`Digest realm="`+digestRealm+`", qop="auth", nonce="`+digestNonce+`", opaque="someOpaque"`)
+			w.WriteHeader(http.StatusUnauthorized)
+			return
+		}
+
+		params := parseDigestAuthorization(header)
+		assert.Equal(t, "someOpaque", params["opaque"])
+		assert.Equal(t, "/some/path", params["uri"])
+
+		ha1 := md5Hex("someUsername:" + digestRealm + ":somePassword")
+		ha2 := md5Hex(r.Method + ":" + params["uri"])
+		expected := md5Hex(strings.Join([]string{
+			ha1, digestNonce, params["nc"], params["cnonce"], params["qop"], ha2,
+		}, ":"))
+
+		if params["response"] != expected {
+			w.WriteHeader(http.StatusUnauthorized)
+			return
+		}
+
+		w.Write([]byte(`{"test":"testValue"}`))
+	}))
+}
+
+func TestClientExecWithDigestAuth(t *testing.T) {
+	requests := 0
+	srv := newDigestServer(t, &requests)
+	defer srv.Close()
+
+	client, err := tvheadend.New(tvheadend.ClientOpts{
+		URL:      srv.URL,
+		Username: "someUsername",
+		Password: "somePassword",
+	})
+	assert.Nil(t, err)
+
+	var model testResponse
+	res, err := client.Exec(context.TODO(), "/some/path", &model)
+
+	assert.Nil(t, err)
+	assert.Equal(t, http.StatusOK, res.StatusCode)
+	assert.Equal(t, "testValue", model.Test)
+	assert.Equal(t, 2, requests)
+
+	// The challenge is reused for further requests.
+	res, err = client.Exec(context.TODO(), "/some/path", &model)
+
+	assert.Nil(t, err)
+	assert.Equal(t, http.StatusOK, res.StatusCode)
+	assert.Equal(t, 3, requests)
+}
+
+func TestClientExecWithDigestAuthReturnsUnauthorizedForInvalidPassword(t *testing.T) {
+	requests := 0
+	srv := newDigestServer(t, &requests)
+	defer srv.Close()
+
+	client, err := tvheadend.New(tvheadend.ClientOpts{
+		URL:      srv.URL,
+		Username: "someUsername",
+		Password: "wrongPassword",
+	})
+	assert.Nil(t, err)
+
+	res, err := client.Exec(context.TODO(), "/some/path", nil)
+
+	assert.Nil(t, err)
+	assert.Equal(t, http.StatusUnauthorized, res.StatusCode)
+	assert.Equal(t, 2, requests)
+}
+
+func TestClientExecFallsBackToBasicAuth(t *testing.T) {
+	requests := 0
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		requests++
+
+		username, password, ok := r.BasicAuth()
+		if !ok {
+			w.Header().Set("WWW-Authenticate", `Basic realm="tvheadend"`)
+			w.WriteHeader(http.StatusUnauthorized)
+			return
+		}
+
+		assert.Equal(t, "someUsername", username)
+		assert.Equal(t, "somePassword", password)
+	}))
+	defer srv.Close()
+
+	client, err := tvheadend.New(tvheadend.ClientOpts{
+		URL:      srv.URL,
+		Username: "someUsername",
+		Password: "somePassword",
+	})
+	assert.Nil(t, err)
+
+	res, err := client.Exec(context.TODO(), "/some/path", nil)
+
+	assert.Nil(t, err)
+	assert.Equal(t, http.StatusOK, res.StatusCode)
+
+	// Basic auth is sent directly on further requests.
+	_, err = client.Exec(context.TODO(), "/some/path", nil)
+
+	assert.Nil(t, err)
+	assert.Equal(t, 3, requests)
+}
+
+func TestClientExecWithDigestAuthDoesNotFallBackToBasicAuth(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		assert.Empty(t, r.Header.Get("Authorization"))
+
+		w.Header().Set("WWW-Authenticate", `Basic realm="tvheadend"`)
+		w.WriteHeader(http.StatusUnauthorized)
+	}))
+	defer srv.Close()
+
+	client, err := tvheadend.New(tvheadend.ClientOpts{
+		URL:        srv.URL,
+		Username:   "someUsername",
+		Password:   "somePassword",
+		AuthMethod: tvheadend.AuthMethodDigest,
+	})
+	assert.Nil(t, err)
+
+	res, err := client.Exec(context.TODO(), "/some/path", nil)
+
+	assert.Nil(t, err)
+	assert.Equal(t, http.StatusUnauthorized, res.StatusCode)
+}
@@ -3,6 +3,8 @@ package tvheadend
 import (
 	"context"
 	"encoding/json"
+	"errors"
+	"fmt"
 	"net/http"
 	"net/url"
 	"strconv"
@@ -16,6 +18,15 @@ type (
 		URL      string
 		Username string
 		Password string
+		// AuthMethod defines how the credentials are sent. Defaults to AuthMethodAuto.
+		AuthMethod AuthMethod
+		TLS        TLSOpts
+		// Retry defines the retries of idempotent grid and load requests.
+		Retry RetryOpts
+		// CircuitBreaker defines when requests fail fast with ErrTvheadendUnavailable.
+		CircuitBreaker CircuitBreakerOpts
+		// MaxConcurrentRequests caps the number of concurrent requests. 0 is unlimited.
+		MaxConcurrentRequests int
 	}
 
 	// Query defines a tvheadend request query.
@@ -33,11 +44,16 @@ type (
 		// Exec performs a request to the given path
 		// and decodes the json-encoded response into the provided interface.
 		Exec(ctx context.Context, path string, dst interface{}, query ...Query) (*Response, error)
+		// CircuitState returns the current state of the circuit breaker.
+		CircuitState() CircuitState
 	}
 
 	client struct {
-		opts ClientOpts
-		http *http.Client
+		opts    ClientOpts
+		http    *http.Client
+		auth    *authenticator
+		breaker *circuitBreaker
+		limiter limiter
 	}
 )
 
@@ -49,28 +65,47 @@ func NewQuery() Query {
 }
 
 // NewWithClient creates a new tvheadend client with a custom http client.
+// The tls options are ignored, since the transport of the http client is used.
 func NewWithClient(opts ClientOpts, httpc *http.Client) Client {
 	return &client{
-		opts: opts,
-		http: httpc,
+		opts:    opts,
+		http:    httpc,
+		auth:    newAuthenticator(opts),
+		breaker: newCircuitBreaker(opts.CircuitBreaker),
+		limiter: newLimiter(opts.MaxConcurrentRequests),
 	}
 }
 
+// errCircuitOpen is returned if a request is rejected by the circuit breaker.
+var errCircuitOpen = fmt.Errorf("%w: circuit breaker is open", ErrTvheadendUnavailable)
+
 // NewStreamingClient creates a new tvheadend client for streaming
 // without http client timeout.
-func NewStreamingClient(opts ClientOpts) Client {
+func NewStreamingClient(opts ClientOpts) (Client, error) {
+	transport, err := newTransport(opts.TLS)
+	if err != nil {
+		return nil, err
+	}
+
 	httpc := &http.Client{
-		Timeout: 0,
+		Timeout:   0,
+		Transport: transport,
 	}
-	return NewWithClient(opts, httpc)
+	return NewWithClient(opts, httpc), nil
 }
 
 // New creates a new tvheadend client with a default http client.
-func New(opts ClientOpts) Client {
+func New(opts ClientOpts) (Client, error) {
+	transport, err := newTransport(opts.TLS)
+	if err != nil {
+		return nil, err
+	}
+
 	httpc := &http.Client{
-		Timeout: 10 * time.Second,
+		Timeout:   10 * time.Second,
+		Transport: transport,
 	}
-	return NewWithClient(opts, httpc)
+	return NewWithClient(opts, httpc), nil
 }
 
 func (c *client) Exec(
@@ -92,19 +127,12 @@ func (c *client) Exec(
 		}
 	}
 
-	encoded := strings.NewReader(q.Encode())
-	req, err := http.NewRequest(http.MethodPost, u, encoded)
-	if err != nil {
+	if err := c.limiter.acquire(ctx); err != nil {
 		return nil, err
 	}
+	defer c.limiter.release()
 
-	req.Header.Add("Content-Type", "application/x-www-form-urlencoded")
-
-	if c.opts.Username != "" && c.opts.Password != "" {
-		req.SetBasicAuth(c.opts.Username, c.opts.Password)
-	}
-
-	res, err := c.http.Do(req)
+	res, err := c.retry(ctx, path, u, q.Encode())
 	if err != nil {
 		return nil, err
 	}
@@ -122,6 +150,98 @@ func (c *client) Exec(
 	return &Response{res}, nil
 }
 
+func (c *client) CircuitState() CircuitState {
+	return c.breaker.current()
+}
+
+// retry sends a request to tvheadend and retries idempotent requests
+// with a jittered backoff if tvheadend is unavailable.
+func (c *client) retry(ctx context.Context, path string, u string, body string) (*http.Response, error) {
+	attempts := 1
+	if isIdempotent(path) {
+		attempts = max(attempts, c.opts.Retry.MaxAttempts)
+	}
+
+	for attempt := 1; ; attempt++ {
+		res, err := c.attempt(ctx, u, body)
+
+		retryable := (err != nil && !errors.Is(err, errCircuitOpen) && ctx.Err() == nil) ||
+			(err == nil && res.StatusCode >= http.StatusInternalServerError)
+		if !retryable || attempt >= attempts {
+			return res, err
+		}
+
+		if res != nil {
+			res.Body.Close()
+		}
+
+		if err := wait(ctx, c.opts.Retry.backoff(attempt-1)); err != nil {
+			return nil, err
+		}
+	}
+}
+
+// attempt sends a request to tvheadend, if the circuit breaker allows it,
+// and records the result.
+func (c *client) attempt(ctx context.Context, u string, body string) (*http.Response, error) {
+	if !c.breaker.allow() {
+		return nil, errCircuitOpen
+	}
+
+	res, err := c.do(ctx, u, body)
+	if err != nil {
+		// A canceled request says nothing about the availability of tvheadend.
+		if ctx.Err() != nil {
+			c.breaker.cancel()
+			return nil, err
+		}
+
+		c.breaker.record(true)
+		return nil, fmt.Errorf("%w: %w", ErrTvheadendUnavailable, err)
+	}
+
+	c.breaker.record(res.StatusCode >= http.StatusInternalServerError)
+	return res, nil
+}
+
+// do sends a request to tvheadend. If tvheadend challenges the request,
+// it is sent again with the credentials for the offered auth scheme.
+func (c *client) do(ctx context.Context, u string, body string) (*http.Response, error) {
+	res, err := c.send(ctx, u, body)
+	if err != nil {
+		return nil, err
+	}
+
+	if res.StatusCode != http.StatusUnauthorized || !c.auth.enabled() {
+		return res, nil
+	}
+
+	// Retry once, if the auth scheme was not known yet or the nonce
+	// of the digest challenge has expired and tvheadend sent a new one.
+	if !c.auth.challenge(res) {
+		return res, nil
+	}
+
+	res.Body.Close()
+
+	return c.send(ctx, u, body)
+}
+
+func (c *client) send(ctx context.Context, u string, body string) (*http.Response, error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, u, strings.NewReader(body))
+	if err != nil {
+		return nil, err
+	}
+
+	req.Header.Add("Content-Type", "application/x-www-form-urlencoded")
+
+	if c.auth.enabled() {
+		c.auth.authorize(req)
+	}
+
+	return c.http.Do(req)
+}
+
 // Limit sets the limit parameter.
 func (p *Query) Limit(limit int64) {
 	p.m["limit"] = strconv.FormatInt(limit, 10)
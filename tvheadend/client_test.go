@@ -51,11 +51,13 @@ func TestClientExec(t *testing.T) {
 		})
 	}))
 
-	client := tvheadend.New(tvheadend.ClientOpts{
-		URL:      srv.URL,
-		Username: "someUsername",
-		Password: "somePassword",
+	client, err := tvheadend.New(tvheadend.ClientOpts{
+		URL:        srv.URL,
+		Username:   "someUsername",
+		Password:   "somePassword",
+		AuthMethod: tvheadend.AuthMethodBasic,
 	})
+	assert.Nil(t, err)
 
 	q := tvheadend.NewQuery()
 	q.Limit(10)
@@ -73,9 +75,11 @@ func TestClientExecNoDest(t *testing.T) {
 		assert.Equal(t, "/some/path", r.URL.Path)
 	}))
 
-	client := tvheadend.New(tvheadend.ClientOpts{
+	client, err := tvheadend.New(tvheadend.ClientOpts{
 		URL: srv.URL,
 	})
+	assert.Nil(t, err)
+
 	res, err := client.Exec(context.TODO(), "/some/path", nil)
 
 	assert.Nil(t, err)
@@ -88,9 +92,11 @@ func TestClientExecWithoutAuth(t *testing.T) {
 		assert.Equal(t, "/some/path", r.URL.Path)
 	}))
 
-	client := tvheadend.New(tvheadend.ClientOpts{
+	client, err := tvheadend.New(tvheadend.ClientOpts{
 		URL: srv.URL,
 	})
+	assert.Nil(t, err)
+
 	res, err := client.Exec(context.TODO(), "/some/path", nil)
 
 	assert.Nil(t, err)
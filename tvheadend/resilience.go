@@ -0,0 +1,225 @@
+package tvheadend
+
+import (
+	"context"
+	"errors"
+	"math/rand/v2"
+	"strings"
+	"sync"
+	"time"
+)
+
+// ErrTvheadendUnavailable is returned if tvheadend can't be reached
+// or the circuit breaker is open after too many failed requests.
+var ErrTvheadendUnavailable = errors.New("tvheadend is unavailable")
+
+// CircuitState is the state of the circuit breaker.
+type CircuitState string
+
+const (
+	// CircuitClosed lets all requests pass.
+	CircuitClosed CircuitState = "closed"
+	// CircuitOpen rejects all requests with ErrTvheadendUnavailable.
+	CircuitOpen CircuitState = "open"
+	// CircuitHalfOpen lets a single request pass to check if tvheadend is available again.
+	CircuitHalfOpen CircuitState = "half-open"
+)
+
+type (
+	// RetryOpts defines the retries of idempotent grid and load requests.
+	RetryOpts struct {
+		// MaxAttempts is the maximum number of attempts including
+		// the first request. Requests are not retried if it is below 2.
+		MaxAttempts int
+		// BaseDelay is the maximum delay before the first retry,
+		// which is doubled on each further retry up to MaxDelay.
+		BaseDelay time.Duration
+		MaxDelay  time.Duration
+	}
+
+	// CircuitBreakerOpts defines when the circuit breaker opens.
+	CircuitBreakerOpts struct {
+		// FailureThreshold is the number of consecutive failed requests
+		// after which the circuit breaker opens. 0 disables the circuit breaker.
+		FailureThreshold int
+		// OpenTimeout is the time the circuit breaker stays open,
+		// before a request is let through to check tvheadend.
+		OpenTimeout time.Duration
+		// Clock measures the open timeout. Defaults to the system time.
+		Clock Clock
+	}
+
+	// Clock returns the current time.
+	Clock interface {
+		Now() time.Time
+	}
+)
+
+type systemClock struct{}
+
+func (systemClock) Now() time.Time {
+	return time.Now()
+}
+
+type circuitBreaker struct {
+	opts  CircuitBreakerOpts
+	clock Clock
+
+	mu       sync.Mutex
+	state    CircuitState
+	failures int
+	openedAt time.Time
+	probing  bool
+}
+
+func newCircuitBreaker(opts CircuitBreakerOpts) *circuitBreaker {
+	clock := opts.Clock
+	if clock == nil {
+		clock = systemClock{}
+	}
+
+	return &circuitBreaker{
+		opts:  opts,
+		clock: clock,
+		state: CircuitClosed,
+	}
+}
+
+// allow checks if a request can be sent to tvheadend.
+func (b *circuitBreaker) allow() bool {
+	if b.opts.FailureThreshold <= 0 {
+		return true
+	}
+
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	if b.state == CircuitOpen && b.clock.Now().Sub(b.openedAt) >= b.opts.OpenTimeout {
+		b.state = CircuitHalfOpen
+		b.probing = false
+	}
+
+	switch b.state {
+	case CircuitOpen:
+		return false
+	case CircuitHalfOpen:
+		if b.probing {
+			return false
+		}
+		b.probing = true
+	}
+
+	return true
+}
+
+// record records the result of a request.
+func (b *circuitBreaker) record(failed bool) {
+	if b.opts.FailureThreshold <= 0 {
+		return
+	}
+
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	if !failed {
+		b.state = CircuitClosed
+		b.failures = 0
+		b.probing = false
+		return
+	}
+
+	b.failures++
+	if b.state == CircuitHalfOpen || b.failures >= b.opts.FailureThreshold {
+		b.state = CircuitOpen
+		b.openedAt = b.clock.Now()
+		b.probing = false
+	}
+}
+
+// cancel releases the probe of a half-open circuit breaker
+// without recording a result.
+func (b *circuitBreaker) cancel() {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	b.probing = false
+}
+
+func (b *circuitBreaker) current() CircuitState {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	if b.state == CircuitOpen && b.clock.Now().Sub(b.openedAt) >= b.opts.OpenTimeout {
+		return CircuitHalfOpen
+	}
+
+	return b.state
+}
+
+// limiter caps the number of concurrent requests to tvheadend.
+type limiter chan struct{}
+
+func newLimiter(max int) limiter {
+	if max <= 0 {
+		return nil
+	}
+
+	return make(limiter, max)
+}
+
+func (l limiter) acquire(ctx context.Context) error {
+	if l == nil {
+		return nil
+	}
+
+	select {
+	case l <- struct{}{}:
+		return nil
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
+
+func (l limiter) release() {
+	if l != nil {
+		<-l
+	}
+}
+
+// isIdempotent checks if a request only reads data and can be retried.
+func isIdempotent(path string) bool {
+	segment := path[strings.LastIndex(path, "/")+1:]
+	return strings.HasPrefix(segment, "grid") || segment == "load"
+}
+
+// backoff returns the jittered delay before a retry.
+func (o RetryOpts) backoff(retry int) time.Duration {
+	if o.BaseDelay <= 0 {
+		return 0
+	}
+
+	delay := o.BaseDelay << retry
+	// The shift overflows for many retries.
+	if o.MaxDelay > 0 && (delay <= 0 || delay > o.MaxDelay) {
+		delay = o.MaxDelay
+	}
+
+	if delay <= 0 {
+		return 0
+	}
+
+	return rand.N(delay)
+}
+
+// wait waits for the delay or until the context is done.
+func wait(ctx context.Context, delay time.Duration) error {
+	timer := time.NewTimer(delay)
+	defer timer.Stop()
+
+	select {
+	case <-timer.C:
+		return nil
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
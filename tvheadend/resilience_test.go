@@ -0,0 +1,235 @@
+package tvheadend_test
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/davidborzek/tvhgo/tvheadend"
+	"github.com/stretchr/testify/assert"
+)
+
+// newFailingServer creates a server, which responds with the status code
+// for the first failures requests and counts the requests it received.
+func newFailingServer(failures int32, status int, requests *atomic.Int32) *httptest.Server {
+	return httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if requests.Add(1) <= failures {
+			w.WriteHeader(status)
+			return
+		}
+
+		w.Write([]byte(`{"test":"testValue"}`))
+	}))
+}
+
+// testClock is a clock, which only advances when told to.
+type testClock struct {
+	mu  sync.Mutex
+	now time.Time
+}
+
+func (c *testClock) Now() time.Time {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	return c.now
+}
+
+func (c *testClock) Advance(d time.Duration) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	c.now = c.now.Add(d)
+}
+
+var testRetryOpts = tvheadend.RetryOpts{
+	MaxAttempts: 3,
+	BaseDelay:   time.Millisecond,
+	MaxDelay:    5 * time.Millisecond,
+}
+
+func TestClientExecRetriesIdempotentRequests(t *testing.T) {
+	var requests atomic.Int32
+	srv := newFailingServer(2, http.StatusServiceUnavailable, &requests)
+	defer srv.Close()
+
+	client, err := tvheadend.New(tvheadend.ClientOpts{
+		URL:   srv.URL,
+		Retry: testRetryOpts,
+	})
+	assert.Nil(t, err)
+
+	var model testResponse
+	res, err := client.Exec(context.TODO(), "/api/channel/grid", &model)
+
+	assert.Nil(t, err)
+	assert.Equal(t, http.StatusOK, res.StatusCode)
+	assert.Equal(t, "testValue", model.Test)
+	assert.Equal(t, int32(3), requests.Load())
+}
+
+func TestClientExecReturnsLastResponseWhenRetriesAreExhausted(t *testing.T) {
+	var requests atomic.Int32
+	srv := newFailingServer(5, http.StatusServiceUnavailable, &requests)
+	defer srv.Close()
+
+	client, err := tvheadend.New(tvheadend.ClientOpts{
+		URL:   srv.URL,
+		Retry: testRetryOpts,
+	})
+	assert.Nil(t, err)
+
+	res, err := client.Exec(context.TODO(), "/api/idnode/load", nil)
+
+	assert.Nil(t, err)
+	assert.Equal(t, http.StatusServiceUnavailable, res.StatusCode)
+	assert.Equal(t, int32(3), requests.Load())
+}
+
+func TestClientExecDoesNotRetryNonIdempotentRequests(t *testing.T) {
+	var requests atomic.Int32
+	srv := newFailingServer(1, http.StatusServiceUnavailable, &requests)
+	defer srv.Close()
+
+	client, err := tvheadend.New(tvheadend.ClientOpts{
+		URL:   srv.URL,
+		Retry: testRetryOpts,
+	})
+	assert.Nil(t, err)
+
+	res, err := client.Exec(context.TODO(), "/api/dvr/entry/create", nil)
+
+	assert.Nil(t, err)
+	assert.Equal(t, http.StatusServiceUnavailable, res.StatusCode)
+	assert.Equal(t, int32(1), requests.Load())
+}
+
+func TestClientExecReturnsUnavailableForUnreachableTvheadend(t *testing.T) {
+	srv := httptest.NewServer(http.NotFoundHandler())
+	srv.Close()
+
+	client, err := tvheadend.New(tvheadend.ClientOpts{
+		URL: srv.URL,
+	})
+	assert.Nil(t, err)
+
+	_, err = client.Exec(context.TODO(), "/api/channel/grid", nil)
+
+	assert.ErrorIs(t, err, tvheadend.ErrTvheadendUnavailable)
+}
+
+func TestClientExecCircuitBreaker(t *testing.T) {
+	var requests atomic.Int32
+	srv := newFailingServer(2, http.StatusInternalServerError, &requests)
+	defer srv.Close()
+
+	clock := &testClock{now: time.Unix(1700000000, 0)}
+	client, err := tvheadend.New(tvheadend.ClientOpts{
+		URL: srv.URL,
+		CircuitBreaker: tvheadend.CircuitBreakerOpts{
+			FailureThreshold: 2,
+			OpenTimeout:      time.Minute,
+			Clock:            clock,
+		},
+	})
+	assert.Nil(t, err)
+	assert.Equal(t, tvheadend.CircuitClosed, client.CircuitState())
+
+	for range 2 {
+		_, err = client.Exec(context.TODO(), "/some/path", nil)
+		assert.Nil(t, err)
+	}
+
+	assert.Equal(t, tvheadend.CircuitOpen, client.CircuitState())
+
+	// Requests fail fast while the circuit breaker is open.
+	_, err = client.Exec(context.TODO(), "/some/path", nil)
+
+	assert.ErrorIs(t, err, tvheadend.ErrTvheadendUnavailable)
+	assert.Equal(t, int32(2), requests.Load())
+
+	clock.Advance(time.Minute - time.Second)
+	assert.Equal(t, tvheadend.CircuitOpen, client.CircuitState())
+
+	clock.Advance(time.Second)
+	assert.Equal(t, tvheadend.CircuitHalfOpen, client.CircuitState())
+
+	// A successful request closes the circuit breaker again.
+	res, err := client.Exec(context.TODO(), "/some/path", nil)
+
+	assert.Nil(t, err)
+	assert.Equal(t, http.StatusOK, res.StatusCode)
+	assert.Equal(t, tvheadend.CircuitClosed, client.CircuitState())
+}
+
+func TestClientExecCircuitBreakerReopensWhenProbeFails(t *testing.T) {
+	var requests atomic.Int32
+	srv := newFailingServer(2, http.StatusInternalServerError, &requests)
+	defer srv.Close()
+
+	clock := &testClock{now: time.Unix(1700000000, 0)}
+	client, err := tvheadend.New(tvheadend.ClientOpts{
+		URL: srv.URL,
+		CircuitBreaker: tvheadend.CircuitBreakerOpts{
+			FailureThreshold: 1,
+			OpenTimeout:      time.Minute,
+			Clock:            clock,
+		},
+	})
+	assert.Nil(t, err)
+
+	_, err = client.Exec(context.TODO(), "/some/path", nil)
+	assert.Nil(t, err)
+	assert.Equal(t, tvheadend.CircuitOpen, client.CircuitState())
+
+	clock.Advance(time.Minute)
+
+	_, err = client.Exec(context.TODO(), "/some/path", nil)
+	assert.Nil(t, err)
+	assert.Equal(t, tvheadend.CircuitOpen, client.CircuitState())
+	assert.Equal(t, int32(2), requests.Load())
+}
+
+func TestClientExecLimitsConcurrentRequests(t *testing.T) {
+	var active, maxActive atomic.Int32
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		n := active.Add(1)
+		defer active.Add(-1)
+
+		for {
+			m := maxActive.Load()
+			if n <= m || maxActive.CompareAndSwap(m, n) {
+				break
+			}
+		}
+
+		time.Sleep(10 * time.Millisecond)
+	}))
+	defer srv.Close()
+
+	client, err := tvheadend.New(tvheadend.ClientOpts{
+		URL:                   srv.URL,
+		MaxConcurrentRequests: 2,
+	})
+	assert.Nil(t, err)
+
+	var wg sync.WaitGroup
+	for range 6 {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+
+			res, err := client.Exec(context.TODO(), "/some/path", nil)
+			if assert.Nil(t, err) {
+				res.Body.Close()
+			}
+		}()
+	}
+	wg.Wait()
+
+	assert.Equal(t, int32(2), maxActive.Load())
+}
@@ -0,0 +1,67 @@
+package tvheadend
+
+import (
+	"crypto/tls"
+	"crypto/x509"
+	"errors"
+	"fmt"
+	"net/http"
+	"os"
+)
+
+// TLSOpts defines the tls options for connections to tvheadend.
+type TLSOpts struct {
+	// CAFile is a pem encoded ca bundle used in addition
+	// to the system certificates to verify tvheadend.
+	CAFile string
+	// CertFile and KeyFile are a pem encoded client
+	// certificate and key sent to tvheadend.
+	CertFile string
+	KeyFile  string
+	// InsecureSkipVerify disables the verification of the certificate of tvheadend.
+	InsecureSkipVerify bool
+}
+
+// newTransport creates a http transport with the tls options.
+func newTransport(opts TLSOpts) (*http.Transport, error) {
+	transport := http.DefaultTransport.(*http.Transport).Clone()
+
+	if opts == (TLSOpts{}) {
+		return transport, nil
+	}
+
+	cfg := &tls.Config{
+		MinVersion:         tls.VersionTLS12,
+		InsecureSkipVerify: opts.InsecureSkipVerify,
+	}
+
+	if opts.CAFile != "" {
+		pem, err := os.ReadFile(opts.CAFile)
+		if err != nil {
+			return nil, fmt.Errorf("failed to read tvheadend ca file: %w", err)
+		}
+
+		pool, err := x509.SystemCertPool()
+		if err != nil {
+			pool = x509.NewCertPool()
+		}
+
+		if !pool.AppendCertsFromPEM(pem) {
+			return nil, errors.New("tvheadend ca file contains no certificates")
+		}
+
+		cfg.RootCAs = pool
+	}
+
+	if opts.CertFile != "" || opts.KeyFile != "" {
+		cert, err := tls.LoadX509KeyPair(opts.CertFile, opts.KeyFile)
+		if err != nil {
+			return nil, fmt.Errorf("failed to load tvheadend client certificate: %w", err)
+		}
+
+		cfg.Certificates = []tls.Certificate{cert}
+	}
+
+	transport.TLSClientConfig = cfg
+	return transport, nil
+}
@@ -0,0 +1,102 @@
+package tvheadend_test
+
+import (
+	"context"
+	"encoding/pem"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/davidborzek/tvhgo/tvheadend"
+	"github.com/stretchr/testify/assert"
+)
+
+func newTLSServer() *httptest.Server {
+	return httptest.NewTLSServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Write([]byte(`{"test":"testValue"}`))
+	}))
+}
+
+func TestClientExecWithCAFile(t *testing.T) {
+	srv := newTLSServer()
+	defer srv.Close()
+
+	caFile := filepath.Join(t.TempDir(), "ca.pem")
+	err := os.WriteFile(caFile, pem.EncodeToMemory(&pem.Block{
+		Type:  "CERTIFICATE",
+		Bytes: srv.Certificate().Raw,
+	}), 0o600)
+	assert.Nil(t, err)
+
+	client, err := tvheadend.New(tvheadend.ClientOpts{
+		URL: srv.URL,
+		TLS: tvheadend.TLSOpts{
+			CAFile: caFile,
+		},
+	})
+	assert.Nil(t, err)
+
+	var model testResponse
+	_, err = client.Exec(context.TODO(), "/some/path", &model)
+
+	assert.Nil(t, err)
+	assert.Equal(t, "testValue", model.Test)
+}
+
+func TestClientExecFailsForUnknownCertificate(t *testing.T) {
+	srv := newTLSServer()
+	defer srv.Close()
+
+	client, err := tvheadend.New(tvheadend.ClientOpts{
+		URL: srv.URL,
+	})
+	assert.Nil(t, err)
+
+	_, err = client.Exec(context.TODO(), "/some/path", nil)
+
+	assert.ErrorContains(t, err, "certificate")
+}
+
+func TestClientExecWithInsecureSkipVerify(t *testing.T) {
+	srv := newTLSServer()
+	defer srv.Close()
+
+	client, err := tvheadend.New(tvheadend.ClientOpts{
+		URL: srv.URL,
+		TLS: tvheadend.TLSOpts{
+			InsecureSkipVerify: true,
+		},
+	})
+	assert.Nil(t, err)
+
+	_, err = client.Exec(context.TODO(), "/some/path", nil)
+
+	assert.Nil(t, err)
+}
+
+func TestNewFailsForInvalidCAFile(t *testing.T) {
+	caFile := filepath.Join(t.TempDir(), "ca.pem")
+	err := os.WriteFile(caFile, []byte("invalid"), 0o600)
+	assert.Nil(t, err)
+
+	_, err = tvheadend.New(tvheadend.ClientOpts{
+		TLS: tvheadend.TLSOpts{
+			CAFile: caFile,
+		},
+	})
+
+	assert.EqualError(t, err, "tvheadend ca file contains no certificates")
+}
+
+func TestNewFailsForMissingClientCertificate(t *testing.T) {
+	_, err := tvheadend.New(tvheadend.ClientOpts{
+		TLS: tvheadend.TLSOpts{
+			CertFile: "/nonexistent/cert.pem",
+			KeyFile:  "/nonexistent/key.pem",
+		},
+	})
+
+	assert.ErrorContains(t, err, "failed to load tvheadend client certificate")
+}
@@ -136,6 +136,10 @@ type (
 		UUID string `json:"uuid"`
 	}
 
+	DvrRecordingsCreatedByEvent struct {
+		UUID []string `json:"uuid"`
+	}
+
 	EpgEventGridEntry struct {
 		EventID       int64  `json:"eventId"`
 		ChannelName   string `json:"channelName"`